@@ -14,4 +14,6 @@ type Activity struct {
 	Key    string `serialize:"true" json:"key,omitempty"`
 	To     string `serialize:"true" json:"to,omitempty"` // common.Address will be 0x000 when not populated
 	Units  uint64 `serialize:"true" json:"units,omitempty"`
+	// Seconds a lifeline explicitly extended the space by
+	Extension uint64 `serialize:"true" json:"extension,omitempty"`
 }
@@ -0,0 +1,60 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ava-labs/spacesvm/tdata"
+)
+
+var _ UnsignedTransaction = &ApproveTx{}
+
+type ApproveTx struct {
+	*BaseTx `serialize:"true" json:"baseTx"`
+
+	// Action is the [ActionHash] of a transaction the sender approves as the
+	// co-signer of a [SpacePolicy]. The approval is consumed by the
+	// transaction, and is only honored while the sender is the co-signer of
+	// the space it acts on.
+	Action common.Hash `serialize:"true" json:"action"`
+}
+
+func (a *ApproveTx) Execute(t *TransactionContext) error {
+	if a.Action == (common.Hash{}) {
+		return ErrNonActionable
+	}
+	return t.State.PutApproval(a.Action, t.Sender)
+}
+
+func (a *ApproveTx) Copy() UnsignedTransaction {
+	return &ApproveTx{
+		BaseTx: a.BaseTx.Copy(),
+		Action: a.Action,
+	}
+}
+
+func (a *ApproveTx) TypedData() *tdata.TypedData {
+	return tdata.CreateTypedData(
+		a.Magic, a.typedChainID(), Approve,
+		[]tdata.Type{
+			{Name: tdAction, Type: tdBytes32},
+			{Name: tdPrice, Type: tdUint64},
+			{Name: tdBlockID, Type: tdString},
+		},
+		tdata.TypedDataMessage{
+			tdAction:  a.Action.Hex(),
+			tdPrice:   strconv.FormatUint(a.Price, 10),
+			tdBlockID: a.BlockID.String(),
+		},
+	)
+}
+
+func (a *ApproveTx) Activity() *Activity {
+	return &Activity{
+		Typ: Approve,
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/codec"
+	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/hashing"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// atomicCodec encodes the UTXOs exchanged through shared memory. The
+// secp256k1fx types are registered at the same type IDs as in the AVM and
+// PlatformVM, so the X and P chains can parse the UTXOs this chain exports
+// (and this chain can parse the UTXOs they export).
+var atomicCodec codec.Manager
+
+func init() {
+	c := linearcodec.NewDefault()
+	atomicCodec = codec.NewDefaultManager()
+	c.SkipRegistrations(5)
+	errs := wrappers.Errs{}
+	errs.Add(
+		c.RegisterType(&secp256k1fx.TransferInput{}),
+		c.RegisterType(&secp256k1fx.MintOutput{}),
+		c.RegisterType(&secp256k1fx.TransferOutput{}),
+		c.RegisterType(&secp256k1fx.MintOperation{}),
+		c.RegisterType(&secp256k1fx.Credential{}),
+		c.RegisterType(&secp256k1fx.Input{}),
+		c.RegisterType(&secp256k1fx.OutputOwners{}),
+		atomicCodec.RegisterCodec(CodecVersion, c),
+	)
+	if errs.Errored() {
+		panic(errs.Err)
+	}
+}
+
+// Atomic gives transactions access to the memory this chain shares with the
+// other chains of its subnet
+type Atomic interface {
+	SharedMemory() atomic.SharedMemory
+	// VerifyPeerChain returns an error if units can't be moved between this
+	// chain and [chainID]
+	VerifyPeerChain(chainID ids.ID) error
+}
+
+// AtomicTransaction is implemented by transactions that move units between
+// chains through shared memory
+type AtomicTransaction interface {
+	// AtomicRequests returns the shared memory operations performed with
+	// [peerChainID] once the transaction [txID] is accepted
+	AtomicRequests(g *Genesis, txID ids.ID) (peerChainID ids.ID, requests *atomic.Requests, err error)
+}
+
+// ShortAddress returns the address that owns UTXOs on the X and P chains
+// for [pk]
+func ShortAddress(pk *ecdsa.PublicKey) ids.ShortID {
+	addr, err := ids.ToShortID(hashing.PubkeyBytesToAddress(crypto.CompressPubkey(pk)))
+	if err != nil {
+		// Hash160 always returns 20 bytes
+		panic(err)
+	}
+	return addr
+}
+
+// ParseAtomicUTXO parses a UTXO read from shared memory
+func ParseAtomicUTXO(b []byte) (*avax.UTXO, error) {
+	utxo := new(avax.UTXO)
+	if _, err := atomicCodec.Unmarshal(b, utxo); err != nil {
+		return nil, err
+	}
+	return utxo, nil
+}
+
+// MarshalAtomicUTXO encodes [utxo] for shared memory
+func MarshalAtomicUTXO(utxo *avax.UTXO) ([]byte, error) {
+	return atomicCodec.Marshal(CodecVersion, utxo)
+}
+
+// atomicRequests merges the shared memory operations of every atomic
+// transaction in [txs]
+func atomicRequests(g *Genesis, txs []*Transaction) (map[ids.ID]*atomic.Requests, error) {
+	requests := map[ids.ID]*atomic.Requests{}
+	for _, tx := range txs {
+		atx, ok := tx.UnsignedTransaction.(AtomicTransaction)
+		if !ok {
+			continue
+		}
+		peerChainID, req, err := atx.AtomicRequests(g, tx.ID())
+		if err != nil {
+			return nil, fmt.Errorf("%w: tx %s", err, tx.ID())
+		}
+		merged, ok := requests[peerChainID]
+		if !ok {
+			requests[peerChainID] = req
+			continue
+		}
+		merged.RemoveRequests = append(merged.RemoveRequests, req.RemoveRequests...)
+		merged.PutRequests = append(merged.PutRequests, req.PutRequests...)
+	}
+	return requests, nil
+}
+
+// verifyAtomic returns an error if units can't be moved between this chain
+// and [peerChainID]
+func verifyAtomic(c *TransactionContext, peerChainID ids.ID) error {
+	if c.Genesis.AtomicAssetID == ids.Empty {
+		return ErrAtomicDisabled
+	}
+	if c.Atomic == nil {
+		return ErrAtomicUnavailable
+	}
+	if err := c.Atomic.VerifyPeerChain(peerChainID); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPeerChain, err)
+	}
+	return nil
+}
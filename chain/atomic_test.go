@@ -0,0 +1,176 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// testAtomic allows units to be moved to any other chain
+type testAtomic struct {
+	sm      atomic.SharedMemory
+	chainID ids.ID
+}
+
+func (a *testAtomic) SharedMemory() atomic.SharedMemory { return a.sm }
+
+func (a *testAtomic) VerifyPeerChain(chainID ids.ID) error {
+	if chainID == a.chainID {
+		return errors.New("same chain")
+	}
+	return nil
+}
+
+func TestAtomicTxs(t *testing.T) {
+	t.Parallel()
+
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := crypto.PubkeyToAddress(priv.PublicKey)
+	shortSender := ShortAddress(&priv.PublicKey)
+
+	db := memdb.New()
+	defer db.Close()
+	g := DefaultGenesis()
+	g.AtomicAssetID = ids.GenerateTestID()
+	g.CustomAllocation = []*CustomAllocation{{Address: sender, Balance: 100}}
+	if err := g.Load(db, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &atomic.Memory{}
+	if err := m.Initialize(logging.NoLog{}, prefixdb.New([]byte{0}, memdb.New())); err != nil {
+		t.Fatal(err)
+	}
+	chainID, peerChainID := ids.GenerateTestID(), ids.GenerateTestID()
+	a := &testAtomic{sm: m.NewSharedMemory(chainID), chainID: chainID}
+	peer := m.NewSharedMemory(peerChainID)
+	tc := &TransactionContext{
+		Genesis:       g,
+		State:         NewStateDB(db),
+		BlockTime:     1,
+		Sender:        sender,
+		SenderShortID: shortSender,
+		Atomic:        a,
+	}
+
+	// Export units to the peer chain
+	to := ids.GenerateTestShortID()
+	etx := &ExportTx{BaseTx: &BaseTx{}, DestinationChain: peerChainID, To: to, Units: 40}
+	if err := etx.Execute(tc); err != nil {
+		t.Fatal(err)
+	}
+	if bal, _ := GetBalance(db, sender); bal != 60 {
+		t.Fatalf("expected balance 60, got %d", bal)
+	}
+	exportID := ids.GenerateTestID()
+	peerID, req, err := etx.AtomicRequests(g, exportID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := a.sm.Apply(map[ids.ID]*atomic.Requests{peerID: req}); err != nil {
+		t.Fatal(err)
+	}
+	utxoID := etx.UTXO(g, exportID).InputID()
+	values, err := peer.Get(chainID, [][]byte{utxoID[:]})
+	if err != nil {
+		t.Fatal(err)
+	}
+	utxo, err := ParseAtomicUTXO(values[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out := utxo.Out.(*secp256k1fx.TransferOutput); out.Amt != 40 || out.Addrs[0] != to {
+		t.Fatalf("unexpected exported output %+v", out)
+	}
+
+	// Import UTXOs exported by the peer chain
+	put := func(amt uint64, assetID ids.ID, owner ids.ShortID) ids.ID {
+		utxo := &avax.UTXO{
+			UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+			Asset:  avax.Asset{ID: assetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt:          amt,
+				OutputOwners: secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{owner}},
+			},
+		}
+		b, err := MarshalAtomicUTXO(utxo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		id := utxo.InputID()
+		if err := peer.Apply(map[ids.ID]*atomic.Requests{chainID: {
+			PutRequests: []*atomic.Element{{Key: id[:], Value: b, Traits: [][]byte{owner.Bytes()}}},
+		}}); err != nil {
+			t.Fatal(err)
+		}
+		return id
+	}
+	owned := put(25, g.AtomicAssetID, shortSender)
+	tt := []struct {
+		utx      *ImportTx
+		noAtomic bool
+		err      error
+	}{
+		{ // wrong owner
+			utx: &ImportTx{SourceChain: peerChainID, UTXOID: put(25, g.AtomicAssetID, to)},
+			err: ErrUnauthorized,
+		},
+		{ // wrong asset
+			utx: &ImportTx{SourceChain: peerChainID, UTXOID: put(25, ids.GenerateTestID(), shortSender)},
+			err: ErrInvalidUTXO,
+		},
+		{ // not exported
+			utx: &ImportTx{SourceChain: peerChainID, UTXOID: ids.GenerateTestID()},
+			err: ErrUTXOMissing,
+		},
+		{ // importing from this chain
+			utx: &ImportTx{SourceChain: chainID, UTXOID: owned},
+			err: ErrInvalidPeerChain,
+		},
+		{ // no shared memory
+			utx:      &ImportTx{SourceChain: peerChainID, UTXOID: owned},
+			noAtomic: true,
+			err:      ErrAtomicUnavailable,
+		},
+		{ // valid
+			utx: &ImportTx{SourceChain: peerChainID, UTXOID: owned},
+		},
+		{ // already imported
+			utx: &ImportTx{SourceChain: peerChainID, UTXOID: owned},
+			err: ErrUTXOImported,
+		},
+	}
+	for i, tv := range tt {
+		tc.Atomic = a
+		if tv.noAtomic {
+			tc.Atomic = nil
+		}
+		tv.utx.BaseTx = &BaseTx{}
+		if err := tv.utx.Execute(tc); !errors.Is(err, tv.err) {
+			t.Fatalf("#%d: tx.Execute err expected %v, got %v", i, tv.err, err)
+		}
+	}
+	if bal, _ := GetBalance(db, sender); bal != 85 {
+		t.Fatalf("expected balance 85, got %d", bal)
+	}
+
+	// Atomic txs are disabled without an asset
+	g.AtomicAssetID = ids.Empty
+	if err := etx.Execute(tc); !errors.Is(err, ErrAtomicDisabled) {
+		t.Fatalf("expected %v, got %v", ErrAtomicDisabled, err)
+	}
+}
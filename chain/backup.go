@@ -0,0 +1,248 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+const (
+	backupVersion = uint32(1)
+
+	backupRecordEntry = byte(1)
+	backupRecordEnd   = byte(0)
+
+	// maximum batch size written to the database when restoring
+	restoreBatchSize = 4 * 1024 * 1024
+)
+
+var (
+	backupMagic = []byte("spacesvm-backup")
+)
+
+// ExportBackup writes every key/value pair in [db] to [w]. The caller must
+// ensure [db] is not modified during the export for the backup to be
+// consistent (or use [WriteBackup] with an iterator over a consistent view).
+// It returns the number of entries written.
+func ExportBackup(db database.Iteratee, w io.Writer) (int, error) {
+	cursor := db.NewIterator()
+	defer cursor.Release()
+	return WriteBackup(cursor, w)
+}
+
+// WriteBackup writes every key/value pair of [cursor] to [w]. It returns the
+// number of entries written.
+//
+// Backup format:
+// [magic] + [version] + ([entry] + [klen] + [key] + [vlen] + [value])* +
+// [end] + [count] + [sha256 of all preceding entries]
+func WriteBackup(cursor database.Iterator, w io.Writer) (int, error) {
+	bw := bufio.NewWriter(w)
+	if _, err := bw.Write(backupMagic); err != nil {
+		return 0, err
+	}
+	if err := binary.Write(bw, binary.BigEndian, backupVersion); err != nil {
+		return 0, err
+	}
+
+	h := sha256.New()
+	hw := io.MultiWriter(bw, h)
+	count := 0
+	for cursor.Next() {
+		if err := writeBackupEntry(hw, cursor.Key(), cursor.Value()); err != nil {
+			return count, err
+		}
+		count++
+	}
+	if err := cursor.Error(); err != nil {
+		return count, err
+	}
+
+	if err := bw.WriteByte(backupRecordEnd); err != nil {
+		return count, err
+	}
+	if err := binary.Write(bw, binary.BigEndian, uint64(count)); err != nil {
+		return count, err
+	}
+	if _, err := bw.Write(h.Sum(nil)); err != nil {
+		return count, err
+	}
+	return count, bw.Flush()
+}
+
+func writeBackupEntry(w io.Writer, k []byte, v []byte) error {
+	if _, err := w.Write([]byte{backupRecordEntry}); err != nil {
+		return err
+	}
+	for _, b := range [][]byte{k, v} {
+		if err := binary.Write(w, binary.BigEndian, uint32(len(b))); err != nil {
+			return err
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ImportBackup restores a backup created by [ExportBackup] from [r] into
+// [db], which must be empty. It returns the number of entries restored.
+//
+// Entries are written in batches before the backup is fully verified, so
+// [db] is cleared if the restore fails to never leave a partial restore
+// behind.
+func ImportBackup(db database.Database, r io.Reader) (int, error) {
+	cursor := db.NewIterator()
+	nonEmpty := cursor.Next()
+	cursor.Release()
+	if nonEmpty {
+		return 0, ErrDatabaseNotEmpty
+	}
+
+	count, err := importBackup(db, r)
+	if err != nil {
+		if cerr := ClearDatabase(db); cerr != nil {
+			return count, fmt.Errorf("%w (unable to clear partial restore: %v)", err, cerr)
+		}
+		return count, err
+	}
+	return count, nil
+}
+
+// ClearDatabase deletes every key in [db].
+func ClearDatabase(db database.Database) error {
+	cursor := db.NewIterator()
+	defer cursor.Release()
+	batch := db.NewBatch()
+	for cursor.Next() {
+		if err := batch.Delete(cursor.Key()); err != nil {
+			return err
+		}
+		if batch.Size() > restoreBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := cursor.Error(); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+func importBackup(db database.Database, r io.Reader) (int, error) {
+	br := bufio.NewReader(r)
+	magic := make([]byte, len(backupMagic))
+	if _, err := io.ReadFull(br, magic); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+	}
+	if !bytes.Equal(magic, backupMagic) {
+		return 0, fmt.Errorf("%w: unexpected magic %x", ErrInvalidBackup, magic)
+	}
+	var version uint32
+	if err := binary.Read(br, binary.BigEndian, &version); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+	}
+	if version != backupVersion {
+		return 0, fmt.Errorf("%w: unsupported version %d", ErrInvalidBackup, version)
+	}
+
+	h := sha256.New()
+	batch := db.NewBatch()
+	count := 0
+	for {
+		typ, err := br.ReadByte()
+		if err != nil {
+			return count, fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+		}
+		if typ == backupRecordEnd {
+			break
+		}
+		if typ != backupRecordEntry {
+			return count, fmt.Errorf("%w: unexpected record type %d", ErrInvalidBackup, typ)
+		}
+		k, v, err := readBackupEntry(br, h)
+		if err != nil {
+			return count, fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+		}
+		if err := batch.Put(k, v); err != nil {
+			return count, err
+		}
+		count++
+		if batch.Size() > restoreBatchSize {
+			if err := batch.Write(); err != nil {
+				return count, err
+			}
+			batch.Reset()
+		}
+	}
+
+	var expected uint64
+	if err := binary.Read(br, binary.BigEndian, &expected); err != nil {
+		return count, fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+	}
+	if expected != uint64(count) {
+		return count, fmt.Errorf("%w: expected %d entries but found %d", ErrInvalidBackup, expected, count)
+	}
+	checksum := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(br, checksum); err != nil {
+		return count, fmt.Errorf("%w: %v", ErrInvalidBackup, err)
+	}
+	if !bytes.Equal(checksum, h.Sum(nil)) {
+		return count, fmt.Errorf("%w: checksum mismatch", ErrInvalidBackup)
+	}
+	return count, batch.Write()
+}
+
+func readBackupEntry(r io.Reader, h hash.Hash) ([]byte, []byte, error) {
+	h.Write([]byte{backupRecordEntry})
+	tr := io.TeeReader(r, h)
+	kv := make([][]byte, 2)
+	for i := range kv {
+		var l uint32
+		if err := binary.Read(tr, binary.BigEndian, &l); err != nil {
+			return nil, nil, err
+		}
+		kv[i] = make([]byte, l)
+		if _, err := io.ReadFull(tr, kv[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+	return kv[0], kv[1], nil
+}
+
+// VerifyLastAccepted ensures the last accepted block in [db] exists and that
+// its stored contents hash to its ID.
+func VerifyLastAccepted(db database.KeyValueReader) (ids.ID, *StatefulBlock, error) {
+	bid, err := GetLastAccepted(db)
+	if err != nil {
+		return ids.Empty, nil, err
+	}
+	if bid == ids.Empty {
+		return ids.Empty, nil, ErrNoLastAccepted
+	}
+	blk, err := GetBlock(db, bid)
+	if err != nil {
+		return bid, nil, fmt.Errorf("%w: last accepted block %s unavailable: %v", ErrInvalidBackup, bid, err)
+	}
+	b, err := Marshal(blk)
+	if err != nil {
+		return bid, nil, err
+	}
+	if id := ids.ID(crypto.Keccak256Hash(b)); id != bid {
+		return bid, nil, fmt.Errorf("%w: last accepted block %s hashes to %s", ErrInvalidBackup, bid, id)
+	}
+	return bid, blk, nil
+}
@@ -0,0 +1,116 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestBackupRestore(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.New()
+	blk := &StatefulBlock{Tmstmp: 1, Hght: 10, Price: 1, Cost: 1}
+	b, err := Marshal(blk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bid := ids.ID(crypto.Keccak256Hash(b))
+	if err := db.Put(lastAccepted, bid[:]); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put(PrefixBlockKey(bid), b); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetBalance(db, common.Address{0x1}, 100); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	exported, err := ExportBackup(db, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exported != 3 {
+		t.Fatalf("exported expected 3, got %d", exported)
+	}
+	backup := buf.Bytes()
+
+	restored := memdb.New()
+	imported, err := ImportBackup(restored, bytes.NewReader(backup))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if imported != exported {
+		t.Fatalf("imported expected %d, got %d", exported, imported)
+	}
+	rid, rblk, err := VerifyLastAccepted(restored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rid != bid || rblk.Hght != blk.Hght {
+		t.Fatalf("unexpected last accepted %s at height %d", rid, rblk.Hght)
+	}
+	bal, err := GetBalance(restored, common.Address{0x1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bal != 100 {
+		t.Fatalf("balance expected 100, got %d", bal)
+	}
+
+	// Restoring into a non-empty database should fail
+	if _, err := ImportBackup(restored, bytes.NewReader(backup)); !errors.Is(err, ErrDatabaseNotEmpty) {
+		t.Fatalf("expected %v, got %v", ErrDatabaseNotEmpty, err)
+	}
+
+	// Corrupted backups should be rejected
+	corrupted := make([]byte, len(backup))
+	copy(corrupted, backup)
+	corrupted[len(backupMagic)+8] ^= 0xff
+	if _, err := ImportBackup(memdb.New(), bytes.NewReader(corrupted)); !errors.Is(err, ErrInvalidBackup) {
+		t.Fatalf("expected %v, got %v", ErrInvalidBackup, err)
+	}
+}
+
+func TestImportTruncatedBackup(t *testing.T) {
+	t.Parallel()
+
+	// Write enough data for the restore to flush batches before it reaches
+	// the end of the backup
+	db := memdb.New()
+	value := make([]byte, restoreBatchSize/2)
+	for i := byte(0); i < 4; i++ {
+		if err := db.Put([]byte{i}, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var buf bytes.Buffer
+	if _, err := ExportBackup(db, &buf); err != nil {
+		t.Fatal(err)
+	}
+	backup := buf.Bytes()
+
+	restored := memdb.New()
+	if _, err := ImportBackup(restored, bytes.NewReader(backup[:len(backup)-1])); !errors.Is(err, ErrInvalidBackup) {
+		t.Fatalf("expected %v, got %v", ErrInvalidBackup, err)
+	}
+	cursor := restored.NewIterator()
+	defer cursor.Release()
+	if cursor.Next() {
+		t.Fatalf("expected partial restore to be cleared, found %x", cursor.Key())
+	}
+
+	// The cleared database can be restored again
+	if _, err := ImportBackup(restored, bytes.NewReader(backup)); err != nil {
+		t.Fatal(err)
+	}
+}
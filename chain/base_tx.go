@@ -17,6 +17,12 @@ type BaseTx struct {
 
 	// Price is the value per unit to spend on this transaction.
 	Price uint64 `serialize:"true" json:"price"`
+
+	// ChainID is the ID of the chain this transaction may be executed on, to
+	// protect against replay attacks on forked or parallel chains.
+	// Transactions signed before this field was added have no chain ID (see
+	// [legacyCodecVersion]).
+	ChainID ids.ID `serializeV1:"true" json:"chainId"`
 }
 
 func (b *BaseTx) GetBlockID() ids.ID {
@@ -35,6 +41,23 @@ func (b *BaseTx) SetMagic(magic uint64) {
 	b.Magic = magic
 }
 
+func (b *BaseTx) GetChainID() ids.ID {
+	return b.ChainID
+}
+
+func (b *BaseTx) SetChainID(chainID ids.ID) {
+	b.ChainID = chainID
+}
+
+// typedChainID is the chain ID in the typed data domain, which is omitted for
+// transactions that are not bound to a chain
+func (b *BaseTx) typedChainID() string {
+	if b.ChainID == ids.Empty {
+		return ""
+	}
+	return b.ChainID.String()
+}
+
 func (b *BaseTx) GetPrice() uint64 {
 	return b.Price
 }
@@ -56,20 +79,23 @@ func (b *BaseTx) ExecuteBase(g *Genesis) error {
 	return nil
 }
 
-func (b *BaseTx) FeeUnits(g *Genesis) uint64 {
+func (b *BaseTx) FeeUnits(g *Genesis) Units {
 	return g.BaseTxUnits
 }
 
-func (b *BaseTx) LoadUnits(g *Genesis) uint64 {
+func (b *BaseTx) LoadUnits(g *Genesis) Units {
 	return b.FeeUnits(g)
 }
 
 func (b *BaseTx) Copy() *BaseTx {
 	blockID := ids.ID{}
 	copy(blockID[:], b.BlockID[:])
+	chainID := ids.ID{}
+	copy(chainID[:], b.ChainID[:])
 	return &BaseTx{
 		BlockID: blockID,
 		Magic:   b.Magic,
 		Price:   b.Price,
+		ChainID: chainID,
 	}
 }
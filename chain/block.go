@@ -12,11 +12,18 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	log "github.com/inconshreveable/log15"
 )
 
-const futureBound = 10 * time.Second
+const (
+	futureBound = 10 * time.Second
+
+	// ExpiryBudget is the number of value keys of expired spaces deleted while
+	// executing each block. Any remaining keys are pruned asynchronously.
+	ExpiryBudget = 256
+)
 
 var _ snowman.Block = &StatelessBlock{}
 
@@ -27,6 +34,10 @@ type StatefulBlock struct {
 	Price  uint64         `serialize:"true" json:"price"`
 	Cost   uint64         `serialize:"true" json:"cost"`
 	Txs    []*Transaction `serialize:"true" json:"txs"`
+
+	// Beneficiary (if not empty) is credited with
+	// [Genesis.BlockBeneficiaryShare] of the fees paid in the block
+	Beneficiary common.Address `serialize:"true" json:"beneficiary"`
 }
 
 // Stateless is defined separately from "Block"
@@ -41,6 +52,8 @@ type StatelessBlock struct {
 	bytes []byte
 
 	Winners map[ids.ID]*Activity
+	// BuilderReward is the reward credited to [Beneficiary] (nil if none)
+	BuilderReward *Activity
 
 	vm         VM
 	children   []*StatelessBlock
@@ -67,8 +80,14 @@ func ParseBlock(
 	vm VM,
 ) (*StatelessBlock, error) {
 	blk := new(StatefulBlock)
-	if _, err := Unmarshal(source, blk); err != nil {
-		return nil, err
+	version, err := Unmarshal(source, blk)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBlockBytes, err)
+	}
+	if expected := codecVersion(blk); version != expected {
+		// Blocks are re-encoded when loaded from disk, so they must use the
+		// codec version they are re-encoded with to keep their IDs
+		return nil, fmt.Errorf("%w: codec version %d, expected %d", ErrInvalidBlockBytes, version, expected)
 	}
 	return ParseStatefulBlock(blk, source, status, vm)
 }
@@ -99,11 +118,8 @@ func ParseStatefulBlock(
 		return nil, err
 	}
 	b.id = id
-	g := vm.Genesis()
-	for _, tx := range blk.Txs {
-		if err := tx.Init(g); err != nil {
-			return nil, err
-		}
+	if err := vm.Verifier().InitAll(vm.Genesis(), blk.Txs); err != nil {
+		return nil, err
 	}
 	return b, nil
 }
@@ -122,13 +138,7 @@ func (b *StatelessBlock) init() error {
 	}
 	b.id = id
 	b.t = time.Unix(b.StatefulBlock.Tmstmp, 0)
-	g := b.vm.Genesis()
-	for _, tx := range b.StatefulBlock.Txs {
-		if err := tx.Init(g); err != nil {
-			return err
-		}
-	}
-	return nil
+	return b.vm.Verifier().InitAll(b.vm.Genesis(), b.StatefulBlock.Txs)
 }
 
 // implements "snowman.Block.choices.Decidable"
@@ -140,10 +150,11 @@ func (b *StatelessBlock) verify() (*StatelessBlock, *versiondb.Database, error)
 	g := b.vm.Genesis()
 
 	// Perform basic correctness checks before doing any expensive work
-	if len(b.Txs) == 0 {
+	heartbeat := len(b.Txs) == 0
+	if heartbeat && g.HeartbeatInterval == 0 {
 		return nil, nil, ErrNoTxs
 	}
-	if b.Timestamp().Unix() >= time.Now().Add(futureBound).Unix() {
+	if b.Timestamp().Unix() >= b.vm.Now().Add(futureBound).Unix() {
 		return nil, nil, ErrTimestampTooLate
 	}
 	blockSize := uint64(0)
@@ -155,6 +166,7 @@ func (b *StatelessBlock) verify() (*StatelessBlock, *versiondb.Database, error)
 	}
 
 	// Verify parent is available
+	bootstrapped := b.vm.IsBootstrapped()
 	parent, err := b.vm.GetStatelessBlock(b.Prnt)
 	if err != nil {
 		log.Debug("could not get parent", "id", b.Prnt)
@@ -163,16 +175,36 @@ func (b *StatelessBlock) verify() (*StatelessBlock, *versiondb.Database, error)
 	if b.Timestamp().Unix() < parent.Timestamp().Unix() {
 		return nil, nil, ErrTimestampTooEarly
 	}
-
-	context, err := b.vm.ExecutionContext(b.Tmstmp, parent)
-	if err != nil {
-		return nil, nil, err
+	// Empty (heartbeat) blocks may only be produced once the chain has been
+	// idle for [HeartbeatInterval]
+	if heartbeat && b.Tmstmp-parent.Tmstmp < g.HeartbeatInterval {
+		return nil, nil, fmt.Errorf(
+			"%w: heartbeat %ds after parent (interval=%ds)",
+			ErrNoTxs, b.Tmstmp-parent.Tmstmp, g.HeartbeatInterval,
+		)
 	}
-	if b.Cost != context.NextCost {
-		return nil, nil, ErrInvalidCost
+
+	// Blocks executed while bootstrapping were already decided by the network,
+	// so there is no need to walk the lookback window to recompute the
+	// expected cost and price
+	context := &Context{
+		ChainID:       b.vm.ChainID(),
+		Bootstrapping: true,
+		Atomic:        b.vm.Atomic(),
+		NextCost:      b.Cost,
+		NextPrice:     b.Price,
 	}
-	if b.Price != context.NextPrice {
-		return nil, nil, ErrInvalidPrice
+	if bootstrapped {
+		context, err = b.vm.ExecutionContext(b.Tmstmp, parent)
+		if err != nil {
+			return nil, nil, err
+		}
+		if b.Cost != context.NextCost {
+			return nil, nil, ErrInvalidCost
+		}
+		if b.Price != context.NextPrice {
+			return nil, nil, ErrInvalidPrice
+		}
 	}
 
 	parentState, err := parent.onAccept()
@@ -182,27 +214,62 @@ func (b *StatelessBlock) verify() (*StatelessBlock, *versiondb.Database, error)
 	onAcceptDB := versiondb.New(parentState)
 
 	// Remove all expired spaces
-	if err := ExpireNext(onAcceptDB, parent.Tmstmp, b.Tmstmp, b.vm.IsBootstrapped()); err != nil {
+	if err := ExpireNext(onAcceptDB, parent.Tmstmp, b.Tmstmp, ExpiryBudget); err != nil {
+		return nil, nil, err
+	}
+	// Transfer all spaces whose scheduled moves activated
+	if err := ActivateMoves(onAcceptDB, b.Tmstmp); err != nil {
 		return nil, nil, err
 	}
 
 	// Process new transactions
 	log.Debug("build context", "height", b.Hght, "price", b.Price, "cost", b.Cost)
 	surplusFee := uint64(0)
+	fees := uint64(0)
 	for _, tx := range b.Txs {
 		if err := tx.Execute(g, onAcceptDB, b, context); err != nil {
 			return nil, nil, err
 		}
 		surplusFee += (tx.GetPrice() - b.Price) * tx.FeeUnits(g)
+		fees += tx.GetPrice() * tx.FeeUnits(g)
 	}
 	// Ensure enough fee is paid to compensate for block production speed
+	// (heartbeats pay no fees, so they are instead rate limited by
+	// [HeartbeatInterval])
 	requiredSurplus := b.Price * b.Cost
-	if surplusFee < requiredSurplus {
+	if !heartbeat && surplusFee < requiredSurplus {
 		return nil, nil, fmt.Errorf("%w: required=%d found=%d", ErrInsufficientSurplus, requiredSurplus, surplusFee)
 	}
+	if err := b.rewardBeneficiary(g, onAcceptDB, fees); err != nil {
+		return nil, nil, err
+	}
 	return parent, onAcceptDB, nil
 }
 
+// rewardBeneficiary credits the beneficiary of the block with its share of
+// [fees] (the rest is burned). The credit is written to the block's state, so
+// it only takes effect once the block is accepted.
+func (b *StatelessBlock) rewardBeneficiary(g *Genesis, db database.Database, fees uint64) error {
+	b.BuilderReward = nil
+	if b.Beneficiary == zeroAddress {
+		return nil
+	}
+	reward := fees * g.BlockBeneficiaryShare / BeneficiaryDivisor
+	if reward == 0 {
+		return nil
+	}
+	if _, err := ModifyBalance(db, b.Beneficiary, true, reward); err != nil {
+		return err
+	}
+	b.BuilderReward = &Activity{
+		Tmstmp: b.Tmstmp,
+		Typ:    Reward,
+		To:     b.Beneficiary.Hex(),
+		Units:  reward,
+	}
+	return nil
+}
+
 // implements "snowman.Block"
 func (b *StatelessBlock) Verify() error {
 	parent, onAcceptDB, err := b.verify()
@@ -212,6 +279,16 @@ func (b *StatelessBlock) Verify() error {
 	}
 	b.onAcceptDB = onAcceptDB
 
+	// Index activity so it is persisted with the rest of the block. Indexing is
+	// deferred while bootstrapping and caught up once bootstrapping finishes.
+	if b.vm.IsBootstrapped() {
+		if err := IndexHistory(b.onAcceptDB, b); err != nil {
+			return err
+		}
+	} else if err := DeferHistory(b.onAcceptDB, b); err != nil {
+		return err
+	}
+
 	// Set last accepted block and store
 	if err := SetLastAccepted(b.onAcceptDB, b); err != nil {
 		return err
@@ -224,19 +301,46 @@ func (b *StatelessBlock) Verify() error {
 
 // implements "snowman.Block.choices.Decidable"
 func (b *StatelessBlock) Accept() error {
-	if err := b.onAcceptDB.Commit(); err != nil {
+	if b.onAcceptDB == nil {
+		return ErrBlockReleased
+	}
+	// All state changes, tx indexes, linked values, block bytes, and the last
+	// accepted pointer were written to [onAcceptDB] during verification, so
+	// they are persisted together in a single batch (along with any shared
+	// memory operations).
+	if err := b.commit(); err != nil {
 		return err
 	}
-	for _, child := range b.children {
-		if err := child.onAcceptDB.SetDatabase(b.vm.State()); err != nil {
-			return err
-		}
+	if err := b.SetChildrenDB(b.vm.State()); err != nil {
+		return err
 	}
 	b.st = choices.Accepted
 	b.vm.Accepted(b)
 	return nil
 }
 
+// commit persists [onAcceptDB], atomically applying the shared memory
+// operations of the block's atomic transactions
+func (b *StatelessBlock) commit() error {
+	requests, err := atomicRequests(b.vm.Genesis(), b.Txs)
+	if err != nil {
+		return err
+	}
+	if len(requests) == 0 {
+		return b.onAcceptDB.Commit()
+	}
+	atomic := b.vm.Atomic()
+	if atomic == nil {
+		return ErrAtomicUnavailable
+	}
+	batch, err := b.onAcceptDB.CommitBatch()
+	if err != nil {
+		return err
+	}
+	defer b.onAcceptDB.Abort()
+	return atomic.SharedMemory().Apply(requests, batch)
+}
+
 // implements "snowman.Block.choices.Decidable"
 func (b *StatelessBlock) Reject() error {
 	b.st = choices.Rejected
@@ -259,8 +363,38 @@ func (b *StatelessBlock) Height() uint64 { return b.StatefulBlock.Hght }
 // implements "snowman.Block"
 func (b *StatelessBlock) Timestamp() time.Time { return b.t }
 
+// BlockHeader is every field of a block except its transactions, for light
+// clients that follow the chain without executing it.
+type BlockHeader struct {
+	BlockID     ids.ID         `serialize:"true" json:"blockId"`
+	Parent      ids.ID         `serialize:"true" json:"parent"`
+	Height      uint64         `serialize:"true" json:"height"`
+	Timestamp   int64          `serialize:"true" json:"timestamp"`
+	Price       uint64         `serialize:"true" json:"price"`
+	Cost        uint64         `serialize:"true" json:"cost"`
+	Beneficiary common.Address `serialize:"true" json:"beneficiary"`
+	Txs         int            `serialize:"true" json:"txs"`
+}
+
+func (b *StatelessBlock) Header() *BlockHeader {
+	return &BlockHeader{
+		BlockID:     b.id,
+		Parent:      b.Prnt,
+		Height:      b.Hght,
+		Timestamp:   b.Tmstmp,
+		Price:       b.Price,
+		Cost:        b.Cost,
+		Beneficiary: b.Beneficiary,
+		Txs:         len(b.Txs),
+	}
+}
+
 func (b *StatelessBlock) SetChildrenDB(db database.Database) error {
 	for _, child := range b.children {
+		if child.onAcceptDB == nil {
+			// Released
+			continue
+		}
 		if err := child.onAcceptDB.SetDatabase(db); err != nil {
 			return err
 		}
@@ -268,6 +402,13 @@ func (b *StatelessBlock) SetChildrenDB(db database.Database) error {
 	return nil
 }
 
+// StateView returns the state after [b] is applied. For verified blocks that
+// have not been accepted, this includes the changes of their processing
+// ancestors.
+func (b *StatelessBlock) StateView() (database.Database, error) {
+	return b.onAccept()
+}
+
 func (b *StatelessBlock) onAccept() (database.Database, error) {
 	if b.st == choices.Accepted || b.Hght == 0 /* genesis */ {
 		return b.vm.State(), nil
@@ -278,6 +419,17 @@ func (b *StatelessBlock) onAccept() (database.Database, error) {
 	return nil, ErrParentBlockNotVerified
 }
 
+// Release drops the uncommitted state of a processing block that can no longer
+// be accepted (and the state views of its descendants). The block can't be
+// accepted afterwards.
+func (b *StatelessBlock) Release() {
+	if b.onAcceptDB == nil {
+		return
+	}
+	b.onAcceptDB.Abort()
+	b.onAcceptDB = nil
+}
+
 func (b *StatelessBlock) addChild(c *StatelessBlock) {
 	b.children = append(b.children, c)
 }
@@ -4,12 +4,15 @@
 package chain
 
 import (
+	"encoding/binary"
 	"errors"
 	"testing"
 	"time"
 
+	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	gomock "github.com/golang/mock/gomock"
 )
@@ -147,6 +150,28 @@ func TestBlock(t *testing.T) {
 			},
 			expectedVerifyErr: ErrInvalidPrice,
 		},
+		{
+			// cost and price are not recomputed while bootstrapping
+			createBlk: func() *StatelessBlock {
+				blk := createTestBlk(
+					t,
+					&StatelessBlock{
+						StatefulBlock: &StatefulBlock{
+							Tmstmp: 1,
+							Prnt:   ids.GenerateTestID(),
+							Hght:   1, Price: 1000, Cost: 1000,
+						},
+						st: choices.Processing,
+					},
+					2,
+					&Context{NextPrice: 1, NextCost: 1},
+					nil,
+					1,
+				)
+				return blk
+			},
+			expectedVerifyErr: ErrParentBlockNotVerified,
+		},
 	}
 	for i, tv := range tt {
 		blk := tv.createBlk()
@@ -170,6 +195,9 @@ func createTestBlk(
 	ctrl := gomock.NewController(t)
 	vm := NewMockVM(ctrl)
 	vm.EXPECT().Genesis().Return(DefaultGenesis()).AnyTimes()
+	vm.EXPECT().Verifier().Return(nil).AnyTimes()
+	vm.EXPECT().Now().Return(time.Now()).AnyTimes()
+	vm.EXPECT().ChainID().Return(ids.Empty).AnyTimes()
 	parentBlk.vm = vm
 	if err := parentBlk.init(); err != nil {
 		t.Fatal(err)
@@ -194,6 +222,10 @@ func createTestBlk(
 	for i := 0; i < txsN; i++ {
 		blk.StatefulBlock.Txs[i] = createTestTx(t, blk.id, priv)
 	}
+	// Blocks without an execution context are verified as if bootstrapping,
+	// so [ExecutionContext] must not be called
+	vm.EXPECT().IsBootstrapped().Return(execCtx != nil).AnyTimes()
+	vm.EXPECT().Atomic().Return(nil).AnyTimes()
 	if execCtx != nil {
 		execCtx.RecentBlockIDs.Add(parentBlk.ID(), blk.id)
 		vm.EXPECT().ExecutionContext(blkTmpstp, parentBlk).Return(execCtx, nil)
@@ -201,3 +233,135 @@ func createTestBlk(
 
 	return blk
 }
+
+func TestBlockHeader(t *testing.T) {
+	t.Parallel()
+
+	parent := &StatelessBlock{
+		StatefulBlock: &StatefulBlock{Tmstmp: 1, Prnt: ids.GenerateTestID(), Hght: 1},
+		st:            choices.Accepted,
+	}
+	blk := createTestBlk(t, parent, 2, &Context{NextPrice: 3, NextCost: 4}, nil, 2)
+	blk.Beneficiary = common.HexToAddress("0x01")
+
+	h := blk.Header()
+	if h.BlockID != blk.ID() || h.Parent != parent.ID() || h.Height != 2 || h.Timestamp != 2 {
+		t.Fatalf("unexpected header %+v", h)
+	}
+	if h.Price != 3 || h.Cost != 4 || h.Beneficiary != blk.Beneficiary || h.Txs != 2 {
+		t.Fatalf("unexpected header %+v", h)
+	}
+}
+
+func TestRewardBeneficiary(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.New()
+	defer db.Close()
+	g := DefaultGenesis()
+	beneficiary := common.Address{0x1}
+
+	blk := &StatelessBlock{StatefulBlock: &StatefulBlock{Tmstmp: 1}}
+	if err := blk.rewardBeneficiary(g, db, 1000); err != nil {
+		t.Fatal(err)
+	}
+	if blk.BuilderReward != nil {
+		t.Fatal("unexpected reward without a beneficiary")
+	}
+
+	blk.Beneficiary = beneficiary
+	if err := blk.rewardBeneficiary(g, db, 1000); err != nil {
+		t.Fatal(err)
+	}
+	expected := 1000 * g.BlockBeneficiaryShare / BeneficiaryDivisor
+	if bal, err := GetBalance(db, beneficiary); err != nil || bal != expected {
+		t.Fatalf("expected beneficiary balance %d, got %d %v", expected, bal, err)
+	}
+	if blk.BuilderReward == nil || blk.BuilderReward.Units != expected || blk.BuilderReward.To != beneficiary.Hex() {
+		t.Fatalf("unexpected reward activity %+v", blk.BuilderReward)
+	}
+}
+
+func TestLegacyCodecVersion(t *testing.T) {
+	t.Parallel()
+
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Transactions without a chain ID keep the encoding (and so the ID and
+	// signer) they had before transactions were bound to a chain
+	legacy := createTestTx(t, ids.GenerateTestID(), priv)
+	if domain := legacy.TypedData().Types["EIP712Domain"]; len(domain) != 2 {
+		t.Fatal("legacy domain should not include the chain ID")
+	}
+	legacyBytes, err := codecManager.Marshal(legacyCodecVersion, legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := new(Transaction)
+	if _, err := Unmarshal(legacyBytes, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if err := decoded.Init(DefaultGenesis()); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.ID() != legacy.ID() || decoded.ID() != ids.ID(crypto.Keccak256Hash(legacyBytes)) {
+		t.Fatalf("legacy tx ID changed from %s to %s", legacy.ID(), decoded.ID())
+	}
+	if decoded.Sender() != crypto.PubkeyToAddress(priv.PublicKey) {
+		t.Fatalf("unexpected legacy tx sender %s", decoded.Sender())
+	}
+
+	bound := createTestTx(t, ids.GenerateTestID(), priv)
+	bound.SetChainID(ids.ID{1})
+	if err := bound.Init(DefaultGenesis()); err != nil {
+		t.Fatal(err)
+	}
+	decoded = new(Transaction)
+	if version, err := Unmarshal(bound.Bytes(), decoded); err != nil || version != CodecVersion {
+		t.Fatalf("unexpected codec version %d (err=%v)", version, err)
+	}
+	if decoded.GetChainID() != (ids.ID{1}) {
+		t.Fatalf("unexpected chain ID %s", decoded.GetChainID())
+	}
+
+	// Blocks must use the codec version of their transactions
+	ctrl := gomock.NewController(t)
+	vm := NewMockVM(ctrl)
+	vm.EXPECT().Genesis().Return(DefaultGenesis()).AnyTimes()
+	vm.EXPECT().Verifier().Return(nil).AnyTimes()
+	tt := []struct {
+		txs     []*Transaction
+		version uint16
+	}{
+		{version: legacyCodecVersion},
+		{txs: []*Transaction{legacy}, version: legacyCodecVersion},
+		{txs: []*Transaction{legacy, bound}, version: CodecVersion},
+	}
+	for i, tv := range tt {
+		blk := &StatefulBlock{Prnt: ids.GenerateTestID(), Hght: 1, Txs: tv.txs}
+		b, err := Marshal(blk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if version := binary.BigEndian.Uint16(b); version != tv.version {
+			t.Fatalf("#%d: expected codec version %d, got %d", i, tv.version, version)
+		}
+		if _, err := ParseBlock(b, choices.Processing, vm); err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if tv.version == CodecVersion {
+			continue
+		}
+		// Legacy blocks re-encoded with the current codec version would get a
+		// different ID
+		b, err = codecManager.Marshal(CodecVersion, blk)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ParseBlock(b, choices.Processing, vm); !errors.Is(err, ErrInvalidBlockBytes) {
+			t.Fatalf("#%d: expected %v, got %v", i, ErrInvalidBlockBytes, err)
+		}
+	}
+}
@@ -4,8 +4,6 @@
 package chain
 
 import (
-	"time"
-
 	"github.com/ava-labs/avalanchego/database/versiondb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
@@ -16,7 +14,7 @@ func BuildBlock(vm VM, preferred ids.ID) (snowman.Block, error) {
 	g := vm.Genesis()
 
 	log.Debug("attempting block building")
-	nextTime := time.Now().Unix()
+	nextTime := vm.Now().Unix()
 	parent, err := vm.GetStatelessBlock(preferred)
 	if err != nil {
 		log.Debug("block building failed: couldn't get parent", "err", err)
@@ -28,6 +26,7 @@ func BuildBlock(vm VM, preferred ids.ID) (snowman.Block, error) {
 		return nil, err
 	}
 	b := NewBlock(vm, parent, nextTime, context)
+	b.Beneficiary = vm.Beneficiary()
 
 	// Clean out invalid txs
 	mempool := vm.Mempool()
@@ -41,7 +40,11 @@ func BuildBlock(vm VM, preferred ids.ID) (snowman.Block, error) {
 	vdb := versiondb.New(parentDB)
 
 	// Remove all expired spaces
-	if err := ExpireNext(vdb, parent.Tmstmp, b.Tmstmp, true); err != nil {
+	if err := ExpireNext(vdb, parent.Tmstmp, b.Tmstmp, ExpiryBudget); err != nil {
+		return nil, err
+	}
+	// Transfer all spaces whose scheduled moves activated
+	if err := ActivateMoves(vdb, b.Tmstmp); err != nil {
 		return nil, err
 	}
 
@@ -74,6 +77,7 @@ func BuildBlock(vm VM, preferred ids.ID) (snowman.Block, error) {
 		tvdb := versiondb.New(vdb)
 		if err := next.Execute(g, tvdb, b, context); err != nil {
 			log.Debug("skipping tx: failed verification", "err", err)
+			mempool.Drop(next.ID(), DropInvalid)
 			continue
 		}
 		if err := tvdb.Commit(); err != nil {
@@ -81,6 +85,7 @@ func BuildBlock(vm VM, preferred ids.ID) (snowman.Block, error) {
 		}
 		// Wait to add spaces until after verification
 		b.Txs = append(b.Txs, next)
+		mempool.Drop(next.ID(), DropIncluded)
 		units += nextLoad
 	}
 	vdb.Abort()
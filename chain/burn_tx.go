@@ -0,0 +1,93 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ava-labs/spacesvm/parser"
+	"github.com/ava-labs/spacesvm/tdata"
+)
+
+var _ UnsignedTransaction = &BurnTx{}
+
+type BurnTx struct {
+	*BaseTx `serialize:"true" json:"baseTx"`
+
+	// Space is the namespace for the "SpaceInfo"
+	// whose owner can write and read value for the
+	// specific key space.
+	// The space must be ^[a-z0-9]{1,256}$.
+	Space string `serialize:"true" json:"space"`
+
+	// Cooldown is the number of seconds after the burn before [Space] may be
+	// claimed again (at most [Genesis.MaxBurnCooldown]).
+	Cooldown uint64 `serialize:"true" json:"cooldown"`
+}
+
+// Execute retires the space immediately, as if it expired: its info is
+// removed and its values are scheduled for pruning. Spaces with frozen keys
+// can't be burned (see [FreezeTx]).
+func (b *BurnTx) Execute(t *TransactionContext) error {
+	g := t.Genesis
+	if err := parser.CheckContents(b.Space); err != nil {
+		return err
+	}
+	if b.Cooldown > g.MaxBurnCooldown {
+		return fmt.Errorf("%w: max=%d found=%d", ErrCooldownTooLong, g.MaxBurnCooldown, b.Cooldown)
+	}
+
+	// Verify space is owned by sender
+	i, err := verifySpace(b.Space, t)
+	if err != nil {
+		return err
+	}
+	if err := i.Policy.check(t, Burn, b, 0); err != nil {
+		return err
+	}
+	if err := i.checkUnfrozen(t.BlockTime); err != nil {
+		return err
+	}
+	if err := t.State.BurnSpace([]byte(b.Space), i, t.BlockTime); err != nil {
+		return err
+	}
+	if b.Cooldown == 0 {
+		return nil
+	}
+	return t.State.PutBurned([]byte(b.Space), t.BlockTime+b.Cooldown)
+}
+
+func (b *BurnTx) Copy() UnsignedTransaction {
+	return &BurnTx{
+		BaseTx:   b.BaseTx.Copy(),
+		Space:    b.Space,
+		Cooldown: b.Cooldown,
+	}
+}
+
+func (b *BurnTx) TypedData() *tdata.TypedData {
+	return tdata.CreateTypedData(
+		b.Magic, b.typedChainID(), Burn,
+		[]tdata.Type{
+			{Name: tdSpace, Type: tdString},
+			{Name: tdCooldown, Type: tdUint64},
+			{Name: tdPrice, Type: tdUint64},
+			{Name: tdBlockID, Type: tdString},
+		},
+		tdata.TypedDataMessage{
+			tdSpace:    b.Space,
+			tdCooldown: strconv.FormatUint(b.Cooldown, 10),
+			tdPrice:    strconv.FormatUint(b.Price, 10),
+			tdBlockID:  b.BlockID.String(),
+		},
+	)
+}
+
+func (b *BurnTx) Activity() *Activity {
+	return &Activity{
+		Typ:   Burn,
+		Space: b.Space,
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestBurnTx(t *testing.T) {
+	t.Parallel()
+
+	owner := common.Address{0x1}
+	other := common.Address{0x2}
+	db := memdb.New()
+	defer db.Close()
+
+	g := DefaultGenesis()
+	g.MaxBurnCooldown = 100
+	tt := []struct {
+		utx       UnsignedTransaction
+		blockTime uint64
+		sender    common.Address
+		err       error
+	}{
+		{
+			utx:       &ClaimTx{BaseTx: &BaseTx{}, Space: "foo"},
+			blockTime: 1,
+			sender:    owner,
+		},
+		{
+			utx:       &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: []byte("abc")},
+			blockTime: 1,
+			sender:    owner,
+		},
+		{
+			utx:       &MoveTx{BaseTx: &BaseTx{}, Space: "foo", To: other, Activation: 50},
+			blockTime: 1,
+			sender:    owner,
+		},
+		{
+			utx:       &BurnTx{BaseTx: &BaseTx{}, Space: "foo"},
+			blockTime: 2,
+			sender:    other,
+			err:       ErrUnauthorized,
+		},
+		{
+			utx:       &BurnTx{BaseTx: &BaseTx{}, Space: "foo", Cooldown: 101},
+			blockTime: 2,
+			sender:    owner,
+			err:       ErrCooldownTooLong,
+		},
+		{
+			utx:       &BurnTx{BaseTx: &BaseTx{}, Space: "foo", Cooldown: 100},
+			blockTime: 2,
+			sender:    owner,
+		},
+		{
+			utx:       &BurnTx{BaseTx: &BaseTx{}, Space: "foo"},
+			blockTime: 2,
+			sender:    owner,
+			err:       ErrSpaceMissing,
+		},
+		{
+			utx:       &ClaimTx{BaseTx: &BaseTx{}, Space: "foo"},
+			blockTime: 101,
+			sender:    other,
+			err:       ErrSpaceBurned,
+		},
+		{
+			utx:       &ClaimTx{BaseTx: &BaseTx{}, Space: "foo"},
+			blockTime: 102,
+			sender:    other,
+		},
+	}
+	for i, tv := range tt {
+		tc := &TransactionContext{Genesis: g, State: NewStateDB(db), BlockTime: tv.blockTime, Sender: tv.sender}
+		if err := tv.utx.Execute(tc); !errors.Is(err, tv.err) {
+			t.Fatalf("#%d: tx.Execute err expected %v, got %v", i, tv.err, err)
+		}
+		if i != 5 {
+			continue
+		}
+
+		// The burned space is removed immediately and pruned later
+		if owned, err := GetAllOwned(db, owner); err != nil || len(owned) != 0 {
+			t.Fatalf("expected owner to own nothing, got %v (err=%v)", owned, err)
+		}
+		if stats, err := GetStateStats(db); err != nil || stats.Spaces != 0 {
+			t.Fatalf("expected no spaces, got %+v (err=%v)", stats, err)
+		}
+		removals, keys, err := PruneNext(db, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if removals != 1 || keys != 1 {
+			t.Fatalf("expected 1 space with 1 key to be pruned, got %d and %d", removals, keys)
+		}
+		// Its scheduled move is discarded
+		if err := ActivateMoves(db, 50); err != nil {
+			t.Fatal(err)
+		}
+		if owned, err := GetAllOwned(db, other); err != nil || len(owned) != 0 {
+			t.Fatalf("expected recipient to own nothing, got %v (err=%v)", owned, err)
+		}
+	}
+	i, _, err := GetSpaceInfo(db, []byte("foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i.Owner != other || i.Size != 0 {
+		t.Fatalf("expected a new space owned by %s, got %+v", other, i)
+	}
+}
+
+func TestBurnTxReclaimSameSecond(t *testing.T) {
+	t.Parallel()
+
+	owner := common.Address{0x1}
+	other := common.Address{0x2}
+	db := memdb.New()
+	defer db.Close()
+
+	g := DefaultGenesis()
+	tt := []struct {
+		utx    UnsignedTransaction
+		sender common.Address
+	}{
+		{utx: &ClaimTx{BaseTx: &BaseTx{}, Space: "foo"}, sender: owner},
+		{utx: &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: []byte("abc")}, sender: owner},
+		{utx: &BurnTx{BaseTx: &BaseTx{}, Space: "foo"}, sender: owner},
+		{utx: &ClaimTx{BaseTx: &BaseTx{}, Space: "foo"}, sender: other},
+	}
+	var burned ids.ShortID
+	for i, tv := range tt {
+		if i == 2 {
+			info, _, err := GetSpaceInfo(db, []byte("foo"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			burned = info.RawSpace
+		}
+		tc := &TransactionContext{
+			Genesis:   g,
+			State:     NewStateDB(db),
+			BlockTime: 1,
+			TxID:      ids.GenerateTestID(),
+			Sender:    tv.sender,
+		}
+		if err := tv.utx.Execute(tc); err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+	}
+
+	// The new space does not see the values of the burned one
+	info, _, err := GetSpaceInfo(db, []byte("foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.RawSpace == burned {
+		t.Fatal("expected the new space to have a new raw space")
+	}
+	if has, err := HasSpaceKey(db, []byte("foo"), []byte("bar")); err != nil || has {
+		t.Fatalf("expected burned key to be hidden from the new space (err=%v)", err)
+	}
+
+	// Pruning the burned space leaves the new space intact
+	tc := &TransactionContext{Genesis: g, State: NewStateDB(db), BlockTime: 1, TxID: ids.GenerateTestID(), Sender: other}
+	if err := (&SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "baz", Value: []byte("abc")}).Execute(tc); err != nil {
+		t.Fatal(err)
+	}
+	if _, keys, err := PruneNext(db, 10); err != nil || keys != 1 {
+		t.Fatalf("expected 1 burned key to be pruned, got %d (err=%v)", keys, err)
+	}
+	if has, err := HasSpaceKey(db, []byte("foo"), []byte("baz")); err != nil || !has {
+		t.Fatalf("expected key of the new space to be kept (err=%v)", err)
+	}
+}
@@ -4,10 +4,13 @@
 package chain
 
 import (
+	"fmt"
 	"strconv"
 	"strings"
 
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 
 	"github.com/ava-labs/spacesvm/parser"
 	"github.com/ava-labs/spacesvm/tdata"
@@ -28,12 +31,35 @@ type ClaimTx struct {
 	// specific key space.
 	// The space must be ^[a-z0-9]{1,256}$.
 	Space string `serialize:"true" json:"space"`
+
+	// Beneficiary (if not empty) is credited with
+	// [Genesis.ClaimBeneficiaryShare] of the claim fee, so referrers (or
+	// block builders) can be rewarded for bringing in claims.
+	Beneficiary common.Address `serialize:"true" json:"beneficiary"`
+
+	// Lease is the number of standard leases (up to
+	// [Genesis.MaxClaimLease]) bought with the space, multiplying its
+	// horizon and the claim fee. 0 buys the standard lease.
+	Lease uint64 `serialize:"true" json:"lease"`
+
+	// Salt (if not empty) reveals the [ClaimCommitment] the sender made with
+	// a [CommitTx]. Claims must reveal a commitment when
+	// [Genesis.ClaimCommitDelay] is set.
+	Salt common.Hash `serialize:"true" json:"salt"`
+
+	// Keys (up to [Genesis.MaxClaimKeys]) are set in the space atomically
+	// with the claim, as if each was set by a [SetTx] right after it, so a
+	// space can be provisioned in a single transaction.
+	Keys []*CustomKey `serialize:"true" json:"keys"`
 }
 
 func (c *ClaimTx) Execute(t *TransactionContext) error {
 	if err := parser.CheckContents(c.Space); err != nil {
 		return err
 	}
+	if c.Lease > 1 && c.Lease > t.Genesis.MaxClaimLease {
+		return fmt.Errorf("%w: max=%d found=%d", ErrLeaseTooLong, t.Genesis.MaxClaimLease, c.Lease)
+	}
 
 	// Restrict address space to be owned by address
 	if len(c.Space) == hexAddressLen && strings.ToLower(t.Sender.Hex()) != c.Space {
@@ -41,77 +67,223 @@ func (c *ClaimTx) Execute(t *TransactionContext) error {
 	}
 
 	// Space keys only exist if they are still valid
-	exists, err := HasSpace(t.Database, []byte(c.Space))
+	exists, err := t.State.HasSpace([]byte(c.Space))
 	if err != nil {
 		return err
 	}
 	if exists {
 		return ErrSpaceNotExpired
 	}
+	until, burned, err := t.State.GetBurned([]byte(c.Space))
+	if err != nil {
+		return err
+	}
+	if burned && t.BlockTime < until {
+		return fmt.Errorf("%w: claimable at %d", ErrSpaceBurned, until)
+	}
+	if err := c.reveal(t); err != nil {
+		return err
+	}
 
 	// Anything previously at the space was previously removed...
+	rspace, err := ClaimRawSpace([]byte(c.Space), t.BlockTime, t.TxID)
+	if err != nil {
+		return err
+	}
+	horizon := ClaimHorizon(t.Genesis, c.Lease)
 	newInfo := &SpaceInfo{
-		Owner:   t.Sender,
-		Created: t.BlockTime,
-		Updated: t.BlockTime,
-		Expiry:  t.BlockTime + t.Genesis.ClaimReward/t.Genesis.ClaimExpiryUnits,
-		Units:   t.Genesis.ClaimExpiryUnits,
+		Owner:    t.Sender,
+		Created:  t.BlockTime,
+		Updated:  t.BlockTime,
+		Expiry:   t.BlockTime + LifelineCredit(horizon, t.Genesis.ClaimExpiryUnits, 1),
+		Units:    t.Genesis.ClaimExpiryUnits,
+		Horizon:  horizon,
+		RawSpace: rspace,
+	}
+	if err := c.addKeys(t, newInfo); err != nil {
+		return err
+	}
+	if err := t.State.PutSpaceInfo([]byte(c.Space), newInfo, 0); err != nil {
+		return err
 	}
-	if err := PutSpaceInfo(t.Database, []byte(c.Space), newInfo, 0); err != nil {
+	if err := c.setKeys(t); err != nil {
 		return err
 	}
+	if c.Beneficiary == zeroAddress {
+		return nil
+	}
+	share := BeneficiaryShare(t.Genesis, c.Space, t.Price)
+	if share == 0 {
+		return nil
+	}
+	_, err = t.State.ModifyBalance(c.Beneficiary, true, share)
+	return err
+}
+
+// addKeys verifies [Keys] and charges their storage to [i], shortening its
+// expiry just like setting each key would.
+func (c *ClaimTx) addKeys(t *TransactionContext, i *SpaceInfo) error {
+	g := t.Genesis
+	if len(c.Keys) == 0 {
+		return nil
+	}
+	if uint64(len(c.Keys)) > g.MaxClaimKeys {
+		return fmt.Errorf("%w: max=%d found=%d", ErrTooManyClaimKeys, g.MaxClaimKeys, len(c.Keys))
+	}
+	timeRemaining := (i.Expiry - i.Updated) * i.Units
+	keys := make(map[string]struct{}, len(c.Keys))
+	for _, ck := range c.Keys {
+		if err := parser.CheckContents(ck.Key); err != nil {
+			return fmt.Errorf("%w: key=%s", err, ck.Key)
+		}
+		if _, ok := keys[ck.Key]; ok {
+			return fmt.Errorf("%w: key=%s", ErrDuplicateKey, ck.Key)
+		}
+		keys[ck.Key] = struct{}{}
+		if err := g.CheckValue(ck.Key, uint64(len(ck.Value))); err != nil {
+			return err
+		}
+		if len(ck.Key) == HashLen {
+			if h := valueHash(ck.Value); ck.Key != h {
+				return fmt.Errorf("%w: expected %s got %x", ErrInvalidKey, h, ck.Key)
+			}
+		}
+		i.Units += StorageUnits(g, uint64(len(ck.Value)))
+		i.Size += uint64(len(ck.Value))
+	}
+	if err := g.CheckSpaceSize(i.Size); err != nil {
+		return err
+	}
+	i.Expiry = t.BlockTime + timeRemaining/i.Units
 	return nil
 }
 
-// [spaceNameUnits] requires the caller to pay more to get spaces of
-// a shorter length because they are more desirable. This creates a "lottery"
-// mechanism where the people that spend the most mining power will win the
-// space.
-//
-// [spaceNameUnits] should only be called on a space that is valid
-func spaceNameUnits(g *Genesis, s string) uint64 {
-	desirability := uint64(parser.MaxIdentifierSize - len(s))
-	desirability *= g.SpaceDesirabilityMultiplier
-	if desirability < g.MinClaimFee {
-		return g.MinClaimFee
-	}
-	return desirability
+// setKeys writes [Keys] to the claimed space. Each value is linked to its
+// [ClaimValueID].
+func (c *ClaimTx) setKeys(t *TransactionContext) error {
+	for idx, ck := range c.Keys {
+		vmeta := &ValueMeta{
+			Size:    uint64(len(ck.Value)),
+			TxID:    ClaimValueID(t.TxID, idx),
+			Created: t.BlockTime,
+			Updated: t.BlockTime,
+		}
+		if err := t.State.SetValue([]byte(c.Space), []byte(ck.Key), vmeta, ValueHash(ck.Value)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func (c *ClaimTx) FeeUnits(g *Genesis) uint64 {
-	return c.LoadUnits(g) + spaceNameUnits(g, c.Space)
+// ClaimValueID is the ID the value of the [idx]th key set by the claim
+// [txID] is linked to (and that its [ValueMeta.TxID] references).
+func ClaimValueID(txID ids.ID, idx int) ids.ID {
+	return txID.Prefix(uint64(idx))
 }
 
-func (c *ClaimTx) LoadUnits(g *Genesis) uint64 {
-	return c.BaseTx.LoadUnits(g) * g.ClaimLoadMultiplier
+// keyUnits are the value units of [Keys]
+func (c *ClaimTx) keyUnits(g *Genesis) Units {
+	units := Units(0)
+	for _, ck := range c.Keys {
+		units += ValueUnits(g, uint64(len(ck.Value)))
+	}
+	return units
+}
+
+// reveal consumes the commitment to this claim, if one is required.
+func (c *ClaimTx) reveal(t *TransactionContext) error {
+	g := t.Genesis
+	if g.ClaimCommitDelay == 0 && c.Salt == (common.Hash{}) {
+		return nil
+	}
+	commitment := ClaimCommitment(t.Sender, c.Space, c.Salt)
+	committed, exists, err := t.State.GetCommitment(commitment)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrCommitmentMissing, commitment)
+	}
+	if t.BlockTime < committed+g.ClaimCommitDelay {
+		return fmt.Errorf("%w: revealable at %d", ErrCommitmentTooRecent, committed+g.ClaimCommitDelay)
+	}
+	if g.ClaimCommitExpiry > 0 && t.BlockTime > committed+g.ClaimCommitExpiry {
+		return fmt.Errorf("%w: expired at %d", ErrCommitmentExpired, committed+g.ClaimCommitExpiry)
+	}
+	return t.State.DeleteCommitment(commitment)
+}
+
+func (c *ClaimTx) FeeUnits(g *Genesis) Units {
+	leases := c.Lease
+	if leases == 0 {
+		leases = 1
+	}
+	return c.LoadUnits(g) + ClaimUnits(g, c.Space)*leases
+}
+
+func (c *ClaimTx) LoadUnits(g *Genesis) Units {
+	return c.BaseTx.LoadUnits(g)*g.ClaimLoadMultiplier + c.keyUnits(g)
 }
 
 func (c *ClaimTx) Copy() UnsignedTransaction {
+	var keys []*CustomKey
+	if len(c.Keys) > 0 {
+		keys = make([]*CustomKey, len(c.Keys))
+		for i, ck := range c.Keys {
+			value := make([]byte, len(ck.Value))
+			copy(value, ck.Value)
+			keys[i] = &CustomKey{Key: ck.Key, Value: value}
+		}
+	}
 	return &ClaimTx{
-		BaseTx: c.BaseTx.Copy(),
-		Space:  c.Space,
+		BaseTx:      c.BaseTx.Copy(),
+		Space:       c.Space,
+		Beneficiary: c.Beneficiary,
+		Lease:       c.Lease,
+		Salt:        c.Salt,
+		Keys:        keys,
 	}
 }
 
 func (c *ClaimTx) TypedData() *tdata.TypedData {
+	keys := make([]interface{}, len(c.Keys))
+	values := make([]interface{}, len(c.Keys))
+	for i, ck := range c.Keys {
+		keys[i] = ck.Key
+		values[i] = hexutil.Encode(ck.Value)
+	}
 	return tdata.CreateTypedData(
-		c.Magic, Claim,
+		c.Magic, c.typedChainID(), Claim,
 		[]tdata.Type{
 			{Name: tdSpace, Type: tdString},
+			{Name: tdBeneficiary, Type: tdAddress},
+			{Name: tdLease, Type: tdUint64},
+			{Name: tdSalt, Type: tdBytes32},
+			{Name: tdKeys, Type: tdString + "[]"},
+			{Name: tdValues, Type: tdBytes + "[]"},
 			{Name: tdPrice, Type: tdUint64},
 			{Name: tdBlockID, Type: tdString},
 		},
 		tdata.TypedDataMessage{
-			tdSpace:   c.Space,
-			tdPrice:   strconv.FormatUint(c.Price, 10),
-			tdBlockID: c.BlockID.String(),
+			tdSpace:       c.Space,
+			tdBeneficiary: c.Beneficiary.Hex(),
+			tdLease:       strconv.FormatUint(c.Lease, 10),
+			tdSalt:        c.Salt.Hex(),
+			tdKeys:        keys,
+			tdValues:      values,
+			tdPrice:       strconv.FormatUint(c.Price, 10),
+			tdBlockID:     c.BlockID.String(),
 		},
 	)
 }
 
 func (c *ClaimTx) Activity() *Activity {
-	return &Activity{
+	activity := &Activity{
 		Typ:   Claim,
 		Space: c.Space,
 	}
+	if c.Beneficiary != zeroAddress {
+		activity.To = c.Beneficiary.Hex()
+	}
+	return activity
 }
@@ -6,6 +6,7 @@ package chain
 import (
 	"bytes"
 	"errors"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -87,13 +88,13 @@ func TestClaimTx(t *testing.T) {
 	for i, tv := range tt {
 		if i > 0 {
 			// Expire old spaces between txs
-			if err := ExpireNext(db, tt[i-1].blockTime, tv.blockTime, true); err != nil {
+			if err := ExpireNext(db, tt[i-1].blockTime, tv.blockTime, 0); err != nil {
 				t.Fatalf("#%d: ExpireNext errored %v", i, err)
 			}
 		}
 		tc := &TransactionContext{
 			Genesis:   g,
-			Database:  db,
+			State:     NewStateDB(db),
 			BlockTime: uint64(tv.blockTime),
 			TxID:      ids.Empty,
 			Sender:    tv.sender,
@@ -132,10 +133,10 @@ func TestClaimTx(t *testing.T) {
 	if len(sender2Spaces) != 1 {
 		t.Fatalf("sender2 owned spaces should = 1, found %d", len(sender2Spaces))
 	}
-	if err := ExpireNext(db, 0, ClaimReward*10, true); err != nil {
+	if err := ExpireNext(db, 0, ClaimReward*10, 0); err != nil {
 		t.Fatal(err)
 	}
-	pruned, err := PruneNext(db, 100)
+	pruned, _, err := PruneNext(db, 100)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -164,3 +165,170 @@ func TestClaimTx(t *testing.T) {
 		t.Fatal("owned spaces should be empty")
 	}
 }
+
+func TestClaimTxBeneficiary(t *testing.T) {
+	t.Parallel()
+
+	sender := common.Address{0x1}
+	beneficiary := common.Address{0x2}
+	db := memdb.New()
+	defer db.Close()
+
+	g := DefaultGenesis()
+	tc := &TransactionContext{Genesis: g, State: NewStateDB(db), BlockTime: 1, Sender: sender, Price: 3}
+	if err := (&ClaimTx{BaseTx: &BaseTx{}, Space: "foo"}).Execute(tc); err != nil {
+		t.Fatal(err)
+	}
+	if bal, err := GetBalance(db, beneficiary); err != nil || bal != 0 {
+		t.Fatalf("expected no balance without a beneficiary, got %d %v", bal, err)
+	}
+
+	tx := &ClaimTx{BaseTx: &BaseTx{}, Space: "bar", Beneficiary: beneficiary}
+	if err := tx.Execute(tc); err != nil {
+		t.Fatal(err)
+	}
+	expected := ClaimUnits(g, "bar") * 3 * g.ClaimBeneficiaryShare / BeneficiaryDivisor
+	if expected == 0 {
+		t.Fatal("expected non-zero share")
+	}
+	if bal, err := GetBalance(db, beneficiary); err != nil || bal != expected {
+		t.Fatalf("expected beneficiary balance %d, got %d %v", expected, bal, err)
+	}
+	if to := tx.Activity().To; to != beneficiary.Hex() {
+		t.Fatalf("expected activity to record beneficiary, got %s", to)
+	}
+}
+
+func TestClaimTxLease(t *testing.T) {
+	t.Parallel()
+
+	sender := common.Address{0x1}
+	db := memdb.New()
+	defer db.Close()
+
+	g := DefaultGenesis()
+	tc := &TransactionContext{Genesis: g, State: NewStateDB(db), BlockTime: 1, Sender: sender}
+	tx := &ClaimTx{BaseTx: &BaseTx{}, Space: "foo", Lease: g.MaxClaimLease + 1}
+	if err := tx.Execute(tc); !errors.Is(err, ErrLeaseTooLong) {
+		t.Fatalf("expected %v, got %v", ErrLeaseTooLong, err)
+	}
+
+	standard := &ClaimTx{BaseTx: &BaseTx{}, Space: "foo"}
+	premium := &ClaimTx{BaseTx: &BaseTx{}, Space: "bar", Lease: 3}
+	for _, tx := range []*ClaimTx{standard, premium} {
+		if err := tx.Execute(tc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if standard.FeeUnits(g)-standard.LoadUnits(g) != ClaimUnits(g, "foo") {
+		t.Fatal("standard lease should cost the claim units")
+	}
+	if premium.FeeUnits(g)-premium.LoadUnits(g) != 3*ClaimUnits(g, "bar") {
+		t.Fatal("premium lease should cost a multiple of the claim units")
+	}
+
+	fooInfo, _, err := GetSpaceInfo(db, []byte("foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	barInfo, _, err := GetSpaceInfo(db, []byte("bar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if SpaceHorizon(g, fooInfo) != g.ClaimReward || SpaceHorizon(g, barInfo) != 3*g.ClaimReward {
+		t.Fatalf("unexpected horizons %d and %d", fooInfo.Horizon, barInfo.Horizon)
+	}
+	if barInfo.Expiry-1 != 3*(fooInfo.Expiry-1) {
+		t.Fatalf("expected premium lease to expire 3x later, got %d and %d", fooInfo.Expiry, barInfo.Expiry)
+	}
+}
+
+func TestClaimTxKeys(t *testing.T) {
+	t.Parallel()
+
+	sender := common.Address{0x1}
+	db := memdb.New()
+	defer db.Close()
+
+	g := DefaultGenesis()
+	g.MaxClaimKeys = 2
+	tc := &TransactionContext{
+		Genesis:   g,
+		State:     NewStateDB(db),
+		BlockTime: 1,
+		TxID:      ids.GenerateTestID(),
+		Sender:    sender,
+	}
+	for i, tt := range []struct {
+		keys []*CustomKey
+		err  error
+	}{
+		{ // too many keys
+			keys: []*CustomKey{{Key: "a", Value: []byte{1}}, {Key: "b", Value: []byte{1}}, {Key: "c", Value: []byte{1}}},
+			err:  ErrTooManyClaimKeys,
+		},
+		{ // duplicate keys
+			keys: []*CustomKey{{Key: "a", Value: []byte{1}}, {Key: "a", Value: []byte{2}}},
+			err:  ErrDuplicateKey,
+		},
+		{ // value too large
+			keys: []*CustomKey{{Key: "a", Value: make([]byte, g.MaxValueSize+1)}},
+			err:  ErrValueTooBig,
+		},
+	} {
+		tx := &ClaimTx{BaseTx: &BaseTx{}, Space: "bad", Keys: tt.keys}
+		if err := tx.Execute(tc); !errors.Is(err, tt.err) {
+			t.Fatalf("#%d: expected %v, got %v", i, tt.err, err)
+		}
+	}
+	if _, exists, err := GetSpaceInfo(db, []byte("bad")); err != nil || exists {
+		t.Fatalf("failed claims should not claim the space (err=%v)", err)
+	}
+
+	empty := &ClaimTx{BaseTx: &BaseTx{}, Space: "empty"}
+	tx := &ClaimTx{
+		BaseTx: &BaseTx{},
+		Space:  "foo",
+		Keys: []*CustomKey{
+			{Key: "name", Value: []byte("hello")},
+			{Key: "config", Value: make([]byte, 20*g.ValueUnitSize)},
+		},
+	}
+	for _, tx := range []*ClaimTx{empty, tx} {
+		if err := tx.Execute(tc); err != nil {
+			t.Fatal(err)
+		}
+	}
+	bare := &ClaimTx{BaseTx: &BaseTx{}, Space: tx.Space}
+	if tx.FeeUnits(g)-bare.FeeUnits(g) != ValueUnits(g, 5)+ValueUnits(g, 20*g.ValueUnitSize) {
+		t.Fatal("keys should be charged as values")
+	}
+	for idx, ck := range tx.Keys {
+		vmeta, exists, err := GetValueMeta(db, []byte("foo"), []byte(ck.Key))
+		if err != nil || !exists {
+			t.Fatalf("%s should be set (err=%v)", ck.Key, err)
+		}
+		if vmeta.TxID != ClaimValueID(tc.TxID, idx) || vmeta.Size != uint64(len(ck.Value)) {
+			t.Fatalf("unexpected value meta %+v", vmeta)
+		}
+	}
+	i, _, err := GetSpaceInfo(db, []byte("foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ei, _, err := GetSpaceInfo(db, []byte("empty"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i.Size != 5+20*g.ValueUnitSize || i.Units <= ei.Units || i.Expiry >= ei.Expiry {
+		t.Fatalf("keys should be charged to the space %+v", i)
+	}
+
+	parsed, err := ParseTypedData(tx.TypedData())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed.(*ClaimTx).Keys, tx.Keys) {
+		t.Fatalf("keys did not round trip: %+v", parsed)
+	}
+}
@@ -6,24 +6,51 @@ package chain
 import (
 	"github.com/ava-labs/avalanchego/codec"
 	"github.com/ava-labs/avalanchego/codec/linearcodec"
+	"github.com/ava-labs/avalanchego/codec/reflectcodec"
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/ava-labs/avalanchego/utils/wrappers"
 )
 
 const (
-	// codecVersion is the current default codec version
-	codecVersion = 0
+	// CodecVersion is the current default codec version
+	CodecVersion = 1
+
+	// legacyCodecVersion is the codec version from before transactions were
+	// bound to a chain ID. Transactions without a chain ID (and blocks that
+	// only include such transactions) are still encoded with it, so blocks
+	// and transactions accepted before the upgrade keep their IDs.
+	legacyCodecVersion = 0
+
+	// v1TagName marks the fields that were added in codec version 1
+	v1TagName = "serializeV1"
 
 	// maxSize is 4MB to support large values
 	maxSize = 4 * units.MiB
+
+	// maxSliceLen is the default of [linearcodec.NewDefault]
+	maxSliceLen = 256 * 1024
 )
 
 var codecManager codec.Manager
 
 func init() {
-	c := linearcodec.NewDefault()
 	codecManager = codec.NewManager(maxSize)
 	errs := wrappers.Errs{}
+	errs.Add(
+		codecManager.RegisterCodec(legacyCodecVersion, newCodec(reflectcodec.DefaultTagName)),
+		codecManager.RegisterCodec(CodecVersion, newCodec(reflectcodec.DefaultTagName, v1TagName)),
+	)
+	if errs.Errored() {
+		panic(errs.Err)
+	}
+}
+
+// newCodec returns a codec that encodes the fields tagged with any of
+// [tagNames]. Every codec version registers the same types in the same order.
+func newCodec(tagNames ...string) codec.Codec {
+	c := linearcodec.New(tagNames, maxSliceLen)
+	errs := wrappers.Errs{}
 	errs.Add(
 		c.RegisterType(&BaseTx{}),
 		c.RegisterType(&ClaimTx{}),
@@ -38,15 +65,42 @@ func init() {
 		c.RegisterType(&CustomAllocation{}),
 		c.RegisterType(&Airdrop{}),
 		c.RegisterType(&Genesis{}),
-		codecManager.RegisterCodec(codecVersion, c),
+		c.RegisterType(&ImportTx{}),
+		c.RegisterType(&ExportTx{}),
+		c.RegisterType(&CommitTx{}),
+		c.RegisterType(&PolicyTx{}),
+		c.RegisterType(&ApproveTx{}),
+		c.RegisterType(&BurnTx{}),
+		c.RegisterType(&FreezeTx{}),
 	)
 	if errs.Errored() {
 		panic(errs.Err)
 	}
+	return c
 }
 
+// Marshal encodes [source] with the codec version it must be encoded with (see
+// [legacyCodecVersion]).
 func Marshal(source interface{}) ([]byte, error) {
-	return codecManager.Marshal(codecVersion, source)
+	return codecManager.Marshal(codecVersion(source), source)
+}
+
+// codecVersion returns the version [source] is encoded with
+func codecVersion(source interface{}) uint16 {
+	switch s := source.(type) {
+	case *Transaction:
+		if s.GetChainID() == ids.Empty {
+			return legacyCodecVersion
+		}
+	case *StatefulBlock:
+		for _, tx := range s.Txs {
+			if tx.GetChainID() != ids.Empty {
+				return CodecVersion
+			}
+		}
+		return legacyCodecVersion
+	}
+	return CodecVersion
 }
 
 func Unmarshal(source []byte, destination interface{}) (uint16, error) {
@@ -0,0 +1,76 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ava-labs/spacesvm/tdata"
+)
+
+var _ UnsignedTransaction = &CommitTx{}
+
+type CommitTx struct {
+	*BaseTx `serialize:"true" json:"baseTx"`
+
+	// Commitment is the [ClaimCommitment] of a space the sender will claim.
+	// It is revealed by a [ClaimTx] with the same salt after
+	// [Genesis.ClaimCommitDelay], so the space is not known (and can't be
+	// front-run) until the sender is able to claim it.
+	Commitment common.Hash `serialize:"true" json:"commitment"`
+}
+
+// ClaimCommitment is the commitment [sender] makes to claim [space]. Binding
+// the sender prevents a gossiped commitment from being revealed by anyone
+// else, and [salt] prevents the space from being guessed from it.
+func ClaimCommitment(sender common.Address, space string, salt common.Hash) common.Hash {
+	return crypto.Keccak256Hash(sender[:], []byte(space), salt[:])
+}
+
+func (c *CommitTx) Execute(t *TransactionContext) error {
+	if c.Commitment == (common.Hash{}) {
+		return ErrNonActionable
+	}
+	// Anyone may copy a commitment, so it can't be remade to reset its delay
+	_, exists, err := t.State.GetCommitment(c.Commitment)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrCommitmentExists
+	}
+	return t.State.PutCommitment(c.Commitment, t.BlockTime)
+}
+
+func (c *CommitTx) Copy() UnsignedTransaction {
+	return &CommitTx{
+		BaseTx:     c.BaseTx.Copy(),
+		Commitment: c.Commitment,
+	}
+}
+
+func (c *CommitTx) TypedData() *tdata.TypedData {
+	return tdata.CreateTypedData(
+		c.Magic, c.typedChainID(), Commit,
+		[]tdata.Type{
+			{Name: tdCommitment, Type: tdBytes32},
+			{Name: tdPrice, Type: tdUint64},
+			{Name: tdBlockID, Type: tdString},
+		},
+		tdata.TypedDataMessage{
+			tdCommitment: c.Commitment.Hex(),
+			tdPrice:      strconv.FormatUint(c.Price, 10),
+			tdBlockID:    c.BlockID.String(),
+		},
+	)
+}
+
+func (c *CommitTx) Activity() *Activity {
+	return &Activity{
+		Typ: Commit,
+	}
+}
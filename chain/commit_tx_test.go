@@ -0,0 +1,130 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestCommitTx(t *testing.T) {
+	t.Parallel()
+
+	sender := common.Address{0x1}
+	other := common.Address{0x2}
+	salt := common.Hash{0x3}
+	db := memdb.New()
+	defer db.Close()
+
+	g := DefaultGenesis()
+	g.ClaimCommitDelay = 10
+	g.ClaimCommitExpiry = 100
+	commitment := ClaimCommitment(sender, "foo", salt)
+	tt := []struct {
+		utx       UnsignedTransaction
+		blockTime uint64
+		sender    common.Address
+		err       error
+	}{
+		{ // claims must reveal a commitment
+			utx:       &ClaimTx{BaseTx: &BaseTx{}, Space: "foo"},
+			blockTime: 1,
+			sender:    sender,
+			err:       ErrCommitmentMissing,
+		},
+		{
+			utx:       &CommitTx{BaseTx: &BaseTx{}},
+			blockTime: 1,
+			sender:    sender,
+			err:       ErrNonActionable,
+		},
+		{
+			utx:       &CommitTx{BaseTx: &BaseTx{}, Commitment: commitment},
+			blockTime: 1,
+			sender:    sender,
+		},
+		{ // copying a commitment can't reset its delay
+			utx:       &CommitTx{BaseTx: &BaseTx{}, Commitment: commitment},
+			blockTime: 5,
+			sender:    other,
+			err:       ErrCommitmentExists,
+		},
+		{
+			utx:       &ClaimTx{BaseTx: &BaseTx{}, Space: "foo", Salt: salt},
+			blockTime: 10,
+			sender:    sender,
+			err:       ErrCommitmentTooRecent,
+		},
+		{ // commitments are bound to the sender
+			utx:       &ClaimTx{BaseTx: &BaseTx{}, Space: "foo", Salt: salt},
+			blockTime: 11,
+			sender:    other,
+			err:       ErrCommitmentMissing,
+		},
+		{
+			utx:       &ClaimTx{BaseTx: &BaseTx{}, Space: "bar", Salt: salt},
+			blockTime: 11,
+			sender:    sender,
+			err:       ErrCommitmentMissing,
+		},
+		{
+			utx:       &ClaimTx{BaseTx: &BaseTx{}, Space: "foo", Salt: salt},
+			blockTime: 11,
+			sender:    sender,
+		},
+		{ // commitments are consumed by their reveal
+			utx:       &CommitTx{BaseTx: &BaseTx{}, Commitment: ClaimCommitment(sender, "bar", salt)},
+			blockTime: 20,
+			sender:    sender,
+		},
+		{
+			utx:       &ClaimTx{BaseTx: &BaseTx{}, Space: "bar", Salt: salt},
+			blockTime: 121,
+			sender:    sender,
+			err:       ErrCommitmentExpired,
+		},
+	}
+	for i, tv := range tt {
+		tc := &TransactionContext{Genesis: g, State: NewStateDB(db), BlockTime: tv.blockTime, Sender: tv.sender}
+		if err := tv.utx.Execute(tc); !errors.Is(err, tv.err) {
+			t.Fatalf("#%d: tx.Execute err expected %v, got %v", i, tv.err, err)
+		}
+	}
+	if _, exists, err := GetCommitment(db, commitment); err != nil || exists {
+		t.Fatalf("expected revealed commitment to be removed (exists=%t err=%v)", exists, err)
+	}
+	if i, _, err := GetSpaceInfo(db, []byte("foo")); err != nil || i == nil || i.Owner != sender {
+		t.Fatalf("expected sender to own revealed space (err=%v)", err)
+	}
+}
+
+func TestCommitTxTypedData(t *testing.T) {
+	t.Parallel()
+
+	commit := &CommitTx{BaseTx: &BaseTx{ChainID: ids.ID{1}}, Commitment: common.Hash{0x1}}
+	claim := &ClaimTx{BaseTx: &BaseTx{ChainID: ids.ID{1}}, Space: "foo", Salt: common.Hash{0x2}}
+	for _, utx := range []UnsignedTransaction{commit, claim} {
+		if _, err := DigestHash(utx); err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := ParseTypedData(utx.TypedData())
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch p := parsed.(type) {
+		case *CommitTx:
+			if p.Commitment != commit.Commitment {
+				t.Fatalf("unexpected commitment %s", p.Commitment)
+			}
+		case *ClaimTx:
+			if p.Salt != claim.Salt {
+				t.Fatalf("unexpected salt %s", p.Salt)
+			}
+		}
+	}
+}
@@ -18,7 +18,7 @@ func (t *TransactionContext) authorized(owner common.Address) bool {
 }
 
 func verifySpace(s string, t *TransactionContext) (*SpaceInfo, error) {
-	i, has, err := GetSpaceInfo(t.Database, []byte(s))
+	i, has, err := t.State.GetSpaceInfo([]byte(s))
 	if err != nil {
 		return nil, err
 	}
@@ -45,11 +45,7 @@ func updateSpace(s string, t *TransactionContext, timeRemaining uint64, i *Space
 	i.Updated = t.BlockTime
 	lastExpiry := i.Expiry
 	i.Expiry = t.BlockTime + newTimeRemaining
-	return PutSpaceInfo(t.Database, []byte(s), i, lastExpiry)
-}
-
-func valueUnits(g *Genesis, size uint64) uint64 {
-	return size/g.ValueUnitSize + 1
+	return t.State.PutSpaceInfo([]byte(s), i, lastExpiry)
 }
 
 func valueHash(v []byte) string {
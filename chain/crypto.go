@@ -5,6 +5,7 @@ package chain
 
 import (
 	"crypto/ecdsa"
+	"fmt"
 
 	"github.com/ethereum/go-ethereum/crypto"
 )
@@ -35,5 +36,9 @@ func DeriveSender(dh []byte, sig []byte) (*ecdsa.PublicKey, error) {
 	if sigcpy[vOffset] >= legacySigAdj {
 		sigcpy[vOffset] -= legacySigAdj
 	}
-	return crypto.SigToPub(dh, sigcpy)
+	pk, err := crypto.SigToPub(dh, sigcpy)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	return pk, nil
 }
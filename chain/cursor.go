@@ -0,0 +1,109 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+)
+
+const (
+	cursorVersion = 0
+	// [version] + [height]
+	cursorHeaderLen = 1 + 8
+
+	// MaxPageLimit is the most entries a paginated listing returns at once
+	MaxPageLimit = 256
+)
+
+// Cursor is the position of a paginated listing (history, keys, owned
+// spaces, and recent blocks). Pages resume strictly after [Key], the last key
+// returned, so entries that exist for the whole listing are never repeated or
+// skipped as state changes between pages. [Height] is the last accepted height
+// when the listing started, and listings ordered by height (history and
+// blocks) exclude entries accepted after it.
+type Cursor struct {
+	Height uint64
+	Key    []byte
+}
+
+// String returns the opaque encoding of [c] passed back to continue a listing
+func (c *Cursor) String() string {
+	b := make([]byte, cursorHeaderLen+len(c.Key))
+	b[0] = cursorVersion
+	binary.BigEndian.PutUint64(b[1:], c.Height)
+	copy(b[cursorHeaderLen:], c.Key)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// ParseCursor decodes a cursor returned by a listing. If [s] is empty, a new
+// listing is started at [height].
+func ParseCursor(s string, height uint64) (*Cursor, error) {
+	if len(s) == 0 {
+		return &Cursor{Height: height}, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil || len(b) <= cursorHeaderLen || b[0] != cursorVersion {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidCursor, s)
+	}
+	return &Cursor{
+		Height: binary.BigEndian.Uint64(b[1:]),
+		Key:    b[cursorHeaderLen:],
+	}, nil
+}
+
+// PageLimit returns [limit], or [MaxPageLimit] if it is unset or too large
+func PageLimit(limit int) int {
+	if limit <= 0 || limit > MaxPageLimit {
+		return MaxPageLimit
+	}
+	return limit
+}
+
+// paginate calls [f] with the suffix and value of up to [limit] keys under
+// [baseKey] after [c]. Iteration ends at the first suffix [within] rejects
+// (if provided). It returns the cursor of the next page, which is empty when
+// there are no more entries.
+func paginate(
+	db database.Iteratee, baseKey []byte, c *Cursor, limit int,
+	within func(suffix []byte) bool, f func(suffix []byte, value []byte) error,
+) (string, error) {
+	limit = PageLimit(limit)
+	start := make([]byte, len(baseKey)+len(c.Key))
+	copy(start, baseKey)
+	copy(start[len(baseKey):], c.Key)
+
+	iter := db.NewIteratorWithStart(start)
+	defer iter.Release()
+	var (
+		last  []byte
+		count int
+	)
+	for iter.Next() {
+		curKey := iter.Key()
+		if !bytes.HasPrefix(curKey, baseKey) {
+			break
+		}
+		suffix := curKey[len(baseKey):]
+		if len(c.Key) > 0 && bytes.Equal(suffix, c.Key) {
+			continue
+		}
+		if within != nil && !within(suffix) {
+			break
+		}
+		if count == limit {
+			return (&Cursor{Height: c.Height, Key: last}).String(), iter.Error()
+		}
+		if err := f(suffix, iter.Value()); err != nil {
+			return "", err
+		}
+		last = append(last[:0], suffix...)
+		count++
+	}
+	return "", iter.Error()
+}
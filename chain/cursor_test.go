@@ -0,0 +1,80 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestCursor(t *testing.T) {
+	t.Parallel()
+
+	c := &Cursor{Height: 10, Key: []byte("key")}
+	parsed, err := ParseCursor(c.String(), 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Height != 10 || string(parsed.Key) != "key" {
+		t.Fatalf("unexpected cursor %+v", parsed)
+	}
+
+	// New listings start at the provided height
+	parsed, err = ParseCursor("", 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.Height != 20 || len(parsed.Key) != 0 {
+		t.Fatalf("unexpected cursor %+v", parsed)
+	}
+
+	for _, s := range []string{"!!", "AAAAAAAAAAAK", (&Cursor{}).String()[:4]} {
+		if _, err := ParseCursor(s, 0); !errors.Is(err, ErrInvalidCursor) {
+			t.Fatalf("%q: expected %v, got %v", s, ErrInvalidCursor, err)
+		}
+	}
+}
+
+func TestListOwned(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.New()
+	owner := common.Address{0x1}
+	for _, space := range []string{"a", "b", "c", "d"} {
+		if err := db.Put(PrefixOwnedKey(owner, []byte(space)), nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Spaces of other owners should not be returned
+	if err := db.Put(PrefixOwnedKey(common.Address{0x2}, []byte("e")), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	spaces, next, err := ListOwned(db, owner, "", 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spaces) != 2 || spaces[0] != "a" || spaces[1] != "b" || next == "" {
+		t.Fatalf("unexpected page %v %q", spaces, next)
+	}
+
+	// Removing the last returned space and adding one before it between
+	// pages does not cause spaces to be skipped or repeated
+	if err := db.Delete(PrefixOwnedKey(owner, []byte("b"))); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put(PrefixOwnedKey(owner, []byte("aa")), nil); err != nil {
+		t.Fatal(err)
+	}
+	spaces, next, err = ListOwned(db, owner, next, 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spaces) != 2 || spaces[0] != "c" || spaces[1] != "d" || next != "" {
+		t.Fatalf("unexpected page %v %q", spaces, next)
+	}
+}
@@ -6,6 +6,7 @@ package chain
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -21,6 +22,13 @@ const (
 	Delete   = "delete"
 	Move     = "move"
 	Transfer = "transfer"
+	Import   = "import"
+	Export   = "export"
+	Commit   = "commit"
+	Policy   = "policy"
+	Approve  = "approve"
+	Burn     = "burn"
+	Freeze   = "freeze"
 
 	// Non-user created event
 	Reward = "reward"
@@ -33,27 +41,68 @@ type Input struct {
 	Value []byte         `json:"value"`
 	To    common.Address `json:"to"`
 	Units uint64         `json:"units"`
+	// Extension is the exact number of seconds a lifeline extends a space by
+	Extension uint64 `json:"extension"`
+	// Beneficiary is credited with a share of the fee of a claim
+	Beneficiary common.Address `json:"beneficiary"`
+	// Activation is when a move transfers the space (0 moves it immediately)
+	Activation uint64 `json:"activation"`
+	// Cooldown is how long a burn blocks its space from being claimed again
+	Cooldown uint64 `json:"cooldown"`
+	// Lease is the number of standard leases a claim buys
+	Lease uint64 `json:"lease"`
+	// Salt reveals the commitment of a claim
+	Salt common.Hash `json:"salt"`
+	// Keys are set in the space a claim claims
+	Keys []*CustomKey `json:"keys"`
+	// Commitment is the hash of a future claim (see [ClaimCommitment])
+	Commitment common.Hash `json:"commitment"`
+	// Policy replaces the policy of a space
+	Policy SpacePolicy `json:"policy"`
+	// Action is the [ActionHash] a co-signer approves
+	Action common.Hash `json:"action"`
+	// Broadcast makes a set emit a warp message
+	Broadcast bool `json:"broadcast"`
+	// Kind is how a set value should be interpreted
+	Kind string `json:"kind"`
+	// PeerChain is the chain units are imported from or exported to
+	PeerChain ids.ID `json:"peerChain"`
+	// PeerTo owns exported units on [PeerChain]
+	PeerTo ids.ShortID `json:"peerTo"`
+	// UTXOID is the atomic UTXO an import consumes
+	UTXOID ids.ID `json:"utxoID"`
+	// Prefix is the key prefix a freeze freezes (or unfreezes)
+	Prefix string `json:"prefix"`
+	// Unfreeze makes a freeze unfreeze [Prefix]
+	Unfreeze bool `json:"unfreeze"`
 }
 
 func (i *Input) Decode() (UnsignedTransaction, error) {
 	switch i.Typ {
 	case Claim:
 		return &ClaimTx{
-			BaseTx: &BaseTx{},
-			Space:  i.Space,
+			BaseTx:      &BaseTx{},
+			Space:       i.Space,
+			Beneficiary: i.Beneficiary,
+			Lease:       i.Lease,
+			Salt:        i.Salt,
+			Keys:        i.Keys,
 		}, nil
 	case Lifeline:
 		return &LifelineTx{
-			BaseTx: &BaseTx{},
-			Space:  i.Space,
-			Units:  i.Units,
+			BaseTx:    &BaseTx{},
+			Space:     i.Space,
+			Units:     i.Units,
+			Extension: i.Extension,
 		}, nil
 	case Set:
 		return &SetTx{
-			BaseTx: &BaseTx{},
-			Space:  i.Space,
-			Key:    i.Key,
-			Value:  i.Value,
+			BaseTx:    &BaseTx{},
+			Space:     i.Space,
+			Key:       i.Key,
+			Value:     i.Value,
+			Broadcast: i.Broadcast,
+			Kind:      i.Kind,
 		}, nil
 	case Delete:
 		return &DeleteTx{
@@ -63,9 +112,10 @@ func (i *Input) Decode() (UnsignedTransaction, error) {
 		}, nil
 	case Move:
 		return &MoveTx{
-			BaseTx: &BaseTx{},
-			Space:  i.Space,
-			To:     i.To,
+			BaseTx:     &BaseTx{},
+			Space:      i.Space,
+			To:         i.To,
+			Activation: i.Activation,
 		}, nil
 	case Transfer:
 		return &TransferTx{
@@ -73,6 +123,48 @@ func (i *Input) Decode() (UnsignedTransaction, error) {
 			To:     i.To,
 			Units:  i.Units,
 		}, nil
+	case Import:
+		return &ImportTx{
+			BaseTx:      &BaseTx{},
+			SourceChain: i.PeerChain,
+			UTXOID:      i.UTXOID,
+		}, nil
+	case Export:
+		return &ExportTx{
+			BaseTx:           &BaseTx{},
+			DestinationChain: i.PeerChain,
+			To:               i.PeerTo,
+			Units:            i.Units,
+		}, nil
+	case Commit:
+		return &CommitTx{
+			BaseTx:     &BaseTx{},
+			Commitment: i.Commitment,
+		}, nil
+	case Policy:
+		return &PolicyTx{
+			BaseTx: &BaseTx{},
+			Space:  i.Space,
+			Policy: i.Policy.Copy(),
+		}, nil
+	case Approve:
+		return &ApproveTx{
+			BaseTx: &BaseTx{},
+			Action: i.Action,
+		}, nil
+	case Burn:
+		return &BurnTx{
+			BaseTx:   &BaseTx{},
+			Space:    i.Space,
+			Cooldown: i.Cooldown,
+		}, nil
+	case Freeze:
+		return &FreezeTx{
+			BaseTx:   &BaseTx{},
+			Space:    i.Space,
+			Prefix:   i.Prefix,
+			Unfreeze: i.Unfreeze,
+		}, nil
 	default:
 		return nil, ErrInvalidType
 	}
@@ -83,15 +175,47 @@ const (
 	tdUint64  = "uint64"
 	tdBytes   = "bytes"
 	tdAddress = "address"
+	tdBool    = "bool"
+	tdBytes32 = "bytes32"
 
 	tdBlockID = "blockID"
 	tdPrice   = "price"
 
-	tdSpace = "space"
-	tdKey   = "key"
-	tdValue = "value"
-	tdUnits = "units"
-	tdTo    = "to"
+	tdSpace     = "space"
+	tdKey       = "key"
+	tdValue     = "value"
+	tdUnits     = "units"
+	tdExtension = "extension"
+	tdTo        = "to"
+	// Only claims specify a beneficiary, lease, salt, and keys
+	tdBeneficiary = "beneficiary"
+	tdLease       = "lease"
+	tdSalt        = "salt"
+	tdKeys        = "keys"
+	tdValues      = "values"
+	// Only moves specify an activation
+	tdActivation = "activation"
+	// Only commits specify a commitment
+	tdCommitment = "commitment"
+	// Only policies specify ops, max value size, lock, and co-signer
+	tdOps          = "ops"
+	tdMaxValueSize = "maxValueSize"
+	tdLockedUntil  = "lockedUntil"
+	tdCoSigner     = "coSigner"
+	// Only approvals specify an action
+	tdAction = "action"
+	// Only burns specify a cooldown
+	tdCooldown = "cooldown"
+	// Only freezes specify a prefix and unfreeze
+	tdPrefix   = "prefix"
+	tdUnfreeze = "unfreeze"
+	// Only sets specify broadcast and kind
+	tdBroadcast = "broadcast"
+	tdKind      = "kind"
+
+	tdSourceChain      = "sourceChain"
+	tdDestinationChain = "destinationChain"
+	tdUTXOID           = "utxoID"
 )
 
 func parseUint64Message(td *tdata.TypedData, k string) (uint64, error) {
@@ -102,6 +226,70 @@ func parseUint64Message(td *tdata.TypedData, k string) (uint64, error) {
 	return strconv.ParseUint(r, 10, 64)
 }
 
+func parseIDMessage(td *tdata.TypedData, k string) (ids.ID, error) {
+	r, ok := td.Message[k].(string)
+	if !ok {
+		return ids.Empty, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, k)
+	}
+	return ids.FromString(r)
+}
+
+func parseHashMessage(td *tdata.TypedData, k string) (common.Hash, error) {
+	r, ok := td.Message[k].(string)
+	if !ok {
+		return common.Hash{}, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, k)
+	}
+	b, err := hexutil.Decode(r)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if len(b) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("%w: %s must be %d bytes", ErrInvalidType, k, common.HashLength)
+	}
+	return common.BytesToHash(b), nil
+}
+
+func parseStringsMessage(td *tdata.TypedData, k string) ([]string, error) {
+	r, ok := td.Message[k].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, k)
+	}
+	strs := make([]string, len(r))
+	for i, v := range r {
+		if strs[i], ok = v.(string); !ok {
+			return nil, fmt.Errorf("%w: %s must be strings", ErrInvalidType, k)
+		}
+	}
+	return strs, nil
+}
+
+// parseClaimKeys parses the keys (and their hex-encoded values) of a claim
+func parseClaimKeys(td *tdata.TypedData) ([]*CustomKey, error) {
+	keys, err := parseStringsMessage(td, tdKeys)
+	if err != nil {
+		return nil, err
+	}
+	values, err := parseStringsMessage(td, tdValues)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) != len(values) {
+		return nil, fmt.Errorf("%w: %d keys but %d values", ErrInvalidType, len(keys), len(values))
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	cks := make([]*CustomKey, len(keys))
+	for i, key := range keys {
+		value, err := hexutil.Decode(values[i])
+		if err != nil {
+			return nil, err
+		}
+		cks[i] = &CustomKey{Key: key, Value: value}
+	}
+	return cks, nil
+}
+
 func parseBaseTx(td *tdata.TypedData) (*BaseTx, error) {
 	rblockID, ok := td.Message[tdBlockID].(string)
 	if !ok {
@@ -115,11 +303,18 @@ func parseBaseTx(td *tdata.TypedData) (*BaseTx, error) {
 	if err != nil {
 		return nil, err
 	}
+	chainID := ids.Empty
+	if len(td.Domain.ChainID) > 0 {
+		chainID, err = ids.FromString(td.Domain.ChainID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid chain ID", err)
+		}
+	}
 	price, err := parseUint64Message(td, tdPrice)
 	if err != nil {
 		return nil, err
 	}
-	return &BaseTx{BlockID: blockID, Magic: magic, Price: price}, nil
+	return &BaseTx{BlockID: blockID, Magic: magic, Price: price, ChainID: chainID}, nil
 }
 
 func ParseTypedData(td *tdata.TypedData) (UnsignedTransaction, error) {
@@ -134,7 +329,30 @@ func ParseTypedData(td *tdata.TypedData) (UnsignedTransaction, error) {
 		if !ok {
 			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdSpace)
 		}
-		return &ClaimTx{BaseTx: bTx, Space: space}, nil
+		beneficiary, ok := td.Message[tdBeneficiary].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdBeneficiary)
+		}
+		lease, err := parseUint64Message(td, tdLease)
+		if err != nil {
+			return nil, err
+		}
+		salt, err := parseHashMessage(td, tdSalt)
+		if err != nil {
+			return nil, err
+		}
+		keys, err := parseClaimKeys(td)
+		if err != nil {
+			return nil, err
+		}
+		return &ClaimTx{
+			BaseTx:      bTx,
+			Space:       space,
+			Beneficiary: common.HexToAddress(beneficiary),
+			Lease:       lease,
+			Salt:        salt,
+			Keys:        keys,
+		}, nil
 	case Lifeline:
 		space, ok := td.Message[tdSpace].(string)
 		if !ok {
@@ -144,7 +362,11 @@ func ParseTypedData(td *tdata.TypedData) (UnsignedTransaction, error) {
 		if err != nil {
 			return nil, err
 		}
-		return &LifelineTx{BaseTx: bTx, Space: space, Units: units}, nil
+		extension, err := parseUint64Message(td, tdExtension)
+		if err != nil {
+			return nil, err
+		}
+		return &LifelineTx{BaseTx: bTx, Space: space, Units: units, Extension: extension}, nil
 	case Set:
 		space, ok := td.Message[tdSpace].(string)
 		if !ok {
@@ -162,7 +384,15 @@ func ParseTypedData(td *tdata.TypedData) (UnsignedTransaction, error) {
 		if err != nil {
 			return nil, err
 		}
-		return &SetTx{BaseTx: bTx, Space: space, Key: key, Value: value}, nil
+		broadcast, ok := td.Message[tdBroadcast].(bool)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdBroadcast)
+		}
+		kind, ok := td.Message[tdKind].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdKind)
+		}
+		return &SetTx{BaseTx: bTx, Space: space, Key: key, Value: value, Broadcast: broadcast, Kind: kind}, nil
 	case Delete:
 		space, ok := td.Message[tdSpace].(string)
 		if !ok {
@@ -182,7 +412,11 @@ func ParseTypedData(td *tdata.TypedData) (UnsignedTransaction, error) {
 		if !ok {
 			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdTo)
 		}
-		return &MoveTx{BaseTx: bTx, Space: space, To: common.HexToAddress(to)}, nil
+		activation, err := parseUint64Message(td, tdActivation)
+		if err != nil {
+			return nil, err
+		}
+		return &MoveTx{BaseTx: bTx, Space: space, To: common.HexToAddress(to), Activation: activation}, nil
 	case Transfer:
 		to, ok := td.Message[tdTo].(string)
 		if !ok {
@@ -193,6 +427,101 @@ func ParseTypedData(td *tdata.TypedData) (UnsignedTransaction, error) {
 			return nil, err
 		}
 		return &TransferTx{BaseTx: bTx, To: common.HexToAddress(to), Units: units}, nil
+	case Import:
+		sourceChain, err := parseIDMessage(td, tdSourceChain)
+		if err != nil {
+			return nil, err
+		}
+		utxoID, err := parseIDMessage(td, tdUTXOID)
+		if err != nil {
+			return nil, err
+		}
+		return &ImportTx{BaseTx: bTx, SourceChain: sourceChain, UTXOID: utxoID}, nil
+	case Export:
+		destinationChain, err := parseIDMessage(td, tdDestinationChain)
+		if err != nil {
+			return nil, err
+		}
+		rto, ok := td.Message[tdTo].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdTo)
+		}
+		to, err := ids.ShortFromString(rto)
+		if err != nil {
+			return nil, err
+		}
+		units, err := parseUint64Message(td, tdUnits)
+		if err != nil {
+			return nil, err
+		}
+		return &ExportTx{BaseTx: bTx, DestinationChain: destinationChain, To: to, Units: units}, nil
+	case Commit:
+		commitment, err := parseHashMessage(td, tdCommitment)
+		if err != nil {
+			return nil, err
+		}
+		return &CommitTx{BaseTx: bTx, Commitment: commitment}, nil
+	case Policy:
+		space, ok := td.Message[tdSpace].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdSpace)
+		}
+		rops, ok := td.Message[tdOps].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdOps)
+		}
+		var ops []string
+		if len(rops) > 0 {
+			ops = strings.Split(rops, ",")
+		}
+		maxValueSize, err := parseUint64Message(td, tdMaxValueSize)
+		if err != nil {
+			return nil, err
+		}
+		lockedUntil, err := parseUint64Message(td, tdLockedUntil)
+		if err != nil {
+			return nil, err
+		}
+		coSigner, ok := td.Message[tdCoSigner].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdCoSigner)
+		}
+		return &PolicyTx{BaseTx: bTx, Space: space, Policy: SpacePolicy{
+			Ops:          ops,
+			MaxValueSize: maxValueSize,
+			LockedUntil:  lockedUntil,
+			CoSigner:     common.HexToAddress(coSigner),
+		}}, nil
+	case Approve:
+		action, err := parseHashMessage(td, tdAction)
+		if err != nil {
+			return nil, err
+		}
+		return &ApproveTx{BaseTx: bTx, Action: action}, nil
+	case Burn:
+		space, ok := td.Message[tdSpace].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdSpace)
+		}
+		cooldown, err := parseUint64Message(td, tdCooldown)
+		if err != nil {
+			return nil, err
+		}
+		return &BurnTx{BaseTx: bTx, Space: space, Cooldown: cooldown}, nil
+	case Freeze:
+		space, ok := td.Message[tdSpace].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdSpace)
+		}
+		prefix, ok := td.Message[tdPrefix].(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdPrefix)
+		}
+		unfreeze, ok := td.Message[tdUnfreeze].(bool)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrTypedDataKeyMissing, tdUnfreeze)
+		}
+		return &FreezeTx{BaseTx: bTx, Space: space, Prefix: prefix, Unfreeze: unfreeze}, nil
 	default:
 		return nil, ErrInvalidType
 	}
@@ -39,9 +39,15 @@ func (d *DeleteTx) Execute(t *TransactionContext) error {
 	if err != nil {
 		return err
 	}
+	if err := i.Policy.check(t, Delete, d, 0); err != nil {
+		return err
+	}
+	if err := i.checkFrozen(d.Key, t.BlockTime); err != nil {
+		return err
+	}
 
 	// Delete value
-	v, exists, err := GetValueMeta(t.Database, []byte(d.Space), []byte(d.Key))
+	v, exists, err := t.State.GetValueMeta([]byte(d.Space), []byte(d.Key))
 	if err != nil {
 		return err
 	}
@@ -49,8 +55,12 @@ func (d *DeleteTx) Execute(t *TransactionContext) error {
 		return ErrKeyMissing
 	}
 	timeRemaining := (i.Expiry - i.Updated) * i.Units
-	i.Units -= valueUnits(g, v.Size) / g.ValueExpiryDiscount
-	if err := DeleteSpaceKey(t.Database, []byte(d.Space), []byte(d.Key)); err != nil {
+	i.Units -= StorageUnits(g, v.Size)
+	i.Size -= v.Size
+	if err := t.State.DeleteValue([]byte(d.Space), []byte(d.Key)); err != nil {
+		return err
+	}
+	if err := t.State.PutTombstone(t.BlockHeight, v.TxID, v.Size); err != nil {
 		return err
 	}
 	return updateSpace(d.Space, t, timeRemaining, i)
@@ -66,7 +76,7 @@ func (d *DeleteTx) Copy() UnsignedTransaction {
 
 func (d *DeleteTx) TypedData() *tdata.TypedData {
 	return tdata.CreateTypedData(
-		d.Magic, Delete,
+		d.Magic, d.typedChainID(), Delete,
 		[]tdata.Type{
 			{Name: tdSpace, Type: tdString},
 			{Name: tdKey, Type: tdString},
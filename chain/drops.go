@@ -0,0 +1,130 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+var nextDropKey = []byte("next_drop")
+
+// DroppedTx records why (and when) a transaction left the mempool, so it can
+// be explained long after the mempool has forgotten it.
+type DroppedTx struct {
+	TxID      ids.ID     `serialize:"true" json:"txId"`
+	Reason    DropReason `serialize:"true" json:"reason"`
+	Timestamp int64      `serialize:"true" json:"timestamp"`
+}
+
+// [dropPrefix] + [delimiter] + [slot]
+func prefixDropKey(slot uint64) (k []byte) {
+	k = make([]byte, 2+8)
+	k[0] = dropPrefix
+	k[1] = parser.ByteDelimiter
+	binary.BigEndian.PutUint64(k[2:], slot)
+	return k
+}
+
+// [dropIndexPrefix] + [delimiter] + [txID]
+func prefixDropIndexKey(txID ids.ID) (k []byte) {
+	k = make([]byte, 2+len(txID))
+	k[0] = dropIndexPrefix
+	k[1] = parser.ByteDelimiter
+	copy(k[2:], txID[:])
+	return k
+}
+
+// PutDroppedTx stores [d] in a ring of [size] records, overwriting the
+// oldest record once the ring is full. Callers must not store drops
+// concurrently.
+func PutDroppedTx(db database.Database, size uint64, d *DroppedTx) error {
+	if size == 0 {
+		return nil
+	}
+	var next uint64
+	v, err := db.Get(nextDropKey)
+	switch {
+	case err == nil:
+		next = binary.BigEndian.Uint64(v)
+	case !errors.Is(err, database.ErrNotFound):
+		return err
+	}
+	slot := next % size
+	slotKey := prefixDropKey(slot)
+	slotBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(slotBytes, slot)
+
+	batch := db.NewBatch()
+	prev, err := getDroppedTx(db, slotKey)
+	if err != nil {
+		return err
+	}
+	if prev != nil {
+		// Only forget the overwritten tx if it was not dropped again since
+		indexKey := prefixDropIndexKey(prev.TxID)
+		indexed, err := db.Get(indexKey)
+		if err != nil && !errors.Is(err, database.ErrNotFound) {
+			return err
+		}
+		if err == nil && binary.BigEndian.Uint64(indexed) == slot {
+			if err := batch.Delete(indexKey); err != nil {
+				return err
+			}
+		}
+	}
+	b, err := Marshal(d)
+	if err != nil {
+		return err
+	}
+	if err := batch.Put(slotKey, b); err != nil {
+		return err
+	}
+	if err := batch.Put(prefixDropIndexKey(d.TxID), slotBytes); err != nil {
+		return err
+	}
+	nextBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(nextBytes, next+1)
+	if err := batch.Put(nextDropKey, nextBytes); err != nil {
+		return err
+	}
+	return batch.Write()
+}
+
+// GetDroppedTx returns the most recent record of [txID] leaving the mempool
+// (or nil if none is kept).
+func GetDroppedTx(db database.KeyValueReader, txID ids.ID) (*DroppedTx, error) {
+	v, err := db.Get(prefixDropIndexKey(txID))
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	d, err := getDroppedTx(db, prefixDropKey(binary.BigEndian.Uint64(v)))
+	if err != nil || d == nil || d.TxID != txID {
+		return nil, err
+	}
+	return d, nil
+}
+
+func getDroppedTx(db database.KeyValueReader, k []byte) (*DroppedTx, error) {
+	v, err := db.Get(k)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	d := new(DroppedTx)
+	if _, err := Unmarshal(v, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
@@ -0,0 +1,61 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestDroppedTxs(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.New()
+	txs := []ids.ID{ids.GenerateTestID(), ids.GenerateTestID(), ids.GenerateTestID()}
+	put := func(txID ids.ID, reason DropReason, ts int64) {
+		if err := PutDroppedTx(db, 2, &DroppedTx{TxID: txID, Reason: reason, Timestamp: ts}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check := func(txID ids.ID, expected *DroppedTx) {
+		d, err := GetDroppedTx(db, txID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		switch {
+		case expected == nil && d != nil:
+			t.Fatalf("expected no record of %s, got %+v", txID, d)
+		case expected != nil && (d == nil || *d != *expected):
+			t.Fatalf("expected %+v, got %+v", expected, d)
+		}
+	}
+
+	put(txs[0], DropExpired, 1)
+	put(txs[1], DropEvicted, 2)
+	check(txs[0], &DroppedTx{TxID: txs[0], Reason: DropExpired, Timestamp: 1})
+	check(txs[1], &DroppedTx{TxID: txs[1], Reason: DropEvicted, Timestamp: 2})
+	check(txs[2], nil)
+
+	// The oldest record is overwritten once the ring is full
+	put(txs[2], DropInvalid, 3)
+	check(txs[0], nil)
+	check(txs[2], &DroppedTx{TxID: txs[2], Reason: DropInvalid, Timestamp: 3})
+
+	// A tx dropped again keeps its latest record when its older one is
+	// overwritten
+	put(txs[2], DropGossiped, 4)
+	check(txs[1], nil)
+	check(txs[2], &DroppedTx{TxID: txs[2], Reason: DropGossiped, Timestamp: 4})
+	put(txs[0], DropConflict, 5)
+	check(txs[0], &DroppedTx{TxID: txs[0], Reason: DropConflict, Timestamp: 5})
+	check(txs[2], &DroppedTx{TxID: txs[2], Reason: DropGossiped, Timestamp: 4})
+
+	// Nothing is kept without a ring
+	if err := PutDroppedTx(db, 0, &DroppedTx{TxID: txs[1], Reason: DropExpired}); err != nil {
+		t.Fatal(err)
+	}
+	check(txs[1], nil)
+}
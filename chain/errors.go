@@ -5,12 +5,26 @@ package chain
 
 import (
 	"errors"
+	"strings"
 )
 
 var (
 	// Genesis Correctness
-	ErrInvalidMagic     = errors.New("invalid magic")
-	ErrInvalidBlockRate = errors.New("invalid block rate")
+	ErrInvalidMagic            = errors.New("invalid magic")
+	ErrInvalidBlockRate        = errors.New("invalid block rate")
+	ErrInvalidLookbackWindow   = errors.New("invalid lookback window")
+	ErrInvalidBlockSize        = errors.New("invalid block size")
+	ErrInvalidClaimExpiry      = errors.New("invalid claim expiry")
+	ErrInvalidValueUnitSize    = errors.New("invalid value unit size")
+	ErrInvalidStorageLimit     = errors.New("invalid storage limit")
+	ErrInvalidRenewalDiscount  = errors.New("invalid renewal discount")
+	ErrInvalidBeneficiaryShare = errors.New("invalid beneficiary share")
+	ErrInvalidHeartbeat        = errors.New("invalid heartbeat interval")
+	ErrInvalidClaimCommit      = errors.New("invalid claim commit window")
+	ErrInvalidFreeWrites       = errors.New("invalid free write quota")
+	ErrDuplicateSpace          = errors.New("duplicate space")
+	ErrDuplicateKey            = errors.New("duplicate key")
+	ErrAirdropMismatch         = errors.New("airdrop allocation hash mismatch")
 
 	// Block Correctness
 	ErrTimestampTooEarly      = errors.New("block timestamp too early")
@@ -20,9 +34,12 @@ var (
 	ErrInvalidPrice           = errors.New("invalid price")
 	ErrInsufficientSurplus    = errors.New("insufficient surplus fee")
 	ErrParentBlockNotVerified = errors.New("parent block not verified or accepted")
+	ErrBlockReleased          = errors.New("block state was released")
+	ErrInvalidBlockBytes      = errors.New("invalid block bytes")
 
 	// Tx Correctness
 	ErrInvalidBlockID      = errors.New("invalid blockID")
+	ErrInvalidChainID      = errors.New("invalid chainID")
 	ErrInvalidSignature    = errors.New("invalid signature")
 	ErrDuplicateTx         = errors.New("duplicate transaction")
 	ErrInsufficientPrice   = errors.New("insufficient price")
@@ -32,9 +49,13 @@ var (
 	// Execution Correctness
 	ErrValueEmpty      = errors.New("value empty")
 	ErrValueTooBig     = errors.New("value too big")
+	ErrKeyTooLong      = errors.New("key too long")
+	ErrSpaceFull       = errors.New("space storage limit exceeded")
 	ErrSpaceExpired    = errors.New("space expired")
 	ErrKeyMissing      = errors.New("key missing")
 	ErrInvalidKey      = errors.New("key is invalid")
+	ErrInvalidKind     = errors.New("invalid value kind")
+	ErrInvalidRecord   = errors.New("value does not match its kind")
 	ErrAddressMismatch = errors.New("address does not match decoded space")
 	ErrSpaceNotExpired = errors.New("space not expired")
 	ErrSpaceMissing    = errors.New("space missing")
@@ -42,4 +63,167 @@ var (
 	ErrInvalidBalance  = errors.New("invalid balance")
 	ErrNonActionable   = errors.New("transaction doesn't do anything")
 	ErrBlockTooBig     = errors.New("block too big")
+
+	ErrAtomicDisabled    = errors.New("atomic transactions are disabled")
+	ErrAtomicUnavailable = errors.New("shared memory is unavailable")
+	ErrInvalidPeerChain  = errors.New("invalid peer chain")
+	ErrUTXOMissing       = errors.New("atomic UTXO missing")
+	ErrUTXOImported      = errors.New("atomic UTXO already imported")
+	ErrInvalidUTXO       = errors.New("invalid atomic UTXO")
+
+	ErrExtensionTooLong     = errors.New("lifeline extension too long")
+	ErrInsufficientLifeline = errors.New("lifeline units do not cover extension")
+	ErrLeaseTooLong         = errors.New("claim lease too long")
+	ErrTooManyClaimKeys     = errors.New("too many claim keys")
+
+	ErrCommitmentMissing   = errors.New("claim commitment missing")
+	ErrCommitmentExists    = errors.New("claim commitment already exists")
+	ErrCommitmentTooRecent = errors.New("claim commitment too recent")
+	ErrCommitmentExpired   = errors.New("claim commitment expired")
+
+	ErrInvalidPolicy   = errors.New("invalid space policy")
+	ErrPolicyDenied    = errors.New("denied by space policy")
+	ErrSpaceLocked     = errors.New("space is time locked")
+	ErrApprovalMissing = errors.New("co-signer approval missing")
+
+	ErrCooldownTooLong = errors.New("burn cooldown too long")
+	ErrSpaceBurned     = errors.New("space was burned and is cooling down")
+
+	ErrKeyFrozen             = errors.New("key is frozen")
+	ErrTooManyFrozenPrefixes = errors.New("too many frozen prefixes")
+
+	// Query Correctness
+	ErrInvalidCursor      = errors.New("invalid cursor")
+	ErrHistoryUnavailable = errors.New("history unavailable")
+
+	// Storage Correctness
+	ErrInvalidTombstone  = errors.New("invalid tombstone")
+	ErrInvalidIndex      = errors.New("invalid index")
+	ErrInvalidKeyFormat  = errors.New("invalid key format")
+	ErrInvalidCacheEntry = errors.New("invalid cache entry")
+
+	// Backup Correctness
+	ErrInvalidBackup    = errors.New("invalid backup")
+	ErrDatabaseNotEmpty = errors.New("database is not empty")
+	ErrNoLastAccepted   = errors.New("no last accepted block")
+	ErrInvalidExport    = errors.New("invalid export")
 )
+
+// Errors are all of the sentinel errors returned by this package. Every
+// failure is either one of them or wraps one (with "%w"), so callers can
+// classify it with [errors.Is].
+var Errors = []error{
+	ErrInvalidMagic,
+	ErrInvalidBlockRate,
+	ErrInvalidLookbackWindow,
+	ErrInvalidBlockSize,
+	ErrInvalidClaimExpiry,
+	ErrInvalidValueUnitSize,
+	ErrInvalidStorageLimit,
+	ErrInvalidRenewalDiscount,
+	ErrInvalidBeneficiaryShare,
+	ErrInvalidHeartbeat,
+	ErrInvalidClaimCommit,
+	ErrInvalidFreeWrites,
+	ErrDuplicateSpace,
+	ErrDuplicateKey,
+	ErrAirdropMismatch,
+
+	ErrTimestampTooEarly,
+	ErrTimestampTooLate,
+	ErrNoTxs,
+	ErrInvalidCost,
+	ErrInvalidPrice,
+	ErrInsufficientSurplus,
+	ErrParentBlockNotVerified,
+	ErrBlockReleased,
+	ErrInvalidBlockBytes,
+
+	ErrInvalidBlockID,
+	ErrInvalidChainID,
+	ErrInvalidSignature,
+	ErrDuplicateTx,
+	ErrInsufficientPrice,
+	ErrInvalidType,
+	ErrTypedDataKeyMissing,
+
+	ErrValueEmpty,
+	ErrValueTooBig,
+	ErrKeyTooLong,
+	ErrSpaceFull,
+	ErrSpaceExpired,
+	ErrKeyMissing,
+	ErrInvalidKey,
+	ErrInvalidKind,
+	ErrInvalidRecord,
+	ErrAddressMismatch,
+	ErrSpaceNotExpired,
+	ErrSpaceMissing,
+	ErrUnauthorized,
+	ErrInvalidBalance,
+	ErrNonActionable,
+	ErrBlockTooBig,
+
+	ErrAtomicDisabled,
+	ErrAtomicUnavailable,
+	ErrInvalidPeerChain,
+	ErrUTXOMissing,
+	ErrUTXOImported,
+	ErrInvalidUTXO,
+
+	ErrExtensionTooLong,
+	ErrInsufficientLifeline,
+	ErrLeaseTooLong,
+	ErrTooManyClaimKeys,
+
+	ErrCommitmentMissing,
+	ErrCommitmentExists,
+	ErrCommitmentTooRecent,
+	ErrCommitmentExpired,
+
+	ErrInvalidPolicy,
+	ErrPolicyDenied,
+	ErrSpaceLocked,
+	ErrApprovalMissing,
+
+	ErrCooldownTooLong,
+	ErrSpaceBurned,
+
+	ErrKeyFrozen,
+	ErrTooManyFrozenPrefixes,
+
+	ErrInvalidCursor,
+	ErrHistoryUnavailable,
+
+	ErrInvalidTombstone,
+	ErrInvalidIndex,
+	ErrInvalidKeyFormat,
+	ErrInvalidCacheEntry,
+
+	ErrInvalidBackup,
+	ErrDatabaseNotEmpty,
+	ErrNoLastAccepted,
+	ErrInvalidExport,
+}
+
+// ParseError returns the error in [Errors] that [msg] reports, or nil if it
+// does not report any of them. Errors received over the API only carry their
+// message, which starts with the outermost sentinel it wraps, so the
+// sentinel found earliest in [msg] is returned (preferring the longest if
+// several start at the same position).
+func ParseError(msg string) error {
+	var (
+		match error
+		at    = len(msg)
+	)
+	for _, err := range Errors {
+		i := strings.Index(msg, err.Error())
+		if i < 0 {
+			continue
+		}
+		if i < at || (i == at && len(err.Error()) > len(match.Error())) {
+			match, at = err, i
+		}
+	}
+	return match
+}
@@ -0,0 +1,53 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestParseError(t *testing.T) {
+	t.Parallel()
+
+	msgs := map[string]struct{}{}
+	for _, err := range Errors {
+		if _, ok := msgs[err.Error()]; ok {
+			t.Fatalf("duplicate error message %q", err)
+		}
+		msgs[err.Error()] = struct{}{}
+		if perr := ParseError(err.Error()); perr != err {
+			t.Fatalf("%q parsed as %v", err, perr)
+		}
+	}
+
+	tt := []struct {
+		msg string
+		err error
+	}{
+		{
+			msg: fmt.Errorf("%w: space=foo", ErrSpaceExpired).Error(),
+			err: ErrSpaceExpired,
+		},
+		{
+			// The outermost sentinel is reported
+			msg: fmt.Errorf("%w: %v", ErrInvalidExport, ErrInvalidSignature).Error(),
+			err: ErrInvalidExport,
+		},
+		{
+			// Messages containing shorter sentinels are not mistaken for them
+			msg: fmt.Sprintf("failed to decode client response: %v", ErrTypedDataKeyMissing),
+			err: ErrTypedDataKeyMissing,
+		},
+		{
+			msg: "connection refused",
+		},
+	}
+	for i, tv := range tt {
+		if err := ParseError(tv.msg); !errors.Is(err, tv.err) || (tv.err == nil && err != nil) {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.err, err)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// WriteBlockRecord writes the bytes of a block to [w], prefixed by their
+// length.
+//
+// Record format:
+// [len (uint32, big endian)] + [block bytes]
+func WriteBlockRecord(w io.Writer, b []byte) error {
+	if err := binary.Write(w, binary.BigEndian, uint32(len(b))); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+// ReadBlockRecord reads the next record written by [WriteBlockRecord] from
+// [r]. It returns [io.EOF] if there are no more records and
+// [ErrInvalidExport] if the record is truncated or larger than the codec
+// allows.
+func ReadBlockRecord(r io.Reader) ([]byte, error) {
+	var l uint32
+	if err := binary.Read(r, binary.BigEndian, &l); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, fmt.Errorf("%w: truncated record length", ErrInvalidExport)
+		}
+		return nil, err
+	}
+	if l > maxSize {
+		return nil, fmt.Errorf("%w: record of %d bytes exceeds %d", ErrInvalidExport, l, maxSize)
+	}
+	b := make([]byte, l)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, fmt.Errorf("%w: truncated record (%v)", ErrInvalidExport, err)
+	}
+	return b, nil
+}
@@ -0,0 +1,51 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestBlockRecords(t *testing.T) {
+	t.Parallel()
+
+	records := [][]byte{{0x1}, {}, bytes.Repeat([]byte{0x2}, 1024)}
+	var buf bytes.Buffer
+	for _, r := range records {
+		if err := WriteBlockRecord(&buf, r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	exported := buf.Bytes()
+
+	r := bytes.NewReader(exported)
+	for i, expected := range records {
+		b, err := ReadBlockRecord(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b, expected) {
+			t.Fatalf("record %d expected %x, got %x", i, expected, b)
+		}
+	}
+	if _, err := ReadBlockRecord(r); !errors.Is(err, io.EOF) {
+		t.Fatalf("expected %v, got %v", io.EOF, err)
+	}
+
+	if _, err := ReadBlockRecord(bytes.NewReader(exported[:2])); !errors.Is(err, ErrInvalidExport) {
+		t.Fatalf("truncated length: expected %v, got %v", ErrInvalidExport, err)
+	}
+	r = bytes.NewReader(exported[:len(exported)-1])
+	for range records[:len(records)-1] {
+		if _, err := ReadBlockRecord(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := ReadBlockRecord(r); !errors.Is(err, ErrInvalidExport) {
+		t.Fatalf("truncated record: expected %v, got %v", ErrInvalidExport, err)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"strconv"
+
+	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+
+	"github.com/ava-labs/spacesvm/tdata"
+)
+
+var (
+	_ UnsignedTransaction = &ExportTx{}
+	_ AtomicTransaction   = &ExportTx{}
+)
+
+type ExportTx struct {
+	*BaseTx `serialize:"true" json:"baseTx"`
+
+	// DestinationChain is the chain (such as the X or P chain) the units are
+	// exported to.
+	DestinationChain ids.ID `serialize:"true" json:"destinationChain"`
+
+	// To owns the exported UTXO on [DestinationChain].
+	To ids.ShortID `serialize:"true" json:"to"`
+
+	// Units are removed from the sender's balance and exported as a UTXO of
+	// [Genesis.AtomicAssetID].
+	Units uint64 `serialize:"true" json:"units"`
+}
+
+func (e *ExportTx) Execute(c *TransactionContext) error {
+	if e.To == ids.ShortEmpty || e.Units == 0 {
+		return ErrNonActionable
+	}
+	if err := verifyAtomic(c, e.DestinationChain); err != nil {
+		return err
+	}
+	if _, err := c.State.ModifyBalance(c.Sender, false, e.Units); err != nil {
+		return err
+	}
+	return nil
+}
+
+// UTXO returns the UTXO exported by the transaction [txID]
+func (e *ExportTx) UTXO(g *Genesis, txID ids.ID) *avax.UTXO {
+	return &avax.UTXO{
+		UTXOID: avax.UTXOID{TxID: txID},
+		Asset:  avax.Asset{ID: g.AtomicAssetID},
+		Out: &secp256k1fx.TransferOutput{
+			Amt: e.Units,
+			OutputOwners: secp256k1fx.OutputOwners{
+				Threshold: 1,
+				Addrs:     []ids.ShortID{e.To},
+			},
+		},
+	}
+}
+
+func (e *ExportTx) AtomicRequests(g *Genesis, txID ids.ID) (ids.ID, *atomic.Requests, error) {
+	utxo := e.UTXO(g, txID)
+	b, err := MarshalAtomicUTXO(utxo)
+	if err != nil {
+		return ids.Empty, nil, err
+	}
+	utxoID := utxo.InputID()
+	return e.DestinationChain, &atomic.Requests{
+		PutRequests: []*atomic.Element{{
+			Key:    utxoID[:],
+			Value:  b,
+			Traits: [][]byte{e.To.Bytes()},
+		}},
+	}, nil
+}
+
+func (e *ExportTx) Copy() UnsignedTransaction {
+	return &ExportTx{
+		BaseTx:           e.BaseTx.Copy(),
+		DestinationChain: e.DestinationChain,
+		To:               e.To,
+		Units:            e.Units,
+	}
+}
+
+func (e *ExportTx) TypedData() *tdata.TypedData {
+	return tdata.CreateTypedData(
+		e.Magic, e.typedChainID(), Export,
+		[]tdata.Type{
+			{Name: tdDestinationChain, Type: tdString},
+			{Name: tdTo, Type: tdString},
+			{Name: tdUnits, Type: tdUint64},
+			{Name: tdPrice, Type: tdUint64},
+			{Name: tdBlockID, Type: tdString},
+		},
+		tdata.TypedDataMessage{
+			tdDestinationChain: e.DestinationChain.String(),
+			tdTo:               e.To.String(),
+			tdUnits:            strconv.FormatUint(e.Units, 10),
+			tdPrice:            strconv.FormatUint(e.Price, 10),
+			tdBlockID:          e.BlockID.String(),
+		},
+	)
+}
+
+func (e *ExportTx) Activity() *Activity {
+	return &Activity{
+		Typ:   Export,
+		To:    e.To.String(),
+		Units: e.Units,
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ava-labs/spacesvm/parser"
+	"github.com/ava-labs/spacesvm/tdata"
+)
+
+var _ UnsignedTransaction = &FreezeTx{}
+
+type FreezeTx struct {
+	*BaseTx `serialize:"true" json:"baseTx"`
+
+	// Space is the namespace for the "SpaceInfo"
+	// whose owner can write and read value for the
+	// specific key space.
+	// The space must be ^[a-z0-9]{1,256}$.
+	Space string `serialize:"true" json:"space"`
+
+	// Prefix is frozen in [Space]: keys starting with it can't be set or
+	// deleted until it is unfrozen. An empty prefix freezes every key.
+	Prefix string `serialize:"true" json:"prefix"`
+
+	// Unfreeze schedules [Prefix] to thaw [Genesis.UnfreezeDelay] seconds
+	// after the transaction instead, so readers are warned before frozen
+	// keys can change again. A space can't be burned (see [BurnTx]) while
+	// any of its prefixes is frozen.
+	Unfreeze bool `serialize:"true" json:"unfreeze"`
+}
+
+// FrozenPrefix is a key prefix of a space that is read-only (see [FreezeTx])
+type FrozenPrefix struct {
+	Prefix string `serialize:"true" json:"prefix"`
+	// ThawAt is the unix time the prefix is unfrozen at (0 while it is not
+	// being unfrozen)
+	ThawAt uint64 `serialize:"true" json:"thawAt,omitempty"`
+}
+
+// active returns true if [f] is frozen at [now]
+func (f *FrozenPrefix) active(now uint64) bool {
+	return f.ThawAt == 0 || now < f.ThawAt
+}
+
+// checkFrozen returns an error if [key] of the space [i] is frozen at [now].
+func (i *SpaceInfo) checkFrozen(key string, now uint64) error {
+	for _, f := range i.Frozen {
+		if f.active(now) && strings.HasPrefix(key, f.Prefix) {
+			return fmt.Errorf("%w: %q frozen by prefix %q", ErrKeyFrozen, key, f.Prefix)
+		}
+	}
+	return nil
+}
+
+// checkUnfrozen returns an error if any key prefix of the space [i] is
+// frozen at [now], as removing the space would remove its frozen keys.
+func (i *SpaceInfo) checkUnfrozen(now uint64) error {
+	for _, f := range i.Frozen {
+		if f.active(now) {
+			return fmt.Errorf("%w: prefix %q must thaw first", ErrKeyFrozen, f.Prefix)
+		}
+	}
+	return nil
+}
+
+func (f *FreezeTx) Execute(t *TransactionContext) error {
+	g := t.Genesis
+	if err := parser.CheckContents(f.Space); err != nil {
+		return err
+	}
+	if len(f.Prefix) > 0 {
+		if err := parser.CheckContents(f.Prefix); err != nil {
+			return err
+		}
+	}
+
+	// Verify space is owned by sender
+	i, err := verifySpace(f.Space, t)
+	if err != nil {
+		return err
+	}
+	if err := i.Policy.check(t, Freeze, f, 0); err != nil {
+		return err
+	}
+
+	// Thawed prefixes are dropped
+	var existing *FrozenPrefix
+	frozen := make([]*FrozenPrefix, 0, len(i.Frozen)+1)
+	for _, fp := range i.Frozen {
+		if !fp.active(t.BlockTime) {
+			continue
+		}
+		if fp.Prefix == f.Prefix {
+			existing = fp
+		}
+		frozen = append(frozen, fp)
+	}
+	switch {
+	case f.Unfreeze && (existing == nil || existing.ThawAt != 0):
+		return fmt.Errorf("%w: prefix %q is not frozen indefinitely", ErrNonActionable, f.Prefix)
+	case f.Unfreeze:
+		existing.ThawAt = t.BlockTime + g.UnfreezeDelay
+		if g.UnfreezeDelay == 0 {
+			frozen = removeFrozen(frozen, existing)
+		}
+	case existing != nil && existing.ThawAt == 0:
+		return fmt.Errorf("%w: prefix %q is already frozen", ErrNonActionable, f.Prefix)
+	case existing != nil:
+		// Cancel the pending unfreeze
+		existing.ThawAt = 0
+	case uint64(len(frozen)) >= g.MaxFrozenPrefixes:
+		return fmt.Errorf("%w: max=%d", ErrTooManyFrozenPrefixes, g.MaxFrozenPrefixes)
+	default:
+		frozen = append(frozen, &FrozenPrefix{Prefix: f.Prefix})
+	}
+	if len(frozen) == 0 {
+		frozen = nil
+	}
+	i.Frozen = frozen
+	return t.State.PutSpaceInfo([]byte(f.Space), i, i.Expiry)
+}
+
+func removeFrozen(frozen []*FrozenPrefix, f *FrozenPrefix) []*FrozenPrefix {
+	kept := frozen[:0]
+	for _, fp := range frozen {
+		if fp != f {
+			kept = append(kept, fp)
+		}
+	}
+	return kept
+}
+
+func (f *FreezeTx) Copy() UnsignedTransaction {
+	return &FreezeTx{
+		BaseTx:   f.BaseTx.Copy(),
+		Space:    f.Space,
+		Prefix:   f.Prefix,
+		Unfreeze: f.Unfreeze,
+	}
+}
+
+func (f *FreezeTx) TypedData() *tdata.TypedData {
+	return tdata.CreateTypedData(
+		f.Magic, f.typedChainID(), Freeze,
+		[]tdata.Type{
+			{Name: tdSpace, Type: tdString},
+			{Name: tdPrefix, Type: tdString},
+			{Name: tdUnfreeze, Type: tdBool},
+			{Name: tdPrice, Type: tdUint64},
+			{Name: tdBlockID, Type: tdString},
+		},
+		tdata.TypedDataMessage{
+			tdSpace:    f.Space,
+			tdPrefix:   f.Prefix,
+			tdUnfreeze: f.Unfreeze,
+			tdPrice:    strconv.FormatUint(f.Price, 10),
+			tdBlockID:  f.BlockID.String(),
+		},
+	)
+}
+
+func (f *FreezeTx) Activity() *Activity {
+	return &Activity{
+		Typ:   Freeze,
+		Space: f.Space,
+		Key:   f.Prefix,
+	}
+}
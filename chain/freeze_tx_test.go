@@ -0,0 +1,150 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestFreezeTx(t *testing.T) {
+	t.Parallel()
+
+	owner := common.Address{0x1}
+	other := common.Address{0x2}
+	db := memdb.New()
+	defer db.Close()
+
+	g := DefaultGenesis()
+	g.UnfreezeDelay = 10
+	g.MaxFrozenPrefixes = 2
+	tt := []struct {
+		utx       UnsignedTransaction
+		blockTime uint64
+		sender    common.Address
+		err       error
+	}{
+		{
+			utx:       &ClaimTx{BaseTx: &BaseTx{}, Space: "foo"},
+			blockTime: 1,
+			sender:    owner,
+		},
+		{
+			utx:       &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "release1", Value: []byte("abc")},
+			blockTime: 1,
+			sender:    owner,
+		},
+		{
+			utx:       &FreezeTx{BaseTx: &BaseTx{}, Space: "foo", Prefix: "release"},
+			blockTime: 2,
+			sender:    other,
+			err:       ErrUnauthorized,
+		},
+		{
+			utx:       &FreezeTx{BaseTx: &BaseTx{}, Space: "foo", Prefix: "release", Unfreeze: true},
+			blockTime: 2,
+			sender:    owner,
+			err:       ErrNonActionable,
+		},
+		{
+			utx:       &FreezeTx{BaseTx: &BaseTx{}, Space: "foo", Prefix: "release"},
+			blockTime: 2,
+			sender:    owner,
+		},
+		{
+			utx:       &FreezeTx{BaseTx: &BaseTx{}, Space: "foo", Prefix: "release"},
+			blockTime: 2,
+			sender:    owner,
+			err:       ErrNonActionable,
+		},
+		{ // frozen keys can't be set or deleted
+			utx:       &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "release1", Value: []byte("def")},
+			blockTime: 3,
+			sender:    owner,
+			err:       ErrKeyFrozen,
+		},
+		{
+			utx:       &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "release2", Value: []byte("def")},
+			blockTime: 3,
+			sender:    owner,
+			err:       ErrKeyFrozen,
+		},
+		{
+			utx:       &DeleteTx{BaseTx: &BaseTx{}, Space: "foo", Key: "release1"},
+			blockTime: 3,
+			sender:    owner,
+			err:       ErrKeyFrozen,
+		},
+		{ // the space can't be burned while keys are frozen
+			utx:       &BurnTx{BaseTx: &BaseTx{}, Space: "foo"},
+			blockTime: 3,
+			sender:    owner,
+			err:       ErrKeyFrozen,
+		},
+		{ // other keys can
+			utx:       &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "draft", Value: []byte("def")},
+			blockTime: 3,
+			sender:    owner,
+		},
+		{
+			utx:       &FreezeTx{BaseTx: &BaseTx{}, Space: "foo", Prefix: "draft"},
+			blockTime: 3,
+			sender:    owner,
+		},
+		{
+			utx:       &FreezeTx{BaseTx: &BaseTx{}, Space: "foo"},
+			blockTime: 3,
+			sender:    owner,
+			err:       ErrTooManyFrozenPrefixes,
+		},
+		{
+			utx:       &FreezeTx{BaseTx: &BaseTx{}, Space: "foo", Prefix: "release", Unfreeze: true},
+			blockTime: 4,
+			sender:    owner,
+		},
+		{ // still frozen until the unfreeze delay passes
+			utx:       &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "release1", Value: []byte("def")},
+			blockTime: 13,
+			sender:    owner,
+			err:       ErrKeyFrozen,
+		},
+		{
+			utx:       &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "release1", Value: []byte("def")},
+			blockTime: 14,
+			sender:    owner,
+		},
+		{ // thawed prefixes no longer count against the limit
+			utx:       &FreezeTx{BaseTx: &BaseTx{}, Space: "foo"},
+			blockTime: 14,
+			sender:    owner,
+		},
+	}
+	for i, tv := range tt {
+		tc := &TransactionContext{Genesis: g, State: NewStateDB(db), BlockTime: tv.blockTime, Sender: tv.sender}
+		if err := tv.utx.Execute(tc); !errors.Is(err, tv.err) {
+			t.Fatalf("#%d: tx.Execute err expected %v, got %v", i, tv.err, err)
+		}
+	}
+	i, _, err := GetSpaceInfo(db, []byte("foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []*FrozenPrefix{{Prefix: "draft"}, {Prefix: ""}}
+	if !reflect.DeepEqual(i.Frozen, expected) {
+		t.Fatalf("unexpected frozen prefixes %+v", i.Frozen)
+	}
+
+	utx := &FreezeTx{BaseTx: &BaseTx{}, Space: "foo", Prefix: "draft", Unfreeze: true}
+	parsed, err := ParseTypedData(utx.TypedData())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(parsed, utx) {
+		t.Fatalf("unexpected parsed tx %+v", parsed)
+	}
+}
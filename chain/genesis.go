@@ -6,18 +6,24 @@ package chain
 import (
 	"encoding/json"
 	"fmt"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/versiondb"
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/avalanchego/utils/units"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	log "github.com/inconshreveable/log15"
+
+	"github.com/ava-labs/spacesvm/parser"
 )
 
 const (
 	LotteryRewardDivisor = 100
+	BeneficiaryDivisor   = 100
 	MinBlockCost         = 0
 
 	DefaultFreeClaimStorage  = 1 * units.MiB
@@ -26,6 +32,17 @@ const (
 	DefaultFreeClaimDuration = 60 * 60 * 24 * 30 // 30 Days
 
 	DefaultLookbackWindow = 60
+
+	DefaultMaxLifelineExtension = 60 * 60 * 24 * 365 * 2 // 2 Years
+	DefaultMaxClaimLease        = 10
+	DefaultMaxClaimKeys         = 16
+	DefaultClaimCommitExpiry    = 60 * 60 * 24      // 1 Day
+	DefaultMaxBurnCooldown      = 60 * 60 * 24 * 30 // 30 Days
+	DefaultUnfreezeDelay        = 60 * 60 * 24 * 7  // 7 Days
+	DefaultMaxFrozenPrefixes    = 16
+
+	DefaultFreeWriteQuota  = 16
+	DefaultFreeWriteWindow = 60 * 60 // 1 Hour
 )
 
 type Airdrop struct {
@@ -39,6 +56,30 @@ type CustomAllocation struct {
 	Balance uint64         `serialize:"true" json:"balance"`
 }
 
+// CustomSpace is a space that is claimed at genesis, along with any keys that
+// should be set in it.
+type CustomSpace struct {
+	Space string         `serialize:"true" json:"space"`
+	Owner common.Address `serialize:"true" json:"owner"`
+	// Expiry is the unix timestamp when the space expires. It is not derived
+	// from [Genesis.ClaimReward] because the genesis block has a timestamp of
+	// 0.
+	Expiry uint64       `serialize:"true" json:"expiry"`
+	Keys   []*CustomKey `serialize:"true" json:"keys"`
+}
+
+// RenewalTier discounts the lifeline fees of spaces no longer than
+// [MaxLength] by [Discount].
+type RenewalTier struct {
+	MaxLength uint64 `serialize:"true" json:"maxLength"`
+	Discount  uint64 `serialize:"true" json:"discount"`
+}
+
+type CustomKey struct {
+	Key   string `serialize:"true" json:"key"`
+	Value []byte `serialize:"true" json:"value"`
+}
+
 type Genesis struct {
 	Magic uint64 `serialize:"true" json:"magic"`
 
@@ -49,22 +90,74 @@ type Genesis struct {
 	ValueUnitSize       uint64 `serialize:"true" json:"valueUnitSize"`
 	MaxValueSize        uint64 `serialize:"true" json:"maxValueSize"`
 	ValueExpiryDiscount uint64 `serialize:"true" json:"valueExpiryDiscount"`
+	// MaxKeyLength is the longest key that may be set (0 defaults to
+	// [parser.MaxIdentifierSize])
+	MaxKeyLength uint64 `serialize:"true" json:"maxKeyLength"`
+	// MaxSpaceSize is the most value bytes a single space may store (0
+	// disables the limit)
+	MaxSpaceSize uint64 `serialize:"true" json:"maxSpaceSize"`
+	// FreeWriteSize is the largest value (in bytes) a set may write without
+	// paying for its value units (0 disables free writes). Each space may make
+	// [FreeWriteQuota] free writes every [FreeWriteWindow] seconds, after
+	// which the value units are charged when the set executes.
+	FreeWriteSize   uint64 `serialize:"true" json:"freeWriteSize"`
+	FreeWriteQuota  uint64 `serialize:"true" json:"freeWriteQuota"`
+	FreeWriteWindow uint64 `serialize:"true" json:"freeWriteWindow"`
 
 	// Claim Params
 	ClaimLoadMultiplier         uint64 `serialize:"true" json:"claimLoadMultiplier"`
 	MinClaimFee                 uint64 `serialize:"true" json:"minClaimFee"`
 	SpaceDesirabilityMultiplier uint64 `serialize:"true" json:"spaceDesirabilityMultiplier"`
+	// Share of the claim fee credited to the beneficiary of a claim
+	ClaimBeneficiaryShare uint64 `serialize:"true" json:"claimBeneficiaryShare"` // divided by 100
+	// ClaimCommitDelay is the number of seconds a claim must be committed to
+	// (see [CommitTx]) before it is revealed, so the space can't be front-run
+	// from the gossiped claim. Only claims that set [ClaimTx.Salt] are
+	// committed to when it is 0.
+	ClaimCommitDelay uint64 `serialize:"true" json:"claimCommitDelay"`
+	// ClaimCommitExpiry is the number of seconds a commitment may be revealed
+	// for after it is made (0 never expires commitments)
+	ClaimCommitExpiry uint64 `serialize:"true" json:"claimCommitExpiry"`
+	// MaxClaimKeys is the most keys a claim may set in the space it claims
+	// (see [ClaimTx.Keys]). Claims may not set keys when it is 0.
+	MaxClaimKeys uint64 `serialize:"true" json:"maxClaimKeys"`
+	// MaxBurnCooldown is the longest (in seconds) a burned space may be
+	// blocked from being claimed again (see [BurnTx]). Burns may not block
+	// claims when it is 0.
+	MaxBurnCooldown uint64 `serialize:"true" json:"maxBurnCooldown"`
+	// UnfreezeDelay is the number of seconds after an unfreeze (see
+	// [FreezeTx]) before the keys of its prefix may change again
+	UnfreezeDelay uint64 `serialize:"true" json:"unfreezeDelay"`
+	// MaxFrozenPrefixes is the most prefixes a space may freeze at once.
+	// Spaces may not freeze keys when it is 0.
+	MaxFrozenPrefixes uint64 `serialize:"true" json:"maxFrozenPrefixes"`
 
 	// Lifeline Params
 	SpaceRenewalDiscount uint64 `serialize:"true" json:"spaceRenewalDiscount"`
+	// SpaceRenewalTiers override [SpaceRenewalDiscount] for spaces within
+	// their length (sorted by increasing [RenewalTier.MaxLength]). The first
+	// matching tier applies.
+	SpaceRenewalTiers []*RenewalTier `serialize:"true" json:"spaceRenewalTiers"`
+	// MaxLifelineExtension is the longest explicit extension (in seconds) a
+	// single lifeline may request
+	MaxLifelineExtension uint64 `serialize:"true" json:"maxLifelineExtension"`
 
 	// Reward Params
 	ClaimReward      uint64 `serialize:"true" json:"claimReward"`
 	ClaimExpiryUnits uint64 `serialize:"true" json:"claimExpiryUnits"`
+	// MaxClaimLease is the most standard leases (each worth [ClaimReward])
+	// a claim may buy. A space's lease sets its horizon (see
+	// [SpaceInfo.Horizon]), so premium spaces live longer per unit of
+	// lifeline. Claims may only buy the standard lease when it is 0.
+	MaxClaimLease uint64 `serialize:"true" json:"maxClaimLease"`
 
 	// Mining Reward (% of min required fee)
 	LotteryRewardMultipler uint64 `serialize:"true" json:"lotteryRewardMultipler"` // divided by 100
 
+	// Block Builder Reward (% of fees paid by the txs in a block, credited to
+	// the beneficiary of the block)
+	BlockBeneficiaryShare uint64 `serialize:"true" json:"blockBeneficiaryShare"` // divided by 100
+
 	// Fee Mechanism Params
 	MinPrice         uint64 `serialize:"true" json:"minPrice"`
 	LookbackWindow   int64  `serialize:"true" json:"lookbackWindow"`
@@ -72,11 +165,23 @@ type Genesis struct {
 	TargetBlockSize  uint64 `serialize:"true" json:"targetBlockSize"` // units
 	MaxBlockSize     uint64 `serialize:"true" json:"maxBlockSize"`    // units
 	BlockCostEnabled bool   `serialize:"true" json:"blockCostEnabled"`
+	// HeartbeatInterval is the number of seconds after its parent that a block
+	// without transactions is valid (0 disables empty blocks)
+	HeartbeatInterval int64 `serialize:"true" json:"heartbeatInterval"`
+
+	// AtomicAssetID is the asset whose UTXOs represent native units on other
+	// chains of the subnet (such as the X and P chains). Units are imported
+	// and exported 1:1 as UTXOs of this asset. Import and export transactions
+	// are disabled when empty.
+	AtomicAssetID ids.ID `serialize:"true" json:"atomicAssetID"`
 
 	// Allocations
 	CustomAllocation []*CustomAllocation `serialize:"true" json:"customAllocation"`
 	AirdropHash      string              `serialize:"true" json:"airdropHash"`
 	AirdropUnits     uint64              `serialize:"true" json:"airdropUnits"`
+
+	// Preloaded spaces
+	CustomSpaces []*CustomSpace `serialize:"true" json:"customSpaces"`
 }
 
 func DefaultGenesis() *Genesis {
@@ -88,22 +193,36 @@ func DefaultGenesis() *Genesis {
 		ValueUnitSize:       DefaultValueUnitSize,
 		MaxValueSize:        200 * units.KiB,
 		ValueExpiryDiscount: 10,
+		MaxKeyLength:        parser.MaxIdentifierSize,
+		FreeWriteQuota:      DefaultFreeWriteQuota,
+		FreeWriteWindow:     DefaultFreeWriteWindow,
 
 		// Claim Params
 		ClaimLoadMultiplier:         5,
 		ClaimExpiryUnits:            100,
 		MinClaimFee:                 100,
 		SpaceDesirabilityMultiplier: 5,
+		ClaimBeneficiaryShare:       10,
+		ClaimCommitExpiry:           DefaultClaimCommitExpiry,
+		MaxClaimKeys:                DefaultMaxClaimKeys,
+		MaxBurnCooldown:             DefaultMaxBurnCooldown,
+		UnfreezeDelay:               DefaultUnfreezeDelay,
+		MaxFrozenPrefixes:           DefaultMaxFrozenPrefixes,
 
 		// Lifeline Params
 		SpaceRenewalDiscount: 10,
+		MaxLifelineExtension: DefaultMaxLifelineExtension,
 
 		// Reward Params
-		ClaimReward: DefaultFreeClaimUnits * DefaultFreeClaimDuration,
+		ClaimReward:   DefaultFreeClaimUnits * DefaultFreeClaimDuration,
+		MaxClaimLease: DefaultMaxClaimLease,
 
 		// Lottery Reward (50% of tx.FeeUnits() * block.Price)
 		LotteryRewardMultipler: 50,
 
+		// Block Builder Reward (25% of the fees paid in a block)
+		BlockBeneficiaryShare: 25,
+
 		// Fee Mechanism Params
 		LookbackWindow:   DefaultLookbackWindow, // 60 Seconds
 		TargetBlockRate:  1,                     // 1 Block per Second
@@ -121,13 +240,224 @@ func (g *Genesis) StatefulBlock() *StatefulBlock {
 	}
 }
 
+// Verify rejects parameter combinations that would make the chain unusable.
 func (g *Genesis) Verify() error {
 	if g.Magic == 0 {
 		return ErrInvalidMagic
 	}
-	if g.TargetBlockRate == 0 {
+	if g.TargetBlockRate <= 0 {
 		return ErrInvalidBlockRate
 	}
+	// The fee window must span at least one target block interval
+	if g.LookbackWindow < g.TargetBlockRate {
+		return fmt.Errorf(
+			"%w: lookback window (%d) must be >= target block rate (%d)",
+			ErrInvalidLookbackWindow, g.LookbackWindow, g.TargetBlockRate,
+		)
+	}
+	if g.TargetBlockSize == 0 {
+		return fmt.Errorf("%w: target block size must be > 0", ErrInvalidBlockSize)
+	}
+	if g.HeartbeatInterval < 0 {
+		return fmt.Errorf("%w: heartbeat interval (%d) must be >= 0", ErrInvalidHeartbeat, g.HeartbeatInterval)
+	}
+	// Prices only adjust if the target throughput is at least 1 unit per
+	// second
+	if g.TargetBlockSize/uint64(g.TargetBlockRate) == 0 {
+		return fmt.Errorf(
+			"%w: target block size (%d) must be >= target block rate (%d)",
+			ErrInvalidBlockSize, g.TargetBlockSize, g.TargetBlockRate,
+		)
+	}
+	if g.MaxBlockSize < g.TargetBlockSize {
+		return fmt.Errorf(
+			"%w: max block size (%d) must be >= target block size (%d)",
+			ErrInvalidBlockSize, g.MaxBlockSize, g.TargetBlockSize,
+		)
+	}
+	// Claimed spaces must not expire immediately
+	if g.ClaimReward == 0 || g.ClaimExpiryUnits == 0 {
+		return fmt.Errorf(
+			"%w: claim reward (%d) and claim expiry units (%d) must be > 0",
+			ErrInvalidClaimExpiry, g.ClaimReward, g.ClaimExpiryUnits,
+		)
+	}
+	if g.ClaimReward/g.ClaimExpiryUnits == 0 {
+		return fmt.Errorf(
+			"%w: claim reward (%d) must be >= claim expiry units (%d)",
+			ErrInvalidClaimExpiry, g.ClaimReward, g.ClaimExpiryUnits,
+		)
+	}
+	if g.MaxClaimLease > 0 && g.ClaimReward > math.MaxUint64/g.MaxClaimLease {
+		return fmt.Errorf(
+			"%w: max claim lease (%d) overflows the horizon of claim reward (%d)",
+			ErrInvalidClaimExpiry, g.MaxClaimLease, g.ClaimReward,
+		)
+	}
+	if g.ClaimCommitExpiry > 0 && g.ClaimCommitExpiry <= g.ClaimCommitDelay {
+		return fmt.Errorf(
+			"%w: claim commit expiry (%d) must be > claim commit delay (%d)",
+			ErrInvalidClaimCommit, g.ClaimCommitExpiry, g.ClaimCommitDelay,
+		)
+	}
+	if g.ValueUnitSize == 0 {
+		return ErrInvalidValueUnitSize
+	}
+	if err := g.verifyStorageLimits(); err != nil {
+		return err
+	}
+	if g.ClaimBeneficiaryShare > BeneficiaryDivisor {
+		return fmt.Errorf(
+			"%w: claim beneficiary share (%d) must be <= %d",
+			ErrInvalidBeneficiaryShare, g.ClaimBeneficiaryShare, BeneficiaryDivisor,
+		)
+	}
+	if g.BlockBeneficiaryShare+g.LotteryRewardMultipler > BeneficiaryDivisor {
+		return fmt.Errorf(
+			"%w: block beneficiary share (%d) and lottery reward (%d) must sum to <= %d",
+			ErrInvalidBeneficiaryShare, g.BlockBeneficiaryShare, g.LotteryRewardMultipler, BeneficiaryDivisor,
+		)
+	}
+	if err := g.verifyRenewalTiers(); err != nil {
+		return err
+	}
+	return g.verifyCustomSpaces()
+}
+
+func (g *Genesis) verifyStorageLimits() error {
+	if g.MaxValueSize == 0 {
+		return fmt.Errorf("%w: max value size must be > 0", ErrInvalidStorageLimit)
+	}
+	// Content-addressed keys must always be settable
+	if g.MaxKeyLength != 0 && (g.MaxKeyLength < HashLen || g.MaxKeyLength > parser.MaxIdentifierSize) {
+		return fmt.Errorf(
+			"%w: max key length (%d) must be between %d and %d",
+			ErrInvalidStorageLimit, g.MaxKeyLength, HashLen, parser.MaxIdentifierSize,
+		)
+	}
+	if g.MaxSpaceSize != 0 && g.MaxSpaceSize < g.MaxValueSize {
+		return fmt.Errorf(
+			"%w: max space size (%d) must be >= max value size (%d)",
+			ErrInvalidStorageLimit, g.MaxSpaceSize, g.MaxValueSize,
+		)
+	}
+	if g.FreeWriteSize > 0 && (g.FreeWriteQuota == 0 || g.FreeWriteWindow == 0) {
+		return fmt.Errorf(
+			"%w: free write quota (%d) and window (%d) must be > 0",
+			ErrInvalidFreeWrites, g.FreeWriteQuota, g.FreeWriteWindow,
+		)
+	}
+	return nil
+}
+
+// FreeWrite returns true if a value of [size] bytes may be written without
+// paying for its value units (see [FreeWriteSize]).
+func (g *Genesis) FreeWrite(size uint64) bool {
+	return g.FreeWriteSize > 0 && size <= g.FreeWriteSize
+}
+
+// KeyLengthLimit returns the longest key that may be set.
+func (g *Genesis) KeyLengthLimit() uint64 {
+	if g.MaxKeyLength == 0 {
+		return parser.MaxIdentifierSize
+	}
+	return g.MaxKeyLength
+}
+
+// CheckValue returns an error if [key] or a value of [size] bytes exceed the
+// storage limits of a single value.
+func (g *Genesis) CheckValue(key string, size uint64) error {
+	if l := g.KeyLengthLimit(); uint64(len(key)) > l {
+		return fmt.Errorf("%w: max=%d found=%d", ErrKeyTooLong, l, len(key))
+	}
+	switch {
+	case size == 0:
+		return ErrValueEmpty
+	case size > g.MaxValueSize:
+		return fmt.Errorf("%w: max=%d found=%d", ErrValueTooBig, g.MaxValueSize, size)
+	}
+	return nil
+}
+
+// CheckSpaceSize returns an error if a space storing [size] value bytes
+// exceeds [MaxSpaceSize].
+func (g *Genesis) CheckSpaceSize(size uint64) error {
+	if g.MaxSpaceSize > 0 && size > g.MaxSpaceSize {
+		return fmt.Errorf("%w: max=%d found=%d", ErrSpaceFull, g.MaxSpaceSize, size)
+	}
+	return nil
+}
+
+func (g *Genesis) verifyRenewalTiers() error {
+	if g.SpaceRenewalDiscount == 0 {
+		return fmt.Errorf("%w: space renewal discount must be > 0", ErrInvalidRenewalDiscount)
+	}
+	prev := uint64(0)
+	for i, tier := range g.SpaceRenewalTiers {
+		if tier.Discount == 0 {
+			return fmt.Errorf("%w: tier %d discount must be > 0", ErrInvalidRenewalDiscount, i)
+		}
+		if tier.MaxLength <= prev {
+			return fmt.Errorf(
+				"%w: tier %d max length (%d) must be > previous max length (%d)",
+				ErrInvalidRenewalDiscount, i, tier.MaxLength, prev,
+			)
+		}
+		prev = tier.MaxLength
+	}
+	return nil
+}
+
+// RenewalDiscount returns the discount applied to the lifeline fees of
+// [space].
+func (g *Genesis) RenewalDiscount(space string) uint64 {
+	for _, tier := range g.SpaceRenewalTiers {
+		if uint64(len(space)) <= tier.MaxLength {
+			return tier.Discount
+		}
+	}
+	return g.SpaceRenewalDiscount
+}
+
+func (g *Genesis) verifyCustomSpaces() error {
+	spaces := map[string]struct{}{}
+	for _, cs := range g.CustomSpaces {
+		if err := parser.CheckContents(cs.Space); err != nil {
+			return fmt.Errorf("%w: space=%s", err, cs.Space)
+		}
+		if _, ok := spaces[cs.Space]; ok {
+			return fmt.Errorf("%w: space=%s", ErrDuplicateSpace, cs.Space)
+		}
+		spaces[cs.Space] = struct{}{}
+		if len(cs.Space) == hexAddressLen && strings.ToLower(cs.Owner.Hex()) != cs.Space {
+			return fmt.Errorf("%w: space=%s", ErrAddressMismatch, cs.Space)
+		}
+		if cs.Expiry == 0 {
+			return fmt.Errorf("%w: space=%s must have an expiry", ErrInvalidClaimExpiry, cs.Space)
+		}
+
+		keys := map[string]struct{}{}
+		size := uint64(0)
+		for _, ck := range cs.Keys {
+			if err := parser.CheckContents(ck.Key); err != nil {
+				return fmt.Errorf("%w: space=%s key=%s", err, cs.Space, ck.Key)
+			}
+			if _, ok := keys[ck.Key]; ok {
+				return fmt.Errorf("%w: space=%s key=%s", ErrDuplicateKey, cs.Space, ck.Key)
+			}
+			keys[ck.Key] = struct{}{}
+			if err := g.CheckValue(ck.Key, uint64(len(ck.Value))); err != nil {
+				return fmt.Errorf("%w: space=%s key=%s", err, cs.Space, ck.Key)
+			}
+			size += uint64(len(ck.Value))
+			if err := g.CheckSpaceSize(size); err != nil {
+				return fmt.Errorf("%w: space=%s", err, cs.Space)
+			}
+			if len(ck.Key) == HashLen && ck.Key != valueHash(ck.Value) {
+				return fmt.Errorf("%w: space=%s key=%s", ErrInvalidKey, cs.Space, ck.Key)
+			}
+		}
+	}
 	return nil
 }
 
@@ -141,7 +471,7 @@ func (g *Genesis) Load(db database.Database, airdropData []byte) error {
 	if len(g.AirdropHash) > 0 {
 		h := common.BytesToHash(crypto.Keccak256(airdropData)).Hex()
 		if g.AirdropHash != h {
-			return fmt.Errorf("expected standard allocation %s but got %s", g.AirdropHash, h)
+			return fmt.Errorf("%w: expected standard allocation %s but got %s", ErrAirdropMismatch, g.AirdropHash, h)
 		}
 
 		airdrop := []*Airdrop{}
@@ -169,6 +499,45 @@ func (g *Genesis) Load(db database.Database, airdropData []byte) error {
 		log.Debug("applied custom allocation", "addr", alloc.Address, "balance", alloc.Balance)
 	}
 
+	if err := g.loadCustomSpaces(vdb); err != nil {
+		return err
+	}
+
 	// Commit as a batch to improve speed
 	return vdb.Commit()
 }
+
+// loadCustomSpaces claims each [CustomSpace] as if it were claimed in the
+// genesis block and stores its keys. Values are linked by their hash since
+// there is no transaction that created them.
+func (g *Genesis) loadCustomSpaces(db database.Database) error {
+	for _, cs := range g.CustomSpaces {
+		i := &SpaceInfo{
+			Owner:  cs.Owner,
+			Expiry: cs.Expiry,
+			Units:  g.ClaimExpiryUnits,
+		}
+		for _, ck := range cs.Keys {
+			i.Units += StorageUnits(g, uint64(len(ck.Value)))
+			i.Size += uint64(len(ck.Value))
+		}
+		if err := PutSpaceInfo(db, []byte(cs.Space), i, 0); err != nil {
+			return fmt.Errorf("%w: space=%s", err, cs.Space)
+		}
+		for _, ck := range cs.Keys {
+			vid := ids.ID(crypto.Keccak256Hash(ck.Value))
+			if err := db.Put(PrefixTxValueKey(vid), ck.Value); err != nil {
+				return err
+			}
+			vmeta := &ValueMeta{
+				Size: uint64(len(ck.Value)),
+				TxID: vid,
+			}
+			if err := PutSpaceKey(db, []byte(cs.Space), []byte(ck.Key), vmeta, common.Hash(vid)); err != nil {
+				return fmt.Errorf("%w: space=%s key=%s", err, cs.Space, ck.Key)
+			}
+		}
+		log.Debug("applied custom space", "space", cs.Space, "owner", cs.Owner, "keys", len(cs.Keys))
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+func TestGenesisVerify(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		name   string
+		modify func(g *Genesis)
+		err    error
+	}{
+		{
+			name:   "default",
+			modify: func(g *Genesis) {},
+		},
+		{
+			name:   "zero magic",
+			modify: func(g *Genesis) { g.Magic = 0 },
+			err:    ErrInvalidMagic,
+		},
+		{
+			name:   "zero block rate",
+			modify: func(g *Genesis) { g.TargetBlockRate = 0 },
+			err:    ErrInvalidBlockRate,
+		},
+		{
+			name:   "lookback shorter than block rate",
+			modify: func(g *Genesis) { g.TargetBlockRate, g.LookbackWindow = 10, 5 },
+			err:    ErrInvalidLookbackWindow,
+		},
+		{
+			name:   "zero target block size",
+			modify: func(g *Genesis) { g.TargetBlockSize = 0 },
+			err:    ErrInvalidBlockSize,
+		},
+		{
+			name:   "target throughput below 1 unit per second",
+			modify: func(g *Genesis) { g.TargetBlockRate, g.TargetBlockSize = 10, 5 },
+			err:    ErrInvalidBlockSize,
+		},
+		{
+			name:   "negative heartbeat interval",
+			modify: func(g *Genesis) { g.HeartbeatInterval = -1 },
+			err:    ErrInvalidHeartbeat,
+		},
+		{
+			name:   "max block size below target",
+			modify: func(g *Genesis) { g.MaxBlockSize = g.TargetBlockSize - 1 },
+			err:    ErrInvalidBlockSize,
+		},
+		{
+			name:   "zero claim reward",
+			modify: func(g *Genesis) { g.ClaimReward = 0 },
+			err:    ErrInvalidClaimExpiry,
+		},
+		{
+			name:   "zero claim expiry units",
+			modify: func(g *Genesis) { g.ClaimExpiryUnits = 0 },
+			err:    ErrInvalidClaimExpiry,
+		},
+		{
+			name:   "claim expires immediately",
+			modify: func(g *Genesis) { g.ClaimReward = g.ClaimExpiryUnits - 1 },
+			err:    ErrInvalidClaimExpiry,
+		},
+		{
+			name:   "claim commitments expire before reveal",
+			modify: func(g *Genesis) { g.ClaimCommitDelay = g.ClaimCommitExpiry },
+			err:    ErrInvalidClaimCommit,
+		},
+		{
+			name:   "free writes without quota",
+			modify: func(g *Genesis) { g.FreeWriteSize = 1; g.FreeWriteQuota = 0 },
+			err:    ErrInvalidFreeWrites,
+		},
+		{
+			name:   "zero value unit size",
+			modify: func(g *Genesis) { g.ValueUnitSize = 0 },
+			err:    ErrInvalidValueUnitSize,
+		},
+		{
+			name:   "zero max value size",
+			modify: func(g *Genesis) { g.MaxValueSize = 0 },
+			err:    ErrInvalidStorageLimit,
+		},
+		{
+			name:   "max key length shorter than a value hash",
+			modify: func(g *Genesis) { g.MaxKeyLength = HashLen - 1 },
+			err:    ErrInvalidStorageLimit,
+		},
+		{
+			name:   "max key length above identifier size",
+			modify: func(g *Genesis) { g.MaxKeyLength = parser.MaxIdentifierSize + 1 },
+			err:    ErrInvalidStorageLimit,
+		},
+		{
+			name:   "max space size below max value size",
+			modify: func(g *Genesis) { g.MaxSpaceSize = g.MaxValueSize - 1 },
+			err:    ErrInvalidStorageLimit,
+		},
+		{
+			name:   "block beneficiary share and lottery reward above 100%",
+			modify: func(g *Genesis) { g.BlockBeneficiaryShare = BeneficiaryDivisor - g.LotteryRewardMultipler + 1 },
+			err:    ErrInvalidBeneficiaryShare,
+		},
+		{
+			name:   "beneficiary share above 100%",
+			modify: func(g *Genesis) { g.ClaimBeneficiaryShare = BeneficiaryDivisor + 1 },
+			err:    ErrInvalidBeneficiaryShare,
+		},
+		{
+			name:   "zero renewal discount",
+			modify: func(g *Genesis) { g.SpaceRenewalDiscount = 0 },
+			err:    ErrInvalidRenewalDiscount,
+		},
+		{
+			name: "renewal tiers",
+			modify: func(g *Genesis) {
+				g.SpaceRenewalTiers = []*RenewalTier{
+					{MaxLength: 4, Discount: 2},
+					{MaxLength: 8, Discount: 5},
+				}
+			},
+		},
+		{
+			name: "zero renewal tier discount",
+			modify: func(g *Genesis) {
+				g.SpaceRenewalTiers = []*RenewalTier{{MaxLength: 4}}
+			},
+			err: ErrInvalidRenewalDiscount,
+		},
+		{
+			name: "unsorted renewal tiers",
+			modify: func(g *Genesis) {
+				g.SpaceRenewalTiers = []*RenewalTier{
+					{MaxLength: 8, Discount: 5},
+					{MaxLength: 4, Discount: 2},
+				}
+			},
+			err: ErrInvalidRenewalDiscount,
+		},
+		{
+			name: "duplicate custom space",
+			modify: func(g *Genesis) {
+				g.CustomSpaces = []*CustomSpace{
+					{Space: "network", Expiry: 1},
+					{Space: "network", Expiry: 1},
+				}
+			},
+			err: ErrDuplicateSpace,
+		},
+		{
+			name: "custom space without expiry",
+			modify: func(g *Genesis) {
+				g.CustomSpaces = []*CustomSpace{{Space: "network"}}
+			},
+			err: ErrInvalidClaimExpiry,
+		},
+		{
+			name: "custom key without value",
+			modify: func(g *Genesis) {
+				g.CustomSpaces = []*CustomSpace{
+					{Space: "network", Expiry: 1, Keys: []*CustomKey{{Key: "name"}}},
+				}
+			},
+			err: ErrValueEmpty,
+		},
+		{
+			name: "custom key too long",
+			modify: func(g *Genesis) {
+				g.MaxKeyLength = HashLen
+				g.CustomSpaces = []*CustomSpace{
+					{Space: "network", Expiry: 1, Keys: []*CustomKey{
+						{Key: strings.Repeat("a", HashLen+1), Value: []byte("spaces")},
+					}},
+				}
+			},
+			err: ErrKeyTooLong,
+		},
+		{
+			name: "custom space too big",
+			modify: func(g *Genesis) {
+				g.MaxValueSize, g.MaxSpaceSize = 4, 6
+				g.CustomSpaces = []*CustomSpace{
+					{Space: "network", Expiry: 1, Keys: []*CustomKey{
+						{Key: "a", Value: []byte("abcd")},
+						{Key: "b", Value: []byte("abc")},
+					}},
+				}
+			},
+			err: ErrSpaceFull,
+		},
+	}
+	for _, tv := range tt {
+		g := DefaultGenesis()
+		g.Magic = 1
+		tv.modify(g)
+		err := g.Verify()
+		if tv.err == nil && err != nil {
+			t.Fatalf("%s: unexpected error %v", tv.name, err)
+		}
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("%s: expected %v, got %v", tv.name, tv.err, err)
+		}
+	}
+}
+
+func TestGenesisLoadCustomSpaces(t *testing.T) {
+	t.Parallel()
+
+	g := DefaultGenesis()
+	g.Magic = 1
+	owner := common.Address{0x1}
+	g.CustomSpaces = []*CustomSpace{
+		{
+			Space:  "network",
+			Owner:  owner,
+			Expiry: 1000,
+			Keys: []*CustomKey{
+				{Key: "name", Value: []byte("spaces")},
+			},
+		},
+	}
+	if err := g.Verify(); err != nil {
+		t.Fatal(err)
+	}
+
+	db := memdb.New()
+	if err := g.Load(db, nil); err != nil {
+		t.Fatal(err)
+	}
+	i, exists, err := GetSpaceInfo(db, []byte("network"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exists {
+		t.Fatal("space should exist")
+	}
+	if i.Owner != owner || i.Expiry != 1000 || i.Size != uint64(len("spaces")) {
+		t.Fatalf("unexpected space info %+v", i)
+	}
+	v, exists, err := GetValue(db, []byte("network"), []byte("name"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exists || !bytes.Equal(v, []byte("spaces")) {
+		t.Fatalf("unexpected value %q", v)
+	}
+	owned, err := GetAllOwned(db, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(owned) != 1 || owned[0] != "network" {
+		t.Fatalf("unexpected owned spaces %v", owned)
+	}
+}
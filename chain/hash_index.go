@@ -0,0 +1,146 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+// hashIndexKey is present once every stored value is indexed by its hash
+var hashIndexKey = []byte("hash_index")
+
+// [hashPrefix] + [delimiter] + [hash]
+func hashBaseKey(h common.Hash) (k []byte) {
+	k = make([]byte, 2+common.HashLength)
+	k[0] = hashPrefix
+	k[1] = parser.ByteDelimiter
+	copy(k[2:], h[:])
+	return k
+}
+
+// [hashPrefix] + [delimiter] + [hash] + [space] + [delimiter] + [key]
+func prefixHashKey(h common.Hash, space []byte, key []byte) (k []byte) {
+	base := hashBaseKey(h)
+	k = make([]byte, len(base)+len(space)+1+len(key))
+	copy(k, base)
+	copy(k[len(base):], space)
+	k[len(base)+len(space)] = parser.ByteDelimiter
+	copy(k[len(base)+len(space)+1:], key)
+	return k
+}
+
+// [valueHashPrefix] + [delimiter] + [rawSpace] + [key]
+func prefixValueHashKey(rspace ids.ShortID, key []byte) (k []byte) {
+	k = make([]byte, 2+shortIDLen+len(key))
+	k[0] = valueHashPrefix
+	k[1] = parser.ByteDelimiter
+	copy(k[2:], rspace[:])
+	copy(k[2+shortIDLen:], key)
+	return k
+}
+
+// putValueHash indexes the value of [space]/[key] by its hash [h], replacing
+// the entry of the value it overwrites.
+func putValueHash(
+	db database.KeyValueReaderWriterDeleter, space []byte, rspace ids.ShortID, key []byte, h common.Hash,
+) error {
+	if err := deleteValueHash(db, rspace, key); err != nil {
+		return err
+	}
+	// [hash] + [space]
+	v := make([]byte, common.HashLength+len(space))
+	copy(v, h[:])
+	copy(v[common.HashLength:], space)
+	if err := db.Put(prefixValueHashKey(rspace, key), v); err != nil {
+		return err
+	}
+	return db.Put(prefixHashKey(h, space, key), nil)
+}
+
+// deleteValueHash removes the hash index entry of the value at [key] in
+// [rspace] (if any).
+func deleteValueHash(db database.KeyValueReaderWriterDeleter, rspace ids.ShortID, key []byte) error {
+	k := prefixValueHashKey(rspace, key)
+	v, err := db.Get(k)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(v) < common.HashLength {
+		return fmt.Errorf("%w: value hash %x", ErrInvalidIndex, v)
+	}
+	h := common.BytesToHash(v[:common.HashLength])
+	if err := db.Delete(prefixHashKey(h, v[common.HashLength:], key)); err != nil {
+		return err
+	}
+	return db.Delete(k)
+}
+
+// FindByHash returns up to [limit] paths ("space/key", sorted) whose values
+// hash to [h] (see [ValueHash]), continuing from [cursor] (see [Cursor]). The
+// returned cursor is empty when there are no more paths.
+func FindByHash(db database.Iteratee, h common.Hash, cursor string, limit int, height uint64) ([]string, string, error) {
+	c, err := ParseCursor(cursor, height)
+	if err != nil {
+		return nil, "", err
+	}
+	paths := []string{}
+	next, err := paginate(db, hashBaseKey(h), c, limit, nil, func(path []byte, _ []byte) error {
+		paths = append(paths, string(path))
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return paths, next, nil
+}
+
+// ValueHash is the hash values are indexed by (and that resolve responses
+// attest to)
+func ValueHash(value []byte) common.Hash {
+	return crypto.Keccak256Hash(value)
+}
+
+// HasHashIndex returns true if every stored value is indexed by its hash
+func HasHashIndex(db database.KeyValueReader) (bool, error) {
+	return db.Has(hashIndexKey)
+}
+
+// RebuildHashIndex indexes every stored value by its hash (used when upgrading
+// a database that does not index them) and returns the number of values
+// indexed.
+func RebuildHashIndex(db database.Database) (int, error) {
+	infos, err := loadSpaceInfos(db)
+	if err != nil {
+		return 0, err
+	}
+	indexed := 0
+	for space, i := range infos {
+		kvs, err := GetAllValueMetas(db, i.RawSpace)
+		if err != nil {
+			return indexed, err
+		}
+		for _, kv := range kvs {
+			v, err := getLinkedValue(db, kv.ValueMeta.TxID[:])
+			if err != nil {
+				return indexed, fmt.Errorf("%w: value of %s/%s: %v", ErrInvalidIndex, space, kv.Key, err)
+			}
+			if err := putValueHash(db, []byte(space), i.RawSpace, []byte(kv.Key), ValueHash(v)); err != nil {
+				return indexed, err
+			}
+			indexed++
+		}
+	}
+	return indexed, db.Put(hashIndexKey, nil)
+}
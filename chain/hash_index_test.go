@@ -0,0 +1,117 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestHashIndex(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.New()
+	for _, space := range []string{"foo", "bar"} {
+		if err := PutSpaceInfo(db, []byte(space), &SpaceInfo{Owner: common.Address{0x1}, Expiry: 100, Units: 1}, 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	put := func(space string, key string, value string) {
+		txID := ids.GenerateTestID()
+		if err := db.Put(PrefixTxValueKey(txID), []byte(value)); err != nil {
+			t.Fatal(err)
+		}
+		vmeta := &ValueMeta{Size: uint64(len(value)), TxID: txID}
+		if err := PutSpaceKey(db, []byte(space), []byte(key), vmeta, ValueHash([]byte(value))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	check := func(value string, expected ...string) {
+		t.Helper()
+		paths, next, err := FindByHash(db, ValueHash([]byte(value)), "", 0, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if next != "" || len(paths) != len(expected) {
+			t.Fatalf("expected %v, got %v (next=%q)", expected, paths, next)
+		}
+		for i := range expected {
+			if paths[i] != expected[i] {
+				t.Fatalf("expected %v, got %v", expected, paths)
+			}
+		}
+	}
+
+	put("foo", "a", "hello")
+	put("foo", "b", "hello")
+	put("bar", "a", "hello")
+	put("bar", "b", "world")
+	check("hello", "bar/a", "foo/a", "foo/b")
+	check("world", "bar/b")
+
+	// Overwritten and deleted values are no longer found
+	put("foo", "b", "world")
+	if err := DeleteSpaceKey(db, []byte("bar"), []byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	check("hello", "foo/a")
+	check("world", "bar/b", "foo/b")
+
+	// Pages continue after the cursor
+	paths, next, err := FindByHash(db, ValueHash([]byte("world")), "", 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 || paths[0] != "bar/b" || next == "" {
+		t.Fatalf("unexpected first page %v (next=%q)", paths, next)
+	}
+	paths, next, err = FindByHash(db, ValueHash([]byte("world")), next, 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 || paths[0] != "foo/b" || next != "" {
+		t.Fatalf("unexpected second page %v (next=%q)", paths, next)
+	}
+
+	// Values of cleared spaces are no longer found
+	i, _, err := GetSpaceInfo(db, []byte("bar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := clearSpaceValues(db, i.RawSpace, math.MaxInt); err != nil {
+		t.Fatal(err)
+	}
+	check("world", "foo/b")
+
+	// Rebuilding the index from the stored values finds the same paths
+	if has, err := HasHashIndex(db); err != nil || has {
+		t.Fatalf("expected no hash index marker (err=%v)", err)
+	}
+	for _, p := range []byte{hashPrefix, valueHashPrefix} {
+		cursor := db.NewIteratorWithPrefix(CompactablePrefixKey(p))
+		for cursor.Next() {
+			if err := db.Delete(cursor.Key()); err != nil {
+				t.Fatal(err)
+			}
+		}
+		cursor.Release()
+	}
+	check("hello")
+	indexed, err := RebuildHashIndex(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if indexed != 2 {
+		t.Fatalf("expected 2 values indexed, got %d", indexed)
+	}
+	if has, err := HasHashIndex(db); err != nil || !has {
+		t.Fatalf("expected hash index marker (err=%v)", err)
+	}
+	check("hello", "foo/a")
+	check("world", "foo/b")
+}
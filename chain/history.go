@@ -0,0 +1,365 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+// [height] + [tx index]
+const historyPositionLen = 8 + 4
+
+// deferredHistory is the height of the first accepted block whose activity
+// has not been indexed yet (only present while indexing is deferred)
+var deferredHistory = []byte("deferred_history")
+
+// [historyPrefix] + [delimiter] + [id] + [delimiter]
+func historyBaseKey(p byte, id []byte) (k []byte) {
+	k = make([]byte, 2+len(id)+1)
+	k[0] = p
+	k[1] = parser.ByteDelimiter
+	copy(k[2:], id)
+	k[2+len(id)] = parser.ByteDelimiter
+	return k
+}
+
+// [historyPrefix] + [delimiter] + [id] + [delimiter] + [height] + [tx index]
+func historyKey(p byte, id []byte, position []byte) (k []byte) {
+	base := historyBaseKey(p, id)
+	k = make([]byte, len(base)+len(position))
+	copy(k, base)
+	copy(k[len(base):], position)
+	return k
+}
+
+func historyPosition(height uint64, index uint32) []byte {
+	p := make([]byte, historyPositionLen)
+	binary.BigEndian.PutUint64(p, height)
+	binary.BigEndian.PutUint32(p[8:], index)
+	return p
+}
+
+// indexedBlock is the tx index and history entries written for an accepted
+// block, so they can be trimmed by [TrimIndexes]
+type indexedBlock struct {
+	Tmstmp int64        `serialize:"true"`
+	Txs    []*indexedTx `serialize:"true"`
+}
+
+type indexedTx struct {
+	TxID   ids.ID         `serialize:"true"`
+	Sender common.Address `serialize:"true"`
+	Space  string         `serialize:"true"`
+}
+
+// [indexedPrefix] + [delimiter] + [height]
+func indexedKey(height uint64) (k []byte) {
+	k = make([]byte, 2+8)
+	k[0] = indexedPrefix
+	k[1] = parser.ByteDelimiter
+	binary.BigEndian.PutUint64(k[2:], height)
+	return k
+}
+
+// IndexHistory records the activity of each transaction in [blk] under the
+// space it affects and under its sender.
+func IndexHistory(db database.KeyValueWriter, blk *StatelessBlock) error {
+	indexed := &indexedBlock{Tmstmp: blk.Tmstmp, Txs: make([]*indexedTx, len(blk.Txs))}
+	for i, tx := range blk.Txs {
+		activity := tx.Activity()
+		activity.Tmstmp = blk.Tmstmp
+		b, err := Marshal(activity)
+		if err != nil {
+			return err
+		}
+		position := historyPosition(blk.Hght, uint32(i))
+		if len(activity.Space) > 0 {
+			if err := db.Put(historyKey(historyPrefix, []byte(activity.Space), position), b); err != nil {
+				return err
+			}
+		}
+		sender := tx.Sender()
+		if err := db.Put(historyKey(senderPrefix, sender[:], position), b); err != nil {
+			return err
+		}
+		indexed.Txs[i] = &indexedTx{TxID: tx.ID(), Sender: sender, Space: activity.Space}
+	}
+	b, err := Marshal(indexed)
+	if err != nil {
+		return err
+	}
+	return db.Put(indexedKey(blk.Hght), b)
+}
+
+// TrimIndexes deletes the tx index and history entries of up to [limit]
+// accepted blocks with timestamps at or before [cutoff], oldest first. It
+// returns the number of blocks trimmed and index entries deleted. Blocks
+// indexed before trimming was introduced are not trimmed.
+func TrimIndexes(db database.Database, cutoff int64, limit int) (blocks int, entries int, err error) {
+	cursor := db.NewIteratorWithPrefix([]byte{indexedPrefix, parser.ByteDelimiter})
+	defer cursor.Release()
+	for blocks < limit && cursor.Next() {
+		// [indexedPrefix] + [delimiter] + [height]
+		curKey := cursor.Key()
+		if len(curKey) != 2+8 {
+			return blocks, entries, fmt.Errorf("%w: indexed block key %x", ErrInvalidIndex, curKey)
+		}
+		indexed := new(indexedBlock)
+		if _, err := Unmarshal(cursor.Value(), indexed); err != nil {
+			return blocks, entries, err
+		}
+		if indexed.Tmstmp > cutoff {
+			break
+		}
+		height := binary.BigEndian.Uint64(curKey[2:])
+		for i, tx := range indexed.Txs {
+			position := historyPosition(height, uint32(i))
+			keys := [][]byte{
+				PrefixTxKey(tx.TxID),
+				historyKey(senderPrefix, tx.Sender[:], position),
+			}
+			if len(tx.Space) > 0 {
+				keys = append(keys, historyKey(historyPrefix, []byte(tx.Space), position))
+			}
+			for _, k := range keys {
+				if err := db.Delete(k); err != nil {
+					return blocks, entries, err
+				}
+			}
+			entries += len(keys)
+		}
+		if err := db.Delete(curKey); err != nil {
+			return blocks, entries, err
+		}
+		blocks++
+	}
+	return blocks, entries, cursor.Error()
+}
+
+// ClearIndexes deletes the tx index, the space and sender history, and the
+// indexed block records (along with any deferred history marker) so they can
+// be rebuilt with [ReindexBlock]. It returns the number of entries deleted.
+func ClearIndexes(db database.Database) (int, error) {
+	cleared := 0
+	for _, p := range []byte{txPrefix, historyPrefix, senderPrefix, indexedPrefix} {
+		cursor := db.NewIteratorWithPrefix(CompactablePrefixKey(p))
+		for cursor.Next() {
+			if err := db.Delete(cursor.Key()); err != nil {
+				cursor.Release()
+				return cleared, err
+			}
+			cleared++
+		}
+		err := cursor.Error()
+		cursor.Release()
+		if err != nil {
+			return cleared, err
+		}
+	}
+	return cleared, ClearDeferredHistory(db)
+}
+
+// ReindexBlock writes the height, tx index, and history entries of the
+// accepted block [blk].
+func ReindexBlock(db database.KeyValueWriter, blk *StatelessBlock) error {
+	bid := blk.ID()
+	if err := db.Put(PrefixBlockHeightKey(blk.Hght), bid[:]); err != nil {
+		return err
+	}
+	for _, tx := range blk.Txs {
+		if err := SetTransaction(db, tx, bid); err != nil {
+			return err
+		}
+	}
+	return IndexHistory(db, blk)
+}
+
+// DeferHistory records that the activity of [blk] (and every block accepted
+// after it) has not been indexed. If indexing was already deferred, the
+// original height is kept.
+func DeferHistory(db database.KeyValueReaderWriter, blk *StatelessBlock) error {
+	has, err := db.Has(deferredHistory)
+	if err != nil || has {
+		return err
+	}
+	return SetDeferredHistory(db, blk.Hght)
+}
+
+// GetDeferredHistory returns the height of the first block whose activity has
+// not been indexed, if any.
+func GetDeferredHistory(db database.KeyValueReader) (uint64, bool, error) {
+	v, err := db.Get(deferredHistory)
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return binary.BigEndian.Uint64(v), true, nil
+}
+
+// SetDeferredHistory sets the height of the first block whose activity has not
+// been indexed.
+func SetDeferredHistory(db database.KeyValueWriter, height uint64) error {
+	v := make([]byte, 8)
+	binary.BigEndian.PutUint64(v, height)
+	return db.Put(deferredHistory, v)
+}
+
+// ClearDeferredHistory records that the activity of every accepted block has
+// been indexed.
+func ClearDeferredHistory(db database.KeyValueDeleter) error {
+	return db.Delete(deferredHistory)
+}
+
+// GetSpaceHistory returns up to [limit] activities affecting [space] in
+// chronological order, continuing from [cursor] (see [Cursor]). A new
+// listing includes activity accepted up to [height]. The returned cursor is
+// empty when there are no more activities.
+func GetSpaceHistory(db database.Iteratee, space []byte, cursor string, limit int, height uint64) ([]*Activity, string, error) {
+	return getHistory(db, historyPrefix, space, cursor, limit, height)
+}
+
+// GetSenderHistory returns up to [limit] transactions sent by [sender] in
+// chronological order, continuing from [cursor] (see [Cursor]). A new
+// listing includes activity accepted up to [height]. The returned cursor is
+// empty when there are no more activities.
+func GetSenderHistory(db database.Iteratee, sender common.Address, cursor string, limit int, height uint64) ([]*Activity, string, error) {
+	return getHistory(db, senderPrefix, sender[:], cursor, limit, height)
+}
+
+// GetSpaceChanges returns the keys of [space] starting with [keyPrefix] (in
+// sorted order) that were set or deleted by the blocks accepted after
+// [since], up to [height]. About [limit] activities are read (see
+// [PageLimit]), but blocks are never split, so it also returns the height of
+// the last block read ([height] once every change was read). [reset] is true
+// if the space was claimed since, in which case keys may have been removed by
+// its expiry without being reported.
+func GetSpaceChanges(
+	db database.Database, space []byte, keyPrefix string, since uint64, height uint64, limit int,
+) (keys []string, through uint64, reset bool, err error) {
+	if err := checkHistory(db, since, height); err != nil {
+		return nil, 0, false, err
+	}
+	limit = PageLimit(limit)
+	base := historyBaseKey(historyPrefix, space)
+	iter := db.NewIteratorWithStart(historyKey(historyPrefix, space, historyPosition(since+1, 0)))
+	defer iter.Release()
+
+	changed := map[string]struct{}{}
+	through = height
+	var (
+		last uint64
+		read int
+	)
+	for iter.Next() {
+		curKey := iter.Key()
+		if !bytes.HasPrefix(curKey, base) {
+			break
+		}
+		position := curKey[len(base):]
+		if len(position) != historyPositionLen {
+			return nil, 0, false, fmt.Errorf("%w: history key %x", ErrInvalidIndex, curKey)
+		}
+		h := binary.BigEndian.Uint64(position)
+		if h > height {
+			break
+		}
+		if read >= limit && h != last {
+			through = last
+			break
+		}
+		a := new(Activity)
+		if _, err := Unmarshal(iter.Value(), a); err != nil {
+			return nil, 0, false, err
+		}
+		switch a.Typ {
+		case Set, Delete:
+			if strings.HasPrefix(a.Key, keyPrefix) {
+				changed[a.Key] = struct{}{}
+			}
+		case Claim:
+			reset = true
+		}
+		last = h
+		read++
+	}
+	if err := iter.Error(); err != nil {
+		return nil, 0, false, err
+	}
+	keys = make([]string, 0, len(changed))
+	for k := range changed {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, through, reset, nil
+}
+
+// checkHistory returns [ErrHistoryUnavailable] if the activity of the blocks
+// accepted after [since] (up to [height]) was trimmed or is not indexed yet
+func checkHistory(db database.Database, since uint64, height uint64) error {
+	if since >= height {
+		return nil
+	}
+	deferred, deferring, err := GetDeferredHistory(db)
+	if err != nil {
+		return err
+	}
+	if deferring && deferred <= height {
+		return fmt.Errorf("%w: activity since height %d is not indexed yet", ErrHistoryUnavailable, deferred)
+	}
+	// Indexes are trimmed oldest first, so the oldest indexed block is the
+	// first one with history
+	cursor := db.NewIteratorWithPrefix([]byte{indexedPrefix, parser.ByteDelimiter})
+	defer cursor.Release()
+	if cursor.Next() {
+		curKey := cursor.Key()
+		if len(curKey) != 2+8 {
+			return fmt.Errorf("%w: indexed block key %x", ErrInvalidIndex, curKey)
+		}
+		if oldest := binary.BigEndian.Uint64(curKey[2:]); oldest > since+1 {
+			return fmt.Errorf("%w: activity before height %d was trimmed", ErrHistoryUnavailable, oldest)
+		}
+	}
+	return cursor.Error()
+}
+
+func getHistory(db database.Iteratee, p byte, id []byte, cursor string, limit int, height uint64) ([]*Activity, string, error) {
+	c, err := ParseCursor(cursor, height)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(c.Key) > 0 && len(c.Key) != historyPositionLen {
+		return nil, "", fmt.Errorf("%w: %q", ErrInvalidCursor, cursor)
+	}
+	activity := []*Activity{}
+	next, err := paginate(db, historyBaseKey(p, id), c, limit,
+		func(position []byte) bool {
+			return binary.BigEndian.Uint64(position) <= c.Height
+		},
+		func(_ []byte, v []byte) error {
+			a := new(Activity)
+			if _, err := Unmarshal(v, a); err != nil {
+				return err
+			}
+			activity = append(activity, a)
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, "", err
+	}
+	return activity, next, nil
+}
@@ -0,0 +1,314 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestGetSpaceHistory(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.New()
+	space := []byte("foo")
+	for h := uint64(1); h <= 5; h++ {
+		b, err := Marshal(&Activity{Tmstmp: int64(h), Typ: Set, Space: "foo"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Put(historyKey(historyPrefix, space, historyPosition(h, 0)), b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Entries for other spaces should not be returned
+	b, err := Marshal(&Activity{Typ: Claim, Space: "foobar"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put(historyKey(historyPrefix, []byte("foobar"), historyPosition(1, 0)), b); err != nil {
+		t.Fatal(err)
+	}
+
+	activity, next, err := GetSpaceHistory(db, space, "", 3, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(activity) != 3 || next == "" {
+		t.Fatalf("expected 3 activities and a cursor, got %d %q", len(activity), next)
+	}
+	for i, a := range activity {
+		if a.Tmstmp != int64(i+1) {
+			t.Fatalf("activity %d has unexpected timestamp %d", i, a.Tmstmp)
+		}
+	}
+
+	activity, next, err = GetSpaceHistory(db, space, next, 3, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(activity) != 2 || next != "" {
+		t.Fatalf("expected 2 activities and no cursor, got %d %q", len(activity), next)
+	}
+	if activity[0].Tmstmp != 4 || activity[1].Tmstmp != 5 {
+		t.Fatalf("unexpected activity %+v %+v", activity[0], activity[1])
+	}
+
+	// Listings exclude activity accepted after they started, even if it is
+	// indexed before the next page is requested
+	activity, next, err = GetSpaceHistory(db, space, "", 2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(activity) != 2 || next == "" {
+		t.Fatalf("expected 2 activities and a cursor, got %d %q", len(activity), next)
+	}
+	activity, next, err = GetSpaceHistory(db, space, next, 2, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(activity) != 1 || activity[0].Tmstmp != 3 || next != "" {
+		t.Fatalf("expected only the activity at height 3 and no cursor, got %d %q", len(activity), next)
+	}
+
+	if _, _, err := GetSpaceHistory(db, space, "zz", 3, 5); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("expected %v, got %v", ErrInvalidCursor, err)
+	}
+}
+
+func TestGetSenderHistory(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.New()
+	sender := common.Address{0x1}
+	b, err := Marshal(&Activity{Typ: Transfer, Sender: sender.Hex()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put(historyKey(senderPrefix, sender[:], historyPosition(1, 0)), b); err != nil {
+		t.Fatal(err)
+	}
+
+	activity, next, err := GetSenderHistory(db, sender, "", 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(activity) != 1 || next != "" {
+		t.Fatalf("expected 1 activity and no cursor, got %d %q", len(activity), next)
+	}
+	activity, _, err = GetSenderHistory(db, common.Address{0x2}, "", 0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(activity) != 0 {
+		t.Fatalf("expected no activity, got %d", len(activity))
+	}
+}
+
+func TestDeferHistory(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.New()
+	if _, deferred, err := GetDeferredHistory(db); err != nil || deferred {
+		t.Fatalf("expected no deferred history, got %t %v", deferred, err)
+	}
+	for h := uint64(3); h <= 5; h++ {
+		blk := &StatelessBlock{StatefulBlock: &StatefulBlock{Hght: h}}
+		if err := DeferHistory(db, blk); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// The first deferred height is kept
+	height, deferred, err := GetDeferredHistory(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !deferred || height != 3 {
+		t.Fatalf("expected deferred history at 3, got %t %d", deferred, height)
+	}
+
+	if err := ClearDeferredHistory(db); err != nil {
+		t.Fatal(err)
+	}
+	if _, deferred, err := GetDeferredHistory(db); err != nil || deferred {
+		t.Fatalf("expected no deferred history, got %t %v", deferred, err)
+	}
+}
+
+func TestTrimIndexes(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.New()
+	sender := common.Address{0x1}
+	txIDs := []ids.ID{ids.GenerateTestID(), ids.GenerateTestID(), ids.GenerateTestID()}
+	for i, txID := range txIDs {
+		h := uint64(i + 1)
+		indexed := &indexedBlock{
+			Tmstmp: int64(h * 10),
+			Txs:    []*indexedTx{{TxID: txID, Sender: sender, Space: "foo"}},
+		}
+		b, err := Marshal(indexed)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Put(indexedKey(h), b); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Put(PrefixTxKey(txID), nil); err != nil {
+			t.Fatal(err)
+		}
+		b, err = Marshal(&Activity{Tmstmp: indexed.Tmstmp, TxID: txID, Typ: Set, Space: "foo"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, k := range [][]byte{
+			historyKey(historyPrefix, []byte("foo"), historyPosition(h, 0)),
+			historyKey(senderPrefix, sender[:], historyPosition(h, 0)),
+		} {
+			if err := db.Put(k, b); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	blocks, entries, err := TrimIndexes(db, 20, 1)
+	if err != nil || blocks != 1 || entries != 3 {
+		t.Fatalf("unexpected trim blocks=%d entries=%d err=%v", blocks, entries, err)
+	}
+	blocks, entries, err = TrimIndexes(db, 20, 10)
+	if err != nil || blocks != 1 || entries != 3 {
+		t.Fatalf("unexpected trim blocks=%d entries=%d err=%v", blocks, entries, err)
+	}
+	for i, txID := range txIDs {
+		has, err := HasTransaction(db, txID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if trimmed := i < 2; has == trimmed {
+			t.Fatalf("#%d: tx index exists=%t after trimming", i, has)
+		}
+	}
+	activity, _, err := GetSpaceHistory(db, []byte("foo"), "", 10, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(activity) != 1 {
+		t.Fatalf("expected 1 activity after trimming, got %d", len(activity))
+	}
+	activity, _, err = GetSenderHistory(db, sender, "", 10, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(activity) != 1 {
+		t.Fatalf("expected 1 sender activity after trimming, got %d", len(activity))
+	}
+}
+
+func TestClearIndexes(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.New()
+	sender := common.Address{0x1}
+	txID := ids.GenerateTestID()
+	position := historyPosition(1, 0)
+	for _, k := range [][]byte{
+		PrefixTxKey(txID),
+		historyKey(historyPrefix, []byte("foo"), position),
+		historyKey(senderPrefix, sender[:], position),
+		indexedKey(1),
+	} {
+		if err := db.Put(k, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := SetDeferredHistory(db, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetBalance(db, sender, 1); err != nil {
+		t.Fatal(err)
+	}
+
+	cleared, err := ClearIndexes(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cleared != 4 {
+		t.Fatalf("cleared expected 4, got %d", cleared)
+	}
+	if _, deferred, err := GetDeferredHistory(db); err != nil || deferred {
+		t.Fatalf("unexpected deferred history %t (err=%v)", deferred, err)
+	}
+	if bal, err := GetBalance(db, sender); err != nil || bal != 1 {
+		t.Fatalf("unexpected balance %d (err=%v)", bal, err)
+	}
+}
+
+func TestGetSpaceChanges(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.New()
+	space := []byte("foo")
+	put := func(h uint64, i uint32, a *Activity) {
+		b, err := Marshal(a)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Put(historyKey(historyPrefix, space, historyPosition(h, i)), b); err != nil {
+			t.Fatal(err)
+		}
+	}
+	put(1, 0, &Activity{Typ: Claim, Space: "foo"})
+	put(2, 0, &Activity{Typ: Set, Space: "foo", Key: "a1"})
+	put(2, 1, &Activity{Typ: Set, Space: "foo", Key: "b"})
+	put(3, 0, &Activity{Typ: Lifeline, Space: "foo"})
+	put(4, 0, &Activity{Typ: Delete, Space: "foo", Key: "a1"})
+	put(4, 1, &Activity{Typ: Set, Space: "foo", Key: "a2"})
+
+	check := func(since uint64, keyPrefix string, limit int, keys []string, through uint64, reset bool) {
+		t.Helper()
+		k, th, r, err := GetSpaceChanges(db, space, keyPrefix, since, 4, limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(k) != len(keys) || th != through || r != reset {
+			t.Fatalf("expected %v through %d (reset=%t), got %v through %d (reset=%t)", keys, through, reset, k, th, r)
+		}
+		for i := range keys {
+			if k[i] != keys[i] {
+				t.Fatalf("expected %v, got %v", keys, k)
+			}
+		}
+	}
+	check(0, "", 0, []string{"a1", "a2", "b"}, 4, true)
+	check(1, "", 0, []string{"a1", "a2", "b"}, 4, false)
+	check(1, "a", 0, []string{"a1", "a2"}, 4, false)
+	check(2, "", 0, []string{"a1", "a2"}, 4, false)
+	check(4, "", 0, []string{}, 4, false)
+	// Blocks are not split by the limit
+	check(1, "", 1, []string{"a1", "b"}, 2, false)
+	check(2, "", 1, []string{}, 3, false)
+
+	// Activity of trimmed or deferred blocks is unavailable
+	b, err := Marshal(&indexedBlock{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Put(indexedKey(3), b); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := GetSpaceChanges(db, space, "", 1, 4, 0); !errors.Is(err, ErrHistoryUnavailable) {
+		t.Fatalf("expected %v, got %v", ErrHistoryUnavailable, err)
+	}
+	check(2, "", 0, []string{"a1", "a2"}, 4, false)
+	if err := SetDeferredHistory(db, 4); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := GetSpaceChanges(db, space, "", 2, 4, 0); !errors.Is(err, ErrHistoryUnavailable) {
+		t.Fatalf("expected %v, got %v", ErrHistoryUnavailable, err)
+	}
+}
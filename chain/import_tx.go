@@ -0,0 +1,122 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
+
+	"github.com/ava-labs/spacesvm/tdata"
+)
+
+var (
+	_ UnsignedTransaction = &ImportTx{}
+	_ AtomicTransaction   = &ImportTx{}
+)
+
+type ImportTx struct {
+	*BaseTx `serialize:"true" json:"baseTx"`
+
+	// SourceChain is the chain (such as the X or P chain) that exported the
+	// UTXO.
+	SourceChain ids.ID `serialize:"true" json:"sourceChain"`
+
+	// UTXOID is the ID of a UTXO of [Genesis.AtomicAssetID] that
+	// [SourceChain] exported to this chain. It must be spendable by the
+	// sender's key alone (see [ShortAddress]). Its amount is credited to the
+	// sender.
+	UTXOID ids.ID `serialize:"true" json:"utxoID"`
+}
+
+func (i *ImportTx) Execute(c *TransactionContext) error {
+	if err := verifyAtomic(c, i.SourceChain); err != nil {
+		return err
+	}
+	imported, err := c.State.HasImported(i.UTXOID)
+	if err != nil {
+		return err
+	}
+	if imported {
+		return fmt.Errorf("%w: %s", ErrUTXOImported, i.UTXOID)
+	}
+	values, err := c.Atomic.SharedMemory().Get(i.SourceChain, [][]byte{i.UTXOID[:]})
+	if err != nil {
+		return fmt.Errorf("%w: %s (%v)", ErrUTXOMissing, i.UTXOID, err)
+	}
+	utxo, err := ParseAtomicUTXO(values[0])
+	if err != nil {
+		return err
+	}
+	if utxo.InputID() != i.UTXOID {
+		return fmt.Errorf("%w: %s", ErrUTXOMissing, i.UTXOID)
+	}
+	if utxo.AssetID() != c.Genesis.AtomicAssetID {
+		return fmt.Errorf("%w: asset %s", ErrInvalidUTXO, utxo.AssetID())
+	}
+	out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+	if !ok {
+		return fmt.Errorf("%w: unexpected output %T", ErrInvalidUTXO, utxo.Out)
+	}
+	if out.Locktime > c.BlockTime {
+		return fmt.Errorf("%w: locked until %d", ErrInvalidUTXO, out.Locktime)
+	}
+	if out.Threshold != 1 || !containsShortID(out.Addrs, c.SenderShortID) {
+		return ErrUnauthorized
+	}
+	if _, err := c.State.ModifyBalance(c.Sender, true, out.Amt); err != nil {
+		return err
+	}
+	return c.State.SetImported(i.UTXOID)
+}
+
+func containsShortID(addrs []ids.ShortID, addr ids.ShortID) bool {
+	for _, a := range addrs {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *ImportTx) AtomicRequests(_ *Genesis, _ ids.ID) (ids.ID, *atomic.Requests, error) {
+	return i.SourceChain, &atomic.Requests{
+		RemoveRequests: [][]byte{i.UTXOID[:]},
+	}, nil
+}
+
+func (i *ImportTx) Copy() UnsignedTransaction {
+	return &ImportTx{
+		BaseTx:      i.BaseTx.Copy(),
+		SourceChain: i.SourceChain,
+		UTXOID:      i.UTXOID,
+	}
+}
+
+func (i *ImportTx) TypedData() *tdata.TypedData {
+	return tdata.CreateTypedData(
+		i.Magic, i.typedChainID(), Import,
+		[]tdata.Type{
+			{Name: tdSourceChain, Type: tdString},
+			{Name: tdUTXOID, Type: tdString},
+			{Name: tdPrice, Type: tdUint64},
+			{Name: tdBlockID, Type: tdString},
+		},
+		tdata.TypedDataMessage{
+			tdSourceChain: i.SourceChain.String(),
+			tdUTXOID:      i.UTXOID.String(),
+			tdPrice:       strconv.FormatUint(i.Price, 10),
+			tdBlockID:     i.BlockID.String(),
+		},
+	)
+}
+
+func (i *ImportTx) Activity() *Activity {
+	return &Activity{
+		Typ: Import,
+	}
+}
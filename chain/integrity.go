@@ -0,0 +1,171 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ethereum/go-ethereum/common"
+	log "github.com/inconshreveable/log15"
+)
+
+// runningKey is present while a VM has the database open, so it is only
+// found at startup if the node did not shut down cleanly.
+var runningKey = []byte("running")
+
+// MarkRunning records that the database is in use until [MarkStopped] is
+// called.
+func MarkRunning(db database.KeyValueWriter) error {
+	return db.Put(runningKey, nil)
+}
+
+// MarkStopped records that the database was closed cleanly.
+func MarkStopped(db database.KeyValueDeleter) error {
+	return db.Delete(runningKey)
+}
+
+// HasUncleanShutdown returns true if the database was last opened by a VM
+// that did not shut down cleanly (see [MarkRunning]).
+func HasUncleanShutdown(db database.KeyValueReader) (bool, error) {
+	return db.Has(runningKey)
+}
+
+// RepairIndexes verifies that the expiry and owned indexes are consistent
+// with the stored [SpaceInfo] records and repairs any drift (missing or
+// dangling entries). It returns the number of repairs made.
+func RepairIndexes(db database.Database) (int, error) {
+	repairs := 0
+
+	infos, err := loadSpaceInfos(db)
+	if err != nil {
+		return repairs, err
+	}
+
+	// Fix missing or stale index entries for each space
+	expected := map[string]struct{}{}
+	for space, i := range infos {
+		k := PrefixExpiryKey(i.Expiry, i.RawSpace)
+		expected[string(k)] = struct{}{}
+		v := ExpiryDataValue(i.Owner, []byte(space))
+		stored, err := db.Get(k)
+		if err != nil && !errors.Is(err, database.ErrNotFound) {
+			return repairs, err
+		}
+		if err != nil || !bytes.Equal(stored, v) {
+			log.Warn("repairing expiry index", "space", space, "expiry", i.Expiry)
+			if err := db.Put(k, v); err != nil {
+				return repairs, err
+			}
+			repairs++
+		}
+
+		ok := PrefixOwnedKey(i.Owner, []byte(space))
+		has, err := db.Has(ok)
+		if err != nil {
+			return repairs, err
+		}
+		if !has {
+			log.Warn("repairing owned index", "space", space, "owner", i.Owner)
+			if err := db.Put(ok, nil); err != nil {
+				return repairs, err
+			}
+			repairs++
+		}
+	}
+
+	// Remove dangling expiry entries
+	danglingExpiry, err := collectKeys(db, CompactablePrefixKey(expiryPrefix), func(k []byte, _ []byte) bool {
+		_, ok := expected[string(k)]
+		return !ok
+	})
+	if err != nil {
+		return repairs, err
+	}
+
+	// Remove dangling owned entries
+	danglingOwned, err := collectKeys(db, CompactablePrefixKey(ownedPrefix), func(k []byte, _ []byte) bool {
+		// [ownedPrefix] + [delimiter] + [address] + [delimiter] + [space]
+		if len(k) <= 2+common.AddressLength+1 {
+			return true
+		}
+		owner := common.BytesToAddress(k[2 : 2+common.AddressLength])
+		i, ok := infos[string(k[2+common.AddressLength+1:])]
+		return !ok || i.Owner != owner
+	})
+	if err != nil {
+		return repairs, err
+	}
+
+	for _, k := range append(danglingExpiry, danglingOwned...) {
+		log.Warn("removing dangling index entry", "key", common.Bytes2Hex(k))
+		if err := db.Delete(k); err != nil {
+			return repairs, err
+		}
+		repairs++
+	}
+	return repairs, nil
+}
+
+// RepairTxIndex ensures that every transaction in [blk] is present in the
+// tx index. It returns the number of repairs made.
+func RepairTxIndex(db database.Database, blk *StatelessBlock) (int, error) {
+	repairs := 0
+	for _, tx := range blk.Txs {
+		has, err := HasTransaction(db, tx.ID())
+		if err != nil {
+			return repairs, err
+		}
+		if has {
+			continue
+		}
+		log.Warn("repairing tx index", "txID", tx.ID(), "blkID", blk.ID())
+		if err := SetTransaction(db, tx, blk.ID()); err != nil {
+			return repairs, err
+		}
+		repairs++
+	}
+	return repairs, nil
+}
+
+// loadSpaceInfos returns all stored [SpaceInfo] records keyed by space.
+func loadSpaceInfos(db database.Iteratee) (map[string]*SpaceInfo, error) {
+	baseKey := SpaceInfoKey(nil)
+	cursor := db.NewIteratorWithStart(baseKey)
+	defer cursor.Release()
+	infos := map[string]*SpaceInfo{}
+	for cursor.Next() {
+		curKey := cursor.Key()
+		if !bytes.HasPrefix(curKey, baseKey) {
+			break
+		}
+		i := new(SpaceInfo)
+		if _, err := Unmarshal(cursor.Value(), i); err != nil {
+			return nil, err
+		}
+		// [infoPrefix] + [delimiter] + [space]
+		infos[string(curKey[2:])] = i
+	}
+	return infos, cursor.Error()
+}
+
+// collectKeys returns copies of all keys with [prefix] that satisfy [f].
+func collectKeys(db database.Iteratee, prefix []byte, f func(k []byte, v []byte) bool) ([][]byte, error) {
+	cursor := db.NewIteratorWithStart(prefix)
+	defer cursor.Release()
+	keys := [][]byte{}
+	for cursor.Next() {
+		curKey := cursor.Key()
+		if !bytes.HasPrefix(curKey, prefix) {
+			break
+		}
+		if f(curKey, cursor.Value()) {
+			k := make([]byte, len(curKey))
+			copy(k, curKey)
+			keys = append(keys, k)
+		}
+	}
+	return keys, cursor.Error()
+}
@@ -0,0 +1,96 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestRepairIndexes(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.New()
+	owner := common.Address{0x1}
+	space := []byte("foo")
+	i := &SpaceInfo{Owner: owner, Created: 1, Updated: 1, Expiry: 100, Units: 1}
+	if err := PutSpaceInfo(db, space, i, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// Consistent state should not require any repairs
+	repairs, err := RepairIndexes(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repairs != 0 {
+		t.Fatalf("repairs expected 0, got %d", repairs)
+	}
+
+	// Drop the expiry and owned entries and add dangling ones
+	if err := db.Delete(PrefixExpiryKey(i.Expiry, i.RawSpace)); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Delete(PrefixOwnedKey(owner, space)); err != nil {
+		t.Fatal(err)
+	}
+	dangling := PrefixExpiryKey(50, ids.ShortID{0x2})
+	if err := db.Put(dangling, ExpiryDataValue(owner, []byte("bar"))); err != nil {
+		t.Fatal(err)
+	}
+	danglingOwned := PrefixOwnedKey(common.Address{0x3}, space)
+	if err := db.Put(danglingOwned, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	repairs, err = RepairIndexes(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if repairs != 4 {
+		t.Fatalf("repairs expected 4, got %d", repairs)
+	}
+	for _, k := range [][]byte{PrefixExpiryKey(i.Expiry, i.RawSpace), PrefixOwnedKey(owner, space)} {
+		has, err := db.Has(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !has {
+			t.Fatalf("expected %x to be repaired", k)
+		}
+	}
+	for _, k := range [][]byte{dangling, danglingOwned} {
+		has, err := db.Has(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if has {
+			t.Fatalf("expected %x to be removed", k)
+		}
+	}
+}
+
+func TestUncleanShutdown(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.New()
+	if unclean, err := HasUncleanShutdown(db); err != nil || unclean {
+		t.Fatalf("expected a new database to be clean (err=%v)", err)
+	}
+	if err := MarkRunning(db); err != nil {
+		t.Fatal(err)
+	}
+	if unclean, err := HasUncleanShutdown(db); err != nil || !unclean {
+		t.Fatalf("expected a running database to be unclean (err=%v)", err)
+	}
+	if err := MarkStopped(db); err != nil {
+		t.Fatal(err)
+	}
+	if unclean, err := HasUncleanShutdown(db); err != nil || unclean {
+		t.Fatalf("expected a stopped database to be clean (err=%v)", err)
+	}
+}
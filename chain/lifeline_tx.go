@@ -4,6 +4,7 @@
 package chain
 
 import (
+	"fmt"
 	"strconv"
 
 	"github.com/ava-labs/spacesvm/parser"
@@ -22,9 +23,19 @@ type LifelineTx struct {
 	// The space must be ^[a-z0-9]{1,256}$.
 	Space string `serialize:"true" json:"space"`
 
-	// Units is the number of [ClaimReward] to extend
-	// the life of the [Space].
+	// Units is the number of lifeline units (each worth the horizon of the
+	// [Space]) to extend the life of the [Space] with.
 	Units uint64 `serialize:"true" json:"units"`
+
+	// Extension is the exact number of seconds to extend the life of the
+	// [Space] by (up to [Genesis.MaxLifelineExtension]). [Units] must cover
+	// it, and any credit beyond it is forfeited. If zero, the [Space] is
+	// extended by all of the credit [Units] provide.
+	//
+	// Anyone may fund a lifeline, so services can sell renewals of a
+	// predictable length. The funder is recorded as the sender of the
+	// lifeline activity.
+	Extension uint64 `serialize:"true" json:"extension"`
 }
 
 func (l *LifelineTx) Execute(t *TransactionContext) error {
@@ -37,7 +48,7 @@ func (l *LifelineTx) Execute(t *TransactionContext) error {
 	}
 
 	g := t.Genesis
-	i, has, err := GetSpaceInfo(t.Database, []byte(l.Space))
+	i, has, err := t.State.GetSpaceInfo([]byte(l.Space))
 	if err != nil {
 		return err
 	}
@@ -46,57 +57,67 @@ func (l *LifelineTx) Execute(t *TransactionContext) error {
 		return ErrSpaceMissing
 	}
 	// Lifeline spread across all units
+	credit := LifelineCredit(SpaceHorizon(g, i), i.Units, l.Units)
+	if l.Extension > 0 {
+		if l.Extension > g.MaxLifelineExtension {
+			return fmt.Errorf("%w: max=%d found=%d", ErrExtensionTooLong, g.MaxLifelineExtension, l.Extension)
+		}
+		if credit < l.Extension {
+			return fmt.Errorf("%w: credit=%d extension=%d", ErrInsufficientLifeline, credit, l.Extension)
+		}
+		credit = l.Extension
+	}
 	lastExpiry := i.Expiry
-	i.Expiry += (g.ClaimReward * l.Units) / i.Units
-	return PutSpaceInfo(t.Database, []byte(l.Space), i, lastExpiry)
+	i.Expiry += credit
+	return t.State.PutSpaceInfo([]byte(l.Space), i, lastExpiry)
 }
 
-func (l *LifelineTx) FeeUnits(g *Genesis) uint64 {
-	// FeeUnits are discounted so that, all else equal, it is easier for an owner
-	// to retain their space than for another to claim it.
-	dSpaceNameUnits := spaceNameUnits(g, l.Space) / g.SpaceRenewalDiscount
-
+func (l *LifelineTx) FeeUnits(g *Genesis) Units {
 	// The more desirable the space, the more it costs to maintain it.
 	//
 	// Note, this heavy base cost incentivizes users to send fewer transactions
 	// to extend their space's life instead of many small ones.
-	return l.LoadUnits(g) + dSpaceNameUnits*l.Units
+	return l.LoadUnits(g) + RenewalUnits(g, l.Space)*l.Units
 }
 
-func (l *LifelineTx) LoadUnits(g *Genesis) uint64 {
+func (l *LifelineTx) LoadUnits(g *Genesis) Units {
 	return l.BaseTx.LoadUnits(g) * g.ClaimLoadMultiplier
 }
 
 func (l *LifelineTx) Copy() UnsignedTransaction {
 	return &LifelineTx{
-		BaseTx: l.BaseTx.Copy(),
-		Space:  l.Space,
-		Units:  l.Units,
+		BaseTx:    l.BaseTx.Copy(),
+		Space:     l.Space,
+		Units:     l.Units,
+		Extension: l.Extension,
 	}
 }
 
 func (l *LifelineTx) TypedData() *tdata.TypedData {
 	return tdata.CreateTypedData(
-		l.Magic, Lifeline,
+		l.Magic, l.typedChainID(), Lifeline,
 		[]tdata.Type{
 			{Name: tdSpace, Type: tdString},
 			{Name: tdUnits, Type: tdUint64},
+			{Name: tdExtension, Type: tdUint64},
 			{Name: tdPrice, Type: tdUint64},
 			{Name: tdBlockID, Type: tdString},
 		},
 		tdata.TypedDataMessage{
-			tdSpace:   l.Space,
-			tdUnits:   strconv.FormatUint(l.Units, 10),
-			tdPrice:   strconv.FormatUint(l.Price, 10),
-			tdBlockID: l.BlockID.String(),
+			tdSpace:     l.Space,
+			tdUnits:     strconv.FormatUint(l.Units, 10),
+			tdExtension: strconv.FormatUint(l.Extension, 10),
+			tdPrice:     strconv.FormatUint(l.Price, 10),
+			tdBlockID:   l.BlockID.String(),
 		},
 	)
 }
 
 func (l *LifelineTx) Activity() *Activity {
 	return &Activity{
-		Typ:   Lifeline,
-		Space: l.Space,
-		Units: l.Units,
+		Typ:       Lifeline,
+		Space:     l.Space,
+		Units:     l.Units,
+		Extension: l.Extension,
 	}
 }
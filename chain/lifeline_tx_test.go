@@ -66,7 +66,7 @@ func TestLifelineTx(t *testing.T) {
 	for i, tv := range tt {
 		tc := &TransactionContext{
 			Genesis:   g,
-			Database:  db,
+			State:     NewStateDB(db),
 			BlockTime: tv.blockTime,
 			TxID:      ids.Empty,
 			Sender:    tv.sender,
@@ -80,3 +80,100 @@ func TestLifelineTx(t *testing.T) {
 		}
 	}
 }
+
+func TestLifelineTxExtension(t *testing.T) {
+	t.Parallel()
+
+	owner := common.Address{0x1}
+	funder := common.Address{0x2}
+	db := memdb.New()
+	defer db.Close()
+
+	g := DefaultGenesis()
+	tc := &TransactionContext{Genesis: g, State: NewStateDB(db), BlockTime: 1, Sender: owner}
+	if err := (&ClaimTx{BaseTx: &BaseTx{}, Space: "foo"}).Execute(tc); err != nil {
+		t.Fatal(err)
+	}
+	credit := LifelineCredit(g.ClaimReward, g.ClaimExpiryUnits, 1)
+
+	tt := []struct {
+		utx      *LifelineTx
+		extended uint64
+		err      error
+	}{
+		{
+			utx: &LifelineTx{BaseTx: &BaseTx{}, Space: "foo", Units: 1, Extension: g.MaxLifelineExtension + 1},
+			err: ErrExtensionTooLong,
+		},
+		{
+			utx: &LifelineTx{BaseTx: &BaseTx{}, Space: "foo", Units: 1, Extension: credit + 1},
+			err: ErrInsufficientLifeline,
+		},
+		{ // excess credit is forfeited
+			utx:      &LifelineTx{BaseTx: &BaseTx{}, Space: "foo", Units: 1, Extension: 60},
+			extended: 60,
+		},
+		{
+			utx: &LifelineTx{
+				BaseTx:    &BaseTx{},
+				Space:     "foo",
+				Units:     RequiredLifelineUnits(g.ClaimReward, g.ClaimExpiryUnits, credit+1),
+				Extension: credit + 1,
+			},
+			extended: credit + 1,
+		},
+	}
+	for i, tv := range tt {
+		before, _, err := GetSpaceInfo(db, []byte("foo"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Anyone may fund a lifeline
+		tc := &TransactionContext{Genesis: g, State: NewStateDB(db), BlockTime: 1, Sender: funder}
+		if err := tv.utx.Execute(tc); !errors.Is(err, tv.err) {
+			t.Fatalf("#%d: tx.Execute err expected %v, got %v", i, tv.err, err)
+		}
+		after, _, err := GetSpaceInfo(db, []byte("foo"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if after.Expiry-before.Expiry != tv.extended {
+			t.Fatalf("#%d: expected extension of %d, got %d", i, tv.extended, after.Expiry-before.Expiry)
+		}
+		if after.Owner != owner {
+			t.Fatalf("#%d: funding a lifeline should not change the owner", i)
+		}
+	}
+}
+
+func TestLifelineTxPremiumHorizon(t *testing.T) {
+	t.Parallel()
+
+	owner := common.Address{0x1}
+	db := memdb.New()
+	defer db.Close()
+
+	g := DefaultGenesis()
+	tc := &TransactionContext{Genesis: g, State: NewStateDB(db), BlockTime: 1, Sender: owner}
+	if err := (&ClaimTx{BaseTx: &BaseTx{}, Space: "foo", Lease: 2}).Execute(tc); err != nil {
+		t.Fatal(err)
+	}
+	before, _, err := GetSpaceInfo(db, []byte("foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each lifeline unit is worth the premium horizon of the space
+	credit := LifelineCredit(2*g.ClaimReward, g.ClaimExpiryUnits, 1)
+	utx := &LifelineTx{BaseTx: &BaseTx{}, Space: "foo", Units: 1, Extension: credit}
+	if err := utx.Execute(tc); err != nil {
+		t.Fatal(err)
+	}
+	after, _, err := GetSpaceInfo(db, []byte("foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Expiry-before.Expiry != credit {
+		t.Fatalf("expected extension of %d, got %d", credit, after.Expiry-before.Expiry)
+	}
+}
@@ -7,10 +7,35 @@ import (
 	"github.com/ava-labs/avalanchego/ids"
 )
 
+// DropReason is why a transaction left the mempool without being accepted
+type DropReason string
+
+const (
+	// DropIncluded txs were included in a verified block
+	DropIncluded DropReason = "included"
+	// DropExpired txs referenced a block that left the lookback window
+	DropExpired DropReason = "expired"
+	// DropInvalid txs failed execution while building a block
+	DropInvalid DropReason = "invalid"
+	// DropEvicted txs paid the lowest price when the mempool was full
+	DropEvicted DropReason = "evicted"
+	// DropGossiped txs were handed off to peers while regossiping
+	DropGossiped DropReason = "gossiped"
+	// DropConflict claims paid no more than a competing claim for the same
+	// space (only one of which can be accepted)
+	DropConflict DropReason = "conflict"
+)
+
+// DropReasons are all possible [DropReason]s
+var DropReasons = []DropReason{DropIncluded, DropExpired, DropInvalid, DropEvicted, DropGossiped, DropConflict}
+
 type Mempool interface {
 	Len() int
 	Prune(ids.Set)
 	PopMax() (*Transaction, uint64)
 	Add(*Transaction) bool
 	NewTxs(uint64) []*Transaction
+	// Drop removes [txID] (if it is still in the mempool) and records that
+	// it left for [reason]
+	Drop(txID ids.ID, reason DropReason)
 }
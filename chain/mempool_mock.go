@@ -51,6 +51,18 @@ func (mr *MockMempoolMockRecorder) Add(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockMempool)(nil).Add), arg0)
 }
 
+// Drop mocks base method.
+func (m *MockMempool) Drop(txID ids.ID, reason DropReason) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Drop", txID, reason)
+}
+
+// Drop indicates an expected call of Drop.
+func (mr *MockMempoolMockRecorder) Drop(txID, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Drop", reflect.TypeOf((*MockMempool)(nil).Drop), txID, reason)
+}
+
 // Len mocks base method.
 func (m *MockMempool) Len() int {
 	m.ctrl.T.Helper()
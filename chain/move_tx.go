@@ -24,8 +24,14 @@ type MoveTx struct {
 	// The space must be ^[a-z0-9]{1,256}$.
 	Space string `serialize:"true" json:"space"`
 
-	// To is the recipient of the Space.
+	// To is the recipient of the Space. Moving a space to its owner cancels
+	// its scheduled move.
 	To common.Address `serialize:"true" json:"to"`
+
+	// Activation (if after the block time) schedules the move to transfer the
+	// space in the first block at or after it instead of immediately. The
+	// owner may cancel or replace the move until then.
+	Activation uint64 `serialize:"true" json:"activation"`
 }
 
 func (m *MoveTx) Execute(c *TransactionContext) error {
@@ -38,20 +44,43 @@ func (m *MoveTx) Execute(c *TransactionContext) error {
 		return ErrNonActionable
 	}
 
-	// This prevents someone from transferring a space to themselves.
-	if bytes.Equal(m.To[:], c.Sender[:]) {
-		return ErrNonActionable
-	}
-
 	// Veify space is owned by sender
 	i, err := verifySpace(m.Space, c)
 	if err != nil {
 		return err
 	}
+
+	// This prevents someone from transferring a space to themselves (which
+	// only cancels a scheduled move).
+	scheduled := i.Scheduled.Activation > 0
+	if bytes.Equal(m.To[:], c.Sender[:]) && !scheduled {
+		return ErrNonActionable
+	}
+	if err := i.Policy.check(c, Move, m, 0); err != nil {
+		return err
+	}
+
+	// Replace any scheduled move
+	if scheduled {
+		if err := c.State.DeleteScheduledMove(i.RawSpace, i.Scheduled.Activation); err != nil {
+			return err
+		}
+		i.Scheduled = ScheduledMove{}
+	}
+	if bytes.Equal(m.To[:], c.Sender[:]) {
+		return c.State.PutSpaceInfo([]byte(m.Space), i, i.Expiry)
+	}
+	if m.Activation > c.BlockTime {
+		i.Scheduled = ScheduledMove{To: m.To, Activation: m.Activation}
+		if err := c.State.PutScheduledMove([]byte(m.Space), i.RawSpace, m.Activation); err != nil {
+			return err
+		}
+		return c.State.PutSpaceInfo([]byte(m.Space), i, i.Expiry)
+	}
 	i.Owner = m.To
 
 	// Update space
-	if err := MoveSpaceInfo(c.Database, c.Sender, []byte(m.Space), i); err != nil {
+	if err := c.State.MoveSpaceInfo(c.Sender, []byte(m.Space), i); err != nil {
 		return err
 	}
 	return nil
@@ -61,26 +90,29 @@ func (m *MoveTx) Copy() UnsignedTransaction {
 	to := make([]byte, common.AddressLength)
 	copy(to, m.To[:])
 	return &MoveTx{
-		BaseTx: m.BaseTx.Copy(),
-		Space:  m.Space,
-		To:     common.BytesToAddress(to),
+		BaseTx:     m.BaseTx.Copy(),
+		Space:      m.Space,
+		To:         common.BytesToAddress(to),
+		Activation: m.Activation,
 	}
 }
 
 func (m *MoveTx) TypedData() *tdata.TypedData {
 	return tdata.CreateTypedData(
-		m.Magic, Move,
+		m.Magic, m.typedChainID(), Move,
 		[]tdata.Type{
 			{Name: tdSpace, Type: tdString},
 			{Name: tdTo, Type: tdAddress},
+			{Name: tdActivation, Type: tdUint64},
 			{Name: tdPrice, Type: tdUint64},
 			{Name: tdBlockID, Type: tdString},
 		},
 		tdata.TypedDataMessage{
-			tdSpace:   m.Space,
-			tdTo:      m.To.Hex(),
-			tdPrice:   strconv.FormatUint(m.Price, 10),
-			tdBlockID: m.BlockID.String(),
+			tdSpace:      m.Space,
+			tdTo:         m.To.Hex(),
+			tdActivation: strconv.FormatUint(m.Activation, 10),
+			tdPrice:      strconv.FormatUint(m.Price, 10),
+			tdBlockID:    m.BlockID.String(),
 		},
 	)
 }
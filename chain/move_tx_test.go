@@ -96,7 +96,7 @@ func TestMoveTx(t *testing.T) {
 	for i, tv := range tt {
 		tc := &TransactionContext{
 			Genesis:   g,
-			Database:  db,
+			State:     NewStateDB(db),
 			BlockTime: tv.blockTime,
 			TxID:      ids.Empty,
 			Sender:    tv.sender,
@@ -107,3 +107,102 @@ func TestMoveTx(t *testing.T) {
 		}
 	}
 }
+
+func TestMoveTxScheduled(t *testing.T) {
+	t.Parallel()
+
+	owner := common.Address{0x1}
+	heir := common.Address{0x2}
+	other := common.Address{0x3}
+	db := memdb.New()
+	defer db.Close()
+
+	g := DefaultGenesis()
+	tt := []struct {
+		utx       UnsignedTransaction
+		blockTime uint64
+		sender    common.Address
+		err       error
+	}{
+		{
+			utx:       &ClaimTx{BaseTx: &BaseTx{}, Space: "foo"},
+			blockTime: 1,
+			sender:    owner,
+		},
+		{ // nothing to cancel
+			utx:       &MoveTx{BaseTx: &BaseTx{}, Space: "foo", To: owner},
+			blockTime: 1,
+			sender:    owner,
+			err:       ErrNonActionable,
+		},
+		{
+			utx:       &MoveTx{BaseTx: &BaseTx{}, Space: "foo", To: other, Activation: 10},
+			blockTime: 1,
+			sender:    owner,
+		},
+		{ // cancel
+			utx:       &MoveTx{BaseTx: &BaseTx{}, Space: "foo", To: owner},
+			blockTime: 2,
+			sender:    owner,
+		},
+		{
+			utx:       &MoveTx{BaseTx: &BaseTx{}, Space: "foo", To: owner},
+			blockTime: 2,
+			sender:    owner,
+			err:       ErrNonActionable,
+		},
+		{
+			utx:       &MoveTx{BaseTx: &BaseTx{}, Space: "foo", To: other, Activation: 10},
+			blockTime: 2,
+			sender:    owner,
+		},
+		{ // replace
+			utx:       &MoveTx{BaseTx: &BaseTx{}, Space: "foo", To: heir, Activation: 20},
+			blockTime: 3,
+			sender:    owner,
+		},
+		{ // only the owner may schedule moves
+			utx:       &MoveTx{BaseTx: &BaseTx{}, Space: "foo", To: other, Activation: 20},
+			blockTime: 3,
+			sender:    heir,
+			err:       ErrUnauthorized,
+		},
+	}
+	for i, tv := range tt {
+		tc := &TransactionContext{Genesis: g, State: NewStateDB(db), BlockTime: tv.blockTime, Sender: tv.sender}
+		if err := tv.utx.Execute(tc); !errors.Is(err, tv.err) {
+			t.Fatalf("#%d: tx.Execute err expected %v, got %v", i, tv.err, err)
+		}
+	}
+
+	for _, tv := range []struct {
+		now       int64
+		owner     common.Address
+		scheduled ScheduledMove
+	}{
+		// the canceled and replaced moves never activate
+		{now: 19, owner: owner, scheduled: ScheduledMove{To: heir, Activation: 20}},
+		{now: 20, owner: heir},
+	} {
+		if err := ActivateMoves(db, tv.now); err != nil {
+			t.Fatal(err)
+		}
+		i, _, err := GetSpaceInfo(db, []byte("foo"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i.Owner != tv.owner || i.Scheduled != tv.scheduled {
+			t.Fatalf("%d: expected owner %s and scheduled move %+v, got %s and %+v", tv.now, tv.owner, tv.scheduled, i.Owner, i.Scheduled)
+		}
+		owned, err := GetAllOwned(db, tv.owner)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(owned) != 1 || owned[0] != "foo" {
+			t.Fatalf("%d: expected %s to own foo, got %v", tv.now, tv.owner, owned)
+		}
+	}
+	if owned, err := GetAllOwned(db, owner); err != nil || len(owned) != 0 {
+		t.Fatalf("expected previous owner to own nothing, got %v (err=%v)", owned, err)
+	}
+}
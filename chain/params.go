@@ -0,0 +1,62 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+// Params is the rule set a node runs: the constants compiled into this
+// package and the fee mechanism of its genesis. Nodes log it at startup and
+// serve it over RPC, so operators can tell whether two nodes follow the same
+// rules.
+type Params struct {
+	CodecVersion         uint16 `serialize:"true" json:"codecVersion"`
+	MinBlockCost         uint64 `serialize:"true" json:"minBlockCost"`
+	ExpiryBudget         int    `serialize:"true" json:"expiryBudget"`
+	LotteryRewardDivisor uint64 `serialize:"true" json:"lotteryRewardDivisor"`
+	BeneficiaryDivisor   uint64 `serialize:"true" json:"beneficiaryDivisor"`
+	MaxIdentifierSize    int    `serialize:"true" json:"maxIdentifierSize"`
+
+	MinPrice        uint64 `serialize:"true" json:"minPrice"`
+	LookbackWindow  int64  `serialize:"true" json:"lookbackWindow"`
+	TargetBlockRate int64  `serialize:"true" json:"targetBlockRate"`
+	TargetBlockSize uint64 `serialize:"true" json:"targetBlockSize"`
+	MaxBlockSize    uint64 `serialize:"true" json:"maxBlockSize"`
+	// TargetRangeUnits are the units expected to be processed over the
+	// [LookbackWindow] at the target block rate and size
+	TargetRangeUnits uint64 `serialize:"true" json:"targetRangeUnits"`
+}
+
+// Params returns the rule set of [g]. Assumes [g] has been verified.
+func (g *Genesis) Params() *Params {
+	return &Params{
+		CodecVersion:         CodecVersion,
+		MinBlockCost:         MinBlockCost,
+		ExpiryBudget:         ExpiryBudget,
+		LotteryRewardDivisor: LotteryRewardDivisor,
+		BeneficiaryDivisor:   BeneficiaryDivisor,
+		MaxIdentifierSize:    parser.MaxIdentifierSize,
+
+		MinPrice:         g.MinPrice,
+		LookbackWindow:   g.LookbackWindow,
+		TargetBlockRate:  g.TargetBlockRate,
+		TargetBlockSize:  g.TargetBlockSize,
+		MaxBlockSize:     g.MaxBlockSize,
+		TargetRangeUnits: g.TargetBlockSize / uint64(g.TargetBlockRate) * uint64(g.LookbackWindow),
+	}
+}
+
+func (p *Params) String() string {
+	return fmt.Sprintf(
+		"codecVersion=%d minBlockCost=%d expiryBudget=%d lotteryRewardDivisor=%d beneficiaryDivisor=%d "+
+			"maxIdentifierSize=%d minPrice=%d lookbackWindow=%d targetBlockRate=%d targetBlockSize=%d "+
+			"maxBlockSize=%d targetRangeUnits=%d",
+		p.CodecVersion, p.MinBlockCost, p.ExpiryBudget, p.LotteryRewardDivisor, p.BeneficiaryDivisor,
+		p.MaxIdentifierSize, p.MinPrice, p.LookbackWindow, p.TargetBlockRate, p.TargetBlockSize,
+		p.MaxBlockSize, p.TargetRangeUnits,
+	)
+}
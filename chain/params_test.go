@@ -0,0 +1,29 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenesisParams(t *testing.T) {
+	t.Parallel()
+
+	g := DefaultGenesis()
+	g.TargetBlockRate = 2
+	p := g.Params()
+	if p.CodecVersion != CodecVersion || p.ExpiryBudget != ExpiryBudget || p.MinPrice != g.MinPrice {
+		t.Fatalf("unexpected params %+v", p)
+	}
+	expected := g.TargetBlockSize / 2 * uint64(g.LookbackWindow)
+	if p.TargetRangeUnits != expected {
+		t.Fatalf("expected target range units %d, got %d", expected, p.TargetRangeUnits)
+	}
+	for _, s := range []string{"minBlockCost=0", "targetBlockRate=2", "lookbackWindow=60"} {
+		if !strings.Contains(p.String(), s) {
+			t.Fatalf("expected %q in %q", s, p.String())
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// PolicyOps are the operations a [SpacePolicy] governs. [Policy] is the
+// replacement of the policy itself, so a policy that does not allow it can
+// never be changed.
+var PolicyOps = []string{Set, Delete, Move, Burn, Policy, Freeze}
+
+// SpacePolicy is a declarative set of rules the owner of a space registers
+// with a [PolicyTx]. Every rule restricts the owner (lifelines may still be
+// issued by anyone), and the policy moves with the space. The zero value
+// allows everything.
+type SpacePolicy struct {
+	// Ops the owner may perform (empty allows all [PolicyOps])
+	Ops []string `serialize:"true" json:"ops,omitempty"`
+	// MaxValueSize is the largest value the owner may set (0 uses the
+	// genesis limit)
+	MaxValueSize uint64 `serialize:"true" json:"maxValueSize,omitempty"`
+	// LockedUntil is the unix time before which the owner may not perform
+	// any op
+	LockedUntil uint64 `serialize:"true" json:"lockedUntil,omitempty"`
+	// CoSigner (if not empty) must approve every op with an [ApproveTx]
+	// before it is issued
+	CoSigner common.Address `serialize:"true" json:"coSigner"`
+}
+
+func (p *SpacePolicy) Verify() error {
+	seen := map[string]struct{}{}
+	for _, op := range p.Ops {
+		if !p.known(op) {
+			return fmt.Errorf("%w: unknown op %q", ErrInvalidPolicy, op)
+		}
+		if _, ok := seen[op]; ok {
+			return fmt.Errorf("%w: duplicate op %q", ErrInvalidPolicy, op)
+		}
+		seen[op] = struct{}{}
+	}
+	return nil
+}
+
+func (p *SpacePolicy) known(op string) bool {
+	for _, known := range PolicyOps {
+		if op == known {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *SpacePolicy) allows(op string) bool {
+	if len(p.Ops) == 0 {
+		return true
+	}
+	for _, allowed := range p.Ops {
+		if op == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *SpacePolicy) Copy() SpacePolicy {
+	var ops []string
+	if len(p.Ops) > 0 {
+		ops = make([]string, len(p.Ops))
+		copy(ops, p.Ops)
+	}
+	return SpacePolicy{
+		Ops:          ops,
+		MaxValueSize: p.MaxValueSize,
+		LockedUntil:  p.LockedUntil,
+		CoSigner:     p.CoSigner,
+	}
+}
+
+// check returns an error if [p] does not allow [utx] to perform [op] with a
+// value of [size] bytes. A co-signer approval of [utx] is consumed.
+func (p *SpacePolicy) check(t *TransactionContext, op string, utx UnsignedTransaction, size uint64) error {
+	if !p.allows(op) {
+		return fmt.Errorf("%w: %s is not allowed", ErrPolicyDenied, op)
+	}
+	if p.MaxValueSize > 0 && size > p.MaxValueSize {
+		return fmt.Errorf("%w: value size %d > %d", ErrPolicyDenied, size, p.MaxValueSize)
+	}
+	if t.BlockTime < p.LockedUntil {
+		return fmt.Errorf("%w: until %d", ErrSpaceLocked, p.LockedUntil)
+	}
+	if p.CoSigner == zeroAddress {
+		return nil
+	}
+	action, err := ActionHash(utx)
+	if err != nil {
+		return err
+	}
+	approved, err := t.State.HasApproval(action, p.CoSigner)
+	if err != nil {
+		return err
+	}
+	if !approved {
+		return fmt.Errorf("%w: %s", ErrApprovalMissing, action)
+	}
+	return t.State.DeleteApproval(action, p.CoSigner)
+}
+
+// ActionHash is the hash a co-signer approves to allow [utx]. It is the
+// digest of [utx] without its block ID and price, so the approval remains
+// valid however long the owner waits to issue it.
+func ActionHash(utx UnsignedTransaction) (common.Hash, error) {
+	cp := utx.Copy()
+	cp.SetBlockID(ids.Empty)
+	cp.SetPrice(0)
+	dh, err := DigestHash(cp)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return common.BytesToHash(dh), nil
+}
@@ -0,0 +1,87 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/ava-labs/spacesvm/parser"
+	"github.com/ava-labs/spacesvm/tdata"
+)
+
+var _ UnsignedTransaction = &PolicyTx{}
+
+type PolicyTx struct {
+	*BaseTx `serialize:"true" json:"baseTx"`
+
+	// Space is the namespace for the "SpaceInfo"
+	// whose owner can write and read value for the
+	// specific key space.
+	// The space must be ^[a-z0-9]{1,256}$.
+	Space string `serialize:"true" json:"space"`
+
+	// Policy replaces the policy of [Space]. Replacing a policy is itself
+	// subject to the current policy.
+	Policy SpacePolicy `serialize:"true" json:"policy"`
+}
+
+func (p *PolicyTx) Execute(t *TransactionContext) error {
+	if err := parser.CheckContents(p.Space); err != nil {
+		return err
+	}
+	if err := p.Policy.Verify(); err != nil {
+		return err
+	}
+
+	// Verify space is owned by sender
+	i, err := verifySpace(p.Space, t)
+	if err != nil {
+		return err
+	}
+	if err := i.Policy.check(t, Policy, p, 0); err != nil {
+		return err
+	}
+	i.Policy = p.Policy.Copy()
+	return t.State.PutSpaceInfo([]byte(p.Space), i, i.Expiry)
+}
+
+func (p *PolicyTx) Copy() UnsignedTransaction {
+	return &PolicyTx{
+		BaseTx: p.BaseTx.Copy(),
+		Space:  p.Space,
+		Policy: p.Policy.Copy(),
+	}
+}
+
+func (p *PolicyTx) TypedData() *tdata.TypedData {
+	return tdata.CreateTypedData(
+		p.Magic, p.typedChainID(), Policy,
+		[]tdata.Type{
+			{Name: tdSpace, Type: tdString},
+			{Name: tdOps, Type: tdString},
+			{Name: tdMaxValueSize, Type: tdUint64},
+			{Name: tdLockedUntil, Type: tdUint64},
+			{Name: tdCoSigner, Type: tdAddress},
+			{Name: tdPrice, Type: tdUint64},
+			{Name: tdBlockID, Type: tdString},
+		},
+		tdata.TypedDataMessage{
+			tdSpace:        p.Space,
+			tdOps:          strings.Join(p.Policy.Ops, ","),
+			tdMaxValueSize: strconv.FormatUint(p.Policy.MaxValueSize, 10),
+			tdLockedUntil:  strconv.FormatUint(p.Policy.LockedUntil, 10),
+			tdCoSigner:     p.Policy.CoSigner.Hex(),
+			tdPrice:        strconv.FormatUint(p.Price, 10),
+			tdBlockID:      p.BlockID.String(),
+		},
+	)
+}
+
+func (p *PolicyTx) Activity() *Activity {
+	return &Activity{
+		Typ:   Policy,
+		Space: p.Space,
+	}
+}
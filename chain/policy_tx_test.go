@@ -0,0 +1,194 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestPolicyTx(t *testing.T) {
+	t.Parallel()
+
+	owner := common.Address{0x1}
+	coSigner := common.Address{0x2}
+	db := memdb.New()
+	defer db.Close()
+
+	g := DefaultGenesis()
+	set := &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: []byte("abc")}
+	policy := SpacePolicy{Ops: []string{Set, Policy}, MaxValueSize: 4, CoSigner: coSigner}
+	lock := &PolicyTx{BaseTx: &BaseTx{}, Space: "foo", Policy: policy.Copy()}
+	lock.Policy.LockedUntil = 100
+	approve := func(utx UnsignedTransaction) UnsignedTransaction {
+		action, err := ActionHash(utx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &ApproveTx{BaseTx: &BaseTx{}, Action: action}
+	}
+	tt := []struct {
+		utx       UnsignedTransaction
+		blockTime uint64
+		sender    common.Address
+		err       error
+	}{
+		{
+			utx:       &ClaimTx{BaseTx: &BaseTx{}, Space: "foo"},
+			blockTime: 1,
+			sender:    owner,
+		},
+		{
+			utx:       &PolicyTx{BaseTx: &BaseTx{}, Space: "foo", Policy: policy},
+			blockTime: 1,
+			sender:    coSigner,
+			err:       ErrUnauthorized,
+		},
+		{
+			utx:       &PolicyTx{BaseTx: &BaseTx{}, Space: "foo", Policy: SpacePolicy{Ops: []string{Claim}}},
+			blockTime: 1,
+			sender:    owner,
+			err:       ErrInvalidPolicy,
+		},
+		{
+			utx:       &PolicyTx{BaseTx: &BaseTx{}, Space: "foo", Policy: SpacePolicy{Ops: []string{Set, Set}}},
+			blockTime: 1,
+			sender:    owner,
+			err:       ErrInvalidPolicy,
+		},
+		{
+			utx:       &PolicyTx{BaseTx: &BaseTx{}, Space: "foo", Policy: policy},
+			blockTime: 1,
+			sender:    owner,
+		},
+		{ // op is not allowed
+			utx:       &DeleteTx{BaseTx: &BaseTx{}, Space: "foo", Key: "bar"},
+			blockTime: 2,
+			sender:    owner,
+			err:       ErrPolicyDenied,
+		},
+		{ // value is too big
+			utx:       &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: []byte("abcde")},
+			blockTime: 2,
+			sender:    owner,
+			err:       ErrPolicyDenied,
+		},
+		{
+			utx:       set,
+			blockTime: 2,
+			sender:    owner,
+			err:       ErrApprovalMissing,
+		},
+		{ // only approvals of the co-signer are honored
+			utx:       approve(set),
+			blockTime: 2,
+			sender:    owner,
+		},
+		{
+			utx:       set,
+			blockTime: 2,
+			sender:    owner,
+			err:       ErrApprovalMissing,
+		},
+		{
+			utx:       approve(set),
+			blockTime: 2,
+			sender:    coSigner,
+		},
+		{ // approvals do not depend on the block ID or price
+			utx:       &SetTx{BaseTx: &BaseTx{BlockID: ids.GenerateTestID(), Price: 10}, Space: "foo", Key: "bar", Value: []byte("abc")},
+			blockTime: 3,
+			sender:    owner,
+		},
+		{ // approvals are consumed
+			utx:       set,
+			blockTime: 3,
+			sender:    owner,
+			err:       ErrApprovalMissing,
+		},
+		{ // replacing the policy is subject to it
+			utx:       lock,
+			blockTime: 3,
+			sender:    owner,
+			err:       ErrApprovalMissing,
+		},
+		{
+			utx:       approve(lock),
+			blockTime: 3,
+			sender:    coSigner,
+		},
+		{
+			utx:       lock,
+			blockTime: 3,
+			sender:    owner,
+		},
+		{
+			utx:       approve(set),
+			blockTime: 4,
+			sender:    coSigner,
+		},
+		{
+			utx:       set,
+			blockTime: 99,
+			sender:    owner,
+			err:       ErrSpaceLocked,
+		},
+		{
+			utx:       set,
+			blockTime: 100,
+			sender:    owner,
+		},
+		{ // anyone may issue a lifeline
+			utx:       &LifelineTx{BaseTx: &BaseTx{}, Space: "foo", Units: 1},
+			blockTime: 100,
+			sender:    coSigner,
+		},
+	}
+	for i, tv := range tt {
+		tc := &TransactionContext{Genesis: g, State: NewStateDB(db), BlockTime: tv.blockTime, Sender: tv.sender}
+		if err := tv.utx.Execute(tc); !errors.Is(err, tv.err) {
+			t.Fatalf("#%d: tx.Execute err expected %v, got %v", i, tv.err, err)
+		}
+	}
+	i, _, err := GetSpaceInfo(db, []byte("foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(i.Policy, lock.Policy) {
+		t.Fatalf("expected policy %+v, got %+v", lock.Policy, i.Policy)
+	}
+}
+
+func TestPolicyTxTypedData(t *testing.T) {
+	t.Parallel()
+
+	policy := &PolicyTx{
+		BaseTx: &BaseTx{ChainID: ids.ID{1}},
+		Space:  "foo",
+		Policy: SpacePolicy{
+			Ops:          []string{Set, Delete},
+			MaxValueSize: 10,
+			LockedUntil:  20,
+			CoSigner:     common.Address{0x1},
+		},
+	}
+	approve := &ApproveTx{BaseTx: &BaseTx{ChainID: ids.ID{1}}, Action: common.Hash{0x2}}
+	for _, utx := range []UnsignedTransaction{policy, approve, &PolicyTx{BaseTx: &BaseTx{}, Space: "foo"}} {
+		if _, err := DigestHash(utx); err != nil {
+			t.Fatal(err)
+		}
+		parsed, err := ParseTypedData(utx.TypedData())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(parsed, utx) {
+			t.Fatalf("expected %+v, got %+v", utx, parsed)
+		}
+	}
+}
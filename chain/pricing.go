@@ -0,0 +1,76 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+// Units measure the fees and load of transactions ("fee units" and "load
+// units") and the storage held by a space ("expiry units"). Fees are the
+// number of fee units multiplied by the price.
+type Units = uint64
+
+// ClaimUnits requires the caller to pay more to get spaces of a shorter
+// length because they are more desirable. This creates a "lottery" mechanism
+// where the people that spend the most mining power will win the space.
+//
+// ClaimUnits should only be called on a space that is valid.
+func ClaimUnits(g *Genesis, space string) Units {
+	desirability := uint64(parser.MaxIdentifierSize - len(space))
+	desirability *= g.SpaceDesirabilityMultiplier
+	if desirability < g.MinClaimFee {
+		return g.MinClaimFee
+	}
+	return desirability
+}
+
+// BeneficiaryShare is the amount of the fee paid to claim [space] at [price]
+// credited to the beneficiary of the claim. It is only taken from the
+// [ClaimUnits], so it never exceeds what the claimer paid.
+func BeneficiaryShare(g *Genesis, space string, price uint64) uint64 {
+	return ClaimUnits(g, space) * price * g.ClaimBeneficiaryShare / BeneficiaryDivisor
+}
+
+// RenewalUnits are the fee units charged for each unit of lifeline given to
+// [space]. They are discounted so that, all else equal, it is easier for an
+// owner to retain their space than for another to claim it.
+func RenewalUnits(g *Genesis, space string) Units {
+	return ClaimUnits(g, space) / g.RenewalDiscount(space)
+}
+
+// ValueUnits are the fee units charged to store a value of [size] bytes.
+//
+// We don't subtract by 1 here because we want to charge extra for any
+// value-based interaction (even if it is small or a delete).
+func ValueUnits(g *Genesis, size uint64) Units {
+	return size/g.ValueUnitSize + 1
+}
+
+// StorageUnits are the expiry units a value of [size] bytes adds to the space
+// storing it. The more expiry units a space has, the faster it expires.
+func StorageUnits(g *Genesis, size uint64) Units {
+	return ValueUnits(g, size) / g.ValueExpiryDiscount
+}
+
+// RequiredLifelineUnits is the number of lifeline units needed to extend the
+// life of a space with [horizon] and [expiryUnits] by [extension] seconds.
+func RequiredLifelineUnits(horizon uint64, expiryUnits Units, extension uint64) Units {
+	return (extension*expiryUnits + horizon - 1) / horizon
+}
+
+// LifelineCredit is the number of seconds [units] of lifeline (each worth
+// [horizon]) extend the life of a space with [expiryUnits].
+func LifelineCredit(horizon uint64, expiryUnits Units, units Units) uint64 {
+	return (horizon * units) / expiryUnits
+}
+
+// ClaimHorizon is the horizon of a space claimed with [lease] standard leases
+// (0 buys one).
+func ClaimHorizon(g *Genesis, lease uint64) uint64 {
+	if lease == 0 {
+		lease = 1
+	}
+	return g.ClaimReward * lease
+}
@@ -0,0 +1,72 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+func TestPricing(t *testing.T) {
+	t.Parallel()
+
+	g := DefaultGenesis()
+	long := strings.Repeat("a", parser.MaxIdentifierSize-1)
+	if u := ClaimUnits(g, long); u != g.MinClaimFee {
+		t.Fatalf("expected long spaces to cost the min claim fee %d, got %d", g.MinClaimFee, u)
+	}
+	short := ClaimUnits(g, "a")
+	if short != uint64(parser.MaxIdentifierSize-1)*g.SpaceDesirabilityMultiplier {
+		t.Fatalf("unexpected claim units %d", short)
+	}
+	if u := RenewalUnits(g, "a"); u != short/g.SpaceRenewalDiscount {
+		t.Fatalf("expected renewals to be discounted, got %d", u)
+	}
+
+	// The first tier containing the space applies
+	tiered := DefaultGenesis()
+	tiered.SpaceRenewalTiers = []*RenewalTier{
+		{MaxLength: 4, Discount: 2},
+		{MaxLength: 8, Discount: 5},
+	}
+	for space, discount := range map[string]uint64{
+		"a":         2,
+		"abcd":      2,
+		"abcde":     5,
+		"abcdefgh":  5,
+		"abcdefghi": tiered.SpaceRenewalDiscount,
+	} {
+		if u := RenewalUnits(tiered, space); u != ClaimUnits(tiered, space)/discount {
+			t.Fatalf("%s: expected discount %d, got %d units", space, discount, u)
+		}
+	}
+
+	tt := []struct {
+		size    uint64
+		value   Units
+		storage Units
+	}{
+		{size: 1, value: 1, storage: 0},
+		{size: g.ValueUnitSize, value: 2, storage: 0},
+		{size: 10 * g.ValueUnitSize, value: 11, storage: 1},
+	}
+	for i, tv := range tt {
+		if u := ValueUnits(g, tv.size); u != tv.value {
+			t.Fatalf("#%d: expected %d value units, got %d", i, tv.value, u)
+		}
+		if u := StorageUnits(g, tv.size); u != tv.storage {
+			t.Fatalf("#%d: expected %d storage units, got %d", i, tv.storage, u)
+		}
+	}
+
+	// Lifeline is spread across all expiry units of a space
+	if c := LifelineCredit(g.ClaimReward, g.ClaimExpiryUnits, 2); c != 2*g.ClaimReward/g.ClaimExpiryUnits {
+		t.Fatalf("unexpected lifeline credit %d", c)
+	}
+	if LifelineCredit(g.ClaimReward, 2*g.ClaimExpiryUnits, 1) >= LifelineCredit(g.ClaimReward, g.ClaimExpiryUnits, 1) {
+		t.Fatal("spaces storing more should be extended less")
+	}
+}
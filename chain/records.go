@@ -0,0 +1,118 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+// Value kinds tell readers how to interpret a value. Every kind other than
+// [ValueRaw] is validated when it is set.
+const (
+	// ValueRaw values are stored as-is
+	ValueRaw = ""
+	// ValueIPFS values are IPFS CIDs pointing to content stored off-chain
+	ValueIPFS = "ipfs"
+	// ValueA values are IPv4 or IPv6 addresses
+	ValueA = "a"
+	// ValueTXT values are UTF-8 text
+	ValueTXT = "txt"
+	// ValueRedirect values are another space/key path (an alias) or an
+	// http(s) URL
+	ValueRedirect = "redirect"
+	// ValueContentHash values are "<protocol>://<hash>" references to
+	// content on a decentralized storage network (ipfs, ipns, or bzz)
+	ValueContentHash = "contenthash"
+)
+
+// Content hash protocols
+const (
+	ProtocolIPFS  = "ipfs"
+	ProtocolIPNS  = "ipns"
+	ProtocolSwarm = "bzz"
+
+	swarmHashLen = 32
+)
+
+// ValidateValue returns an error if [value] does not match the schema of
+// [kind]
+func ValidateValue(kind string, value []byte) error {
+	switch kind {
+	case ValueRaw:
+		return nil
+	case ValueIPFS:
+		_, err := parser.ParseCID(string(value))
+		return err
+	case ValueA:
+		if net.ParseIP(string(value)) == nil {
+			return fmt.Errorf("%w: %q is not an IP address", ErrInvalidRecord, value)
+		}
+		return nil
+	case ValueTXT:
+		if !utf8.Valid(value) {
+			return fmt.Errorf("%w: text is not valid UTF-8", ErrInvalidRecord)
+		}
+		return nil
+	case ValueRedirect:
+		_, _, err := ParseRedirect(value)
+		return err
+	case ValueContentHash:
+		_, _, err := ParseContentHash(value)
+		return err
+	default:
+		return fmt.Errorf("%w: %q", ErrInvalidKind, kind)
+	}
+}
+
+// ParseRedirect returns the space/key path or the http(s) URL (only one is
+// set) that the redirect record [value] points to
+func ParseRedirect(value []byte) (string, *url.URL, error) {
+	s := string(value)
+	if strings.Contains(s, "://") {
+		u, err := url.Parse(s)
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: %v", ErrInvalidRecord, err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+			return "", nil, fmt.Errorf("%w: %q is not an http(s) URL", ErrInvalidRecord, s)
+		}
+		return "", u, nil
+	}
+	if _, _, err := parser.ResolvePath(s); err != nil {
+		return "", nil, fmt.Errorf("%w: %v", ErrInvalidRecord, err)
+	}
+	return s, nil, nil
+}
+
+// ParseContentHash returns the protocol and hash of the content hash record
+// [value]
+func ParseContentHash(value []byte) (string, string, error) {
+	s := string(value)
+	i := strings.Index(s, "://")
+	if i < 0 {
+		return "", "", fmt.Errorf("%w: %q is not of the form <protocol>://<hash>", ErrInvalidRecord, s)
+	}
+	protocol, hash := s[:i], s[i+3:]
+	switch protocol {
+	case ProtocolIPFS, ProtocolIPNS:
+		if _, err := parser.ParseCID(hash); err != nil {
+			return "", "", err
+		}
+	case ProtocolSwarm:
+		b, err := hex.DecodeString(hash)
+		if err != nil || len(b) != swarmHashLen {
+			return "", "", fmt.Errorf("%w: swarm hash must be %d hex-encoded bytes", ErrInvalidRecord, swarmHashLen)
+		}
+	default:
+		return "", "", fmt.Errorf("%w: unsupported protocol %q", ErrInvalidRecord, protocol)
+	}
+	return protocol, hash, nil
+}
@@ -0,0 +1,51 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+func TestValidateValue(t *testing.T) {
+	t.Parallel()
+
+	const cid = "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG"
+	tt := []struct {
+		kind  string
+		value string
+		err   error
+	}{
+		{kind: ValueRaw, value: "\xff anything"},
+		{kind: ValueIPFS, value: cid},
+		{kind: ValueIPFS, value: "ipfs://" + cid, err: parser.ErrInvalidCID},
+		{kind: ValueA, value: "192.0.2.1"},
+		{kind: ValueA, value: "2001:db8::1"},
+		{kind: ValueA, value: "192.0.2.256", err: ErrInvalidRecord},
+		{kind: ValueA, value: " 192.0.2.1", err: ErrInvalidRecord},
+		{kind: ValueTXT, value: "v=spf1 -all"},
+		{kind: ValueTXT, value: "\xff", err: ErrInvalidRecord},
+		{kind: ValueRedirect, value: "other/key"},
+		{kind: ValueRedirect, value: "https://example.com/page"},
+		{kind: ValueRedirect, value: "ftp://example.com", err: ErrInvalidRecord},
+		{kind: ValueRedirect, value: "other", err: ErrInvalidRecord},
+		{kind: ValueRedirect, value: "Other/Key", err: ErrInvalidRecord},
+		{kind: ValueContentHash, value: "ipfs://" + cid},
+		{kind: ValueContentHash, value: "ipns://" + cid},
+		{kind: ValueContentHash, value: "bzz://" + strings.Repeat("ab", swarmHashLen)},
+		{kind: ValueContentHash, value: "bzz://abab", err: ErrInvalidRecord},
+		{kind: ValueContentHash, value: "ipfs://notacid", err: parser.ErrInvalidCID},
+		{kind: ValueContentHash, value: "http://" + cid, err: ErrInvalidRecord},
+		{kind: ValueContentHash, value: cid, err: ErrInvalidRecord},
+		{kind: "mx", value: "mail", err: ErrInvalidKind},
+	}
+	for i, tv := range tt {
+		if err := ValidateValue(tv.kind, []byte(tv.value)); !errors.Is(err, tv.err) {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.err, err)
+		}
+	}
+}
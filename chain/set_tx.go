@@ -34,21 +34,34 @@ type SetTx struct {
 	// Value is written as the key-value pair to the storage. If a previous value
 	// exists, it is overwritten.
 	Value []byte `serialize:"true" json:"value"`
+
+	// Broadcast emits a [WarpMessage] committing to the value so other
+	// subnets can consume it.
+	Broadcast bool `serialize:"true" json:"broadcast"`
+
+	// Kind is how [Value] should be interpreted (see [ValidateValue]).
+	Kind string `serialize:"true" json:"kind"`
+
+	// overQuota is set by [Execute] when [Value] is small enough to be
+	// written for free but the free write quota of [Space] is used up, so
+	// [FeeUnits] charges its value units.
+	overQuota bool
 }
 
 func (s *SetTx) Execute(t *TransactionContext) error {
 	g := t.Genesis
+	s.overQuota = false
 	if err := parser.CheckContents(s.Space); err != nil {
 		return err
 	}
 	if err := parser.CheckContents(s.Key); err != nil {
 		return err
 	}
-	switch {
-	case len(s.Value) == 0:
-		return ErrValueEmpty
-	case uint64(len(s.Value)) > g.MaxValueSize:
-		return ErrValueTooBig
+	if err := g.CheckValue(s.Key, uint64(len(s.Value))); err != nil {
+		return err
+	}
+	if err := ValidateValue(s.Kind, s.Value); err != nil {
+		return err
 	}
 
 	// Verify space is owned by sender
@@ -56,6 +69,19 @@ func (s *SetTx) Execute(t *TransactionContext) error {
 	if err != nil {
 		return err
 	}
+	if err := i.Policy.check(t, Set, s, uint64(len(s.Value))); err != nil {
+		return err
+	}
+	if err := i.checkFrozen(s.Key, t.BlockTime); err != nil {
+		return err
+	}
+	if g.FreeWrite(uint64(len(s.Value))) {
+		free, err := s.useFreeWrite(t)
+		if err != nil {
+			return err
+		}
+		s.overQuota = !free
+	}
 
 	// If Key is equal to hash length, ensure it is equal to the hash of the
 	// value
@@ -71,63 +97,118 @@ func (s *SetTx) Execute(t *TransactionContext) error {
 	nvmeta := &ValueMeta{
 		Size:    valueSize,
 		TxID:    t.TxID,
+		Kind:    s.Kind,
 		Updated: t.BlockTime,
 	}
-	v, exists, err := GetValueMeta(t.Database, []byte(s.Space), []byte(s.Key))
+	v, exists, err := t.State.GetValueMeta([]byte(s.Space), []byte(s.Key))
 	if err != nil {
 		return err
 	}
 	timeRemaining := (i.Expiry - i.Updated) * i.Units
 	if exists {
-		i.Units -= valueUnits(g, v.Size) / g.ValueExpiryDiscount
+		i.Units -= StorageUnits(g, v.Size)
+		i.Size -= v.Size
 		nvmeta.Created = v.Created
+		if err := t.State.PutTombstone(t.BlockHeight, v.TxID, v.Size); err != nil {
+			return err
+		}
 	} else {
 		nvmeta.Created = t.BlockTime
 	}
-	i.Units += valueUnits(g, valueSize) / g.ValueExpiryDiscount
-	if err := PutSpaceKey(t.Database, []byte(s.Space), []byte(s.Key), nvmeta); err != nil {
+	i.Units += StorageUnits(g, valueSize)
+	i.Size += valueSize
+	if err := g.CheckSpaceSize(i.Size); err != nil {
 		return err
 	}
+	vh := ValueHash(s.Value)
+	if err := t.State.SetValue([]byte(s.Space), []byte(s.Key), nvmeta, vh); err != nil {
+		return err
+	}
+	if s.Broadcast {
+		if err := t.State.PutWarpMessage(t.TxID, &WarpMessage{
+			SourceChainID: s.ChainID,
+			TxID:          t.TxID,
+			Sender:        t.Sender,
+			Space:         s.Space,
+			Key:           s.Key,
+			ValueHash:     vh,
+			Timestamp:     t.BlockTime,
+		}); err != nil {
+			return err
+		}
+	}
 	return updateSpace(s.Space, t, timeRemaining, i)
 }
 
-func (s *SetTx) FeeUnits(g *Genesis) uint64 {
-	// We don't subtract by 1 here because we want to charge extra for any
-	// value-based interaction (even if it is small or a delete).
-	return s.BaseTx.FeeUnits(g) + valueUnits(g, uint64(len(s.Value)))
+// useFreeWrite counts a free write against the quota of its space. It
+// returns false if the quota is used up, in which case the write pays for
+// its value units.
+func (s *SetTx) useFreeWrite(t *TransactionContext) (bool, error) {
+	g := t.Genesis
+	window := t.BlockTime / g.FreeWriteWindow
+	writes, err := t.State.GetFreeWrites([]byte(s.Space), window)
+	if err != nil {
+		return false, err
+	}
+	if writes >= g.FreeWriteQuota {
+		return false, nil
+	}
+	return true, t.State.PutFreeWrites([]byte(s.Space), window, writes+1)
+}
+
+// FeeUnits only charges the value units of free writes once [Execute] finds
+// the free write quota of the space used up.
+func (s *SetTx) FeeUnits(g *Genesis) Units {
+	size := uint64(len(s.Value))
+	if g.FreeWrite(size) && !s.overQuota {
+		return s.BaseTx.FeeUnits(g)
+	}
+	return s.BaseTx.FeeUnits(g) + ValueUnits(g, size)
 }
 
-func (s *SetTx) LoadUnits(g *Genesis) uint64 {
-	return s.FeeUnits(g)
+// LoadUnits does not depend on the free write quota, so blocks are sized the
+// same way before and after their txs execute.
+func (s *SetTx) LoadUnits(g *Genesis) Units {
+	size := uint64(len(s.Value))
+	if g.FreeWrite(size) {
+		return s.BaseTx.LoadUnits(g)
+	}
+	return s.BaseTx.LoadUnits(g) + ValueUnits(g, size)
 }
 
 func (s *SetTx) Copy() UnsignedTransaction {
 	value := make([]byte, len(s.Value))
 	copy(value, s.Value)
 	return &SetTx{
-		BaseTx: s.BaseTx.Copy(),
-		Space:  s.Space,
-		Key:    s.Key,
-		Value:  value,
+		BaseTx:    s.BaseTx.Copy(),
+		Space:     s.Space,
+		Key:       s.Key,
+		Value:     value,
+		Broadcast: s.Broadcast,
+		Kind:      s.Kind,
 	}
 }
 
 func (s *SetTx) TypedData() *tdata.TypedData {
 	return tdata.CreateTypedData(
-		s.Magic, Set,
+		s.Magic, s.typedChainID(), Set,
 		[]tdata.Type{
 			{Name: tdSpace, Type: tdString},
 			{Name: tdKey, Type: tdString},
 			{Name: tdValue, Type: tdBytes},
+			{Name: tdBroadcast, Type: tdBool},
+			{Name: tdKind, Type: tdString},
 			{Name: tdPrice, Type: tdUint64},
 			{Name: tdBlockID, Type: tdString},
 		},
 		tdata.TypedDataMessage{
-			tdSpace:   s.Space,
-			tdKey:     s.Key,
-			tdValue:   hexutil.Encode(s.Value),
-			tdPrice:   strconv.FormatUint(s.Price, 10),
-			tdBlockID: s.BlockID.String(),
+			tdSpace:     s.Space,
+			tdKey:       s.Key,
+			tdValue:     hexutil.Encode(s.Value),
+			tdBroadcast: s.Broadcast,
+			tdKind:      s.Kind,
+			tdPrice:     strconv.FormatUint(s.Price, 10),
+			tdBlockID:   s.BlockID.String(),
 		},
 	)
 }
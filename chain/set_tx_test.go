@@ -116,6 +116,62 @@ func TestSetTx(t *testing.T) {
 			sender:    sender,
 			err:       nil,
 		},
+		{ // write and emit a warp message
+			utx: &SetTx{
+				BaseTx: &BaseTx{
+					BlockID: ids.GenerateTestID(),
+				},
+				Space:     "foo",
+				Key:       "broadcast",
+				Value:     []byte("value"),
+				Broadcast: true,
+			},
+			blockTime: 1,
+			sender:    sender,
+			err:       nil,
+		},
+		{ // write an IPFS CID
+			utx: &SetTx{
+				BaseTx: &BaseTx{
+					BlockID: ids.GenerateTestID(),
+				},
+				Space: "foo",
+				Key:   "cid",
+				Value: []byte("QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG"),
+				Kind:  ValueIPFS,
+			},
+			blockTime: 1,
+			sender:    sender,
+			err:       nil,
+		},
+		{ // write an invalid IPFS CID
+			utx: &SetTx{
+				BaseTx: &BaseTx{
+					BlockID: ids.GenerateTestID(),
+				},
+				Space: "foo",
+				Key:   "cid",
+				Value: []byte("QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbd"),
+				Kind:  ValueIPFS,
+			},
+			blockTime: 1,
+			sender:    sender,
+			err:       parser.ErrInvalidCID,
+		},
+		{ // write an unknown kind
+			utx: &SetTx{
+				BaseTx: &BaseTx{
+					BlockID: ids.GenerateTestID(),
+				},
+				Space: "foo",
+				Key:   "bar",
+				Value: []byte("value"),
+				Kind:  "unknown",
+			},
+			blockTime: 1,
+			sender:    sender,
+			err:       ErrInvalidKind,
+		},
 		{ // write incorrect hashed value
 			utx: &SetTx{
 				BaseTx: &BaseTx{
@@ -228,7 +284,7 @@ func TestSetTx(t *testing.T) {
 	for i, tv := range tt {
 		if i > 0 {
 			// Expire old spaces between txs
-			if err := ExpireNext(db, tt[i-1].blockTime, tv.blockTime, true); err != nil {
+			if err := ExpireNext(db, tt[i-1].blockTime, tv.blockTime, ExpiryBudget); err != nil {
 				t.Fatalf("#%d: ExpireNext errored %v", i, err)
 			}
 		}
@@ -243,7 +299,7 @@ func TestSetTx(t *testing.T) {
 		}
 		tc := &TransactionContext{
 			Genesis:   g,
-			Database:  db,
+			State:     NewStateDB(db),
 			BlockTime: uint64(tv.blockTime),
 			TxID:      id,
 			Sender:    tv.sender,
@@ -281,6 +337,9 @@ func TestSetTx(t *testing.T) {
 				if vmeta.TxID != id {
 					t.Fatalf("#%d: unexpected txID %q, expected %q", i, vmeta.TxID, id)
 				}
+				if vmeta.Kind != tp.Kind {
+					t.Fatalf("#%d: unexpected kind %q, expected %q", i, vmeta.Kind, tp.Kind)
+				}
 			}
 
 			val, exists, err := GetValue(db, []byte(tp.Space), []byte(tp.Key))
@@ -295,6 +354,17 @@ func TestSetTx(t *testing.T) {
 					t.Fatalf("#%d: unexpected value %q, expected %q", i, val, tp.Value)
 				}
 			}
+
+			m, exists, err := GetWarpMessage(db, id)
+			if err != nil {
+				t.Fatalf("#%d: failed to get warp message %v", i, err)
+			}
+			if exists != tp.Broadcast {
+				t.Fatalf("#%d: warp message exists=%t, expected %t", i, exists, tp.Broadcast)
+			}
+			if exists && (m.Key != tp.Key || m.ValueHash != crypto.Keccak256Hash(tp.Value) || m.Sender != tv.sender) {
+				t.Fatalf("#%d: unexpected warp message %+v", i, m)
+			}
 		case *DeleteTx:
 			_, exists, err := GetValue(db, []byte(tp.Space), []byte(tp.Key))
 			if err != nil {
@@ -306,3 +376,152 @@ func TestSetTx(t *testing.T) {
 		}
 	}
 }
+
+func TestSetTxStorageLimits(t *testing.T) {
+	t.Parallel()
+
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := crypto.PubkeyToAddress(priv.PublicKey)
+
+	db := memdb.New()
+	defer db.Close()
+
+	g := DefaultGenesis()
+	g.MaxKeyLength = HashLen
+	g.MaxValueSize = 4
+	g.MaxSpaceSize = 6
+	tt := []struct {
+		utx  UnsignedTransaction
+		size uint64
+		err  error
+	}{
+		{ // claim
+			utx: &ClaimTx{BaseTx: &BaseTx{}, Space: "foo"},
+		},
+		{ // key too long
+			utx: &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: strings.Repeat("a", HashLen+1), Value: []byte("a")},
+			err: ErrKeyTooLong,
+		},
+		{ // value too big
+			utx: &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: []byte("abcde")},
+			err: ErrValueTooBig,
+		},
+		{ // write
+			utx:  &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: []byte("abcd")},
+			size: 4,
+		},
+		{ // exceed space size
+			utx: &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "baz", Value: []byte("abc")},
+			err: ErrSpaceFull,
+		},
+		{ // overwrite only counts the new value
+			utx:  &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: []byte("ab")},
+			size: 2,
+		},
+		{ // fill space
+			utx:  &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "baz", Value: []byte("abcd")},
+			size: 6,
+		},
+		{ // delete frees space
+			utx:  &DeleteTx{BaseTx: &BaseTx{}, Space: "foo", Key: "bar"},
+			size: 4,
+		},
+	}
+	for i, tv := range tt {
+		id := ids.GenerateTestID()
+		if tp, ok := tv.utx.(*SetTx); ok {
+			if err := db.Put(PrefixTxValueKey(id), tp.Value); err != nil {
+				t.Fatal(err)
+			}
+		}
+		err := tv.utx.Execute(&TransactionContext{
+			Genesis:   g,
+			State:     NewStateDB(db),
+			BlockTime: 1,
+			TxID:      id,
+			Sender:    sender,
+		})
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("#%d: tx.Execute err expected %v, got %v", i, tv.err, err)
+		}
+		info, _, err := GetSpaceInfo(db, []byte("foo"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tv.err == nil && info.Size != tv.size {
+			t.Fatalf("#%d: expected space size %d, got %d", i, tv.size, info.Size)
+		}
+	}
+}
+
+func TestSetTxFreeWrites(t *testing.T) {
+	t.Parallel()
+
+	sender := common.Address{0x1}
+	db := memdb.New()
+	defer db.Close()
+
+	g := DefaultGenesis()
+	g.FreeWriteSize = 4
+	g.FreeWriteQuota = 2
+	g.FreeWriteWindow = 10
+	tc := &TransactionContext{Genesis: g, State: NewStateDB(db), BlockTime: 1, Sender: sender, Price: 3}
+	if err := (&ClaimTx{BaseTx: &BaseTx{}, Space: "foo"}).Execute(tc); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ModifyBalance(db, sender, true, 100); err != nil {
+		t.Fatal(err)
+	}
+
+	tiny := &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: []byte("abcd")}
+	large := &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: []byte("abcde")}
+	if tiny.FeeUnits(g) != g.BaseTxUnits {
+		t.Fatalf("expected free write to only cost base units, got %d", tiny.FeeUnits(g))
+	}
+	if large.FeeUnits(g) != g.BaseTxUnits+ValueUnits(g, 5) {
+		t.Fatalf("expected paid write to cost value units, got %d", large.FeeUnits(g))
+	}
+
+	paid := g.BaseTxUnits + ValueUnits(g, 4)
+	tt := []struct {
+		blockTime uint64
+		units     Units
+	}{
+		{blockTime: 1, units: g.BaseTxUnits},
+		{blockTime: 2, units: g.BaseTxUnits},
+		{blockTime: 3, units: paid},           // quota used up
+		{blockTime: 10, units: g.BaseTxUnits}, // next window
+	}
+	for i, tv := range tt {
+		tc.BlockTime = tv.blockTime
+		tc.TxID = ids.GenerateTestID()
+		if err := tiny.Execute(tc); err != nil {
+			t.Fatalf("#%d: %v", i, err)
+		}
+		if units := tiny.FeeUnits(g); units != tv.units {
+			t.Fatalf("#%d: expected %d fee units, got %d", i, tv.units, units)
+		}
+		// The fee is charged by the transaction, not the write
+		if bal, err := GetBalance(db, sender); err != nil || bal != 100 {
+			t.Fatalf("#%d: expected balance 100, got %d (%v)", i, bal, err)
+		}
+	}
+	if writes, err := GetFreeWrites(db, []byte("foo"), 1); err != nil || writes != 1 {
+		t.Fatalf("expected 1 free write in window, got %d (%v)", writes, err)
+	}
+
+	// The quota is dropped with the space
+	info, _, err := GetSpaceInfo(db, []byte("foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := BurnSpace(db, []byte("foo"), info, 10); err != nil {
+		t.Fatal(err)
+	}
+	if has, err := db.Has(PrefixFreeWritesKey([]byte("foo"))); err != nil || has {
+		t.Fatalf("expected free writes of removed space to be deleted (%v)", err)
+	}
+}
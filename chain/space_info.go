@@ -14,6 +14,35 @@ type SpaceInfo struct {
 	Updated uint64         `serialize:"true" json:"updated"`
 	Expiry  uint64         `serialize:"true" json:"expiry"`
 	Units   uint64         `serialize:"true" json:"units"` // decays faster the more units you have
+	Size    uint64         `serialize:"true" json:"size"`  // bytes of values stored
+	// Horizon is the number of seconds one lifeline unit keeps the space
+	// alive with one expiry unit, fixed by the lease bought when it was
+	// claimed (see [ClaimHorizon])
+	Horizon uint64 `serialize:"true" json:"horizon"`
+	// Policy restricts what the owner may do with the space (see [PolicyTx])
+	Policy SpacePolicy `serialize:"true" json:"policy"`
+	// Scheduled is the move that transfers the space once it activates (see
+	// [MoveTx.Activation])
+	Scheduled ScheduledMove `serialize:"true" json:"scheduledMove"`
+	// Frozen are the key prefixes that can't be set or deleted (see
+	// [FreezeTx])
+	Frozen []*FrozenPrefix `serialize:"true" json:"frozen,omitempty"`
 
 	RawSpace ids.ShortID `serialize:"true" json:"rawSpace"`
 }
+
+// ScheduledMove transfers a space to [To] in the first block with a timestamp
+// at or after [Activation]. The zero value is no scheduled move.
+type ScheduledMove struct {
+	To         common.Address `serialize:"true" json:"to"`
+	Activation uint64         `serialize:"true" json:"activation"`
+}
+
+// SpaceHorizon returns the horizon of [i], which is [Genesis.ClaimReward] for
+// spaces without one (such as those allocated at genesis).
+func SpaceHorizon(g *Genesis, i *SpaceInfo) uint64 {
+	if i.Horizon == 0 {
+		return g.ClaimReward
+	}
+	return i.Horizon
+}
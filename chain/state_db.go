@@ -0,0 +1,181 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var _ StateDB = &stateDB{}
+
+// StateDB is the state transactions read and write when they execute. It hides
+// how that state is laid out in the database, so alternative storage layouts
+// and instrumentation wrappers (such as ones that record reads and writes) can
+// be used without changing transaction logic.
+type StateDB interface {
+	HasSpace(space []byte) (bool, error)
+	GetSpaceInfo(space []byte) (*SpaceInfo, bool, error)
+	// PutSpaceInfo writes [i] and moves the expiry of [space] from
+	// [lastExpiry] (0 if it is new) to [i.Expiry]
+	PutSpaceInfo(space []byte, i *SpaceInfo, lastExpiry uint64) error
+	// MoveSpaceInfo writes [i] and moves [space] from [oldOwner] to
+	// [i.Owner] without changing its expiry
+	MoveSpaceInfo(oldOwner common.Address, space []byte, i *SpaceInfo) error
+	// PutScheduledMove queues the move of [space] (with raw space [rspace])
+	// to activate at [activation]
+	PutScheduledMove(space []byte, rspace ids.ShortID, activation uint64) error
+	DeleteScheduledMove(rspace ids.ShortID, activation uint64) error
+	// BurnSpace removes [space] (with info [i]) at [t], as if it expired
+	BurnSpace(space []byte, i *SpaceInfo, t uint64) error
+	// GetBurned returns the end of the claim cooldown of burned [space]
+	GetBurned(space []byte) (uint64, bool, error)
+	PutBurned(space []byte, until uint64) error
+
+	GetValueMeta(space []byte, key []byte) (*ValueMeta, bool, error)
+	// SetValue writes [vmeta] at [key] and indexes the value by [valueHash]
+	SetValue(space []byte, key []byte, vmeta *ValueMeta, valueHash common.Hash) error
+	DeleteValue(space []byte, key []byte) error
+	// PutTombstone records that the value set by [txID] was removed at
+	// [height], so its bytes can be reclaimed
+	PutTombstone(height uint64, txID ids.ID, size uint64) error
+	PutWarpMessage(txID ids.ID, m *WarpMessage) error
+
+	GetBalance(address common.Address) (uint64, error)
+	ModifyBalance(address common.Address, add bool, change uint64) (uint64, error)
+
+	HasImported(utxoID ids.ID) (bool, error)
+	SetImported(utxoID ids.ID) error
+
+	// GetCommitment returns the block timestamp [commitment] was made at
+	GetCommitment(commitment common.Hash) (uint64, bool, error)
+	PutCommitment(commitment common.Hash, t uint64) error
+	DeleteCommitment(commitment common.Hash) error
+
+	// GetFreeWrites returns the free writes [space] made in [window]
+	GetFreeWrites(space []byte, window uint64) (uint64, error)
+	PutFreeWrites(space []byte, window uint64, writes uint64) error
+
+	// HasApproval returns true if [coSigner] approved [action] (see
+	// [ActionHash])
+	HasApproval(action common.Hash, coSigner common.Address) (bool, error)
+	PutApproval(action common.Hash, coSigner common.Address) error
+	DeleteApproval(action common.Hash, coSigner common.Address) error
+}
+
+// stateDB is the [StateDB] of the default layout (see the key prefixes in
+// storage.go)
+type stateDB struct {
+	db database.Database
+}
+
+// NewStateDB returns the [StateDB] stored in [db] with the default layout.
+func NewStateDB(db database.Database) StateDB {
+	return &stateDB{db: db}
+}
+
+func (s *stateDB) HasSpace(space []byte) (bool, error) {
+	return HasSpace(s.db, space)
+}
+
+func (s *stateDB) GetSpaceInfo(space []byte) (*SpaceInfo, bool, error) {
+	return GetSpaceInfo(s.db, space)
+}
+
+func (s *stateDB) PutSpaceInfo(space []byte, i *SpaceInfo, lastExpiry uint64) error {
+	return PutSpaceInfo(s.db, space, i, lastExpiry)
+}
+
+func (s *stateDB) MoveSpaceInfo(oldOwner common.Address, space []byte, i *SpaceInfo) error {
+	return MoveSpaceInfo(s.db, oldOwner, space, i)
+}
+
+func (s *stateDB) PutScheduledMove(space []byte, rspace ids.ShortID, activation uint64) error {
+	return PutScheduledMove(s.db, space, rspace, activation)
+}
+
+func (s *stateDB) DeleteScheduledMove(rspace ids.ShortID, activation uint64) error {
+	return DeleteScheduledMove(s.db, rspace, activation)
+}
+
+func (s *stateDB) BurnSpace(space []byte, i *SpaceInfo, t uint64) error {
+	return BurnSpace(s.db, space, i, t)
+}
+
+func (s *stateDB) GetBurned(space []byte) (uint64, bool, error) {
+	return GetBurned(s.db, space)
+}
+
+func (s *stateDB) PutBurned(space []byte, until uint64) error {
+	return PutBurned(s.db, space, until)
+}
+
+func (s *stateDB) GetValueMeta(space []byte, key []byte) (*ValueMeta, bool, error) {
+	return GetValueMeta(s.db, space, key)
+}
+
+func (s *stateDB) SetValue(space []byte, key []byte, vmeta *ValueMeta, valueHash common.Hash) error {
+	return PutSpaceKey(s.db, space, key, vmeta, valueHash)
+}
+
+func (s *stateDB) DeleteValue(space []byte, key []byte) error {
+	return DeleteSpaceKey(s.db, space, key)
+}
+
+func (s *stateDB) PutTombstone(height uint64, txID ids.ID, size uint64) error {
+	return PutTombstone(s.db, height, txID, size)
+}
+
+func (s *stateDB) PutWarpMessage(txID ids.ID, m *WarpMessage) error {
+	return PutWarpMessage(s.db, txID, m)
+}
+
+func (s *stateDB) GetBalance(address common.Address) (uint64, error) {
+	return GetBalance(s.db, address)
+}
+
+func (s *stateDB) ModifyBalance(address common.Address, add bool, change uint64) (uint64, error) {
+	return ModifyBalance(s.db, address, add, change)
+}
+
+func (s *stateDB) HasImported(utxoID ids.ID) (bool, error) {
+	return HasImported(s.db, utxoID)
+}
+
+func (s *stateDB) SetImported(utxoID ids.ID) error {
+	return SetImported(s.db, utxoID)
+}
+
+func (s *stateDB) GetCommitment(commitment common.Hash) (uint64, bool, error) {
+	return GetCommitment(s.db, commitment)
+}
+
+func (s *stateDB) PutCommitment(commitment common.Hash, t uint64) error {
+	return PutCommitment(s.db, commitment, t)
+}
+
+func (s *stateDB) DeleteCommitment(commitment common.Hash) error {
+	return DeleteCommitment(s.db, commitment)
+}
+
+func (s *stateDB) GetFreeWrites(space []byte, window uint64) (uint64, error) {
+	return GetFreeWrites(s.db, space, window)
+}
+
+func (s *stateDB) PutFreeWrites(space []byte, window uint64, writes uint64) error {
+	return PutFreeWrites(s.db, space, window, writes)
+}
+
+func (s *stateDB) HasApproval(action common.Hash, coSigner common.Address) (bool, error) {
+	return HasApproval(s.db, action, coSigner)
+}
+
+func (s *stateDB) PutApproval(action common.Hash, coSigner common.Address) error {
+	return PutApproval(s.db, action, coSigner)
+}
+
+func (s *stateDB) DeleteApproval(action common.Hash, coSigner common.Address) error {
+	return DeleteApproval(s.db, action, coSigner)
+}
@@ -0,0 +1,70 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// countingState wraps a [StateDB] and counts the values set and deleted
+type countingState struct {
+	StateDB
+	sets, deletes int
+}
+
+func (c *countingState) SetValue(space []byte, key []byte, vmeta *ValueMeta, valueHash common.Hash) error {
+	c.sets++
+	return c.StateDB.SetValue(space, key, vmeta, valueHash)
+}
+
+func (c *countingState) DeleteValue(space []byte, key []byte) error {
+	c.deletes++
+	return c.StateDB.DeleteValue(space, key)
+}
+
+func TestStateDBWrapper(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.New()
+	defer db.Close()
+	g := DefaultGenesis()
+	sender := common.HexToAddress("0x1")
+	state := &countingState{StateDB: NewStateDB(db)}
+
+	for i, utx := range []UnsignedTransaction{
+		&ClaimTx{BaseTx: &BaseTx{}, Space: "foo"},
+		&SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: []byte("value")},
+		&SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: []byte("value2")},
+		&DeleteTx{BaseTx: &BaseTx{}, Space: "foo", Key: "bar"},
+	} {
+		if err := utx.Execute(&TransactionContext{
+			Genesis:   g,
+			State:     state,
+			BlockTime: 1,
+			TxID:      ids.GenerateTestID(),
+			Sender:    sender,
+		}); err != nil {
+			t.Fatalf("#%d: unexpected error %v", i, err)
+		}
+	}
+	if state.sets != 2 || state.deletes != 1 {
+		t.Fatalf("expected 2 sets and 1 delete, got %d and %d", state.sets, state.deletes)
+	}
+
+	// Writes through the wrapper land in the underlying database
+	i, exists, err := GetSpaceInfo(db, []byte("foo"))
+	if err != nil || !exists {
+		t.Fatalf("expected space to exist (err=%v)", err)
+	}
+	if i.Owner != sender || i.Size != 0 {
+		t.Fatalf("unexpected space info %+v", i)
+	}
+	if _, exists, err := GetValueMeta(db, []byte("foo"), []byte("bar")); err != nil || exists {
+		t.Fatalf("expected value to be deleted (err=%v)", err)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/ava-labs/avalanchego/database"
+)
+
+var stateStatsKey = []byte("state_stats")
+
+// StateStats are running totals of the state that are updated as spaces and
+// keys are created, modified, and removed.
+type StateStats struct {
+	Spaces     uint64 `serialize:"true" json:"spaces"`
+	Keys       uint64 `serialize:"true" json:"keys"`
+	ValueBytes uint64 `serialize:"true" json:"valueBytes"`
+}
+
+func HasStateStats(db database.KeyValueReader) (bool, error) {
+	return db.Has(stateStatsKey)
+}
+
+// GetStateStats returns the stored [StateStats] (or empty stats if they have
+// not yet been written).
+func GetStateStats(db database.KeyValueReader) (*StateStats, error) {
+	s := new(StateStats)
+	v, err := db.Get(stateStatsKey)
+	if errors.Is(err, database.ErrNotFound) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	_, err = Unmarshal(v, s)
+	return s, err
+}
+
+func putStateStats(db database.KeyValueWriter, s *StateStats) error {
+	b, err := Marshal(s)
+	if err != nil {
+		return err
+	}
+	return db.Put(stateStatsKey, b)
+}
+
+func applyDelta(v uint64, d int64) uint64 {
+	if d < 0 {
+		if uint64(-d) > v {
+			return 0
+		}
+		return v - uint64(-d)
+	}
+	return v + uint64(d)
+}
+
+// updateStateStats applies the provided deltas to the stored [StateStats].
+func updateStateStats(db database.KeyValueReaderWriter, spaces int64, keys int64, valueBytes int64) error {
+	s, err := GetStateStats(db)
+	if err != nil {
+		return err
+	}
+	s.Spaces = applyDelta(s.Spaces, spaces)
+	s.Keys = applyDelta(s.Keys, keys)
+	s.ValueBytes = applyDelta(s.ValueBytes, valueBytes)
+	return putStateStats(db, s)
+}
+
+// RecomputeStateStats scans all spaces and keys to compute and store
+// [StateStats] (used when upgrading a database that does not track them).
+func RecomputeStateStats(db database.Database) (*StateStats, error) {
+	s := new(StateStats)
+	infos, err := loadSpaceInfos(db)
+	if err != nil {
+		return nil, err
+	}
+	s.Spaces = uint64(len(infos))
+	for _, i := range infos {
+		baseKey := SpaceValueKey(i.RawSpace, nil)
+		cursor := db.NewIteratorWithStart(baseKey)
+		for cursor.Next() {
+			if !bytes.HasPrefix(cursor.Key(), baseKey) {
+				break
+			}
+			vmeta := new(ValueMeta)
+			if _, err := Unmarshal(cursor.Value(), vmeta); err != nil {
+				cursor.Release()
+				return nil, err
+			}
+			s.Keys++
+			s.ValueBytes += vmeta.Size
+		}
+		err := cursor.Error()
+		cursor.Release()
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s, putStateStats(db, s)
+}
@@ -0,0 +1,102 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestStateStats(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.New()
+	space := []byte("foo")
+	i := &SpaceInfo{Owner: common.Address{0x1}, Expiry: 100, Units: 1}
+	if err := PutSpaceInfo(db, space, i, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := PutSpaceKey(db, space, []byte("a"), &ValueMeta{Size: 10, TxID: ids.GenerateTestID()}, common.Hash{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := PutSpaceKey(db, space, []byte("b"), &ValueMeta{Size: 5, TxID: ids.GenerateTestID()}, common.Hash{}); err != nil {
+		t.Fatal(err)
+	}
+	// Overwrite should only change size
+	if err := PutSpaceKey(db, space, []byte("a"), &ValueMeta{Size: 20, TxID: ids.GenerateTestID()}, common.Hash{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := DeleteSpaceKey(db, space, []byte("b")); err != nil {
+		t.Fatal(err)
+	}
+	checkStats := func(expected StateStats) {
+		s, err := GetStateStats(db)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *s != expected {
+			t.Fatalf("expected %+v, got %+v", expected, *s)
+		}
+	}
+	checkStats(StateStats{Spaces: 1, Keys: 1, ValueBytes: 20})
+
+	recomputed, err := RecomputeStateStats(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *recomputed != (StateStats{Spaces: 1, Keys: 1, ValueBytes: 20}) {
+		t.Fatalf("unexpected recomputed stats %+v", *recomputed)
+	}
+
+	// Expire and prune the space
+	if err := ExpireNext(db, 0, 101, 0); err != nil {
+		t.Fatal(err)
+	}
+	checkStats(StateStats{Spaces: 0, Keys: 1, ValueBytes: 20})
+	if _, _, err := PruneNext(db, 10); err != nil {
+		t.Fatal(err)
+	}
+	checkStats(StateStats{})
+}
+
+func TestExpireNextBudget(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.New()
+	for _, space := range []string{"bar", "foo"} {
+		i := &SpaceInfo{Owner: common.Address{0x1}, Expiry: 100, Units: 1}
+		if err := PutSpaceInfo(db, []byte(space), i, 0); err != nil {
+			t.Fatal(err)
+		}
+		for _, k := range []string{"a", "b", "c"} {
+			if err := PutSpaceKey(db, []byte(space), []byte(k), &ValueMeta{Size: 1, TxID: ids.GenerateTestID()}, common.Hash{}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// The budget is shared by every space expiring in the block
+	if err := ExpireNext(db, 0, 101, 4); err != nil {
+		t.Fatal(err)
+	}
+	s, err := GetStateStats(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *s != (StateStats{Keys: 2, ValueBytes: 2}) {
+		t.Fatalf("unexpected stats after expiry %+v", *s)
+	}
+
+	// Only the space with remaining values is scheduled for pruning
+	removals, keys, err := PruneNext(db, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removals != 1 || keys != 2 {
+		t.Fatalf("expected to prune 1 space and 2 keys, got %d %d", removals, keys)
+	}
+}
@@ -24,7 +24,7 @@ import (
 
 // 0x0/ (block hashes)
 // 0x1/ (tx hashes)
-//   -> [tx hash]=>nil
+//   -> [tx hash]=>[block ID] (nil if accepted before blocks were indexed)
 // 0x2/ (tx values)
 //   -> [tx hash]=>value
 // 0x3/ (singleton space info)
@@ -40,17 +40,74 @@ import (
 //   -> [owner]=> balance
 // 0x8/ (owned spaces)
 //   -> [owner]/[space]=> nil
+// 0x9/ (space history)
+//   -> [space]/[height][tx index]=> activity
+// 0xa/ (sender history)
+//   -> [sender]/[height][tx index]=> activity
+// 0xb/ (block heights)
+//   -> [height]=> block ID
+// 0xc/ (imported atomic UTXOs)
+//   -> [utxo ID]=> nil
+// 0xd/ (warp messages)
+//   -> [tx ID]=> warp message
+// 0xe/ (removed value tombstones)
+//   -> [height][tx ID]=> value size
+// 0xf/ (indexed blocks)
+//   -> [height]=> index entries written for the block
+// 0x10/ (claim commitments)
+//   -> [commitment]=> timestamp
+// 0x11/ (free write quotas)
+//   -> [space]=> window + free writes made in window
+// 0x12/ (co-signer approvals)
+//   -> [action hash][co-signer]=> nil
+// 0x13/ (scheduled move queue)
+//   -> [activation][raw space]=> space
+// 0x14/ (burned spaces)
+//   -> [space]=> end of cooldown
+// 0x15/ (dropped txs)
+//   -> [slot]=> dropped tx
+// 0x16/ (dropped tx index)
+//   -> [tx ID]=> slot
+// 0x17/ (value hash index)
+//   -> [hash][space]/[key]=> nil
+// 0x18/ (value hashes)
+//   -> [raw space][key]=> [hash][space]
+// 0x19/ (deduplicated values)
+//   -> [hash]=> value
+// 0x1a/ (value references)
+//   -> [hash]=> number of txs linked to the value
+// 0x1b/ (value links)
+//   -> [tx hash]=> [hash]
 
 const (
-	blockPrefix   = 0x0
-	txPrefix      = 0x1
-	txValuePrefix = 0x2
-	infoPrefix    = 0x3
-	keyPrefix     = 0x4
-	expiryPrefix  = 0x5
-	pruningPrefix = 0x6
-	balancePrefix = 0x7
-	ownedPrefix   = 0x8
+	blockPrefix     = 0x0
+	txPrefix        = 0x1
+	txValuePrefix   = 0x2
+	infoPrefix      = 0x3
+	keyPrefix       = 0x4
+	expiryPrefix    = 0x5
+	pruningPrefix   = 0x6
+	balancePrefix   = 0x7
+	ownedPrefix     = 0x8
+	historyPrefix   = 0x9
+	senderPrefix    = 0xa
+	heightPrefix    = 0xb
+	atomicPrefix    = 0xc
+	warpPrefix      = 0xd
+	tombPrefix      = 0xe
+	indexedPrefix   = 0xf
+	commitPrefix    = 0x10
+	freePrefix      = 0x11
+	approvePrefix   = 0x12
+	movePrefix      = 0x13
+	burnPrefix      = 0x14
+	dropPrefix      = 0x15
+	dropIndexPrefix = 0x16
+	hashPrefix      = 0x17
+	valueHashPrefix = 0x18
+	contentPrefix   = 0x19
+	refPrefix       = 0x1a
+	valueLinkPrefix = 0x1b
 
 	shortIDLen = 20
 
@@ -62,18 +119,31 @@ type CompactRange struct {
 	Limit []byte
 }
 
+// Contains returns true if [k] falls in [Start, Limit).
+func (r *CompactRange) Contains(k []byte) bool {
+	return bytes.Compare(r.Start, k) <= 0 && bytes.Compare(k, r.Limit) < 0
+}
+
 var (
 	lastAccepted  = []byte("last_accepted")
 	linkedTxCache = &cache.LRU{Size: linkedTxLRUSize}
 
 	CompactRanges = []*CompactRange{
-		// Don't compact block/tx/txValue ranges because no overwriting/deletion
+		// Don't compact the block range because no overwriting/deletion
+		{[]byte{txPrefix, parser.ByteDelimiter}, []byte{infoPrefix, parser.ByteDelimiter}},
 		{[]byte{infoPrefix, parser.ByteDelimiter}, []byte{keyPrefix, parser.ByteDelimiter}},
 		{[]byte{keyPrefix, parser.ByteDelimiter}, []byte{expiryPrefix, parser.ByteDelimiter}},
 		// Group expiry and pruning together
 		{[]byte{expiryPrefix, parser.ByteDelimiter}, []byte{balancePrefix, parser.ByteDelimiter}},
 		{[]byte{balancePrefix, parser.ByteDelimiter}, []byte{ownedPrefix, parser.ByteDelimiter}},
 		{[]byte{ownedPrefix, parser.ByteDelimiter}, []byte{ownedPrefix + 1, parser.ByteDelimiter}},
+		// Group space and sender history together
+		{[]byte{historyPrefix, parser.ByteDelimiter}, []byte{heightPrefix, parser.ByteDelimiter}},
+		{[]byte{tombPrefix, parser.ByteDelimiter}, []byte{indexedPrefix + 1, parser.ByteDelimiter}},
+		{[]byte{commitPrefix, parser.ByteDelimiter}, []byte{burnPrefix + 1, parser.ByteDelimiter}},
+		{[]byte{dropPrefix, parser.ByteDelimiter}, []byte{dropIndexPrefix + 1, parser.ByteDelimiter}},
+		{[]byte{hashPrefix, parser.ByteDelimiter}, []byte{valueHashPrefix + 1, parser.ByteDelimiter}},
+		{[]byte{contentPrefix, parser.ByteDelimiter}, []byte{valueLinkPrefix + 1, parser.ByteDelimiter}},
 	}
 )
 
@@ -86,6 +156,80 @@ func PrefixBlockKey(blockID ids.ID) (k []byte) {
 	return k
 }
 
+// [heightPrefix] + [delimiter] + [height]
+func PrefixBlockHeightKey(height uint64) (k []byte) {
+	k = make([]byte, 2+8)
+	k[0] = heightPrefix
+	k[1] = parser.ByteDelimiter
+	binary.BigEndian.PutUint64(k[2:], height)
+	return k
+}
+
+// [atomicPrefix] + [delimiter] + [utxoID]
+func PrefixImportedKey(utxoID ids.ID) (k []byte) {
+	k = make([]byte, 2+len(utxoID))
+	k[0] = atomicPrefix
+	k[1] = parser.ByteDelimiter
+	copy(k[2:], utxoID[:])
+	return k
+}
+
+// [commitPrefix] + [delimiter] + [commitment]
+func PrefixCommitmentKey(commitment common.Hash) (k []byte) {
+	k = make([]byte, 2+common.HashLength)
+	k[0] = commitPrefix
+	k[1] = parser.ByteDelimiter
+	copy(k[2:], commitment[:])
+	return k
+}
+
+// [freePrefix] + [delimiter] + [space]
+func PrefixFreeWritesKey(space []byte) (k []byte) {
+	k = make([]byte, 2+len(space))
+	k[0] = freePrefix
+	k[1] = parser.ByteDelimiter
+	copy(k[2:], space)
+	return k
+}
+
+// [burnPrefix] + [delimiter] + [space]
+func PrefixBurnedKey(space []byte) (k []byte) {
+	k = make([]byte, 2+len(space))
+	k[0] = burnPrefix
+	k[1] = parser.ByteDelimiter
+	copy(k[2:], space)
+	return k
+}
+
+// [approvePrefix] + [delimiter] + [action] + [coSigner]
+func PrefixApprovalKey(action common.Hash, coSigner common.Address) (k []byte) {
+	k = make([]byte, 2+common.HashLength+common.AddressLength)
+	k[0] = approvePrefix
+	k[1] = parser.ByteDelimiter
+	copy(k[2:], action[:])
+	copy(k[2+common.HashLength:], coSigner[:])
+	return k
+}
+
+// [warpPrefix] + [delimiter] + [txID]
+func PrefixWarpKey(txID ids.ID) (k []byte) {
+	k = make([]byte, 2+len(txID))
+	k[0] = warpPrefix
+	k[1] = parser.ByteDelimiter
+	copy(k[2:], txID[:])
+	return k
+}
+
+// [tombPrefix] + [delimiter] + [height] + [txID]
+func PrefixTombstoneKey(height uint64, txID ids.ID) (k []byte) {
+	k = make([]byte, 2+8+len(txID))
+	k[0] = tombPrefix
+	k[1] = parser.ByteDelimiter
+	binary.BigEndian.PutUint64(k[2:], height)
+	copy(k[2+8:], txID[:])
+	return k
+}
+
 // [txPrefix] + [delimiter] + [txID]
 func PrefixTxKey(txID ids.ID) (k []byte) {
 	k = make([]byte, 2+len(txID))
@@ -127,6 +271,25 @@ func RawSpace(space []byte, blockTime uint64) (ids.ShortID, error) {
 	return rspace, nil
 }
 
+// ClaimRawSpace returns the raw space of [space] claimed by [txID] at
+// [blockTime]. Hashing in [txID] keeps it unique when [space] is burned and
+// claimed again in the same second, as the values of the burned raw space
+// are pruned asynchronously by each node.
+func ClaimRawSpace(space []byte, blockTime uint64, txID ids.ID) (ids.ShortID, error) {
+	spaceLen := len(space)
+	r := make([]byte, spaceLen+1+8+len(txID))
+	copy(r, space)
+	r[spaceLen] = parser.ByteDelimiter
+	binary.BigEndian.PutUint64(r[spaceLen+1:], blockTime)
+	copy(r[spaceLen+1+8:], txID[:])
+	h := hashing.ComputeHash160(r)
+	rspace, err := ids.ToShortID(h)
+	if err != nil {
+		return ids.ShortID{}, err
+	}
+	return rspace, nil
+}
+
 // Assumes [space] and [key] do not contain delimiter
 // [keyPrefix] + [delimiter] + [rawSpace] + [delimiter] + [key]
 func SpaceValueKey(rspace ids.ShortID, key []byte) (k []byte) {
@@ -154,6 +317,11 @@ func PrefixExpiryKey(expiry uint64, rspace ids.ShortID) (k []byte) {
 	return specificTimeKey(expiryPrefix, expiry, rspace)
 }
 
+// [movePrefix] + [delimiter] + [timestamp] + [delimiter] + [rawSpace]
+func PrefixScheduledMoveKey(activation uint64, rspace ids.ShortID) (k []byte) {
+	return specificTimeKey(movePrefix, activation, rspace)
+}
+
 // [pruningPrefix] + [delimiter] + [timestamp] + [delimiter] + [rawSpace]
 func PrefixPruningKey(expired uint64, rspace ids.ShortID) (k []byte) {
 	return specificTimeKey(pruningPrefix, expired, rspace)
@@ -192,9 +360,7 @@ func specificTimeKey(p byte, t uint64, rspace ids.ShortID) (k []byte) {
 	return k
 }
 
-var ErrInvalidKeyFormat = errors.New("invalid key format")
-
-// extracts expiry/pruning timstamp and raw space
+// extracts expiry/pruning/move timstamp and raw space
 func extractSpecificTimeKey(k []byte) (timestamp uint64, rspace ids.ShortID, err error) {
 	if len(k) != specificTimeKeyLen {
 		return 0, ids.ShortEmpty, ErrInvalidKeyFormat
@@ -308,10 +474,34 @@ func GetAllValueMetas(db database.Database, rspace ids.ShortID) (kvs []*KeyValue
 	return kvs, cursor.Error()
 }
 
-// linkValues extracts all *SetTx.Value in [block] and replaces them with the
-// corresponding txID where they were found. The extracted value is then
-// written to disk.
-func linkValues(db database.KeyValueWriter, block *StatelessBlock) ([]*Transaction, error) {
+// ListValueMetas returns up to [limit] keys of [rspace] and their metadata in
+// key order, continuing from [cursor] (see [Cursor]). The returned cursor is
+// empty when there are no more keys.
+func ListValueMetas(db database.Iteratee, rspace ids.ShortID, cursor string, limit int, height uint64) ([]*KeyValueMeta, string, error) {
+	c, err := ParseCursor(cursor, height)
+	if err != nil {
+		return nil, "", err
+	}
+	kvs := []*KeyValueMeta{}
+	next, err := paginate(db, SpaceValueKey(rspace, nil), c, limit, nil, func(key []byte, v []byte) error {
+		vmeta := new(ValueMeta)
+		if _, err := Unmarshal(v, vmeta); err != nil {
+			return err
+		}
+		kvs = append(kvs, &KeyValueMeta{Key: string(key), ValueMeta: vmeta})
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return kvs, next, nil
+}
+
+// linkValues extracts all *SetTx.Value (and the values of the keys of each
+// *ClaimTx) in [block] and replaces them with the ID they are linked to (the
+// txID where they were found or its [ClaimValueID]). The extracted value is
+// then written to disk (once per distinct value, see [putTxValue]).
+func linkValues(db database.KeyValueReaderWriterDeleter, block *StatelessBlock) ([]*Transaction, error) {
 	g := block.vm.Genesis()
 	ogTxs := make([]*Transaction, len(block.Txs))
 	for i, tx := range block.Txs {
@@ -329,10 +519,32 @@ func linkValues(db database.KeyValueWriter, block *StatelessBlock) ([]*Transacti
 			}
 			ogTxs[i] = cptx
 
-			if err := db.Put(PrefixTxValueKey(tx.ID()), t.Value); err != nil {
+			if err := putTxValue(db, tx.ID(), t.Value); err != nil {
 				return nil, err
 			}
 			t.Value = tx.id[:] // used to properly parse on restore
+		case *ClaimTx:
+			if len(t.Keys) == 0 {
+				ogTxs[i] = tx
+				continue
+			}
+
+			cptx := tx.Copy()
+			if err := cptx.Init(g); err != nil {
+				return nil, err
+			}
+			ogTxs[i] = cptx
+
+			for idx, ck := range t.Keys {
+				if len(ck.Value) == 0 {
+					continue
+				}
+				vid := ClaimValueID(tx.ID(), idx)
+				if err := putTxValue(db, vid, ck.Value); err != nil {
+					return nil, err
+				}
+				ck.Value = vid[:]
+			}
 		default:
 			ogTxs[i] = tx
 		}
@@ -340,29 +552,70 @@ func linkValues(db database.KeyValueWriter, block *StatelessBlock) ([]*Transacti
 	return ogTxs, nil
 }
 
+// PutLinkedValue stores the value of [tx] (if it is a *SetTx, or the values
+// of the keys of a *ClaimTx) where it is linked once a block including [tx] is
+// accepted, so state that [tx] was executed on outside of a block can still
+// resolve it.
+func PutLinkedValue(db database.KeyValueReaderWriterDeleter, tx *Transaction) error {
+	switch t := tx.UnsignedTransaction.(type) {
+	case *SetTx:
+		if len(t.Value) == 0 {
+			return nil
+		}
+		return putTxValue(db, tx.ID(), t.Value)
+	case *ClaimTx:
+		for idx, ck := range t.Keys {
+			if len(ck.Value) == 0 {
+				continue
+			}
+			if err := putTxValue(db, ClaimValueID(tx.ID(), idx), ck.Value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // restoreValues restores the unlinked values associated with all *SetTx.Value
-// in [block].
+// (and the keys of each *ClaimTx) in [block].
 func restoreValues(db database.KeyValueReader, block *StatefulBlock) error {
 	for _, tx := range block.Txs {
-		if t, ok := tx.UnsignedTransaction.(*SetTx); ok {
+		switch t := tx.UnsignedTransaction.(type) {
+		case *SetTx:
 			if len(t.Value) == 0 {
 				continue
 			}
-			txID, err := ids.ToID(t.Value)
-			if err != nil {
-				return err
-			}
-			b, err := db.Get(PrefixTxValueKey(txID))
+			b, err := restoreValue(db, t.Value)
 			if err != nil {
 				return err
 			}
 			t.Value = b
+		case *ClaimTx:
+			for _, ck := range t.Keys {
+				if len(ck.Value) == 0 {
+					continue
+				}
+				b, err := restoreValue(db, ck.Value)
+				if err != nil {
+					return err
+				}
+				ck.Value = b
+			}
 		}
 	}
 	return nil
 }
 
-func SetLastAccepted(db database.KeyValueWriter, block *StatelessBlock) error {
+// restoreValue returns the value linked to [id] (see [linkValues])
+func restoreValue(db database.KeyValueReader, id []byte) ([]byte, error) {
+	txID, err := ids.ToID(id)
+	if err != nil {
+		return nil, err
+	}
+	return getTxValue(db, txID)
+}
+
+func SetLastAccepted(db database.KeyValueReaderWriterDeleter, block *StatelessBlock) error {
 	bid := block.ID()
 	if err := db.Put(lastAccepted, bid[:]); err != nil {
 		return err
@@ -378,6 +631,9 @@ func SetLastAccepted(db database.KeyValueWriter, block *StatelessBlock) error {
 	if err := db.Put(PrefixBlockKey(bid), sbytes); err != nil {
 		return err
 	}
+	if err := db.Put(PrefixBlockHeightKey(block.Hght), bid[:]); err != nil {
+		return err
+	}
 	// Restore the original transactions in the block in case it is cached for
 	// later use.
 	block.Txs = ogTxs
@@ -399,6 +655,20 @@ func GetLastAccepted(db database.KeyValueReader) (ids.ID, error) {
 	return ids.ToID(v)
 }
 
+// GetBlockIDAtHeight returns the ID of the accepted block at [height]. Blocks
+// accepted before heights were indexed are not found.
+func GetBlockIDAtHeight(db database.KeyValueReader, height uint64) (ids.ID, bool, error) {
+	v, err := db.Get(PrefixBlockHeightKey(height))
+	if errors.Is(err, database.ErrNotFound) {
+		return ids.Empty, false, nil
+	}
+	if err != nil {
+		return ids.Empty, false, err
+	}
+	id, err := ids.ToID(v)
+	return id, true, err
+}
+
 func GetBlock(db database.KeyValueReader, bid ids.ID) (*StatefulBlock, error) {
 	b, err := db.Get(PrefixBlockKey(bid))
 	if err != nil {
@@ -414,9 +684,12 @@ func GetBlock(db database.KeyValueReader, bid ids.ID) (*StatefulBlock, error) {
 	return blk, nil
 }
 
-// ExpireNext queries "expiryPrefix" key space to find expiring keys,
-// deletes their spaceInfos, and schedules its key pruning with its raw space.
-func ExpireNext(db database.Database, rparent int64, rcurrent int64, bootstrapped bool) (err error) {
+// ExpireNext queries "expiryPrefix" key space to find expiring keys and
+// deletes their spaceInfos. Up to [budget] value keys of the expired spaces are
+// deleted immediately, and spaces with values remaining are scheduled for
+// pruning with their raw space. This keeps the work done when a block is
+// accepted bounded, regardless of how much data expires.
+func ExpireNext(db database.Database, rparent int64, rcurrent int64, budget int) (err error) {
 	parent, current := uint64(rparent), uint64(rcurrent)
 	startKey := RangeTimeKey(expiryPrefix, parent)
 	endKey := RangeTimeKey(expiryPrefix, current)
@@ -440,42 +713,101 @@ func ExpireNext(db database.Database, rparent int64, rcurrent int64, bootstrappe
 		owner := common.BytesToAddress(expiryValue[:common.AddressLength])
 		space := expiryValue[common.AddressLength:]
 
-		// Update owned prefix
-		if err := db.Delete(PrefixOwnedKey(owner, space)); err != nil {
+		expired, rspc, err := extractSpecificTimeKey(curKey)
+		if err != nil {
 			return err
 		}
-
-		// [infoPrefix] + [delimiter] + [space]
-		k := SpaceInfoKey(space)
-		if err := db.Delete(k); err != nil {
+		cleared, done, err := removeSpace(db, owner, space, rspc, expired, budget)
+		if err != nil {
 			return err
 		}
+		budget -= cleared
+		log.Debug("space expired", "space", string(space), "keys", cleared, "pruned", done)
+	}
+	return cursor.Error()
+}
 
-		expired, rspc, err := extractSpecificTimeKey(curKey)
+// removeSpace deletes the info (and free write quota) of [space] and up to
+// [budget] of its value keys, scheduling it for pruning (as removed at [removed]) if values remain.
+func removeSpace(
+	db database.Database, owner common.Address, space []byte,
+	rspc ids.ShortID, removed uint64, budget int,
+) (int, bool, error) {
+	// Update owned prefix
+	if err := db.Delete(PrefixOwnedKey(owner, space)); err != nil {
+		return 0, false, err
+	}
+
+	// [infoPrefix] + [delimiter] + [space]
+	k := SpaceInfoKey(space)
+	if err := db.Delete(k); err != nil {
+		return 0, false, err
+	}
+	if err := updateStateStats(db, -1, 0, 0); err != nil {
+		return 0, false, err
+	}
+
+	// A future owner of [space] starts with a fresh free write quota
+	if err := db.Delete(PrefixFreeWritesKey(space)); err != nil {
+		return 0, false, err
+	}
+
+	cleared, done := 0, false
+	if budget > 0 {
+		var err error
+		cleared, done, err = clearSpaceValues(db, rspc, budget)
 		if err != nil {
-			return err
+			return 0, false, err
 		}
-		if bootstrapped {
-			// [pruningPrefix] + [delimiter] + [timestamp] + [delimiter] + [rawSpace]
-			k = PrefixPruningKey(expired, rspc)
-			if err := db.Put(k, nil); err != nil {
-				return err
-			}
-		} else {
-			// If we are not yet bootstrapped, we should delete the dangling value keys
-			// immediately instead of clearing async.
-			if err := database.ClearPrefix(db, db, SpaceValueKey(rspc, nil)); err != nil {
-				return err
-			}
+	}
+	if !done {
+		// [pruningPrefix] + [delimiter] + [timestamp] + [delimiter] + [rawSpace]
+		k = PrefixPruningKey(removed, rspc)
+		if err := db.Put(k, nil); err != nil {
+			return 0, false, err
 		}
-		log.Debug("space expired", "space", string(space))
 	}
-	return cursor.Error()
+	return cleared, done, nil
+}
+
+// BurnSpace removes [space] (with info [i]) immediately, scheduling its
+// values for pruning as if it expired at [t].
+func BurnSpace(db database.Database, space []byte, i *SpaceInfo, t uint64) error {
+	if err := db.Delete(PrefixExpiryKey(i.Expiry, i.RawSpace)); err != nil {
+		return err
+	}
+	if i.Scheduled.Activation > 0 {
+		if err := DeleteScheduledMove(db, i.RawSpace, i.Scheduled.Activation); err != nil {
+			return err
+		}
+	}
+	_, _, err := removeSpace(db, i.Owner, space, i.RawSpace, t, 0)
+	return err
+}
+
+// GetBurned returns the end of the cooldown of burned [space], if it has one
+func GetBurned(db database.KeyValueReader, space []byte) (uint64, bool, error) {
+	v, err := db.Get(PrefixBurnedKey(space))
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return binary.BigEndian.Uint64(v), true, nil
+}
+
+// PutBurned blocks burned [space] from being claimed until [until]
+func PutBurned(db database.KeyValueWriter, space []byte, until uint64) error {
+	v := make([]byte, 8)
+	binary.BigEndian.PutUint64(v, until)
+	return db.Put(PrefixBurnedKey(space), v)
 }
 
 // PruneNext queries the keys that are currently marked with "pruningPrefix",
-// and clears them from the database.
-func PruneNext(db database.Database, limit int) (removals int, err error) {
+// and clears them from the database. It returns the number of raw spaces
+// pruned and the total number of value keys deleted.
+func PruneNext(db database.Database, limit int) (removals int, keys int, err error) {
 	startKey := RangeTimeKey(pruningPrefix, 0)
 	endKey := RangeTimeKey(pruningPrefix, math.MaxInt64)
 	cursor := db.NewIteratorWithStart(startKey)
@@ -491,19 +823,54 @@ func PruneNext(db database.Database, limit int) (removals int, err error) {
 		}
 		_, rspc, err := extractSpecificTimeKey(curKey)
 		if err != nil {
-			return removals, err
+			return removals, keys, err
 		}
 		if err := db.Delete(curKey); err != nil {
-			return removals, err
+			return removals, keys, err
 		}
 		// [keyPrefix] + [delimiter] + [rawSpace] + [delimiter] + [key]
-		if err := database.ClearPrefix(db, db, SpaceValueKey(rspc, nil)); err != nil {
-			return removals, err
+		cleared, _, err := clearSpaceValues(db, rspc, math.MaxInt)
+		if err != nil {
+			return removals, keys, err
 		}
-		log.Debug("rspace pruned", "rspace", rspc.Hex())
+		keys += cleared
+		log.Debug("rspace pruned", "rspace", rspc.Hex(), "keys", cleared)
 		removals++
 	}
-	return removals, cursor.Error()
+	return removals, keys, cursor.Error()
+}
+
+// clearSpaceValues deletes up to [limit] value keys of [rspace], updates
+// [StateStats], and returns the number of keys deleted and whether no value
+// keys remain.
+func clearSpaceValues(db database.Database, rspace ids.ShortID, limit int) (int, bool, error) {
+	cursor := db.NewIteratorWithPrefix(SpaceValueKey(rspace, nil))
+	defer cursor.Release()
+	cleared, valueBytes, done := 0, uint64(0), true
+	for cursor.Next() {
+		if cleared == limit {
+			done = false
+			break
+		}
+		vmeta := new(ValueMeta)
+		if _, err := Unmarshal(cursor.Value(), vmeta); err != nil {
+			return cleared, false, err
+		}
+		// [keyPrefix] + [delimiter] + [rawSpace] + [delimiter] + [key]
+		key := cursor.Key()[2+shortIDLen+1:]
+		if err := deleteValueHash(db, rspace, key); err != nil {
+			return cleared, false, err
+		}
+		if err := db.Delete(cursor.Key()); err != nil {
+			return cleared, false, err
+		}
+		cleared++
+		valueBytes += vmeta.Size
+	}
+	if err := cursor.Error(); err != nil {
+		return cleared, false, err
+	}
+	return cleared, done, updateStateStats(db, 0, -int64(cleared), -int64(valueBytes))
 }
 
 // DB
@@ -534,19 +901,29 @@ func ExpiryDataValue(address common.Address, space []byte) (v []byte) {
 	return v
 }
 
-func PutSpaceInfo(db database.KeyValueWriterDeleter, space []byte, i *SpaceInfo, lastExpiry uint64) error {
-	// If [RawSpace] is empty, this is a new space.
-	if i.RawSpace == ids.ShortEmpty {
-		rspace, err := RawSpace(space, i.Created)
-		if err != nil {
-			return err
+func PutSpaceInfo(db database.KeyValueReaderWriterDeleter, space []byte, i *SpaceInfo, lastExpiry uint64) error {
+	// If [space] has no info yet, this is a new space. Claims derive its
+	// [RawSpace] themselves (see [ClaimRawSpace]).
+	exists, err := db.Has(SpaceInfoKey(space))
+	if err != nil {
+		return err
+	}
+	if !exists {
+		if i.RawSpace == ids.ShortEmpty {
+			rspace, err := RawSpace(space, i.Created)
+			if err != nil {
+				return err
+			}
+			i.RawSpace = rspace
 		}
-		i.RawSpace = rspace
 
 		// Only store the owner on creation
 		if err := db.Put(PrefixOwnedKey(i.Owner, space), nil); err != nil {
 			return err
 		}
+		if err := updateStateStats(db, 1, 0, 0); err != nil {
+			return err
+		}
 	}
 	if lastExpiry > 0 {
 		// [expiryPrefix] + [delimiter] + [timestamp] + [delimiter] + [rawSpace]
@@ -569,6 +946,58 @@ func PutSpaceInfo(db database.KeyValueWriterDeleter, space []byte, i *SpaceInfo,
 	return db.Put(k, b)
 }
 
+// ActivateMoves transfers every space whose scheduled move activates at or
+// before [rcurrent] to its recipient. Moves that were replaced or canceled,
+// or whose space expired, are discarded.
+func ActivateMoves(db database.Database, rcurrent int64) error {
+	current := uint64(rcurrent)
+	endKey := RangeTimeKey(movePrefix, current+1)
+	cursor := db.NewIteratorWithStart(RangeTimeKey(movePrefix, 0))
+	defer cursor.Release()
+	for cursor.Next() {
+		// [movePrefix] + [delimiter] + [timestamp] + [delimiter] + [rawSpace]
+		curKey := cursor.Key()
+		if bytes.Compare(curKey, endKey) >= 0 {
+			break
+		}
+		if err := db.Delete(curKey); err != nil {
+			return err
+		}
+		activation, rspc, err := extractSpecificTimeKey(curKey)
+		if err != nil {
+			return err
+		}
+		space := cursor.Value()
+		i, exists, err := GetSpaceInfo(db, space)
+		if err != nil {
+			return err
+		}
+		if !exists || i.RawSpace != rspc || i.Scheduled.Activation != activation {
+			continue
+		}
+		oldOwner := i.Owner
+		i.Owner = i.Scheduled.To
+		i.Scheduled = ScheduledMove{}
+		if err := MoveSpaceInfo(db, oldOwner, space, i); err != nil {
+			return err
+		}
+		log.Debug("scheduled move activated", "space", string(space), "to", i.Owner)
+	}
+	return cursor.Error()
+}
+
+// PutScheduledMove queues the move of [space] scheduled to activate at
+// [activation]
+func PutScheduledMove(db database.KeyValueWriter, space []byte, rspace ids.ShortID, activation uint64) error {
+	return db.Put(PrefixScheduledMoveKey(activation, rspace), space)
+}
+
+// DeleteScheduledMove removes the move of [rspace] scheduled to activate at
+// [activation] from the queue
+func DeleteScheduledMove(db database.KeyValueDeleter, rspace ids.ShortID, activation uint64) error {
+	return db.Delete(PrefixScheduledMoveKey(activation, rspace))
+}
+
 // MoveSpaceInfo should only be used if the expiry isn't changing and
 // [SpaceInfo] is already in the database.
 func MoveSpaceInfo(
@@ -598,12 +1027,18 @@ func MoveSpaceInfo(
 type ValueMeta struct {
 	Size uint64 `serialize:"true" json:"size"`
 	TxID ids.ID `serialize:"true" json:"txId"`
+	// Kind is how the value should be interpreted (see [SetTx.Kind])
+	Kind string `serialize:"true" json:"kind,omitempty"`
 
 	Created uint64 `serialize:"true" json:"created"`
 	Updated uint64 `serialize:"true" json:"updated"`
 }
 
-func PutSpaceKey(db database.KeyValueReaderWriter, space []byte, key []byte, vmeta *ValueMeta) error {
+// PutSpaceKey stores [vmeta] at [key] in [space] and indexes the value by
+// [valueHash] (see [ValueHash]).
+func PutSpaceKey(
+	db database.KeyValueReaderWriterDeleter, space []byte, key []byte, vmeta *ValueMeta, valueHash common.Hash,
+) error {
 	spaceInfo, exists, err := GetSpaceInfo(db, space)
 	if err != nil {
 		return err
@@ -613,6 +1048,21 @@ func PutSpaceKey(db database.KeyValueReaderWriter, space []byte, key []byte, vme
 	}
 	// [keyPrefix] + [delimiter] + [rawSpace] + [delimiter] + [key]
 	k := SpaceValueKey(spaceInfo.RawSpace, key)
+	prev, exists, err := getValueMetaAt(db, k)
+	if err != nil {
+		return err
+	}
+	if exists {
+		err = updateStateStats(db, 0, 0, int64(vmeta.Size)-int64(prev.Size))
+	} else {
+		err = updateStateStats(db, 0, 1, int64(vmeta.Size))
+	}
+	if err != nil {
+		return err
+	}
+	if err := putValueHash(db, space, spaceInfo.RawSpace, key, valueHash); err != nil {
+		return err
+	}
 	rvmeta, err := Marshal(vmeta)
 	if err != nil {
 		return err
@@ -620,6 +1070,21 @@ func PutSpaceKey(db database.KeyValueReaderWriter, space []byte, key []byte, vme
 	return db.Put(k, rvmeta)
 }
 
+func getValueMetaAt(db database.KeyValueReader, k []byte) (*ValueMeta, bool, error) {
+	rvmeta, err := db.Get(k)
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	vmeta := new(ValueMeta)
+	if _, err := Unmarshal(rvmeta, vmeta); err != nil {
+		return nil, false, err
+	}
+	return vmeta, true, nil
+}
+
 func DeleteSpaceKey(db database.Database, space []byte, key []byte) error {
 	spaceInfo, exists, err := GetSpaceInfo(db, space)
 	if err != nil {
@@ -629,12 +1094,72 @@ func DeleteSpaceKey(db database.Database, space []byte, key []byte) error {
 		return ErrSpaceMissing
 	}
 	k := SpaceValueKey(spaceInfo.RawSpace, key)
+	prev, exists, err := getValueMetaAt(db, k)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if err := updateStateStats(db, 0, -1, -int64(prev.Size)); err != nil {
+			return err
+		}
+	}
+	if err := deleteValueHash(db, spaceInfo.RawSpace, key); err != nil {
+		return err
+	}
 	return db.Delete(k)
 }
 
-func SetTransaction(db database.KeyValueWriter, tx *Transaction) error {
+// PutTombstone records that the value linked to [txID] was deleted or
+// overwritten in the block at [height], so it can be reclaimed by
+// [ReclaimNext].
+func PutTombstone(db database.KeyValueWriter, height uint64, txID ids.ID, size uint64) error {
+	v := make([]byte, 8)
+	binary.BigEndian.PutUint64(v, size)
+	return db.Put(PrefixTombstoneKey(height, txID), v)
+}
+
+// ReclaimNext deletes up to [limit] values whose tombstones were recorded at
+// or below [height]. It returns the number of values reclaimed and their total
+// size. A value set by several txs is only deleted once the last of them is
+// reclaimed. Values preloaded by the genesis are linked by their hash (and may
+// be shared by several keys), so only their tombstones are deleted.
+//
+// Reclaimed values can no longer be restored in the blocks that set them.
+func ReclaimNext(db database.Database, height uint64, limit int) (values int, valueBytes uint64, err error) {
+	cursor := db.NewIteratorWithPrefix([]byte{tombPrefix, parser.ByteDelimiter})
+	defer cursor.Release()
+	for values < limit && cursor.Next() {
+		// [tombPrefix] + [delimiter] + [height] + [txID]
+		curKey := cursor.Key()
+		if len(curKey) < 2+8 || len(cursor.Value()) != 8 {
+			return values, valueBytes, ErrInvalidTombstone
+		}
+		if binary.BigEndian.Uint64(curKey[2:]) > height {
+			break
+		}
+		txID, err := ids.ToID(curKey[2+8:])
+		if err != nil {
+			return values, valueBytes, err
+		}
+		deleted, err := deleteTxValue(db, txID)
+		if err != nil {
+			return values, valueBytes, err
+		}
+		if deleted {
+			linkedTxCache.Evict(string(txID[:]))
+			values++
+			valueBytes += binary.BigEndian.Uint64(cursor.Value())
+		}
+		if err := db.Delete(curKey); err != nil {
+			return values, valueBytes, err
+		}
+	}
+	return values, valueBytes, cursor.Error()
+}
+
+func SetTransaction(db database.KeyValueWriter, tx *Transaction, blockID ids.ID) error {
 	k := PrefixTxKey(tx.ID())
-	return db.Put(k, nil)
+	return db.Put(k, blockID[:])
 }
 
 func HasTransaction(db database.KeyValueReader, txID ids.ID) (bool, error) {
@@ -642,12 +1167,50 @@ func HasTransaction(db database.KeyValueReader, txID ids.ID) (bool, error) {
 	return db.Has(k)
 }
 
+// GetTransactionBlock returns the ID of the block that accepted [txID], which
+// is [ids.Empty] if it was accepted before blocks were indexed.
+func GetTransactionBlock(db database.KeyValueReader, txID ids.ID) (ids.ID, bool, error) {
+	v, err := db.Get(PrefixTxKey(txID))
+	if errors.Is(err, database.ErrNotFound) {
+		return ids.Empty, false, nil
+	}
+	if err != nil {
+		return ids.Empty, false, err
+	}
+	if len(v) == 0 {
+		return ids.Empty, true, nil
+	}
+	id, err := ids.ToID(v)
+	return id, true, err
+}
+
+// uncachedDB is a database whose linked values are read directly instead of
+// through [linkedTxCache]
+type uncachedDB struct {
+	database.Database
+}
+
+// Uncached wraps [db] so values read from it bypass the process-wide linked
+// value cache. Use it for state that is never committed (ex: pending
+// transactions overlaid on accepted state), whose values must not be cached
+// alongside accepted ones.
+func Uncached(db database.Database) database.Database {
+	return &uncachedDB{db}
+}
+
 func getLinkedValue(db database.KeyValueReader, b []byte) ([]byte, error) {
+	if _, ok := db.(*uncachedDB); ok {
+		txID, err := ids.ToID(b)
+		if err != nil {
+			return nil, err
+		}
+		return getTxValue(db, txID)
+	}
 	bh := string(b)
 	if v, ok := linkedTxCache.Get(bh); ok {
 		bytes, ok := v.([]byte)
 		if !ok {
-			return nil, fmt.Errorf("expected []byte but got %T", v)
+			return nil, fmt.Errorf("%w: expected []byte but got %T", ErrInvalidCacheEntry, v)
 		}
 		return bytes, nil
 	}
@@ -655,8 +1218,7 @@ func getLinkedValue(db database.KeyValueReader, b []byte) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
-	vk := PrefixTxValueKey(txID)
-	v, err := db.Get(vk)
+	v, err := getTxValue(db, txID)
 	if err != nil {
 		return nil, err
 	}
@@ -772,6 +1334,125 @@ func GetAllOwned(db database.Database, owner common.Address) (spaces []string, e
 	return spaces, cursor.Error()
 }
 
+// ListOwned returns up to [limit] spaces owned by [owner] in order,
+// continuing from [cursor] (see [Cursor]). The returned cursor is empty when
+// there are no more spaces.
+func ListOwned(db database.Iteratee, owner common.Address, cursor string, limit int, height uint64) ([]string, string, error) {
+	c, err := ParseCursor(cursor, height)
+	if err != nil {
+		return nil, "", err
+	}
+	spaces := []string{}
+	next, err := paginate(db, PrefixOwnedKey(owner, nil), c, limit, nil, func(space []byte, _ []byte) error {
+		spaces = append(spaces, string(space))
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return spaces, next, nil
+}
+
+// HasImported returns true if the atomic UTXO [utxoID] was imported by an
+// accepted (or ancestor) block
+func HasImported(db database.KeyValueReader, utxoID ids.ID) (bool, error) {
+	return db.Has(PrefixImportedKey(utxoID))
+}
+
+// SetImported marks the atomic UTXO [utxoID] as imported, so it can't be
+// imported again before it is removed from shared memory
+func SetImported(db database.KeyValueWriter, utxoID ids.ID) error {
+	return db.Put(PrefixImportedKey(utxoID), nil)
+}
+
+// GetCommitment returns the timestamp of the block that accepted
+// [commitment], if it has not been revealed
+func GetCommitment(db database.KeyValueReader, commitment common.Hash) (uint64, bool, error) {
+	v, err := db.Get(PrefixCommitmentKey(commitment))
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return binary.BigEndian.Uint64(v), true, nil
+}
+
+// PutCommitment records that [commitment] was made in a block with timestamp
+// [t]
+func PutCommitment(db database.KeyValueWriter, commitment common.Hash, t uint64) error {
+	v := make([]byte, 8)
+	binary.BigEndian.PutUint64(v, t)
+	return db.Put(PrefixCommitmentKey(commitment), v)
+}
+
+// DeleteCommitment removes a revealed [commitment]
+func DeleteCommitment(db database.KeyValueDeleter, commitment common.Hash) error {
+	return db.Delete(PrefixCommitmentKey(commitment))
+}
+
+// GetFreeWrites returns the number of free writes [space] made in [window]
+func GetFreeWrites(db database.KeyValueReader, space []byte, window uint64) (uint64, error) {
+	v, err := db.Get(PrefixFreeWritesKey(space))
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if binary.BigEndian.Uint64(v) != window {
+		return 0, nil
+	}
+	return binary.BigEndian.Uint64(v[8:]), nil
+}
+
+// PutFreeWrites records that [space] made [writes] free writes in [window]
+func PutFreeWrites(db database.KeyValueWriter, space []byte, window uint64, writes uint64) error {
+	v := make([]byte, 16)
+	binary.BigEndian.PutUint64(v, window)
+	binary.BigEndian.PutUint64(v[8:], writes)
+	return db.Put(PrefixFreeWritesKey(space), v)
+}
+
+// HasApproval returns true if [coSigner] approved [action] and it has not
+// been used
+func HasApproval(db database.KeyValueReader, action common.Hash, coSigner common.Address) (bool, error) {
+	return db.Has(PrefixApprovalKey(action, coSigner))
+}
+
+// PutApproval records that [coSigner] approved [action]
+func PutApproval(db database.KeyValueWriter, action common.Hash, coSigner common.Address) error {
+	return db.Put(PrefixApprovalKey(action, coSigner), nil)
+}
+
+// DeleteApproval removes a used approval
+func DeleteApproval(db database.KeyValueDeleter, action common.Hash, coSigner common.Address) error {
+	return db.Delete(PrefixApprovalKey(action, coSigner))
+}
+
+// PutWarpMessage stores the warp message emitted by [txID]
+func PutWarpMessage(db database.KeyValueWriter, txID ids.ID, m *WarpMessage) error {
+	b, err := Marshal(m)
+	if err != nil {
+		return err
+	}
+	return db.Put(PrefixWarpKey(txID), b)
+}
+
+// GetWarpMessage returns the warp message emitted by [txID], if any
+func GetWarpMessage(db database.KeyValueReader, txID ids.ID) (*WarpMessage, bool, error) {
+	v, err := db.Get(PrefixWarpKey(txID))
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	m := new(WarpMessage)
+	_, err = Unmarshal(v, m)
+	return m, true, err
+}
+
 func CompactablePrefixKey(pfx byte) []byte {
 	return []byte{pfx, parser.ByteDelimiter}
 }
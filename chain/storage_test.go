@@ -8,7 +8,9 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/versiondb"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ava-labs/spacesvm/parser"
 	"github.com/ethereum/go-ethereum/common"
@@ -99,6 +101,45 @@ func TestPrefixBlockKey(t *testing.T) {
 	}
 }
 
+func TestPrefixBlockHeightKey(t *testing.T) {
+	t.Parallel()
+
+	k := PrefixBlockHeightKey(258)
+	expected := []byte{heightPrefix, parser.ByteDelimiter, 0, 0, 0, 0, 0, 0, 1, 2}
+	if !bytes.Equal(expected, k) {
+		t.Fatalf("value expected %q, got %q", expected, k)
+	}
+}
+
+func TestGetTransactionBlock(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.New()
+	tx := &Transaction{id: ids.GenerateTestID()}
+	if _, found, err := GetTransactionBlock(db, tx.ID()); err != nil || found {
+		t.Fatalf("unexpected tx found=%t err=%v", found, err)
+	}
+
+	blkID := ids.GenerateTestID()
+	if err := SetTransaction(db, tx, blkID); err != nil {
+		t.Fatal(err)
+	}
+	id, found, err := GetTransactionBlock(db, tx.ID())
+	if err != nil || !found || id != blkID {
+		t.Fatalf("expected block %s, got %s (found=%t err=%v)", blkID, id, found, err)
+	}
+
+	// Transactions accepted before blocks were indexed
+	legacy := ids.GenerateTestID()
+	if err := db.Put(PrefixTxKey(legacy), nil); err != nil {
+		t.Fatal(err)
+	}
+	id, found, err = GetTransactionBlock(db, legacy)
+	if err != nil || !found || id != ids.Empty {
+		t.Fatalf("expected unknown block, got %s (found=%t err=%v)", id, found, err)
+	}
+}
+
 func TestPutSpaceInfoAndKey(t *testing.T) {
 	t.Parallel()
 
@@ -115,7 +156,7 @@ func TestPutSpaceInfoAndKey(t *testing.T) {
 	if ok, err := HasSpaceKey(db, spc, k); ok || err != nil {
 		t.Fatalf("unexpected ok %v, err %v", ok, err)
 	}
-	if err := PutSpaceKey(db, spc, k, v); !errors.Is(err, ErrSpaceMissing) {
+	if err := PutSpaceKey(db, spc, k, v, common.Hash{}); !errors.Is(err, ErrSpaceMissing) {
 		t.Fatalf("unexpected error %v, expected %v", err, ErrSpaceMissing)
 	}
 
@@ -129,7 +170,7 @@ func TestPutSpaceInfoAndKey(t *testing.T) {
 	); err != nil {
 		t.Fatal(err)
 	}
-	if err := PutSpaceKey(db, spc, k, v); err != nil {
+	if err := PutSpaceKey(db, spc, k, v, common.Hash{}); err != nil {
 		t.Fatalf("unexpected error %v", err)
 	}
 
@@ -337,7 +378,7 @@ func TestGetAllValueMetas(t *testing.T) {
 	for i, tv := range tt {
 		if i > 0 {
 			// Expire old spaces between txs
-			if err := ExpireNext(db, tt[i-1].blockTime, tv.blockTime, true); err != nil {
+			if err := ExpireNext(db, tt[i-1].blockTime, tv.blockTime, ExpiryBudget); err != nil {
 				t.Fatalf("#%d: ExpireNext errored %v", i, err)
 			}
 		}
@@ -352,7 +393,7 @@ func TestGetAllValueMetas(t *testing.T) {
 		}
 		tc := &TransactionContext{
 			Genesis:   g,
-			Database:  db,
+			State:     NewStateDB(db),
 			BlockTime: uint64(tv.blockTime),
 			TxID:      id,
 			Sender:    tv.sender,
@@ -391,3 +432,141 @@ func TestGetAllValueMetas(t *testing.T) {
 		}
 	}
 }
+
+func TestReclaimNext(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.New()
+	txs := []*Transaction{
+		{id: ids.GenerateTestID()},
+		{id: ids.GenerateTestID()},
+		{id: ids.GenerateTestID()},
+	}
+	for i, tx := range txs {
+		if err := SetTransaction(db, tx, ids.GenerateTestID()); err != nil {
+			t.Fatal(err)
+		}
+		if err := db.Put(PrefixTxValueKey(tx.ID()), []byte("value")); err != nil {
+			t.Fatal(err)
+		}
+		if err := PutTombstone(db, uint64(i+1), tx.ID(), 5); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Genesis values are linked by their hash
+	genesis := ids.ID(crypto.Keccak256Hash([]byte("value")))
+	if err := db.Put(PrefixTxValueKey(genesis), []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	if err := PutTombstone(db, 1, genesis, 5); err != nil {
+		t.Fatal(err)
+	}
+
+	values, valueBytes, err := ReclaimNext(db, 2, 1)
+	if err != nil || values != 1 || valueBytes != 5 {
+		t.Fatalf("unexpected reclaim values=%d bytes=%d err=%v", values, valueBytes, err)
+	}
+	values, valueBytes, err = ReclaimNext(db, 2, 10)
+	if err != nil || values != 1 || valueBytes != 5 {
+		t.Fatalf("unexpected reclaim values=%d bytes=%d err=%v", values, valueBytes, err)
+	}
+	for i, tx := range txs {
+		has, err := db.Has(PrefixTxValueKey(tx.ID()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reclaimed := i < 2; has == reclaimed {
+			t.Fatalf("#%d: value exists=%t after reclaiming height 2", i, has)
+		}
+	}
+	if has, err := db.Has(PrefixTxValueKey(genesis)); err != nil || !has {
+		t.Fatalf("genesis value should be kept (err=%v)", err)
+	}
+	if has, err := db.Has(PrefixTombstoneKey(1, genesis)); err != nil || has {
+		t.Fatalf("genesis tombstone should be deleted (err=%v)", err)
+	}
+	if has, err := db.Has(PrefixTombstoneKey(3, txs[2].ID())); err != nil || !has {
+		t.Fatalf("tombstone above height should be kept (err=%v)", err)
+	}
+}
+
+func TestReclaimDeduplicatedValues(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.New()
+	value := []byte("shared asset")
+	h := ValueHash(value)
+	txs := []ids.ID{ids.GenerateTestID(), ids.GenerateTestID()}
+	for i, txID := range txs {
+		if err := putTxValue(db, txID, value); err != nil {
+			t.Fatal(err)
+		}
+		// Linking the same tx again must not add a reference
+		if err := putTxValue(db, txID, value); err != nil {
+			t.Fatal(err)
+		}
+		if err := PutTombstone(db, uint64(i+1), txID, uint64(len(value))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if refs, err := getValueRefs(db, h); err != nil || refs != 2 {
+		t.Fatalf("unexpected references=%d (err=%v)", refs, err)
+	}
+	for _, txID := range txs {
+		v, err := getTxValue(db, txID)
+		if err != nil || !bytes.Equal(v, value) {
+			t.Fatalf("unexpected value %q (err=%v)", v, err)
+		}
+	}
+
+	// The content is kept while any tx still references it
+	values, _, err := ReclaimNext(db, 1, 10)
+	if err != nil || values != 1 {
+		t.Fatalf("unexpected reclaim values=%d err=%v", values, err)
+	}
+	if _, err := getTxValue(db, txs[0]); !errors.Is(err, database.ErrNotFound) {
+		t.Fatalf("reclaimed value should not be found (err=%v)", err)
+	}
+	if v, err := getTxValue(db, txs[1]); err != nil || !bytes.Equal(v, value) {
+		t.Fatalf("shared value should be kept %q (err=%v)", v, err)
+	}
+
+	values, _, err = ReclaimNext(db, 2, 10)
+	if err != nil || values != 1 {
+		t.Fatalf("unexpected reclaim values=%d err=%v", values, err)
+	}
+	for _, k := range [][]byte{prefixContentKey(h), prefixRefKey(h), prefixValueLinkKey(txs[1])} {
+		if has, err := db.Has(k); err != nil || has {
+			t.Fatalf("%x should be deleted once unreferenced (err=%v)", k, err)
+		}
+	}
+}
+
+func TestUncachedLinkedValues(t *testing.T) {
+	t.Parallel()
+
+	db := versiondb.New(memdb.New())
+	tx := &Transaction{
+		UnsignedTransaction: &SetTx{BaseTx: &BaseTx{}, Space: "foo", Key: "bar", Value: []byte("pending")},
+		id:                  ids.GenerateTestID(),
+	}
+	if err := PutLinkedValue(db, tx); err != nil {
+		t.Fatal(err)
+	}
+	v, err := getLinkedValue(Uncached(db), tx.id[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(v, []byte("pending")) {
+		t.Fatalf("unexpected value %q", v)
+	}
+	if _, ok := linkedTxCache.Get(string(tx.id[:])); ok {
+		t.Fatal("uncached value should not be cached")
+	}
+	if _, err := getLinkedValue(db, tx.id[:]); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := linkedTxCache.Get(string(tx.id[:])); !ok {
+		t.Fatal("value should be cached")
+	}
+}
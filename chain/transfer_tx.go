@@ -36,10 +36,10 @@ func (t *TransferTx) Execute(c *TransactionContext) error {
 	if t.Units == 0 {
 		return ErrNonActionable
 	}
-	if _, err := ModifyBalance(c.Database, c.Sender, false, t.Units); err != nil {
+	if _, err := c.State.ModifyBalance(c.Sender, false, t.Units); err != nil {
 		return err
 	}
-	if _, err := ModifyBalance(c.Database, t.To, true, t.Units); err != nil {
+	if _, err := c.State.ModifyBalance(t.To, true, t.Units); err != nil {
 		return err
 	}
 	return nil
@@ -57,7 +57,7 @@ func (t *TransferTx) Copy() UnsignedTransaction {
 
 func (t *TransferTx) TypedData() *tdata.TypedData {
 	return tdata.CreateTypedData(
-		t.Magic, Transfer,
+		t.Magic, t.typedChainID(), Transfer,
 		[]tdata.Type{
 			{Name: tdTo, Type: tdAddress},
 			{Name: tdUnits, Type: tdUint64},
@@ -113,7 +113,7 @@ func TestTransferTx(t *testing.T) {
 	for i, tv := range tt {
 		tc := &TransactionContext{
 			Genesis:   g,
-			Database:  db,
+			State:     NewStateDB(db),
 			BlockTime: tv.blockTime,
 			TxID:      ids.Empty,
 			Sender:    tv.sender,
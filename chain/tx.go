@@ -20,6 +20,8 @@ type Transaction struct {
 	id         ids.ID
 	size       uint64
 	sender     common.Address
+	// shortSender owns the sender's UTXOs on the X and P chains
+	shortSender ids.ShortID
 }
 
 func NewTx(utx UnsignedTransaction, sig []byte) *Transaction {
@@ -68,6 +70,7 @@ func (t *Transaction) Init(g *Genesis) error {
 		return err
 	}
 	t.sender = crypto.PubkeyToAddress(*pk)
+	t.shortSender = ShortAddress(pk)
 
 	t.size = uint64(len(t.Bytes()))
 	return nil
@@ -87,36 +90,48 @@ func (t *Transaction) Execute(g *Genesis, db database.Database, blk *StatelessBl
 	if err := t.UnsignedTransaction.ExecuteBase(g); err != nil {
 		return err
 	}
-	if !context.RecentBlockIDs.Contains(t.GetBlockID()) {
-		// Hash must be recent to be any good
-		// Should not happen beause of mempool cleanup
-		return ErrInvalidBlockID
+	if t.GetChainID() != context.ChainID {
+		// Signed for a different chain
+		return ErrInvalidChainID
 	}
-	if context.RecentTxIDs.Contains(t.ID()) {
-		// Tx hash must not be recently executed (otherwise could be replayed)
-		//
-		// NOTE: We only need to keep cached tx hashes around as long as the
-		// block hash referenced in the tx is valid
-		return ErrDuplicateTx
+	if !context.Bootstrapping {
+		if !context.RecentBlockIDs.Contains(t.GetBlockID()) {
+			// Hash must be recent to be any good
+			// Should not happen beause of mempool cleanup
+			return ErrInvalidBlockID
+		}
+		if context.RecentTxIDs.Contains(t.ID()) {
+			// Tx hash must not be recently executed (otherwise could be replayed)
+			//
+			// NOTE: We only need to keep cached tx hashes around as long as the
+			// block hash referenced in the tx is valid
+			return ErrDuplicateTx
+		}
 	}
 
-	// Ensure sender has balance
-	if _, err := ModifyBalance(db, t.sender, false, t.FeeUnits(g)*t.GetPrice()); err != nil {
-		return err
-	}
 	if t.GetPrice() < context.NextPrice {
 		return ErrInsufficientPrice
 	}
+	state := NewStateDB(db)
 	if err := t.UnsignedTransaction.Execute(&TransactionContext{
-		Genesis:   g,
-		Database:  db,
-		BlockTime: uint64(blk.Tmstmp),
-		TxID:      t.id,
-		Sender:    t.sender,
+		Genesis:       g,
+		State:         state,
+		BlockTime:     uint64(blk.Tmstmp),
+		BlockHeight:   blk.Hght,
+		TxID:          t.id,
+		Sender:        t.sender,
+		SenderShortID: t.shortSender,
+		Price:         t.GetPrice(),
+		Atomic:        context.Atomic,
 	}); err != nil {
 		return err
 	}
-	if err := SetTransaction(db, t); err != nil {
+	// Ensure sender has balance (charged after execution so imports can pay
+	// their fee with the units they import)
+	if _, err := state.ModifyBalance(t.sender, false, t.FeeUnits(g)*t.GetPrice()); err != nil {
+		return err
+	}
+	if err := SetTransaction(db, t, blk.ID()); err != nil {
 		return err
 	}
 
@@ -0,0 +1,127 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"crypto/ecdsa"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TxParams are the values of the chain every transaction must reference.
+type TxParams struct {
+	Genesis *Genesis
+	ChainID ids.ID
+	// BlockID must be a block in the lookback window (usually the last
+	// accepted block)
+	BlockID ids.ID
+	// Price and BlockCost are the suggested fee. The block cost is spread
+	// across the fee units of each transaction.
+	Price     uint64
+	BlockCost uint64
+}
+
+// Prepare sets the BlockID, Magic, ChainID, and Price of [utx].
+func (p *TxParams) Prepare(utx UnsignedTransaction) {
+	utx.SetBlockID(p.BlockID)
+	utx.SetMagic(p.Genesis.Magic)
+	utx.SetChainID(p.ChainID)
+	utx.SetPrice(p.Price + p.BlockCost/utx.FeeUnits(p.Genesis))
+}
+
+// Build prepares [utx] and computes its digest hash. [utx] must not be
+// modified once built.
+func (p *TxParams) Build(utx UnsignedTransaction) (*UnsignedTx, error) {
+	p.Prepare(utx)
+	dh, err := DigestHash(utx)
+	if err != nil {
+		return nil, err
+	}
+	return &UnsignedTx{UnsignedTransaction: utx, Digest: dh, g: p.Genesis}, nil
+}
+
+// UnsignedTx is a transaction that is ready to be signed.
+type UnsignedTx struct {
+	UnsignedTransaction
+	// Digest is the EIP-712 hash of the transaction that the sender signs
+	Digest []byte
+
+	g *Genesis
+}
+
+// Cost is the maximum fee the transaction pays.
+func (u *UnsignedTx) Cost() uint64 {
+	return u.GetPrice() * u.FeeUnits(u.g)
+}
+
+// Sign signs the transaction with [priv].
+func (u *UnsignedTx) Sign(priv *ecdsa.PrivateKey) (*Transaction, error) {
+	sig, err := Sign(u.Digest, priv)
+	if err != nil {
+		return nil, err
+	}
+	return u.WithSignature(sig)
+}
+
+// WithSignature returns the transaction signed by [sig] (such as one produced
+// by a hardware wallet over [Digest]). The transaction's Bytes are ready to
+// be issued.
+func (u *UnsignedTx) WithSignature(sig []byte) (*Transaction, error) {
+	tx := NewTx(u.UnsignedTransaction, sig)
+	if err := tx.Init(u.g); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// NewClaimTx builds a claim of [space] for the standard lease.
+func NewClaimTx(p *TxParams, space string) (*UnsignedTx, error) {
+	return p.Build(&ClaimTx{BaseTx: &BaseTx{}, Space: space})
+}
+
+// NewLifelineTx builds a lifeline that extends [space] with [units].
+func NewLifelineTx(p *TxParams, space string, units uint64) (*UnsignedTx, error) {
+	return p.Build(&LifelineTx{BaseTx: &BaseTx{}, Space: space, Units: units})
+}
+
+// NewSetTx builds a set of [key] in [space] to [value].
+func NewSetTx(p *TxParams, space string, key string, value []byte) (*UnsignedTx, error) {
+	return p.Build(&SetTx{BaseTx: &BaseTx{}, Space: space, Key: key, Value: value})
+}
+
+// NewDeleteTx builds a delete of [key] in [space].
+func NewDeleteTx(p *TxParams, space string, key string) (*UnsignedTx, error) {
+	return p.Build(&DeleteTx{BaseTx: &BaseTx{}, Space: space, Key: key})
+}
+
+// NewMoveTx builds an immediate move of [space] to [to].
+func NewMoveTx(p *TxParams, space string, to common.Address) (*UnsignedTx, error) {
+	return p.Build(&MoveTx{BaseTx: &BaseTx{}, Space: space, To: to})
+}
+
+// NewTransferTx builds a transfer of [units] to [to].
+func NewTransferTx(p *TxParams, to common.Address, units uint64) (*UnsignedTx, error) {
+	return p.Build(&TransferTx{BaseTx: &BaseTx{}, To: to, Units: units})
+}
+
+// NewBurnTx builds a burn of [space] without a claim cooldown.
+func NewBurnTx(p *TxParams, space string) (*UnsignedTx, error) {
+	return p.Build(&BurnTx{BaseTx: &BaseTx{}, Space: space})
+}
+
+// NewFreezeTx builds a freeze of the keys of [space] starting with [prefix].
+func NewFreezeTx(p *TxParams, space string, prefix string) (*UnsignedTx, error) {
+	return p.Build(&FreezeTx{BaseTx: &BaseTx{}, Space: space, Prefix: prefix})
+}
+
+// NewCommitTx builds a commitment to a future claim (see [ClaimCommitment]).
+func NewCommitTx(p *TxParams, commitment common.Hash) (*UnsignedTx, error) {
+	return p.Build(&CommitTx{BaseTx: &BaseTx{}, Commitment: commitment})
+}
+
+// NewApproveTx builds a co-signer approval of [action] (see [ActionHash]).
+func NewApproveTx(p *TxParams, action common.Hash) (*UnsignedTx, error) {
+	return p.Build(&ApproveTx{BaseTx: &BaseTx{}, Action: action})
+}
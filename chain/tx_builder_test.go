@@ -0,0 +1,75 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestTxBuilder(t *testing.T) {
+	t.Parallel()
+
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := crypto.PubkeyToAddress(priv.PublicKey)
+
+	g := DefaultGenesis()
+	p := &TxParams{
+		Genesis:   g,
+		ChainID:   ids.GenerateTestID(),
+		BlockID:   ids.GenerateTestID(),
+		Price:     2,
+		BlockCost: 1000,
+	}
+	utx, err := NewSetTx(p, "foo", "bar", []byte("baz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if utx.GetBlockID() != p.BlockID || utx.GetChainID() != p.ChainID || utx.GetMagic() != g.Magic {
+		t.Fatal("base tx not populated")
+	}
+	units := utx.FeeUnits(g)
+	if price := p.Price + p.BlockCost/units; utx.GetPrice() != price {
+		t.Fatalf("expected price %d, got %d", price, utx.GetPrice())
+	}
+	if utx.Cost() != utx.GetPrice()*units {
+		t.Fatalf("unexpected cost %d", utx.Cost())
+	}
+	dh, err := DigestHash(utx.UnsignedTransaction)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(utx.Digest, dh) {
+		t.Fatal("unexpected digest")
+	}
+
+	tx, err := utx.Sign(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Sender() != sender {
+		t.Fatalf("expected sender %s, got %s", sender, tx.Sender())
+	}
+	// Signatures produced elsewhere over the digest are accepted
+	sig, err := Sign(utx.Digest, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stx, err := utx.WithSignature(sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stx.ID() != tx.ID() || !bytes.Equal(stx.Bytes(), tx.Bytes()) {
+		t.Fatal("transactions signed over the digest do not match")
+	}
+	if _, err := utx.WithSignature([]byte{1}); err != ErrInvalidSignature {
+		t.Fatalf("expected %v, got %v", ErrInvalidSignature, err)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// TxJSON is the stable JSON encoding of an unsigned transaction, so that
+// wallets can build transactions in any language:
+//
+//	{"type":"set","tx":{"baseTx":{...},"space":"foo","key":"bar","value":"0x..."}}
+//
+// [Type] is the type of the transaction (see [Input]) and [Tx] holds its
+// fields, named by their json tags. Byte fields are hex encoded and unknown
+// fields are rejected.
+type TxJSON struct {
+	UnsignedTransaction
+}
+
+type txEnvelope struct {
+	Type string          `json:"type"`
+	Tx   json.RawMessage `json:"tx"`
+}
+
+// setTxJSON hex encodes the value of a [SetTx]
+type setTxJSON struct {
+	*SetTx
+	Value hexutil.Bytes `json:"value"`
+}
+
+// claimTxJSON hex encodes the values of the keys of a [ClaimTx]
+type claimTxJSON struct {
+	*ClaimTx
+	Keys []*claimKeyJSON `json:"keys"`
+}
+
+type claimKeyJSON struct {
+	Key   string        `json:"key"`
+	Value hexutil.Bytes `json:"value"`
+}
+
+// MarshalTxJSON encodes [utx] as a [TxJSON].
+func MarshalTxJSON(utx UnsignedTransaction) ([]byte, error) {
+	return json.Marshal(&TxJSON{UnsignedTransaction: utx})
+}
+
+// UnmarshalTxJSON decodes a [TxJSON] into the transaction it encodes.
+func UnmarshalTxJSON(b []byte) (UnsignedTransaction, error) {
+	t := new(TxJSON)
+	if err := json.Unmarshal(b, t); err != nil {
+		return nil, err
+	}
+	return t.UnsignedTransaction, nil
+}
+
+func (t *TxJSON) MarshalJSON() ([]byte, error) {
+	if t.UnsignedTransaction == nil {
+		return nil, fmt.Errorf("%w: transaction is nil", ErrInvalidType)
+	}
+	var tx interface{} = t.UnsignedTransaction
+	switch u := tx.(type) {
+	case *SetTx:
+		tx = &setTxJSON{SetTx: u, Value: u.Value}
+	case *ClaimTx:
+		c := &claimTxJSON{ClaimTx: u}
+		for _, ck := range u.Keys {
+			c.Keys = append(c.Keys, &claimKeyJSON{Key: ck.Key, Value: ck.Value})
+		}
+		tx = c
+	}
+	b, err := json.Marshal(tx)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&txEnvelope{Type: t.TypedData().PrimaryType, Tx: b})
+}
+
+func (t *TxJSON) UnmarshalJSON(b []byte) error {
+	env := new(txEnvelope)
+	if err := strictUnmarshal(b, env); err != nil {
+		return err
+	}
+	utx, err := (&Input{Typ: env.Type}).Decode()
+	if err != nil {
+		return fmt.Errorf("%w: %q", err, env.Type)
+	}
+	var dst interface{} = utx
+	switch u := utx.(type) {
+	case *SetTx:
+		dst = &setTxJSON{SetTx: u}
+	case *ClaimTx:
+		dst = &claimTxJSON{ClaimTx: u}
+	}
+	if len(env.Tx) > 0 {
+		fields := map[string]json.RawMessage{}
+		if err := json.Unmarshal(env.Tx, &fields); err != nil {
+			return err
+		}
+		// Every transaction must have a (possibly empty) base
+		if base, ok := fields["baseTx"]; ok && bytes.Equal(base, []byte("null")) {
+			return fmt.Errorf("%w: baseTx is null", ErrInvalidType)
+		}
+		if err := strictUnmarshal(env.Tx, dst); err != nil {
+			return err
+		}
+	}
+	switch d := dst.(type) {
+	case *setTxJSON:
+		d.SetTx.Value = d.Value
+	case *claimTxJSON:
+		for _, ck := range d.Keys {
+			if ck == nil {
+				return fmt.Errorf("%w: key is null", ErrInvalidType)
+			}
+			d.ClaimTx.Keys = append(d.ClaimTx.Keys, &CustomKey{Key: ck.Key, Value: ck.Value})
+		}
+	}
+	t.UnsignedTransaction = utx
+	return nil
+}
+
+func strictUnmarshal(b []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestTxJSON(t *testing.T) {
+	t.Parallel()
+
+	base := &BaseTx{BlockID: ids.GenerateTestID(), Magic: 1, Price: 2, ChainID: ids.GenerateTestID()}
+	to := common.HexToAddress("0x8db97C7cEcE249c2b98bDC0226Cc4C2A57BF52FC")
+	utxs := []UnsignedTransaction{
+		&ClaimTx{BaseTx: base, Space: "foo", Beneficiary: to, Lease: 2, Salt: common.HexToHash("0x01")},
+		&ClaimTx{BaseTx: base, Space: "foo", Keys: []*CustomKey{{Key: "bar", Value: []byte{0, 1}}}},
+		&LifelineTx{BaseTx: base, Space: "foo", Units: 1, Extension: 10},
+		&SetTx{BaseTx: base, Space: "foo", Key: "bar", Value: []byte{0, 1, 2}, Broadcast: true, Kind: "json"},
+		&DeleteTx{BaseTx: base, Space: "foo", Key: "bar"},
+		&MoveTx{BaseTx: base, Space: "foo", To: to, Activation: 5},
+		&TransferTx{BaseTx: base, To: to, Units: 3},
+		&ImportTx{BaseTx: base, SourceChain: ids.GenerateTestID(), UTXOID: ids.GenerateTestID()},
+		&ExportTx{BaseTx: base, DestinationChain: ids.GenerateTestID(), To: ids.GenerateTestShortID(), Units: 3},
+		&CommitTx{BaseTx: base, Commitment: common.HexToHash("0x02")},
+		&PolicyTx{BaseTx: base, Space: "foo", Policy: SpacePolicy{Ops: []string{Set}, CoSigner: to}},
+		&ApproveTx{BaseTx: base, Action: common.HexToHash("0x03")},
+		&BurnTx{BaseTx: base, Space: "foo", Cooldown: 60},
+		&FreezeTx{BaseTx: base, Space: "foo", Prefix: "bar", Unfreeze: true},
+	}
+	for _, utx := range utxs {
+		b, err := MarshalTxJSON(utx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dtx, err := UnmarshalTxJSON(b)
+		if err != nil {
+			t.Fatalf("%s: %v", b, err)
+		}
+		if !reflect.DeepEqual(utx, dtx) {
+			t.Fatalf("%s did not round trip: %+v", b, dtx)
+		}
+	}
+
+	b, err := MarshalTxJSON(&SetTx{BaseTx: &BaseTx{}, Space: "patrick", Key: "twitter", Value: []byte("@pzywek")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Byte fields are hex encoded
+	expected := `{"type":"set","tx":{"baseTx":{"blockId":"11111111111111111111111111111111LpoYY","magic":0,"price":0,"chainId":"11111111111111111111111111111111LpoYY"},"space":"patrick","key":"twitter","broadcast":false,"kind":"","value":"0x40707a7977656b"}}`
+	if string(b) != expected {
+		t.Fatalf("unexpected encoding %s", b)
+	}
+
+	// Omitted fields are empty
+	utx, err := UnmarshalTxJSON([]byte(`{"type":"transfer","tx":{"units":1}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx, ok := utx.(*TransferTx); !ok || tx.Units != 1 || tx.BaseTx == nil {
+		t.Fatalf("unexpected tx %+v", utx)
+	}
+
+	for _, invalid := range []string{
+		`{"type":"reward","tx":{}}`,
+		`{"type":"transfer","tx":{"amount":1}}`,
+		`{"type":"transfer","tx":{"baseTx":null}}`,
+		`{"type":"set","tx":{"value":"AAE="}}`,
+		`{"type":"set","tx":{},"signature":"0x"}`,
+	} {
+		if _, err := UnmarshalTxJSON([]byte(invalid)); err == nil {
+			t.Fatalf("%s should not decode", invalid)
+		}
+	}
+	if _, err := UnmarshalTxJSON([]byte(`{"type":"reward"}`)); !errors.Is(err, ErrInvalidType) {
+		t.Fatalf("expected %v, got %v", ErrInvalidType, err)
+	}
+}
@@ -4,6 +4,7 @@
 package chain
 
 import (
+	"bytes"
 	"crypto/ecdsa"
 	"errors"
 	"fmt"
@@ -54,6 +55,33 @@ func TestTransaction(t *testing.T) {
 	}
 }
 
+func TestTransactionDigestChainID(t *testing.T) {
+	t.Parallel()
+
+	utx := &ClaimTx{BaseTx: &BaseTx{ChainID: ids.ID{1}}, Space: "a"}
+	dh, err := DigestHash(utx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	utx2 := utx.Copy()
+	utx2.SetChainID(ids.ID{2})
+	dh2, err := DigestHash(utx2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(dh, dh2) {
+		t.Fatal("digest should depend on chain ID")
+	}
+
+	parsed, err := ParseTypedData(utx2.TypedData())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.GetChainID() != (ids.ID{2}) {
+		t.Fatalf("unexpected chain ID %s", parsed.GetChainID())
+	}
+}
+
 func TestTransactionErrInvalidSignature(t *testing.T) {
 	t.Parallel()
 
@@ -92,6 +120,44 @@ func TestTransactionErrInvalidSignature(t *testing.T) {
 			ctx:        &Context{RecentBlockIDs: ids.Set{{0, 1}: struct{}{}}},
 			executeErr: ErrInvalidBalance,
 		},
+		{
+			createTx: func() *Transaction {
+				return createTestTx(t, ids.ID{0, 1}, priv)
+			},
+			blockTime: 1,
+			ctx: &Context{
+				ChainID:        ids.ID{1},
+				RecentBlockIDs: ids.Set{{0, 1}: struct{}{}},
+			},
+			executeErr: ErrInvalidChainID,
+		},
+		{
+			// decided blocks are not checked against recent blocks
+			createTx: func() *Transaction {
+				return createTestTx(t, ids.ID{0, 1}, priv)
+			},
+			blockTime:  1,
+			ctx:        &Context{Bootstrapping: true},
+			executeErr: nil,
+		},
+		{
+			// but must still be bound to this chain
+			createTx: func() *Transaction {
+				return createTestTx(t, ids.ID{0, 1}, priv)
+			},
+			blockTime:  1,
+			ctx:        &Context{ChainID: ids.ID{1}, Bootstrapping: true},
+			executeErr: ErrInvalidChainID,
+		},
+		{
+			// and pay the price of their block
+			createTx: func() *Transaction {
+				return createTestTx(t, ids.ID{0, 1}, priv)
+			},
+			blockTime:  1,
+			ctx:        &Context{Bootstrapping: true, NextPrice: 11},
+			executeErr: ErrInsufficientPrice,
+		},
 	}
 	for i, tv := range tt {
 		db := memdb.New()
@@ -4,7 +4,6 @@
 package chain
 
 import (
-	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ethereum/go-ethereum/common"
 
@@ -12,23 +11,32 @@ import (
 )
 
 type TransactionContext struct {
-	Genesis   *Genesis
-	Database  database.Database
-	BlockTime uint64
-	TxID      ids.ID
-	Sender    common.Address
+	Genesis     *Genesis
+	State       StateDB
+	BlockTime   uint64
+	BlockHeight uint64
+	TxID        ids.ID
+	Sender      common.Address
+	// SenderShortID owns the sender's UTXOs on the X and P chains
+	SenderShortID ids.ShortID
+	// Price paid per fee unit
+	Price uint64
+	// Atomic is this chain's shared memory (nil if unavailable)
+	Atomic Atomic
 }
 
 type UnsignedTransaction interface {
 	Copy() UnsignedTransaction
 	GetBlockID() ids.ID
 	GetMagic() uint64
+	GetChainID() ids.ID
 	GetPrice() uint64
 	SetBlockID(ids.ID)
 	SetMagic(uint64)
+	SetChainID(ids.ID)
 	SetPrice(uint64)
-	FeeUnits(*Genesis) uint64  // number of units to mine tx
-	LoadUnits(*Genesis) uint64 // units that should impact fee rate
+	FeeUnits(*Genesis) Units  // number of units to mine tx
+	LoadUnits(*Genesis) Units // units that should impact fee rate
 
 	ExecuteBase(*Genesis) error
 	Execute(*TransactionContext) error
@@ -122,6 +122,20 @@ func (mr *MockUnsignedTransactionMockRecorder) GetBlockID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockID", reflect.TypeOf((*MockUnsignedTransaction)(nil).GetBlockID))
 }
 
+// GetChainID mocks base method.
+func (m *MockUnsignedTransaction) GetChainID() ids.ID {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChainID")
+	ret0, _ := ret[0].(ids.ID)
+	return ret0
+}
+
+// GetChainID indicates an expected call of GetChainID.
+func (mr *MockUnsignedTransactionMockRecorder) GetChainID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChainID", reflect.TypeOf((*MockUnsignedTransaction)(nil).GetChainID))
+}
+
 // GetMagic mocks base method.
 func (m *MockUnsignedTransaction) GetMagic() uint64 {
 	m.ctrl.T.Helper()
@@ -176,6 +190,18 @@ func (mr *MockUnsignedTransactionMockRecorder) SetBlockID(arg0 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBlockID", reflect.TypeOf((*MockUnsignedTransaction)(nil).SetBlockID), arg0)
 }
 
+// SetChainID mocks base method.
+func (m *MockUnsignedTransaction) SetChainID(arg0 ids.ID) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "SetChainID", arg0)
+}
+
+// SetChainID indicates an expected call of SetChainID.
+func (mr *MockUnsignedTransactionMockRecorder) SetChainID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetChainID", reflect.TypeOf((*MockUnsignedTransaction)(nil).SetChainID), arg0)
+}
+
 // SetMagic mocks base method.
 func (m *MockUnsignedTransaction) SetMagic(arg0 uint64) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,161 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+// Values are stored once per distinct content (keyed by [ValueHash]) and
+// linked to each transaction that set them. The content is deleted once the
+// last transaction linked to it is reclaimed.
+//
+// Values linked before deduplication (and the values preloaded by the
+// genesis) are still read from [txValuePrefix].
+
+// [contentPrefix] + [delimiter] + [hash]
+func prefixContentKey(h common.Hash) (k []byte) {
+	k = make([]byte, 2+common.HashLength)
+	k[0] = contentPrefix
+	k[1] = parser.ByteDelimiter
+	copy(k[2:], h[:])
+	return k
+}
+
+// [refPrefix] + [delimiter] + [hash]
+func prefixRefKey(h common.Hash) (k []byte) {
+	k = make([]byte, 2+common.HashLength)
+	k[0] = refPrefix
+	k[1] = parser.ByteDelimiter
+	copy(k[2:], h[:])
+	return k
+}
+
+// [valueLinkPrefix] + [delimiter] + [txID]
+func prefixValueLinkKey(txID ids.ID) (k []byte) {
+	k = make([]byte, 2+len(txID))
+	k[0] = valueLinkPrefix
+	k[1] = parser.ByteDelimiter
+	copy(k[2:], txID[:])
+	return k
+}
+
+// getValueRefs returns the number of transactions linked to the content [h]
+func getValueRefs(db database.KeyValueReader, h common.Hash) (uint64, error) {
+	v, err := db.Get(prefixRefKey(h))
+	if errors.Is(err, database.ErrNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(v) != 8 {
+		return 0, fmt.Errorf("%w: references of %s", ErrInvalidIndex, h)
+	}
+	return binary.BigEndian.Uint64(v), nil
+}
+
+func putValueRefs(db database.KeyValueWriterDeleter, h common.Hash, refs uint64) error {
+	if refs == 0 {
+		if err := db.Delete(prefixContentKey(h)); err != nil {
+			return err
+		}
+		return db.Delete(prefixRefKey(h))
+	}
+	v := make([]byte, 8)
+	binary.BigEndian.PutUint64(v, refs)
+	return db.Put(prefixRefKey(h), v)
+}
+
+// getValueLink returns the hash of the content linked to [txID] (if any)
+func getValueLink(db database.KeyValueReader, txID ids.ID) (common.Hash, bool, error) {
+	v, err := db.Get(prefixValueLinkKey(txID))
+	if errors.Is(err, database.ErrNotFound) {
+		return common.Hash{}, false, nil
+	}
+	if err != nil {
+		return common.Hash{}, false, err
+	}
+	if len(v) != common.HashLength {
+		return common.Hash{}, false, fmt.Errorf("%w: value link of %s", ErrInvalidIndex, txID)
+	}
+	return common.BytesToHash(v), true, nil
+}
+
+// putTxValue links [value] to [txID], storing its content if no other
+// transaction is linked to the same content. Linking [txID] again is a no-op.
+func putTxValue(db database.KeyValueReaderWriterDeleter, txID ids.ID, value []byte) error {
+	if _, ok, err := getValueLink(db, txID); ok || err != nil {
+		return err
+	}
+	h := ValueHash(value)
+	refs, err := getValueRefs(db, h)
+	if err != nil {
+		return err
+	}
+	if refs == 0 {
+		if err := db.Put(prefixContentKey(h), value); err != nil {
+			return err
+		}
+	}
+	if err := putValueRefs(db, h, refs+1); err != nil {
+		return err
+	}
+	return db.Put(prefixValueLinkKey(txID), h[:])
+}
+
+// getTxValue returns the value linked to [txID]
+func getTxValue(db database.KeyValueReader, txID ids.ID) ([]byte, error) {
+	h, ok, err := getValueLink(db, txID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return db.Get(PrefixTxValueKey(txID))
+	}
+	return db.Get(prefixContentKey(h))
+}
+
+// deleteTxValue unlinks the value of [txID], deleting its content once no
+// other transaction is linked to it. It returns false if there was no value
+// to delete.
+func deleteTxValue(db database.KeyValueReaderWriterDeleter, txID ids.ID) (bool, error) {
+	h, ok, err := getValueLink(db, txID)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		// Genesis values are linked by their hash
+		v, err := db.Get(PrefixTxValueKey(txID))
+		if errors.Is(err, database.ErrNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if ids.ID(ValueHash(v)) == txID {
+			return false, nil
+		}
+		return true, db.Delete(PrefixTxValueKey(txID))
+	}
+	refs, err := getValueRefs(db, h)
+	if err != nil {
+		return false, err
+	}
+	if refs == 0 {
+		return false, fmt.Errorf("%w: %s has no references", ErrInvalidIndex, h)
+	}
+	if err := putValueRefs(db, h, refs-1); err != nil {
+		return false, err
+	}
+	return true, db.Delete(prefixValueLinkKey(txID))
+}
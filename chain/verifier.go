@@ -0,0 +1,84 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"sync"
+)
+
+// Verifier initializes transactions (recovering their senders from their
+// signatures, the most expensive part of admitting a transaction) on a fixed
+// set of workers shared by every caller. Mempool admission, gossip, and block
+// verification all use the same [Verifier], so a busy node never runs more
+// signature recoveries at once than it has workers.
+//
+// A nil [Verifier] initializes transactions on the calling goroutine.
+type Verifier struct {
+	jobs chan func()
+	done <-chan struct{}
+}
+
+// NewVerifier starts [workers] workers that exit once [done] is closed.
+// Transactions initialized after that (or by a [Verifier] without workers)
+// are initialized on the calling goroutine.
+func NewVerifier(workers int, done <-chan struct{}) *Verifier {
+	if workers <= 0 {
+		return nil
+	}
+	v := &Verifier{jobs: make(chan func()), done: done}
+	for i := 0; i < workers; i++ {
+		go v.work()
+	}
+	return v
+}
+
+func (v *Verifier) work() {
+	for {
+		select {
+		case f := <-v.jobs:
+			f()
+		case <-v.done:
+			return
+		}
+	}
+}
+
+// InitTxs initializes [txs] and returns the error of each transaction that
+// could not be initialized (nil if all were), in the order of [txs].
+func (v *Verifier) InitTxs(g *Genesis, txs []*Transaction) []error {
+	errs := make([]error, len(txs))
+	if v == nil {
+		for i, tx := range txs {
+			errs[i] = tx.Init(g)
+		}
+		return errs
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(len(txs))
+	for i, tx := range txs {
+		i, tx := i, tx
+		f := func() {
+			errs[i] = tx.Init(g)
+			wg.Done()
+		}
+		select {
+		case v.jobs <- f:
+		case <-v.done:
+			f()
+		}
+	}
+	wg.Wait()
+	return errs
+}
+
+// InitAll initializes [txs] and returns the first error encountered, if any.
+func (v *Verifier) InitAll(g *Genesis, txs []*Transaction) error {
+	for _, err := range v.InitTxs(g, txs) {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
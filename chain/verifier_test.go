@@ -0,0 +1,61 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestVerifier(t *testing.T) {
+	t.Parallel()
+
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sender := crypto.PubkeyToAddress(priv.PublicKey)
+	g := DefaultGenesis()
+
+	done := make(chan struct{})
+	for i, v := range []*Verifier{nil, NewVerifier(0, done), NewVerifier(2, done)} {
+		txs := make([]*Transaction, 8)
+		for j := range txs {
+			txs[j] = createTestTx(t, ids.GenerateTestID(), priv)
+			// Reset the sender to ensure it is recovered again
+			txs[j].sender = [20]byte{}
+		}
+		invalid := txs[3].Copy()
+		invalid.Signature = []byte{1}
+		txs[3] = invalid
+
+		errs := v.InitTxs(g, txs)
+		for j, tx := range txs {
+			if j == 3 {
+				if errs[j] == nil {
+					t.Fatalf("#%d: expected invalid signature to fail", i)
+				}
+				continue
+			}
+			if errs[j] != nil {
+				t.Fatalf("#%d: unexpected error %v", i, errs[j])
+			}
+			if tx.Sender() != sender {
+				t.Fatalf("#%d: expected sender %s, got %s", i, sender, tx.Sender())
+			}
+		}
+		if err := v.InitAll(g, txs); err == nil {
+			t.Fatalf("#%d: expected InitAll to fail", i)
+		}
+	}
+
+	// Transactions are still initialized once the workers exit
+	close(done)
+	v := NewVerifier(2, done)
+	if err := v.InitAll(g, []*Transaction{createTestTx(t, ids.GenerateTestID(), priv)}); err != nil {
+		t.Fatal(err)
+	}
+}
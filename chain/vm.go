@@ -4,11 +4,26 @@
 package chain
 
 import (
+	"time"
+
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
 )
 
 type Context struct {
+	// ChainID is the ID of the executing chain
+	ChainID ids.ID
+
+	// Bootstrapping is set when executing a block that was already decided by
+	// the network. The recent block and tx ID checks are skipped, so only
+	// [ChainID], [Atomic], and the [NextCost] and [NextPrice] of the block
+	// are populated.
+	Bootstrapping bool
+
+	// Atomic is this chain's shared memory (nil if unavailable)
+	Atomic Atomic
+
 	RecentBlockIDs  ids.Set
 	RecentTxIDs     ids.Set
 	RecentLoadUnits uint64
@@ -22,9 +37,18 @@ type Context struct {
 
 type VM interface {
 	Genesis() *Genesis
+	// ChainID is the ID of this chain, which transactions must be bound to
+	ChainID() ids.ID
+	// Verifier initializes the transactions of parsed blocks
+	Verifier() *Verifier
 	IsBootstrapped() bool
 	State() database.Database
 	Mempool() Mempool
+	Now() time.Time
+	// Beneficiary is credited with a share of the fees of built blocks
+	Beneficiary() common.Address
+	// Atomic returns this chain's shared memory (nil if unavailable)
+	Atomic() Atomic
 	GetStatelessBlock(ids.ID) (*StatelessBlock, error)
 	ExecutionContext(currentTime int64, parent *StatelessBlock) (*Context, error)
 	Verified(*StatelessBlock)
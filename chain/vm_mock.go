@@ -10,9 +10,11 @@ package chain
 
 import (
 	reflect "reflect"
+	time "time"
 
 	database "github.com/ava-labs/avalanchego/database"
 	ids "github.com/ava-labs/avalanchego/ids"
+	common "github.com/ethereum/go-ethereum/common"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -51,6 +53,48 @@ func (mr *MockVMMockRecorder) Accepted(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Accepted", reflect.TypeOf((*MockVM)(nil).Accepted), arg0)
 }
 
+// Atomic mocks base method.
+func (m *MockVM) Atomic() Atomic {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Atomic")
+	ret0, _ := ret[0].(Atomic)
+	return ret0
+}
+
+// Atomic indicates an expected call of Atomic.
+func (mr *MockVMMockRecorder) Atomic() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Atomic", reflect.TypeOf((*MockVM)(nil).Atomic))
+}
+
+// Beneficiary mocks base method.
+func (m *MockVM) Beneficiary() common.Address {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Beneficiary")
+	ret0, _ := ret[0].(common.Address)
+	return ret0
+}
+
+// Beneficiary indicates an expected call of Beneficiary.
+func (mr *MockVMMockRecorder) Beneficiary() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Beneficiary", reflect.TypeOf((*MockVM)(nil).Beneficiary))
+}
+
+// ChainID mocks base method.
+func (m *MockVM) ChainID() ids.ID {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChainID")
+	ret0, _ := ret[0].(ids.ID)
+	return ret0
+}
+
+// ChainID indicates an expected call of ChainID.
+func (mr *MockVMMockRecorder) ChainID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChainID", reflect.TypeOf((*MockVM)(nil).ChainID))
+}
+
 // ExecutionContext mocks base method.
 func (m *MockVM) ExecutionContext(currentTime int64, parent *StatelessBlock) (*Context, error) {
 	m.ctrl.T.Helper()
@@ -123,6 +167,20 @@ func (mr *MockVMMockRecorder) Mempool() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Mempool", reflect.TypeOf((*MockVM)(nil).Mempool))
 }
 
+// Now mocks base method.
+func (m *MockVM) Now() time.Time {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Now")
+	ret0, _ := ret[0].(time.Time)
+	return ret0
+}
+
+// Now indicates an expected call of Now.
+func (mr *MockVMMockRecorder) Now() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Now", reflect.TypeOf((*MockVM)(nil).Now))
+}
+
 // Rejected mocks base method.
 func (m *MockVM) Rejected(arg0 *StatelessBlock) {
 	m.ctrl.T.Helper()
@@ -160,3 +218,17 @@ func (mr *MockVMMockRecorder) Verified(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verified", reflect.TypeOf((*MockVM)(nil).Verified), arg0)
 }
+
+// Verifier mocks base method.
+func (m *MockVM) Verifier() *Verifier {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Verifier")
+	ret0, _ := ret[0].(*Verifier)
+	return ret0
+}
+
+// Verifier indicates an expected call of Verifier.
+func (mr *MockVMMockRecorder) Verifier() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Verifier", reflect.TypeOf((*MockVM)(nil).Verifier))
+}
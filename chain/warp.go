@@ -0,0 +1,46 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package chain
+
+import (
+	"encoding/binary"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// warpMessagePrefix separates warp message digests from transaction and
+// attestation digests so a signature over one can never be replayed as another
+var warpMessagePrefix = []byte("spacesvm warp message\n")
+
+// WarpMessage is emitted by a broadcast [SetTx] so other subnets can consume
+// the value written to [Space]/[Key] without trusting the relayer that
+// delivers it.
+type WarpMessage struct {
+	SourceChainID ids.ID         `serialize:"true" json:"sourceChainId"`
+	TxID          ids.ID         `serialize:"true" json:"txId"`
+	Sender        common.Address `serialize:"true" json:"sender"`
+	Space         string         `serialize:"true" json:"space"`
+	Key           string         `serialize:"true" json:"key"`
+	ValueHash     common.Hash    `serialize:"true" json:"valueHash"`
+	Timestamp     uint64         `serialize:"true" json:"timestamp"`
+}
+
+// Digest returns the hash validators sign to authenticate the message
+func (m *WarpMessage) Digest() []byte {
+	timestamp := make([]byte, 8)
+	binary.BigEndian.PutUint64(timestamp, m.Timestamp)
+	return crypto.Keccak256(
+		warpMessagePrefix,
+		m.SourceChainID[:],
+		m.TxID[:],
+		m.Sender[:],
+		[]byte(m.Space),
+		[]byte{0},
+		[]byte(m.Key),
+		m.ValueHash[:],
+		timestamp,
+	)
+}
@@ -0,0 +1,106 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/rpc"
+
+	"github.com/ava-labs/spacesvm/vm"
+)
+
+// AdminClient defines spacesvm admin operations. The VM must be started with
+// the admin API enabled.
+type AdminClient interface {
+	// Compacts all database ranges and returns the number compacted.
+	Compact(ctx context.Context) (int, error)
+	// Prunes expired spaces and reclaims removed values immediately.
+	Prune(ctx context.Context) (*vm.PruneStats, error)
+	// Rebuilds the secondary indexes from the accepted blocks and state.
+	Reindex(ctx context.Context) (*vm.ReindexStats, error)
+	// Writes a consistent backup of the database to [dir] on the node.
+	Backup(ctx context.Context, dir string) (*vm.BackupMetadata, error)
+	// Writes every accepted block to [dir] on the node in [format] (and a
+	// backup of the database if [state] is set).
+	Export(ctx context.Context, dir string, format string, state bool) (*vm.ExportMetadata, error)
+}
+
+// NewAdmin creates a new admin client object.
+func NewAdmin(uri string, reqTimeout time.Duration, opts ...ClientOption) AdminClient {
+	ret := &ClientOp{}
+	ret.applyOpts(opts)
+	// Admin calls target a specific node, so [WithEndpoints] is ignored
+	return &adminClient{req: ret.requester([]string{uri}, vm.AdminEndpoint)}
+}
+
+type adminClient struct {
+	req rpc.EndpointRequester
+}
+
+func (cli *adminClient) Compact(ctx context.Context) (int, error) {
+	resp := new(vm.CompactReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"compact",
+		nil,
+		resp,
+	); err != nil {
+		return 0, err
+	}
+	return resp.Ranges, nil
+}
+
+func (cli *adminClient) Prune(ctx context.Context) (*vm.PruneStats, error) {
+	resp := new(vm.PruneReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"prune",
+		nil,
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp.Stats, nil
+}
+
+func (cli *adminClient) Reindex(ctx context.Context) (*vm.ReindexStats, error) {
+	resp := new(vm.ReindexReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"reindex",
+		nil,
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp.Stats, nil
+}
+
+func (cli *adminClient) Backup(ctx context.Context, dir string) (*vm.BackupMetadata, error) {
+	resp := new(vm.BackupReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"backup",
+		&vm.BackupArgs{Dir: dir},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp.Metadata, nil
+}
+
+func (cli *adminClient) Export(ctx context.Context, dir string, format string, state bool) (*vm.ExportMetadata, error) {
+	resp := new(vm.ExportReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"export",
+		&vm.ExportArgs{Dir: dir, Format: format, State: state},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp.Metadata, nil
+}
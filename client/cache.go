@@ -0,0 +1,200 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	log "github.com/inconshreveable/log15"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+var _ Client = &CachedClient{}
+
+// CachedClient is a [Client] that memoizes [Client.Info] and
+// [Client.Resolve] results for read-heavy applications. Each space read
+// through it is watched (like [Watch], its keys are polled with
+// [Client.Info] every interval), and resolved values are invalidated when
+// the transaction that last wrote their key changes. Results may therefore be
+// up to an interval stale. All other methods are passed through.
+type CachedClient struct {
+	Client
+
+	ctx      context.Context
+	interval time.Duration
+
+	l      sync.Mutex
+	spaces map[string]*cachedSpace
+	hits   uint64
+	misses uint64
+}
+
+type cachedSpace struct {
+	info *chain.SpaceInfo
+	kvs  []*chain.KeyValueMeta
+	// txs is the transaction that last wrote each key as of [kvs]
+	txs    map[string]ids.ID
+	values map[string]*watchedValue
+}
+
+// NewCachedClient caches the reads of [cli] until [ctx] is done, refreshing
+// each cached space every [interval].
+func NewCachedClient(ctx context.Context, cli Client, interval time.Duration) *CachedClient {
+	return &CachedClient{
+		Client:   cli,
+		ctx:      ctx,
+		interval: interval,
+		spaces:   map[string]*cachedSpace{},
+	}
+}
+
+// CacheStats returns the number of reads served from the cache and from the
+// node.
+func (c *CachedClient) CacheStats() (hits uint64, misses uint64) {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	return c.hits, c.misses
+}
+
+func (c *CachedClient) Info(ctx context.Context, space string) (*chain.SpaceInfo, []*chain.KeyValueMeta, error) {
+	c.l.Lock()
+	if s, ok := c.spaces[space]; ok {
+		c.hits++
+		c.l.Unlock()
+		return s.info, s.kvs, nil
+	}
+	c.misses++
+	c.l.Unlock()
+
+	s, err := c.watch(ctx, space)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.info, s.kvs, nil
+}
+
+func (c *CachedClient) Resolve(ctx context.Context, path string) (bool, []byte, *chain.ValueMeta, error) {
+	space, key, err := parser.ResolvePath(path)
+	if err != nil {
+		return false, nil, nil, err
+	}
+	c.l.Lock()
+	if s, ok := c.spaces[space]; ok {
+		if v, ok := s.values[key]; ok {
+			c.hits++
+			c.l.Unlock()
+			return v.meta != nil, v.value, v.meta, nil
+		}
+	}
+	c.misses++
+	c.l.Unlock()
+
+	if _, err := c.watch(ctx, space); err != nil {
+		// The space may not exist, so resolve without caching
+		return c.Client.Resolve(ctx, path)
+	}
+	exists, v, vmeta, err := c.Client.Resolve(ctx, path)
+	if err != nil {
+		return false, nil, nil, err
+	}
+
+	c.l.Lock()
+	defer c.l.Unlock()
+	// Only cache the value if it matches the latest snapshot of the space,
+	// otherwise it could be older (or newer) than the change that would
+	// invalidate it
+	if s, ok := c.spaces[space]; ok {
+		txID, listed := s.txs[key]
+		switch {
+		case exists && listed && vmeta.TxID == txID:
+			s.values[key] = &watchedValue{value: v, meta: vmeta}
+		case !exists && !listed:
+			s.values[key] = &watchedValue{}
+		}
+	}
+	return exists, v, vmeta, nil
+}
+
+// watch returns the cached [space], taking a snapshot of it and refreshing
+// it every interval if it is not cached yet.
+func (c *CachedClient) watch(ctx context.Context, space string) (*cachedSpace, error) {
+	c.l.Lock()
+	if s, ok := c.spaces[space]; ok {
+		c.l.Unlock()
+		return s, nil
+	}
+	c.l.Unlock()
+
+	info, kvs, err := c.Client.Info(ctx, space)
+	if err != nil {
+		return nil, err
+	}
+
+	c.l.Lock()
+	defer c.l.Unlock()
+	if s, ok := c.spaces[space]; ok {
+		// Watched concurrently
+		return s, nil
+	}
+	s := &cachedSpace{values: map[string]*watchedValue{}}
+	s.update(info, kvs)
+	if c.ctx.Err() != nil {
+		// No longer caching
+		return s, nil
+	}
+	c.spaces[space] = s
+	go c.refresh(space)
+	return s, nil
+}
+
+// refresh polls [space] every interval until [c.ctx] is done, invalidating
+// the values of keys that changed. If polling fails, the space is evicted so
+// reads are served by the node until it is watched again.
+func (c *CachedClient) refresh(space string) {
+	t := time.NewTicker(c.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+		case <-c.ctx.Done():
+			c.l.Lock()
+			delete(c.spaces, space)
+			c.l.Unlock()
+			return
+		}
+
+		info, kvs, err := c.Client.Info(c.ctx, space)
+		c.l.Lock()
+		if err != nil {
+			log.Debug("evicting cached space", "space", space, "err", err)
+			delete(c.spaces, space)
+			c.l.Unlock()
+			return
+		}
+		c.spaces[space].update(info, kvs)
+		c.l.Unlock()
+	}
+}
+
+// update replaces the snapshot of the space with [info] and [kvs] and
+// invalidates the values of keys that were created, modified, or deleted.
+func (s *cachedSpace) update(info *chain.SpaceInfo, kvs []*chain.KeyValueMeta) {
+	txs := make(map[string]ids.ID, len(kvs))
+	for _, kv := range kvs {
+		txs[kv.Key] = kv.ValueMeta.TxID
+	}
+	for key, v := range s.values {
+		txID, listed := txs[key]
+		if listed != (v.meta != nil) || (listed && txID != v.meta.TxID) {
+			delete(s.values, key)
+		}
+	}
+	s.info, s.kvs, s.txs = info, kvs, txs
+}
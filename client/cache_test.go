@@ -0,0 +1,70 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+func TestCachedClient(t *testing.T) {
+	cli := &watchClient{values: map[string]string{}, txs: map[string]ids.ID{}, polled: make(chan struct{})}
+	cli.set("a", "1")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cc := NewCachedClient(ctx, cli, time.Millisecond)
+
+	resolve := func(key string) (bool, string) {
+		exists, v, _, err := cc.Resolve(context.Background(), "space/"+key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return exists, string(v)
+	}
+	for i := 0; i < 2; i++ {
+		if exists, v := resolve("a"); !exists || v != "1" {
+			t.Fatalf("expected a=1, got exists=%t value=%q", exists, v)
+		}
+		if exists, _ := resolve("b"); exists {
+			t.Fatal("expected b to not exist")
+		}
+	}
+	if _, kvs, err := cc.Info(context.Background(), "space"); err != nil || len(kvs) != 1 {
+		t.Fatalf("unexpected info values %v (err=%v)", kvs, err)
+	}
+	if hits, misses := cc.CacheStats(); hits != 3 || misses != 2 {
+		t.Fatalf("expected 3 hits and 2 misses, got %d and %d", hits, misses)
+	}
+
+	// Changes are picked up once the space is refreshed
+	cli.set("a", "2")
+	cli.set("b", "3")
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, a := resolve("a")
+		_, b := resolve("b")
+		if a == "2" && b == "3" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("cached values were not invalidated (a=%q b=%q)", a, b)
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cli.set("a", "")
+	for {
+		exists, _ := resolve("a")
+		if !exists {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("deleted value was not invalidated")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -7,7 +7,6 @@ package client
 import (
 	"context"
 	"errors"
-	"fmt"
 	"strings"
 	"time"
 
@@ -32,17 +31,43 @@ type Client interface {
 
 	// Returns the VM genesis.
 	Genesis(ctx context.Context) (*chain.Genesis, error)
+	// Returns the rule set the VM runs (compiled constants and the fee
+	// mechanism of its genesis).
+	Params(ctx context.Context) (*chain.Params, error)
 	// Accepted fetches the ID of the last accepted block.
 	Accepted(ctx context.Context) (ids.ID, error)
+	// Frontier fetches the last accepted block and the blocks being
+	// processed on top of it.
+	Frontier(ctx context.Context) (*vm.Frontier, error)
 
 	// Returns if a space is already claimed
 	Claimed(ctx context.Context, space string) (bool, error)
 	// Returns the corresponding space information.
 	Info(ctx context.Context, space string) (*chain.SpaceInfo, []*chain.KeyValueMeta, error)
+	// Returns the cost of extending the life of a space by [units] (or exactly
+	// [extension] seconds, if non-zero) and its resulting expiry.
+	EstimateLifeline(ctx context.Context, space string, units uint64, extension uint64) (*vm.EstimateLifelineReply, error)
 	// Balance returns the balance of an account
 	Balance(ctx context.Context, addr common.Address) (bal uint64, err error)
+	// AtomicUTXOs returns the UTXOs [sourceChain] exported to this chain that
+	// [addr] can import
+	AtomicUTXOs(ctx context.Context, sourceChain ids.ID, addr ids.ShortID) ([]*vm.AtomicUTXO, error)
 	// Resolve returns the value associated with a path
 	Resolve(ctx context.Context, path string) (exists bool, value []byte, valueMeta *chain.ValueMeta, err error)
+	// ResolveWithAttestation returns the full resolve response, including the
+	// node's attestation (if it signs responses), so it can be cached and
+	// verified later with [VerifyResolve]
+	ResolveWithAttestation(ctx context.Context, path string) (*vm.ResolveReply, error)
+	// ResolveIfNoneMatch is [ResolveWithAttestation] for a value previously
+	// resolved with [etag]. If it is unchanged, the reply is marked not
+	// modified and omits the value.
+	ResolveIfNoneMatch(ctx context.Context, path string, etag string) (*vm.ResolveReply, error)
+	// ResolveMany resolves every path from the state of a single block
+	// (reported in the reply), so no block is accepted between reads
+	ResolveMany(ctx context.Context, paths []string) (*vm.ResolveManyReply, error)
+	// WarpMessage returns the warp message emitted by the broadcast set
+	// [txID] and the node's signature over it (if it signs responses)
+	WarpMessage(ctx context.Context, txID ids.ID) (*vm.WarpMessageReply, error)
 
 	// Requests the suggested price and cost from VM.
 	SuggestedRawFee(ctx context.Context) (uint64, uint64, error)
@@ -55,6 +80,16 @@ type Client interface {
 	// Issues a human-readable transaction and returns the transaction ID.
 	IssueTx(ctx context.Context, td *tdata.TypedData, sig []byte) (ids.ID, error)
 
+	// Encodes the input on the node, returning the digest to sign along with
+	// the block ID and price it was encoded at.
+	EncodeTx(ctx context.Context, i *chain.Input) (*vm.EncodeTxReply, error)
+	// Issues the input signed over the digest returned by [EncodeTx] and
+	// returns the transaction ID.
+	IssueInputTx(ctx context.Context, i *chain.Input, blkID ids.ID, price uint64, sig []byte) (ids.ID, error)
+	// Encodes [utx] on the node like [EncodeTx], using its block ID and price
+	// unless they are empty.
+	EncodeUnsignedTx(ctx context.Context, utx chain.UnsignedTransaction) (*vm.EncodeTxReply, error)
+
 	// Checks the status of the transaction, and returns "true" if confirmed.
 	HasTx(ctx context.Context, id ids.ID) (bool, error)
 	// Polls the transactions until its status is confirmed.
@@ -64,19 +99,77 @@ type Client interface {
 	RecentActivity(ctx context.Context) ([]*chain.Activity, error)
 	// All spaces owned by a given address
 	Owned(ctx context.Context, owner common.Address) ([]string, error)
+	// Keys of a space and their metadata (sorted by key), starting after
+	// [cursor]. Returns the cursor of the next page.
+	ListKeys(ctx context.Context, space string, cursor string) ([]*chain.KeyValueMeta, string, error)
+	// Activity affecting a space (sorted from oldest to newest), starting at
+	// [cursor]. Returns the cursor of the next page.
+	History(ctx context.Context, space string, cursor string) ([]*chain.Activity, string, error)
+	// Transactions sent by an address (sorted from oldest to newest),
+	// starting at [cursor]. Returns the cursor of the next page.
+	SenderHistory(ctx context.Context, addr common.Address, cursor string) ([]*chain.Activity, string, error)
+	// Paths ("space/key", sorted) whose values hash to [hash] (see
+	// [chain.ValueHash]), starting after [cursor]. Returns the cursor of the
+	// next page.
+	FindByHash(ctx context.Context, hash common.Hash, cursor string) ([]string, string, error)
+	// Keys set or deleted under [prefix] (a space or space/key prefix) by the
+	// blocks accepted after [blockID] and their current values. Sync again
+	// from the returned block ID until there are no more changes.
+	ChangesSince(ctx context.Context, prefix string, blockID ids.ID) (*vm.ChangesSinceReply, error)
+	// Summaries of up to [n] accepted blocks (sorted from newest to oldest),
+	// starting at the last accepted block or before [cursor]. Returns the
+	// cursor of the next (older) page.
+	RecentBlocks(ctx context.Context, n int, cursor string) ([]*vm.BlockSummary, string, error)
+	// Full contents of the accepted block with the given ID
+	Block(ctx context.Context, blockID ids.ID) (*vm.BlockDetail, error)
+	// Full contents of the accepted block at the given height
+	BlockAt(ctx context.Context, height uint64) (*vm.BlockDetail, error)
+	// Encoded bytes of the accepted block with the given ID
+	RawBlock(ctx context.Context, blockID ids.ID) ([]byte, error)
+	// Encoded bytes of the accepted block at the given height
+	RawBlockAt(ctx context.Context, height uint64) ([]byte, error)
+	// Header (every field but the transactions) of the accepted block with
+	// the given ID
+	Header(ctx context.Context, blockID ids.ID) (*chain.BlockHeader, error)
+	// Header of the accepted block at the given height
+	HeaderAt(ctx context.Context, height uint64) (*chain.BlockHeader, error)
+	// Headers of up to [n] accepted blocks (sorted from oldest to newest),
+	// starting at height [start]
+	Headers(ctx context.Context, start uint64, n int) ([]*chain.BlockHeader, error)
+	// Full contents of an accepted transaction and the block that accepted it
+	Tx(ctx context.Context, txID ids.ID) (*vm.TxReply, error)
+	// Rolling block production aggregates and state totals
+	Stats(ctx context.Context) (*vm.StatsReply, error)
+	// Price, cost, and lowest included transaction price of up to [n]
+	// recently accepted blocks (sorted from oldest to newest) and the
+	// suggested fee for the next block
+	FeeHistory(ctx context.Context, n int) (*vm.FeeHistoryReply, error)
+	// Node version and the methods it serves, with their parameter and
+	// result shapes
+	Discover(ctx context.Context) (*vm.DiscoverReply, error)
 }
 
 // New creates a new client object.
-func New(uri string, reqTimeout time.Duration) Client {
-	req := rpc.NewEndpointRequester(
-		fmt.Sprintf("%s%s", uri, vm.PublicEndpoint),
-		"spacesvm",
-	)
-	return &client{req: req}
+func New(uri string, reqTimeout time.Duration, opts ...ClientOption) Client {
+	ret := &ClientOp{}
+	ret.applyOpts(opts)
+	uris := append([]string{uri}, ret.endpoints...)
+	return &client{
+		req:       ret.requester(uris, vm.PublicEndpoint),
+		signer:    ret.signer,
+		preferred: ret.preferred,
+		pending:   ret.pending,
+	}
 }
 
 type client struct {
 	req rpc.EndpointRequester
+	// signer must attest resolved values (nil if not required)
+	signer *common.Address
+	// preferred serves state reads from the preferred block
+	preferred bool
+	// pending overlays the mempool on state reads
+	pending bool
 }
 
 func (cli *client) Ping(ctx context.Context) (bool, error) {
@@ -117,12 +210,23 @@ func (cli *client) Genesis(ctx context.Context) (*chain.Genesis, error) {
 	return resp.Genesis, err
 }
 
+func (cli *client) Params(ctx context.Context) (*chain.Params, error) {
+	resp := new(vm.GenesisReply)
+	err := cli.req.SendRequest(
+		ctx,
+		"genesis",
+		nil,
+		resp,
+	)
+	return resp.Params, err
+}
+
 func (cli *client) Claimed(ctx context.Context, space string) (bool, error) {
 	resp := new(vm.ClaimedReply)
 	if err := cli.req.SendRequest(
 		ctx,
 		"claimed",
-		&vm.ClaimedArgs{Space: space},
+		&vm.ClaimedArgs{Space: space, Preferred: cli.preferred, Pending: cli.pending},
 		resp,
 	); err != nil {
 		return false, err
@@ -135,7 +239,7 @@ func (cli *client) Info(ctx context.Context, space string) (*chain.SpaceInfo, []
 	if err := cli.req.SendRequest(
 		ctx,
 		"info",
-		&vm.InfoArgs{Space: space},
+		&vm.InfoArgs{Space: space, Preferred: cli.preferred, Pending: cli.pending},
 		resp,
 	); err != nil {
 		return nil, nil, err
@@ -143,6 +247,24 @@ func (cli *client) Info(ctx context.Context, space string) (*chain.SpaceInfo, []
 	return resp.Info, resp.Values, nil
 }
 
+func (cli *client) EstimateLifeline(
+	ctx context.Context,
+	space string,
+	units uint64,
+	extension uint64,
+) (*vm.EstimateLifelineReply, error) {
+	resp := new(vm.EstimateLifelineReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"estimateLifeline",
+		&vm.EstimateLifelineArgs{Space: space, Units: units, Extension: extension},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 func (cli *client) Accepted(ctx context.Context) (ids.ID, error) {
 	resp := new(vm.LastAcceptedReply)
 	if err := cli.req.SendRequest(
@@ -157,6 +279,19 @@ func (cli *client) Accepted(ctx context.Context) (ids.ID, error) {
 	return resp.BlockID, nil
 }
 
+func (cli *client) Frontier(ctx context.Context) (*vm.Frontier, error) {
+	resp := new(vm.FrontierReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"frontier",
+		nil,
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp.Frontier, nil
+}
+
 func (cli *client) SuggestedRawFee(ctx context.Context) (uint64, uint64, error) {
 	resp := new(vm.SuggestedRawFeeReply)
 	if err := cli.req.SendRequest(
@@ -223,6 +358,51 @@ func (cli *client) IssueTx(ctx context.Context, td *tdata.TypedData, sig []byte)
 	return resp.TxID, nil
 }
 
+func (cli *client) EncodeTx(ctx context.Context, i *chain.Input) (*vm.EncodeTxReply, error) {
+	resp := new(vm.EncodeTxReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"encodeTx",
+		&vm.EncodeTxArgs{Input: i},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (cli *client) EncodeUnsignedTx(ctx context.Context, utx chain.UnsignedTransaction) (*vm.EncodeTxReply, error) {
+	resp := new(vm.EncodeTxReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"encodeTx",
+		&vm.EncodeTxArgs{Tx: &chain.TxJSON{UnsignedTransaction: utx}},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (cli *client) IssueInputTx(
+	ctx context.Context,
+	i *chain.Input,
+	blkID ids.ID,
+	price uint64,
+	sig []byte,
+) (ids.ID, error) {
+	resp := new(vm.IssueTxReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"issueInputTx",
+		&vm.IssueInputTxArgs{Input: i, BlockID: blkID, Price: price, Signature: sig},
+		resp,
+	); err != nil {
+		return ids.Empty, err
+	}
+	return resp.TxID, nil
+}
+
 func (cli *client) PollTx(ctx context.Context, txID ids.ID) (confirmed bool, err error) {
 done:
 	for ctx.Err() == nil {
@@ -245,20 +425,82 @@ done:
 }
 
 func (cli *client) Resolve(ctx context.Context, path string) (bool, []byte, *chain.ValueMeta, error) {
+	resp, err := cli.ResolveWithAttestation(ctx, path)
+	if err != nil {
+		return false, nil, nil, err
+	}
+	if !resp.Exists {
+		return false, nil, nil, nil
+	}
+	return true, resp.Value, resp.ValueMeta, nil
+}
+
+func (cli *client) ResolveWithAttestation(ctx context.Context, path string) (*vm.ResolveReply, error) {
+	return cli.resolve(ctx, &vm.ResolveArgs{Path: path, Preferred: cli.preferred, Pending: cli.pending})
+}
+
+func (cli *client) ResolveIfNoneMatch(ctx context.Context, path string, etag string) (*vm.ResolveReply, error) {
+	return cli.resolve(ctx, &vm.ResolveArgs{Path: path, IfNoneMatch: etag, Preferred: cli.preferred, Pending: cli.pending})
+}
+
+func (cli *client) resolve(ctx context.Context, args *vm.ResolveArgs) (*vm.ResolveReply, error) {
 	resp := new(vm.ResolveReply)
 	if err := cli.req.SendRequest(
 		ctx,
 		"resolve",
-		&vm.ResolveArgs{
-			Path: path,
-		},
+		args,
 		resp,
 	); err != nil {
-		return false, nil, nil, err
+		return nil, err
+	}
+	if err := cli.checkResolve(resp, args.Path, args.IfNoneMatch); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (cli *client) ResolveMany(ctx context.Context, paths []string) (*vm.ResolveManyReply, error) {
+	resp := new(vm.ResolveManyReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"resolveMany",
+		&vm.ResolveManyArgs{Paths: paths, Preferred: cli.preferred, Pending: cli.pending},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	if len(resp.Values) != len(paths) {
+		return nil, ErrIntegrityFailure
+	}
+	for i, path := range paths {
+		v := resp.Values[i]
+		if err := cli.checkResolve(v, path, ""); err != nil {
+			return nil, err
+		}
+		// Every value must be attested as of the same block
+		if v.Attestation != nil && v.Attestation.BlockID != resp.BlockID {
+			return nil, ErrIntegrityFailure
+		}
 	}
+	return resp, nil
+}
 
+// checkResolve verifies [resp] to resolving [path] with [etag]
+func (cli *client) checkResolve(resp *vm.ResolveReply, path string, etag string) error {
+	if cli.signer != nil {
+		if err := VerifyResolve(resp, path, *cli.signer); err != nil {
+			return err
+		}
+	}
+	if resp.NotModified {
+		// The caller's copy is only current if it has the returned hash
+		if resp.ETag != etag {
+			return ErrIntegrityFailure
+		}
+		return nil
+	}
 	if !resp.Exists {
-		return false, nil, nil, nil
+		return nil
 	}
 
 	// If we are here, path is valid
@@ -267,10 +509,72 @@ func (cli *client) Resolve(ctx context.Context, path string) (bool, []byte, *cha
 	// Ensure we are not served malicious chunks
 	if len(k) == chain.HashLen {
 		if k != strings.ToLower(common.Bytes2Hex(crypto.Keccak256(resp.Value))) {
-			return false, nil, nil, ErrIntegrityFailure
+			return ErrIntegrityFailure
 		}
 	}
-	return true, resp.Value, resp.ValueMeta, nil
+	return nil
+}
+
+// VerifyResolve checks that [resp] to resolving [path] carries a valid
+// attestation from [signer].
+func VerifyResolve(resp *vm.ResolveReply, path string, signer common.Address) error {
+	if resp.Attestation == nil {
+		return ErrUnsignedResponse
+	}
+	if resp.NotModified {
+		// The value was omitted, so verify the hash it was matched against
+		hash, err := vm.ParseETag(resp.ETag)
+		if err != nil {
+			return err
+		}
+		return resp.Attestation.VerifyHash(path, resp.Exists, hash, signer)
+	}
+	return resp.Attestation.Verify(path, resp.Exists, resp.Value, signer)
+}
+
+func (cli *client) WarpMessage(ctx context.Context, txID ids.ID) (*vm.WarpMessageReply, error) {
+	resp := new(vm.WarpMessageReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"warpMessage",
+		&vm.WarpMessageArgs{
+			TxID: txID,
+		},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	if cli.signer != nil {
+		if err := VerifyWarpMessage(resp, *cli.signer); err != nil {
+			return nil, err
+		}
+	}
+	return resp, nil
+}
+
+// VerifyWarpMessage checks that [resp] carries a valid signature from
+// [signer] over its message.
+func VerifyWarpMessage(resp *vm.WarpMessageReply, signer common.Address) error {
+	if resp.Signature == nil {
+		return ErrUnsignedResponse
+	}
+	return resp.Signature.Verify(resp.Message, signer)
+}
+
+func (cli *client) AtomicUTXOs(ctx context.Context, sourceChain ids.ID, addr ids.ShortID) ([]*vm.AtomicUTXO, error) {
+	resp := new(vm.AtomicUTXOsReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"atomicUTXOs",
+		&vm.AtomicUTXOsArgs{
+			SourceChain: sourceChain,
+			Address:     addr,
+		},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp.UTXOs, nil
 }
 
 func (cli *client) IssueTxHR(ctx context.Context, d []byte, sig []byte) (ids.ID, error) {
@@ -283,7 +587,9 @@ func (cli *client) Balance(ctx context.Context, addr common.Address) (bal uint64
 		ctx,
 		"balance",
 		&vm.BalanceArgs{
-			Address: addr,
+			Address:   addr,
+			Preferred: cli.preferred,
+			Pending:   cli.pending,
 		},
 		resp,
 	); err != nil {
@@ -305,17 +611,244 @@ func (cli *client) RecentActivity(ctx context.Context) (activity []*chain.Activi
 	return resp.Activity, nil
 }
 
-func (cli *client) Owned(ctx context.Context, addr common.Address) (spaces []string, err error) {
-	resp := new(vm.OwnedReply)
-	if err = cli.req.SendRequest(
+func (cli *client) Owned(ctx context.Context, addr common.Address) ([]string, error) {
+	spaces := []string{}
+	cursor := ""
+	for {
+		resp := new(vm.OwnedReply)
+		if err := cli.req.SendRequest(
+			ctx,
+			"owned",
+			&vm.OwnedArgs{Address: addr, Cursor: cursor, Preferred: cli.preferred, Pending: cli.pending},
+			resp,
+		); err != nil {
+			return nil, err
+		}
+		spaces = append(spaces, resp.Spaces...)
+		if len(resp.Next) == 0 {
+			return spaces, nil
+		}
+		cursor = resp.Next
+	}
+}
+
+func (cli *client) ListKeys(ctx context.Context, space string, cursor string) ([]*chain.KeyValueMeta, string, error) {
+	resp := new(vm.ListKeysReply)
+	if err := cli.req.SendRequest(
 		ctx,
-		"owned",
-		&vm.OwnedArgs{
-			Address: addr,
-		},
+		"listKeys",
+		&vm.ListKeysArgs{Space: space, Cursor: cursor, Preferred: cli.preferred, Pending: cli.pending},
+		resp,
+	); err != nil {
+		return nil, "", err
+	}
+	return resp.Keys, resp.Next, nil
+}
+
+func (cli *client) History(ctx context.Context, space string, cursor string) ([]*chain.Activity, string, error) {
+	resp := new(vm.HistoryReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"history",
+		&vm.HistoryArgs{Space: space, Cursor: cursor},
+		resp,
+	); err != nil {
+		return nil, "", err
+	}
+	return resp.Activity, resp.Next, nil
+}
+
+func (cli *client) SenderHistory(ctx context.Context, addr common.Address, cursor string) ([]*chain.Activity, string, error) {
+	resp := new(vm.HistoryReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"senderHistory",
+		&vm.SenderHistoryArgs{Address: addr, Cursor: cursor},
+		resp,
+	); err != nil {
+		return nil, "", err
+	}
+	return resp.Activity, resp.Next, nil
+}
+
+func (cli *client) FindByHash(ctx context.Context, hash common.Hash, cursor string) ([]string, string, error) {
+	resp := new(vm.FindByHashReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"findByHash",
+		&vm.FindByHashArgs{Hash: hash, Cursor: cursor},
+		resp,
+	); err != nil {
+		return nil, "", err
+	}
+	return resp.Paths, resp.Next, nil
+}
+
+func (cli *client) ChangesSince(ctx context.Context, prefix string, blockID ids.ID) (*vm.ChangesSinceReply, error) {
+	resp := new(vm.ChangesSinceReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"changesSince",
+		&vm.ChangesSinceArgs{Prefix: prefix, BlockID: blockID},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (cli *client) RecentBlocks(ctx context.Context, n int, cursor string) ([]*vm.BlockSummary, string, error) {
+	resp := new(vm.RecentBlocksReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"recentBlocks",
+		&vm.RecentBlocksArgs{N: n, Cursor: cursor},
+		resp,
+	); err != nil {
+		return nil, "", err
+	}
+	return resp.Blocks, resp.Next, nil
+}
+
+func (cli *client) Block(ctx context.Context, blockID ids.ID) (*vm.BlockDetail, error) {
+	resp := new(vm.BlockReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"block",
+		&vm.BlockArgs{BlockID: blockID},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp.Block, nil
+}
+
+func (cli *client) BlockAt(ctx context.Context, height uint64) (*vm.BlockDetail, error) {
+	resp := new(vm.BlockReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"block",
+		&vm.BlockArgs{Height: &height},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp.Block, nil
+}
+
+func (cli *client) RawBlock(ctx context.Context, blockID ids.ID) ([]byte, error) {
+	resp := new(vm.RawBlockReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"rawBlock",
+		&vm.BlockArgs{BlockID: blockID},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp.Bytes, nil
+}
+
+func (cli *client) RawBlockAt(ctx context.Context, height uint64) ([]byte, error) {
+	resp := new(vm.RawBlockReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"rawBlock",
+		&vm.BlockArgs{Height: &height},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp.Bytes, nil
+}
+
+func (cli *client) Header(ctx context.Context, blockID ids.ID) (*chain.BlockHeader, error) {
+	resp := new(vm.HeaderReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"header",
+		&vm.BlockArgs{BlockID: blockID},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp.Header, nil
+}
+
+func (cli *client) HeaderAt(ctx context.Context, height uint64) (*chain.BlockHeader, error) {
+	resp := new(vm.HeaderReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"header",
+		&vm.BlockArgs{Height: &height},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp.Header, nil
+}
+
+func (cli *client) Headers(ctx context.Context, start uint64, n int) ([]*chain.BlockHeader, error) {
+	resp := new(vm.HeadersReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"headers",
+		&vm.HeadersArgs{Start: start, N: n},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp.Headers, nil
+}
+
+func (cli *client) Tx(ctx context.Context, txID ids.ID) (*vm.TxReply, error) {
+	resp := new(vm.TxReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"tx",
+		&vm.TxArgs{TxID: txID},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (cli *client) Stats(ctx context.Context) (*vm.StatsReply, error) {
+	resp := new(vm.StatsReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"stats",
+		nil,
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (cli *client) FeeHistory(ctx context.Context, n int) (*vm.FeeHistoryReply, error) {
+	resp := new(vm.FeeHistoryReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"feeHistory",
+		&vm.FeeHistoryArgs{N: n},
+		resp,
+	); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (cli *client) Discover(ctx context.Context) (*vm.DiscoverReply, error) {
+	resp := new(vm.DiscoverReply)
+	if err := cli.req.SendRequest(
+		ctx,
+		"discover",
+		nil,
 		resp,
 	); err != nil {
 		return nil, err
 	}
-	return resp.Spaces, nil
+	return resp, nil
 }
@@ -5,4 +5,31 @@ package client
 
 import "errors"
 
-var ErrIntegrityFailure = errors.New("received file that does not match hash")
+var (
+	ErrIntegrityFailure = errors.New("received file that does not match hash")
+	ErrMagicMismatch    = errors.New("network magic mismatch")
+	ErrUnsignedResponse = errors.New("response is not signed")
+	ErrNameNotFound     = errors.New("name not found")
+	ErrRedirectLoop     = errors.New("redirect loop")
+	ErrTooManyRedirects = errors.New("too many redirects")
+)
+
+// ServerError is an error reported by a node. It matches the
+// [chain.Errors] sentinel the node failed with (see [chain.ParseError]), so
+// failures can be classified with [errors.Is] as they would be on the node:
+//
+//	if errors.Is(err, chain.ErrSpaceExpired) { ... }
+type ServerError struct {
+	// Err is the error returned by the requester
+	Err error
+	// Sentinel is the [chain.Errors] sentinel reported by the node, if any
+	Sentinel error
+}
+
+func (e *ServerError) Error() string { return e.Err.Error() }
+
+func (e *ServerError) Unwrap() error { return e.Err }
+
+func (e *ServerError) Is(target error) bool {
+	return e.Sentinel != nil && e.Sentinel == target
+}
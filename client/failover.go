@@ -0,0 +1,172 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/rpc"
+
+	"github.com/ava-labs/spacesvm/vm"
+)
+
+const (
+	defaultRetryBackoff = 250 * time.Millisecond
+	maxRetryBackoff     = 5 * time.Second
+
+	// how long an endpoint that failed is skipped before it is health checked
+	// again
+	unhealthyCooldown = 30 * time.Second
+)
+
+type endpoint struct {
+	req            rpc.EndpointRequester
+	unhealthyUntil time.Time
+}
+
+// failoverRequester sends requests to the first healthy endpoint and retries
+// transient failures (on the next healthy endpoint, if there is one) with
+// jittered exponential backoff.
+type failoverRequester struct {
+	retries int
+	backoff time.Duration
+
+	l         sync.Mutex
+	endpoints []*endpoint
+	current   int
+}
+
+func (f *failoverRequester) SendRequest(
+	ctx context.Context,
+	method string,
+	params interface{},
+	reply interface{},
+	options ...rpc.Option,
+) error {
+	var err error
+	for attempt := 0; attempt <= f.retries; attempt++ {
+		if attempt > 0 {
+			if serr := sleep(ctx, retryBackoff(f.backoff, attempt)); serr != nil {
+				return err
+			}
+		}
+		e := f.healthy(ctx, options)
+		err = e.req.SendRequest(ctx, method, params, reply, options...)
+		if err == nil || !transient(ctx, err) {
+			return err
+		}
+		f.markUnhealthy(e)
+	}
+	return err
+}
+
+// healthy returns the first endpoint, starting from the last one used, that
+// has not recently failed. Endpoints whose cooldown has elapsed are pinged
+// before being used again. If no endpoint is healthy, the last one used is
+// returned.
+func (f *failoverRequester) healthy(ctx context.Context, options []rpc.Option) *endpoint {
+	type candidate struct {
+		e *endpoint
+		// Only endpoints that previously failed are checked to avoid adding a
+		// round trip to every request
+		check bool
+	}
+	f.l.Lock()
+	now := time.Now()
+	candidates := make([]candidate, 0, len(f.endpoints))
+	for i := range f.endpoints {
+		e := f.endpoints[(f.current+i)%len(f.endpoints)]
+		if e.unhealthyUntil.After(now) {
+			continue
+		}
+		candidates = append(candidates, candidate{e, len(f.endpoints) > 1 && !e.unhealthyUntil.IsZero()})
+	}
+	fallback := f.endpoints[f.current]
+	f.l.Unlock()
+
+	for _, c := range candidates {
+		if c.check {
+			if err := c.e.req.SendRequest(ctx, "ping", nil, new(vm.PingReply), options...); err != nil {
+				f.markUnhealthy(c.e)
+				continue
+			}
+		}
+		f.use(c.e)
+		return c.e
+	}
+	return fallback
+}
+
+func (f *failoverRequester) use(e *endpoint) {
+	f.l.Lock()
+	defer f.l.Unlock()
+
+	e.unhealthyUntil = time.Time{}
+	for i, o := range f.endpoints {
+		if o == e {
+			f.current = i
+			return
+		}
+	}
+}
+
+func (f *failoverRequester) markUnhealthy(e *endpoint) {
+	f.l.Lock()
+	defer f.l.Unlock()
+
+	e.unhealthyUntil = time.Now().Add(unhealthyCooldown)
+}
+
+// retryBackoff returns a random duration between half and all of the
+// exponential backoff for [attempt], which starts at [base].
+func retryBackoff(base time.Duration, attempt int) time.Duration {
+	d := base << (attempt - 1)
+	if d <= 0 || d > maxRetryBackoff {
+		d = maxRetryBackoff
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1)) //nolint:gosec
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// transient returns true if [err] may succeed when retried, such as when the
+// node is unreachable or overloaded. Errors returned by the VM itself are not
+// transient.
+func transient(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var uerr *url.Error
+	if errors.As(err, &uerr) {
+		return true
+	}
+	// The requester only reports the status code in the error message
+	const prefix = "received status code: "
+	msg := err.Error()
+	if !strings.HasPrefix(msg, prefix) {
+		return false
+	}
+	code, perr := strconv.Atoi(strings.TrimPrefix(msg, prefix))
+	if perr != nil {
+		return false
+	}
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
@@ -0,0 +1,96 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+func newTestNode(t *testing.T, status *int32, calls *int32) *httptest.Server {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		if code := int(atomic.LoadInt32(status)); code != http.StatusOK {
+			w.WriteHeader(code)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","result":{"success":true},"id":1}`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func TestFailover(t *testing.T) {
+	primaryStatus, secondaryStatus := int32(http.StatusServiceUnavailable), int32(http.StatusOK)
+	var primaryCalls, secondaryCalls int32
+	primary := newTestNode(t, &primaryStatus, &primaryCalls)
+	secondary := newTestNode(t, &secondaryStatus, &secondaryCalls)
+
+	cli := New(primary.URL, time.Second, WithEndpoints(secondary.URL), WithRetries(1, time.Millisecond))
+	for i := 0; i < 2; i++ {
+		ok, err := cli.Ping(context.Background())
+		if err != nil || !ok {
+			t.Fatalf("ping failed: %v", err)
+		}
+	}
+	// The unhealthy primary is skipped once it fails
+	if primaryCalls != 1 || secondaryCalls != 2 {
+		t.Fatalf("unexpected calls primary=%d secondary=%d", primaryCalls, secondaryCalls)
+	}
+}
+
+func TestRetryPermanentError(t *testing.T) {
+	status := int32(http.StatusBadRequest)
+	var calls int32
+	node := newTestNode(t, &status, &calls)
+
+	cli := New(node.URL, time.Second, WithRetries(3, time.Millisecond))
+	if _, err := cli.Ping(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 1 {
+		t.Fatalf("permanent errors should not be retried, got %d calls", calls)
+	}
+
+	atomic.StoreInt32(&status, http.StatusTooManyRequests)
+	if _, err := cli.Ping(context.Background()); err == nil {
+		t.Fatal("expected error")
+	}
+	if calls != 5 {
+		t.Fatalf("transient errors should be retried, got %d calls", calls)
+	}
+}
+
+func TestServerError(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(fmt.Sprintf(
+			`{"jsonrpc":"2.0","error":{"code":-32000,"message":"%s: space=foo"},"id":1}`,
+			chain.ErrSpaceExpired,
+		)))
+	}))
+	t.Cleanup(s.Close)
+
+	cli := New(s.URL, time.Second)
+	_, err := cli.Ping(context.Background())
+	if !errors.Is(err, chain.ErrSpaceExpired) {
+		t.Fatalf("expected %v, got %v", chain.ErrSpaceExpired, err)
+	}
+	if errors.Is(err, chain.ErrSpaceMissing) {
+		t.Fatalf("%v should only match the sentinel it reports", err)
+	}
+	var serr *ServerError
+	if !errors.As(err, &serr) || serr.Sentinel != chain.ErrSpaceExpired {
+		t.Fatalf("expected server error, got %T", err)
+	}
+}
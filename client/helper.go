@@ -55,6 +55,15 @@ func SignIssueTx(
 	if err != nil {
 		return ids.Empty, 0, err
 	}
+	if ret.magic != 0 {
+		utx, err := chain.ParseTypedData(td)
+		if err != nil {
+			return ids.Empty, 0, err
+		}
+		if err := ret.checkMagic(utx.GetMagic()); err != nil {
+			return ids.Empty, 0, err
+		}
+	}
 
 	dh, err := tdata.DigestHash(td)
 	if err != nil {
@@ -77,6 +86,34 @@ func SignIssueTx(
 	return txID, txCost, nil
 }
 
+// TxParams fetches the parameters transactions issued to [cli] must
+// reference (see [chain.TxParams]).
+func TxParams(ctx context.Context, cli Client) (*chain.TxParams, error) {
+	g, err := cli.Genesis(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_, _, chainID, err := cli.Network(ctx)
+	if err != nil {
+		return nil, err
+	}
+	la, err := cli.Accepted(ctx)
+	if err != nil {
+		return nil, err
+	}
+	price, blockCost, err := cli.SuggestedRawFee(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &chain.TxParams{
+		Genesis:   g,
+		ChainID:   chainID,
+		BlockID:   la,
+		Price:     price,
+		BlockCost: blockCost,
+	}, nil
+}
+
 // Signs and issues the transaction (local construction).
 func SignIssueRawTx(
 	ctx context.Context,
@@ -88,40 +125,23 @@ func SignIssueRawTx(
 	ret := &Op{}
 	ret.applyOpts(opts)
 
-	g, err := cli.Genesis(ctx)
-	if err != nil {
-		return ids.Empty, 0, err
-	}
-
-	la, err := cli.Accepted(ctx)
+	p, err := TxParams(ctx, cli)
 	if err != nil {
 		return ids.Empty, 0, err
 	}
-
-	price, blockCost, err := cli.SuggestedRawFee(ctx)
-	if err != nil {
+	g := p.Genesis
+	if err := ret.checkMagic(g.Magic); err != nil {
 		return ids.Empty, 0, err
 	}
-
-	utx.SetBlockID(la)
-	utx.SetMagic(g.Magic)
-	utx.SetPrice(price + blockCost/utx.FeeUnits(g))
-
-	dh, err := chain.DigestHash(utx)
+	b, err := p.Build(utx)
 	if err != nil {
 		return ids.Empty, 0, err
 	}
-
-	sig, err := chain.Sign(dh, priv)
+	tx, err := b.Sign(priv)
 	if err != nil {
 		return ids.Empty, 0, err
 	}
 
-	tx := chain.NewTx(utx, sig)
-	if err := tx.Init(g); err != nil {
-		return ids.Empty, 0, err
-	}
-
 	color.Yellow(
 		"issuing tx %s (fee units=%d, load units=%d, price=%d, blkID=%s)",
 		tx.ID(), tx.FeeUnits(g), tx.LoadUnits(g), tx.GetPrice(), tx.GetBlockID(),
@@ -134,7 +154,7 @@ func SignIssueRawTx(
 	if err := handleConfirmation(ctx, ret, cli, txID, priv); err != nil {
 		return ids.Empty, 0, err
 	}
-	return txID, utx.GetPrice() * utx.FeeUnits(g), nil
+	return txID, b.Cost(), nil
 }
 
 func handleConfirmation(
@@ -179,6 +199,7 @@ type Op struct {
 	pollTx  bool
 	space   string
 	balance bool
+	magic   uint64
 }
 
 type OpOption func(*Op)
@@ -202,3 +223,16 @@ func WithInfo(space string) OpOption {
 func WithBalance() OpOption {
 	return func(op *Op) { op.balance = true }
 }
+
+// Non-zero to refuse to sign transactions for a network with a different
+// magic.
+func WithMagic(magic uint64) OpOption {
+	return func(op *Op) { op.magic = magic }
+}
+
+func (op *Op) checkMagic(magic uint64) error {
+	if op.magic != 0 && op.magic != magic {
+		return fmt.Errorf("%w: expected %d but node has %d", ErrMagicMismatch, op.magic, magic)
+	}
+	return nil
+}
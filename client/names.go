@@ -0,0 +1,61 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+// maxNameRedirects is the most aliases [ResolveName] follows
+const maxNameRedirects = 8
+
+// NameRecord is the record a name points to
+type NameRecord struct {
+	// Path is where the record is stored (after following aliases)
+	Path  string
+	Kind  string
+	Value []byte
+	// Hops are the aliases followed, starting with the name resolved
+	Hops []string
+}
+
+// ResolveName answers what the name [path] (space/key) points to. Redirects
+// to other paths are followed, while any other record (including redirects
+// to URLs) is returned as-is.
+func ResolveName(ctx context.Context, cli Client, path string) (*NameRecord, error) {
+	hops := []string{}
+	seen := map[string]struct{}{}
+	for {
+		if _, ok := seen[path]; ok {
+			return nil, fmt.Errorf("%w: %s revisited", ErrRedirectLoop, path)
+		}
+		seen[path] = struct{}{}
+
+		exists, value, vmeta, err := cli.Resolve(ctx, path)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("%w: %s", ErrNameNotFound, path)
+		}
+		if vmeta.Kind == chain.ValueRedirect {
+			target, _, err := chain.ParseRedirect(value)
+			if err != nil {
+				return nil, err
+			}
+			if len(target) > 0 {
+				if len(hops) == maxNameRedirects {
+					return nil, fmt.Errorf("%w: stopped at %s", ErrTooManyRedirects, path)
+				}
+				hops = append(hops, path)
+				path = target
+				continue
+			}
+		}
+		return &NameRecord{Path: path, Kind: vmeta.Kind, Value: value, Hops: hops}, nil
+	}
+}
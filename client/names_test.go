@@ -0,0 +1,88 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+type nameRecord struct {
+	kind  string
+	value string
+}
+
+// namesClient serves [Resolve] from [records]
+type namesClient struct {
+	Client
+
+	records map[string]*nameRecord
+}
+
+func (c *namesClient) Resolve(_ context.Context, path string) (bool, []byte, *chain.ValueMeta, error) {
+	r, ok := c.records[path]
+	if !ok {
+		return false, nil, nil, nil
+	}
+	return true, []byte(r.value), &chain.ValueMeta{Kind: r.kind}, nil
+}
+
+func TestResolveName(t *testing.T) {
+	t.Parallel()
+
+	records := map[string]*nameRecord{
+		"site/www":   {kind: chain.ValueRedirect, value: "site/root"},
+		"site/root":  {kind: chain.ValueA, value: "192.0.2.1"},
+		"site/docs":  {kind: chain.ValueRedirect, value: "https://example.com/docs"},
+		"site/loop":  {kind: chain.ValueRedirect, value: "site/loop2"},
+		"site/loop2": {kind: chain.ValueRedirect, value: "site/loop"},
+		"site/gone":  {kind: chain.ValueRedirect, value: "site/missing"},
+	}
+	// site/chain0 -> site/chain1 -> ... -> site/chain9
+	for i := 0; i < maxNameRedirects+1; i++ {
+		records[fmt.Sprintf("site/chain%d", i)] = &nameRecord{kind: chain.ValueRedirect, value: fmt.Sprintf("site/chain%d", i+1)}
+	}
+	records[fmt.Sprintf("site/chain%d", maxNameRedirects+1)] = &nameRecord{kind: chain.ValueTXT, value: "end"}
+	cli := &namesClient{records: records}
+
+	tt := []struct {
+		path string
+		rec  *NameRecord
+		err  error
+	}{
+		{
+			path: "site/root",
+			rec:  &NameRecord{Path: "site/root", Kind: chain.ValueA, Value: []byte("192.0.2.1"), Hops: []string{}},
+		},
+		{
+			path: "site/www",
+			rec:  &NameRecord{Path: "site/root", Kind: chain.ValueA, Value: []byte("192.0.2.1"), Hops: []string{"site/www"}},
+		},
+		{
+			path: "site/docs",
+			rec: &NameRecord{
+				Path: "site/docs", Kind: chain.ValueRedirect, Value: []byte("https://example.com/docs"), Hops: []string{},
+			},
+		},
+		{path: "site/missing", err: ErrNameNotFound},
+		{path: "site/gone", err: ErrNameNotFound},
+		{path: "site/loop", err: ErrRedirectLoop},
+		{path: "site/chain0", err: ErrTooManyRedirects},
+		{path: "site/chain1"},
+	}
+	for _, tv := range tt {
+		rec, err := ResolveName(context.Background(), cli, tv.path)
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("%s: expected %v, got %v", tv.path, tv.err, err)
+		}
+		if tv.rec != nil && !reflect.DeepEqual(rec, tv.rec) {
+			t.Fatalf("%s: expected %+v, got %+v", tv.path, tv.rec, rec)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/rpc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gorilla/rpc/v2/json2"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+// ClientOp configures how a client communicates with the VM.
+type ClientOp struct {
+	authToken string
+	signer    *common.Address
+	preferred bool
+	pending   bool
+
+	endpoints []string
+	retries   int
+	backoff   time.Duration
+}
+
+type ClientOption func(*ClientOp)
+
+func (op *ClientOp) applyOpts(opts []ClientOption) {
+	for _, opt := range opts {
+		opt(op)
+	}
+}
+
+// WithAuthToken sends [token] as a bearer token with every request.
+func WithAuthToken(token string) ClientOption {
+	return func(op *ClientOp) { op.authToken = token }
+}
+
+// WithResponseSigner requires resolved values to be attested by [signer],
+// failing reads whose attestation is missing or invalid.
+func WithResponseSigner(signer common.Address) ClientOption {
+	return func(op *ClientOp) { op.signer = &signer }
+}
+
+// WithPreferredReads serves state reads (such as resolve, info, and balance)
+// from the node's preferred block instead of its last accepted block, so
+// transactions are visible as soon as the node verifies the block including
+// them. Preferred state may be reverted if the block is rejected.
+func WithPreferredReads() ClientOption {
+	return func(op *ClientOp) { op.preferred = true }
+}
+
+// WithPendingReads overlays the transactions in the node's mempool on state
+// reads, so wallets can show the anticipated result of transactions that have
+// not been accepted yet. This is best-effort: transactions that fail to
+// execute are skipped, and pending reads are never attested (so they fail
+// with [WithResponseSigner]).
+func WithPendingReads() ClientOption {
+	return func(op *ClientOp) { op.pending = true }
+}
+
+// WithEndpoints adds node URIs to fail over to when a request to the primary
+// URI (or the last one used) fails with a transient error.
+func WithEndpoints(uris ...string) ClientOption {
+	return func(op *ClientOp) { op.endpoints = append(op.endpoints, uris...) }
+}
+
+// WithRetries retries requests that fail with a transient error up to
+// [retries] times, waiting a jittered exponential backoff starting at
+// [backoff] between attempts.
+func WithRetries(retries int, backoff time.Duration) ClientOption {
+	return func(op *ClientOp) {
+		op.retries = retries
+		op.backoff = backoff
+	}
+}
+
+// requester creates a requester for [path] on each of [uris], in order of
+// preference.
+func (op *ClientOp) requester(uris []string, path string) rpc.EndpointRequester {
+	reqs := make([]rpc.EndpointRequester, len(uris))
+	for i, uri := range uris {
+		reqs[i] = rpc.NewEndpointRequester(fmt.Sprintf("%s%s", uri, path), "spacesvm")
+	}
+
+	var req rpc.EndpointRequester
+	retries := op.retries
+	if len(reqs) > 1 && retries < len(reqs)-1 {
+		// Try every endpoint at least once
+		retries = len(reqs) - 1
+	}
+	if retries == 0 {
+		req = reqs[0]
+	} else {
+		backoff := op.backoff
+		if backoff <= 0 {
+			backoff = defaultRetryBackoff
+		}
+		endpoints := make([]*endpoint, len(reqs))
+		for i, r := range reqs {
+			endpoints[i] = &endpoint{req: r}
+		}
+		req = &failoverRequester{retries: retries, backoff: backoff, endpoints: endpoints}
+	}
+
+	if len(op.authToken) > 0 {
+		req = &headerRequester{
+			EndpointRequester: req,
+			options:           []rpc.Option{rpc.WithHeader("Authorization", "Bearer "+op.authToken)},
+		}
+	}
+	return &errorRequester{EndpointRequester: req}
+}
+
+// headerRequester adds [options] to every request.
+type headerRequester struct {
+	rpc.EndpointRequester
+	options []rpc.Option
+}
+
+func (r *headerRequester) SendRequest(
+	ctx context.Context,
+	method string,
+	params interface{},
+	reply interface{},
+	options ...rpc.Option,
+) error {
+	return r.EndpointRequester.SendRequest(ctx, method, params, reply, append(r.options, options...)...)
+}
+
+// errorRequester returns the errors reported by the node as [ServerError]s.
+type errorRequester struct {
+	rpc.EndpointRequester
+}
+
+func (r *errorRequester) SendRequest(
+	ctx context.Context,
+	method string,
+	params interface{},
+	reply interface{},
+	options ...rpc.Option,
+) error {
+	err := r.EndpointRequester.SendRequest(ctx, method, params, reply, options...)
+	var jerr *json2.Error
+	if !errors.As(err, &jerr) {
+		return err
+	}
+	return &ServerError{Err: err, Sentinel: chain.ParseError(jerr.Message)}
+}
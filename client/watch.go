@@ -0,0 +1,101 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+// Change is a modification of a key in a watched space. [Old] and [OldMeta]
+// are nil if the key was created and [New] and [NewMeta] are nil if the key
+// was deleted.
+type Change struct {
+	Key string
+
+	Old     []byte
+	OldMeta *chain.ValueMeta
+	New     []byte
+	NewMeta *chain.ValueMeta
+}
+
+type watchedValue struct {
+	value []byte
+	meta  *chain.ValueMeta
+}
+
+// Watch invokes [f] for every change to the keys in [space] until [ctx] is
+// done or a request fails. The VM does not offer subscriptions, so [space] is
+// polled every [interval] and a key is considered changed when the
+// transaction that last wrote it changes.
+func Watch(ctx context.Context, cli Client, space string, interval time.Duration, f func(*Change)) error {
+	values, err := watchSnapshot(ctx, cli, space, nil)
+	if err != nil {
+		return err
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+
+		next, err := watchSnapshot(ctx, cli, space, values)
+		if err != nil {
+			return err
+		}
+		for key, nv := range next {
+			ov, ok := values[key]
+			switch {
+			case !ok:
+				f(&Change{Key: key, New: nv.value, NewMeta: nv.meta})
+			case ov.meta.TxID != nv.meta.TxID:
+				f(&Change{Key: key, Old: ov.value, OldMeta: ov.meta, New: nv.value, NewMeta: nv.meta})
+			}
+		}
+		for key, ov := range values {
+			if _, ok := next[key]; !ok {
+				f(&Change{Key: key, Old: ov.value, OldMeta: ov.meta})
+			}
+		}
+		values = next
+	}
+}
+
+// watchSnapshot returns the current values in [space], only resolving keys
+// that were written since [prev].
+func watchSnapshot(
+	ctx context.Context,
+	cli Client,
+	space string,
+	prev map[string]*watchedValue,
+) (map[string]*watchedValue, error) {
+	_, kvs, err := cli.Info(ctx, space)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]*watchedValue, len(kvs))
+	for _, kv := range kvs {
+		if pv, ok := prev[kv.Key]; ok && pv.meta.TxID == kv.ValueMeta.TxID {
+			values[kv.Key] = pv
+			continue
+		}
+		exists, v, vmeta, err := cli.Resolve(ctx, space+parser.Delimiter+kv.Key)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			// Deleted since listed
+			continue
+		}
+		values[kv.Key] = &watchedValue{value: v, meta: vmeta}
+	}
+	return values, nil
+}
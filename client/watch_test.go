@@ -0,0 +1,101 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package client
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+// watchClient serves [Info] and [Resolve] from [values]
+type watchClient struct {
+	Client
+
+	l      sync.Mutex
+	values map[string]string
+	txs    map[string]ids.ID
+	polls  int
+	polled chan struct{}
+}
+
+func (c *watchClient) set(key, value string) {
+	c.l.Lock()
+	defer c.l.Unlock()
+	if len(value) == 0 {
+		delete(c.values, key)
+		delete(c.txs, key)
+		return
+	}
+	c.values[key] = value
+	c.txs[key] = ids.GenerateTestID()
+}
+
+func (c *watchClient) Info(_ context.Context, space string) (*chain.SpaceInfo, []*chain.KeyValueMeta, error) {
+	c.l.Lock()
+	defer c.l.Unlock()
+	c.polls++
+	if c.polls == 2 {
+		close(c.polled)
+	}
+	kvs := []*chain.KeyValueMeta{}
+	for k := range c.values {
+		kvs = append(kvs, &chain.KeyValueMeta{Key: k, ValueMeta: &chain.ValueMeta{TxID: c.txs[k]}})
+	}
+	return &chain.SpaceInfo{}, kvs, nil
+}
+
+func (c *watchClient) Resolve(_ context.Context, path string) (bool, []byte, *chain.ValueMeta, error) {
+	c.l.Lock()
+	defer c.l.Unlock()
+	key := strings.SplitN(path, "/", 2)[1]
+	v, ok := c.values[key]
+	if !ok {
+		return false, nil, nil, nil
+	}
+	return true, []byte(v), &chain.ValueMeta{TxID: c.txs[key]}, nil
+}
+
+func TestWatch(t *testing.T) {
+	cli := &watchClient{values: map[string]string{}, txs: map[string]ids.ID{}, polled: make(chan struct{})}
+	cli.set("a", "1")
+	cli.set("b", "2")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	changes := make(chan *Change, 8)
+	done := make(chan error)
+	go func() {
+		done <- Watch(ctx, cli, "space", time.Millisecond, func(c *Change) { changes <- c })
+	}()
+
+	// Wait for the initial snapshot to complete before making changes
+	<-cli.polled
+	cli.set("a", "3")
+	c := <-changes
+	if c.Key != "a" || string(c.Old) != "1" || string(c.New) != "3" {
+		t.Fatalf("unexpected update %+v", c)
+	}
+	cli.set("b", "")
+	c = <-changes
+	if c.Key != "b" || string(c.Old) != "2" || c.New != nil {
+		t.Fatalf("unexpected delete %+v", c)
+	}
+	cli.set("c", "4")
+	c = <-changes
+	if c.Key != "c" || c.Old != nil || string(c.New) != "4" {
+		t.Fatalf("unexpected create %+v", c)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+}
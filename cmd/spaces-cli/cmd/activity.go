@@ -10,6 +10,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/vm"
 )
 
 var activityCmd = &cobra.Command{
@@ -22,13 +23,12 @@ func activityFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 0 {
 		return fmt.Errorf("expected exactly 0 arguments, got %d", len(args))
 	}
-	cli := client.New(uri, requestTimeout)
+	cli := client.New(uri, requestTimeout, clientOptions()...)
 	activity, err := cli.RecentActivity(context.Background())
 	if err != nil {
 		return err
 	}
-	if err := client.PPActivity(activity); err != nil {
-		return err
-	}
-	return nil
+	return printResult(&vm.RecentActivityReply{Activity: activity}, func() error {
+		return client.PPActivity(activity)
+	})
 }
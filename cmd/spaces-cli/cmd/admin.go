@@ -0,0 +1,226 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/vm"
+)
+
+var adminCmd = &cobra.Command{
+	Use:   "admin [command]",
+	Short: "Node operator commands (requires the admin API to be enabled)",
+}
+
+var adminCompactCmd = &cobra.Command{
+	Use:   "compact [options]",
+	Short: "Compacts the database of the node",
+	RunE:  adminCompactFunc,
+}
+
+var adminPruneCmd = &cobra.Command{
+	Use:   "prune [options]",
+	Short: "Prunes expired spaces, removed values, and old indexes on the node",
+	Long: `
+Prunes the keys of expired spaces, reclaims the values deleted or
+overwritten at least "deletedValueRetention" blocks ago, and trims the
+tx index and history of blocks older than "indexRetention" (see the VM
+config) without waiting for the background pruner.
+`,
+	RunE: adminPruneFunc,
+}
+
+var adminReindexCmd = &cobra.Command{
+	Use:   "reindex [options]",
+	Short: "Rebuilds the secondary indexes on the node",
+	Long: `
+Rebuilds the block height index, tx index, and space and sender history
+by replaying every accepted block, and repairs the expiry and owned
+indexes from the stored spaces. Use it if the indexes are corrupted or
+were added after blocks were accepted. Blocks are not accepted while the
+reindex runs. Progress is logged by the node, which keeps reindexing if
+this request times out.
+`,
+	RunE: adminReindexFunc,
+}
+
+var adminBackupCmd = &cobra.Command{
+	Use:   "backup [options] <dir>",
+	Short: "Writes a consistent backup of the database to <dir> under the node's backupRoot",
+	RunE:  adminBackupFunc,
+}
+
+var adminExportCmd = &cobra.Command{
+	Use:   "export [options] <dir>",
+	Short: "Writes every accepted block to <dir> on the node",
+	Long: `
+Streams every accepted block after genesis into <dir> on the node, as
+length-prefixed block bytes ("raw") or one JSON object per line
+("jsonl", with the decoded transactions alongside the block bytes). If
+--state is set, a backup of the database is written to <dir> as well.
+<dir> is relative to "backupRoot" in the node's VM config, and exports
+are disabled until it is set.
+
+To replay the blocks into a fresh node, set "importDir" to <dir> in the
+VM config and start the node with an empty database. Setting both
+"restoreDir" and "importDir" restores the backup first and replays only
+the blocks accepted after it.
+`,
+	RunE: adminExportFunc,
+}
+
+var (
+	exportFormat string
+	exportState  bool
+)
+
+var adminVerifyBackupCmd = &cobra.Command{
+	Use:   "verify-backup [options] <dir>",
+	Short: "Verifies that the backup in <dir> can be restored",
+	Long: `
+Restores the backup in <dir> into memory and verifies the restored
+last accepted block.
+
+To restore a node, set "restoreDir" to <dir> in the VM config and
+start the node with an empty database.
+`,
+	RunE: adminVerifyBackupFunc,
+}
+
+func init() {
+	adminExportCmd.PersistentFlags().StringVar(
+		&exportFormat,
+		"format",
+		vm.ExportFormatRaw,
+		"format of the exported blocks (raw or jsonl)",
+	)
+	adminExportCmd.PersistentFlags().BoolVar(
+		&exportState,
+		"state",
+		false,
+		"also write a backup of the database",
+	)
+	adminCmd.AddCommand(
+		adminCompactCmd,
+		adminPruneCmd,
+		adminReindexCmd,
+		adminBackupCmd,
+		adminExportCmd,
+		adminVerifyBackupCmd,
+	)
+}
+
+func adminCompactFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("expected exactly 0 arguments, got %d", len(args))
+	}
+	cli := client.NewAdmin(uri, requestTimeout, clientOptions()...)
+	ranges, err := cli.Compact(context.Background())
+	if err != nil {
+		return err
+	}
+	return printResult(&vm.CompactReply{Ranges: ranges}, func() error {
+		color.Green("compacted %d ranges", ranges)
+		return nil
+	})
+}
+
+func adminPruneFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("expected exactly 0 arguments, got %d", len(args))
+	}
+	cli := client.NewAdmin(uri, requestTimeout, clientOptions()...)
+	stats, err := cli.Prune(context.Background())
+	if err != nil {
+		return err
+	}
+	return printResult(stats, func() error {
+		color.Green(
+			"pruned %d spaces (%d keys), reclaimed %d values (%d bytes), and trimmed the indexes of %d blocks (%d entries)",
+			stats.Spaces, stats.Keys, stats.Values, stats.ValueBytes, stats.TrimmedBlocks, stats.IndexEntries,
+		)
+		return nil
+	})
+}
+
+func adminReindexFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("expected exactly 0 arguments, got %d", len(args))
+	}
+	cli := client.NewAdmin(uri, requestTimeout, clientOptions()...)
+	stats, err := cli.Reindex(context.Background())
+	if err != nil {
+		return err
+	}
+	return printResult(stats, func() error {
+		color.Green(
+			"reindexed %d blocks (%d txs), cleared %d entries, and repaired %d expiry and owned entries",
+			stats.Blocks, stats.Txs, stats.Cleared, stats.Repairs,
+		)
+		return nil
+	})
+}
+
+func adminBackupFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected exactly 1 argument, got %d", len(args))
+	}
+	cli := client.NewAdmin(uri, requestTimeout, clientOptions()...)
+	meta, err := cli.Backup(context.Background(), args[0])
+	if err != nil {
+		return err
+	}
+	return printResult(meta, func() error {
+		color.Green(
+			"created backup in %s (lastAccepted=%s height=%d entries=%d)",
+			args[0], meta.LastAccepted, meta.Height, meta.Entries,
+		)
+		return nil
+	})
+}
+
+func adminExportFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected exactly 1 argument, got %d", len(args))
+	}
+	cli := client.NewAdmin(uri, requestTimeout, clientOptions()...)
+	meta, err := cli.Export(context.Background(), args[0], exportFormat, exportState)
+	if err != nil {
+		return err
+	}
+	return printResult(meta, func() error {
+		color.Green(
+			"exported %d blocks to %s (format=%s lastAccepted=%s height=%d)",
+			meta.Blocks, args[0], meta.Format, meta.LastAccepted, meta.Height,
+		)
+		if meta.State != nil {
+			color.Green("wrote backup of %d entries", meta.State.Entries)
+		}
+		return nil
+	})
+}
+
+func adminVerifyBackupFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected exactly 1 argument, got %d", len(args))
+	}
+	meta, err := vm.RestoreBackup(memdb.New(), args[0])
+	if err != nil {
+		return err
+	}
+	return printResult(meta, func() error {
+		color.Green(
+			"verified backup in %s (lastAccepted=%s height=%d entries=%d)",
+			args[0], meta.LastAccepted, meta.Height, meta.Entries,
+		)
+		return nil
+	})
+}
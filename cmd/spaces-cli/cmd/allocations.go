@@ -0,0 +1,120 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	smath "github.com/ethereum/go-ethereum/common/math"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+var (
+	ErrInvalidAddress       = errors.New("invalid address")
+	ErrDuplicateAllocation  = errors.New("duplicate allocation")
+	ErrAllocationOverflow   = errors.New("total allocation overflows")
+	ErrInvalidAllocationRow = errors.New("invalid allocation row")
+)
+
+// allocations is the JSON format accepted by "--allocations". A plain
+// array of [chain.CustomAllocation] is also accepted.
+type allocations struct {
+	Allocations  []*chain.CustomAllocation `json:"allocations"`
+	CustomSpaces []*chain.CustomSpace      `json:"customSpaces"`
+}
+
+// loadAllocations parses a CSV (address,balance) or JSON allocations file and
+// validates addresses and totals. It returns the total units allocated.
+func loadAllocations(p string) (*allocations, uint64, error) {
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var f *allocations
+	if strings.EqualFold(filepath.Ext(p), ".csv") {
+		f, err = parseCSVAllocations(b)
+	} else {
+		f, err = parseJSONAllocations(b)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	seen := map[common.Address]struct{}{}
+	total := uint64(0)
+	for _, alloc := range f.Allocations {
+		if alloc.Address == (common.Address{}) {
+			return nil, 0, fmt.Errorf("%w: empty address", ErrInvalidAddress)
+		}
+		if _, ok := seen[alloc.Address]; ok {
+			return nil, 0, fmt.Errorf("%w: %s", ErrDuplicateAllocation, alloc.Address)
+		}
+		seen[alloc.Address] = struct{}{}
+		var overflow bool
+		total, overflow = smath.SafeAdd(total, alloc.Balance)
+		if overflow {
+			return nil, 0, ErrAllocationOverflow
+		}
+	}
+	return f, total, nil
+}
+
+func parseJSONAllocations(b []byte) (*allocations, error) {
+	if t := bytes.TrimSpace(b); len(t) > 0 && t[0] == '[' {
+		allocs := []*chain.CustomAllocation{}
+		if err := json.Unmarshal(b, &allocs); err != nil {
+			return nil, err
+		}
+		return &allocations{Allocations: allocs}, nil
+	}
+	f := new(allocations)
+	if err := json.Unmarshal(b, f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func parseCSVAllocations(b []byte) (*allocations, error) {
+	r := csv.NewReader(bytes.NewReader(b))
+	r.FieldsPerRecord = 2
+	r.TrimLeadingSpace = true
+	f := &allocations{}
+	for row := 1; ; row++ {
+		rec, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		// Allow an optional header
+		if row == 1 && strings.EqualFold(rec[0], "address") {
+			continue
+		}
+		if !common.IsHexAddress(rec[0]) {
+			return nil, fmt.Errorf("%w: row %d has address %q", ErrInvalidAddress, row, rec[0])
+		}
+		bal, err := strconv.ParseUint(rec[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: row %d has balance %q: %v", ErrInvalidAllocationRow, row, rec[1], err)
+		}
+		f.Allocations = append(f.Allocations, &chain.CustomAllocation{
+			Address: common.HexToAddress(rec[0]),
+			Balance: bal,
+		})
+	}
+	return f, nil
+}
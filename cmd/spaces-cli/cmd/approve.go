@@ -0,0 +1,77 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/client"
+)
+
+var approveCmd = &cobra.Command{
+	Use:   "approve [options] <action hash>",
+	Short: "Approves an op of a space the sender co-signs",
+	Long: `
+Approves the op with the given action hash as the co-signer of a space
+policy. The action hash is the "action" returned by spacesvm.encodeTx
+for the op, which does not depend on its block ID or price, so the owner
+may issue the op any time after the approval is accepted. Each approval
+allows the op once.
+
+$ spaces-cli approve 0x...
+<<COMMENT
+approved 0x...
+COMMENT
+`,
+	RunE: approveFunc,
+}
+
+// approveResult is the JSON output of approve
+type approveResult struct {
+	TxID   ids.ID      `json:"txId"`
+	Cost   uint64      `json:"cost"`
+	Action common.Hash `json:"action"`
+}
+
+func approveFunc(cmd *cobra.Command, args []string) error {
+	priv, err := loadPrivateKey()
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("expected exactly 1 argument, got %d", len(args))
+	}
+	b, err := hexutil.Decode(args[0])
+	if err != nil || len(b) != common.HashLength {
+		return fmt.Errorf("invalid action hash %q", args[0])
+	}
+
+	utx := &chain.ApproveTx{
+		BaseTx: &chain.BaseTx{},
+		Action: common.BytesToHash(b),
+	}
+
+	cli := client.New(uri, requestTimeout, clientOptions()...)
+	opts := txOptions()
+	if verbose {
+		opts = append(opts, client.WithBalance())
+	}
+	txID, cost, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, opts...)
+	if err != nil {
+		return err
+	}
+
+	return printResult(&approveResult{TxID: txID, Cost: cost, Action: utx.Action}, func() error {
+		color.Green("approved %s", utx.Action.Hex())
+		return nil
+	})
+}
@@ -0,0 +1,193 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/formatting/address"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/vm"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export [options] <destination-chain> <to> <units>",
+	Short: "Exports units to another chain on the same subnet",
+	Long: `
+Debits units from the sender and creates a UTXO of the atomic asset on the
+destination chain owned by <to>. <to> may be a bech32 address (X-avax1...) or
+a CB58 short ID.
+
+$ spaces-cli export 2oYMBNV4eNHyqk2fjjV5nVQLDbtmNJzq5s3qs3Lo6ftnC6FByM X-avax1... 100000
+`,
+	RunE: exportFunc,
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import [options] <source-chain> [utxo-id]",
+	Short: "Imports units exported from another chain on the same subnet",
+	Long: `
+Consumes a UTXO of the atomic asset that the source chain exported to this
+chain and credits its units to the sender. If no UTXO is provided, every
+importable UTXO owned by the sender is imported.
+
+The UTXO must be owned (with threshold 1) by the sender's short address,
+which is printed by this command.
+
+$ spaces-cli import 2oYMBNV4eNHyqk2fjjV5nVQLDbtmNJzq5s3qs3Lo6ftnC6FByM
+`,
+	RunE: importFunc,
+}
+
+// atomicResult is the JSON output of export and import
+type atomicResult struct {
+	TxID      ids.ID      `json:"txId"`
+	Cost      uint64      `json:"cost"`
+	PeerChain ids.ID      `json:"peerChain"`
+	Owner     ids.ShortID `json:"owner"`
+	UTXOID    ids.ID      `json:"utxoID"`
+	Units     uint64      `json:"units"`
+}
+
+func exportFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 3 {
+		return fmt.Errorf("expected exactly 3 arguments, got %d", len(args))
+	}
+	destination, err := ids.FromString(args[0])
+	if err != nil {
+		return fmt.Errorf("%w: failed to parse destination chain", err)
+	}
+	to, err := parseShortAddress(args[1])
+	if err != nil {
+		return err
+	}
+	units, err := strconv.ParseUint(args[2], 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w: failed to parse units", err)
+	}
+
+	priv, err := loadPrivateKey()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+	cli := client.New(uri, requestTimeout, clientOptions()...)
+	g, err := cli.Genesis(ctx)
+	if err != nil {
+		return err
+	}
+	utx := &chain.ExportTx{
+		BaseTx:           &chain.BaseTx{},
+		DestinationChain: destination,
+		To:               to,
+		Units:            units,
+	}
+	txID, cost, err := client.SignIssueRawTx(ctx, cli, utx, priv, txOptions()...)
+	if err != nil {
+		return err
+	}
+	utxo := utx.UTXO(g, txID)
+
+	return printResult(&atomicResult{
+		TxID:      txID,
+		Cost:      cost,
+		PeerChain: destination,
+		Owner:     to,
+		UTXOID:    utxo.InputID(),
+		Units:     units,
+	}, func() error {
+		color.Green("exported %d to %s on %s (utxo=%s)", units, to, destination, utxo.InputID())
+		return nil
+	})
+}
+
+func importFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 && len(args) != 2 {
+		return fmt.Errorf("expected 1 or 2 arguments, got %d", len(args))
+	}
+	source, err := ids.FromString(args[0])
+	if err != nil {
+		return fmt.Errorf("%w: failed to parse source chain", err)
+	}
+
+	priv, err := loadPrivateKey()
+	if err != nil {
+		return err
+	}
+	owner := chain.ShortAddress(&priv.PublicKey)
+	color.Blue("importing UTXOs owned by %s", owner)
+
+	ctx := context.Background()
+	cli := client.New(uri, requestTimeout, clientOptions()...)
+	utxos, err := cli.AtomicUTXOs(ctx, source, owner)
+	if err != nil {
+		return err
+	}
+	if len(args) == 2 {
+		utxoID, err := ids.FromString(args[1])
+		if err != nil {
+			return fmt.Errorf("%w: failed to parse UTXO ID", err)
+		}
+		var found *vm.AtomicUTXO
+		for _, utxo := range utxos {
+			if utxo.UTXOID == utxoID {
+				found = utxo
+				break
+			}
+		}
+		if found == nil {
+			return fmt.Errorf("%w: %s", chain.ErrUTXOMissing, utxoID)
+		}
+		utxos = []*vm.AtomicUTXO{found}
+	}
+	if len(utxos) == 0 {
+		return fmt.Errorf("%w: no UTXOs owned by %s on %s", chain.ErrUTXOMissing, owner, source)
+	}
+
+	results := make([]*atomicResult, 0, len(utxos))
+	for _, utxo := range utxos {
+		txID, cost, err := client.SignIssueRawTx(ctx, cli, &chain.ImportTx{
+			BaseTx:      &chain.BaseTx{},
+			SourceChain: source,
+			UTXOID:      utxo.UTXOID,
+		}, priv, txOptions()...)
+		if err != nil {
+			return err
+		}
+		results = append(results, &atomicResult{
+			TxID:      txID,
+			Cost:      cost,
+			PeerChain: source,
+			Owner:     owner,
+			UTXOID:    utxo.UTXOID,
+			Units:     utxo.Amount,
+		})
+	}
+
+	return printResult(results, func() error {
+		for _, r := range results {
+			color.Green("imported %d from %s (utxo=%s)", r.Units, source, r.UTXOID)
+		}
+		return nil
+	})
+}
+
+// parseShortAddress parses a bech32 address (X-avax1...) or a CB58 short ID
+func parseShortAddress(s string) (ids.ShortID, error) {
+	if addr, err := address.ParseToID(s); err == nil {
+		return addr, nil
+	}
+	addr, err := ids.ShortFromString(s)
+	if err != nil {
+		return ids.ShortEmpty, fmt.Errorf("invalid address %q", s)
+	}
+	return addr, nil
+}
@@ -0,0 +1,384 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"runtime"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+const (
+	// how often the block ID and price used to sign bench txs are refreshed
+	// (txs must reference a block within the lookback window)
+	benchRefreshInterval = 5 * time.Second
+	benchPollInterval    = 250 * time.Millisecond
+)
+
+var (
+	benchTxs       int
+	benchRate      float64
+	benchWorkers   int
+	benchValueSize int
+	benchTimeout   time.Duration
+)
+
+func init() {
+	benchCmd.PersistentFlags().IntVar(
+		&benchTxs,
+		"txs",
+		100,
+		"number of set transactions to issue",
+	)
+	benchCmd.PersistentFlags().Float64Var(
+		&benchRate,
+		"rate",
+		10,
+		"target submission rate (transactions per second)",
+	)
+	benchCmd.PersistentFlags().IntVar(
+		&benchWorkers,
+		"workers",
+		runtime.NumCPU(),
+		"number of transactions signed in parallel",
+	)
+	benchCmd.PersistentFlags().IntVar(
+		&benchValueSize,
+		"value-size",
+		32,
+		"size of each random value (in bytes)",
+	)
+	benchCmd.PersistentFlags().DurationVar(
+		&benchTimeout,
+		"timeout",
+		time.Minute,
+		"how long to wait for each transaction to be accepted",
+	)
+}
+
+var benchCmd = &cobra.Command{
+	Use:   "bench [options] <space>",
+	Short: "Soak tests the network with set transactions",
+	Long: `
+Claims the space (if it is not already claimed), then signs set transactions
+for distinct keys in parallel and submits them at the target rate. Once every
+transaction is accepted (or times out), the achieved throughput and
+acceptance latency percentiles are reported. Interrupting the benchmark stops
+submission and reports on the transactions already submitted.
+
+Every transaction pays fees, so use a funded key on a test network.
+
+$ spaces-cli bench benchspace --txs 1000 --rate 50
+`,
+	RunE: benchFunc,
+}
+
+// benchResult is the JSON output of bench
+type benchResult struct {
+	Submitted int `json:"submitted"`
+	Accepted  int `json:"accepted"`
+	// Transactions rejected on submission or not accepted before the timeout
+	Failed   int           `json:"failed"`
+	Duration time.Duration `json:"duration"`
+	// Accepted transactions per second
+	Throughput float64 `json:"throughput"`
+	TotalCost  uint64  `json:"totalCost"`
+
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+	P99 time.Duration `json:"p99"`
+	Max time.Duration `json:"max"`
+}
+
+type benchTx struct {
+	tx   *chain.Transaction
+	cost uint64
+}
+
+// benchParams are the values every bench tx must reference
+type benchParams struct {
+	l sync.RWMutex
+	p chain.TxParams
+}
+
+func (p *benchParams) refresh(ctx context.Context, cli client.Client) error {
+	la, err := cli.Accepted(ctx)
+	if err != nil {
+		return err
+	}
+	price, blockCost, err := cli.SuggestedRawFee(ctx)
+	if err != nil {
+		return err
+	}
+	p.l.Lock()
+	p.p.BlockID, p.p.Price, p.p.BlockCost = la, price, blockCost
+	p.l.Unlock()
+	return nil
+}
+
+// sign mirrors [client.SignIssueRawTx] without issuing the tx
+func (p *benchParams) sign(utx chain.UnsignedTransaction, priv *ecdsa.PrivateKey) (*benchTx, error) {
+	p.l.RLock()
+	params := p.p
+	p.l.RUnlock()
+
+	b, err := params.Build(utx)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := b.Sign(priv)
+	if err != nil {
+		return nil, err
+	}
+	return &benchTx{tx: tx, cost: b.Cost()}, nil
+}
+
+func benchFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected exactly 1 argument, got %d", len(args))
+	}
+	space := args[0]
+	if err := parser.CheckContents(space); err != nil {
+		return fmt.Errorf("%w: failed to verify space", err)
+	}
+	if benchTxs <= 0 || benchRate <= 0 || benchWorkers <= 0 || benchValueSize <= 0 {
+		return errors.New("--txs, --rate, --workers, and --value-size must be greater than 0")
+	}
+
+	priv, err := loadPrivateKey()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	cli := client.New(uri, requestTimeout, clientOptions()...)
+	if err := benchClaim(ctx, cli, space, priv); err != nil {
+		return err
+	}
+
+	p, err := client.TxParams(ctx, cli)
+	if err != nil {
+		return err
+	}
+	params := &benchParams{p: *p}
+	go func() {
+		t := time.NewTicker(benchRefreshInterval)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				if err := params.refresh(ctx, cli); err != nil {
+					color.Red("unable to refresh block ID: %v", err)
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	// Sign in parallel so signing does not limit the submission rate
+	jobs := make(chan int)
+	signed := make(chan *benchTx, benchWorkers)
+	var signers sync.WaitGroup
+	for i := 0; i < benchWorkers; i++ {
+		signers.Add(1)
+		go func() {
+			defer signers.Done()
+			for i := range jobs {
+				value := make([]byte, benchValueSize)
+				if _, err := rand.Read(value); err != nil {
+					color.Red("unable to generate value: %v", err)
+					continue
+				}
+				tx, err := params.sign(&chain.SetTx{
+					BaseTx: &chain.BaseTx{},
+					Space:  space,
+					Key:    fmt.Sprintf("bench%d", i),
+					Value:  value,
+				}, priv)
+				if err != nil {
+					color.Red("unable to sign tx: %v", err)
+					continue
+				}
+				select {
+				case signed <- tx:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		defer close(jobs)
+		for i := 0; i < benchTxs; i++ {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	go func() {
+		signers.Wait()
+		close(signed)
+	}()
+
+	color.Blue("issuing %d txs to %s at %.2f txs/s", benchTxs, space, benchRate)
+	var (
+		l         sync.Mutex
+		latencies []time.Duration
+		result    = &benchResult{}
+		pollers   sync.WaitGroup
+	)
+	start := time.Now()
+	t := time.NewTicker(time.Duration(float64(time.Second) / benchRate))
+	defer t.Stop()
+submit:
+	for btx := range signed {
+		select {
+		case <-t.C:
+		case <-sigs:
+			color.Yellow("interrupted, waiting for submitted txs")
+			break submit
+		}
+
+		submitted := time.Now()
+		txID, err := cli.IssueRawTx(ctx, btx.tx.Bytes())
+		l.Lock()
+		result.Submitted++
+		if err != nil {
+			result.Failed++
+		}
+		count := result.Submitted
+		l.Unlock()
+		if err != nil {
+			color.Red("unable to issue tx: %v", err)
+			continue
+		}
+		if count%100 == 0 {
+			color.Cyan("submitted %d/%d txs", count, benchTxs)
+		}
+
+		pollers.Add(1)
+		go func(txID ids.ID, cost uint64) {
+			defer pollers.Done()
+			latency, err := benchWait(ctx, cli, txID, submitted)
+			l.Lock()
+			defer l.Unlock()
+			if err != nil {
+				color.Red("tx %s not accepted: %v", txID, err)
+				result.Failed++
+				return
+			}
+			latencies = append(latencies, latency)
+			result.TotalCost += cost
+		}(txID, btx.cost)
+	}
+	pollers.Wait()
+	cancel()
+
+	result.Duration = time.Since(start)
+	result.Accepted = len(latencies)
+	result.Throughput = float64(result.Accepted) / result.Duration.Seconds()
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	result.P50 = percentile(latencies, 50)
+	result.P90 = percentile(latencies, 90)
+	result.P99 = percentile(latencies, 99)
+	result.Max = percentile(latencies, 100)
+
+	return printResult(result, func() error {
+		color.Green(
+			"accepted %d/%d txs (%d failed) in %v (%.2f txs/s, total cost %d)",
+			result.Accepted, result.Submitted, result.Failed,
+			result.Duration.Truncate(time.Millisecond), result.Throughput, result.TotalCost,
+		)
+		color.Green(
+			"acceptance latency: p50=%v p90=%v p99=%v max=%v",
+			result.P50, result.P90, result.P99, result.Max,
+		)
+		return nil
+	})
+}
+
+// benchClaim claims [space] unless it is already owned by [priv]
+func benchClaim(ctx context.Context, cli client.Client, space string, priv *ecdsa.PrivateKey) error {
+	sender := crypto.PubkeyToAddress(priv.PublicKey)
+	claimed, err := cli.Claimed(ctx, space)
+	if err != nil {
+		return err
+	}
+	if claimed {
+		info, _, err := cli.Info(ctx, space)
+		if err != nil {
+			return err
+		}
+		if info.Owner != sender {
+			return fmt.Errorf("%s is owned by %s", space, info.Owner)
+		}
+		return nil
+	}
+	_, _, err = client.SignIssueRawTx(ctx, cli, &chain.ClaimTx{
+		BaseTx: &chain.BaseTx{},
+		Space:  space,
+	}, priv, txOptions()...)
+	return err
+}
+
+// benchWait returns how long after [submitted] [txID] was accepted
+func benchWait(ctx context.Context, cli client.Client, txID ids.ID, submitted time.Time) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, benchTimeout)
+	defer cancel()
+
+	t := time.NewTicker(benchPollInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
+		accepted, err := cli.HasTx(ctx, txID)
+		if err != nil {
+			continue
+		}
+		if accepted {
+			return time.Since(submitted), nil
+		}
+	}
+}
+
+// percentile returns the [p]th percentile of the sorted [durations]
+func percentile(durations []time.Duration, p int) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	i := (len(durations)*p+99)/100 - 1
+	if i < 0 {
+		i = 0
+	}
+	return durations[i]
+}
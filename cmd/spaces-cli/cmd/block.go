@@ -0,0 +1,84 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/vm"
+)
+
+var blockCmd = &cobra.Command{
+	Use:   "block [options] <block ID|height>",
+	Short: "Prints the full contents of an accepted block",
+	RunE:  blockFunc,
+}
+
+func blockFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected exactly 1 argument, got %d", len(args))
+	}
+
+	cli := client.New(uri, requestTimeout, clientOptions()...)
+	var (
+		blk *vm.BlockDetail
+		err error
+	)
+	if height, perr := strconv.ParseUint(args[0], 10, 64); perr == nil {
+		blk, err = cli.BlockAt(context.Background(), height)
+	} else {
+		blkID, perr := ids.FromString(args[0])
+		if perr != nil {
+			return fmt.Errorf("%w: failed to parse block ID or height", perr)
+		}
+		blk, err = cli.Block(context.Background(), blkID)
+	}
+	if err != nil {
+		return err
+	}
+
+	return printResult(blk, func() error {
+		color.Cyan(
+			"block %s: height=%d parent=%s timestamp=%s price=%d cost=%d txs=%d",
+			blk.BlockID, blk.Height, blk.Parent, time.Unix(blk.Timestamp, 0),
+			blk.Price, blk.Cost, len(blk.Txs),
+		)
+		for _, tx := range blk.Txs {
+			ppTx(tx)
+		}
+		return nil
+	})
+}
+
+// ppTx prints every field of [tx], including the values it signed
+func ppTx(tx *vm.TxDetail) {
+	color.Green("tx %s (%s)", tx.TxID, tx.Type)
+	color.Cyan("  sender=%s", tx.Sender)
+	color.Cyan("  signature=%s", tx.Signature)
+	color.Cyan("  digest=%s", tx.DigestHash)
+	color.Cyan(
+		"  size=%d price=%d feeUnits=%d loadUnits=%d cost=%d",
+		tx.Size, tx.Price, tx.FeeUnits, tx.LoadUnits, tx.Cost,
+	)
+	if tx.TypedData == nil {
+		return
+	}
+	fields := make([]string, 0, len(tx.TypedData.Message))
+	for k := range tx.TypedData.Message {
+		fields = append(fields, k)
+	}
+	sort.Strings(fields)
+	for _, k := range fields {
+		color.Cyan("  %s=%v", k, tx.TypedData.Message[k])
+	}
+}
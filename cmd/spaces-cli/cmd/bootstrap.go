@@ -0,0 +1,173 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/vm"
+)
+
+var (
+	snapshotURL    string
+	snapshotDir    string
+	trustedBlockID string
+	vmConfigFile   string
+)
+
+func init() {
+	bootstrapCmd.PersistentFlags().StringVar(
+		&snapshotURL,
+		"from-snapshot",
+		"",
+		"URL of a published snapshot (a directory written by admin backup)",
+	)
+	bootstrapCmd.PersistentFlags().StringVar(
+		&snapshotDir,
+		"dir",
+		filepath.Join(workDir, "snapshot"),
+		"directory to download the snapshot into",
+	)
+	bootstrapCmd.PersistentFlags().StringVar(
+		&trustedBlockID,
+		"trusted-block-id",
+		"",
+		"block ID the snapshot must end at (defaults to the block at its height on --endpoint)",
+	)
+	bootstrapCmd.PersistentFlags().StringVar(
+		&vmConfigFile,
+		"vm-config",
+		"",
+		"VM config file of the node to update with the restore directory",
+	)
+}
+
+var bootstrapCmd = &cobra.Command{
+	Use:   "bootstrap [options]",
+	Short: "Downloads and verifies a snapshot to restore a node from",
+	Long: `
+Downloads the snapshot published at --from-snapshot, restores it into
+memory to verify its checksum and last accepted block, and checks that
+its last accepted block matches a trusted block: --trusted-block-id if
+set, otherwise the block at the same height on --endpoint. Blocks do
+not commit to a state root, so the snapshot is only as trustworthy as
+the node that published it and the block it is checked against.
+
+If --vm-config is set, "restoreDir" is set to the snapshot directory in
+that file, so the node restores the snapshot and resumes from its height
+the next time it starts with an empty database.
+
+$ spaces-cli bootstrap --from-snapshot https://example.com/snapshots/latest \
+    --vm-config ~/.avalanchego/configs/chains/<chainID>/config.json
+`,
+	RunE: bootstrapFunc,
+}
+
+// bootstrapResult is the JSON output of bootstrap
+type bootstrapResult struct {
+	Dir      string             `json:"dir"`
+	Metadata *vm.BackupMetadata `json:"metadata"`
+	VMConfig string             `json:"vmConfig,omitempty"`
+}
+
+func bootstrapFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("expected exactly 0 arguments, got %d", len(args))
+	}
+	if len(snapshotURL) == 0 {
+		return errors.New("--from-snapshot is required")
+	}
+	dir, err := filepath.Abs(snapshotDir)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	color.Blue("downloading snapshot from %s to %s", snapshotURL, dir)
+	if err := vm.FetchBackup(ctx, snapshotURL, dir); err != nil {
+		return err
+	}
+	meta, err := vm.RestoreBackup(memdb.New(), dir)
+	if err != nil {
+		return err
+	}
+
+	trusted, err := trustedBlock(ctx, meta.Height)
+	if err != nil {
+		return err
+	}
+	if meta.LastAccepted != trusted {
+		return fmt.Errorf(
+			"snapshot ends at %s (height=%d) but the trusted block is %s",
+			meta.LastAccepted, meta.Height, trusted,
+		)
+	}
+
+	if len(vmConfigFile) > 0 {
+		if err := setRestoreDir(vmConfigFile, dir); err != nil {
+			return err
+		}
+	}
+	return printResult(&bootstrapResult{Dir: dir, Metadata: meta, VMConfig: vmConfigFile}, func() error {
+		color.Green(
+			"verified snapshot in %s (lastAccepted=%s height=%d entries=%d)",
+			dir, meta.LastAccepted, meta.Height, meta.Entries,
+		)
+		if len(vmConfigFile) > 0 {
+			color.Green("set restoreDir in %s (start the node with an empty database to restore)", vmConfigFile)
+		} else {
+			color.Yellow("set \"restoreDir\" to %s in the VM config and start the node with an empty database to restore", dir)
+		}
+		return nil
+	})
+}
+
+// trustedBlock returns the block ID the snapshot must end at
+func trustedBlock(ctx context.Context, height uint64) (ids.ID, error) {
+	if len(trustedBlockID) > 0 {
+		return ids.FromString(trustedBlockID)
+	}
+	cli := client.New(uri, requestTimeout, clientOptions()...)
+	blk, err := cli.BlockAt(ctx, height)
+	if err != nil {
+		return ids.Empty, fmt.Errorf("%w: unable to fetch trusted block at height %d", err, height)
+	}
+	return blk.BlockID, nil
+}
+
+// setRestoreDir sets "restoreDir" in the VM config at [path] (creating it if
+// it does not exist) and keeps every other field as is
+func setRestoreDir(path string, dir string) error {
+	config := map[string]json.RawMessage{}
+	b, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := json.Unmarshal(b, &config); err != nil {
+			return fmt.Errorf("%w: unable to parse %s", err, path)
+		}
+	case !errors.Is(err, os.ErrNotExist):
+		return err
+	}
+	rdir, err := json.Marshal(dir)
+	if err != nil {
+		return err
+	}
+	config["restoreDir"] = rdir
+	b, err = json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, fsModeWrite)
+}
@@ -0,0 +1,78 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+var burnCooldown uint64
+
+func init() {
+	burnCmd.PersistentFlags().Uint64Var(
+		&burnCooldown,
+		"cooldown",
+		0,
+		"seconds before the space may be claimed again (at most the genesis \"maxBurnCooldown\")",
+	)
+}
+
+var burnCmd = &cobra.Command{
+	Use:   "burn [options] <space>",
+	Short: "Permanently retires the given space and all of its values",
+	Long: `
+Retires the given space immediately, as if it expired: its info is
+removed and its values are pruned by every node. With --cooldown, the
+space may not be claimed again (by anyone) until the cooldown ends.
+
+$ spaces-cli burn hello.avax --cooldown 86400
+<<COMMENT
+burned hello.avax
+COMMENT
+`,
+	RunE: burnFunc,
+}
+
+func burnFunc(cmd *cobra.Command, args []string) error {
+	priv, err := loadPrivateKey()
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("expected exactly 1 argument, got %d", len(args))
+	}
+	space := args[0]
+	if err := parser.CheckContents(space); err != nil {
+		return fmt.Errorf("%w: failed to parse space", err)
+	}
+
+	utx := &chain.BurnTx{
+		BaseTx:   &chain.BaseTx{},
+		Space:    space,
+		Cooldown: burnCooldown,
+	}
+
+	cli := client.New(uri, requestTimeout, clientOptions()...)
+	opts := txOptions()
+	if verbose {
+		opts = append(opts, client.WithBalance())
+	}
+	txID, cost, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, opts...)
+	if err != nil {
+		return err
+	}
+
+	return printResult(&txResult{TxID: txID, Cost: cost, Space: space}, func() error {
+		color.Green("burned %s", space)
+		return nil
+	})
+}
@@ -8,7 +8,8 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
@@ -17,6 +18,40 @@ import (
 	"github.com/ava-labs/spacesvm/parser"
 )
 
+var (
+	claimBeneficiary string
+	claimLease       uint64
+	claimSalt        string
+	claimKeys        []string
+)
+
+func init() {
+	claimCmd.PersistentFlags().StringVar(
+		&claimBeneficiary,
+		"beneficiary",
+		"",
+		"address credited with a share of the claim fee",
+	)
+	claimCmd.PersistentFlags().Uint64Var(
+		&claimLease,
+		"lease",
+		0,
+		"number of standard leases to buy (multiplies the claim fee and the life of each lifeline unit)",
+	)
+	claimCmd.PersistentFlags().StringVar(
+		&claimSalt,
+		"salt",
+		"",
+		"salt of the commitment to reveal (see commit)",
+	)
+	claimCmd.PersistentFlags().StringArrayVar(
+		&claimKeys,
+		"set",
+		nil,
+		"key=value to set in the space with the claim (may be repeated)",
+	)
+}
+
 var claimCmd = &cobra.Command{
 	Use:   "claim [options] <space>",
 	Short: "Claims the given space",
@@ -30,12 +65,24 @@ $ spaces-cli claim hello.avax
 <<COMMENT
 success
 COMMENT
+
+# Credits "0x..." (such as whoever referred you) with a share of the claim fee
+$ spaces-cli claim hello.avax --beneficiary 0x...
+
+# Buys a premium lease 3x as long as the standard one for 3x the claim fee
+$ spaces-cli claim hello.avax --lease 3
+
+# Reveals a commitment made with "spaces-cli commit hello.avax"
+$ spaces-cli claim hello.avax --salt 0x...
+
+# Sets keys in the space in the same transaction as the claim
+$ spaces-cli claim hello.avax --set name=hello --set version=1
 `,
 	RunE: claimFunc,
 }
 
 func claimFunc(cmd *cobra.Command, args []string) error {
-	priv, err := crypto.LoadECDSA(privateKeyFile)
+	priv, err := loadPrivateKey()
 	if err != nil {
 		return err
 	}
@@ -48,20 +95,38 @@ func claimFunc(cmd *cobra.Command, args []string) error {
 	utx := &chain.ClaimTx{
 		BaseTx: &chain.BaseTx{},
 		Space:  space,
+		Lease:  claimLease,
+	}
+	if len(claimBeneficiary) > 0 {
+		if !common.IsHexAddress(claimBeneficiary) {
+			return fmt.Errorf("invalid beneficiary %q", claimBeneficiary)
+		}
+		utx.Beneficiary = common.HexToAddress(claimBeneficiary)
+	}
+	utx.Salt, err = getSalt(claimSalt)
+	if err != nil {
+		return err
+	}
+	utx.Keys, err = getClaimKeys(claimKeys)
+	if err != nil {
+		return err
 	}
 
-	cli := client.New(uri, requestTimeout)
-	opts := []client.OpOption{client.WithPollTx()}
+	cli := client.New(uri, requestTimeout, clientOptions()...)
+	opts := txOptions()
 	if verbose {
 		opts = append(opts, client.WithInfo(space))
 		opts = append(opts, client.WithBalance())
 	}
-	if _, _, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, opts...); err != nil {
+	txID, cost, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, opts...)
+	if err != nil {
 		return err
 	}
 
-	color.Green("claimed %s", space)
-	return nil
+	return printResult(&txResult{TxID: txID, Cost: cost, Space: space}, func() error {
+		color.Green("claimed %s", space)
+		return nil
+	})
 }
 
 func getClaimOp(args []string) (space string, err error) {
@@ -80,3 +145,34 @@ func getClaimOp(args []string) (space string, err error) {
 
 	return space, nil
 }
+
+// getSalt parses the 32-byte hex [salt] of a commitment (empty if not set)
+func getSalt(salt string) (common.Hash, error) {
+	if len(salt) == 0 {
+		return common.Hash{}, nil
+	}
+	b, err := hexutil.Decode(salt)
+	if err != nil || len(b) != common.HashLength {
+		return common.Hash{}, fmt.Errorf("invalid salt %q", salt)
+	}
+	return common.BytesToHash(b), nil
+}
+
+// getClaimKeys parses the "key=value" pairs set by a claim
+func getClaimKeys(pairs []string) ([]*chain.CustomKey, error) {
+	keys := make([]*chain.CustomKey, 0, len(pairs))
+	for _, pair := range pairs {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("expected key=value, got %q", pair)
+		}
+		if err := parser.CheckContents(kv[0]); err != nil {
+			return nil, fmt.Errorf("%w: failed to verify key %q", err, kv[0])
+		}
+		keys = append(keys, &chain.CustomKey{Key: kv[0], Value: []byte(kv[1])})
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	return keys, nil
+}
@@ -0,0 +1,105 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"crypto/rand"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/client"
+)
+
+var commitSalt string
+
+func init() {
+	commitCmd.PersistentFlags().StringVar(
+		&commitSalt,
+		"salt",
+		"",
+		"32-byte hex salt of the commitment (random if empty)",
+	)
+}
+
+var commitCmd = &cobra.Command{
+	Use:   "commit [options] <space>",
+	Short: "Commits to claiming the given space without revealing it",
+	Long: `
+Issues a commitment to claim the given space, which only reveals a hash
+of the sender, the space, and a salt. Once the genesis "claimCommitDelay"
+has passed, claim the space with the same salt to reveal it. Networks
+with a "claimCommitDelay" require every claim to be committed to first.
+
+$ spaces-cli commit hello.avax
+<<COMMENT
+committed to hello.avax (claim with --salt 0x...)
+COMMENT
+
+$ spaces-cli claim hello.avax --salt 0x...
+`,
+	RunE: commitFunc,
+}
+
+// commitResult is the JSON output of commit
+type commitResult struct {
+	TxID       ids.ID      `json:"txId"`
+	Cost       uint64      `json:"cost"`
+	Space      string      `json:"space"`
+	Salt       common.Hash `json:"salt"`
+	Commitment common.Hash `json:"commitment"`
+}
+
+func commitFunc(cmd *cobra.Command, args []string) error {
+	priv, err := loadPrivateKey()
+	if err != nil {
+		return err
+	}
+
+	space, err := getClaimOp(args)
+	if err != nil {
+		return err
+	}
+	salt, err := getSalt(commitSalt)
+	if err != nil {
+		return err
+	}
+	if salt == (common.Hash{}) {
+		if _, err := rand.Read(salt[:]); err != nil {
+			return err
+		}
+	}
+
+	sender := crypto.PubkeyToAddress(priv.PublicKey)
+	utx := &chain.CommitTx{
+		BaseTx:     &chain.BaseTx{},
+		Commitment: chain.ClaimCommitment(sender, space, salt),
+	}
+
+	cli := client.New(uri, requestTimeout, clientOptions()...)
+	opts := txOptions()
+	if verbose {
+		opts = append(opts, client.WithBalance())
+	}
+	txID, cost, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, opts...)
+	if err != nil {
+		return err
+	}
+
+	return printResult(&commitResult{
+		TxID:       txID,
+		Cost:       cost,
+		Space:      space,
+		Salt:       salt,
+		Commitment: utx.Commitment,
+	}, func() error {
+		color.Green("committed to %s (claim with --salt %s)", space, salt.Hex())
+		return nil
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"os"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -47,6 +48,14 @@ func createFunc(cmd *cobra.Command, args []string) error {
 	if err := crypto.SaveECDSA(privateKeyFile, priv); err != nil {
 		return err
 	}
-	color.Green("created address %s and saved to %s", crypto.PubkeyToAddress(priv.PublicKey), privateKeyFile)
-	return nil
+	addr := crypto.PubkeyToAddress(priv.PublicKey)
+	return printResult(&createResult{Address: addr, PrivateKeyFile: privateKeyFile}, func() error {
+		color.Green("created address %s and saved to %s", addr, privateKeyFile)
+		return nil
+	})
+}
+
+type createResult struct {
+	Address        common.Address `json:"address"`
+	PrivateKeyFile string         `json:"privateKeyFile"`
 }
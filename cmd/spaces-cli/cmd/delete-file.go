@@ -7,7 +7,6 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
@@ -22,7 +21,7 @@ var deleteFileCmd = &cobra.Command{
 }
 
 func deleteFileFunc(cmd *cobra.Command, args []string) error {
-	priv, err := crypto.LoadECDSA(privateKeyFile)
+	priv, err := loadPrivateKey()
 	if err != nil {
 		return err
 	}
@@ -31,11 +30,13 @@ func deleteFileFunc(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("expected exactly 1 argument, got %d", len(args))
 	}
 
-	cli := client.New(uri, requestTimeout)
-	if err := tree.Delete(context.Background(), cli, args[0], priv); err != nil {
+	cli := client.New(uri, requestTimeout, clientOptions()...)
+	if err := tree.Delete(context.Background(), cli, args[0], priv, txOptions()...); err != nil {
 		return err
 	}
 
-	color.Green("deleted file %s", args[0])
-	return nil
+	return printResult(&fileResult{Path: args[0]}, func() error {
+		color.Green("deleted file %s", args[0])
+		return nil
+	})
 }
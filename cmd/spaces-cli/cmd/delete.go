@@ -6,7 +6,6 @@ package cmd
 import (
 	"context"
 
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
@@ -21,7 +20,7 @@ var deleteCmd = &cobra.Command{
 }
 
 func deleteFunc(cmd *cobra.Command, args []string) error {
-	priv, err := crypto.LoadECDSA(privateKeyFile)
+	priv, err := loadPrivateKey()
 	if err != nil {
 		return err
 	}
@@ -37,16 +36,19 @@ func deleteFunc(cmd *cobra.Command, args []string) error {
 		Key:    key,
 	}
 
-	cli := client.New(uri, requestTimeout)
-	opts := []client.OpOption{client.WithPollTx()}
+	cli := client.New(uri, requestTimeout, clientOptions()...)
+	opts := txOptions()
 	if verbose {
 		opts = append(opts, client.WithInfo(space))
 		opts = append(opts, client.WithBalance())
 	}
-	if _, _, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, opts...); err != nil {
+	txID, cost, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, opts...)
+	if err != nil {
 		return err
 	}
 
-	color.Green("deleted %s from %s", key, space)
-	return nil
+	return printResult(&txResult{TxID: txID, Cost: cost, Space: space, Key: key}, func() error {
+		color.Green("deleted %s from %s", key, space)
+		return nil
+	})
 }
@@ -0,0 +1,99 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+var unfreeze bool
+
+func init() {
+	freezeCmd.PersistentFlags().BoolVar(
+		&unfreeze,
+		"unfreeze",
+		false,
+		"unfreeze the prefix once the genesis \"unfreezeDelay\" passes",
+	)
+}
+
+var freezeCmd = &cobra.Command{
+	Use:   "freeze [options] <space>[/<prefix>]",
+	Short: "Makes the keys of the given space starting with prefix read-only",
+	Long: `
+Freezes the keys of the given space that start with the given prefix
+(every key if no prefix is given): they can't be set or deleted until
+the prefix is unfrozen. An unfreeze only takes effect after the genesis
+"unfreezeDelay", so readers are warned before frozen keys can change.
+
+$ spaces-cli freeze hello.avax/release1
+<<COMMENT
+froze hello.avax/release1
+COMMENT
+
+$ spaces-cli freeze hello.avax/release1 --unfreeze
+<<COMMENT
+unfreezing hello.avax/release1
+COMMENT
+`,
+	RunE: freezeFunc,
+}
+
+func freezeFunc(cmd *cobra.Command, args []string) error {
+	priv, err := loadPrivateKey()
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("expected exactly 1 argument, got %d", len(args))
+	}
+	splits := strings.SplitN(args[0], parser.Delimiter, 2)
+	space := splits[0]
+	if err := parser.CheckContents(space); err != nil {
+		return fmt.Errorf("%w: failed to parse space", err)
+	}
+	prefix := ""
+	if len(splits) == 2 && len(splits[1]) > 0 {
+		prefix = splits[1]
+		if err := parser.CheckContents(prefix); err != nil {
+			return fmt.Errorf("%w: failed to parse prefix", err)
+		}
+	}
+
+	utx := &chain.FreezeTx{
+		BaseTx:   &chain.BaseTx{},
+		Space:    space,
+		Prefix:   prefix,
+		Unfreeze: unfreeze,
+	}
+
+	cli := client.New(uri, requestTimeout, clientOptions()...)
+	opts := txOptions()
+	if verbose {
+		opts = append(opts, client.WithInfo(space))
+		opts = append(opts, client.WithBalance())
+	}
+	txID, cost, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, opts...)
+	if err != nil {
+		return err
+	}
+
+	return printResult(&txResult{TxID: txID, Cost: cost, Space: space}, func() error {
+		if unfreeze {
+			color.Green("unfreezing %s", args[0])
+		} else {
+			color.Green("froze %s", args[0])
+		}
+		return nil
+	})
+}
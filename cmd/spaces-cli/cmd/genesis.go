@@ -23,8 +23,21 @@ var (
 	minPrice    int64
 	claimReward int64
 
+	lookbackWindow  int64
+	targetBlockRate int64
+	targetBlockSize int64
+	maxBlockSize    int64
+
+	maxKeyLength int64
+	maxValueSize int64
+	maxSpaceSize int64
+
+	freeWriteSize int64
+
 	airdropHash  string
 	airdropUnits uint64
+
+	allocationsFile string
 )
 
 func init() {
@@ -46,6 +59,54 @@ func init() {
 		-1,
 		"seconds until a spaces will expire after being claimed",
 	)
+	genesisCmd.PersistentFlags().Int64Var(
+		&lookbackWindow,
+		"lookback-window",
+		-1,
+		"seconds of recent blocks used to compute prices and detect duplicate txs",
+	)
+	genesisCmd.PersistentFlags().Int64Var(
+		&targetBlockRate,
+		"target-block-rate",
+		-1,
+		"target seconds between blocks",
+	)
+	genesisCmd.PersistentFlags().Int64Var(
+		&targetBlockSize,
+		"target-block-size",
+		-1,
+		"target units per block",
+	)
+	genesisCmd.PersistentFlags().Int64Var(
+		&maxBlockSize,
+		"max-block-size",
+		-1,
+		"maximum units per block",
+	)
+	genesisCmd.PersistentFlags().Int64Var(
+		&maxKeyLength,
+		"max-key-length",
+		-1,
+		"maximum key length",
+	)
+	genesisCmd.PersistentFlags().Int64Var(
+		&maxValueSize,
+		"max-value-size",
+		-1,
+		"maximum value size (in bytes)",
+	)
+	genesisCmd.PersistentFlags().Int64Var(
+		&maxSpaceSize,
+		"max-space-size",
+		-1,
+		"maximum value bytes stored per space (0 is unlimited)",
+	)
+	genesisCmd.PersistentFlags().Int64Var(
+		&freeWriteSize,
+		"free-write-size",
+		-1,
+		"largest value (in bytes) a rate-limited set may write without paying for its value units (0 disables free writes)",
+	)
 	genesisCmd.PersistentFlags().StringVar(
 		&airdropHash,
 		"airdrop-hash",
@@ -58,13 +119,24 @@ func init() {
 		0,
 		"units to allocate to each airdrop address",
 	)
+	genesisCmd.PersistentFlags().StringVar(
+		&allocationsFile,
+		"allocations",
+		"",
+		"CSV (address,balance) or JSON file of custom allocations and pre-claimed spaces",
+	)
 }
 
 var genesisCmd = &cobra.Command{
 	Use:   "genesis [magic] [custom allocations file] [options]",
 	Short: "Creates a new genesis in the default location",
 	PreRunE: func(cmd *cobra.Command, args []string) error {
-		if len(args) != 2 {
+		switch {
+		case len(args) == 2 && len(allocationsFile) > 0:
+			return errors.New("custom allocations file and --allocations are mutually exclusive")
+		case len(args) == 2:
+			allocationsFile = args[1]
+		case len(args) != 1 || len(allocationsFile) == 0:
 			return errors.New("invalid args")
 		}
 
@@ -91,6 +163,30 @@ func genesisFunc(cmd *cobra.Command, args []string) error {
 	if claimReward >= 0 {
 		genesis.ClaimReward = uint64(claimReward)
 	}
+	if lookbackWindow >= 0 {
+		genesis.LookbackWindow = lookbackWindow
+	}
+	if targetBlockRate >= 0 {
+		genesis.TargetBlockRate = targetBlockRate
+	}
+	if targetBlockSize >= 0 {
+		genesis.TargetBlockSize = uint64(targetBlockSize)
+	}
+	if maxBlockSize >= 0 {
+		genesis.MaxBlockSize = uint64(maxBlockSize)
+	}
+	if maxKeyLength >= 0 {
+		genesis.MaxKeyLength = uint64(maxKeyLength)
+	}
+	if maxValueSize >= 0 {
+		genesis.MaxValueSize = uint64(maxValueSize)
+	}
+	if maxSpaceSize >= 0 {
+		genesis.MaxSpaceSize = uint64(maxSpaceSize)
+	}
+	if freeWriteSize >= 0 {
+		genesis.FreeWriteSize = uint64(freeWriteSize)
+	}
 	if len(airdropHash) > 0 {
 		genesis.AirdropHash = airdropHash
 		if airdropUnits == 0 {
@@ -99,15 +195,20 @@ func genesisFunc(cmd *cobra.Command, args []string) error {
 		genesis.AirdropUnits = airdropUnits
 	}
 
-	a, err := os.ReadFile(args[1])
+	allocs, total, err := loadAllocations(allocationsFile)
 	if err != nil {
 		return err
 	}
-	allocs := []*chain.CustomAllocation{}
-	if err := json.Unmarshal(a, &allocs); err != nil {
+	genesis.CustomAllocation = allocs.Allocations
+	genesis.CustomSpaces = allocs.CustomSpaces
+	color.Cyan(
+		"loaded %d allocations (total=%d) and %d spaces from %s",
+		len(allocs.Allocations), total, len(allocs.CustomSpaces), allocationsFile,
+	)
+
+	if err := genesis.Verify(); err != nil {
 		return err
 	}
-	genesis.CustomAllocation = allocs
 
 	b, err := json.Marshal(genesis)
 	if err != nil {
@@ -116,6 +217,8 @@ func genesisFunc(cmd *cobra.Command, args []string) error {
 	if err := os.WriteFile(genesisFile, b, fsModeWrite); err != nil {
 		return err
 	}
-	color.Green("created genesis and saved to %s", genesisFile)
-	return nil
+	return printResult(genesis, func() error {
+		color.Green("created genesis and saved to %s", genesisFile)
+		return nil
+	})
 }
@@ -0,0 +1,78 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/vm"
+)
+
+var (
+	historyCursor  string
+	historyAddress string
+)
+
+func init() {
+	historyCmd.PersistentFlags().StringVar(
+		&historyCursor,
+		"cursor",
+		"",
+		"cursor returned by a previous call",
+	)
+	historyCmd.PersistentFlags().StringVar(
+		&historyAddress,
+		"address",
+		"",
+		"view transactions sent by this address instead of a space",
+	)
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history [space] [options]",
+	Short: "View all activity affecting a space or sent by an address",
+	RunE:  historyFunc,
+}
+
+func historyFunc(cmd *cobra.Command, args []string) error {
+	cli := client.New(uri, requestTimeout, clientOptions()...)
+	var (
+		activity []*chain.Activity
+		next     string
+		err      error
+	)
+	if len(historyAddress) > 0 {
+		if len(args) != 0 {
+			return fmt.Errorf("expected exactly 0 arguments, got %d", len(args))
+		}
+		if !common.IsHexAddress(historyAddress) {
+			return fmt.Errorf("invalid address %q", historyAddress)
+		}
+		activity, next, err = cli.SenderHistory(context.Background(), common.HexToAddress(historyAddress), historyCursor)
+	} else {
+		if len(args) != 1 {
+			return fmt.Errorf("expected exactly 1 argument, got %d", len(args))
+		}
+		activity, next, err = cli.History(context.Background(), args[0], historyCursor)
+	}
+	if err != nil {
+		return err
+	}
+	return printResult(&vm.HistoryReply{Activity: activity, Next: next}, func() error {
+		if err := client.PPActivity(activity); err != nil {
+			return err
+		}
+		if len(next) > 0 {
+			color.Yellow("more activity available (--cursor %s)", next)
+		}
+		return nil
+	})
+}
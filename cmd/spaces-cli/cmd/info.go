@@ -12,6 +12,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/vm"
 )
 
 var infoCmd = &cobra.Command{
@@ -24,19 +25,21 @@ func infoFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
 		return fmt.Errorf("expected exactly 1 argument, got %d", len(args))
 	}
-	cli := client.New(uri, requestTimeout)
+	cli := client.New(uri, requestTimeout, clientOptions()...)
 	info, values, err := cli.Info(context.Background(), args[0])
 	if err != nil {
 		return err
 	}
 
-	client.PPInfo(info)
-	for _, kv := range values {
-		hr, err := json.Marshal(kv.ValueMeta)
-		if err != nil {
-			return err
+	return printResult(&vm.InfoReply{Info: info, Values: values}, func() error {
+		client.PPInfo(info)
+		for _, kv := range values {
+			hr, err := json.Marshal(kv.ValueMeta)
+			if err != nil {
+				return err
+			}
+			color.Yellow("%s=>%s", kv.Key, string(hr))
 		}
-		color.Yellow("%s=>%s", kv.Key, string(hr))
-	}
-	return nil
+		return nil
+	})
 }
@@ -8,8 +8,8 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
@@ -18,14 +18,35 @@ import (
 	"github.com/ava-labs/spacesvm/parser"
 )
 
+var lifelineExtension time.Duration
+
+func init() {
+	lifelineCmd.PersistentFlags().DurationVar(
+		&lifelineExtension,
+		"extension",
+		0,
+		"extend the space by exactly this long (units default to the number required)",
+	)
+}
+
 var lifelineCmd = &cobra.Command{
 	Use:   "lifeline [options] <space> <units>",
 	Short: "Extends the life of a given space",
-	RunE:  lifelineFunc,
+	Long: `
+Extends the life of a space by the credit provided by <units>. Anyone may fund
+a lifeline, not just the owner of the space.
+
+With --extension, the space is extended by exactly that long (any additional
+credit provided by <units> is forfeited), and <units> may be omitted to pay
+for the minimum number required.
+
+$ spaces-cli lifeline jim --extension 8760h
+`,
+	RunE: lifelineFunc,
 }
 
 func lifelineFunc(cmd *cobra.Command, args []string) error {
-	priv, err := crypto.LoadECDSA(privateKeyFile)
+	priv, err := loadPrivateKey()
 	if err != nil {
 		return err
 	}
@@ -34,30 +55,46 @@ func lifelineFunc(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
+	if lifelineExtension < 0 {
+		return fmt.Errorf("--extension must not be negative, got %v", lifelineExtension)
+	}
 
+	ctx := context.Background()
+	cli := client.New(uri, requestTimeout, clientOptions()...)
+	estimate, err := cli.EstimateLifeline(ctx, space, units, uint64(lifelineExtension.Seconds()))
+	if err != nil {
+		return err
+	}
 	utx := &chain.LifelineTx{
-		BaseTx: &chain.BaseTx{},
-		Space:  space,
-		Units:  units,
+		BaseTx:    &chain.BaseTx{},
+		Space:     space,
+		Units:     estimate.Units,
+		Extension: uint64(lifelineExtension.Seconds()),
 	}
+	extension := time.Duration(estimate.Extension) * time.Second
 
-	cli := client.New(uri, requestTimeout)
-	opts := []client.OpOption{client.WithPollTx()}
+	opts := txOptions()
 	if verbose {
 		opts = append(opts, client.WithInfo(space))
 		opts = append(opts, client.WithBalance())
 	}
-	if _, _, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, opts...); err != nil {
+	txID, cost, err := client.SignIssueRawTx(ctx, cli, utx, priv, opts...)
+	if err != nil {
 		return err
 	}
 
-	color.Green("extended life of %s by %d units", space, units)
-	return nil
+	return printResult(&txResult{TxID: txID, Cost: cost, Space: space, Units: utx.Units}, func() error {
+		color.Green("extended life of %s by %d units (%v)", space, utx.Units, extension)
+		return nil
+	})
 }
 
 func getLifelineOp(args []string) (space string, units uint64, err error) {
+	if len(args) == 1 && lifelineExtension > 0 {
+		args = append(args, "0")
+	}
 	if len(args) != 2 {
-		return "", 0, fmt.Errorf("expected exactly 1 argument, got %d", len(args))
+		return "", 0, fmt.Errorf("expected exactly 2 arguments, got %d", len(args))
 	}
 
 	space = args[0]
@@ -0,0 +1,68 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/client"
+)
+
+var lookupCmd = &cobra.Command{
+	Use:   "lookup [options] space/key",
+	Short: "Resolves what a name points to, following redirects",
+	Long: `
+Resolves the record stored at space/key. Redirects to other names are
+followed until a record of another kind (or a redirect to a URL) is found.
+
+$ spaces-cli set --kind a hello/root 192.0.2.1
+$ spaces-cli set --kind redirect hello/www hello/root
+$ spaces-cli lookup hello/www
+<<COMMENT
+hello/www -> hello/root
+hello/root [a] 192.0.2.1
+COMMENT
+`,
+	RunE: lookupFunc,
+}
+
+// lookupResult is the JSON output of lookup
+type lookupResult struct {
+	Path  string   `json:"path"`
+	Kind  string   `json:"kind"`
+	Value string   `json:"value"`
+	Hops  []string `json:"hops"`
+}
+
+func lookupFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected exactly 1 argument, got %d", len(args))
+	}
+	cli := client.New(uri, requestTimeout, clientOptions()...)
+	rec, err := client.ResolveName(context.Background(), cli, args[0])
+	if err != nil {
+		return err
+	}
+
+	res := &lookupResult{Path: rec.Path, Kind: rec.Kind, Value: string(rec.Value), Hops: rec.Hops}
+	return printResult(res, func() error {
+		for i, hop := range rec.Hops {
+			next := rec.Path
+			if i+1 < len(rec.Hops) {
+				next = rec.Hops[i+1]
+			}
+			color.Yellow("%s -> %s", hop, next)
+		}
+		kind := rec.Kind
+		if len(kind) == 0 {
+			kind = "raw"
+		}
+		color.Green("%s [%s] %s", rec.Path, kind, rec.Value)
+		return nil
+	})
+}
@@ -8,7 +8,6 @@ import (
 	"fmt"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
@@ -17,14 +16,36 @@ import (
 	"github.com/ava-labs/spacesvm/parser"
 )
 
+var moveActivation uint64
+
+func init() {
+	moveCmd.PersistentFlags().Uint64Var(
+		&moveActivation,
+		"activation",
+		0,
+		"unix time the move takes effect at (moves immediately if 0 or past)",
+	)
+}
+
 var moveCmd = &cobra.Command{
 	Use:   "move [options] <to> <space>",
 	Short: "Transfers a space to another address",
-	RunE:  moveFunc,
+	Long: `
+Transfers a space to another address. With --activation, the move is
+scheduled instead and transfers the space in the first block at or after
+that time. Until then, the owner may replace the scheduled move with
+another move, or cancel it by moving the space to its own address.
+
+$ spaces-cli move 0x... hello.avax --activation 1700000000
+<<COMMENT
+scheduled hello.avax to move to 0x... at 1700000000
+COMMENT
+`,
+	RunE: moveFunc,
 }
 
 func moveFunc(cmd *cobra.Command, args []string) error {
-	priv, err := crypto.LoadECDSA(privateKeyFile)
+	priv, err := loadPrivateKey()
 	if err != nil {
 		return err
 	}
@@ -35,23 +56,31 @@ func moveFunc(cmd *cobra.Command, args []string) error {
 	}
 
 	utx := &chain.MoveTx{
-		BaseTx: &chain.BaseTx{},
-		To:     to,
-		Space:  space,
+		BaseTx:     &chain.BaseTx{},
+		To:         to,
+		Space:      space,
+		Activation: moveActivation,
 	}
 
-	cli := client.New(uri, requestTimeout)
-	opts := []client.OpOption{client.WithPollTx()}
+	cli := client.New(uri, requestTimeout, clientOptions()...)
+	opts := txOptions()
 	if verbose {
 		opts = append(opts, client.WithInfo(space))
 		opts = append(opts, client.WithBalance())
 	}
-	if _, _, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, opts...); err != nil {
+	txID, cost, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, opts...)
+	if err != nil {
 		return err
 	}
 
-	color.Green("moved %s to %s", space, to.Hex())
-	return nil
+	return printResult(&txResult{TxID: txID, Cost: cost, Space: space, To: &to}, func() error {
+		if moveActivation > 0 {
+			color.Green("scheduled %s to move to %s at %d", space, to.Hex(), moveActivation)
+			return nil
+		}
+		color.Green("moved %s to %s", space, to.Hex())
+		return nil
+	})
 }
 
 func getMoveOp(args []string) (to common.Address, space string, err error) {
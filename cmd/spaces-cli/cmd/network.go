@@ -11,6 +11,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/vm"
 )
 
 var networkCmd = &cobra.Command{
@@ -23,11 +24,13 @@ func networkFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 0 {
 		return fmt.Errorf("expected exactly 0 arguments, got %d", len(args))
 	}
-	cli := client.New(uri, requestTimeout)
+	cli := client.New(uri, requestTimeout, clientOptions()...)
 	networkID, subnetID, chainID, err := cli.Network(context.Background())
 	if err != nil {
 		return err
 	}
-	color.Cyan("networkID=%d subnetID=%s chainID=%s", networkID, subnetID, chainID)
-	return nil
+	return printResult(&vm.NetworkReply{NetworkID: networkID, SubnetID: subnetID, ChainID: chainID}, func() error {
+		color.Cyan("networkID=%d subnetID=%s chainID=%s", networkID, subnetID, chainID)
+		return nil
+	})
 }
@@ -0,0 +1,59 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+)
+
+var output string
+
+// txResult is the JSON output of commands that issue a transaction
+type txResult struct {
+	TxID  ids.ID          `json:"txId"`
+	Cost  uint64          `json:"cost"`
+	Space string          `json:"space,omitempty"`
+	Key   string          `json:"key,omitempty"`
+	To    *common.Address `json:"to,omitempty"`
+	Units uint64          `json:"units,omitempty"`
+}
+
+// checkOutput validates [output]. Human-readable output is moved to stderr
+// when printing JSON so that stdout only contains results.
+func checkOutput(cmd *cobra.Command, args []string) error {
+	switch output {
+	case outputText:
+	case outputJSON:
+		color.Output = os.Stderr
+	default:
+		return fmt.Errorf("invalid output %q (expected %q or %q)", output, outputText, outputJSON)
+	}
+	return nil
+}
+
+// printResult prints [v] as a single line of JSON when --output=json and
+// otherwise calls [text] to print human-readable output.
+func printResult(v interface{}, text func() error) error {
+	if output != outputJSON {
+		return text()
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
@@ -6,6 +6,7 @@ package cmd
 import (
 	"context"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -20,18 +21,25 @@ var ownedCmd = &cobra.Command{
 }
 
 func ownedFunc(cmd *cobra.Command, args []string) error {
-	priv, err := crypto.LoadECDSA(privateKeyFile)
+	priv, err := loadPrivateKey()
 	if err != nil {
 		return err
 	}
 	sender := crypto.PubkeyToAddress(priv.PublicKey)
 
-	cli := client.New(uri, requestTimeout)
+	cli := client.New(uri, requestTimeout, clientOptions()...)
 	spaces, err := cli.Owned(context.Background(), sender)
 	if err != nil {
 		return err
 	}
 
-	color.Green("address %s owns %+v", sender.Hex(), spaces)
-	return nil
+	return printResult(&ownedResult{Address: sender, Spaces: spaces}, func() error {
+		color.Green("address %s owns %+v", sender.Hex(), spaces)
+		return nil
+	})
+}
+
+type ownedResult struct {
+	Address common.Address `json:"address"`
+	Spaces  []string       `json:"spaces"`
 }
@@ -0,0 +1,123 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+var (
+	policyOps          string
+	policyMaxValueSize uint64
+	policyLockedUntil  uint64
+	policyCoSigner     string
+)
+
+func init() {
+	policyCmd.PersistentFlags().StringVar(
+		&policyOps,
+		"ops",
+		"",
+		fmt.Sprintf("comma-separated ops the owner may perform (any of %s; empty allows all)", strings.Join(chain.PolicyOps, ",")),
+	)
+	policyCmd.PersistentFlags().Uint64Var(
+		&policyMaxValueSize,
+		"max-value-size",
+		0,
+		"largest value the owner may set (0 uses the genesis limit)",
+	)
+	policyCmd.PersistentFlags().Uint64Var(
+		&policyLockedUntil,
+		"locked-until",
+		0,
+		"unix time before which the owner may not perform any op",
+	)
+	policyCmd.PersistentFlags().StringVar(
+		&policyCoSigner,
+		"co-signer",
+		"",
+		"address that must approve every op of the owner",
+	)
+}
+
+var policyCmd = &cobra.Command{
+	Use:   "policy [options] <space>",
+	Short: "Replaces the spending policy of the given space",
+	Long: `
+Replaces the policy of the given space, which restricts what its owner
+may do: the ops it may perform, the largest value it may set, a time
+before which it may not perform any op, and a co-signer that must
+approve each op with "spaces-cli approve". Replacing a policy is itself
+subject to the current policy (so a policy without the "policy" op can
+never be replaced), and the policy moves with the space.
+
+$ spaces-cli policy hello.avax --ops set,policy --co-signer 0x...
+<<COMMENT
+replaced the policy of hello.avax
+COMMENT
+`,
+	RunE: policyFunc,
+}
+
+func policyFunc(cmd *cobra.Command, args []string) error {
+	priv, err := loadPrivateKey()
+	if err != nil {
+		return err
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("expected exactly 1 argument, got %d", len(args))
+	}
+	space := args[0]
+	if err := parser.CheckContents(space); err != nil {
+		return fmt.Errorf("%w: failed to parse space", err)
+	}
+
+	policy := chain.SpacePolicy{
+		MaxValueSize: policyMaxValueSize,
+		LockedUntil:  policyLockedUntil,
+	}
+	if len(policyOps) > 0 {
+		policy.Ops = strings.Split(policyOps, ",")
+	}
+	if len(policyCoSigner) > 0 {
+		if !common.IsHexAddress(policyCoSigner) {
+			return fmt.Errorf("invalid co-signer %q", policyCoSigner)
+		}
+		policy.CoSigner = common.HexToAddress(policyCoSigner)
+	}
+	if err := policy.Verify(); err != nil {
+		return err
+	}
+	utx := &chain.PolicyTx{
+		BaseTx: &chain.BaseTx{},
+		Space:  space,
+		Policy: policy,
+	}
+
+	cli := client.New(uri, requestTimeout, clientOptions()...)
+	opts := txOptions()
+	if verbose {
+		opts = append(opts, client.WithInfo(space))
+		opts = append(opts, client.WithBalance())
+	}
+	txID, cost, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, opts...)
+	if err != nil {
+		return err
+	}
+
+	return printResult(&txResult{TxID: txID, Cost: cost, Space: space}, func() error {
+		color.Green("replaced the policy of %s", space)
+		return nil
+	})
+}
@@ -0,0 +1,111 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	configFile  string
+	profileName string
+)
+
+// cliConfig is the contents of [configFile]:
+//
+//	defaultProfile: local
+//	profiles:
+//	  local:
+//	    endpoint: http://127.0.0.1:9650/ext/bc/<chainID>
+//	    privateKeyFile: ~/.spaces-cli/local.pk
+//	    networkMagic: 1
+type cliConfig struct {
+	DefaultProfile string              `json:"defaultProfile"`
+	Profiles       map[string]*profile `json:"profiles"`
+}
+
+// profile is a named set of global flag values. Flags set on the command line
+// take precedence.
+type profile struct {
+	Endpoint       string `json:"endpoint"`
+	PrivateKeyFile string `json:"privateKeyFile"`
+	AuthToken      string `json:"authToken"`
+	NetworkMagic   uint64 `json:"networkMagic"`
+	Output         string `json:"output"`
+}
+
+func defaultConfigFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".spaces-cli", "config.yaml")
+}
+
+// loadProfile applies the selected profile in [configFile] to the flags of
+// [cmd] that were not set explicitly. A missing config file is only an error
+// if a profile was requested.
+func loadProfile(cmd *cobra.Command) error {
+	b, err := os.ReadFile(configFile)
+	switch {
+	case errors.Is(err, os.ErrNotExist) && len(profileName) == 0:
+		return nil
+	case err != nil:
+		return fmt.Errorf("unable to read config %s: %w", configFile, err)
+	}
+	var config cliConfig
+	if err := yaml.UnmarshalStrict(b, &config); err != nil {
+		return fmt.Errorf("unable to parse config %s: %w", configFile, err)
+	}
+
+	name := profileName
+	if len(name) == 0 {
+		name = config.DefaultProfile
+	}
+	if len(name) == 0 {
+		return nil
+	}
+	p, ok := config.Profiles[name]
+	if !ok {
+		return fmt.Errorf("profile %q not found in %s", name, configFile)
+	}
+
+	values := map[string]string{
+		"endpoint":         p.Endpoint,
+		"private-key-file": expandHome(p.PrivateKeyFile),
+		"auth-token":       p.AuthToken,
+		"output":           p.Output,
+	}
+	if p.NetworkMagic != 0 {
+		values["network-magic"] = strconv.FormatUint(p.NetworkMagic, 10)
+	}
+	flags := cmd.Flags()
+	for flag, v := range values {
+		if len(v) == 0 || flags.Changed(flag) {
+			continue
+		}
+		if err := flags.Set(flag, v); err != nil {
+			return fmt.Errorf("invalid %s in profile %q: %w", flag, name, err)
+		}
+	}
+	return nil
+}
+
+func expandHome(path string) string {
+	if len(path) < 2 || path[:2] != "~/" {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[2:])
+}
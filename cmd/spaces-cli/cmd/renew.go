@@ -0,0 +1,150 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+var (
+	renewAll    bool
+	renewWithin time.Duration
+	renewUnits  uint64
+)
+
+var renewCmd = &cobra.Command{
+	Use:   "renew [options] [space...]",
+	Short: "Extends the life of spaces that expire soon",
+	Long: `
+Issues a lifeline for each given space (or every space owned by the private
+key with --all) that expires within the given threshold. Spaces that fail to
+renew are reported after the others are attempted.
+
+$ spaces-cli renew --all --within 72h --units 2
+[1/3] hello expires in 26h0m0s, extended by 2 units
+[2/3] world expires in 312h0m0s, skipping
+[3/3] patrick expires in 1h0m0s, extended by 2 units
+`,
+	RunE: renewFunc,
+}
+
+func init() {
+	renewCmd.PersistentFlags().BoolVar(
+		&renewAll,
+		"all",
+		false,
+		"renew all spaces owned by the private key",
+	)
+	renewCmd.PersistentFlags().DurationVar(
+		&renewWithin,
+		"within",
+		7*24*time.Hour,
+		"only renew spaces expiring within this duration",
+	)
+	renewCmd.PersistentFlags().Uint64Var(
+		&renewUnits,
+		"units",
+		1,
+		"units to extend each space by",
+	)
+}
+
+// renewResult is the JSON output of renew
+type renewResult struct {
+	Renewed []*renewal `json:"renewed"`
+	Skipped []string   `json:"skipped"`
+	Failed  []string   `json:"failed"`
+}
+
+type renewal struct {
+	Space string `json:"space"`
+	TxID  ids.ID `json:"txId"`
+	Cost  uint64 `json:"cost"`
+	// Expiry before the lifeline was issued
+	Expiry uint64 `json:"expiry"`
+}
+
+func renewFunc(cmd *cobra.Command, args []string) error {
+	if renewAll == (len(args) > 0) {
+		return errors.New("expected either --all or at least 1 space")
+	}
+	if renewUnits == 0 {
+		return errors.New("--units must be greater than 0")
+	}
+	for _, space := range args {
+		if err := parser.CheckContents(space); err != nil {
+			return fmt.Errorf("%w: failed to verify space %s", err, space)
+		}
+	}
+
+	priv, err := loadPrivateKey()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	cli := client.New(uri, requestTimeout, clientOptions()...)
+	spaces := args
+	if renewAll {
+		spaces, err = cli.Owned(ctx, crypto.PubkeyToAddress(priv.PublicKey))
+		if err != nil {
+			return err
+		}
+	}
+
+	result := &renewResult{}
+	deadline := time.Now().Add(renewWithin)
+	for i, space := range spaces {
+		progress := fmt.Sprintf("[%d/%d] %s", i+1, len(spaces), space)
+		info, _, err := cli.Info(ctx, space)
+		if err != nil {
+			color.Red("%s: unable to fetch info: %v", progress, err)
+			result.Failed = append(result.Failed, space)
+			continue
+		}
+		expiry := time.Unix(int64(info.Expiry), 0)
+		remaining := time.Until(expiry).Truncate(time.Second)
+		if expiry.After(deadline) {
+			color.Yellow("%s expires in %v, skipping", progress, remaining)
+			result.Skipped = append(result.Skipped, space)
+			continue
+		}
+
+		txID, cost, err := client.SignIssueRawTx(ctx, cli, &chain.LifelineTx{
+			BaseTx: &chain.BaseTx{},
+			Space:  space,
+			Units:  renewUnits,
+		}, priv, txOptions()...)
+		if err != nil {
+			color.Red("%s: unable to renew: %v", progress, err)
+			result.Failed = append(result.Failed, space)
+			continue
+		}
+		color.Green("%s expires in %v, extended by %d units", progress, remaining, renewUnits)
+		result.Renewed = append(result.Renewed, &renewal{Space: space, TxID: txID, Cost: cost, Expiry: info.Expiry})
+	}
+
+	if err := printResult(result, func() error {
+		color.Green("renewed %d spaces, skipped %d", len(result.Renewed), len(result.Skipped))
+		return nil
+	}); err != nil {
+		return err
+	}
+	if len(result.Failed) > 0 {
+		return fmt.Errorf("failed to renew %v", result.Failed)
+	}
+	return nil
+}
@@ -38,11 +38,13 @@ func resolveFileFunc(cmd *cobra.Command, args []string) error {
 	}
 	defer f.Close()
 
-	cli := client.New(uri, requestTimeout)
+	cli := client.New(uri, requestTimeout, clientOptions()...)
 	if err := tree.Download(context.Background(), cli, args[0], f); err != nil {
 		return err
 	}
 
-	color.Green("resolved file %s and stored at %s", args[0], filePath)
-	return nil
+	return printResult(&fileResult{Path: args[0], File: filePath}, func() error {
+		color.Green("resolved file %s and stored at %s", args[0], filePath)
+		return nil
+	})
 }
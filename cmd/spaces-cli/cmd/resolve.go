@@ -8,12 +8,24 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
 	"github.com/ava-labs/spacesvm/client"
 )
 
+var resolveSigner string
+
+func init() {
+	resolveCmd.PersistentFlags().StringVar(
+		&resolveSigner,
+		"signer",
+		"",
+		"address the node's response must be signed by (unverified when empty)",
+	)
+}
+
 var resolveCmd = &cobra.Command{
 	Use:   "resolve [options] space/key",
 	Short: "Reads a value at space/key",
@@ -24,19 +36,35 @@ func resolveFunc(cmd *cobra.Command, args []string) error {
 	if len(args) != 1 {
 		return fmt.Errorf("expected exactly 1 argument, got %d", len(args))
 	}
-	cli := client.New(uri, requestTimeout)
-	_, v, vmeta, err := cli.Resolve(context.Background(), args[0])
-	if err != nil {
-		return err
+	opts := clientOptions()
+	if len(resolveSigner) > 0 {
+		if !common.IsHexAddress(resolveSigner) {
+			return fmt.Errorf("invalid signer %q", resolveSigner)
+		}
+		opts = append(opts, client.WithResponseSigner(common.HexToAddress(resolveSigner)))
 	}
-
-	color.Yellow("%s=>%q", args[0], v)
-	hr, err := json.Marshal(vmeta)
+	cli := client.New(uri, requestTimeout, opts...)
+	resp, err := cli.ResolveWithAttestation(context.Background(), args[0])
 	if err != nil {
 		return err
 	}
-	color.Yellow("Metadata: %s", string(hr))
 
-	color.Green("resolved %s", args[0])
-	return nil
+	return printResult(resp, func() error {
+		color.Yellow("%s=>%q", args[0], resp.Value)
+		hr, err := json.Marshal(resp.ValueMeta)
+		if err != nil {
+			return err
+		}
+		color.Yellow("Metadata: %s", string(hr))
+		if a := resp.Attestation; a != nil {
+			verified := ""
+			if len(resolveSigner) > 0 {
+				verified = " (verified)"
+			}
+			color.Yellow("Signed by %s at height %d%s", a.Signer, a.Height, verified)
+		}
+
+		color.Green("resolved %s", args[0])
+		return nil
+	})
 }
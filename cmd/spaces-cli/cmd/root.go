@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/client"
 )
 
 const (
@@ -20,12 +22,23 @@ var (
 	privateKeyFile string
 	uri            string
 	verbose        bool
+	authToken      string
+	preferredReads bool
+	pendingReads   bool
+	networkMagic   uint64
 	workDir        string
 
 	rootCmd = &cobra.Command{
 		Use:        "spaces-cli",
 		Short:      "SpacesVM CLI",
 		SuggestFor: []string{"spaces-cli", "spacescli", "spacesctl"},
+
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := loadProfile(cmd); err != nil {
+				return err
+			}
+			return checkOutput(cmd, args)
+		},
 	}
 )
 
@@ -41,19 +54,38 @@ func init() {
 		createCmd,
 		genesisCmd,
 		claimCmd,
+		commitCmd,
 		lifelineCmd,
+		renewCmd,
 		setCmd,
 		deleteCmd,
 		resolveCmd,
+		lookupCmd,
 		infoCmd,
 		activityCmd,
 		transferCmd,
 		moveCmd,
+		policyCmd,
+		approveCmd,
+		burnCmd,
+		freezeCmd,
+		exportCmd,
+		importCmd,
 		setFileCmd,
 		resolveFileCmd,
 		deleteFileCmd,
+		syncCmd,
 		networkCmd,
 		ownedCmd,
+		historyCmd,
+		statusCmd,
+		blockCmd,
+		txCmd,
+		statsCmd,
+		benchCmd,
+		bootstrapCmd,
+		adminCmd,
+		shellCmd,
 	)
 
 	rootCmd.PersistentFlags().StringVar(
@@ -68,6 +100,48 @@ func init() {
 		"https://api.tryspaces.xyz",
 		"RPC endpoint for VM",
 	)
+	rootCmd.PersistentFlags().StringVar(
+		&authToken,
+		"auth-token",
+		"",
+		"bearer token sent to the VM (required to issue transactions on some endpoints)",
+	)
+	rootCmd.PersistentFlags().BoolVar(
+		&preferredReads,
+		"preferred",
+		false,
+		"read from the node's preferred block (includes verified but unaccepted transactions)",
+	)
+	rootCmd.PersistentFlags().BoolVar(
+		&pendingReads,
+		"pending",
+		false,
+		"overlay the node's mempool on reads (best-effort preview of unaccepted transactions)",
+	)
+	rootCmd.PersistentFlags().Uint64Var(
+		&networkMagic,
+		"network-magic",
+		0,
+		"refuse to sign transactions for a network with a different magic (0 accepts any)",
+	)
+	rootCmd.PersistentFlags().StringVar(
+		&configFile,
+		"config",
+		defaultConfigFile(),
+		"config file with named profiles",
+	)
+	rootCmd.PersistentFlags().StringVar(
+		&profileName,
+		"profile",
+		"",
+		"profile in the config file to use (defaults to defaultProfile)",
+	)
+	rootCmd.PersistentFlags().StringVar(
+		&output,
+		"output",
+		outputText,
+		"output format (text or json)",
+	)
 	rootCmd.PersistentFlags().BoolVar(
 		&verbose,
 		"verbose",
@@ -76,6 +150,25 @@ func init() {
 	)
 }
 
+func clientOptions() []client.ClientOption {
+	opts := []client.ClientOption{}
+	if len(authToken) > 0 {
+		opts = append(opts, client.WithAuthToken(authToken))
+	}
+	if preferredReads {
+		opts = append(opts, client.WithPreferredReads())
+	}
+	if pendingReads {
+		opts = append(opts, client.WithPendingReads())
+	}
+	return opts
+}
+
+// txOptions are the options used to issue every transaction
+func txOptions() []client.OpOption {
+	return []client.OpOption{client.WithPollTx(), client.WithMagic(networkMagic)}
+}
+
 func Execute() error {
 	return rootCmd.Execute()
 }
@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
@@ -24,7 +23,7 @@ var setFileCmd = &cobra.Command{
 }
 
 func setFileFunc(cmd *cobra.Command, args []string) error {
-	priv, err := crypto.LoadECDSA(privateKeyFile)
+	priv, err := loadPrivateKey()
 	if err != nil {
 		return err
 	}
@@ -35,20 +34,28 @@ func setFileFunc(cmd *cobra.Command, args []string) error {
 	}
 	defer f.Close()
 
-	cli := client.New(uri, requestTimeout)
+	cli := client.New(uri, requestTimeout, clientOptions()...)
 	g, err := cli.Genesis(context.Background())
 	if err != nil {
 		return err
 	}
 
 	// TODO: protect against overflow
-	path, err := tree.Upload(context.Background(), cli, priv, space, f, int(g.MaxValueSize))
+	path, err := tree.Upload(context.Background(), cli, priv, space, f, int(g.MaxValueSize), txOptions()...)
 	if err != nil {
 		return err
 	}
 
-	color.Green("uploaded file %s from %s", path, f.Name())
-	return nil
+	return printResult(&fileResult{Path: path, File: f.Name()}, func() error {
+		color.Green("uploaded file %s from %s", path, f.Name())
+		return nil
+	})
+}
+
+// fileResult is the JSON output of commands that transfer files
+type fileResult struct {
+	Path string `json:"path"`
+	File string `json:"file,omitempty"`
 }
 
 func getSetFileOp(args []string) (space string, f *os.File, err error) {
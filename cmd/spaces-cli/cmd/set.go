@@ -7,7 +7,6 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
@@ -16,6 +15,33 @@ import (
 	"github.com/ava-labs/spacesvm/parser"
 )
 
+var (
+	setBroadcast bool
+	setIPFS      bool
+	setKind      string
+)
+
+func init() {
+	setCmd.PersistentFlags().BoolVar(
+		&setBroadcast,
+		"broadcast",
+		false,
+		"emit a warp message committing to the value (fetch it with spacesvm.warpMessage)",
+	)
+	setCmd.PersistentFlags().BoolVar(
+		&setIPFS,
+		"ipfs",
+		false,
+		"store the value as a pointer to IPFS content (the value must be a valid CID)",
+	)
+	setCmd.PersistentFlags().StringVar(
+		&setKind,
+		"kind",
+		chain.ValueRaw,
+		"record type of the value (a, txt, redirect, contenthash, or ipfs)",
+	)
+}
+
 var setCmd = &cobra.Command{
 	Use:   "set [options] <space/key> <value>",
 	Short: "Writes a key-value pair for the given space",
@@ -59,7 +85,7 @@ COMMENT
 }
 
 func setFunc(cmd *cobra.Command, args []string) error {
-	priv, err := crypto.LoadECDSA(privateKeyFile)
+	priv, err := loadPrivateKey()
 	if err != nil {
 		return err
 	}
@@ -69,25 +95,41 @@ func setFunc(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	kind := setKind
+	if setIPFS {
+		if kind != chain.ValueRaw && kind != chain.ValueIPFS {
+			return fmt.Errorf("--ipfs conflicts with --kind %s", kind)
+		}
+		kind = chain.ValueIPFS
+	}
+	if err := chain.ValidateValue(kind, val); err != nil {
+		return err
+	}
+
 	utx := &chain.SetTx{
-		BaseTx: &chain.BaseTx{},
-		Space:  space,
-		Key:    key,
-		Value:  val,
+		BaseTx:    &chain.BaseTx{},
+		Space:     space,
+		Key:       key,
+		Value:     val,
+		Broadcast: setBroadcast,
+		Kind:      kind,
 	}
 
-	cli := client.New(uri, requestTimeout)
-	opts := []client.OpOption{client.WithPollTx()}
+	cli := client.New(uri, requestTimeout, clientOptions()...)
+	opts := txOptions()
 	if verbose {
 		opts = append(opts, client.WithInfo(space))
 		opts = append(opts, client.WithBalance())
 	}
-	if _, _, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, opts...); err != nil {
+	txID, cost, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, opts...)
+	if err != nil {
 		return err
 	}
 
-	color.Green("set %s in %s", key, space)
-	return nil
+	return printResult(&txResult{TxID: txID, Cost: cost, Space: space, Key: key}, func() error {
+		color.Green("set %s in %s", key, space)
+		return nil
+	})
 }
 
 func getSetOp(args []string) (space string, key string, val []byte, err error) {
@@ -0,0 +1,273 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+
+	"github.com/ava-labs/spacesvm/client"
+)
+
+const shellPrompt = "spaces> "
+
+// shellKey is the private key loaded when a shell session starts (from
+// shellKeyFile) so it is only read once per session
+var (
+	shellKey     *ecdsa.PrivateKey
+	shellKeyFile string
+)
+
+var shellCmd = &cobra.Command{
+	Use:   "shell [options]",
+	Short: "Starts an interactive session",
+	Long: `
+Starts an interactive session that runs spaces-cli commands without
+re-entering global options or reloading the private key. Use the up and
+down arrows to navigate history and tab to complete commands and owned
+spaces. Exit with "exit" or ctrl+d.
+
+$ spaces-cli shell --endpoint http://127.0.0.1:9650/ext/bc/<chainID>
+spaces> claim hello
+spaces> set hello/world hi
+spaces> resolve hello/world
+`,
+	RunE: shellFunc,
+}
+
+// loadPrivateKey returns the key at [privateKeyFile], reusing the key loaded
+// by the shell when possible.
+func loadPrivateKey() (*ecdsa.PrivateKey, error) {
+	if shellKey != nil && shellKeyFile == privateKeyFile {
+		return shellKey, nil
+	}
+	return crypto.LoadECDSA(privateKeyFile)
+}
+
+func shellFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("expected exactly 0 arguments, got %d", len(args))
+	}
+	if priv, err := crypto.LoadECDSA(privateKeyFile); err == nil {
+		shellKey, shellKeyFile = priv, privateKeyFile
+		color.Cyan("loaded key for %s", crypto.PubkeyToAddress(priv.PublicKey))
+	} else {
+		color.Yellow("unable to load key from %s (%v), continuing read-only", privateKeyFile, err)
+	}
+
+	// Errors are reported after each command instead of exiting
+	rootCmd.SilenceErrors = true
+	rootCmd.SilenceUsage = true
+
+	s := &shell{owned: ownedSpaces()}
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		// Run piped commands without line editing
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if !s.run(scanner.Text()) {
+				return nil
+			}
+		}
+		return scanner.Err()
+	}
+
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, os.Stdout}, shellPrompt)
+	t.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+		return s.complete(t, line, pos)
+	}
+	for {
+		// The terminal is only raw while reading so commands can print normally
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return err
+		}
+		line, err := t.ReadLine()
+		if rerr := term.Restore(fd, state); rerr != nil {
+			return rerr
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !s.run(line) {
+			return nil
+		}
+	}
+}
+
+type shell struct {
+	// spaces owned by the loaded key, used for completion
+	owned []string
+}
+
+// run executes [line] and returns false if the session should end
+func (s *shell) run(line string) bool {
+	args, err := splitArgs(line)
+	if err != nil {
+		color.Red("%v", err)
+		return true
+	}
+	if len(args) == 0 {
+		return true
+	}
+	switch args[0] {
+	case "exit", "quit":
+		return false
+	case "shell":
+		color.Red("already in a shell")
+		return true
+	}
+
+	rootCmd.SetArgs(args)
+	executed, err := rootCmd.ExecuteC()
+	if err != nil {
+		color.Red("%v", err)
+	}
+	if executed == nil {
+		return true
+	}
+	resetLocalFlags(executed)
+	switch executed.Name() {
+	case claimCmd.Name(), moveCmd.Name():
+		s.owned = ownedSpaces()
+	}
+	return true
+}
+
+// resetLocalFlags restores the flags of [cmd] to their defaults so they do
+// not carry over to the next command. Global flags are kept for the session.
+func resetLocalFlags(cmd *cobra.Command) {
+	if cmd == rootCmd {
+		return
+	}
+	cmd.LocalFlags().VisitAll(func(f *pflag.Flag) {
+		if !f.Changed {
+			return
+		}
+		if sv, ok := f.Value.(pflag.SliceValue); ok {
+			_ = sv.Replace(nil)
+		} else {
+			_ = f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	})
+}
+
+// complete completes the word before [pos] with a command name (for the first
+// word) or an owned space. Candidates are printed when ambiguous.
+func (s *shell) complete(t *term.Terminal, line string, pos int) (string, int, bool) {
+	start := strings.LastIndexAny(line[:pos], " \t") + 1
+	word := line[start:pos]
+
+	var options []string
+	if strings.TrimSpace(line[:start]) == "" {
+		for _, c := range rootCmd.Commands() {
+			options = append(options, c.Name())
+		}
+		options = append(options, "exit")
+	} else {
+		options = s.owned
+	}
+	var matches []string
+	for _, o := range options {
+		if strings.HasPrefix(o, word) {
+			matches = append(matches, o)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return "", 0, false
+	case 1:
+		completed := matches[0] + " "
+		return line[:start] + completed + line[pos:], start + len(completed), true
+	}
+
+	sort.Strings(matches)
+	fmt.Fprintln(t, strings.Join(matches, "  "))
+	prefix := commonPrefix(matches)
+	return line[:start] + prefix + line[pos:], start + len(prefix), true
+}
+
+func commonPrefix(s []string) string {
+	prefix := s[0]
+	for _, v := range s[1:] {
+		for !strings.HasPrefix(v, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
+
+// ownedSpaces returns the spaces owned by the loaded key, if any
+func ownedSpaces() []string {
+	if shellKey == nil {
+		return nil
+	}
+	cli := client.New(uri, requestTimeout, clientOptions()...)
+	spaces, err := cli.Owned(context.Background(), crypto.PubkeyToAddress(shellKey.PublicKey))
+	if err != nil {
+		color.Yellow("unable to fetch owned spaces: %v", err)
+		return nil
+	}
+	return spaces
+}
+
+// splitArgs splits [line] on whitespace, keeping quoted strings together
+func splitArgs(line string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		quote   rune
+		inArg   bool
+	)
+	for _, r := range line {
+		switch {
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			current.WriteRune(r)
+		case r == '"' || r == '\'':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote %q", quote)
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	return args, nil
+}
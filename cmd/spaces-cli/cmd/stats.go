@@ -0,0 +1,99 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/client"
+)
+
+// clears the terminal and moves the cursor to the top-left corner
+const clearScreen = "\033[H\033[2J"
+
+var (
+	statsWatch    bool
+	statsInterval time.Duration
+)
+
+func init() {
+	statsCmd.PersistentFlags().BoolVar(
+		&statsWatch,
+		"watch",
+		false,
+		"continuously refresh stats until interrupted",
+	)
+	statsCmd.PersistentFlags().DurationVar(
+		&statsInterval,
+		"interval",
+		2*time.Second,
+		"refresh interval when watching",
+	)
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats [options]",
+	Short: "View block production and state statistics",
+	RunE:  statsFunc,
+}
+
+func statsFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("expected exactly 0 arguments, got %d", len(args))
+	}
+	cli := client.New(uri, requestTimeout, clientOptions()...)
+	if !statsWatch {
+		return printStats(cli)
+	}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+
+	t := time.NewTicker(statsInterval)
+	defer t.Stop()
+	for {
+		if output != outputJSON {
+			fmt.Print(clearScreen)
+		}
+		color.Blue("%s (every %s, ctrl+c to exit)", uri, statsInterval)
+		if err := printStats(cli); err != nil {
+			color.Red("unable to fetch stats: %v", err)
+		}
+		select {
+		case <-t.C:
+		case <-sigs:
+			return nil
+		}
+	}
+}
+
+func printStats(cli client.Client) error {
+	ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
+	defer cancel()
+	stats, err := cli.Stats(ctx)
+	if err != nil {
+		return err
+	}
+	return printResult(stats, func() error {
+		color.Green("height:             %d", stats.Height)
+		color.Green("avg block interval: %.2fs (last %d blocks)", stats.AvgBlockInterval, stats.Window)
+		color.Green("txs/s:              %.2f", stats.TxsPerSecond)
+		color.Green("mempool:            %d", stats.Mempool)
+		color.Green("price:              %d", stats.Price)
+		color.Green("cost:               %d", stats.Cost)
+		color.Cyan("spaces:             %d", stats.State.Spaces)
+		color.Cyan("keys:               %d", stats.State.Keys)
+		color.Cyan("value bytes:        %d", stats.State.ValueBytes)
+		return nil
+	})
+}
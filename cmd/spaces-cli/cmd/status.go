@@ -0,0 +1,66 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/vm"
+)
+
+var (
+	statusBlocks int
+	statusCursor string
+)
+
+func init() {
+	statusCmd.PersistentFlags().IntVar(
+		&statusBlocks,
+		"blocks",
+		10,
+		"number of recent blocks to display",
+	)
+	statusCmd.PersistentFlags().StringVar(
+		&statusCursor,
+		"cursor",
+		"",
+		"cursor returned by a previous call (to display older blocks)",
+	)
+}
+
+var statusCmd = &cobra.Command{
+	Use:   "status [options]",
+	Short: "View the most recently accepted blocks",
+	RunE:  statusFunc,
+}
+
+func statusFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("expected exactly 0 arguments, got %d", len(args))
+	}
+	cli := client.New(uri, requestTimeout, clientOptions()...)
+	blocks, next, err := cli.RecentBlocks(context.Background(), statusBlocks, statusCursor)
+	if err != nil {
+		return err
+	}
+	return printResult(&vm.RecentBlocksReply{Blocks: blocks, Next: next}, func() error {
+		for _, blk := range blocks {
+			color.Cyan(
+				"height=%d id=%s time=%s txs=%d units=%d price=%d cost=%d",
+				blk.Height, blk.BlockID, time.Unix(blk.Timestamp, 0).Format(time.RFC3339),
+				blk.Txs, blk.Units, blk.Price, blk.Cost,
+			)
+		}
+		if len(next) > 0 {
+			color.Yellow("older blocks available (--cursor %s)", next)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,88 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/parser"
+	"github.com/ava-labs/spacesvm/tree"
+)
+
+var syncCmd = &cobra.Command{
+	Use:   "sync [options] <dir> <space>",
+	Short: "Syncs a local directory to the given space",
+	Long: `
+Uploads the files in a local directory that changed since the last sync,
+deletes the keys of files that were changed or removed, and records the
+synced files in a manifest stored at "<space>/manifest".
+
+$ spaces-cli sync ./site hello
+uploading index.html
+...
+synced ./site to hello (manifest hello/8d7a...): 1 uploaded, 3 unchanged, 0 removed, 1 keys deleted
+`,
+	RunE: syncFunc,
+}
+
+func syncFunc(cmd *cobra.Command, args []string) error {
+	dir, space, err := getSyncOp(args)
+	if err != nil {
+		return err
+	}
+
+	priv, err := loadPrivateKey()
+	if err != nil {
+		return err
+	}
+
+	cli := client.New(uri, requestTimeout, clientOptions()...)
+	g, err := cli.Genesis(context.Background())
+	if err != nil {
+		return err
+	}
+
+	// TODO: protect against overflow
+	result, err := tree.Sync(context.Background(), cli, priv, space, dir, int(g.MaxValueSize), txOptions()...)
+	if err != nil {
+		return err
+	}
+
+	return printResult(result, func() error {
+		color.Green(
+			"synced %s to %s (manifest %s): %d uploaded, %d unchanged, %d removed, %d keys deleted",
+			dir, space, result.Manifest,
+			len(result.Uploaded), len(result.Unchanged), len(result.Removed), result.DeletedKeys,
+		)
+		return nil
+	})
+}
+
+func getSyncOp(args []string) (dir string, space string, err error) {
+	if len(args) != 2 {
+		return "", "", fmt.Errorf("expected exactly 2 arguments, got %d", len(args))
+	}
+
+	dir = args[0]
+	info, err := os.Stat(dir)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: directory is not accessible", err)
+	}
+	if !info.IsDir() {
+		return "", "", fmt.Errorf("%s is not a directory", dir)
+	}
+
+	space = args[1]
+	if err := parser.CheckContents(space); err != nil {
+		return "", "", fmt.Errorf("%w: failed to parse space", err)
+	}
+
+	return dir, space, nil
+}
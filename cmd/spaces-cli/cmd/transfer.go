@@ -9,7 +9,6 @@ import (
 	"strconv"
 
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 
@@ -24,7 +23,7 @@ var transferCmd = &cobra.Command{
 }
 
 func transferFunc(cmd *cobra.Command, args []string) error {
-	priv, err := crypto.LoadECDSA(privateKeyFile)
+	priv, err := loadPrivateKey()
 	if err != nil {
 		return err
 	}
@@ -40,17 +39,20 @@ func transferFunc(cmd *cobra.Command, args []string) error {
 		Units:  units,
 	}
 
-	cli := client.New(uri, requestTimeout)
-	opts := []client.OpOption{client.WithPollTx()}
+	cli := client.New(uri, requestTimeout, clientOptions()...)
+	opts := txOptions()
 	if verbose {
 		opts = append(opts, client.WithBalance())
 	}
-	if _, _, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, opts...); err != nil {
+	txID, cost, err := client.SignIssueRawTx(context.Background(), cli, utx, priv, opts...)
+	if err != nil {
 		return err
 	}
 
-	color.Green("transferred %d to %s", units, to.Hex())
-	return nil
+	return printResult(&txResult{TxID: txID, Cost: cost, To: &to, Units: units}, func() error {
+		color.Green("transferred %d to %s", units, to.Hex())
+		return nil
+	})
 }
 
 func getTransferOp(args []string) (to common.Address, units uint64, err error) {
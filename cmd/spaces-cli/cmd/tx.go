@@ -0,0 +1,54 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/ava-labs/spacesvm/client"
+)
+
+var txCmd = &cobra.Command{
+	Use:   "tx [options] <tx ID>",
+	Short: "Prints the full contents (or mempool status) of a transaction",
+	RunE:  txFunc,
+}
+
+func txFunc(cmd *cobra.Command, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("expected exactly 1 argument, got %d", len(args))
+	}
+	txID, err := ids.FromString(args[0])
+	if err != nil {
+		return fmt.Errorf("%w: failed to parse tx ID", err)
+	}
+
+	cli := client.New(uri, requestTimeout, clientOptions()...)
+	reply, err := cli.Tx(context.Background(), txID)
+	if err != nil {
+		return err
+	}
+
+	return printResult(reply, func() error {
+		switch {
+		case reply.Pending:
+			color.Yellow("tx %s is pending in the mempool", txID)
+		case !reply.Accepted && len(reply.DropReason) > 0:
+			color.Red("tx %s has not been accepted (dropped from the mempool: %s)", txID, reply.DropReason)
+		case !reply.Accepted:
+			color.Yellow("tx %s has not been accepted", txID)
+		case reply.Tx == nil:
+			color.Yellow("tx %s was accepted before blocks were indexed", txID)
+		default:
+			color.Cyan("accepted in block %s", reply.BlockID)
+			ppTx(reply.Tx)
+		}
+		return nil
+	})
+}
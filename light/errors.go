@@ -0,0 +1,14 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package light
+
+import "errors"
+
+var (
+	ErrInvalidHeader = errors.New("header does not match its block ID")
+	ErrBrokenChain   = errors.New("header does not extend the verified chain")
+	ErrNoSigner      = errors.New("no response signer configured")
+	ErrChainMismatch = errors.New("attestation is for a different chain")
+	ErrUnknownBlock  = errors.New("attested block is not on the verified chain")
+)
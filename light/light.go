@@ -0,0 +1,240 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package light implements a client that follows the chain and verifies the
+// values it reads without running a full node.
+//
+// Headers are verified by hashing the raw bytes of each block (its ID) and
+// checking that it extends the last verified header, starting from a block ID
+// the caller trusts. Blocks do not commit to a state root, so resolved values
+// cannot be proven against a header with a merkle proof. Instead, values must
+// be attested by a node the caller trusts (see [vm.Attestation]) at a block on
+// the verified chain.
+package light
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/vm"
+)
+
+const defaultMaxHeaders = 1024
+
+// Header is the part of an accepted block a light client tracks
+type Header struct {
+	ID        ids.ID `json:"id"`
+	Parent    ids.ID `json:"parent"`
+	Height    uint64 `json:"height"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// ParseHeader returns the header of the block encoded in [b], verifying it
+// hashes to [id].
+func ParseHeader(id ids.ID, b []byte) (*Header, error) {
+	if hid := ids.ID(crypto.Keccak256Hash(b)); hid != id {
+		return nil, fmt.Errorf("%w: expected %s, got %s", ErrInvalidHeader, id, hid)
+	}
+	blk := new(chain.StatefulBlock)
+	if _, err := chain.Unmarshal(b, blk); err != nil {
+		return nil, err
+	}
+	return &Header{ID: id, Parent: blk.Prnt, Height: blk.Hght, Timestamp: blk.Tmstmp}, nil
+}
+
+// parseHeader returns the header of the block encoded in [b] without a known
+// ID to check it against
+func parseHeader(b []byte) (*Header, error) {
+	return ParseHeader(ids.ID(crypto.Keccak256Hash(b)), b)
+}
+
+type Option func(*Client)
+
+// WithResponseSigner sets the node address that must attest resolved values
+func WithResponseSigner(signer common.Address) Option {
+	return func(c *Client) { c.signer = &signer }
+}
+
+// WithMaxHeaders retains the last [n] verified headers (values attested at
+// older blocks can no longer be verified)
+func WithMaxHeaders(n int) Option {
+	return func(c *Client) { c.maxHeaders = n }
+}
+
+// Client follows the chain through an untrusted node
+type Client struct {
+	cli     client.Client
+	chainID ids.ID
+
+	signer     *common.Address
+	maxHeaders int
+
+	l       sync.RWMutex
+	head    *Header
+	headers map[uint64]*Header
+}
+
+// New creates a light client that follows the chain from the block with ID
+// [trusted] (such as the genesis block).
+func New(ctx context.Context, cli client.Client, trusted ids.ID, opts ...Option) (*Client, error) {
+	c := &Client{
+		cli:        cli,
+		maxHeaders: defaultMaxHeaders,
+		headers:    map[uint64]*Header{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	_, _, chainID, err := cli.Network(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.chainID = chainID
+	b, err := cli.RawBlock(ctx, trusted)
+	if err != nil {
+		return nil, err
+	}
+	h, err := ParseHeader(trusted, b)
+	if err != nil {
+		return nil, err
+	}
+	c.head = h
+	c.headers[h.Height] = h
+	return c, nil
+}
+
+// Head returns the last verified header
+func (c *Client) Head() *Header {
+	c.l.RLock()
+	defer c.l.RUnlock()
+	return c.head
+}
+
+// Header returns the verified header at [height], if it is retained
+func (c *Client) Header(height uint64) (*Header, bool) {
+	c.l.RLock()
+	defer c.l.RUnlock()
+	h, ok := c.headers[height]
+	return h, ok
+}
+
+// Sync verifies the headers accepted by the node since the last verified
+// header and returns them (sorted from oldest to newest).
+func (c *Client) Sync(ctx context.Context) ([]*Header, error) {
+	c.l.Lock()
+	defer c.l.Unlock()
+
+	la, err := c.cli.Accepted(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b, err := c.cli.RawBlock(ctx, la)
+	if err != nil {
+		return nil, err
+	}
+	tip, err := ParseHeader(la, b)
+	if err != nil {
+		return nil, err
+	}
+	if tip.Height <= c.head.Height {
+		if h, ok := c.headers[tip.Height]; ok && h.ID != tip.ID {
+			return nil, fmt.Errorf("%w: %s at height %d", ErrBrokenChain, tip.ID, tip.Height)
+		}
+		return nil, nil
+	}
+
+	synced := make([]*Header, 0, tip.Height-c.head.Height)
+	for height := c.head.Height + 1; height < tip.Height; height++ {
+		b, err := c.cli.RawBlockAt(ctx, height)
+		if err != nil {
+			return synced, err
+		}
+		h, err := parseHeader(b)
+		if err != nil {
+			return synced, err
+		}
+		if err := c.extend(h); err != nil {
+			return synced, err
+		}
+		synced = append(synced, h)
+	}
+	if err := c.extend(tip); err != nil {
+		return synced, err
+	}
+	return append(synced, tip), nil
+}
+
+// extend makes [h] the verified head if it is the child of the current head
+//
+// Assumes [c.l] is held.
+func (c *Client) extend(h *Header) error {
+	if h.Parent != c.head.ID || h.Height != c.head.Height+1 || h.Timestamp < c.head.Timestamp {
+		return fmt.Errorf("%w: %s at height %d", ErrBrokenChain, h.ID, h.Height)
+	}
+	c.head = h
+	c.headers[h.Height] = h
+	if c.maxHeaders > 0 && h.Height >= uint64(c.maxHeaders) {
+		delete(c.headers, h.Height-uint64(c.maxHeaders))
+	}
+	return nil
+}
+
+// Follow invokes [f] for every verified header until [ctx] is done or
+// verification fails. The VM does not offer subscriptions, so the node is
+// polled every [interval].
+func (c *Client) Follow(ctx context.Context, interval time.Duration, f func(*Header)) error {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		headers, err := c.Sync(ctx)
+		for _, h := range headers {
+			f(h)
+		}
+		if err != nil {
+			return err
+		}
+
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Resolve returns the value at [path], verifying it was attested by the
+// configured signer at a block on the verified chain.
+func (c *Client) Resolve(ctx context.Context, path string) (*vm.ResolveReply, error) {
+	if c.signer == nil {
+		return nil, ErrNoSigner
+	}
+	resp, err := c.cli.ResolveWithAttestation(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.VerifyResolve(resp, path, *c.signer); err != nil {
+		return nil, err
+	}
+	a := resp.Attestation
+	if a.ChainID != c.chainID {
+		return nil, fmt.Errorf("%w: %s", ErrChainMismatch, a.ChainID)
+	}
+	if a.Height > c.Head().Height {
+		if _, err := c.Sync(ctx); err != nil {
+			return nil, err
+		}
+	}
+	h, ok := c.Header(a.Height)
+	if !ok || h.ID != a.BlockID {
+		return nil, fmt.Errorf("%w: %s at height %d", ErrUnknownBlock, a.BlockID, a.Height)
+	}
+	return resp, nil
+}
@@ -0,0 +1,121 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package light
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/client"
+)
+
+// chainClient serves raw blocks from [blocks], indexed by height
+type chainClient struct {
+	client.Client
+
+	blocks [][]byte
+}
+
+func (c *chainClient) add(t *testing.T, parent ids.ID) ids.ID {
+	b, err := chain.Marshal(&chain.StatefulBlock{
+		Prnt:   parent,
+		Hght:   uint64(len(c.blocks)),
+		Tmstmp: int64(len(c.blocks)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.blocks = append(c.blocks, b)
+	return ids.ID(crypto.Keccak256Hash(b))
+}
+
+func (c *chainClient) Network(context.Context) (uint32, ids.ID, ids.ID, error) {
+	return 0, ids.Empty, ids.Empty, nil
+}
+
+func (c *chainClient) Accepted(context.Context) (ids.ID, error) {
+	return ids.ID(crypto.Keccak256Hash(c.blocks[len(c.blocks)-1])), nil
+}
+
+func (c *chainClient) RawBlock(_ context.Context, blockID ids.ID) ([]byte, error) {
+	for _, b := range c.blocks {
+		if ids.ID(crypto.Keccak256Hash(b)) == blockID {
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("block %s not found", blockID)
+}
+
+func (c *chainClient) RawBlockAt(_ context.Context, height uint64) ([]byte, error) {
+	return c.blocks[height], nil
+}
+
+func TestClientSync(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cli := &chainClient{}
+	genesis := cli.add(t, ids.Empty)
+	if _, err := New(ctx, cli, ids.GenerateTestID()); err == nil {
+		t.Fatal("expected untrusted block to be rejected")
+	}
+	c, err := New(ctx, cli, genesis, WithMaxHeaders(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parent := genesis
+	for i := 0; i < 3; i++ {
+		parent = cli.add(t, parent)
+	}
+	headers, err := c.Sync(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(headers) != 3 {
+		t.Fatalf("expected 3 headers, got %d", len(headers))
+	}
+	if head := c.Head(); head.ID != parent || head.Height != 3 {
+		t.Fatalf("unexpected head %+v", head)
+	}
+	if _, ok := c.Header(1); ok {
+		t.Fatal("expected header 1 to be pruned")
+	}
+	if h, ok := c.Header(2); !ok || h.ID != headers[1].ID {
+		t.Fatal("expected header 2 to be retained")
+	}
+
+	// Nothing new
+	headers, err = c.Sync(ctx)
+	if err != nil || len(headers) != 0 {
+		t.Fatalf("expected no headers, got %d (%v)", len(headers), err)
+	}
+
+	// A node serving a block that does not extend the verified chain is
+	// detected
+	cli.add(t, genesis)
+	if _, err := c.Sync(ctx); !errors.Is(err, ErrBrokenChain) {
+		t.Fatalf("expected %v, got %v", ErrBrokenChain, err)
+	}
+}
+
+func TestClientResolveRequiresSigner(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	cli := &chainClient{}
+	c, err := New(ctx, cli, cli.add(t, ids.Empty))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Resolve(ctx, "space/key"); !errors.Is(err, ErrNoSigner) {
+		t.Fatalf("expected %v, got %v", ErrNoSigner, err)
+	}
+}
@@ -5,8 +5,10 @@ package mempool
 
 import (
 	"container/heap"
+	"sort"
 	"sync"
 
+	"github.com/ava-labs/avalanchego/cache"
 	"github.com/ava-labs/avalanchego/ids"
 
 	"github.com/ava-labs/spacesvm/chain"
@@ -14,6 +16,10 @@ import (
 
 var _ chain.Mempool = &Mempool{}
 
+// dropsPerTx is the number of dropped txs whose [chain.DropReason] is
+// remembered for each tx the mempool can hold
+const dropsPerTx = 4
+
 type Mempool struct {
 	mu      sync.RWMutex
 	g       *chain.Genesis
@@ -26,6 +32,18 @@ type Mempool struct {
 	Pending chan struct{}
 	// newTxs is an array of [Tx] that are ready to be gossiped.
 	newTxs []*chain.Transaction
+
+	// drops is the [chain.DropReason] of recently dropped txs, so users can
+	// find out why a tx they issued disappeared
+	drops *cache.LRU
+	// dropCounts is the number of txs dropped for each [chain.DropReason]
+	dropCounts map[chain.DropReason]uint64
+	// onDrop is called with every drop while the write lock is held
+	onDrop func(ids.ID, chain.DropReason)
+
+	// claims is the pending claim of each space. Only one claim per space
+	// can be accepted, so competing claims that pay less are dropped.
+	claims map[string]ids.ID
 }
 
 // New creates a new [Mempool]. [maxSize] must be > 0 or else the
@@ -37,6 +55,10 @@ func New(g *chain.Genesis, maxSize int) *Mempool {
 		maxHeap: newTxHeap(maxSize, false),
 		minHeap: newTxHeap(maxSize, true),
 		Pending: make(chan struct{}, 1),
+
+		drops:      &cache.LRU{Size: maxSize * dropsPerTx},
+		dropCounts: map[chain.DropReason]uint64{},
+		claims:     map[string]ids.ID{},
 	}
 }
 
@@ -51,10 +73,43 @@ func (th *Mempool) Add(tx *chain.Transaction) bool {
 	if th.maxHeap.Has(txID) {
 		return false
 	}
+	// Txs may be re-added (such as when a block is rejected)
+	th.drops.Evict(txID)
+
+	// Keep only the highest paying claim of each space (the earliest wins
+	// ties)
+	space, isClaim := claimedSpace(tx)
+	var prevID ids.ID
+	replaces := false
+	if isClaim {
+		if prevID, replaces = th.claims[space]; replaces {
+			if prev, _ := th.maxHeap.Get(prevID); prev.price >= price {
+				th.recordDrop(txID, chain.DropConflict)
+				return false
+			}
+		}
+	}
 
-	oldLen := th.maxHeap.Len()
+	// Make room for [tx] by evicting the lowest paying tx (unless it is [tx])
+	// before changing the mempool, so a claim is never replaced by a tx that
+	// is then evicted. Replacing a claim frees its slot.
+	if !replaces && th.maxHeap.Len() >= th.maxSize {
+		if th.minHeap.items[0].price > price {
+			th.recordDrop(txID, chain.DropEvicted)
+			return false
+		}
+		t, _ := th.popMin()
+		th.recordDrop(t.ID(), chain.DropEvicted)
+	}
+	if replaces {
+		th.remove(prevID)
+		th.recordDrop(prevID, chain.DropConflict)
+	}
+	if isClaim {
+		th.claims[space] = txID
+	}
 
-	// Optimistically add tx to mempool
+	oldLen := th.maxHeap.Len()
 	heap.Push(th.maxHeap, &txEntry{
 		id:    txID,
 		price: price,
@@ -68,17 +123,6 @@ func (th *Mempool) Add(tx *chain.Transaction) bool {
 		index: oldLen,
 	})
 
-	// Remove the lowest paying tx
-	//
-	// Note: we do this after adding the new transaction in case it is the new
-	// lowest paying transaction
-	if th.maxHeap.Len() > th.maxSize {
-		t, _ := th.popMin()
-		if t.ID() == txID {
-			return false
-		}
-	}
-
 	// When adding [tx] to the mempool make sure that there is an item in Pending
 	// to signal the VM to produce a block. Note: if the VM's buildStatus has already
 	// been set to something other than [dontBuild], this will be ignored and won't be
@@ -143,8 +187,49 @@ func (th *Mempool) Prune(validHashes ids.Set) {
 	th.mu.RUnlock()
 
 	for _, txID := range toRemove { // O(K * log N)
-		th.Remove(txID)
+		th.Drop(txID, chain.DropExpired)
+	}
+}
+
+// Drop removes [txID] (if it is still in the mempool) and records that it left
+// for [reason]
+func (th *Mempool) Drop(txID ids.ID, reason chain.DropReason) {
+	th.mu.Lock()
+	defer th.mu.Unlock()
+
+	th.remove(txID)
+	th.recordDrop(txID, reason)
+}
+
+// OnDrop registers [f] to be called whenever a tx leaves the mempool. [f] is
+// called while the mempool is locked, so calls are serialized and [f] must
+// not use the mempool.
+func (th *Mempool) OnDrop(f func(txID ids.ID, reason chain.DropReason)) {
+	th.mu.Lock()
+	defer th.mu.Unlock()
+
+	th.onDrop = f
+}
+
+// DropReason returns why [txID] recently left the mempool, if it did
+func (th *Mempool) DropReason(txID ids.ID) (chain.DropReason, bool) {
+	th.mu.RLock()
+	defer th.mu.RUnlock()
+
+	v, ok := th.drops.Get(txID)
+	if !ok {
+		return "", false
 	}
+	reason, ok := v.(chain.DropReason)
+	return reason, ok
+}
+
+// Dropped returns the number of txs that have left the mempool for [reason]
+func (th *Mempool) Dropped(reason chain.DropReason) uint64 {
+	th.mu.RLock()
+	defer th.mu.RUnlock()
+
+	return th.dropCounts[reason]
 }
 
 func (th *Mempool) Len() int {
@@ -172,34 +257,75 @@ func (th *Mempool) Has(id ids.ID) bool {
 	return th.maxHeap.Has(id)
 }
 
-// GetNewTxs returns the array of [newTxs] and replaces it with a new array.
+// Txs returns every pending tx, sorted by price (highest first) like the order
+// they are included in blocks.
+func (th *Mempool) Txs() []*chain.Transaction {
+	th.mu.RLock()
+	entries := make([]*txEntry, len(th.maxHeap.items))
+	copy(entries, th.maxHeap.items)
+	th.mu.RUnlock()
+
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].price > entries[j].price })
+	txs := make([]*chain.Transaction, len(entries))
+	for i, entry := range entries {
+		txs[i] = entry.tx
+	}
+	return txs
+}
+
+// NewTxs returns the new transactions that are most likely to be included in
+// a block (the highest paying per unit first) whose load units fit in
+// [maxUnits]. Transactions that do not fit remain new (in arrival order) until
+// the next call.
 func (th *Mempool) NewTxs(maxUnits uint64) []*chain.Transaction {
 	th.mu.Lock()
 	defer th.mu.Unlock()
 
-	// Note: this algorithm preserves the ordering of new transactions
-	var (
-		units    uint64
-		selected = th.newTxs[:0]
-	)
-	for i, tx := range th.newTxs {
+	pending := make([]*chain.Transaction, 0, len(th.newTxs))
+	for _, tx := range th.newTxs {
 		// It is possible that a block may have been accepted that contains some
 		// new transactions before [NewTxs] is called.
-		if !th.maxHeap.Has(tx.ID()) {
-			continue
+		if th.maxHeap.Has(tx.ID()) {
+			pending = append(pending, tx)
 		}
+	}
+	byPrice := make([]*chain.Transaction, len(pending))
+	copy(byPrice, pending)
+	sort.SliceStable(byPrice, func(i, j int) bool { return byPrice[i].GetPrice() > byPrice[j].GetPrice() })
+
+	var (
+		units    uint64
+		selected = []*chain.Transaction{}
+		chosen   = map[ids.ID]struct{}{}
+	)
+	for _, tx := range byPrice {
 		txUnits := tx.LoadUnits(th.g)
 		if txUnits > maxUnits-units {
-			th.newTxs = th.newTxs[i:]
-			return selected
+			// Smaller txs may still fit
+			continue
 		}
 		units += txUnits
 		selected = append(selected, tx)
+		chosen[tx.ID()] = struct{}{}
+	}
+	th.newTxs = make([]*chain.Transaction, 0, len(pending)-len(selected))
+	for _, tx := range pending {
+		if _, ok := chosen[tx.ID()]; !ok {
+			th.newTxs = append(th.newTxs, tx)
+		}
 	}
-	th.newTxs = th.newTxs[len(th.newTxs):]
 	return selected
 }
 
+// recordDrop assumes the write lock is held
+func (th *Mempool) recordDrop(txID ids.ID, reason chain.DropReason) {
+	th.drops.Put(txID, reason)
+	th.dropCounts[reason]++
+	if th.onDrop != nil {
+		th.onDrop(txID, reason)
+	}
+}
+
 // popMin assumes the write lock is held and takes O(log N) time to run.
 func (th *Mempool) popMin() (*chain.Transaction, uint64) { // O(log N)
 	item := th.minHeap.items[0]
@@ -225,9 +351,21 @@ func (th *Mempool) remove(id ids.ID) *chain.Transaction {
 		// This should never happen
 		return nil
 	}
+	if space, ok := claimedSpace(txe.tx); ok && th.claims[space] == id {
+		delete(th.claims, space)
+	}
 	return txe.tx
 }
 
+// claimedSpace returns the space claimed by [tx], if it is a claim
+func claimedSpace(tx *chain.Transaction) (string, bool) {
+	claim, ok := tx.UnsignedTransaction.(*chain.ClaimTx)
+	if !ok {
+		return "", false
+	}
+	return claim.Space, true
+}
+
 // addPending makes sure that an item is in the Pending channel.
 func (th *Mempool) addPending() {
 	select {
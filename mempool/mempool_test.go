@@ -7,6 +7,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/ava-labs/avalanchego/ids"
 	"github.com/ethereum/go-ethereum/crypto"
 
 	"github.com/ava-labs/spacesvm/chain"
@@ -54,4 +55,229 @@ func TestMempool(t *testing.T) {
 	if length := txm.Len(); length != 3 {
 		t.Fatalf("length expected 3, got %d", length)
 	}
+	txs := txm.Txs()
+	for i, price := range []uint64{250, 220, 200} {
+		if txs[i].GetPrice() != price {
+			t.Fatalf("tx %d price expected %d, got %d", i, price, txs[i].GetPrice())
+		}
+	}
+}
+
+func TestMempoolDropReasons(t *testing.T) {
+	g := chain.DefaultGenesis()
+	txm := mempool.New(g, 2)
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	blkID := ids.GenerateTestID()
+	newTx := func(price uint64) *chain.Transaction {
+		utx := &chain.TransferTx{BaseTx: &chain.BaseTx{BlockID: blkID, Price: price}, Units: price}
+		dh, err := chain.DigestHash(utx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := chain.Sign(dh, priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx := chain.NewTx(utx, sig)
+		if err := tx.Init(g); err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+	expectReason := func(tx *chain.Transaction, expected chain.DropReason) {
+		t.Helper()
+		reason, ok := txm.DropReason(tx.ID())
+		if !ok || reason != expected {
+			t.Fatalf("expected drop reason %q, got %q (found=%t)", expected, reason, ok)
+		}
+	}
+
+	hooked := map[ids.ID]chain.DropReason{}
+	txm.OnDrop(func(txID ids.ID, reason chain.DropReason) { hooked[txID] = reason })
+
+	// Lowest paying tx is evicted when the mempool is full
+	cheap, mid, high := newTx(1), newTx(2), newTx(3)
+	for _, tx := range []*chain.Transaction{cheap, mid, high} {
+		txm.Add(tx)
+	}
+	expectReason(cheap, chain.DropEvicted)
+
+	txm.Drop(high.ID(), chain.DropIncluded)
+	expectReason(high, chain.DropIncluded)
+	if txm.Has(high.ID()) {
+		t.Fatal("expected dropped tx to be removed")
+	}
+
+	// Re-added txs are no longer considered dropped
+	txm.Add(high)
+	if _, ok := txm.DropReason(high.ID()); ok {
+		t.Fatal("expected re-added tx to have no drop reason")
+	}
+
+	// Txs referencing blocks outside the lookback window expire
+	txm.Prune(ids.Set{})
+	expectReason(mid, chain.DropExpired)
+	expectReason(high, chain.DropExpired)
+	if txm.Len() != 0 {
+		t.Fatalf("expected empty mempool, got %d", txm.Len())
+	}
+
+	for reason, expected := range map[chain.DropReason]uint64{
+		chain.DropEvicted:  1,
+		chain.DropIncluded: 1,
+		chain.DropExpired:  2,
+		chain.DropInvalid:  0,
+	} {
+		if dropped := txm.Dropped(reason); dropped != expected {
+			t.Fatalf("expected %d %s drops, got %d", expected, reason, dropped)
+		}
+	}
+
+	// Every drop is passed to the hook
+	for tx, expected := range map[*chain.Transaction]chain.DropReason{
+		cheap: chain.DropEvicted,
+		mid:   chain.DropExpired,
+		high:  chain.DropExpired,
+	} {
+		if reason := hooked[tx.ID()]; reason != expected {
+			t.Fatalf("expected hooked drop reason %q, got %q", expected, reason)
+		}
+	}
+}
+
+func TestMempoolClaimConflicts(t *testing.T) {
+	g := chain.DefaultGenesis()
+	txm := mempool.New(g, 8)
+	newClaim := func(space string, price uint64) *chain.Transaction {
+		priv, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		utx := &chain.ClaimTx{BaseTx: &chain.BaseTx{Price: price}, Space: space}
+		dh, err := chain.DigestHash(utx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := chain.Sign(dh, priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx := chain.NewTx(utx, sig)
+		if err := tx.Init(g); err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+	expectReason := func(tx *chain.Transaction, expected chain.DropReason) {
+		t.Helper()
+		reason, ok := txm.DropReason(tx.ID())
+		if !ok || reason != expected {
+			t.Fatalf("expected drop reason %q, got %q (found=%t)", expected, reason, ok)
+		}
+	}
+
+	first, other := newClaim("contested", 10), newClaim("other", 1)
+	if !txm.Add(first) || !txm.Add(other) {
+		t.Fatal("expected claims of different spaces to be added")
+	}
+
+	// Claims paying no more than the pending claim are rejected
+	tie := newClaim("contested", 10)
+	if txm.Add(tie) {
+		t.Fatal("expected tied claim to be rejected")
+	}
+	expectReason(tie, chain.DropConflict)
+
+	// Claims paying more replace the pending claim
+	higher := newClaim("contested", 11)
+	if !txm.Add(higher) {
+		t.Fatal("expected higher claim to be added")
+	}
+	expectReason(first, chain.DropConflict)
+	if txm.Has(first.ID()) || !txm.Has(higher.ID()) || txm.Len() != 2 {
+		t.Fatal("expected only the higher claim to be pending")
+	}
+
+	// Replacing a claim in a full mempool keeps the other txs
+	full := mempool.New(g, 2)
+	cheap, pending := newClaim("cheap", 1), newClaim("full", 5)
+	if !full.Add(cheap) || !full.Add(pending) {
+		t.Fatal("expected claims to be added")
+	}
+	replacement := newClaim("full", 6)
+	if !full.Add(replacement) {
+		t.Fatal("expected replacement claim to be added")
+	}
+	if !full.Has(cheap.ID()) || full.Has(pending.ID()) || !full.Has(replacement.ID()) {
+		t.Fatal("expected only the replaced claim to be dropped")
+	}
+
+	// Txs that would be evicted don't change the mempool
+	if full.Add(newClaim("evicted", 0)) {
+		t.Fatal("expected lowest paying claim to be evicted")
+	}
+	if !full.Has(cheap.ID()) || !full.Has(replacement.ID()) {
+		t.Fatal("expected pending claims to be kept")
+	}
+
+	// Once the pending claim leaves, competing claims are admitted again
+	txm.Drop(higher.ID(), chain.DropIncluded)
+	if !txm.Add(first) {
+		t.Fatal("expected claim to be re-added")
+	}
+	if dropped := txm.Dropped(chain.DropConflict); dropped != 2 {
+		t.Fatalf("expected 2 conflict drops, got %d", dropped)
+	}
+}
+
+func TestMempoolNewTxs(t *testing.T) {
+	g := chain.DefaultGenesis()
+	txm := mempool.New(g, 8)
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	newTx := func(price uint64) *chain.Transaction {
+		utx := &chain.TransferTx{BaseTx: &chain.BaseTx{BlockID: ids.GenerateTestID(), Price: price}, Units: price}
+		dh, err := chain.DigestHash(utx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := chain.Sign(dh, priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx := chain.NewTx(utx, sig)
+		if err := tx.Init(g); err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+	expectPrices := func(txs []*chain.Transaction, prices ...uint64) {
+		t.Helper()
+		if len(txs) != len(prices) {
+			t.Fatalf("expected %d txs, got %d", len(prices), len(txs))
+		}
+		for i, price := range prices {
+			if txs[i].GetPrice() != price {
+				t.Fatalf("tx %d price expected %d, got %d", i, price, txs[i].GetPrice())
+			}
+		}
+	}
+
+	included := newTx(5)
+	for _, tx := range []*chain.Transaction{newTx(1), newTx(3), included, newTx(2), newTx(4)} {
+		txm.Add(tx)
+	}
+	txm.Drop(included.ID(), chain.DropIncluded)
+
+	// The highest paying new txs are selected first
+	units := included.LoadUnits(g)
+	expectPrices(txm.NewTxs(2*units), 4, 3)
+	// The rest remain new
+	expectPrices(txm.NewTxs(10*units), 2, 1)
+	expectPrices(txm.NewTxs(10 * units))
 }
@@ -0,0 +1,145 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package parser
+
+import (
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mr-tron/base58"
+)
+
+const (
+	// CIDv0s are the base58btc encoding of a sha2-256 multihash
+	cidV0Len    = 46
+	cidV0Prefix = "Qm"
+
+	multihashSHA2256 = 0x12
+)
+
+var ErrInvalidCID = errors.New("invalid IPFS CID")
+
+// multihashLengths are the digest lengths of the hash functions a CID may
+// use. Identity multihashes are rejected so that CIDs can't inline content.
+var multihashLengths = map[uint64]int{
+	0x11:   20, // sha1
+	0x12:   32, // sha2-256
+	0x13:   64, // sha2-512
+	0x14:   64, // sha3-512
+	0x15:   48, // sha3-384
+	0x16:   32, // sha3-256
+	0x17:   28, // sha3-224
+	0x1b:   32, // keccak-256
+	0x1d:   64, // keccak-512
+	0x1e:   32, // blake3
+	0xb220: 32, // blake2b-256
+	0xb240: 64, // blake2b-512
+	0xb260: 32, // blake2s-256
+}
+
+var lowerBase32 = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// CID is a parsed IPFS content identifier
+type CID struct {
+	Version  uint64
+	Codec    uint64
+	HashCode uint64
+	Digest   []byte
+}
+
+// ParseCID parses a CIDv0 ("Qm...") or a multibase (base32, base58btc, or
+// base16) encoded CIDv1 and checks that its multihash uses a known hash
+// function with the correct digest length.
+func ParseCID(s string) (*CID, error) {
+	if len(s) == cidV0Len && strings.HasPrefix(s, cidV0Prefix) {
+		b, err := base58.Decode(s)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidCID, err)
+		}
+		code, digest, err := parseMultihash(b)
+		if err != nil {
+			return nil, err
+		}
+		if code != multihashSHA2256 {
+			return nil, fmt.Errorf("%w: CIDv0 must use sha2-256", ErrInvalidCID)
+		}
+		// CIDv0s are implicitly dag-pb
+		return &CID{Version: 0, Codec: 0x70, HashCode: code, Digest: digest}, nil
+	}
+
+	if len(s) < 2 {
+		return nil, fmt.Errorf("%w: too short", ErrInvalidCID)
+	}
+	var (
+		b   []byte
+		err error
+	)
+	switch s[0] {
+	case 'b':
+		b, err = lowerBase32.DecodeString(s[1:])
+	case 'B':
+		b, err = lowerBase32.DecodeString(strings.ToLower(s[1:]))
+	case 'z':
+		b, err = base58.Decode(s[1:])
+	case 'f', 'F':
+		b, err = hex.DecodeString(s[1:])
+	default:
+		return nil, fmt.Errorf("%w: unsupported multibase %q", ErrInvalidCID, s[0])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidCID, err)
+	}
+	version, b, err := readUvarint(b)
+	if err != nil {
+		return nil, err
+	}
+	if version != 1 {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidCID, version)
+	}
+	codec, b, err := readUvarint(b)
+	if err != nil {
+		return nil, err
+	}
+	code, digest, err := parseMultihash(b)
+	if err != nil {
+		return nil, err
+	}
+	return &CID{Version: version, Codec: codec, HashCode: code, Digest: digest}, nil
+}
+
+// parseMultihash returns the hash function and digest of the multihash [b]
+func parseMultihash(b []byte) (uint64, []byte, error) {
+	code, b, err := readUvarint(b)
+	if err != nil {
+		return 0, nil, err
+	}
+	l, b, err := readUvarint(b)
+	if err != nil {
+		return 0, nil, err
+	}
+	expected, ok := multihashLengths[code]
+	if !ok {
+		return 0, nil, fmt.Errorf("%w: unsupported hash function 0x%x", ErrInvalidCID, code)
+	}
+	if l != uint64(expected) || len(b) != expected {
+		return 0, nil, fmt.Errorf("%w: expected %d byte digest for hash function 0x%x", ErrInvalidCID, expected, code)
+	}
+	return code, b, nil
+}
+
+// readUvarint reads a minimally encoded varint from the start of [b]
+func readUvarint(b []byte) (uint64, []byte, error) {
+	v, n := binary.Uvarint(b)
+	if n <= 0 {
+		return 0, nil, fmt.Errorf("%w: malformed varint", ErrInvalidCID)
+	}
+	if n > 1 && b[n-1] == 0 {
+		return 0, nil, fmt.Errorf("%w: varint is not minimally encoded", ErrInvalidCID)
+	}
+	return v, b[n:], nil
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package parser
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/mr-tron/base58"
+)
+
+func TestParseCID(t *testing.T) {
+	t.Parallel()
+
+	digest := sha256.Sum256([]byte("hello"))
+	multihash := append([]byte{0x12, 0x20}, digest[:]...)
+	v1 := append([]byte{0x01, 0x55}, multihash...)
+
+	tt := []struct {
+		cid     string
+		version uint64
+		err     error
+	}{
+		{cid: "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG", version: 0},
+		{cid: "bafybeie5gq4jxvzmsym6hjlwxej4rwdoxt7wadqvmmwbqi7r27fclha2va", version: 1},
+		{cid: base58.Encode(multihash), version: 0},
+		{cid: "b" + lowerBase32.EncodeToString(v1), version: 1},
+		{cid: "B" + strings.ToUpper(lowerBase32.EncodeToString(v1)), version: 1},
+		{cid: "z" + base58.Encode(v1), version: 1},
+		{cid: "f" + hex.EncodeToString(v1), version: 1},
+		{cid: "", err: ErrInvalidCID},
+		{cid: "hello", err: ErrInvalidCID},
+		// Unsupported multibase
+		{cid: "m" + lowerBase32.EncodeToString(v1), err: ErrInvalidCID},
+		// Truncated digest
+		{cid: "f" + hex.EncodeToString(v1[:len(v1)-1]), err: ErrInvalidCID},
+		// Trailing bytes
+		{cid: "f" + hex.EncodeToString(append(v1, 0)), err: ErrInvalidCID},
+		// Identity multihash
+		{cid: "f" + hex.EncodeToString([]byte{0x01, 0x55, 0x00, 0x05, 'h', 'e', 'l', 'l', 'o'}), err: ErrInvalidCID},
+		// Unsupported version
+		{cid: "f" + hex.EncodeToString(append([]byte{0x02, 0x55}, multihash...)), err: ErrInvalidCID},
+		// Non-minimal varint codec
+		{cid: "f" + hex.EncodeToString(append([]byte{0x01, 0xd5, 0x00}, multihash...)), err: ErrInvalidCID},
+	}
+	for i, tv := range tt {
+		c, err := ParseCID(tv.cid)
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("#%d: expected %v, got %v", i, tv.err, err)
+		}
+		if tv.err != nil {
+			continue
+		}
+		if c.Version != tv.version {
+			t.Fatalf("#%d: expected version %d, got %d", i, tv.version, c.Version)
+		}
+		if len(c.Digest) != multihashLengths[c.HashCode] {
+			t.Fatalf("#%d: unexpected digest length %d", i, len(c.Digest))
+		}
+	}
+}
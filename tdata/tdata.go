@@ -21,7 +21,7 @@
 // You should have received a copy of the GNU Lesser General Public License
 // along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
 
-//nolint
+// nolint
 package tdata
 
 import (
@@ -51,8 +51,9 @@ type TypedDataMessage = map[string]interface{}
 
 // TypedDataDomain represents the domain part of an EIP-712 message.
 type TypedDataDomain struct {
-	Name  string `json:"name"`
-	Magic string `json:"magic"`
+	Name    string `json:"name"`
+	Magic   string `json:"magic"`
+	ChainID string `json:"chainId,omitempty"`
 }
 
 type TypedData struct {
@@ -65,23 +66,36 @@ type TypedData struct {
 var EIP712Domain = []Type{
 	{Name: "name", Type: "string"},
 	{Name: "magic", Type: "uint64"},
+	{Name: "chainId", Type: "string"},
 }
 
-func spacesDomain(m uint64) TypedDataDomain {
+// legacyEIP712Domain is the domain of transactions signed before they were
+// bound to a chain ID
+var legacyEIP712Domain = EIP712Domain[:2]
+
+func spacesDomain(m uint64, chainID string) TypedDataDomain {
 	return TypedDataDomain{
-		Name:  "Spaces",
-		Magic: strconv.FormatUint(m, 10),
+		Name:    "Spaces",
+		Magic:   strconv.FormatUint(m, 10),
+		ChainID: chainID,
 	}
 }
 
-func CreateTypedData(magic uint64, txType string, txFields []Type, msg TypedDataMessage) *TypedData {
+// CreateTypedData returns the EIP-712 typed data of a transaction. [magic] and
+// [chainID] are included in the domain so signatures are only valid on a
+// single chain. The chain ID is omitted from the domain if it is empty.
+func CreateTypedData(magic uint64, chainID string, txType string, txFields []Type, msg TypedDataMessage) *TypedData {
+	domain := EIP712Domain
+	if len(chainID) == 0 {
+		domain = legacyEIP712Domain
+	}
 	return &TypedData{
 		Types: Types{
 			txType:         txFields,
-			"EIP712Domain": EIP712Domain,
+			"EIP712Domain": domain,
 		},
 		PrimaryType: txType,
-		Domain:      spacesDomain(magic),
+		Domain:      spacesDomain(magic, chainID),
 		Message:     msg,
 	}
 }
@@ -393,8 +407,12 @@ func (typedData *TypedData) Map() map[string]interface{} {
 // Checks if the primitive value is valid
 // Map is a helper function to generate a map version of the domain
 func (domain *TypedDataDomain) Map() map[string]interface{} {
-	return map[string]interface{}{
+	m := map[string]interface{}{
 		"name":  domain.Name,
 		"magic": domain.Magic,
 	}
+	if len(domain.ChainID) > 0 {
+		m["chainId"] = domain.ChainID
+	}
+	return m
 }
@@ -5,29 +5,28 @@
 package integration_test
 
 import (
+	"bytes"
 	"context"
 	"crypto/ecdsa"
 	"crypto/sha256"
 	"encoding/hex"
-	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"math/rand"
-	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
-	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/chains/atomic"
 	"github.com/ava-labs/avalanchego/ids"
-	"github.com/ava-labs/avalanchego/snow"
 	"github.com/ava-labs/avalanchego/snow/choices"
-	"github.com/ava-labs/avalanchego/snow/engine/common"
 	"github.com/ava-labs/avalanchego/utils/units"
-	avago_version "github.com/ava-labs/avalanchego/version"
+	"github.com/ava-labs/avalanchego/vms/components/avax"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 	ecommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/fatih/color"
@@ -37,10 +36,12 @@ import (
 
 	"github.com/ava-labs/spacesvm/chain"
 	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/light"
 	"github.com/ava-labs/spacesvm/parser"
-	"github.com/ava-labs/spacesvm/tdata"
 	"github.com/ava-labs/spacesvm/tree"
+	"github.com/ava-labs/spacesvm/version"
 	"github.com/ava-labs/spacesvm/vm"
+	"github.com/ava-labs/spacesvm/vmtest"
 )
 
 func TestIntegration(t *testing.T) {
@@ -83,21 +84,13 @@ var (
 	sender2 ecommon.Address
 
 	// when used with embedded VMs
-	genesisBytes []byte
-	instances    []instance
+	network   *vmtest.Network
+	instances []*vmtest.Instance
 
-	genesis *chain.Genesis
+	genesis     *chain.Genesis
+	airdropData []byte
 )
 
-type instance struct {
-	nodeID     ids.NodeID
-	vm         *vm.VM
-	toEngine   chan common.Message
-	httpServer *httptest.Server
-	cli        client.Client // clients for embedded VMs
-	builder    *vm.ManualBuilder
-}
-
 var _ = ginkgo.BeforeSuite(func() {
 	gomega.Ω(vms).Should(gomega.BeNumerically(">", 1))
 
@@ -114,9 +107,6 @@ var _ = ginkgo.BeforeSuite(func() {
 
 	log.Debug("generated key", "addr", sender2, "priv", hex.EncodeToString(crypto.FromECDSA(priv2)))
 
-	// create embedded VMs
-	instances = make([]instance, vms)
-
 	genesis = chain.DefaultGenesis()
 	if minPrice >= 0 {
 		genesis.MinPrice = uint64(minPrice)
@@ -129,67 +119,23 @@ var _ = ginkgo.BeforeSuite(func() {
 			Balance: 10000000,
 		},
 	}
-	airdropData := []byte(fmt.Sprintf(`[{"address":"%s"}]`, sender2))
+	airdropData = []byte(fmt.Sprintf(`[{"address":"%s"}]`, sender2))
 	genesis.AirdropHash = ecommon.BytesToHash(crypto.Keccak256(airdropData)).Hex()
 	genesis.AirdropUnits = 1000000000
-	genesisBytes, err = json.Marshal(genesis)
-	gomega.Ω(err).Should(gomega.BeNil())
-
-	networkID := uint32(1)
-	subnetID := ids.GenerateTestID()
-	chainID := ids.GenerateTestID()
-
-	app := &appSender{}
-	for i := range instances {
-		ctx := &snow.Context{
-			NetworkID: networkID,
-			SubnetID:  subnetID,
-			ChainID:   chainID,
-			NodeID:    ids.GenerateTestNodeID(),
-		}
-
-		toEngine := make(chan common.Message, 1)
-		db := manager.NewMemDB(avago_version.CurrentDatabase)
-
-		// TODO: test appsender
-		v := &vm.VM{AirdropData: airdropData}
-		err := v.Initialize(
-			ctx,
-			db,
-			genesisBytes,
-			nil,
-			nil,
-			toEngine,
-			nil,
-			app,
-		)
-		gomega.Ω(err).Should(gomega.BeNil())
-
-		var mb *vm.ManualBuilder
-		v.SetBlockBuilder(func() vm.BlockBuilder {
-			mb = v.NewManualBuilder()
-			return mb
-		})
-
-		var hd map[string]*common.HTTPHandler
-		hd, err = v.CreateHandlers()
-		gomega.Ω(err).Should(gomega.BeNil())
 
-		httpServer := httptest.NewServer(hd[vm.PublicEndpoint].Handler)
-		instances[i] = instance{
-			nodeID:     ctx.NodeID,
-			vm:         v,
-			toEngine:   toEngine,
-			httpServer: httpServer,
-			cli:        client.New(httpServer.URL, requestTimeout),
-			builder:    mb,
-		}
-	}
+	// create embedded VMs
+	network, err = vmtest.New(
+		genesis, vms,
+		vmtest.WithAirdropData(airdropData),
+		vmtest.WithRequestTimeout(requestTimeout),
+	)
+	gomega.Ω(err).Should(gomega.BeNil())
+	instances = network.Instances
 
 	// Verify genesis allocations loaded correctly (do here otherwise test may
 	// check during and it will be inaccurate)
 	for _, inst := range instances {
-		cli := inst.cli
+		cli := inst.Client
 		g, err := cli.Genesis(context.Background())
 		gomega.Ω(err).Should(gomega.BeNil())
 
@@ -200,22 +146,17 @@ var _ = ginkgo.BeforeSuite(func() {
 		}
 	}
 
-	app.instances = instances
 	color.Blue("created %d VMs", vms)
 })
 
 var _ = ginkgo.AfterSuite(func() {
-	for _, iv := range instances {
-		iv.httpServer.Close()
-		err := iv.vm.Shutdown()
-		gomega.Ω(err).Should(gomega.BeNil())
-	}
+	gomega.Ω(network.Shutdown()).Should(gomega.BeNil())
 })
 
 var _ = ginkgo.Describe("[Ping]", func() {
 	ginkgo.It("can ping", func() {
 		for _, inst := range instances {
-			cli := inst.cli
+			cli := inst.Client
 			ok, err := cli.Ping(context.Background())
 			gomega.Ω(ok).Should(gomega.BeTrue())
 			gomega.Ω(err).Should(gomega.BeNil())
@@ -226,7 +167,7 @@ var _ = ginkgo.Describe("[Ping]", func() {
 var _ = ginkgo.Describe("[Network]", func() {
 	ginkgo.It("can get network", func() {
 		for _, inst := range instances {
-			cli := inst.cli
+			cli := inst.Client
 			networkID, subnetID, chainID, err := cli.Network(context.Background())
 			gomega.Ω(networkID).Should(gomega.Equal(uint32(1)))
 			gomega.Ω(subnetID).ShouldNot(gomega.Equal(ids.Empty))
@@ -236,6 +177,803 @@ var _ = ginkgo.Describe("[Network]", func() {
 	})
 })
 
+var _ = ginkgo.Describe("[Discover]", func() {
+	ginkgo.It("can discover methods", func() {
+		reply, err := instances[0].Client.Discover(context.Background())
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(reply.Version).Should(gomega.Equal(version.Version))
+
+		names := make([]string, len(reply.Methods))
+		for i, m := range reply.Methods {
+			names[i] = m.Name
+		}
+		gomega.Ω(names).Should(gomega.ContainElements("spacesvm.ping", "spacesvm.issueTx", "spacesvm.discover"))
+	})
+})
+
+var _ = ginkgo.Describe("[SenderRateLimit]", func() {
+	ginkgo.It("rejects senders over the limit", func() {
+		limited, err := vmtest.New(
+			genesis, 1,
+			vmtest.WithAirdropData(airdropData),
+			vmtest.WithConfig([]byte(`{"senderRateLimit":0.001,"senderRateBurst":2}`)),
+			vmtest.WithRequestTimeout(requestTimeout),
+		)
+		gomega.Ω(err).Should(gomega.BeNil())
+		defer func() {
+			gomega.Ω(limited.Shutdown()).Should(gomega.BeNil())
+		}()
+
+		i := limited.Instances[0]
+		for units := uint64(1); units <= 3; units++ {
+			_, err := i.IssueRawTx(context.Background(), &chain.TransferTx{
+				BaseTx: &chain.BaseTx{},
+				To:     sender2,
+				Units:  units,
+			}, priv)
+			if units <= 2 {
+				gomega.Ω(err).Should(gomega.BeNil())
+				continue
+			}
+			gomega.Ω(err).ShouldNot(gomega.BeNil())
+			gomega.Ω(err.Error()).Should(gomega.ContainSubstring(vm.ErrSenderRateLimited.Error()))
+		}
+	})
+})
+
+var _ = ginkgo.Describe("[Denylist]", func() {
+	ginkgo.It("refuses denied spaces", func() {
+		denying, err := vmtest.New(
+			genesis, 1,
+			vmtest.WithAirdropData(airdropData),
+			vmtest.WithConfig([]byte(`{"deniedSpaces":["banned*"]}`)),
+			vmtest.WithRequestTimeout(requestTimeout),
+		)
+		gomega.Ω(err).Should(gomega.BeNil())
+		defer func() {
+			gomega.Ω(denying.Shutdown()).Should(gomega.BeNil())
+		}()
+
+		i := denying.Instances[0]
+		_, err = i.IssueRawTx(context.Background(), &chain.ClaimTx{
+			BaseTx: &chain.BaseTx{},
+			Space:  "bannedspace",
+		}, priv)
+		gomega.Ω(err).ShouldNot(gomega.BeNil())
+		gomega.Ω(err.Error()).Should(gomega.ContainSubstring(vm.ErrSpaceDenied.Error()))
+
+		_, _, _, err = i.Client.Resolve(context.Background(), "bannedspace/key")
+		gomega.Ω(err).ShouldNot(gomega.BeNil())
+		gomega.Ω(err.Error()).Should(gomega.ContainSubstring(vm.ErrSpaceDenied.Error()))
+
+		_, err = i.IssueRawTx(context.Background(), &chain.ClaimTx{
+			BaseTx: &chain.BaseTx{},
+			Space:  "allowedspace",
+		}, priv)
+		gomega.Ω(err).Should(gomega.BeNil())
+	})
+})
+
+var _ = ginkgo.Describe("[BuilderReward]", func() {
+	ginkgo.It("credits the beneficiary with a share of block fees", func() {
+		beneficiary := ecommon.Address{0x42}
+		rewarding, err := vmtest.New(
+			genesis, 1,
+			vmtest.WithAirdropData(airdropData),
+			vmtest.WithConfig([]byte(fmt.Sprintf(`{"beneficiary":"%s"}`, beneficiary))),
+			vmtest.WithRequestTimeout(requestTimeout),
+		)
+		gomega.Ω(err).Should(gomega.BeNil())
+		defer func() {
+			gomega.Ω(rewarding.Shutdown()).Should(gomega.BeNil())
+		}()
+
+		i := rewarding.Instances[0]
+		utx := &chain.TransferTx{
+			BaseTx: &chain.BaseTx{},
+			To:     sender2,
+			Units:  1,
+		}
+		_, err = i.IssueRawTx(context.Background(), utx, priv)
+		gomega.Ω(err).Should(gomega.BeNil())
+		blk, err := i.BuildAndAccept()
+		gomega.Ω(err).Should(gomega.BeNil())
+
+		sblk, ok := blk.(*chain.StatelessBlock)
+		gomega.Ω(ok).Should(gomega.BeTrue())
+		gomega.Ω(sblk.Beneficiary).Should(gomega.Equal(beneficiary))
+
+		fee := utx.GetPrice() * utx.FeeUnits(genesis)
+		bal, err := i.Client.Balance(context.Background(), beneficiary)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(bal).Should(gomega.Equal(fee * genesis.BlockBeneficiaryShare / chain.BeneficiaryDivisor))
+	})
+})
+
+var _ = ginkgo.Describe("[Export]", func() {
+	ginkgo.It("replays exported blocks into a fresh node", func() {
+		source, err := vmtest.New(
+			genesis, 1,
+			vmtest.WithAirdropData(airdropData),
+			vmtest.WithRequestTimeout(requestTimeout),
+		)
+		gomega.Ω(err).Should(gomega.BeNil())
+		defer func() {
+			gomega.Ω(source.Shutdown()).Should(gomega.BeNil())
+		}()
+
+		i := source.Instances[0]
+		for u := uint64(1); u <= 3; u++ {
+			_, err = i.IssueRawTx(context.Background(), &chain.TransferTx{
+				BaseTx: &chain.BaseTx{},
+				To:     sender2,
+				Units:  u,
+			}, priv)
+			gomega.Ω(err).Should(gomega.BeNil())
+			_, err = i.BuildAndAccept()
+			gomega.Ω(err).Should(gomega.BeNil())
+		}
+		lastAccepted, err := i.VM.LastAccepted()
+		gomega.Ω(err).Should(gomega.BeNil())
+		bal, err := i.Client.Balance(context.Background(), sender)
+		gomega.Ω(err).Should(gomega.BeNil())
+
+		for _, format := range []string{vm.ExportFormatRaw, vm.ExportFormatJSONL} {
+			dir := filepath.Join(ginkgo.GinkgoT().TempDir(), format)
+			meta, err := i.VM.Export(dir, format, false)
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(meta.Blocks).Should(gomega.Equal(3))
+			gomega.Ω(meta.LastAccepted).Should(gomega.Equal(lastAccepted))
+
+			replayed, err := vmtest.New(
+				genesis, 1,
+				vmtest.WithAirdropData(airdropData),
+				vmtest.WithConfig([]byte(fmt.Sprintf(`{"importDir":%q}`, dir))),
+				vmtest.WithRequestTimeout(requestTimeout),
+				vmtest.WithChainID(source.ChainID),
+			)
+			gomega.Ω(err).Should(gomega.BeNil())
+			r := replayed.Instances[0]
+			rLastAccepted, err := r.VM.LastAccepted()
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(rLastAccepted).Should(gomega.Equal(lastAccepted))
+			rBal, err := r.Client.Balance(context.Background(), sender)
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(rBal).Should(gomega.Equal(bal))
+			gomega.Ω(replayed.Shutdown()).Should(gomega.BeNil())
+		}
+	})
+})
+
+var _ = ginkgo.Describe("[Reindex]", func() {
+	ginkgo.It("rebuilds the indexes of accepted blocks", func() {
+		reindexed, err := vmtest.New(
+			genesis, 1,
+			vmtest.WithAirdropData(airdropData),
+			vmtest.WithRequestTimeout(requestTimeout),
+		)
+		gomega.Ω(err).Should(gomega.BeNil())
+		defer func() {
+			gomega.Ω(reindexed.Shutdown()).Should(gomega.BeNil())
+		}()
+
+		i := reindexed.Instances[0]
+		txIDs := []ids.ID{}
+		for u := uint64(1); u <= 2; u++ {
+			txID, err := i.IssueRawTx(context.Background(), &chain.TransferTx{
+				BaseTx: &chain.BaseTx{},
+				To:     sender2,
+				Units:  u,
+			}, priv)
+			gomega.Ω(err).Should(gomega.BeNil())
+			txIDs = append(txIDs, txID)
+			_, err = i.BuildAndAccept()
+			gomega.Ω(err).Should(gomega.BeNil())
+		}
+
+		stats, err := i.VM.Reindex()
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(stats.Blocks).Should(gomega.Equal(2))
+		gomega.Ω(stats.Txs).Should(gomega.Equal(2))
+		// Each transfer has a tx index, sender history, and indexed block entry
+		gomega.Ω(stats.Cleared).Should(gomega.Equal(6))
+		gomega.Ω(stats.Repairs).Should(gomega.Equal(0))
+
+		for _, txID := range txIDs {
+			has, err := i.Client.HasTx(context.Background(), txID)
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(has).Should(gomega.BeTrue())
+		}
+		activity, _, err := i.Client.SenderHistory(context.Background(), sender, "")
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(activity).Should(gomega.HaveLen(2))
+	})
+})
+
+var _ = ginkgo.Describe("[ResolveMany]", func() {
+	ginkgo.It("resolves every path from the same block", func() {
+		key, err := crypto.GenerateKey()
+		gomega.Ω(err).Should(gomega.BeNil())
+		signing, err := vmtest.New(
+			genesis, 1,
+			vmtest.WithAirdropData(airdropData),
+			vmtest.WithConfig([]byte(fmt.Sprintf(`{"responseKey":"%x"}`, crypto.FromECDSA(key)))),
+			vmtest.WithRequestTimeout(requestTimeout),
+		)
+		gomega.Ω(err).Should(gomega.BeNil())
+		defer func() {
+			gomega.Ω(signing.Shutdown()).Should(gomega.BeNil())
+		}()
+
+		i := signing.Instances[0]
+		for _, utx := range []chain.UnsignedTransaction{
+			&chain.ClaimTx{BaseTx: &chain.BaseTx{}, Space: "manyspace"},
+			&chain.SetTx{BaseTx: &chain.BaseTx{}, Space: "manyspace", Key: "a", Value: []byte("1")},
+			&chain.SetTx{BaseTx: &chain.BaseTx{}, Space: "manyspace", Key: "b", Value: []byte("2")},
+		} {
+			_, err = i.IssueRawTx(context.Background(), utx, priv)
+			gomega.Ω(err).Should(gomega.BeNil())
+			_, err = i.BuildAndAccept()
+			gomega.Ω(err).Should(gomega.BeNil())
+		}
+
+		signed := client.New(i.HTTPServer.URL, requestTimeout, client.WithResponseSigner(crypto.PubkeyToAddress(key.PublicKey)))
+		resp, err := signed.ResolveMany(context.Background(), []string{"manyspace/a", "manyspace/missing", "manyspace/b"})
+		gomega.Ω(err).Should(gomega.BeNil())
+		lastAccepted, err := i.VM.LastAccepted()
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(resp.BlockID).Should(gomega.Equal(lastAccepted))
+		gomega.Ω(resp.Height).Should(gomega.Equal(uint64(3)))
+		gomega.Ω(resp.Values).Should(gomega.HaveLen(3))
+		gomega.Ω(resp.Values[0].Value).Should(gomega.Equal([]byte("1")))
+		gomega.Ω(resp.Values[1].Exists).Should(gomega.BeFalse())
+		gomega.Ω(resp.Values[2].Value).Should(gomega.Equal([]byte("2")))
+		for _, v := range resp.Values {
+			gomega.Ω(v.Attestation.BlockID).Should(gomega.Equal(resp.BlockID))
+		}
+
+		// Invalid paths fail the whole request
+		_, err = signed.ResolveMany(context.Background(), []string{"manyspace/a", "not a path"})
+		gomega.Ω(err).ShouldNot(gomega.BeNil())
+	})
+})
+
+var _ = ginkgo.Describe("[ChangesSince]", func() {
+	ginkgo.It("returns the keys changed since a block", func() {
+		network, err := vmtest.New(
+			genesis, 1,
+			vmtest.WithAirdropData(airdropData),
+			vmtest.WithRequestTimeout(requestTimeout),
+		)
+		gomega.Ω(err).Should(gomega.BeNil())
+		defer func() {
+			gomega.Ω(network.Shutdown()).Should(gomega.BeNil())
+		}()
+
+		i := network.Instances[0]
+		issue := func(utx chain.UnsignedTransaction) ids.ID {
+			_, err := i.IssueRawTx(context.Background(), utx, priv)
+			gomega.Ω(err).Should(gomega.BeNil())
+			blk, err := i.BuildAndAccept()
+			gomega.Ω(err).Should(gomega.BeNil())
+			return blk.ID()
+		}
+		claimed := issue(&chain.ClaimTx{BaseTx: &chain.BaseTx{}, Space: "changespace"})
+		since := issue(&chain.SetTx{BaseTx: &chain.BaseTx{}, Space: "changespace", Key: "a", Value: []byte("1")})
+		issue(&chain.SetTx{BaseTx: &chain.BaseTx{}, Space: "changespace", Key: "b", Value: []byte("2")})
+		last := issue(&chain.DeleteTx{BaseTx: &chain.BaseTx{}, Space: "changespace", Key: "a"})
+
+		resp, err := i.Client.ChangesSince(context.Background(), "changespace", since)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(resp.BlockID).Should(gomega.Equal(last))
+		gomega.Ω(resp.More).Should(gomega.BeFalse())
+		gomega.Ω(resp.Reset).Should(gomega.BeFalse())
+		gomega.Ω(resp.Changes).Should(gomega.HaveLen(2))
+		gomega.Ω(resp.Changes[0].Key).Should(gomega.Equal("a"))
+		gomega.Ω(resp.Changes[0].Deleted).Should(gomega.BeTrue())
+		gomega.Ω(resp.Changes[1].Key).Should(gomega.Equal("b"))
+		gomega.Ω(resp.Changes[1].Value).Should(gomega.Equal([]byte("2")))
+
+		resp, err = i.Client.ChangesSince(context.Background(), "changespace/b", claimed)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(resp.Changes).Should(gomega.HaveLen(1))
+		gomega.Ω(resp.Changes[0].Key).Should(gomega.Equal("b"))
+
+		// Nothing changed since the last block
+		resp, err = i.Client.ChangesSince(context.Background(), "changespace", last)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(resp.Changes).Should(gomega.BeEmpty())
+		gomega.Ω(resp.BlockID).Should(gomega.Equal(last))
+	})
+})
+
+var _ = ginkgo.Describe("[FindByHash]", func() {
+	ginkgo.It("finds the keys storing a value", func() {
+		network, err := vmtest.New(
+			genesis, 1,
+			vmtest.WithAirdropData(airdropData),
+			vmtest.WithRequestTimeout(requestTimeout),
+		)
+		gomega.Ω(err).Should(gomega.BeNil())
+		defer func() {
+			gomega.Ω(network.Shutdown()).Should(gomega.BeNil())
+		}()
+
+		i := network.Instances[0]
+		for _, utx := range []chain.UnsignedTransaction{
+			&chain.ClaimTx{BaseTx: &chain.BaseTx{}, Space: "hashspace"},
+			&chain.SetTx{BaseTx: &chain.BaseTx{}, Space: "hashspace", Key: "a", Value: []byte("dup")},
+			&chain.SetTx{BaseTx: &chain.BaseTx{}, Space: "hashspace", Key: "b", Value: []byte("dup")},
+			&chain.SetTx{BaseTx: &chain.BaseTx{}, Space: "hashspace", Key: "c", Value: []byte("other")},
+			&chain.DeleteTx{BaseTx: &chain.BaseTx{}, Space: "hashspace", Key: "b"},
+		} {
+			_, err = i.IssueRawTx(context.Background(), utx, priv)
+			gomega.Ω(err).Should(gomega.BeNil())
+			_, err = i.BuildAndAccept()
+			gomega.Ω(err).Should(gomega.BeNil())
+		}
+
+		paths, next, err := i.Client.FindByHash(context.Background(), chain.ValueHash([]byte("dup")), "")
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(paths).Should(gomega.Equal([]string{"hashspace/a"}))
+		gomega.Ω(next).Should(gomega.BeEmpty())
+
+		paths, _, err = i.Client.FindByHash(context.Background(), chain.ValueHash([]byte("missing")), "")
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(paths).Should(gomega.BeEmpty())
+	})
+})
+
+var _ = ginkgo.Describe("[ClaimKeys]", func() {
+	ginkgo.It("sets keys atomically with a claim", func() {
+		network, err := vmtest.New(
+			genesis, 1,
+			vmtest.WithAirdropData(airdropData),
+			vmtest.WithRequestTimeout(requestTimeout),
+		)
+		gomega.Ω(err).Should(gomega.BeNil())
+		defer func() {
+			gomega.Ω(network.Shutdown()).Should(gomega.BeNil())
+		}()
+
+		i := network.Instances[0]
+		claim := &chain.ClaimTx{
+			BaseTx: &chain.BaseTx{},
+			Space:  "template",
+			Keys: []*chain.CustomKey{
+				{Key: "name", Value: []byte("hello")},
+				{Key: "version", Value: []byte("1")},
+			},
+		}
+		txID, err := i.IssueRawTx(context.Background(), claim, priv)
+		gomega.Ω(err).Should(gomega.BeNil())
+		_, err = i.BuildAndAccept()
+		gomega.Ω(err).Should(gomega.BeNil())
+
+		for idx, ck := range claim.Keys {
+			exists, value, vmeta, err := i.Client.Resolve(context.Background(), "template/"+ck.Key)
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(exists).Should(gomega.BeTrue())
+			gomega.Ω(value).Should(gomega.Equal(ck.Value))
+			gomega.Ω(vmeta.TxID).Should(gomega.Equal(chain.ClaimValueID(txID, idx)))
+		}
+		paths, _, err := i.Client.FindByHash(context.Background(), chain.ValueHash([]byte("hello")), "")
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(paths).Should(gomega.Equal([]string{"template/name"}))
+
+		// Invalid keys fail the whole claim
+		_, err = i.IssueRawTx(context.Background(), &chain.ClaimTx{
+			BaseTx: &chain.BaseTx{},
+			Space:  "badtemplate",
+			Keys: []*chain.CustomKey{
+				{Key: "a", Value: []byte("1")},
+				{Key: "a", Value: []byte("2")},
+			},
+		}, priv)
+		gomega.Ω(err).ShouldNot(gomega.BeNil())
+		exists, _, _, err := i.Client.Resolve(context.Background(), "badtemplate/a")
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(exists).Should(gomega.BeFalse())
+	})
+})
+
+var _ = ginkgo.Describe("[Attestation]", func() {
+	ginkgo.It("signs resolve responses with the response key", func() {
+		key, err := crypto.GenerateKey()
+		gomega.Ω(err).Should(gomega.BeNil())
+		signer := crypto.PubkeyToAddress(key.PublicKey)
+		signing, err := vmtest.New(
+			genesis, 1,
+			vmtest.WithAirdropData(airdropData),
+			vmtest.WithConfig([]byte(fmt.Sprintf(`{"responseKey":"%x"}`, crypto.FromECDSA(key)))),
+			vmtest.WithRequestTimeout(requestTimeout),
+		)
+		gomega.Ω(err).Should(gomega.BeNil())
+		defer func() {
+			gomega.Ω(signing.Shutdown()).Should(gomega.BeNil())
+		}()
+
+		i := signing.Instances[0]
+		resp, err := i.Client.ResolveWithAttestation(context.Background(), "unclaimed/key")
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(resp.Exists).Should(gomega.BeFalse())
+		gomega.Ω(resp.Attestation).ShouldNot(gomega.BeNil())
+		gomega.Ω(client.VerifyResolve(resp, "unclaimed/key", signer)).Should(gomega.BeNil())
+
+		// A relay claiming the path holds a value is detected
+		resp.Exists, resp.Value = true, []byte("tampered")
+		gomega.Ω(client.VerifyResolve(resp, "unclaimed/key", signer)).Should(gomega.MatchError(gomega.ContainSubstring(vm.ErrInvalidAttestation.Error())))
+
+		// Responses from nodes without a response key are unsigned
+		resp, err = instances[0].Client.ResolveWithAttestation(context.Background(), "unclaimed/key")
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(client.VerifyResolve(resp, "unclaimed/key", signer)).Should(gomega.MatchError(client.ErrUnsignedResponse))
+	})
+
+	ginkgo.It("omits unchanged values from resolve responses", func() {
+		key, err := crypto.GenerateKey()
+		gomega.Ω(err).Should(gomega.BeNil())
+		signing, err := vmtest.New(
+			genesis, 1,
+			vmtest.WithAirdropData(airdropData),
+			vmtest.WithConfig([]byte(fmt.Sprintf(`{"responseKey":"%x"}`, crypto.FromECDSA(key)))),
+			vmtest.WithRequestTimeout(requestTimeout),
+		)
+		gomega.Ω(err).Should(gomega.BeNil())
+		defer func() {
+			gomega.Ω(signing.Shutdown()).Should(gomega.BeNil())
+		}()
+
+		i := signing.Instances[0]
+		for _, utx := range []chain.UnsignedTransaction{
+			&chain.ClaimTx{BaseTx: &chain.BaseTx{}, Space: "etagspace"},
+			&chain.SetTx{BaseTx: &chain.BaseTx{}, Space: "etagspace", Key: "key", Value: []byte("value")},
+		} {
+			_, err = i.IssueRawTx(context.Background(), utx, priv)
+			gomega.Ω(err).Should(gomega.BeNil())
+			_, err = i.BuildAndAccept()
+			gomega.Ω(err).Should(gomega.BeNil())
+		}
+
+		signed := client.New(i.HTTPServer.URL, requestTimeout, client.WithResponseSigner(crypto.PubkeyToAddress(key.PublicKey)))
+		resp, err := signed.ResolveWithAttestation(context.Background(), "etagspace/key")
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(resp.ETag).Should(gomega.Equal(fmt.Sprintf("%q", crypto.Keccak256Hash([]byte("value")).Hex())))
+
+		// The attestation of a not modified response is still verified
+		cached, err := signed.ResolveIfNoneMatch(context.Background(), "etagspace/key", resp.ETag)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(cached.NotModified).Should(gomega.BeTrue())
+		gomega.Ω(cached.Value).Should(gomega.BeEmpty())
+		gomega.Ω(cached.ValueMeta.TxID).Should(gomega.Equal(resp.ValueMeta.TxID))
+
+		stale, err := signed.ResolveIfNoneMatch(context.Background(), "etagspace/key", `"stale"`)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(stale.NotModified).Should(gomega.BeFalse())
+		gomega.Ω(stale.Value).Should(gomega.Equal([]byte("value")))
+	})
+})
+
+var _ = ginkgo.Describe("[PreferredReads]", func() {
+	ginkgo.It("serves verified but unaccepted writes when requested", func() {
+		network, err := vmtest.New(
+			genesis, 1,
+			vmtest.WithAirdropData(airdropData),
+			vmtest.WithRequestTimeout(requestTimeout),
+		)
+		gomega.Ω(err).Should(gomega.BeNil())
+		defer func() {
+			gomega.Ω(network.Shutdown()).Should(gomega.BeNil())
+		}()
+
+		i := network.Instances[0]
+		_, err = i.IssueRawTx(context.Background(), &chain.ClaimTx{
+			BaseTx: &chain.BaseTx{},
+			Space:  "preferredspace",
+		}, priv)
+		gomega.Ω(err).Should(gomega.BeNil())
+		blk, err := i.BuildAndPrefer()
+		gomega.Ω(err).Should(gomega.BeNil())
+
+		preferred := client.New(i.HTTPServer.URL, requestTimeout, client.WithPreferredReads())
+		claimed, err := i.Client.Claimed(context.Background(), "preferredspace")
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(claimed).Should(gomega.BeFalse())
+		claimed, err = preferred.Claimed(context.Background(), "preferredspace")
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(claimed).Should(gomega.BeTrue())
+		gomega.Ω(blk.Accept()).Should(gomega.BeNil())
+
+		_, err = i.IssueRawTx(context.Background(), &chain.SetTx{
+			BaseTx: &chain.BaseTx{},
+			Space:  "preferredspace",
+			Key:    "key",
+			Value:  []byte("value"),
+		}, priv)
+		gomega.Ω(err).Should(gomega.BeNil())
+		blk, err = i.BuildAndPrefer()
+		gomega.Ω(err).Should(gomega.BeNil())
+
+		exists, _, _, err := i.Client.Resolve(context.Background(), "preferredspace/key")
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(exists).Should(gomega.BeFalse())
+		exists, value, _, err := preferred.Resolve(context.Background(), "preferredspace/key")
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(exists).Should(gomega.BeTrue())
+		gomega.Ω(value).Should(gomega.Equal([]byte("value")))
+
+		// Once accepted, both consistencies agree
+		gomega.Ω(blk.Accept()).Should(gomega.BeNil())
+		exists, value, _, err = i.Client.Resolve(context.Background(), "preferredspace/key")
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(exists).Should(gomega.BeTrue())
+		gomega.Ω(value).Should(gomega.Equal([]byte("value")))
+	})
+})
+
+var _ = ginkgo.Describe("[PendingReads]", func() {
+	ginkgo.It("overlays the mempool when requested", func() {
+		network, err := vmtest.New(
+			genesis, 1,
+			vmtest.WithAirdropData(airdropData),
+			vmtest.WithRequestTimeout(requestTimeout),
+		)
+		gomega.Ω(err).Should(gomega.BeNil())
+		defer func() {
+			gomega.Ω(network.Shutdown()).Should(gomega.BeNil())
+		}()
+
+		i := network.Instances[0]
+		_, err = i.IssueRawTx(context.Background(), &chain.ClaimTx{
+			BaseTx: &chain.BaseTx{},
+			Space:  "pendingspace",
+		}, priv)
+		gomega.Ω(err).Should(gomega.BeNil())
+		_, err = i.BuildAndAccept()
+		gomega.Ω(err).Should(gomega.BeNil())
+
+		_, err = i.IssueRawTx(context.Background(), &chain.SetTx{
+			BaseTx: &chain.BaseTx{},
+			Space:  "pendingspace",
+			Key:    "key",
+			Value:  []byte("value"),
+		}, priv)
+		gomega.Ω(err).Should(gomega.BeNil())
+
+		pending := client.New(i.HTTPServer.URL, requestTimeout, client.WithPendingReads())
+		exists, _, _, err := i.Client.Resolve(context.Background(), "pendingspace/key")
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(exists).Should(gomega.BeFalse())
+		exists, value, _, err := pending.Resolve(context.Background(), "pendingspace/key")
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(exists).Should(gomega.BeTrue())
+		gomega.Ω(value).Should(gomega.Equal([]byte("value")))
+
+		// The overlay is never committed
+		exists, _, _, err = i.Client.Resolve(context.Background(), "pendingspace/key")
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(exists).Should(gomega.BeFalse())
+
+		_, err = i.BuildAndAccept()
+		gomega.Ω(err).Should(gomega.BeNil())
+		exists, value, _, err = i.Client.Resolve(context.Background(), "pendingspace/key")
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(exists).Should(gomega.BeTrue())
+		gomega.Ω(value).Should(gomega.Equal([]byte("value")))
+	})
+})
+
+var _ = ginkgo.Describe("[LightClient]", func() {
+	ginkgo.It("follows verified headers and reads attested values", func() {
+		key, err := crypto.GenerateKey()
+		gomega.Ω(err).Should(gomega.BeNil())
+		signing, err := vmtest.New(
+			genesis, 1,
+			vmtest.WithAirdropData(airdropData),
+			vmtest.WithConfig([]byte(fmt.Sprintf(`{"responseKey":"%x"}`, crypto.FromECDSA(key)))),
+			vmtest.WithRequestTimeout(requestTimeout),
+		)
+		gomega.Ω(err).Should(gomega.BeNil())
+		defer func() {
+			gomega.Ω(signing.Shutdown()).Should(gomega.BeNil())
+		}()
+
+		i := signing.Instances[0]
+		genesisBlk, err := i.Client.BlockAt(context.Background(), 0)
+		gomega.Ω(err).Should(gomega.BeNil())
+		lc, err := light.New(
+			context.Background(), i.Client, genesisBlk.BlockID,
+			light.WithResponseSigner(crypto.PubkeyToAddress(key.PublicKey)),
+		)
+		gomega.Ω(err).Should(gomega.BeNil())
+
+		_, err = i.IssueRawTx(context.Background(), &chain.ClaimTx{
+			BaseTx: &chain.BaseTx{},
+			Space:  "lightspace",
+		}, priv)
+		gomega.Ω(err).Should(gomega.BeNil())
+		blk, err := i.BuildAndAccept()
+		gomega.Ω(err).Should(gomega.BeNil())
+
+		headers, err := lc.Sync(context.Background())
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(headers).Should(gomega.HaveLen(1))
+		gomega.Ω(headers[0].ID).Should(gomega.Equal(blk.ID()))
+
+		resp, err := lc.Resolve(context.Background(), "lightspace/key")
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(resp.Exists).Should(gomega.BeFalse())
+		gomega.Ω(resp.Attestation.BlockID).Should(gomega.Equal(blk.ID()))
+	})
+})
+
+var _ = ginkgo.Describe("[Atomic]", func() {
+	ginkgo.It("exports to and imports from a peer chain", func() {
+		g := *genesis
+		g.AtomicAssetID = ids.GenerateTestID()
+		atomicNet, err := vmtest.New(
+			&g, 1,
+			vmtest.WithAirdropData(airdropData),
+			vmtest.WithRequestTimeout(requestTimeout),
+		)
+		gomega.Ω(err).Should(gomega.BeNil())
+		defer func() {
+			gomega.Ω(atomicNet.Shutdown()).Should(gomega.BeNil())
+		}()
+
+		i := atomicNet.Instances[0]
+		peerChain := ids.GenerateTestID()
+		peer := i.Memory.NewSharedMemory(peerChain)
+		owner := chain.ShortAddress(&priv.PublicKey)
+
+		// Export units to the peer chain
+		export := &chain.ExportTx{
+			BaseTx:           &chain.BaseTx{},
+			DestinationChain: peerChain,
+			To:               owner,
+			Units:            100,
+		}
+		txID, err := i.IssueRawTx(context.Background(), export, priv)
+		gomega.Ω(err).Should(gomega.BeNil())
+		_, err = i.BuildAndAccept()
+		gomega.Ω(err).Should(gomega.BeNil())
+
+		exported := export.UTXO(&g, txID).InputID()
+		values, err := peer.Get(atomicNet.ChainID, [][]byte{exported[:]})
+		gomega.Ω(err).Should(gomega.BeNil())
+		utxo, err := chain.ParseAtomicUTXO(values[0])
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(utxo.AssetID()).Should(gomega.Equal(g.AtomicAssetID))
+
+		// Import a UTXO the peer chain exported
+		utxo = &avax.UTXO{
+			UTXOID: avax.UTXOID{TxID: ids.GenerateTestID()},
+			Asset:  avax.Asset{ID: g.AtomicAssetID},
+			Out: &secp256k1fx.TransferOutput{
+				Amt:          1000,
+				OutputOwners: secp256k1fx.OutputOwners{Threshold: 1, Addrs: []ids.ShortID{owner}},
+			},
+		}
+		b, err := chain.MarshalAtomicUTXO(utxo)
+		gomega.Ω(err).Should(gomega.BeNil())
+		utxoID := utxo.InputID()
+		gomega.Ω(peer.Apply(map[ids.ID]*atomic.Requests{atomicNet.ChainID: {
+			PutRequests: []*atomic.Element{{Key: utxoID[:], Value: b, Traits: [][]byte{owner.Bytes()}}},
+		}})).Should(gomega.BeNil())
+
+		utxos, err := i.Client.AtomicUTXOs(context.Background(), peerChain, owner)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(utxos).Should(gomega.HaveLen(1))
+		gomega.Ω(utxos[0].UTXOID).Should(gomega.Equal(utxoID))
+		gomega.Ω(utxos[0].Amount).Should(gomega.Equal(uint64(1000)))
+
+		before, err := i.Client.Balance(context.Background(), sender)
+		gomega.Ω(err).Should(gomega.BeNil())
+		imp := &chain.ImportTx{
+			BaseTx:      &chain.BaseTx{},
+			SourceChain: peerChain,
+			UTXOID:      utxoID,
+		}
+		_, err = i.IssueRawTx(context.Background(), imp, priv)
+		gomega.Ω(err).Should(gomega.BeNil())
+		_, err = i.BuildAndAccept()
+		gomega.Ω(err).Should(gomega.BeNil())
+
+		after, err := i.Client.Balance(context.Background(), sender)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(after).Should(gomega.Equal(before + 1000 - imp.GetPrice()*imp.FeeUnits(&g)))
+
+		utxos, err = i.Client.AtomicUTXOs(context.Background(), peerChain, owner)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(utxos).Should(gomega.BeEmpty())
+	})
+})
+
+var _ = ginkgo.Describe("[Warp]", func() {
+	ginkgo.It("emits signed warp messages for broadcast sets", func() {
+		key, err := crypto.GenerateKey()
+		gomega.Ω(err).Should(gomega.BeNil())
+		signer := crypto.PubkeyToAddress(key.PublicKey)
+		signing, err := vmtest.New(
+			genesis, 1,
+			vmtest.WithAirdropData(airdropData),
+			vmtest.WithConfig([]byte(fmt.Sprintf(`{"responseKey":"%x"}`, crypto.FromECDSA(key)))),
+			vmtest.WithRequestTimeout(requestTimeout),
+		)
+		gomega.Ω(err).Should(gomega.BeNil())
+		defer func() {
+			gomega.Ω(signing.Shutdown()).Should(gomega.BeNil())
+		}()
+
+		i := signing.Instances[0]
+		_, err = i.IssueRawTx(context.Background(), &chain.ClaimTx{
+			BaseTx: &chain.BaseTx{},
+			Space:  "warpspace",
+		}, priv)
+		gomega.Ω(err).Should(gomega.BeNil())
+		_, err = i.BuildAndAccept()
+		gomega.Ω(err).Should(gomega.BeNil())
+
+		set := func(key string, broadcast bool) ids.ID {
+			txID, err := i.IssueRawTx(context.Background(), &chain.SetTx{
+				BaseTx:    &chain.BaseTx{},
+				Space:     "warpspace",
+				Key:       key,
+				Value:     []byte("value"),
+				Broadcast: broadcast,
+			}, priv)
+			gomega.Ω(err).Should(gomega.BeNil())
+			_, err = i.BuildAndAccept()
+			gomega.Ω(err).Should(gomega.BeNil())
+			return txID
+		}
+
+		txID := set("broadcast", true)
+		resp, err := i.Client.WarpMessage(context.Background(), txID)
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(resp.Message.SourceChainID).Should(gomega.Equal(signing.ChainID))
+		gomega.Ω(resp.Message.Sender).Should(gomega.Equal(sender))
+		gomega.Ω(resp.Message.Space).Should(gomega.Equal("warpspace"))
+		gomega.Ω(resp.Message.Key).Should(gomega.Equal("broadcast"))
+		gomega.Ω(resp.Message.ValueHash).Should(gomega.Equal(crypto.Keccak256Hash([]byte("value"))))
+		gomega.Ω(client.VerifyWarpMessage(resp, signer)).Should(gomega.BeNil())
+
+		// Sets that are not broadcast do not emit messages
+		txID = set("quiet", false)
+		_, err = i.Client.WarpMessage(context.Background(), txID)
+		gomega.Ω(err).Should(gomega.MatchError(gomega.ContainSubstring(vm.ErrWarpMessageNotFound.Error())))
+	})
+})
+
+var _ = ginkgo.Describe("[Heartbeat]", func() {
+	ginkgo.It("only builds empty blocks once the chain is idle", func() {
+		g := *genesis
+		g.HeartbeatInterval = 10
+		heartbeats, err := vmtest.New(
+			&g, 1,
+			vmtest.WithAirdropData(airdropData),
+			vmtest.WithRequestTimeout(requestTimeout),
+		)
+		gomega.Ω(err).Should(gomega.BeNil())
+		defer func() {
+			gomega.Ω(heartbeats.Shutdown()).Should(gomega.BeNil())
+		}()
+
+		i := heartbeats.Instances[0]
+		blk, err := i.BuildAndAccept()
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(blk.(*chain.StatelessBlock).Txs).Should(gomega.BeEmpty())
+
+		_, err = i.BuildAndAccept()
+		gomega.Ω(err).Should(gomega.MatchError(gomega.ContainSubstring(chain.ErrNoTxs.Error())))
+
+		heartbeats.AdvanceTime(time.Duration(g.HeartbeatInterval) * time.Second)
+		next, err := i.BuildAndAccept()
+		gomega.Ω(err).Should(gomega.BeNil())
+		gomega.Ω(next.Height()).Should(gomega.Equal(blk.Height() + 1))
+	})
+})
+
 var letterRunes = []rune("abcdefghijklmnopqrstuvwxyz")
 
 func RandStringRunes(n int) string {
@@ -248,14 +986,14 @@ func RandStringRunes(n int) string {
 
 var _ = ginkgo.Describe("Tx Types", func() {
 	ginkgo.It("ensure activity yet", func() {
-		activity, err := instances[0].cli.RecentActivity(context.Background())
+		activity, err := instances[0].Client.RecentActivity(context.Background())
 		gomega.Ω(err).To(gomega.BeNil())
 
 		gomega.Ω(len(activity)).To(gomega.Equal(0))
 	})
 
 	ginkgo.It("ensure nothing owned yet", func() {
-		spaces, err := instances[0].cli.Owned(context.Background(), sender)
+		spaces, err := instances[0].Client.Owned(context.Background(), sender)
 		gomega.Ω(err).To(gomega.BeNil())
 
 		gomega.Ω(len(spaces)).To(gomega.Equal(0))
@@ -263,7 +1001,7 @@ var _ = ginkgo.Describe("Tx Types", func() {
 
 	ginkgo.It("get currently accepted block ID", func() {
 		for _, inst := range instances {
-			cli := inst.cli
+			cli := inst.Client
 			_, err := cli.Accepted(context.Background())
 			gomega.Ω(err).Should(gomega.BeNil())
 		}
@@ -278,61 +1016,70 @@ var _ = ginkgo.Describe("Tx Types", func() {
 
 		ginkgo.By("mine and issue ClaimTx", func() {
 			ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
-			_, _, err := client.SignIssueRawTx(ctx, instances[0].cli, claimTx, priv)
+			_, _, err := client.SignIssueRawTx(ctx, instances[0].Client, claimTx, priv)
 			cancel()
 			gomega.Ω(err).Should(gomega.BeNil())
 		})
 
 		ginkgo.By("send gossip from node 0 to 1", func() {
-			newTxs := instances[0].vm.Mempool().NewTxs(genesis.TargetBlockSize)
+			newTxs := instances[0].VM.Mempool().NewTxs(genesis.TargetBlockSize)
 			gomega.Ω(len(newTxs)).To(gomega.Equal(1))
 
-			err := instances[0].vm.Network().GossipNewTxs(newTxs)
+			err := instances[0].VM.Network().GossipNewTxs(newTxs)
 			gomega.Ω(err).Should(gomega.BeNil())
 		})
 
 		ginkgo.By("receive gossip in the node 1, and signal block build", func() {
-			instances[1].builder.NotifyBuild()
-			<-instances[1].toEngine
+			instances[1].Builder.NotifyBuild()
+			<-instances[1].ToEngine
 		})
 
 		ginkgo.By("build block in the node 1", func() {
-			blk, err := instances[1].vm.BuildBlock()
+			blk, err := instances[1].VM.BuildBlock()
 			gomega.Ω(err).To(gomega.BeNil())
 
 			gomega.Ω(blk.Verify()).To(gomega.BeNil())
 			gomega.Ω(blk.Status()).To(gomega.Equal(choices.Processing))
 
-			err = instances[1].vm.SetPreference(blk.ID())
+			err = instances[1].VM.SetPreference(blk.ID())
 			gomega.Ω(err).To(gomega.BeNil())
 
 			gomega.Ω(blk.Accept()).To(gomega.BeNil())
 			gomega.Ω(blk.Status()).To(gomega.Equal(choices.Accepted))
 
-			lastAccepted, err := instances[1].vm.LastAccepted()
+			lastAccepted, err := instances[1].VM.LastAccepted()
 			gomega.Ω(err).To(gomega.BeNil())
 			gomega.Ω(lastAccepted).To(gomega.Equal(blk.ID()))
 		})
 
 		ginkgo.By("ensure something owned", func() {
-			spaces, err := instances[1].cli.Owned(context.Background(), sender)
+			spaces, err := instances[1].Client.Owned(context.Background(), sender)
 			gomega.Ω(err).To(gomega.BeNil())
 
 			gomega.Ω(spaces).To(gomega.Equal([]string{space}))
 		})
 
 		ginkgo.By("extend time with lifeline", func() {
+			estimate, err := instances[1].Client.EstimateLifeline(context.Background(), space, 1, 0)
+			gomega.Ω(err).To(gomega.BeNil())
+			gomega.Ω(estimate.Extension).To(gomega.BeNumerically(">", 0))
+
 			lifelineTx := &chain.LifelineTx{
 				BaseTx: &chain.BaseTx{},
 				Space:  space,
 				Units:  1,
 			}
+			gomega.Ω(estimate.FeeUnits).To(gomega.Equal(lifelineTx.FeeUnits(genesis)))
 			createIssueRawTx(instances[1], lifelineTx, priv)
 			expectBlkAccept(instances[1])
+
+			info, _, err := instances[1].Client.Info(context.Background(), space)
+			gomega.Ω(err).To(gomega.BeNil())
+			gomega.Ω(info.Expiry).To(gomega.Equal(estimate.Expiry))
 		})
 
 		ginkgo.By("ensure all activity accounted for", func() {
-			activity, err := instances[1].cli.RecentActivity(context.Background())
+			activity, err := instances[1].Client.RecentActivity(context.Background())
 			gomega.Ω(err).To(gomega.BeNil())
 
 			gomega.Ω(len(activity)).To(gomega.Equal(2))
@@ -363,7 +1110,7 @@ var _ = ginkgo.Describe("Tx Types", func() {
 		err = tx.Init(genesis)
 		gomega.Ω(err).Should(gomega.BeNil())
 
-		_, err = instances[0].cli.IssueRawTx(context.Background(), tx.Bytes())
+		_, err = instances[0].Client.IssueRawTx(context.Background(), tx.Bytes())
 		gomega.Ω(err.Error()).Should(gomega.ContainSubstring(chain.ErrInvalidBlockID.Error()))
 	})
 
@@ -380,7 +1127,7 @@ var _ = ginkgo.Describe("Tx Types", func() {
 		})
 
 		ginkgo.By("ensure everything owned", func() {
-			spaces, err := instances[0].cli.Owned(context.Background(), sender)
+			spaces, err := instances[0].Client.Owned(context.Background(), sender)
 			gomega.Ω(err).To(gomega.BeNil())
 
 			gomega.Ω(spaces).To(gomega.ContainElements(
@@ -390,7 +1137,7 @@ var _ = ginkgo.Describe("Tx Types", func() {
 		})
 
 		ginkgo.By("check space after ClaimTx has been accepted", func() {
-			pf, values, err := instances[0].cli.Info(context.Background(), space)
+			pf, values, err := instances[0].Client.Info(context.Background(), space)
 			gomega.Ω(err).To(gomega.BeNil())
 			gomega.Ω(pf).NotTo(gomega.BeNil())
 			gomega.Ω(pf.Units).To(gomega.Equal(uint64(100)))
@@ -412,14 +1159,14 @@ var _ = ginkgo.Describe("Tx Types", func() {
 		})
 
 		ginkgo.By("read back from VM with range query", func() {
-			_, kvs, err := instances[0].cli.Info(context.Background(), space)
+			_, kvs, err := instances[0].Client.Info(context.Background(), space)
 			gomega.Ω(err).To(gomega.BeNil())
 			gomega.Ω(kvs[0].Key).To(gomega.Equal(k))
 			gomega.Ω(kvs[0].ValueMeta.Size).To(gomega.Equal(uint64(5)))
 		})
 
 		ginkgo.By("read back from VM with resolve", func() {
-			exists, value, valueMeta, err := instances[0].cli.Resolve(context.Background(), space+"/"+k)
+			exists, value, valueMeta, err := instances[0].Client.Resolve(context.Background(), space+"/"+k)
 			gomega.Ω(err).To(gomega.BeNil())
 			gomega.Ω(exists).To(gomega.BeTrue())
 			gomega.Ω(value).To(gomega.Equal(v))
@@ -447,7 +1194,7 @@ var _ = ginkgo.Describe("Tx Types", func() {
 		})
 
 		ginkgo.By("ensure all activity accounted for", func() {
-			activity, err := instances[0].cli.RecentActivity(context.Background())
+			activity, err := instances[0].Client.RecentActivity(context.Background())
 			gomega.Ω(err).To(gomega.BeNil())
 
 			gomega.Ω(len(activity)).To(gomega.Equal(5))
@@ -472,6 +1219,213 @@ var _ = ginkgo.Describe("Tx Types", func() {
 			gomega.Ω(a3.Sender).To(gomega.Equal(sender.Hex()))
 		})
 
+		ginkgo.By("ensure space history accounted for", func() {
+			history, next, err := instances[0].Client.History(context.Background(), space, "")
+			gomega.Ω(err).To(gomega.BeNil())
+			gomega.Ω(next).To(gomega.BeEmpty())
+
+			gomega.Ω(len(history)).To(gomega.Equal(3))
+			gomega.Ω(history[0].Typ).To(gomega.Equal("claim"))
+			gomega.Ω(history[1].Typ).To(gomega.Equal("set"))
+			gomega.Ω(history[1].Key).To(gomega.Equal(k))
+			gomega.Ω(history[2].Typ).To(gomega.Equal("move"))
+			gomega.Ω(history[2].To).To(gomega.Equal(sender2.Hex()))
+		})
+
+		ginkgo.By("ensure sender history accounted for", func() {
+			history, _, err := instances[0].Client.SenderHistory(context.Background(), sender, "")
+			gomega.Ω(err).To(gomega.BeNil())
+
+			n := len(history)
+			gomega.Ω(n >= 5).To(gomega.BeTrue())
+			for _, a := range history {
+				gomega.Ω(a.Sender).To(gomega.Equal(sender.Hex()))
+			}
+			gomega.Ω(history[n-1].Typ).To(gomega.Equal("move"))
+			gomega.Ω(history[n-2].Typ).To(gomega.Equal("transfer"))
+		})
+
+		ginkgo.By("list keys of the space", func() {
+			keys, next, err := instances[0].Client.ListKeys(context.Background(), space, "")
+			gomega.Ω(err).To(gomega.BeNil())
+			gomega.Ω(next).To(gomega.BeEmpty())
+			gomega.Ω(keys).To(gomega.HaveLen(1))
+			gomega.Ω(keys[0].Key).To(gomega.Equal(k))
+		})
+
+		ginkgo.By("ensure recent blocks summarized", func() {
+			blocks, next, err := instances[0].Client.RecentBlocks(context.Background(), 2, "")
+			gomega.Ω(err).To(gomega.BeNil())
+
+			gomega.Ω(len(blocks)).To(gomega.Equal(2))
+			gomega.Ω(blocks[0].Height).To(gomega.Equal(blocks[1].Height + 1))
+			gomega.Ω(blocks[0].Txs).To(gomega.Equal(1))
+			gomega.Ω(blocks[0].Units > 0).To(gomega.BeTrue())
+
+			// The next page continues with older blocks
+			gomega.Ω(next).NotTo(gomega.BeEmpty())
+			older, _, err := instances[0].Client.RecentBlocks(context.Background(), 1, next)
+			gomega.Ω(err).To(gomega.BeNil())
+			gomega.Ω(older).To(gomega.HaveLen(1))
+			gomega.Ω(older[0].Height).To(gomega.Equal(blocks[1].Height - 1))
+		})
+
+		ginkgo.By("inspect blocks and txs", func() {
+			blocks, _, err := instances[0].Client.RecentBlocks(context.Background(), 1, "")
+			gomega.Ω(err).To(gomega.BeNil())
+
+			blk, err := instances[0].Client.BlockAt(context.Background(), blocks[0].Height)
+			gomega.Ω(err).To(gomega.BeNil())
+			gomega.Ω(blk.BlockID).To(gomega.Equal(blocks[0].BlockID))
+			gomega.Ω(blk.Txs).To(gomega.HaveLen(1))
+			tx := blk.Txs[0]
+			gomega.Ω(tx.Type).To(gomega.Equal("move"))
+			gomega.Ω(tx.Sender).To(gomega.Equal(sender))
+			gomega.Ω(tx.TypedData.Message["space"]).To(gomega.Equal(space))
+
+			byID, err := instances[0].Client.Block(context.Background(), blk.BlockID)
+			gomega.Ω(err).To(gomega.BeNil())
+			gomega.Ω(byID).To(gomega.Equal(blk))
+
+			reply, err := instances[0].Client.Tx(context.Background(), tx.TxID)
+			gomega.Ω(err).To(gomega.BeNil())
+			gomega.Ω(reply.Accepted).To(gomega.BeTrue())
+			gomega.Ω(reply.BlockID).To(gomega.Equal(blk.BlockID))
+			gomega.Ω(reply.Tx).To(gomega.Equal(tx))
+
+			reply, err = instances[0].Client.Tx(context.Background(), ids.GenerateTestID())
+			gomega.Ω(err).To(gomega.BeNil())
+			gomega.Ω(reply.Accepted).To(gomega.BeFalse())
+			gomega.Ω(reply.Pending).To(gomega.BeFalse())
+			gomega.Ω(reply.DropReason).To(gomega.BeEmpty())
+
+			_, err = instances[0].Client.BlockAt(context.Background(), blocks[0].Height+1)
+			gomega.Ω(err).NotTo(gomega.BeNil())
+		})
+
+		ginkgo.By("follow block headers", func() {
+			blocks, _, err := instances[0].Client.RecentBlocks(context.Background(), 1, "")
+			gomega.Ω(err).To(gomega.BeNil())
+			last := blocks[0]
+
+			header, err := instances[0].Client.Header(context.Background(), last.BlockID)
+			gomega.Ω(err).To(gomega.BeNil())
+			gomega.Ω(header.Height).To(gomega.Equal(last.Height))
+			gomega.Ω(header.Txs).To(gomega.Equal(last.Txs))
+
+			headers, err := instances[0].Client.Headers(context.Background(), last.Height-1, 10)
+			gomega.Ω(err).To(gomega.BeNil())
+			gomega.Ω(headers).To(gomega.HaveLen(2))
+			gomega.Ω(headers[1]).To(gomega.Equal(header))
+			gomega.Ω(headers[1].Parent).To(gomega.Equal(headers[0].BlockID))
+
+			byHeight, err := instances[0].Client.HeaderAt(context.Background(), last.Height-1)
+			gomega.Ω(err).To(gomega.BeNil())
+			gomega.Ω(byHeight).To(gomega.Equal(headers[0]))
+
+			_, err = instances[0].Client.Headers(context.Background(), last.Height+1, 1)
+			gomega.Ω(err).NotTo(gomega.BeNil())
+		})
+
+		ginkgo.By("issue input encoded by the node", func() {
+			input := &chain.Input{Typ: chain.Lifeline, Space: space, Units: 1}
+			encoded, err := instances[0].Client.EncodeTx(context.Background(), input)
+			gomega.Ω(err).To(gomega.BeNil())
+			gomega.Ω(encoded.BlockID).NotTo(gomega.Equal(ids.Empty))
+
+			sig, err := chain.Sign(encoded.Digest, priv)
+			gomega.Ω(err).To(gomega.BeNil())
+			_, err = instances[0].Client.IssueInputTx(
+				context.Background(), input, encoded.BlockID, encoded.Price, sig,
+			)
+			gomega.Ω(err).To(gomega.BeNil())
+			expectBlkAccept(instances[0])
+		})
+
+		ginkgo.By("issue unsigned tx encoded by the node", func() {
+			encoded, err := instances[0].Client.EncodeUnsignedTx(context.Background(), &chain.LifelineTx{
+				BaseTx: &chain.BaseTx{},
+				Space:  space,
+				Units:  1,
+			})
+			gomega.Ω(err).To(gomega.BeNil())
+			gomega.Ω(encoded.Tx.GetBlockID()).To(gomega.Equal(encoded.BlockID))
+			gomega.Ω(encoded.Tx.GetPrice()).To(gomega.Equal(encoded.Price))
+
+			sig, err := chain.Sign(encoded.Digest, priv)
+			gomega.Ω(err).To(gomega.BeNil())
+			tx := chain.NewTx(encoded.Tx.UnsignedTransaction, sig)
+			gomega.Ω(tx.Init(genesis)).To(gomega.BeNil())
+			_, err = instances[0].Client.IssueRawTx(context.Background(), tx.Bytes())
+			gomega.Ω(err).To(gomega.BeNil())
+			expectBlkAccept(instances[0])
+		})
+
+		ginkgo.By("fund lifeline for an exact extension from another sender", func() {
+			extension := uint64(time.Hour.Seconds())
+			estimate, err := instances[0].Client.EstimateLifeline(context.Background(), space, 0, extension)
+			gomega.Ω(err).To(gomega.BeNil())
+			gomega.Ω(estimate.Units).To(gomega.BeNumerically(">", 0))
+			gomega.Ω(estimate.Extension).To(gomega.Equal(extension))
+
+			createIssueTx(instances[0], &chain.Input{
+				Typ:       chain.Lifeline,
+				Space:     space,
+				Units:     estimate.Units,
+				Extension: extension,
+			}, priv2)
+			expectBlkAccept(instances[0])
+
+			info, _, err := instances[0].Client.Info(context.Background(), space)
+			gomega.Ω(err).To(gomega.BeNil())
+			gomega.Ω(info.Expiry).To(gomega.Equal(estimate.Expiry))
+
+			activity, err := instances[0].Client.RecentActivity(context.Background())
+			gomega.Ω(err).To(gomega.BeNil())
+			var funded *chain.Activity
+			for _, a := range activity {
+				if a.Typ == chain.Lifeline {
+					funded = a
+					break
+				}
+			}
+			gomega.Ω(funded).NotTo(gomega.BeNil())
+			gomega.Ω(funded.Sender).To(gomega.Equal(sender2.Hex()))
+			gomega.Ω(funded.Extension).To(gomega.Equal(extension))
+		})
+
+		ginkgo.By("ensure stats account for state", func() {
+			stats, err := instances[0].Client.Stats(context.Background())
+			gomega.Ω(err).To(gomega.BeNil())
+
+			gomega.Ω(stats.Window > 1).To(gomega.BeTrue())
+			gomega.Ω(stats.State.Spaces >= 2).To(gomega.BeTrue())
+			gomega.Ω(stats.State.Keys >= 1).To(gomega.BeTrue())
+			gomega.Ω(stats.State.ValueBytes >= uint64(len(v))).To(gomega.BeTrue())
+		})
+
+		ginkgo.By("return the fee history of recent blocks", func() {
+			history, err := instances[0].Client.FeeHistory(context.Background(), 2)
+			gomega.Ω(err).To(gomega.BeNil())
+
+			gomega.Ω(history.Blocks).To(gomega.HaveLen(2))
+			gomega.Ω(history.Blocks[0].Height + 1).To(gomega.Equal(history.Blocks[1].Height))
+			gomega.Ω(history.Blocks[1].Txs > 0).To(gomega.BeTrue())
+			gomega.Ω(history.Blocks[1].MinTxPrice >= history.Blocks[1].Price).To(gomega.BeTrue())
+			gomega.Ω(history.SuggestedPrice > 0).To(gomega.BeTrue())
+		})
+
+		ginkgo.By("return the accepted frontier", func() {
+			frontier, err := instances[0].Client.Frontier(context.Background())
+			gomega.Ω(err).To(gomega.BeNil())
+
+			la, err := instances[0].Client.Accepted(context.Background())
+			gomega.Ω(err).To(gomega.BeNil())
+			gomega.Ω(frontier.LastAccepted.BlockID).To(gomega.Equal(la))
+			gomega.Ω(frontier.Processing).To(gomega.BeEmpty())
+			gomega.Ω(frontier.Forks).To(gomega.BeZero())
+		})
+
 		ginkgo.By("transfer funds to other sender (simple)", func() {
 			createIssueTx(instances[0], &chain.Input{
 				Typ:   chain.Transfer,
@@ -493,7 +1447,7 @@ var _ = ginkgo.Describe("Tx Types", func() {
 
 	ginkgo.It("Distribute Lottery Reward", func() {
 		ginkgo.By("ensure that sender is rewarded at least once", func() {
-			bal, err := instances[0].cli.Balance(context.Background(), sender)
+			bal, err := instances[0].Client.Balance(context.Background(), sender)
 			gomega.Ω(err).To(gomega.BeNil())
 
 			found := false
@@ -507,7 +1461,7 @@ var _ = ginkgo.Describe("Tx Types", func() {
 				createIssueRawTx(instances[0], claimTx, priv2)
 				expectBlkAccept(instances[0])
 
-				bal2, err := instances[0].cli.Balance(context.Background(), sender)
+				bal2, err := instances[0].Client.Balance(context.Background(), sender)
 				gomega.Ω(err).To(gomega.BeNil())
 
 				if bal2 > bal {
@@ -519,7 +1473,7 @@ var _ = ginkgo.Describe("Tx Types", func() {
 		})
 
 		ginkgo.By("ensure all activity accounted for", func() {
-			activity, err := instances[0].cli.RecentActivity(context.Background())
+			activity, err := instances[0].Client.RecentActivity(context.Background())
 			gomega.Ω(err).To(gomega.BeNil())
 
 			a0 := activity[0]
@@ -538,21 +1492,21 @@ var _ = ginkgo.Describe("Tx Types", func() {
 
 		ginkgo.By("mine and issue ClaimTx", func() {
 			ctx, cancel := context.WithTimeout(context.Background(), requestTimeout)
-			_, _, err := client.SignIssueRawTx(ctx, instances[0].cli, claimTx, priv)
+			_, _, err := client.SignIssueRawTx(ctx, instances[0].Client, claimTx, priv)
 			cancel()
 			gomega.Ω(err).Should(gomega.BeNil())
 		})
 
 		// since the block from previous test spec has not been replicated yet
 		ginkgo.By("send gossip from node 0 to 1 should fail on server-side since 1 doesn't have the block yet", func() {
-			newTxs := instances[0].vm.Mempool().NewTxs(genesis.TargetBlockSize)
+			newTxs := instances[0].VM.Mempool().NewTxs(genesis.TargetBlockSize)
 			gomega.Ω(len(newTxs)).To(gomega.Equal(1))
 
-			err := instances[0].vm.Network().GossipNewTxs(newTxs)
+			err := instances[0].VM.Network().GossipNewTxs(newTxs)
 			gomega.Ω(err).Should(gomega.BeNil())
 
 			// mempool in 1 should be empty, since gossip/submit failed
-			gomega.Ω(instances[1].vm.Mempool().Len()).Should(gomega.Equal(0))
+			gomega.Ω(instances[1].VM.Mempool().Len()).Should(gomega.Equal(0))
 		})
 	})
 
@@ -606,7 +1560,7 @@ var _ = ginkgo.Describe("Tx Types", func() {
 					close(d)
 				}()
 				path, err = tree.Upload(
-					context.Background(), instances[0].cli, priv,
+					context.Background(), instances[0].Client, priv,
 					space, originalFile, int(genesis.MaxValueSize),
 				)
 				gomega.Ω(err).Should(gomega.BeNil())
@@ -619,7 +1573,7 @@ var _ = ginkgo.Describe("Tx Types", func() {
 				newFile, err = ioutil.TempFile("", "computer")
 				gomega.Ω(err).Should(gomega.BeNil())
 
-				err = tree.Download(context.Background(), instances[0].cli, path, newFile)
+				err = tree.Download(context.Background(), instances[0].Client, path, newFile)
 				gomega.Ω(err).Should(gomega.BeNil())
 			})
 
@@ -651,7 +1605,7 @@ var _ = ginkgo.Describe("Tx Types", func() {
 					asyncBlockPush(instances[0], c)
 					close(d)
 				}()
-				err = tree.Delete(context.Background(), instances[0].cli, path, priv)
+				err = tree.Delete(context.Background(), instances[0].Client, path, priv)
 				gomega.Ω(err).Should(gomega.BeNil())
 				close(c)
 				<-d
@@ -659,57 +1613,105 @@ var _ = ginkgo.Describe("Tx Types", func() {
 				// Should error
 				dummyFile, err := ioutil.TempFile("", "computer_copy")
 				gomega.Ω(err).Should(gomega.BeNil())
-				err = tree.Download(context.Background(), instances[0].cli, path, dummyFile)
+				err = tree.Download(context.Background(), instances[0].Client, path, dummyFile)
 				gomega.Ω(err).Should(gomega.MatchError(tree.ErrMissing))
 				dummyFile.Close()
 			})
 		}
 	})
 
-	// TODO: full replicate blocks between nodes
-})
+	ginkgo.It("sync works", func() {
+		space := "coolsyncedfilesforall"
+		ginkgo.By("create space", func() {
+			createIssueTx(instances[0], &chain.Input{
+				Typ:   chain.Claim,
+				Space: space,
+			}, priv)
+			expectBlkAccept(instances[0])
+		})
 
-func createIssueRawTx(i instance, utx chain.UnsignedTransaction, signer *ecdsa.PrivateKey) {
-	g, err := i.cli.Genesis(context.Background())
-	gomega.Ω(err).Should(gomega.BeNil())
-	utx.SetMagic(g.Magic)
+		dir, err := ioutil.TempDir("", "sync")
+		gomega.Ω(err).Should(gomega.BeNil())
+		defer os.RemoveAll(dir)
+		gomega.Ω(os.MkdirAll(filepath.Join(dir, "nested"), 0o755)).Should(gomega.BeNil())
+		gomega.Ω(ioutil.WriteFile(filepath.Join(dir, "a.txt"), []byte("hello"), 0o600)).Should(gomega.BeNil())
+		gomega.Ω(ioutil.WriteFile(filepath.Join(dir, "nested", "b.txt"), []byte("world"), 0o600)).Should(gomega.BeNil())
+
+		sync := func() *tree.SyncResult {
+			c := make(chan struct{})
+			d := make(chan struct{})
+			go func() {
+				asyncBlockPush(instances[0], c)
+				close(d)
+			}()
+			result, err := tree.Sync(
+				context.Background(), instances[0].Client, priv,
+				space, dir, int(genesis.MaxValueSize),
+			)
+			gomega.Ω(err).Should(gomega.BeNil())
+			close(c)
+			<-d
+			return result
+		}
 
-	la, err := i.cli.Accepted(context.Background())
-	gomega.Ω(err).Should(gomega.BeNil())
-	utx.SetBlockID(la)
+		ginkgo.By("initial sync", func() {
+			result := sync()
+			gomega.Ω(result.Uploaded).Should(gomega.Equal([]string{"a.txt", "nested/b.txt"}))
+			gomega.Ω(result.DeletedKeys).Should(gomega.Equal(0))
+		})
 
-	price, blockCost, err := i.cli.SuggestedRawFee(context.Background())
-	gomega.Ω(err).Should(gomega.BeNil())
-	utx.SetPrice(price + blockCost/utx.FeeUnits(g))
+		var removed string
+		ginkgo.By("sync changes", func() {
+			m, err := tree.GetManifest(context.Background(), instances[0].Client, space)
+			gomega.Ω(err).Should(gomega.BeNil())
+			removed = space + parser.Delimiter + m.Files["nested/b.txt"].Root
+
+			gomega.Ω(ioutil.WriteFile(filepath.Join(dir, "a.txt"), []byte("hello again"), 0o600)).Should(gomega.BeNil())
+			gomega.Ω(os.Remove(filepath.Join(dir, "nested", "b.txt"))).Should(gomega.BeNil())
+			result := sync()
+			gomega.Ω(result.Uploaded).Should(gomega.Equal([]string{"a.txt"}))
+			gomega.Ω(result.Removed).Should(gomega.Equal([]string{"nested/b.txt"}))
+			// old "a.txt", "nested/b.txt", and manifest roots
+			gomega.Ω(result.DeletedKeys).Should(gomega.Equal(3))
+		})
 
-	dh, err := chain.DigestHash(utx)
-	gomega.Ω(err).Should(gomega.BeNil())
-	sig, err := chain.Sign(dh, signer)
-	gomega.Ω(err).Should(gomega.BeNil())
+		ginkgo.By("check synced contents", func() {
+			m, err := tree.GetManifest(context.Background(), instances[0].Client, space)
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(m.Files).Should(gomega.HaveLen(1))
 
-	tx := chain.NewTx(utx, sig)
-	err = tx.Init(genesis)
-	gomega.Ω(err).To(gomega.BeNil())
+			var buf bytes.Buffer
+			err = tree.Download(context.Background(), instances[0].Client, space+parser.Delimiter+m.Files["a.txt"].Root, &buf)
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(buf.String()).Should(gomega.Equal("hello again"))
 
-	_, err = i.cli.IssueRawTx(context.Background(), tx.Bytes())
-	gomega.Ω(err).To(gomega.BeNil())
-}
+			exists, _, _, err := instances[0].Client.Resolve(context.Background(), removed)
+			gomega.Ω(err).Should(gomega.BeNil())
+			gomega.Ω(exists).Should(gomega.BeFalse())
+		})
 
-func createIssueTx(i instance, input *chain.Input, signer *ecdsa.PrivateKey) {
-	td, _, err := i.cli.SuggestedFee(context.Background(), input)
-	gomega.Ω(err).Should(gomega.BeNil())
+		ginkgo.By("sync without changes", func() {
+			result := sync()
+			gomega.Ω(result.Uploaded).Should(gomega.BeEmpty())
+			gomega.Ω(result.Unchanged).Should(gomega.Equal([]string{"a.txt"}))
+			gomega.Ω(result.DeletedKeys).Should(gomega.Equal(0))
+		})
+	})
 
-	dh, err := tdata.DigestHash(td)
-	gomega.Ω(err).Should(gomega.BeNil())
+	// TODO: full replicate blocks between nodes
+})
 
-	sig, err := chain.Sign(dh, signer)
-	gomega.Ω(err).Should(gomega.BeNil())
+func createIssueRawTx(i *vmtest.Instance, utx chain.UnsignedTransaction, signer *ecdsa.PrivateKey) {
+	_, err := i.IssueRawTx(context.Background(), utx, signer)
+	gomega.Ω(err).To(gomega.BeNil())
+}
 
-	_, err = i.cli.IssueTx(context.Background(), td, sig)
+func createIssueTx(i *vmtest.Instance, input *chain.Input, signer *ecdsa.PrivateKey) {
+	_, err := i.IssueTx(context.Background(), input, signer)
 	gomega.Ω(err).To(gomega.BeNil())
 }
 
-func asyncBlockPush(i instance, c chan struct{}) {
+func asyncBlockPush(i *vmtest.Instance, c chan struct{}) {
 	timer := time.NewTicker(500 * time.Millisecond)
 	for {
 		select {
@@ -717,11 +1719,11 @@ func asyncBlockPush(i instance, c chan struct{}) {
 			return
 		case <-timer.C:
 			// manually signal ready
-			i.builder.NotifyBuild()
+			i.Builder.NotifyBuild()
 			// manually ack ready sig as in engine
-			<-i.toEngine
+			<-i.ToEngine
 
-			blk, err := i.vm.BuildBlock()
+			blk, err := i.VM.BuildBlock()
 			if err != nil {
 				continue
 			}
@@ -729,57 +1731,24 @@ func asyncBlockPush(i instance, c chan struct{}) {
 			gomega.Ω(blk.Verify()).To(gomega.BeNil())
 			gomega.Ω(blk.Status()).To(gomega.Equal(choices.Processing))
 
-			err = i.vm.SetPreference(blk.ID())
+			err = i.VM.SetPreference(blk.ID())
 			gomega.Ω(err).To(gomega.BeNil())
 
 			gomega.Ω(blk.Accept()).To(gomega.BeNil())
 			gomega.Ω(blk.Status()).To(gomega.Equal(choices.Accepted))
 
-			lastAccepted, err := i.vm.LastAccepted()
+			lastAccepted, err := i.VM.LastAccepted()
 			gomega.Ω(err).To(gomega.BeNil())
 			gomega.Ω(lastAccepted).To(gomega.Equal(blk.ID()))
 		}
 	}
 }
 
-func expectBlkAccept(i instance) {
-	// manually signal ready
-	i.builder.NotifyBuild()
-	// manually ack ready sig as in engine
-	<-i.toEngine
-
-	blk, err := i.vm.BuildBlock()
+func expectBlkAccept(i *vmtest.Instance) {
+	blk, err := i.BuildAndAccept()
 	gomega.Ω(err).To(gomega.BeNil())
 
-	gomega.Ω(blk.Verify()).To(gomega.BeNil())
-	gomega.Ω(blk.Status()).To(gomega.Equal(choices.Processing))
-
-	err = i.vm.SetPreference(blk.ID())
-	gomega.Ω(err).To(gomega.BeNil())
-
-	gomega.Ω(blk.Accept()).To(gomega.BeNil())
-	gomega.Ω(blk.Status()).To(gomega.Equal(choices.Accepted))
-
-	lastAccepted, err := i.vm.LastAccepted()
+	lastAccepted, err := i.VM.LastAccepted()
 	gomega.Ω(err).To(gomega.BeNil())
 	gomega.Ω(lastAccepted).To(gomega.Equal(blk.ID()))
 }
-
-var _ common.AppSender = &appSender{}
-
-type appSender struct {
-	next      int
-	instances []instance
-}
-
-func (app *appSender) SendAppGossip(appGossipBytes []byte) error {
-	n := len(app.instances)
-	sender := app.instances[app.next].nodeID
-	app.next++
-	app.next %= n
-	return app.instances[app.next].vm.AppGossip(sender, appGossipBytes)
-}
-
-func (app *appSender) SendAppRequest(_ ids.NodeIDSet, _ uint32, _ []byte) error { return nil }
-func (app *appSender) SendAppResponse(_ ids.NodeID, _ uint32, _ []byte) error   { return nil }
-func (app *appSender) SendAppGossipSpecific(_ ids.NodeIDSet, _ []byte) error    { return nil }
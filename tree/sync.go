@@ -0,0 +1,249 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package tree
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/fatih/color"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+// ManifestKey holds the root key of the manifest of a synced space
+const ManifestKey = "manifest"
+
+// Manifest records the files synced into a space, keyed by their path
+// relative to the synced directory (using "/" as the separator).
+type Manifest struct {
+	Files map[string]*ManifestEntry `json:"files"`
+
+	// keys storing the manifest itself
+	keys []string
+}
+
+type ManifestEntry struct {
+	// Root key of the uploaded file (see [Upload])
+	Root string `json:"root"`
+	// Keys of the chunks referenced by [Root]
+	Chunks []string `json:"chunks,omitempty"`
+	Size   int64    `json:"size"`
+	// Keccak256 of the file contents
+	Hash string `json:"hash"`
+	// MIME type the file is served with by the site gateway
+	ContentType string `json:"contentType,omitempty"`
+}
+
+func (e *ManifestEntry) keys() []string {
+	return append([]string{e.Root}, e.Chunks...)
+}
+
+type SyncResult struct {
+	Manifest  string   `json:"manifest"`
+	Uploaded  []string `json:"uploaded"`
+	Unchanged []string `json:"unchanged"`
+	Removed   []string `json:"removed"`
+	// Number of keys deleted because they were no longer referenced
+	DeletedKeys int `json:"deletedKeys"`
+}
+
+// Sync uploads the files in [dir] that changed since the last sync into
+// [space], deletes the keys of files that were removed or changed, and
+// records the result in a [Manifest] stored at [ManifestKey]. Empty files are
+// skipped.
+func Sync(
+	ctx context.Context, cli client.Client, priv *ecdsa.PrivateKey,
+	space string, dir string, chunkSize int, opts ...client.OpOption,
+) (*SyncResult, error) {
+	prev, err := GetManifest(ctx, cli, space)
+	if err != nil {
+		return nil, err
+	}
+	opts = append([]client.OpOption{client.WithPollTx()}, opts...)
+
+	next := &Manifest{Files: map[string]*ManifestEntry{}}
+	result := &SyncResult{}
+	if err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if len(b) == 0 {
+			color.Yellow("skipping empty file %s", rel)
+			return nil
+		}
+		hash := common.Bytes2Hex(crypto.Keccak256(b))
+		if entry, ok := prev.Files[rel]; ok && entry.Hash == hash {
+			entry.ContentType = contentType(rel, b)
+			next.Files[rel] = entry
+			result.Unchanged = append(result.Unchanged, rel)
+			return nil
+		}
+
+		color.Cyan("uploading %s", rel)
+		entry, err := uploadEntry(ctx, cli, priv, space, bytes.NewReader(b), chunkSize, opts)
+		if err != nil {
+			return fmt.Errorf("%w: unable to upload %s", err, rel)
+		}
+		entry.Size = int64(len(b))
+		entry.Hash = hash
+		entry.ContentType = contentType(rel, b)
+		next.Files[rel] = entry
+		result.Uploaded = append(result.Uploaded, rel)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	for rel := range prev.Files {
+		if _, ok := next.Files[rel]; !ok {
+			result.Removed = append(result.Removed, rel)
+		}
+	}
+	sort.Strings(result.Removed)
+
+	// Record the new manifest before deleting anything so an interrupted sync
+	// never leaves a manifest that references deleted keys
+	mb, err := json.Marshal(next)
+	if err != nil {
+		return nil, err
+	}
+	mentry, err := uploadEntry(ctx, cli, priv, space, bytes.NewReader(mb), chunkSize, opts)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to upload manifest", err)
+	}
+	next.keys = mentry.keys()
+	if _, _, err := client.SignIssueRawTx(ctx, cli, &chain.SetTx{
+		BaseTx: &chain.BaseTx{},
+		Space:  space,
+		Key:    ManifestKey,
+		Value:  []byte(mentry.Root),
+	}, priv, opts...); err != nil {
+		return nil, err
+	}
+	result.Manifest = space + parser.Delimiter + mentry.Root
+
+	// Chunks are content-addressed, so only delete keys no longer referenced
+	// by any file
+	referenced := map[string]struct{}{}
+	for _, k := range next.keys {
+		referenced[k] = struct{}{}
+	}
+	for _, entry := range next.Files {
+		for _, k := range entry.keys() {
+			referenced[k] = struct{}{}
+		}
+	}
+	stale := map[string]struct{}{}
+	for _, k := range prev.keys {
+		stale[k] = struct{}{}
+	}
+	for _, entry := range prev.Files {
+		for _, k := range entry.keys() {
+			stale[k] = struct{}{}
+		}
+	}
+	for k := range stale {
+		if _, ok := referenced[k]; ok {
+			continue
+		}
+		txID, _, err := client.SignIssueRawTx(ctx, cli, &chain.DeleteTx{
+			BaseTx: &chain.BaseTx{},
+			Space:  space,
+			Key:    k,
+		}, priv, opts...)
+		if err != nil {
+			return nil, err
+		}
+		color.Yellow("deleted k=%s txID=%s", k, txID)
+		result.DeletedKeys++
+	}
+	return result, nil
+}
+
+// GetManifest returns the [Manifest] of [space], which is empty if [space]
+// has never been synced.
+func GetManifest(ctx context.Context, cli client.Client, space string) (*Manifest, error) {
+	m := &Manifest{Files: map[string]*ManifestEntry{}}
+	exists, root, _, err := cli.Resolve(ctx, space+parser.Delimiter+ManifestKey)
+	if err != nil || !exists {
+		return m, err
+	}
+	path := space + parser.Delimiter + string(root)
+	var buf bytes.Buffer
+	if err := Download(ctx, cli, path, &buf); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(buf.Bytes(), m); err != nil {
+		return nil, fmt.Errorf("%w: invalid manifest %s", err, path)
+	}
+	entry, err := resolveEntry(ctx, cli, space, string(root))
+	if err != nil {
+		return nil, err
+	}
+	m.keys = entry.keys()
+	return m, nil
+}
+
+// contentType returns the MIME type of the file at [rel], sniffing [b] when
+// the extension is unknown
+func contentType(rel string, b []byte) string {
+	if t := mime.TypeByExtension(path.Ext(rel)); len(t) > 0 {
+		return t
+	}
+	return http.DetectContentType(b)
+}
+
+// uploadEntry uploads [f] and returns the keys it is stored at
+func uploadEntry(
+	ctx context.Context, cli client.Client, priv *ecdsa.PrivateKey,
+	space string, f *bytes.Reader, chunkSize int, opts []client.OpOption,
+) (*ManifestEntry, error) {
+	path, err := Upload(ctx, cli, priv, space, f, chunkSize, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return resolveEntry(ctx, cli, space, strings.TrimPrefix(path, space+parser.Delimiter))
+}
+
+// resolveEntry returns the chunks referenced by [root]
+func resolveEntry(ctx context.Context, cli client.Client, space string, root string) (*ManifestEntry, error) {
+	path := space + parser.Delimiter + root
+	exists, rb, _, err := cli.Resolve(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w:%s", ErrMissing, path)
+	}
+	var r Root
+	if err := json.Unmarshal(rb, &r); err != nil {
+		return nil, err
+	}
+	return &ManifestEntry{Root: root, Chunks: r.Children}, nil
+}
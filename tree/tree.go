@@ -29,12 +29,12 @@ type Root struct {
 
 func Upload(
 	ctx context.Context, cli client.Client, priv *ecdsa.PrivateKey,
-	space string, f io.Reader, chunkSize int,
+	space string, f io.Reader, chunkSize int, opts ...client.OpOption,
 ) (string, error) {
 	hashes := []string{}
 	chunk := make([]byte, chunkSize)
 	shouldExit := false
-	opts := []client.OpOption{client.WithPollTx()}
+	opts = append([]client.OpOption{client.WithPollTx()}, opts...)
 	totalCost := uint64(0)
 	uploaded := map[string]struct{}{}
 	for !shouldExit {
@@ -157,7 +157,7 @@ func Download(ctx context.Context, cli client.Client, path string, f io.Writer)
 }
 
 // Delete all hashes under a root
-func Delete(ctx context.Context, cli client.Client, path string, priv *ecdsa.PrivateKey) error {
+func Delete(ctx context.Context, cli client.Client, path string, priv *ecdsa.PrivateKey, opts ...client.OpOption) error {
 	exists, rb, _, err := cli.Resolve(ctx, path)
 	if err != nil {
 		return err
@@ -173,7 +173,7 @@ func Delete(ctx context.Context, cli client.Client, path string, priv *ecdsa.Pri
 	spl := strings.Split(path, parser.Delimiter)
 	space := spl[0]
 	root := spl[1]
-	opts := []client.OpOption{client.WithPollTx()}
+	opts = append([]client.OpOption{client.WithPollTx()}, opts...)
 	totalCost := uint64(0)
 	deleted := map[string]struct{}{}
 	for _, h := range r.Children {
@@ -0,0 +1,134 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/snow"
+	log "github.com/inconshreveable/log15"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+// AcceptHook is notified of every block (and each of its transactions)
+// accepted by the VM, so operators can export the chain to other systems
+// (such as Kafka, webhooks, or databases) without forking the VM.
+//
+// Hooks are called in order while the VM is locked, after the block is
+// committed. They should hand blocks off quickly (blocking delays
+// consensus), and an erroring hook is logged but can't prevent acceptance.
+// Hooks that implement [io.Closer] are closed when the VM shuts down.
+type AcceptHook interface {
+	AcceptedBlock(blk *chain.StatelessBlock) error
+	AcceptedTx(blk *chain.StatelessBlock, tx *chain.Transaction) error
+}
+
+// AcceptHookFactory creates an [AcceptHook] when the VM is initialized.
+// [config] is the value of the hook in [Config.AcceptHooks].
+type AcceptHookFactory func(vm *VM, config json.RawMessage) (AcceptHook, error)
+
+var (
+	acceptHooksLock     sync.RWMutex
+	acceptHookFactories = map[string]AcceptHookFactory{}
+)
+
+// RegisterAcceptHook makes [factory] available as [name] to
+// [Config.AcceptHooks]. It is meant to be called from the init function of a
+// package compiled into a custom VM binary and panics if [name] is
+// registered twice.
+func RegisterAcceptHook(name string, factory AcceptHookFactory) {
+	acceptHooksLock.Lock()
+	defer acceptHooksLock.Unlock()
+
+	if _, ok := acceptHookFactories[name]; ok {
+		panic(fmt.Sprintf("accept hook %q registered twice", name))
+	}
+	acceptHookFactories[name] = factory
+}
+
+// initAcceptHooks creates the hooks enabled in [Config.AcceptHooks] (sorted
+// by name)
+func (vm *VM) initAcceptHooks() error {
+	acceptHooksLock.RLock()
+	defer acceptHooksLock.RUnlock()
+
+	names := make([]string, 0, len(vm.config.AcceptHooks))
+	for name := range vm.config.AcceptHooks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		factory, ok := acceptHookFactories[name]
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownAcceptHook, name)
+		}
+		hook, err := factory(vm, vm.config.AcceptHooks[name])
+		if err != nil {
+			return fmt.Errorf("unable to create accept hook %q: %w", name, err)
+		}
+		vm.acceptHooks = append(vm.acceptHooks, hook)
+		log.Info("enabled accept hook", "name", name)
+	}
+	return nil
+}
+
+// notifyAcceptHooks passes [b] and its transactions to all accept hooks
+func (vm *VM) notifyAcceptHooks(b *chain.StatelessBlock) {
+	for _, hook := range vm.acceptHooks {
+		if err := hook.AcceptedBlock(b); err != nil {
+			log.Warn("accept hook failed", "blkID", b.ID(), "err", err)
+			continue
+		}
+		for _, tx := range b.Txs {
+			if err := hook.AcceptedTx(b, tx); err != nil {
+				log.Warn("accept hook failed", "blkID", b.ID(), "txID", tx.ID(), "err", err)
+			}
+		}
+	}
+}
+
+// closeAcceptHooks closes the accept hooks that implement [io.Closer]
+func (vm *VM) closeAcceptHooks() {
+	for _, hook := range vm.acceptHooks {
+		c, ok := hook.(io.Closer)
+		if !ok {
+			continue
+		}
+		if err := c.Close(); err != nil {
+			log.Warn("unable to close accept hook", "err", err)
+		}
+	}
+}
+
+// RegisterAcceptor subscribes [acceptor] to every block accepted by this VM
+// after registration. Acceptors receive the same (ID, bytes) pair the node
+// dispatches to its own acceptors, so node-level tooling (such as indexers)
+// can consume spacesvm blocks when the VM is embedded in-process.
+//
+// Acceptors are called after the block is committed, so an erroring acceptor
+// is logged but can't prevent acceptance.
+func (vm *VM) RegisterAcceptor(name string, acceptor snow.Acceptor) error {
+	return vm.acceptors.RegisterAcceptor(vm.ctx.ChainID, name, acceptor, false)
+}
+
+// DeregisterAcceptor removes the acceptor registered as [name]
+func (vm *VM) DeregisterAcceptor(name string) error {
+	return vm.acceptors.DeregisterAcceptor(vm.ctx.ChainID, name)
+}
+
+// notifyAcceptors dispatches [b] to all registered acceptors
+func (vm *VM) notifyAcceptors(b *chain.StatelessBlock) {
+	if vm.acceptors == nil {
+		return
+	}
+	ctx := &snow.ConsensusContext{Context: vm.ctx}
+	if err := vm.acceptors.Accept(ctx, b.ID(), b.Bytes()); err != nil {
+		log.Warn("acceptor failed", "blkID", b.ID(), "err", err)
+	}
+}
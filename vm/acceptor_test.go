@@ -0,0 +1,149 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/cache"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/choices"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+type testAcceptor struct {
+	accepted []ids.ID
+	bytes    [][]byte
+}
+
+func (a *testAcceptor) Accept(_ *snow.ConsensusContext, containerID ids.ID, container []byte) error {
+	a.accepted = append(a.accepted, containerID)
+	a.bytes = append(a.bytes, container)
+	return nil
+}
+
+func TestRegisterAcceptor(t *testing.T) {
+	ctx := snow.DefaultContextTest()
+	vm := &VM{
+		ctx:            ctx,
+		db:             memdb.New(),
+		blocks:         &cache.LRU{Size: 3},
+		verifiedBlocks: make(map[ids.ID]*chain.StatelessBlock),
+		acceptors:      snow.NewAcceptorGroup(ctx.Log),
+	}
+	blk, err := chain.ParseStatefulBlock(&chain.StatefulBlock{
+		Prnt:   ids.GenerateTestID(),
+		Hght:   1,
+		Tmstmp: 1,
+	}, nil, choices.Processing, vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm.preferred = blk.ID()
+
+	a := &testAcceptor{}
+	if err := vm.RegisterAcceptor("test", a); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.RegisterAcceptor("test", a); err == nil {
+		t.Fatal("expected duplicate registration to fail")
+	}
+	vm.Accepted(blk)
+	if len(a.accepted) != 1 || a.accepted[0] != blk.ID() || !bytes.Equal(a.bytes[0], blk.Bytes()) {
+		t.Fatalf("unexpected accepted blocks %v", a.accepted)
+	}
+
+	if err := vm.DeregisterAcceptor("test"); err != nil {
+		t.Fatal(err)
+	}
+	vm.Accepted(blk)
+	if len(a.accepted) != 1 {
+		t.Fatalf("expected no notifications after deregistering, got %d", len(a.accepted))
+	}
+}
+
+type testAcceptHook struct {
+	config string
+	blocks []ids.ID
+	txs    []ids.ID
+	closed bool
+}
+
+func (h *testAcceptHook) AcceptedBlock(blk *chain.StatelessBlock) error {
+	h.blocks = append(h.blocks, blk.ID())
+	return nil
+}
+
+func (h *testAcceptHook) AcceptedTx(_ *chain.StatelessBlock, tx *chain.Transaction) error {
+	h.txs = append(h.txs, tx.ID())
+	return nil
+}
+
+func (h *testAcceptHook) Close() error {
+	h.closed = true
+	return nil
+}
+
+func TestAcceptHooks(t *testing.T) {
+	hook := &testAcceptHook{}
+	RegisterAcceptHook("test-hook", func(_ *VM, config json.RawMessage) (AcceptHook, error) {
+		hook.config = string(config)
+		return hook, nil
+	})
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("expected duplicate registration to panic")
+		}
+	}()
+	defer RegisterAcceptHook("test-hook", nil)
+
+	ctx := snow.DefaultContextTest()
+	vm := &VM{
+		ctx:            ctx,
+		db:             memdb.New(),
+		blocks:         &cache.LRU{Size: 3},
+		verifiedBlocks: make(map[ids.ID]*chain.StatelessBlock),
+	}
+	vm.config.AcceptHooks = map[string]json.RawMessage{"unknown": nil}
+	if err := vm.initAcceptHooks(); !errors.Is(err, ErrUnknownAcceptHook) {
+		t.Fatalf("expected %v, got %v", ErrUnknownAcceptHook, err)
+	}
+	vm.config.AcceptHooks = map[string]json.RawMessage{"test-hook": json.RawMessage(`{"topic":"blocks"}`)}
+	if err := vm.initAcceptHooks(); err != nil {
+		t.Fatal(err)
+	}
+	if hook.config != `{"topic":"blocks"}` {
+		t.Fatalf("unexpected hook config %q", hook.config)
+	}
+
+	blk, err := chain.ParseStatefulBlock(&chain.StatefulBlock{
+		Prnt:   ids.GenerateTestID(),
+		Hght:   1,
+		Tmstmp: 1,
+	}, nil, choices.Processing, vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := chain.NewTx(&chain.ClaimTx{BaseTx: &chain.BaseTx{}, Space: "foo"}, nil)
+	blk.Txs = []*chain.Transaction{tx}
+	vm.preferred = blk.ID()
+	vm.Accepted(blk)
+	if len(hook.blocks) != 1 || hook.blocks[0] != blk.ID() {
+		t.Fatalf("unexpected accepted blocks %v", hook.blocks)
+	}
+	if len(hook.txs) != 1 || hook.txs[0] != tx.ID() {
+		t.Fatalf("unexpected accepted txs %v", hook.txs)
+	}
+
+	vm.closeAcceptHooks()
+	if !hook.closed {
+		t.Fatal("expected hook to be closed")
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"net/http"
+
+	log "github.com/inconshreveable/log15"
+)
+
+// AdminService exposes node operator functionality. It is only served when
+// [Config.AdminAPIEnabled] is set.
+type AdminService struct {
+	vm *VM
+}
+
+type CompactReply struct {
+	Ranges int `serialize:"true" json:"ranges"`
+}
+
+func (svc *AdminService) Compact(_ *http.Request, _ *struct{}, reply *CompactReply) (err error) {
+	log.Info("admin compaction requested")
+	reply.Ranges, err = svc.vm.CompactAll()
+	return err
+}
+
+type PruneReply struct {
+	Stats *PruneStats `serialize:"true" json:"stats"`
+}
+
+// Prune prunes expired spaces and reclaims removed values immediately instead
+// of waiting for the background pruner.
+func (svc *AdminService) Prune(_ *http.Request, _ *struct{}, reply *PruneReply) (err error) {
+	log.Info("admin pruning requested")
+	reply.Stats, err = svc.vm.PruneAll()
+	return err
+}
+
+type ReindexReply struct {
+	Stats *ReindexStats `serialize:"true" json:"stats"`
+}
+
+// Reindex rebuilds the secondary indexes (block heights, tx index, history,
+// expiry, and owned spaces) from the accepted blocks and stored state.
+func (svc *AdminService) Reindex(_ *http.Request, _ *struct{}, reply *ReindexReply) (err error) {
+	log.Info("admin reindex requested")
+	reply.Stats, err = svc.vm.Reindex()
+	return err
+}
+
+type BackupArgs struct {
+	Dir string `serialize:"true" json:"dir"`
+}
+
+type BackupReply struct {
+	Metadata *BackupMetadata `serialize:"true" json:"metadata"`
+}
+
+// Backup writes a backup of the database to [BackupArgs.Dir] under
+// [Config.BackupRoot].
+func (svc *AdminService) Backup(_ *http.Request, args *BackupArgs, reply *BackupReply) (err error) {
+	dir, err := svc.vm.backupPath(args.Dir)
+	if err != nil {
+		return err
+	}
+	log.Info("admin backup requested", "dir", dir)
+	reply.Metadata, err = svc.vm.Backup(dir)
+	return err
+}
+
+type ExportArgs struct {
+	Dir    string `serialize:"true" json:"dir"`
+	Format string `serialize:"true" json:"format"`
+	// State also writes a backup of the database to [Dir]
+	State bool `serialize:"true" json:"state"`
+}
+
+type ExportReply struct {
+	Metadata *ExportMetadata `serialize:"true" json:"metadata"`
+}
+
+// Export streams every accepted block (and optionally a backup of the
+// state) to flat files in [ExportArgs.Dir] under [Config.BackupRoot].
+func (svc *AdminService) Export(_ *http.Request, args *ExportArgs, reply *ExportReply) (err error) {
+	dir, err := svc.vm.backupPath(args.Dir)
+	if err != nil {
+		return err
+	}
+	log.Info("admin export requested", "dir", dir, "format", args.Format, "state", args.State)
+	reply.Metadata, err = svc.vm.Export(dir, args.Format, args.State)
+	return err
+}
@@ -0,0 +1,39 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/vms/components/verify"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+var _ chain.Atomic = &sharedMemory{}
+
+// sharedMemory is the memory this chain shares with the other chains of its
+// subnet
+type sharedMemory struct {
+	ctx *snow.Context
+}
+
+func (s *sharedMemory) SharedMemory() atomic.SharedMemory {
+	return s.ctx.SharedMemory
+}
+
+// VerifyPeerChain follows the X and P chains in only allowing units to be
+// moved between distinct chains of the same subnet
+func (s *sharedMemory) VerifyPeerChain(chainID ids.ID) error {
+	return verify.SameSubnet(s.ctx, chainID)
+}
+
+// Atomic returns nil if the node did not provide shared memory
+func (vm *VM) Atomic() chain.Atomic {
+	if vm.ctx.SharedMemory == nil || vm.ctx.SNLookup == nil {
+		return nil
+	}
+	return &sharedMemory{ctx: vm.ctx}
+}
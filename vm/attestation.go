@@ -0,0 +1,126 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"crypto/ecdsa"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+// attestationPrefix separates attestation digests from transaction digests so
+// a signature over one can never be replayed as the other
+var attestationPrefix = []byte("spacesvm attestation\n")
+
+// Attestation is a node's signature over a resolve response. Caches and light
+// clients that trust [Signer] can use it to detect responses tampered with by
+// the intermediaries that relayed them.
+type Attestation struct {
+	ChainID   ids.ID      `serialize:"true" json:"chainId"`
+	Path      string      `serialize:"true" json:"path"`
+	Exists    bool        `serialize:"true" json:"exists"`
+	ValueHash common.Hash `serialize:"true" json:"valueHash"`
+	// Height and BlockID are the last accepted block when the response was
+	// served (the value reflects state at or after it)
+	Height  uint64 `serialize:"true" json:"height"`
+	BlockID ids.ID `serialize:"true" json:"blockId"`
+
+	Signer    common.Address `serialize:"true" json:"signer"`
+	Signature hexutil.Bytes  `serialize:"true" json:"signature"`
+}
+
+// Digest returns the hash signed by [Signer]
+func (a *Attestation) Digest() []byte {
+	exists := byte(0)
+	if a.Exists {
+		exists = 1
+	}
+	height := make([]byte, 8)
+	binary.BigEndian.PutUint64(height, a.Height)
+	return crypto.Keccak256(
+		attestationPrefix,
+		a.ChainID[:],
+		[]byte(a.Path),
+		[]byte{exists},
+		a.ValueHash[:],
+		height,
+		a.BlockID[:],
+	)
+}
+
+func (a *Attestation) sign(priv *ecdsa.PrivateKey) error {
+	sig, err := chain.Sign(a.Digest(), priv)
+	if err != nil {
+		return err
+	}
+	a.Signer = crypto.PubkeyToAddress(priv.PublicKey)
+	a.Signature = sig
+	return nil
+}
+
+// Verify checks that the attestation was signed by [signer] and covers
+// [path] resolving to [value] ([exists] is false when the path is unset).
+func (a *Attestation) Verify(path string, exists bool, value []byte, signer common.Address) error {
+	return a.VerifyHash(path, exists, valueHash(exists, value), signer)
+}
+
+// VerifyHash is [Verify] for a value known only by its hash (such as the ETag
+// of a not modified resolve reply)
+func (a *Attestation) VerifyHash(path string, exists bool, hash common.Hash, signer common.Address) error {
+	if a.Path != path {
+		return fmt.Errorf("%w: path %q does not match %q", ErrInvalidAttestation, a.Path, path)
+	}
+	if a.Exists != exists {
+		return fmt.Errorf("%w: exists=%t does not match response", ErrInvalidAttestation, a.Exists)
+	}
+	if a.ValueHash != hash {
+		return fmt.Errorf("%w: value hash mismatch", ErrInvalidAttestation)
+	}
+	if a.Signer != signer {
+		return fmt.Errorf("%w: signed by %s, expected %s", ErrInvalidAttestation, a.Signer, signer)
+	}
+	pk, err := chain.DeriveSender(a.Digest(), a.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidAttestation, err)
+	}
+	if recovered := crypto.PubkeyToAddress(*pk); recovered != signer {
+		return fmt.Errorf("%w: signature recovers %s, expected %s", ErrInvalidAttestation, recovered, signer)
+	}
+	return nil
+}
+
+// valueHash is the hash of [value], or the empty hash if it does not exist
+func valueHash(exists bool, value []byte) common.Hash {
+	if !exists {
+		return common.Hash{}
+	}
+	return crypto.Keccak256Hash(value)
+}
+
+// attest signs the response for [path] with the configured response key (if
+// any)
+func (vm *VM) attest(path string, exists bool, value []byte, la *chain.StatelessBlock) (*Attestation, error) {
+	if vm.responseKey == nil {
+		return nil, nil
+	}
+	a := &Attestation{
+		ChainID:   vm.ctx.ChainID,
+		Path:      path,
+		Exists:    exists,
+		ValueHash: valueHash(exists, value),
+		Height:    la.Hght,
+		BlockID:   la.ID(),
+	}
+	if err := a.sign(vm.responseKey); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
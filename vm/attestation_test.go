@@ -0,0 +1,79 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestAttestation(t *testing.T) {
+	t.Parallel()
+
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.PubkeyToAddress(priv.PublicKey)
+	other, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	value := []byte("value")
+	a := &Attestation{
+		ChainID:   ids.GenerateTestID(),
+		Path:      "space/key",
+		Exists:    true,
+		ValueHash: valueHash(true, value),
+		Height:    10,
+		BlockID:   ids.GenerateTestID(),
+	}
+	if err := a.sign(priv); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.Verify("space/key", true, value, signer); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := a.VerifyHash("space/key", true, crypto.Keccak256Hash(value), signer); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	tt := []struct {
+		name   string
+		path   string
+		exists bool
+		value  []byte
+		modify func(a *Attestation)
+	}{
+		{name: "path", path: "space/other", exists: true, value: value},
+		{name: "value", path: "space/key", exists: true, value: []byte("tampered")},
+		{name: "exists", path: "space/key", exists: false},
+		{
+			name: "signer", path: "space/key", exists: true, value: value,
+			modify: func(a *Attestation) { a.Signer = crypto.PubkeyToAddress(other.PublicKey) },
+		},
+		{
+			name: "height", path: "space/key", exists: true, value: value,
+			modify: func(a *Attestation) { a.Height++ },
+		},
+		{
+			name: "signature", path: "space/key", exists: true, value: value,
+			modify: func(a *Attestation) { a.Signature = a.Signature[1:] },
+		},
+	}
+	for _, tv := range tt {
+		c := *a
+		c.Signature = append([]byte{}, a.Signature...)
+		if tv.modify != nil {
+			tv.modify(&c)
+		}
+		if err := c.Verify(tv.path, tv.exists, tv.value, signer); !errors.Is(err, ErrInvalidAttestation) {
+			t.Fatalf("%s: expected %v, got %v", tv.name, ErrInvalidAttestation, err)
+		}
+	}
+}
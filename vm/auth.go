@@ -0,0 +1,87 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// methods on [PublicService] that modify state. They require authentication
+// when [Config.AuthTokens] is set and are limited by [Config.WriteRateLimit].
+var writeMethods = map[string]struct{}{
+	Name + ".issueTx":      {},
+	Name + ".issueRawTx":   {},
+	Name + ".issueInputTx": {},
+}
+
+// authHandler rejects requests to [h] that call a method for which
+// [requiresAuth] returns true unless they provide one of [tokens] as a bearer
+// token.
+func authHandler(h http.Handler, tokens []string, requiresAuth func(method string) bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			h.ServeHTTP(w, r)
+			return
+		}
+		method, err := rpcMethod(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if requiresAuth(method) && !authorized(r, tokens) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// rpcMethod returns the JSON-RPC method called by [r], restoring the body so
+// it can be read again. Malformed requests return an empty method and are
+// rejected by the RPC server.
+func rpcMethod(r *http.Request) (string, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return "", err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	var req struct {
+		Method string `json:"method"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return "", nil
+	}
+	return req.Method, nil
+}
+
+// bearerToken returns the bearer token provided with [r], if any
+func bearerToken(r *http.Request) (string, bool) {
+	auth := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if !strings.HasPrefix(auth, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(auth, prefix), true
+}
+
+func authorized(r *http.Request, tokens []string) bool {
+	token, ok := bearerToken(r)
+	if !ok {
+		return false
+	}
+	provided := []byte(token)
+	for _, token := range tokens {
+		if subtle.ConstantTimeCompare(provided, []byte(token)) == 1 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,44 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAuthHandler(t *testing.T) {
+	h := authHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), []string{"secret"}, func(method string) bool {
+		_, ok := writeMethods[method]
+		return ok
+	})
+
+	tt := []struct {
+		method string
+		token  string
+		status int
+	}{
+		{method: "spacesvm.ping", status: http.StatusOK},
+		{method: "spacesvm.issueRawTx", status: http.StatusUnauthorized},
+		{method: "spacesvm.issueRawTx", token: "wrong", status: http.StatusUnauthorized},
+		{method: "spacesvm.issueRawTx", token: "secret", status: http.StatusOK},
+		{method: "spacesvm.issueTx", token: "secret", status: http.StatusOK},
+	}
+	for _, tv := range tt {
+		body := `{"jsonrpc":"2.0","method":"` + tv.method + `","params":{},"id":1}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		if len(tv.token) > 0 {
+			req.Header.Set("Authorization", "Bearer "+tv.token)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tv.status {
+			t.Fatalf("%s (token=%q): expected status %d, got %d", tv.method, tv.token, tv.status, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	ejson "encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/ids"
+	log "github.com/inconshreveable/log15"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+const (
+	backupDataFile     = "db.backup"
+	backupMetadataFile = "metadata.json"
+
+	backupDirMode  = 0o700
+	backupFileMode = 0o600
+)
+
+// BackupMetadata is stored alongside the backup data and is used to verify
+// the restored database.
+type BackupMetadata struct {
+	LastAccepted ids.ID `serialize:"true" json:"lastAccepted"`
+	Height       uint64 `serialize:"true" json:"height"`
+	Entries      int    `serialize:"true" json:"entries"`
+	Created      int64  `serialize:"true" json:"created"`
+}
+
+// Backup writes a consistent copy of the database to [dir]. The context lock
+// is only held to open an iterator over the database as of the last accepted
+// block, so blocks keep being verified and accepted while the copy is
+// written.
+func (vm *VM) Backup(dir string) (*BackupMetadata, error) {
+	vm.ctx.Lock.Lock()
+	cursor := vm.db.NewIterator()
+	lastAccepted := vm.lastAccepted
+	vm.ctx.Lock.Unlock()
+	defer cursor.Release()
+
+	return writeBackup(dir, cursor, lastAccepted)
+}
+
+// writeBackup writes the entries of [cursor], which must be a consistent view
+// of the database as of [lastAccepted], to [dir].
+func writeBackup(dir string, cursor database.Iterator, lastAccepted *chain.StatelessBlock) (*BackupMetadata, error) {
+	if err := os.MkdirAll(dir, backupDirMode); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(
+		filepath.Join(dir, backupDataFile),
+		os.O_CREATE|os.O_EXCL|os.O_WRONLY,
+		backupFileMode,
+	)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	entries, err := chain.WriteBackup(cursor, f)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+
+	meta := &BackupMetadata{
+		LastAccepted: lastAccepted.ID(),
+		Height:       lastAccepted.Hght,
+		Entries:      entries,
+		Created:      time.Now().Unix(),
+	}
+	b, err := ejson.Marshal(meta)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, backupMetadataFile), b, backupFileMode); err != nil {
+		return nil, err
+	}
+	log.Info("created backup",
+		"dir", dir,
+		"lastAccepted", meta.LastAccepted,
+		"height", meta.Height,
+		"entries", entries,
+		"t", time.Since(start),
+	)
+	return meta, nil
+}
+
+// backupPath resolves [dir] (relative to [Config.BackupRoot] unless it is
+// absolute) and ensures it does not resolve outside of [Config.BackupRoot],
+// following symlinks.
+func (vm *VM) backupPath(dir string) (string, error) {
+	if len(vm.config.BackupRoot) == 0 {
+		return "", ErrBackupsDisabled
+	}
+	if len(dir) == 0 {
+		return "", ErrBackupDirEmpty
+	}
+	if err := os.MkdirAll(vm.config.BackupRoot, backupDirMode); err != nil {
+		return "", err
+	}
+	root, err := filepath.Abs(vm.config.BackupRoot)
+	if err != nil {
+		return "", err
+	}
+	root, err = filepath.EvalSymlinks(root)
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(root, dir)
+	}
+	resolved, err := resolvePath(filepath.Clean(dir))
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil {
+		return "", err
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: %s", ErrBackupDirOutside, dir)
+	}
+	return resolved, nil
+}
+
+// resolvePath follows the symlinks in the longest prefix of [path] that
+// exists.
+func resolvePath(path string) (string, error) {
+	existing, rest := path, ""
+	for {
+		resolved, err := filepath.EvalSymlinks(existing)
+		if err == nil {
+			return filepath.Join(resolved, rest), nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			return path, nil
+		}
+		rest = filepath.Join(filepath.Base(existing), rest)
+		existing = parent
+	}
+}
+
+// RestoreBackup restores the backup in [dir] into [db], which must be empty,
+// and verifies that the restored last accepted block matches the backup
+// metadata. [db] is left empty if the backup is invalid.
+func RestoreBackup(db database.Database, dir string) (*BackupMetadata, error) {
+	b, err := os.ReadFile(filepath.Join(dir, backupMetadataFile))
+	if err != nil {
+		return nil, err
+	}
+	meta := new(BackupMetadata)
+	if err := ejson.Unmarshal(b, meta); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(filepath.Join(dir, backupDataFile))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	entries, err := chain.ImportBackup(db, f)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyRestore(db, meta, entries); err != nil {
+		if cerr := chain.ClearDatabase(db); cerr != nil {
+			return nil, fmt.Errorf("%w (unable to clear restore: %v)", err, cerr)
+		}
+		return nil, err
+	}
+	return meta, nil
+}
+
+// verifyRestore ensures the [entries] restored into [db] match [meta]
+func verifyRestore(db database.Database, meta *BackupMetadata, entries int) error {
+	if entries != meta.Entries {
+		return fmt.Errorf("%w: expected %d entries but restored %d", chain.ErrInvalidBackup, meta.Entries, entries)
+	}
+	blkID, blk, err := chain.VerifyLastAccepted(db)
+	if err != nil {
+		return err
+	}
+	if blkID != meta.LastAccepted || blk.Hght != meta.Height {
+		return fmt.Errorf(
+			"%w: restored last accepted %s (height=%d) but expected %s (height=%d)",
+			chain.ErrInvalidBackup, blkID, blk.Hght, meta.LastAccepted, meta.Height,
+		)
+	}
+	return nil
+}
+
+// FetchBackup downloads a backup published at [url] (a directory containing
+// the files written by [VM.Backup], served over HTTP) into [dir]. Existing
+// files are never overwritten. The backup must still be verified with
+// [RestoreBackup].
+func FetchBackup(ctx context.Context, url string, dir string) error {
+	if err := os.MkdirAll(dir, backupDirMode); err != nil {
+		return err
+	}
+	for _, name := range []string{backupMetadataFile, backupDataFile} {
+		if err := fetchBackupFile(ctx, strings.TrimSuffix(url, "/")+"/"+name, filepath.Join(dir, name)); err != nil {
+			return fmt.Errorf("%w: unable to fetch %s", err, name)
+		}
+	}
+	return nil
+}
+
+func fetchBackupFile(ctx context.Context, url string, path string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s returned %s", ErrBackupUnavailable, url, resp.Status)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, backupFileMode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
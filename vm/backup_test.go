@@ -0,0 +1,150 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"bytes"
+	"context"
+	ejson "encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+func TestFetchBackup(t *testing.T) {
+	t.Parallel()
+
+	published := t.TempDir()
+	files := map[string][]byte{
+		backupMetadataFile: []byte(`{"height":1}`),
+		backupDataFile:     []byte("data"),
+	}
+	for name, b := range files {
+		if err := os.WriteFile(filepath.Join(published, name), b, backupFileMode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	srv := httptest.NewServer(http.FileServer(http.Dir(published)))
+	defer srv.Close()
+
+	dir := filepath.Join(t.TempDir(), "snapshot")
+	if err := FetchBackup(context.Background(), srv.URL+"/", dir); err != nil {
+		t.Fatal(err)
+	}
+	for name, expected := range files {
+		b, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b, expected) {
+			t.Fatalf("%s: expected %q, got %q", name, expected, b)
+		}
+	}
+
+	// Existing backups are never overwritten
+	if err := FetchBackup(context.Background(), srv.URL, dir); !errors.Is(err, os.ErrExist) {
+		t.Fatalf("expected %v, got %v", os.ErrExist, err)
+	}
+	if err := FetchBackup(context.Background(), srv.URL+"/missing", t.TempDir()); !errors.Is(err, ErrBackupUnavailable) {
+		t.Fatalf("expected %v, got %v", ErrBackupUnavailable, err)
+	}
+}
+
+func TestRestoreTruncatedBackup(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.New()
+	if err := db.Put([]byte("key"), []byte("value")); err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	var buf bytes.Buffer
+	entries, err := chain.ExportBackup(db, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backup := buf.Bytes()
+	if err := os.WriteFile(filepath.Join(dir, backupDataFile), backup[:len(backup)/2], backupFileMode); err != nil {
+		t.Fatal(err)
+	}
+	meta, err := ejson.Marshal(&BackupMetadata{Entries: entries})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, backupMetadataFile), meta, backupFileMode); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := memdb.New()
+	if _, err := RestoreBackup(restored, dir); !errors.Is(err, chain.ErrInvalidBackup) {
+		t.Fatalf("expected %v, got %v", chain.ErrInvalidBackup, err)
+	}
+	if has, err := chain.HasLastAccepted(restored); err != nil || has {
+		t.Fatalf("expected no last accepted block (err=%v)", err)
+	}
+	cursor := restored.NewIterator()
+	defer cursor.Release()
+	if cursor.Next() {
+		t.Fatalf("expected nothing to be restored, found %x", cursor.Key())
+	}
+
+	// A complete backup that doesn't verify is cleared too
+	if err := os.WriteFile(filepath.Join(dir, backupDataFile), backup, backupFileMode); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RestoreBackup(restored, dir); !errors.Is(err, chain.ErrNoLastAccepted) {
+		t.Fatalf("expected %v, got %v", chain.ErrNoLastAccepted, err)
+	}
+	if has, err := restored.Has([]byte("key")); err != nil || has {
+		t.Fatalf("expected unverified restore to be cleared (err=%v)", err)
+	}
+}
+
+func TestBackupPath(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+	vm := &VM{}
+	if _, err := vm.backupPath("latest"); !errors.Is(err, ErrBackupsDisabled) {
+		t.Fatalf("expected %v, got %v", ErrBackupsDisabled, err)
+	}
+
+	vm.config.BackupRoot = root
+	resolvedRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for dir, expected := range map[string]string{
+		"latest":                           filepath.Join(resolvedRoot, "latest"),
+		"daily/../latest":                  filepath.Join(resolvedRoot, "latest"),
+		filepath.Join(root, "nested", "a"): filepath.Join(resolvedRoot, "nested", "a"),
+	} {
+		resolved, err := vm.backupPath(dir)
+		if err != nil {
+			t.Fatalf("%s: %v", dir, err)
+		}
+		if resolved != expected {
+			t.Fatalf("%s: expected %s, got %s", dir, expected, resolved)
+		}
+	}
+	for _, dir := range []string{"../escape", outside, "link/backup"} {
+		if _, err := vm.backupPath(dir); !errors.Is(err, ErrBackupDirOutside) {
+			t.Fatalf("%s: expected %v, got %v", dir, ErrBackupDirOutside, err)
+		}
+	}
+	if _, err := vm.backupPath(""); !errors.Is(err, ErrBackupDirEmpty) {
+		t.Fatalf("expected %v, got %v", ErrBackupDirEmpty, err)
+	}
+}
@@ -38,8 +38,8 @@ func (vm *VM) SetBlockBuilder(b func() BlockBuilder) {
 
 	// Start new builder
 	vm.builder = b()
-	go vm.builder.Build()
-	go vm.builder.Gossip()
+	vm.workers.Go(vm.builder.Build)
+	vm.workers.Go(vm.builder.Gossip)
 }
 
 // buildingBlkStatus denotes the current status of the VM in block production.
@@ -47,6 +47,7 @@ type buildingBlkStatus uint8
 
 const (
 	dontBuild buildingBlkStatus = iota
+	batching
 	mayBuild
 	building
 )
@@ -60,6 +61,8 @@ type TimeBuilder struct {
 
 	// status signals the phase of block building the VM is currently in.
 	// [dontBuild] indicates there's no need to build a block.
+	// [batching] indicates the VM is waiting (up to [MaxBuildDelay]) for
+	// [MinBuildTxs] transactions before building a block.
 	// [mayBuild] indicates the VM should proceed to build a block.
 	// [building] indicates the VM has sent a request to the engine to build a block.
 	status buildingBlkStatus
@@ -69,7 +72,7 @@ type TimeBuilder struct {
 	// Stage2 build a block regardless of the size.
 	buildBlockTimer *timer.Timer
 
-	stop        chan struct{}
+	stop        <-chan struct{}
 	builderStop chan struct{}
 
 	doneBuild  chan struct{}
@@ -81,7 +84,7 @@ func (vm *VM) NewTimeBuilder() *TimeBuilder {
 		vm:          vm,
 		status:      dontBuild,
 		builderStop: vm.builderStop,
-		stop:        vm.stop,
+		stop:        vm.workers.Done(),
 		doneBuild:   vm.doneBuild,
 		doneGossip:  vm.doneGossip,
 	}
@@ -91,19 +94,42 @@ func (vm *VM) NewTimeBuilder() *TimeBuilder {
 
 // signalTxsReady sets the initial timeout on the two stage timer if the process
 // has not already begun from an earlier notification. If [buildStatus] is anything
-// other than [dontBuild], then the attempt has already begun and this notification
-// can be safely skipped.
+// other than [dontBuild] or [batching], then the attempt has already begun and
+// this notification can be safely skipped.
 func (b *TimeBuilder) signalTxsReady() {
 	b.l.Lock()
 	defer b.l.Unlock()
 
-	if b.status != dontBuild {
+	switch b.status {
+	case dontBuild:
+		if b.needToBatch() {
+			b.startBatching()
+			return
+		}
+	case batching:
+		if b.needToBatch() {
+			return
+		}
+	default:
 		return
 	}
 
 	b.markBuilding()
 }
 
+// needToBatch returns true if there are fewer than [MinBuildTxs] outstanding
+// transactions, so building should wait for more to arrive.
+func (b *TimeBuilder) needToBatch() bool {
+	return b.vm.mempool.Len() < b.vm.config.MinBuildTxs
+}
+
+// startBatching waits up to [MaxBuildDelay] for enough transactions to build a
+// block
+func (b *TimeBuilder) startBatching() {
+	b.status = batching
+	b.buildBlockTimer.SetTimeoutIn(b.vm.config.MaxBuildDelay)
+}
+
 // signal the avalanchego engine
 // to build a block from pending transactions
 func (b *TimeBuilder) markBuilding() {
@@ -123,12 +149,16 @@ func (b *TimeBuilder) HandleGenerateBlock() {
 	defer b.l.Unlock()
 
 	// If we still need to build a block immediately after building, we let the
-	// engine know it [mayBuild] in [buildInterval].
-	if b.needToBuild() {
+	// engine know it [mayBuild] in [buildInterval] (or wait for more
+	// transactions if there are too few to fill a block).
+	switch {
+	case !b.needToBuild():
+		b.status = dontBuild
+	case b.needToBatch():
+		b.startBatching()
+	default:
 		b.status = mayBuild
 		b.buildBlockTimer.SetTimeoutIn(b.vm.config.BuildInterval)
-	} else {
-		b.status = dontBuild
 	}
 }
 
@@ -148,7 +178,7 @@ func (b *TimeBuilder) buildBlockTwoStageTimer() (time.Duration, bool) {
 
 	switch b.status {
 	case dontBuild:
-	case mayBuild:
+	case batching, mayBuild:
 		b.markBuilding()
 	case building:
 		// If the status has already been set to building, there is no need
@@ -163,14 +193,50 @@ func (b *TimeBuilder) buildBlockTwoStageTimer() (time.Duration, bool) {
 	return 0, false
 }
 
+// signalHeartbeat asks the engine to build an empty block if no block has been
+// accepted for the genesis [HeartbeatInterval]
+func (b *TimeBuilder) signalHeartbeat() {
+	if !b.vm.workers.acquire(b.vm.ctx.Lock.RLock, b.vm.ctx.Lock.RUnlock) {
+		return
+	}
+	idle := b.vm.Now().Unix() - b.vm.lastAccepted.Tmstmp
+	b.vm.ctx.Lock.RUnlock()
+	if idle < b.vm.genesis.HeartbeatInterval {
+		return
+	}
+	log.Debug("signaling heartbeat", "idle", idle)
+
+	// Heartbeats are built regardless of [MinBuildTxs]
+	b.l.Lock()
+	defer b.l.Unlock()
+	if b.status == dontBuild || b.status == batching {
+		b.markBuilding()
+	}
+}
+
 func (b *TimeBuilder) Build() {
 	log.Debug("starting build loops")
 	defer close(b.doneBuild)
 
+	// The timer only fires while it is being dispatched
+	go b.buildBlockTimer.Dispatch()
+	defer b.buildBlockTimer.Stop()
+
+	// A nil channel is never ready, so heartbeats are only signaled when
+	// enabled
+	var heartbeat <-chan time.Time
+	if b.vm.config.BuildHeartbeats && b.vm.genesis.HeartbeatInterval > 0 {
+		t := time.NewTicker(time.Duration(b.vm.genesis.HeartbeatInterval) * time.Second)
+		defer t.Stop()
+		heartbeat = t.C
+	}
+
 	for {
 		select {
 		case <-b.vm.mempool.Pending:
 			b.signalTxsReady()
+		case <-heartbeat:
+			b.signalHeartbeat()
 		case <-b.builderStop:
 			return
 		case <-b.stop:
@@ -193,7 +259,7 @@ func (b *TimeBuilder) Gossip() {
 	for {
 		select {
 		case <-g.C:
-			newTxs := b.vm.mempool.NewTxs(b.vm.genesis.TargetBlockSize)
+			newTxs := b.vm.mempool.NewTxs(b.vm.network.gossipUnits())
 			_ = b.vm.network.GossipNewTxs(newTxs) // handles case where there are none
 		case <-rg.C:
 			_ = b.vm.network.RegossipTxs()
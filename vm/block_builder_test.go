@@ -0,0 +1,76 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/mempool"
+)
+
+func TestTimeBuilderMinBuildTxs(t *testing.T) {
+	g := chain.DefaultGenesis()
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	addTx := func(vm *VM, units uint64) {
+		utx := &chain.TransferTx{BaseTx: &chain.BaseTx{Price: 1}, Units: units}
+		dh, err := chain.DigestHash(utx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := chain.Sign(dh, priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx := chain.NewTx(utx, sig)
+		if err := tx.Init(g); err != nil {
+			t.Fatal(err)
+		}
+		vm.mempool.Add(tx)
+	}
+	newBuilder := func(maxBuildDelay time.Duration) (*VM, *TimeBuilder, chan common.Message) {
+		toEngine := make(chan common.Message, 1)
+		vm := &VM{
+			genesis:  g,
+			mempool:  mempool.New(g, 16),
+			toEngine: toEngine,
+			config:   Config{MinBuildTxs: 2, MaxBuildDelay: maxBuildDelay},
+			workers:  newLifecycle(),
+		}
+		b := vm.NewTimeBuilder()
+		go b.buildBlockTimer.Dispatch()
+		t.Cleanup(b.buildBlockTimer.Stop)
+		return vm, b, toEngine
+	}
+
+	// Building waits for enough txs
+	vm, b, toEngine := newBuilder(time.Hour)
+	addTx(vm, 1)
+	b.signalTxsReady()
+	if b.status != batching || len(toEngine) != 0 {
+		t.Fatalf("expected to wait for more txs, status=%d", b.status)
+	}
+	addTx(vm, 2)
+	b.signalTxsReady()
+	if b.status != building || len(toEngine) != 1 {
+		t.Fatalf("expected to build once enough txs are pending, status=%d", b.status)
+	}
+
+	// Building proceeds after [MaxBuildDelay] even without enough txs
+	vm, b, toEngine = newBuilder(10 * time.Millisecond)
+	addTx(vm, 1)
+	b.signalTxsReady()
+	select {
+	case <-toEngine:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected to build after the max build delay")
+	}
+}
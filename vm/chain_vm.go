@@ -4,8 +4,12 @@
 package vm
 
 import (
+	"time"
+
 	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+	"github.com/ethereum/go-ethereum/common"
 	log "github.com/inconshreveable/log15"
 
 	"github.com/ava-labs/spacesvm/chain"
@@ -15,6 +19,10 @@ func (vm *VM) Genesis() *chain.Genesis {
 	return vm.genesis
 }
 
+func (vm *VM) Verifier() *chain.Verifier {
+	return vm.verifier
+}
+
 func (vm *VM) IsBootstrapped() bool {
 	return vm.bootstrapped.GetValue()
 }
@@ -27,10 +35,31 @@ func (vm *VM) Mempool() chain.Mempool {
 	return vm.mempool
 }
 
+func (vm *VM) ChainID() ids.ID {
+	return vm.ctx.ChainID
+}
+
+func (vm *VM) Now() time.Time {
+	return vm.clock.Time()
+}
+
+func (vm *VM) Beneficiary() common.Address {
+	return vm.config.Beneficiary
+}
+
+// Clock returns the clock used by the VM so that tests can control the
+// passage of time. [Config.ClockOffset] is applied to the time it is set to.
+func (vm *VM) Clock() *mockable.Clock {
+	return &vm.clock.Clock
+}
+
 func (vm *VM) Verified(b *chain.StatelessBlock) {
 	vm.verifiedBlocks[b.ID()] = b
 	for _, tx := range b.Txs {
-		_ = vm.mempool.Remove(tx.ID())
+		// Txs included by the block builder were already dropped
+		if vm.mempool.Has(tx.ID()) {
+			vm.mempool.Drop(tx.ID(), chain.DropIncluded)
+		}
 	}
 	log.Debug("verified block", "id", b.ID(), "parent", b.Prnt)
 }
@@ -48,6 +77,10 @@ func (vm *VM) Accepted(b *chain.StatelessBlock) {
 	delete(vm.verifiedBlocks, b.ID())
 	vm.lastAccepted = b
 	log.Debug("accepted block", "blkID", b.ID())
+	vm.blockStats.add(b)
+	vm.checkPreference(b)
+	vm.notifyAcceptors(b)
+	vm.notifyAcceptHooks(b)
 
 	if vm.config.ActivityCacheSize == 0 {
 		return
@@ -63,6 +96,44 @@ func (vm *VM) Accepted(b *chain.StatelessBlock) {
 			vm.activityCacheCursor++
 		}
 	}
+	if b.BuilderReward != nil {
+		vm.activityCache[vm.activityCacheCursor%cs] = b.BuilderReward
+		vm.activityCacheCursor++
+	}
+}
+
+// checkPreference resets the preferred block to [accepted] if the preferred
+// block is no longer a descendant of it. Otherwise, blocks would be built on
+// (and submissions checked against) a branch that can never be accepted. The
+// transactions in the abandoned branch are returned to the mempool when its
+// blocks are rejected.
+func (vm *VM) checkPreference(accepted *chain.StatelessBlock) {
+	if vm.isDescendant(vm.preferred, accepted) {
+		return
+	}
+	log.Warn("preferred block left the canonical chain",
+		"preferred", vm.preferred,
+		"lastAccepted", accepted.ID(),
+	)
+	vm.preferred = accepted.ID()
+}
+
+// isDescendant returns true if [blkID] is [ancestor] or one of its
+// descendants
+func (vm *VM) isDescendant(blkID ids.ID, ancestor *chain.StatelessBlock) bool {
+	for {
+		if blkID == ancestor.ID() {
+			return true
+		}
+		blk, err := vm.GetStatelessBlock(blkID)
+		if err != nil {
+			return false
+		}
+		if blk.Hght <= ancestor.Hght {
+			return false
+		}
+		blkID = blk.Prnt
+	}
 }
 
 func (vm *VM) ExecutionContext(currTime int64, lastBlock *chain.StatelessBlock) (*chain.Context, error) {
@@ -117,6 +188,9 @@ func (vm *VM) ExecutionContext(currTime int64, lastBlock *chain.StatelessBlock)
 	}
 
 	return &chain.Context{
+		ChainID: vm.ctx.ChainID,
+		Atomic:  vm.Atomic(),
+
 		RecentBlockIDs:  recentBlockIDs,
 		RecentTxIDs:     recentTxIDs,
 		RecentLoadUnits: recentUnits,
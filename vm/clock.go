@@ -0,0 +1,22 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/utils/timer/mockable"
+)
+
+// offsetClock is a [mockable.Clock] shifted by [offset] (see
+// [Config.ClockOffset]). The offset also applies while the clock is faked.
+type offsetClock struct {
+	mockable.Clock
+	offset time.Duration
+}
+
+// Time returns the time of the underlying clock shifted by [offset]
+func (c *offsetClock) Time() time.Time {
+	return c.Clock.Time().Add(c.offset)
+}
@@ -0,0 +1,26 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClockOffset(t *testing.T) {
+	vm := &VM{}
+	vm.Clock().Set(time.Unix(100, 0))
+	if now := vm.Now(); !now.Equal(time.Unix(100, 0)) {
+		t.Fatalf("expected 100, got %d", now.Unix())
+	}
+
+	vm.clock.offset = time.Hour
+	if now := vm.Now(); !now.Equal(time.Unix(3700, 0)) {
+		t.Fatalf("expected 3700, got %d", now.Unix())
+	}
+	vm.Clock().Sync()
+	if d := time.Until(vm.Now()); d < 59*time.Minute || d > time.Hour {
+		t.Fatalf("expected the clock to run an hour ahead, got %s", d)
+	}
+}
@@ -6,33 +6,85 @@ package vm
 import (
 	"time"
 
+	"github.com/ava-labs/avalanchego/ids"
 	log "github.com/inconshreveable/log15"
 
 	"github.com/ava-labs/spacesvm/chain"
 )
 
-func (vm *VM) compactCall(r *chain.CompactRange) {
-	// Lock to prevent concurrent modification of state
-	vm.ctx.Lock.Lock()
-	defer vm.ctx.Lock.Unlock()
+// how long to wait before retrying a compaction skipped because the VM was
+// busy
+const compactRetryInterval = 5 * time.Second
 
+// busy returns true if the VM has pending transactions or blocks that have
+// not yet been decided. Assumes ctx.Lock is held.
+func (vm *VM) busy() bool {
+	return vm.mempool.Len() > 0 || len(vm.verifiedBlocks) > 0
+}
+
+// compactRange compacts [r] and updates metrics. Assumes ctx.Lock is held.
+func (vm *VM) compactRange(r *chain.CompactRange) error {
 	start := time.Now()
 	if err := vm.db.Compact(r.Start, r.Limit); err != nil {
-		log.Error("unable to compact range", "start", r.Start, "stop", r.Limit)
-		return
+		log.Error("unable to compact range", "start", r.Start, "stop", r.Limit, "error", err)
+		vm.metrics.compactFailures.Inc()
+		return err
+	}
+	d := time.Since(start)
+	log.Debug("compacted range", "start", r.Start, "stop", r.Limit, "t", d)
+	vm.metrics.compactions.Inc()
+	vm.metrics.compactDuration.Observe(d.Seconds())
+
+	// Space values removed by pruning are only reclaimed once the key range
+	// is compacted
+	if r.Contains(chain.SpaceValueKey(ids.ShortEmpty, nil)) && vm.unreclaimedKeys > 0 {
+		log.Debug("reclaimed pruned keys", "keys", vm.unreclaimedKeys)
+		vm.metrics.reclaimedKeys.Add(float64(vm.unreclaimedKeys))
+		vm.unreclaimedKeys = 0
 	}
-	log.Debug("compacted range", "start", r.Start, "stop", r.Limit, "t", time.Since(start))
 
 	// Make sure to update children or else won't be persisted
 	if err := vm.lastAccepted.SetChildrenDB(vm.db); err != nil {
 		log.Error("unable to update child databases of last accepted block", "error", err)
-		return
+		return err
+	}
+	return nil
+}
+
+// compactCall compacts [r] unless the VM is busy and [CompactWhenIdle] is
+// set. It returns false if compaction was skipped.
+func (vm *VM) compactCall(r *chain.CompactRange) bool {
+	// Lock to prevent concurrent modification of state
+	if !vm.workers.acquire(vm.ctx.Lock.Lock, vm.ctx.Lock.Unlock) {
+		return false
+	}
+	defer vm.ctx.Lock.Unlock()
+
+	if vm.config.CompactWhenIdle && vm.busy() {
+		log.Debug("deferring compaction because vm is busy", "start", r.Start, "stop", r.Limit)
+		vm.metrics.compactSkipped.Inc()
+		return false
+	}
+	_ = vm.compactRange(r)
+	return true
+}
+
+// CompactAll compacts every range in [chain.CompactRanges] immediately,
+// regardless of whether the VM is busy.
+func (vm *VM) CompactAll() (int, error) {
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	for i, r := range chain.CompactRanges {
+		if err := vm.compactRange(r); err != nil {
+			return i, err
+		}
 	}
+	return len(chain.CompactRanges), nil
 }
 
 func (vm *VM) compact() {
 	log.Debug("starting compaction loops")
-	defer close(vm.doneCompact)
 
 	t := time.NewTimer(vm.config.CompactInterval)
 	defer t.Stop()
@@ -48,13 +100,17 @@ func (vm *VM) compact() {
 	for {
 		select {
 		case <-t.C:
-		case <-vm.stop:
+		case <-vm.workers.Done():
 			return
 		}
 
 		// Compact next range
 		prefix := ranges[currentRange]
-		vm.compactCall(prefix)
+		if !vm.compactCall(prefix) {
+			// Retry the same range once the VM is idle
+			t.Reset(compactRetryInterval)
+			continue
+		}
 
 		// Update range compaction index
 		currentRange++
@@ -0,0 +1,75 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// responses smaller than this are not worth compressing
+const minCompressSize = 1024
+
+// gzipHandler compresses responses of [h] when the client advertises gzip
+// support. Responses are buffered so that small responses (which would grow
+// when compressed) can be sent as-is.
+func gzipHandler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Encoding")
+		if !acceptsGzip(r) {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		bw := &bufferedResponseWriter{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(bw, r)
+
+		body := bw.buf.Bytes()
+		if len(body) < minCompressSize || len(w.Header().Get("Content-Encoding")) > 0 {
+			w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+			w.WriteHeader(bw.status)
+			_, _ = w.Write(body)
+			return
+		}
+
+		var compressed bytes.Buffer
+		gw := gzip.NewWriter(&compressed)
+		if _, err := gw.Write(body); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := gw.Close(); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Set("Content-Length", strconv.Itoa(compressed.Len()))
+		w.WriteHeader(bw.status)
+		_, _ = w.Write(compressed.Bytes())
+	})
+}
+
+func acceptsGzip(r *http.Request) bool {
+	for _, enc := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		// Ignore any quality values (ex: "gzip;q=1.0")
+		enc = strings.TrimSpace(strings.SplitN(enc, ";", 2)[0])
+		if enc == "gzip" || enc == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+type bufferedResponseWriter struct {
+	http.ResponseWriter
+	buf    bytes.Buffer
+	status int
+}
+
+func (w *bufferedResponseWriter) WriteHeader(status int) { w.status = status }
+
+func (w *bufferedResponseWriter) Write(b []byte) (int, error) { return w.buf.Write(b) }
@@ -0,0 +1,64 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGzipHandler(t *testing.T) {
+	large := bytes.Repeat([]byte("a"), 2*minCompressSize)
+	small := []byte("ok")
+	h := gzipHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/large" {
+			_, _ = w.Write(large)
+			return
+		}
+		_, _ = w.Write(small)
+	}))
+
+	tt := []struct {
+		path     string
+		encoding string
+		gzipped  bool
+		body     []byte
+	}{
+		{path: "/large", encoding: "gzip, deflate", gzipped: true, body: large},
+		{path: "/large", encoding: "", gzipped: false, body: large},
+		{path: "/small", encoding: "gzip", gzipped: false, body: small},
+	}
+	for _, tv := range tt {
+		req := httptest.NewRequest(http.MethodPost, tv.path, nil)
+		if len(tv.encoding) > 0 {
+			req.Header.Set("Accept-Encoding", tv.encoding)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+
+		var body io.Reader = rec.Body
+		gzipped := rec.Header().Get("Content-Encoding") == "gzip"
+		if gzipped != tv.gzipped {
+			t.Fatalf("%s: expected gzipped=%t", tv.path, tv.gzipped)
+		}
+		if gzipped {
+			gr, err := gzip.NewReader(rec.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body = gr
+		}
+		b, err := io.ReadAll(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b, tv.body) {
+			t.Fatalf("%s: unexpected body", tv.path)
+		}
+	}
+}
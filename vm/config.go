@@ -4,35 +4,225 @@
 package vm
 
 import (
+	"encoding/json"
+	"net/http"
+	"runtime"
 	"time"
+
+	"github.com/ava-labs/avalanchego/utils/units"
+	"github.com/ethereum/go-ethereum/common"
 )
 
 type Config struct {
 	BuildInterval    time.Duration `serialize:"true" json:"buildInterval"`
 	GossipInterval   time.Duration `serialize:"true" json:"gossipInterval"`
 	RegossipInterval time.Duration `serialize:"true" json:"regossipInterval"`
+	// GossipMaxUnits caps the load units of the txs gossiped at once (the
+	// highest paying per unit first). Zero gossips up to the target block
+	// size of the genesis.
+	GossipMaxUnits uint64 `serialize:"true" json:"gossipMaxUnits"`
+	// GossipFanout is the number of random peers each gossip message is sent
+	// to. Zero sends it to every peer.
+	GossipFanout int `serialize:"true" json:"gossipFanout"`
+	// GossipMaxTxs splits gossip into messages of at most that many txs. Zero
+	// sends every tx gossiped at once in a single message.
+	GossipMaxTxs int `serialize:"true" json:"gossipMaxTxs"`
+	// GossipMaxResends is the number of times a tx may be regossiped after it
+	// was first gossiped (while it is remembered). Txs that reach it are
+	// dropped by regossip without being sent. Zero resends without limit.
+	GossipMaxResends int `serialize:"true" json:"gossipMaxResends"`
+
+	// MinBuildTxs is the number of pending transactions required before a
+	// block is proposed. If fewer are pending, block production waits up to
+	// MaxBuildDelay for more to arrive, so low-traffic networks build fewer,
+	// fuller blocks. Batching is disabled when MinBuildTxs is at most 1.
+	MinBuildTxs   int           `serialize:"true" json:"minBuildTxs"`
+	MaxBuildDelay time.Duration `serialize:"true" json:"maxBuildDelay"`
+
+	// BuildHeartbeats builds empty blocks whenever the chain has been idle for
+	// the genesis [HeartbeatInterval], so timestamps and expiry advance
+	// without transactions
+	BuildHeartbeats bool `serialize:"true" json:"buildHeartbeats"`
+
+	// ClockOffset shifts the time the node builds and verifies blocks at
+	// (ex: to reach expiry sooner on a devnet). Every node of a network must
+	// use the same offset, or they reject each other's blocks as too far in
+	// the future.
+	ClockOffset time.Duration `serialize:"true" json:"clockOffset"`
+
+	// StaleBlockAge is how old (by timestamp) a processing block that is not
+	// an ancestor of the preferred block can get before it is considered
+	// stale and checked with pruning (see [VM.releaseStaleBlocks]). Zero
+	// disables the check.
+	StaleBlockAge time.Duration `serialize:"true" json:"staleBlockAge"`
 
 	PruneLimit        int           `serialize:"true" json:"pruneLimit"`
 	PruneInterval     time.Duration `serialize:"true" json:"pruneInterval"`
 	FullPruneInterval time.Duration `serialize:"true" json:"fullPruneInterval"`
+	// DeletedValueRetention is the number of blocks the bytes of deleted and
+	// overwritten values are kept after the block that removed them was
+	// accepted (0 reclaims them as soon as it is accepted). Reclaimed values
+	// can no longer be restored in the blocks that set them, so nodes that
+	// serve historical blocks should keep values forever (when negative).
+	DeletedValueRetention int64 `serialize:"true" json:"deletedValueRetention"`
+	// IndexRetention is how long the tx index and the space and sender
+	// history of accepted blocks are kept before they are trimmed with
+	// pruning. Zero keeps them forever (for archive nodes) and a negative
+	// value trims them as soon as their block is accepted.
+	IndexRetention time.Duration `serialize:"true" json:"indexRetention"`
 
 	CompactInterval time.Duration `serialize:"true" json:"compactInterval"`
+	// CompactWhenIdle defers scheduled compaction while there are pending
+	// transactions or blocks being processed
+	CompactWhenIdle bool `serialize:"true" json:"compactWhenIdle"`
 
 	MempoolSize       int `serialize:"true" json:"mempoolSize"`
 	ActivityCacheSize int `serialize:"true" json:"activityCacheSize"`
+	// DroppedTxRecords is the number of txs that left the mempool (other
+	// than by being included in a block) whose drop reason is persisted, so
+	// it can be looked up after the mempool has forgotten it. Zero disables
+	// the records.
+	DroppedTxRecords uint64 `serialize:"true" json:"droppedTxRecords"`
+
+	// VerificationWorkers is the number of workers that recover the senders
+	// of transactions for mempool admission, gossip, and block verification
+	// (shared by all three, so a busy node does not oversubscribe its CPU)
+	VerificationWorkers int `serialize:"true" json:"verificationWorkers"`
+
+	// IntegrityCheck verifies (and repairs) database indexes at startup if
+	// the node did not shut down cleanly
+	IntegrityCheck bool `serialize:"true" json:"integrityCheck"`
+
+	// IntegrityCheckDepth is the number of recent accepted blocks whose txs
+	// are checked against the tx index by the integrity check when indexes
+	// are kept forever. Every block within IndexRetention is checked
+	// otherwise.
+	IntegrityCheckDepth uint64 `serialize:"true" json:"integrityCheckDepth"`
+
+	// CompressResponses gzips API responses for clients that support it
+	CompressResponses bool `serialize:"true" json:"compressResponses"`
+
+	// CORSAllowedOrigins are the origins browsers may call the API from ("*"
+	// allows any origin). CORS is disabled when empty.
+	CORSAllowedOrigins []string `serialize:"true" json:"corsAllowedOrigins"`
+	CORSAllowedMethods []string `serialize:"true" json:"corsAllowedMethods"`
+
+	// AuthTokens are bearer tokens that must be provided to issue
+	// transactions or call admin methods. Reads are always open. Auth is
+	// disabled when empty.
+	AuthTokens []string `serialize:"true" json:"authTokens"`
+
+	// ReadRateLimit and WriteRateLimit are the number of read and write
+	// (transaction issuing and admin) requests per second each client, as
+	// identified by its bearer token or IP, may make. Limiting is disabled
+	// when zero.
+	ReadRateLimit  float64 `serialize:"true" json:"readRateLimit"`
+	ReadRateBurst  int     `serialize:"true" json:"readRateBurst"`
+	WriteRateLimit float64 `serialize:"true" json:"writeRateLimit"`
+	WriteRateBurst int     `serialize:"true" json:"writeRateBurst"`
+
+	// SenderRateLimit is the number of transactions per second each sender
+	// may submit to this node, through the API or gossip. Transactions over
+	// the limit are rejected before execution. Limiting is disabled when zero.
+	SenderRateLimit float64 `serialize:"true" json:"senderRateLimit"`
+	SenderRateBurst int     `serialize:"true" json:"senderRateBurst"`
+
+	// DeniedSpaces are spaces (exact names or patterns like "spam*") whose
+	// values this node refuses to serve and whose transactions it refuses to
+	// admit to its mempool (and therefore to gossip or build blocks with).
+	// Blocks from other nodes that include them are still accepted.
+	DeniedSpaces []string `serialize:"true" json:"deniedSpaces"`
+
+	// MaxRequestBytes, RequestTimeout, and MaxConcurrentRequests bound the
+	// resources API requests may use. Each limit is disabled when zero.
+	MaxRequestBytes       int64         `serialize:"true" json:"maxRequestBytes"`
+	RequestTimeout        time.Duration `serialize:"true" json:"requestTimeout"`
+	MaxConcurrentRequests int           `serialize:"true" json:"maxConcurrentRequests"`
+	// SlowRequestThreshold logs every API request that takes at least this
+	// long to serve. Logging is disabled when zero.
+	SlowRequestThreshold time.Duration `serialize:"true" json:"slowRequestThreshold"`
+
+	// MinConnectedValidators is the fraction (0-1) of the subnet's
+	// validators the node must be connected to (counting itself) to report
+	// healthy, so network partitions are surfaced by the health API. The
+	// check is disabled when zero.
+	MinConnectedValidators float64 `serialize:"true" json:"minConnectedValidators"`
+
+	// AcceptHooks enables the accept hooks registered (see
+	// [RegisterAcceptHook]) under each name, passing them the value as their
+	// config
+	AcceptHooks map[string]json.RawMessage `serialize:"true" json:"acceptHooks"`
+
+	// Webhooks are POSTed the changes of each accepted block under their
+	// prefix. Failed deliveries are retried up to WebhookRetries times,
+	// waiting WebhookBackoff (doubled after each attempt) in between.
+	Webhooks       []*WebhookConfig `serialize:"true" json:"webhooks"`
+	WebhookRetries int              `serialize:"true" json:"webhookRetries"`
+	WebhookBackoff time.Duration    `serialize:"true" json:"webhookBackoff"`
+
+	// AdminAPIEnabled serves the admin API at [AdminEndpoint]
+	AdminAPIEnabled bool `serialize:"true" json:"adminAPIEnabled"`
+
+	// Beneficiary is credited with a share of the fees of the blocks this node
+	// builds (no reward is claimed when empty)
+	Beneficiary common.Address `serialize:"true" json:"beneficiary"`
+
+	// ResponseKey is a hex-encoded secp256k1 private key used to sign resolve
+	// responses, so clients can detect responses tampered with by untrusted
+	// intermediaries. Responses are unsigned when empty.
+	ResponseKey string `serialize:"true" json:"responseKey"`
+
+	// IPFSGateway (such as "https://ipfs.io") enables the [GatewayEndpoint],
+	// which redirects reads of IPFS values to the content on the gateway.
+	// The endpoint is disabled when empty.
+	IPFSGateway string `serialize:"true" json:"ipfsGateway"`
+	// SitesEnabled serves the files synced into each space (see
+	// `spaces-cli sync`) as a static website at [SiteEndpoint].
+	SitesEnabled bool `serialize:"true" json:"sitesEnabled"`
+
+	// BackupRoot is the directory admin backups and exports are written
+	// under. Directories passed to the admin API are relative to it and may
+	// not resolve outside of it. Admin backups and exports are disabled when
+	// it is empty.
+	BackupRoot string `serialize:"true" json:"backupRoot"`
+	// RestoreDir is a backup directory to restore from when the database is
+	// empty
+	RestoreDir string `serialize:"true" json:"restoreDir"`
+	// ImportDir is a block export (see `spaces-cli admin export`) to replay
+	// when the database is empty, after restoring [RestoreDir] (if set)
+	ImportDir string `serialize:"true" json:"importDir"`
 }
 
 func (c *Config) SetDefaults() {
 	c.BuildInterval = 500 * time.Millisecond
 	c.GossipInterval = 1 * time.Second
 	c.RegossipInterval = 30 * time.Second
+	c.MaxBuildDelay = 5 * time.Second
 
 	c.PruneLimit = 128
 	c.PruneInterval = time.Minute
 	c.FullPruneInterval = time.Second
+	c.StaleBlockAge = 5 * time.Minute
+	c.DeletedValueRetention = -1
 
 	c.CompactInterval = 1 * time.Minute
+	c.CompactWhenIdle = true
 
 	c.MempoolSize = 1024
+	c.DroppedTxRecords = 16384
 	c.ActivityCacheSize = 128
+	c.VerificationWorkers = runtime.NumCPU()
+
+	c.IntegrityCheck = true
+	c.IntegrityCheckDepth = 1024
+	c.CompressResponses = true
+	c.CORSAllowedMethods = []string{http.MethodPost, http.MethodOptions}
+	c.ReadRateBurst = 32
+	c.WriteRateBurst = 8
+	c.SenderRateBurst = 16
+	c.MaxRequestBytes = 2 * units.MiB
+	c.RequestTimeout = 30 * time.Second
+	c.MaxConcurrentRequests = 128
+	c.WebhookRetries = 5
+	c.WebhookBackoff = time.Second
 }
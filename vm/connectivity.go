@@ -0,0 +1,169 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+
+	"github.com/ava-labs/avalanchego/ids"
+)
+
+// peerTracker is the set of peers the node is connected to and the VM
+// versions they reported in their handshake (see [handshake]).
+type peerTracker struct {
+	l     sync.RWMutex
+	peers map[ids.NodeID]*handshake
+}
+
+func newPeerTracker() *peerTracker {
+	return &peerTracker{peers: map[ids.NodeID]*handshake{}}
+}
+
+func (p *peerTracker) connect(id ids.NodeID) int {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	if _, ok := p.peers[id]; !ok {
+		p.peers[id] = nil
+	}
+	return len(p.peers)
+}
+
+func (p *peerTracker) disconnect(id ids.NodeID) int {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	delete(p.peers, id)
+	return len(p.peers)
+}
+
+// setHandshake records the handshake of [id], if it is still connected.
+func (p *peerTracker) setHandshake(id ids.NodeID, h *handshake) {
+	p.l.Lock()
+	defer p.l.Unlock()
+
+	if _, ok := p.peers[id]; ok {
+		p.peers[id] = h
+	}
+}
+
+func (p *peerTracker) len() int {
+	p.l.RLock()
+	defer p.l.RUnlock()
+
+	return len(p.peers)
+}
+
+func (p *peerTracker) connected(id ids.NodeID) bool {
+	p.l.RLock()
+	defer p.l.RUnlock()
+
+	_, ok := p.peers[id]
+	return ok
+}
+
+// versions returns the number of peers running each VM version (peers that
+// have not completed a handshake are counted as [unknownVersion]) and the
+// number of peers running an incompatible codec.
+func (p *peerTracker) versions() (map[string]int, int) {
+	p.l.RLock()
+	defer p.l.RUnlock()
+
+	versions := map[string]int{}
+	incompatible := 0
+	for _, h := range p.peers {
+		if h == nil {
+			versions[unknownVersion]++
+			continue
+		}
+		versions[h.Version]++
+		if !h.compatible() {
+			incompatible++
+		}
+	}
+	return versions, incompatible
+}
+
+// gossipTargets returns every peer that is not known to run an incompatible
+// codec, sampled down to [fanout] peers (if positive), or false if there are
+// no such peers to exclude.
+func (p *peerTracker) gossipTargets(fanout int) (ids.NodeIDSet, bool) {
+	p.l.RLock()
+	defer p.l.RUnlock()
+
+	compatible := make([]ids.NodeID, 0, len(p.peers))
+	for id, h := range p.peers {
+		if h == nil || h.compatible() {
+			compatible = append(compatible, id)
+		}
+	}
+	if fanout > 0 && fanout < len(compatible) {
+		rand.Shuffle(len(compatible), func(i, j int) { //nolint:gosec
+			compatible[i], compatible[j] = compatible[j], compatible[i]
+		})
+		compatible = compatible[:fanout]
+	}
+	targets := ids.NewNodeIDSet(len(compatible))
+	targets.Add(compatible...)
+	return targets, targets.Len() < len(p.peers)
+}
+
+// Connectivity is the health check result of the VM.
+type Connectivity struct {
+	Peers int `json:"peers"`
+	// Validators of the subnet at the current P-Chain height (zero when the
+	// validator set is unavailable)
+	Validators int `json:"validators"`
+	// ConnectedValidators includes this node if it is a validator
+	ConnectedValidators int `json:"connectedValidators"`
+	// Versions counts the peers running each VM version
+	Versions map[string]int `json:"versions"`
+	// IncompatiblePeers run a codec this node cannot parse and are not
+	// gossiped to
+	IncompatiblePeers int `json:"incompatiblePeers"`
+}
+
+// connectivity counts the peers and subnet validators the node is connected
+// to.
+func (vm *VM) connectivity() (*Connectivity, error) {
+	c := &Connectivity{Peers: vm.peers.len()}
+	c.Versions, c.IncompatiblePeers = vm.peers.versions()
+	state := vm.ctx.ValidatorState
+	if state == nil {
+		return c, nil
+	}
+	height, err := state.GetCurrentHeight()
+	if err != nil {
+		return nil, err
+	}
+	validators, err := state.GetValidatorSet(height, vm.ctx.SubnetID)
+	if err != nil {
+		return nil, err
+	}
+	c.Validators = len(validators)
+	for id := range validators {
+		if id == vm.ctx.NodeID || vm.peers.connected(id) {
+			c.ConnectedValidators++
+		}
+	}
+	return c, nil
+}
+
+// checkConnectivity returns an error if the node is connected to fewer than
+// [Config.MinConnectedValidators] of the subnet's validators.
+func (vm *VM) checkConnectivity(c *Connectivity) error {
+	min := vm.config.MinConnectedValidators
+	if min <= 0 || c.Validators == 0 {
+		return nil
+	}
+	if float64(c.ConnectedValidators) < min*float64(c.Validators) {
+		return fmt.Errorf(
+			"%w: connected to %d/%d validators (min=%.2f)",
+			ErrInsufficientConnectivity, c.ConnectedValidators, c.Validators, min,
+		)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+)
+
+type testValidatorState struct {
+	validators map[ids.NodeID]uint64
+}
+
+func (s *testValidatorState) GetMinimumHeight() (uint64, error) { return 0, nil }
+func (s *testValidatorState) GetCurrentHeight() (uint64, error) { return 1, nil }
+func (s *testValidatorState) GetValidatorSet(uint64, ids.ID) (map[ids.NodeID]uint64, error) {
+	return s.validators, nil
+}
+
+func TestConnectivity(t *testing.T) {
+	t.Parallel()
+
+	self, v1, v2, v3, peer := ids.GenerateTestNodeID(), ids.GenerateTestNodeID(),
+		ids.GenerateTestNodeID(), ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	ctx := snow.DefaultContextTest()
+	ctx.NodeID = self
+	ctx.ValidatorState = &testValidatorState{
+		validators: map[ids.NodeID]uint64{self: 1, v1: 1, v2: 1, v3: 1},
+	}
+	vm := &VM{ctx: ctx, peers: newPeerTracker()}
+	vm.config.MinConnectedValidators = 0.75
+
+	vm.peers.connect(v1)
+	vm.peers.connect(peer)
+	c, err := vm.connectivity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Peers != 2 || c.Validators != 4 || c.ConnectedValidators != 2 {
+		t.Fatalf("unexpected connectivity %+v", c)
+	}
+	if err := vm.checkConnectivity(c); !errors.Is(err, ErrInsufficientConnectivity) {
+		t.Fatalf("expected %v, got %v", ErrInsufficientConnectivity, err)
+	}
+
+	vm.peers.connect(v2)
+	c, err = vm.connectivity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.ConnectedValidators != 3 {
+		t.Fatalf("expected 3 connected validators, got %d", c.ConnectedValidators)
+	}
+	if err := vm.checkConnectivity(c); err != nil {
+		t.Fatal(err)
+	}
+
+	vm.peers.disconnect(v1)
+	c, err = vm.connectivity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.checkConnectivity(c); !errors.Is(err, ErrInsufficientConnectivity) {
+		t.Fatalf("expected %v, got %v", ErrInsufficientConnectivity, err)
+	}
+
+	vm.config.MinConnectedValidators = 0
+	if err := vm.checkConnectivity(c); err != nil {
+		t.Fatalf("disabled check should pass, got %v", err)
+	}
+}
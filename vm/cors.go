@@ -0,0 +1,50 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"net/http"
+	"strings"
+)
+
+// corsHandler adds CORS headers to responses of [h] for requests from
+// [origins] ("*" allows any origin) and answers preflight requests.
+func corsHandler(h http.Handler, origins []string, methods []string) http.Handler {
+	allowAny := false
+	allowed := map[string]struct{}{}
+	for _, o := range origins {
+		if o == "*" {
+			allowAny = true
+		}
+		allowed[o] = struct{}{}
+	}
+	allowMethods := strings.Join(methods, ", ")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		if len(origin) == 0 {
+			h.ServeHTTP(w, r)
+			return
+		}
+		w.Header().Add("Vary", "Origin")
+		_, ok := allowed[origin]
+		if !ok && !allowAny {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		if allowAny {
+			w.Header().Set("Access-Control-Allow-Origin", "*")
+		} else {
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+		}
+		if r.Method == http.MethodOptions && len(r.Header.Get("Access-Control-Request-Method")) > 0 {
+			w.Header().Set("Access-Control-Allow-Methods", allowMethods)
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
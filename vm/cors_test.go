@@ -0,0 +1,53 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCORSHandler(t *testing.T) {
+	h := corsHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), []string{"https://tryspaces.xyz"}, []string{http.MethodPost})
+
+	tt := []struct {
+		method       string
+		origin       string
+		preflight    bool
+		status       int
+		allowOrigin  string
+		allowMethods string
+	}{
+		{method: http.MethodPost, status: http.StatusOK},
+		{method: http.MethodPost, origin: "https://evil.xyz", status: http.StatusOK},
+		{method: http.MethodPost, origin: "https://tryspaces.xyz", status: http.StatusOK, allowOrigin: "https://tryspaces.xyz"},
+		{
+			method: http.MethodOptions, origin: "https://tryspaces.xyz", preflight: true,
+			status: http.StatusNoContent, allowOrigin: "https://tryspaces.xyz", allowMethods: http.MethodPost,
+		},
+	}
+	for i, tv := range tt {
+		req := httptest.NewRequest(tv.method, "/", nil)
+		if len(tv.origin) > 0 {
+			req.Header.Set("Origin", tv.origin)
+		}
+		if tv.preflight {
+			req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tv.status {
+			t.Fatalf("%d: expected status %d, got %d", i, tv.status, rec.Code)
+		}
+		if o := rec.Header().Get("Access-Control-Allow-Origin"); o != tv.allowOrigin {
+			t.Fatalf("%d: expected allowed origin %q, got %q", i, tv.allowOrigin, o)
+		}
+		if m := rec.Header().Get("Access-Control-Allow-Methods"); m != tv.allowMethods {
+			t.Fatalf("%d: expected allowed methods %q, got %q", i, tv.allowMethods, m)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// denylist matches the spaces this node refuses to serve values from or
+// admit transactions for. Blocks containing denied spaces are still verified
+// and accepted, so the denylist never affects consensus.
+type denylist struct {
+	exact    map[string]struct{}
+	patterns []string
+}
+
+// newDenylist returns a [denylist] of [entries], which are either exact space
+// names or [path.Match] patterns (such as "spam*"). It returns nil if
+// [entries] is empty.
+func newDenylist(entries []string) (*denylist, error) {
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	d := &denylist{exact: map[string]struct{}{}}
+	for _, e := range entries {
+		if !strings.ContainsAny(e, `*?[\`) {
+			d.exact[e] = struct{}{}
+			continue
+		}
+		if _, err := path.Match(e, ""); err != nil {
+			return nil, fmt.Errorf("%w: invalid denylist pattern %q", err, e)
+		}
+		d.patterns = append(d.patterns, e)
+	}
+	return d, nil
+}
+
+// denied returns true if [space] is on the denylist
+func (d *denylist) denied(space string) bool {
+	if d == nil || len(space) == 0 {
+		return false
+	}
+	if _, ok := d.exact[space]; ok {
+		return true
+	}
+	for _, p := range d.patterns {
+		if ok, _ := path.Match(p, space); ok {
+			return true
+		}
+	}
+	return false
+}
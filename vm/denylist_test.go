@@ -0,0 +1,55 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"testing"
+)
+
+func TestDenylist(t *testing.T) {
+	t.Parallel()
+
+	d, err := newDenylist([]string{"banned", "spam*", "x?z"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tt := []struct {
+		space  string
+		denied bool
+	}{
+		{space: "banned", denied: true},
+		{space: "bannedtoo", denied: false},
+		{space: "spam", denied: true},
+		{space: "spammer", denied: true},
+		{space: "notspam", denied: false},
+		{space: "xyz", denied: true},
+		{space: "xz", denied: false},
+		{space: "", denied: false},
+	}
+	for i, tv := range tt {
+		if denied := d.denied(tv.space); denied != tv.denied {
+			t.Fatalf("#%d: %q expected denied=%t, got %t", i, tv.space, tv.denied, denied)
+		}
+	}
+}
+
+func TestDenylistEmpty(t *testing.T) {
+	t.Parallel()
+
+	d, err := newDenylist(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.denied("anything") {
+		t.Fatal("empty denylist should not deny")
+	}
+}
+
+func TestDenylistInvalidPattern(t *testing.T) {
+	t.Parallel()
+
+	if _, err := newDenylist([]string{"bad["}); err == nil {
+		t.Fatal("expected invalid pattern to fail")
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	requestType        = reflect.TypeOf((*http.Request)(nil))
+	errorType          = reflect.TypeOf((*error)(nil)).Elem()
+	textMarshalerType  = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonMarshalerType  = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	emptyInterfaceType = reflect.TypeOf((*interface{})(nil)).Elem()
+	rawMessageType     = reflect.TypeOf(json.RawMessage{})
+	byteSliceType      = reflect.TypeOf([]byte{})
+)
+
+// shape of values that may hold any JSON
+const describedAsAnything = "any"
+
+type MethodDescription struct {
+	Name   string      `serialize:"true" json:"name"`
+	Params interface{} `serialize:"true" json:"params"`
+	Result interface{} `serialize:"true" json:"result"`
+}
+
+// describeService returns a description of every RPC method [service]
+// serves under [name], in the form expected by the gorilla RPC server:
+//
+//	func (s *Service) Method(*http.Request, *Args, *Reply) error
+//
+// Shapes mirror the JSON encoding of each type: objects map field names to
+// their shapes, arrays contain the shape of their elements, and scalars (or
+// types with custom encodings) are described by their Go type name.
+func describeService(service interface{}, name string) []*MethodDescription {
+	typ := reflect.TypeOf(service)
+	methods := make([]*MethodDescription, 0, typ.NumMethod())
+	for i := 0; i < typ.NumMethod(); i++ {
+		m := typ.Method(i)
+		mt := m.Type
+		if mt.NumIn() != 4 || mt.NumOut() != 1 ||
+			mt.In(1) != requestType ||
+			mt.In(2).Kind() != reflect.Ptr || mt.In(3).Kind() != reflect.Ptr ||
+			mt.Out(0) != errorType {
+			continue
+		}
+		methods = append(methods, &MethodDescription{
+			Name:   name + "." + lowerFirst(m.Name),
+			Params: describeType(mt.In(2).Elem(), map[reflect.Type]bool{}),
+			Result: describeType(mt.In(3).Elem(), map[reflect.Type]bool{}),
+		})
+	}
+	return methods
+}
+
+func describeType(t reflect.Type, seen map[reflect.Type]bool) interface{} {
+	if t.Kind() == reflect.Ptr {
+		return describeType(t.Elem(), seen)
+	}
+	switch {
+	case t == emptyInterfaceType || t == rawMessageType:
+		return describedAsAnything
+	case t == byteSliceType:
+		return "bytes"
+	case t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType),
+		t.Implements(textMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType):
+		return t.String()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		// Recursive types are described by name after their first occurrence
+		if seen[t] {
+			return t.String()
+		}
+		seen[t] = true
+		defer delete(seen, t)
+
+		fields := map[string]interface{}{}
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			name := f.Name
+			if tag := f.Tag.Get("json"); tag != "" {
+				if tag == "-" {
+					continue
+				}
+				if n := strings.Split(tag, ",")[0]; n != "" {
+					name = n
+				}
+			}
+			fields[name] = describeType(f.Type, seen)
+		}
+		return fields
+	case reflect.Slice, reflect.Array:
+		return []interface{}{describeType(t.Elem(), seen)}
+	case reflect.Map:
+		return map[string]interface{}{"<" + t.Key().String() + ">": describeType(t.Elem(), seen)}
+	case reflect.Interface:
+		return describedAsAnything
+	default:
+		return t.Kind().String()
+	}
+}
+
+func lowerFirst(s string) string {
+	r, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToLower(r)) + s[n:]
+}
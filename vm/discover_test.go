@@ -0,0 +1,38 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDescribeService(t *testing.T) {
+	methods := map[string]*MethodDescription{}
+	for _, m := range describeService(&PublicService{}, Name) {
+		methods[m.Name] = m
+	}
+	if _, ok := methods["spacesvm.discover"]; !ok {
+		t.Fatal("discover should describe itself")
+	}
+
+	balance, ok := methods["spacesvm.balance"]
+	if !ok {
+		t.Fatal("missing spacesvm.balance")
+	}
+	if params := map[string]interface{}{"address": "common.Address", "preferred": "bool", "pending": "bool"}; !reflect.DeepEqual(balance.Params, params) {
+		t.Fatalf("unexpected params %v", balance.Params)
+	}
+	if result := map[string]interface{}{"balance": "uint64"}; !reflect.DeepEqual(balance.Result, result) {
+		t.Fatalf("unexpected result %v", balance.Result)
+	}
+
+	issue, ok := methods["spacesvm.issueRawTx"]
+	if !ok {
+		t.Fatal("missing spacesvm.issueRawTx")
+	}
+	if params := map[string]interface{}{"tx": "bytes"}; !reflect.DeepEqual(issue.Params, params) {
+		t.Fatalf("unexpected params %v", issue.Params)
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+func TestPersistDropUsesClock(t *testing.T) {
+	vm := &VM{db: memdb.New()}
+	vm.config.DroppedTxRecords = 4
+	vm.clock.Set(time.Unix(42, 0))
+
+	txID := ids.GenerateTestID()
+	vm.persistDrop(txID, chain.DropExpired)
+	d, err := chain.GetDroppedTx(vm.db, txID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Reason != chain.DropExpired || d.Timestamp != 42 {
+		t.Fatalf("unexpected drop record %+v", d)
+	}
+}
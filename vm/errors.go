@@ -13,4 +13,34 @@ var (
 	ErrInputIsNil     = errors.New("input is nil")
 	ErrInvalidEmptyTx = errors.New("invalid empty transaction")
 	ErrCorruption     = errors.New("corruption detected")
+	ErrBackupDirEmpty = errors.New("backup directory is required")
+	ErrBlockIDIsEmpty = errors.New("block ID is empty")
+	ErrBlockNotFound  = errors.New("block not found")
+
+	ErrBackupUnavailable   = errors.New("backup unavailable")
+	ErrInvalidExportFormat = errors.New("invalid export format")
+	ErrBackupsDisabled     = errors.New("backups are disabled (backupRoot is not set)")
+	ErrBackupDirOutside    = errors.New("backup directory is outside the backup root")
+
+	ErrInsufficientConnectivity = errors.New("insufficient validator connectivity")
+	ErrInvalidHandshake         = errors.New("invalid handshake")
+
+	ErrSenderRateLimited = errors.New("sender rate limit exceeded")
+	ErrSpaceDenied       = errors.New("space is denied by this node")
+	ErrClaimConflict     = errors.New("competing claim pays at least as much")
+	ErrTooManyPaths      = errors.New("too many paths")
+
+	ErrInvalidResponseKey = errors.New("invalid response key")
+	ErrInvalidAttestation = errors.New("invalid attestation")
+
+	ErrWarpMessageNotFound  = errors.New("warp message not found")
+	ErrInvalidWarpSignature = errors.New("invalid warp signature")
+
+	ErrUnknownAcceptHook = errors.New("unknown accept hook")
+	ErrInvalidWebhook    = errors.New("invalid webhook")
+	ErrWebhookFailed     = errors.New("webhook failed")
+
+	ErrInvalidGateway  = errors.New("invalid IPFS gateway")
+	ErrSiteFileMissing = errors.New("site file missing")
+	ErrInvalidETag     = errors.New("invalid ETag")
 )
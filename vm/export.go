@@ -0,0 +1,270 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"bufio"
+	ejson "encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	log "github.com/inconshreveable/log15"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+const (
+	// ExportFormatRaw writes each block as its length-prefixed bytes (see
+	// [chain.WriteBlockRecord])
+	ExportFormatRaw = "raw"
+	// ExportFormatJSONL writes each block as a line of JSON (see
+	// [ExportedBlock])
+	ExportFormatJSONL = "jsonl"
+
+	exportRawFile      = "blocks.bin"
+	exportJSONLFile    = "blocks.jsonl"
+	exportMetadataFile = "export.json"
+)
+
+// ExportMetadata is stored alongside the exported blocks and is used to
+// verify the chain replayed from them.
+type ExportMetadata struct {
+	Format       string `serialize:"true" json:"format"`
+	LastAccepted ids.ID `serialize:"true" json:"lastAccepted"`
+	Height       uint64 `serialize:"true" json:"height"`
+	Blocks       int    `serialize:"true" json:"blocks"`
+	Created      int64  `serialize:"true" json:"created"`
+	// State is the metadata of the backup written with the blocks, if any
+	State *BackupMetadata `serialize:"true" json:"state,omitempty"`
+}
+
+// ExportedBlock is a line of a [ExportFormatJSONL] export. [Bytes] are the
+// raw block bytes that are replayed on import.
+type ExportedBlock struct {
+	*BlockDetail
+	Bytes hexutil.Bytes `serialize:"true" json:"bytes"`
+}
+
+func exportFile(format string) (string, error) {
+	switch format {
+	case ExportFormatRaw:
+		return exportRawFile, nil
+	case ExportFormatJSONL:
+		return exportJSONLFile, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrInvalidExportFormat, format)
+	}
+}
+
+// Export writes every accepted block after genesis to [dir] in [format]
+// and, if [state] is set, a backup of the database (see [VM.Backup]). The
+// context lock is held for the duration of the export so that no blocks are
+// accepted while it is taken.
+func (vm *VM) Export(dir string, format string, state bool) (*ExportMetadata, error) {
+	name, err := exportFile(format)
+	if err != nil {
+		return nil, err
+	}
+
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	if err := os.MkdirAll(dir, backupDirMode); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(
+		filepath.Join(dir, name),
+		os.O_CREATE|os.O_EXCL|os.O_WRONLY,
+		backupFileMode,
+	)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	blocks, err := vm.exportBlocks(f, format)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	if err := f.Close(); err != nil {
+		return nil, err
+	}
+
+	meta := &ExportMetadata{
+		Format:       format,
+		LastAccepted: vm.lastAccepted.ID(),
+		Height:       vm.lastAccepted.Hght,
+		Blocks:       blocks,
+		Created:      time.Now().Unix(),
+	}
+	if state {
+		cursor := vm.db.NewIterator()
+		meta.State, err = writeBackup(dir, cursor, vm.lastAccepted)
+		cursor.Release()
+		if err != nil {
+			return nil, err
+		}
+	}
+	b, err := ejson.Marshal(meta)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, exportMetadataFile), b, backupFileMode); err != nil {
+		return nil, err
+	}
+	log.Info("exported blocks",
+		"dir", dir,
+		"format", format,
+		"lastAccepted", meta.LastAccepted,
+		"blocks", blocks,
+		"state", state,
+		"t", time.Since(start),
+	)
+	return meta, nil
+}
+
+// acceptedIDs returns the IDs of the accepted blocks after genesis, oldest
+// first. Assumes ctx.Lock is held.
+func (vm *VM) acceptedIDs() ([]ids.ID, error) {
+	blkIDs := make([]ids.ID, vm.lastAccepted.Hght)
+	blkID, prnt := vm.lastAccepted.ID(), vm.lastAccepted.Prnt
+	for i := len(blkIDs) - 1; i >= 0; i-- {
+		blkIDs[i] = blkID
+		if i == 0 {
+			break
+		}
+		// Only the parent is needed, so the block is not parsed
+		blk, err := chain.GetBlock(vm.db, prnt)
+		if err != nil {
+			return nil, fmt.Errorf("%w: block %s unavailable: %v", ErrCorruption, prnt, err)
+		}
+		blkID, prnt = prnt, blk.Prnt
+	}
+	return blkIDs, nil
+}
+
+// exportBlocks writes every accepted block after genesis to [w] in [format]
+// and returns the number of blocks written. Assumes ctx.Lock is held.
+func (vm *VM) exportBlocks(w io.Writer, format string) (int, error) {
+	blkIDs, err := vm.acceptedIDs()
+	if err != nil {
+		return 0, err
+	}
+	bw := bufio.NewWriter(w)
+	enc := ejson.NewEncoder(bw)
+	svc := &PublicService{vm: vm}
+	for _, blkID := range blkIDs {
+		blk, err := vm.GetStatelessBlock(blkID)
+		if err != nil {
+			return 0, err
+		}
+		switch format {
+		case ExportFormatRaw:
+			err = chain.WriteBlockRecord(bw, blk.Bytes())
+		case ExportFormatJSONL:
+			err = enc.Encode(&ExportedBlock{BlockDetail: svc.blockDetail(blk), Bytes: blk.Bytes()})
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(blkIDs), bw.Flush()
+}
+
+// blockReader returns a function that reads the next block from an export
+// in [format], returning [io.EOF] once all blocks have been read.
+func blockReader(r io.Reader, format string) func() ([]byte, error) {
+	if format == ExportFormatRaw {
+		br := bufio.NewReader(r)
+		return func() ([]byte, error) { return chain.ReadBlockRecord(br) }
+	}
+	dec := ejson.NewDecoder(r)
+	return func() ([]byte, error) {
+		blk := new(ExportedBlock)
+		if err := dec.Decode(blk); err != nil {
+			return nil, err
+		}
+		return blk.Bytes, nil
+	}
+}
+
+// importBlocks replays the blocks exported to [dir] on top of the last
+// accepted block. Each block is verified and accepted as if it were
+// bootstrapped from the network, and blocks at or below the last accepted
+// height (such as those already included in a restored backup) are skipped.
+// Assumes ctx.Lock is held.
+func (vm *VM) importBlocks(dir string) (*ExportMetadata, error) {
+	b, err := os.ReadFile(filepath.Join(dir, exportMetadataFile))
+	if err != nil {
+		return nil, err
+	}
+	meta := new(ExportMetadata)
+	if err := ejson.Unmarshal(b, meta); err != nil {
+		return nil, err
+	}
+	name, err := exportFile(meta.Format)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(filepath.Join(dir, name))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	next := blockReader(f, meta.Format)
+	read := 0
+	for {
+		source, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		read++
+		stBlk := new(chain.StatefulBlock)
+		if _, err := chain.Unmarshal(source, stBlk); err != nil {
+			return nil, fmt.Errorf("%w: unable to parse block %d: %v", chain.ErrInvalidExport, read, err)
+		}
+		if stBlk.Hght <= vm.lastAccepted.Hght {
+			continue
+		}
+		if stBlk.Prnt != vm.lastAccepted.ID() {
+			return nil, fmt.Errorf(
+				"%w: block at height %d does not extend %s (height=%d)",
+				chain.ErrInvalidExport, stBlk.Hght, vm.lastAccepted.ID(), vm.lastAccepted.Hght,
+			)
+		}
+		blk, err := chain.ParseStatefulBlock(stBlk, source, choices.Processing, vm)
+		if err != nil {
+			return nil, err
+		}
+		if err := blk.Verify(); err != nil {
+			return nil, fmt.Errorf("%w: block %s (height=%d) is invalid: %v", chain.ErrInvalidExport, blk.ID(), blk.Hght, err)
+		}
+		if err := blk.Accept(); err != nil {
+			return nil, err
+		}
+	}
+	if read != meta.Blocks || vm.lastAccepted.ID() != meta.LastAccepted {
+		return nil, fmt.Errorf(
+			"%w: replayed %d blocks to %s but expected %d blocks to %s",
+			chain.ErrInvalidExport, read, vm.lastAccepted.ID(), meta.Blocks, meta.LastAccepted,
+		)
+	}
+	vm.preferred = vm.lastAccepted.ID()
+	return meta, nil
+}
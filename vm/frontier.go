@@ -0,0 +1,76 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"sort"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+// FrontierBlock identifies a block on the frontier of the chain.
+type FrontierBlock struct {
+	BlockID   ids.ID `serialize:"true" json:"blockId"`
+	Parent    ids.ID `serialize:"true" json:"parent"`
+	Height    uint64 `serialize:"true" json:"height"`
+	Timestamp int64  `serialize:"true" json:"timestamp"`
+	Txs       int    `serialize:"true" json:"txs"`
+}
+
+func newFrontierBlock(b *chain.StatelessBlock) *FrontierBlock {
+	return &FrontierBlock{
+		BlockID:   b.ID(),
+		Parent:    b.Prnt,
+		Height:    b.Hght,
+		Timestamp: b.Tmstmp,
+		Txs:       len(b.Txs),
+	}
+}
+
+// Frontier is the last accepted block and the blocks being processed on top
+// of it.
+type Frontier struct {
+	LastAccepted *FrontierBlock `serialize:"true" json:"lastAccepted"`
+	// Age is the number of seconds since the timestamp of the last accepted
+	// block (a growing age means the chain has stalled)
+	Age       int64  `serialize:"true" json:"age"`
+	Preferred ids.ID `serialize:"true" json:"preferred"`
+	// Processing blocks have been verified but not yet accepted or rejected
+	// (sorted by height)
+	Processing []*FrontierBlock `serialize:"true" json:"processing"`
+	// Forks is the number of heights with more than one processing block
+	Forks int `serialize:"true" json:"forks"`
+}
+
+// frontier returns the current [Frontier]. Assumes ctx.Lock is held.
+func (vm *VM) frontier() *Frontier {
+	f := &Frontier{
+		LastAccepted: newFrontierBlock(vm.lastAccepted),
+		Age:          vm.clock.Time().Unix() - vm.lastAccepted.Tmstmp,
+		Preferred:    vm.preferred,
+		Processing:   make([]*FrontierBlock, 0, len(vm.verifiedBlocks)),
+	}
+	for _, blk := range vm.verifiedBlocks {
+		f.Processing = append(f.Processing, newFrontierBlock(blk))
+	}
+	sort.Slice(f.Processing, func(i, j int) bool {
+		pi, pj := f.Processing[i], f.Processing[j]
+		if pi.Height != pj.Height {
+			return pi.Height < pj.Height
+		}
+		return pi.Timestamp < pj.Timestamp
+	})
+	for i := 1; i < len(f.Processing); i++ {
+		if f.Processing[i].Height != f.Processing[i-1].Height {
+			continue
+		}
+		// Only count each forked height once
+		if i == 1 || f.Processing[i-2].Height != f.Processing[i].Height {
+			f.Forks++
+		}
+	}
+	return f
+}
@@ -0,0 +1,60 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+func TestFrontier(t *testing.T) {
+	newBlock := func(prnt ids.ID, hght uint64, tmstmp int64) *chain.StatelessBlock {
+		blk, err := chain.ParseStatefulBlock(&chain.StatefulBlock{
+			Prnt:   prnt,
+			Hght:   hght,
+			Tmstmp: tmstmp,
+		}, nil, choices.Processing, &VM{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return blk
+	}
+	root := newBlock(ids.GenerateTestID(), 1, 1)
+	left := newBlock(root.ID(), 2, 2)
+	right := newBlock(root.ID(), 2, 3)
+	middle := newBlock(root.ID(), 2, 4)
+	leftChild := newBlock(left.ID(), 3, 5)
+
+	vm := &VM{
+		lastAccepted:   root,
+		preferred:      leftChild.ID(),
+		verifiedBlocks: map[ids.ID]*chain.StatelessBlock{},
+	}
+	vm.clock.Set(time.Unix(11, 0))
+	for _, blk := range []*chain.StatelessBlock{leftChild, right, middle, left} {
+		vm.verifiedBlocks[blk.ID()] = blk
+	}
+
+	f := vm.frontier()
+	if f.LastAccepted.BlockID != root.ID() || f.Age != 10 || f.Preferred != leftChild.ID() {
+		t.Fatalf("unexpected frontier %+v", f)
+	}
+	expected := []*chain.StatelessBlock{left, right, middle, leftChild}
+	if len(f.Processing) != len(expected) {
+		t.Fatalf("expected %d processing blocks, got %d", len(expected), len(f.Processing))
+	}
+	for i, blk := range expected {
+		if f.Processing[i].BlockID != blk.ID() || f.Processing[i].Parent != blk.Prnt {
+			t.Fatalf("#%d: expected processing block %s, got %s", i, blk.ID(), f.Processing[i].BlockID)
+		}
+	}
+	if f.Forks != 1 {
+		t.Fatalf("expected 1 forked height, got %d", f.Forks)
+	}
+}
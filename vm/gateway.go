@@ -0,0 +1,154 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+// gatewayCacheControl lets browsers and CDNs store gateway responses but
+// requires them to revalidate (values can be updated at any time)
+const gatewayCacheControl = "public, no-cache"
+
+// gatewayLookup returns the value stored at [space]/[key] and its metadata
+type gatewayLookup func(space string, key string) (*chain.ValueMeta, []byte, bool, error)
+
+// parseGateway validates the configured IPFS gateway (such as
+// "https://ipfs.io") and strips any trailing slash
+func parseGateway(gateway string) (string, error) {
+	u, err := url.Parse(gateway)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidGateway, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 {
+		return "", fmt.Errorf("%w: %q is not an http(s) URL", ErrInvalidGateway, gateway)
+	}
+	return strings.TrimSuffix(gateway, "/"), nil
+}
+
+// gatewayHandler serves GET requests for ?path=space/key. IPFS values and
+// content hashes are redirected to the content on [gateway], redirect records
+// are followed with a redirect, and other values are served directly with an
+// ETag of their hash and a Last-Modified time of the block that accepted them.
+func gatewayHandler(gateway string, denied func(space string) bool, lookup gatewayLookup) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		space, key, err := parser.ResolvePath(r.URL.Query().Get("path"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if denied(space) {
+			http.Error(w, ErrSpaceDenied.Error(), http.StatusForbidden)
+			return
+		}
+		vmeta, value, exists, err := lookup(space, key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !exists {
+			http.NotFound(w, r)
+			return
+		}
+		switch vmeta.Kind {
+		case chain.ValueIPFS:
+			http.Redirect(w, r, gateway+"/ipfs/"+string(value), http.StatusFound)
+			return
+		case chain.ValueRedirect:
+			target, u, err := chain.ParseRedirect(value)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if u != nil {
+				http.Redirect(w, r, u.String(), http.StatusFound)
+				return
+			}
+			http.Redirect(w, r, "?path="+url.QueryEscape(target), http.StatusFound)
+			return
+		case chain.ValueContentHash:
+			protocol, hash, err := chain.ParseContentHash(value)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			if protocol == chain.ProtocolIPFS || protocol == chain.ProtocolIPNS {
+				http.Redirect(w, r, gateway+"/"+protocol+"/"+hash, http.StatusFound)
+				return
+			}
+		}
+		contentType := "text/plain; charset=utf-8"
+		if vmeta.Kind == chain.ValueRaw {
+			contentType = http.DetectContentType(value)
+		}
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("ETag", valueETag(value))
+		w.Header().Set("Cache-Control", gatewayCacheControl)
+		http.ServeContent(w, r, "", time.Unix(int64(vmeta.Updated), 0), bytes.NewReader(value))
+	})
+}
+
+// valueETag is the strong ETag of [value], which matches the value hash
+// attested in resolve responses
+func valueETag(value []byte) string {
+	return `"` + crypto.Keccak256Hash(value).Hex() + `"`
+}
+
+// ParseETag returns the value hash of an ETag returned by the gateway or
+// resolve
+func ParseETag(etag string) (common.Hash, error) {
+	h := strings.TrimSuffix(strings.TrimPrefix(etag, `"`), `"`)
+	if len(h) != 2+2*common.HashLength || !strings.HasPrefix(h, "0x") {
+		return common.Hash{}, fmt.Errorf("%w: %q", ErrInvalidETag, etag)
+	}
+	b, err := hex.DecodeString(h[2:])
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("%w: %v", ErrInvalidETag, err)
+	}
+	return common.BytesToHash(b), nil
+}
+
+// etagMatches reports whether the If-None-Match [header] matches [etag]
+func etagMatches(header string, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// lookupValue implements [gatewayLookup] against the accepted state
+func (vm *VM) lookupValue(space string, key string) (*chain.ValueMeta, []byte, bool, error) {
+	vmeta, exists, err := chain.GetValueMeta(vm.db, []byte(space), []byte(key))
+	if err != nil || !exists {
+		return nil, nil, false, err
+	}
+	value, exists, err := chain.GetValue(vm.db, []byte(space), []byte(key))
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if !exists {
+		return nil, nil, false, ErrCorruption
+	}
+	return vmeta, value, true, nil
+}
@@ -0,0 +1,165 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+func TestParseGateway(t *testing.T) {
+	tt := []struct {
+		gateway  string
+		expected string
+		err      error
+	}{
+		{gateway: "https://ipfs.io", expected: "https://ipfs.io"},
+		{gateway: "http://localhost:8080/", expected: "http://localhost:8080"},
+		{gateway: "ipfs.io", err: ErrInvalidGateway},
+		{gateway: "ftp://ipfs.io", err: ErrInvalidGateway},
+	}
+	for i, tv := range tt {
+		gateway, err := parseGateway(tv.gateway)
+		if !errors.Is(err, tv.err) {
+			t.Fatalf("%d: expected error %v, got %v", i, tv.err, err)
+		}
+		if gateway != tv.expected {
+			t.Fatalf("%d: expected %q, got %q", i, tv.expected, gateway)
+		}
+	}
+}
+
+func TestGatewayHandler(t *testing.T) {
+	const cid = "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG"
+	values := map[string]struct {
+		kind  string
+		value string
+	}{
+		"space/cid":   {kind: chain.ValueIPFS, value: cid},
+		"space/raw":   {kind: chain.ValueRaw, value: "hello"},
+		"space/txt":   {kind: chain.ValueTXT, value: "hello"},
+		"space/alias": {kind: chain.ValueRedirect, value: "space/raw"},
+		"space/url":   {kind: chain.ValueRedirect, value: "https://example.com"},
+		"space/ipns":  {kind: chain.ValueContentHash, value: "ipns://" + cid},
+	}
+	h := gatewayHandler(
+		"https://ipfs.io",
+		func(space string) bool { return space == "denied" },
+		func(space string, key string) (*chain.ValueMeta, []byte, bool, error) {
+			v, ok := values[space+"/"+key]
+			if !ok {
+				return nil, nil, false, nil
+			}
+			return &chain.ValueMeta{Kind: v.kind}, []byte(v.value), true, nil
+		},
+	)
+
+	tt := []struct {
+		method   string
+		path     string
+		status   int
+		location string
+		body     string
+	}{
+		{method: http.MethodGet, path: "space/cid", status: http.StatusFound, location: "https://ipfs.io/ipfs/" + cid},
+		{method: http.MethodGet, path: "space/raw", status: http.StatusOK, body: "hello"},
+		{method: http.MethodGet, path: "space/txt", status: http.StatusOK, body: "hello"},
+		{method: http.MethodGet, path: "space/alias", status: http.StatusFound, location: "/?path=space%2Fraw"},
+		{method: http.MethodGet, path: "space/url", status: http.StatusFound, location: "https://example.com"},
+		{method: http.MethodGet, path: "space/ipns", status: http.StatusFound, location: "https://ipfs.io/ipns/" + cid},
+		{method: http.MethodGet, path: "space/missing", status: http.StatusNotFound},
+		{method: http.MethodGet, path: "denied/cid", status: http.StatusForbidden},
+		{method: http.MethodGet, path: "invalid", status: http.StatusBadRequest},
+		{method: http.MethodPost, path: "space/cid", status: http.StatusMethodNotAllowed},
+	}
+	for i, tv := range tt {
+		req := httptest.NewRequest(tv.method, "/?path="+tv.path, nil)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tv.status {
+			t.Fatalf("%d: expected status %d, got %d", i, tv.status, rec.Code)
+		}
+		if l := rec.Header().Get("Location"); l != tv.location {
+			t.Fatalf("%d: expected location %q, got %q", i, tv.location, l)
+		}
+		if len(tv.body) > 0 && rec.Body.String() != tv.body {
+			t.Fatalf("%d: expected body %q, got %q", i, tv.body, rec.Body.String())
+		}
+	}
+}
+
+func TestGatewayCaching(t *testing.T) {
+	value := []byte("hello")
+	etag := valueETag(value)
+	h := gatewayHandler(
+		"https://ipfs.io",
+		func(string) bool { return false },
+		func(space string, key string) (*chain.ValueMeta, []byte, bool, error) {
+			return &chain.ValueMeta{Updated: 1_000_000}, value, true, nil
+		},
+	)
+	lastModified := time.Unix(1_000_000, 0).UTC().Format(http.TimeFormat)
+
+	tt := []struct {
+		header string
+		value  string
+		status int
+	}{
+		{status: http.StatusOK},
+		{header: "If-None-Match", value: etag, status: http.StatusNotModified},
+		{header: "If-None-Match", value: `"other", ` + etag, status: http.StatusNotModified},
+		{header: "If-None-Match", value: `"other"`, status: http.StatusOK},
+		{header: "If-Modified-Since", value: lastModified, status: http.StatusNotModified},
+		{header: "If-Modified-Since", value: time.Unix(999_999, 0).UTC().Format(http.TimeFormat), status: http.StatusOK},
+	}
+	for i, tv := range tt {
+		req := httptest.NewRequest(http.MethodGet, "/?path=space/key", nil)
+		if len(tv.header) > 0 {
+			req.Header.Set(tv.header, tv.value)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tv.status {
+			t.Fatalf("%d: expected status %d, got %d", i, tv.status, rec.Code)
+		}
+		if e := rec.Header().Get("ETag"); e != etag {
+			t.Fatalf("%d: expected etag %q, got %q", i, etag, e)
+		}
+		if c := rec.Header().Get("Cache-Control"); c != gatewayCacheControl {
+			t.Fatalf("%d: expected cache control %q, got %q", i, gatewayCacheControl, c)
+		}
+		if rec.Code == http.StatusOK {
+			if l := rec.Header().Get("Last-Modified"); l != lastModified {
+				t.Fatalf("%d: expected last modified %q, got %q", i, lastModified, l)
+			}
+			if rec.Body.String() != string(value) {
+				t.Fatalf("%d: expected body %q, got %q", i, value, rec.Body.String())
+			}
+		}
+	}
+}
+
+func TestParseETag(t *testing.T) {
+	value := []byte("hello")
+	hash, err := ParseETag(valueETag(value))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != crypto.Keccak256Hash(value) {
+		t.Fatalf("expected %s, got %s", crypto.Keccak256Hash(value), hash)
+	}
+	for _, etag := range []string{`"hello"`, `"0x1234"`, `"0x` + strings.Repeat("z", 64) + `"`} {
+		if _, err := ParseETag(etag); !errors.Is(err, ErrInvalidETag) {
+			t.Fatalf("%s: expected %v, got %v", etag, ErrInvalidETag, err)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	log "github.com/inconshreveable/log15"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/version"
+)
+
+const (
+	// handshakeVersion is the version of the handshake encoding (not of the
+	// VM), so it can be extended without breaking older peers
+	handshakeVersion = 0
+
+	// unknownVersion is reported for peers that have not completed a
+	// handshake (such as peers running a VM version without handshakes)
+	unknownVersion = "unknown"
+)
+
+// handshake is exchanged with each peer on connect via AppRequest and
+// AppResponse. It is packed by hand (instead of with the chain codec) so that
+// peers running a different codec can still parse it.
+type handshake struct {
+	Version string `json:"version"`
+	Codec   uint16 `json:"codec"`
+}
+
+func localHandshake() *handshake {
+	return &handshake{Version: version.Version, Codec: chain.CodecVersion}
+}
+
+// compatible returns true if gossip from this node can be parsed by the peer
+// that sent [h].
+func (h *handshake) compatible() bool {
+	return h.Codec == chain.CodecVersion
+}
+
+func (h *handshake) Bytes() []byte {
+	p := wrappers.Packer{MaxSize: 2 + 2 + wrappers.ShortLen + len(h.Version)}
+	p.PackShort(handshakeVersion)
+	p.PackShort(h.Codec)
+	p.PackStr(h.Version)
+	return p.Bytes
+}
+
+func parseHandshake(b []byte) (*handshake, error) {
+	p := wrappers.Packer{Bytes: b}
+	if v := p.UnpackShort(); !p.Errored() && v != handshakeVersion {
+		return nil, fmt.Errorf("%w: unknown handshake version %d", ErrInvalidHandshake, v)
+	}
+	h := &handshake{
+		Codec:   p.UnpackShort(),
+		Version: p.UnpackStr(),
+	}
+	if p.Errored() {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidHandshake, p.Err)
+	}
+	return h, nil
+}
+
+// sendHandshake requests the handshake of [id], sending ours with the request.
+func (vm *VM) sendHandshake(id ids.NodeID) {
+	if vm.appSender == nil {
+		return
+	}
+	nodes := ids.NewNodeIDSet(1)
+	nodes.Add(id)
+	requestID := atomic.AddUint32(&vm.handshakeRequestID, 1)
+	if err := vm.appSender.SendAppRequest(nodes, requestID, localHandshake().Bytes()); err != nil {
+		log.Warn("unable to send handshake", "peerID", id, "error", err)
+	}
+}
+
+// recordHandshake records the handshake [msg] sent by [id].
+func (vm *VM) recordHandshake(id ids.NodeID, msg []byte) bool {
+	h, err := parseHandshake(msg)
+	if err != nil {
+		log.Debug("peer sent invalid handshake", "peerID", id, "error", err)
+		return false
+	}
+	vm.peers.setHandshake(id, h)
+	if !h.compatible() {
+		log.Info("peer runs an incompatible codec, not gossiping to it",
+			"peerID", id, "version", h.Version, "codec", h.Codec,
+		)
+	}
+	return true
+}
+
+// implements "snowmanblock.ChainVM.commom.VM.AppHandler"
+func (vm *VM) AppRequest(nodeID ids.NodeID, requestID uint32, deadline time.Time, request []byte) error {
+	// Handshakes are the only app requests, so anything else is dropped
+	if !vm.recordHandshake(nodeID, request) || vm.appSender == nil {
+		return nil
+	}
+	if err := vm.appSender.SendAppResponse(nodeID, requestID, localHandshake().Bytes()); err != nil {
+		log.Warn("unable to respond to handshake", "peerID", nodeID, "error", err)
+	}
+	return nil
+}
+
+// implements "snowmanblock.ChainVM.commom.VM.AppHandler"
+func (vm *VM) AppRequestFailed(nodeID ids.NodeID, requestID uint32) error {
+	// Peers running a VM version without handshakes never respond, so they
+	// stay unknown (and are still gossiped to)
+	log.Debug("handshake failed", "peerID", nodeID, "requestID", requestID)
+	return nil
+}
+
+// implements "snowmanblock.ChainVM.commom.VM.AppHandler"
+func (vm *VM) AppResponse(nodeID ids.NodeID, requestID uint32, response []byte) error {
+	vm.recordHandshake(nodeID, response)
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+func TestHandshake(t *testing.T) {
+	t.Parallel()
+
+	h := localHandshake()
+	parsed, err := parseHandshake(h.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *parsed != *h {
+		t.Fatalf("expected %+v, got %+v", h, parsed)
+	}
+	if !parsed.compatible() {
+		t.Fatal("local handshake should be compatible")
+	}
+
+	for i, b := range [][]byte{nil, {0}, {0, 1}, h.Bytes()[:5]} {
+		if _, err := parseHandshake(b); !errors.Is(err, ErrInvalidHandshake) {
+			t.Fatalf("#%d: expected %v, got %v", i, ErrInvalidHandshake, err)
+		}
+	}
+}
+
+func TestPeerTrackerVersions(t *testing.T) {
+	t.Parallel()
+
+	current, old, unknown := ids.GenerateTestNodeID(), ids.GenerateTestNodeID(), ids.GenerateTestNodeID()
+	p := newPeerTracker()
+	p.connect(current)
+	p.connect(old)
+	p.connect(unknown)
+	if _, ok := p.gossipTargets(0); ok {
+		t.Fatal("no peers should be excluded before handshakes")
+	}
+
+	p.setHandshake(current, &handshake{Version: "v1", Codec: chain.CodecVersion})
+	p.setHandshake(old, &handshake{Version: "v0", Codec: chain.CodecVersion + 1})
+	// Handshakes from disconnected peers are ignored
+	p.setHandshake(ids.GenerateTestNodeID(), &handshake{Version: "v2"})
+
+	versions, incompatible := p.versions()
+	if incompatible != 1 {
+		t.Fatalf("expected 1 incompatible peer, got %d", incompatible)
+	}
+	if len(versions) != 3 || versions["v1"] != 1 || versions["v0"] != 1 || versions[unknownVersion] != 1 {
+		t.Fatalf("unexpected versions %v", versions)
+	}
+	targets, ok := p.gossipTargets(0)
+	if !ok {
+		t.Fatal("incompatible peer should be excluded")
+	}
+	if targets.Len() != 2 || !targets.Contains(current) || !targets.Contains(unknown) {
+		t.Fatalf("unexpected targets %v", targets)
+	}
+
+	p.disconnect(old)
+	if _, ok := p.gossipTargets(0); ok {
+		t.Fatal("no peers should be excluded after disconnect")
+	}
+}
@@ -6,9 +6,11 @@ package vm
 import (
 	"fmt"
 	"sort"
-	"time"
 
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/versiondb"
 	"github.com/ava-labs/avalanchego/ids"
+	log "github.com/inconshreveable/log15"
 
 	"github.com/ava-labs/spacesvm/chain"
 )
@@ -42,7 +44,7 @@ func (vm *VM) lookback(currTime int64, lastID ids.ID, f func(b *chain.StatelessB
 
 func (vm *VM) ValidBlockID(blockID ids.ID) (bool, error) {
 	var foundBlockID bool
-	err := vm.lookback(time.Now().Unix(), vm.preferred, func(b *chain.StatelessBlock) (bool, error) {
+	err := vm.lookback(vm.clock.Time().Unix(), vm.preferred, func(b *chain.StatelessBlock) (bool, error) {
 		if b.ID() == blockID {
 			foundBlockID = true
 			return false, nil
@@ -62,7 +64,7 @@ func (vm *VM) SuggestedFee() (uint64, uint64, error) {
 		return 0, 0, fmt.Errorf("unexpected snowman.Block %T, expected *StatelessBlock", prnt)
 	}
 
-	ctx, err := vm.ExecutionContext(time.Now().Unix(), parent)
+	ctx, err := vm.ExecutionContext(vm.clock.Time().Unix(), parent)
 	if err != nil {
 		return 0, 0, err
 	}
@@ -92,3 +94,88 @@ func (vm *VM) SuggestedFee() (uint64, uint64, error) {
 	}
 	return pPrice, cPerTx, nil
 }
+
+// readState returns the state reads are served from. If [preferred] is set,
+// this is the state after the preferred block, which includes verified blocks
+// that have not been accepted yet (so clients can read their own writes). If
+// [pending] is set, the transactions in the mempool are overlaid on that state
+// (see [pendingState]).
+func (vm *VM) readState(preferred bool, pending bool) (database.Database, error) {
+	db, _, err := vm.readView(preferred, pending)
+	return db, err
+}
+
+// readView is [readState] that also returns the block the state is after
+// (for pending reads, the block the mempool is overlaid on).
+func (vm *VM) readView(preferred bool, pending bool) (database.Database, *chain.StatelessBlock, error) {
+	parent, db := vm.lastAccepted, database.Database(vm.db)
+	if blk, ok := vm.verifiedBlocks[vm.preferred]; preferred && ok {
+		sdb, err := blk.StateView()
+		if err != nil {
+			return nil, nil, err
+		}
+		parent, db = blk, sdb
+	}
+	if !pending {
+		return db, parent, nil
+	}
+	pdb, err := vm.pendingState(db, parent)
+	return pdb, parent, err
+}
+
+// pendingState overlays the transactions in the mempool on [db] (the state
+// after [parent]) in the order they would be included in blocks, so wallets
+// can show the anticipated result of transactions that have not been accepted
+// yet. This is best-effort: transactions that fail to execute are skipped, and
+// the blocks that eventually include them may order (or reject) them
+// differently. The overlay is never committed, so values are read from it
+// without going through the linked value cache.
+func (vm *VM) pendingState(db database.Database, parent *chain.StatelessBlock) (database.Database, error) {
+	now := vm.clock.Time().Unix()
+	ctx, err := vm.ExecutionContext(now, parent)
+	if err != nil {
+		return nil, err
+	}
+	vdb := versiondb.New(db)
+	if err := chain.ExpireNext(vdb, parent.Tmstmp, now, 0); err != nil {
+		return nil, err
+	}
+	if err := chain.ActivateMoves(vdb, now); err != nil {
+		return nil, err
+	}
+	for _, tx := range vm.mempool.Txs() {
+		tdb := versiondb.New(vdb)
+		if err := tx.Execute(vm.genesis, tdb, chain.DummyBlock(now, tx), ctx); err != nil {
+			log.Debug("skipping pending transaction", "tx", tx.ID(), "error", err)
+			tdb.Abort()
+			continue
+		}
+		if err := chain.PutLinkedValue(tdb, tx); err != nil {
+			return nil, err
+		}
+		if err := tdb.Commit(); err != nil {
+			return nil, err
+		}
+	}
+	return chain.Uncached(vdb), nil
+}
+
+// initHashIndex indexes every stored value by its hash if the database was
+// created before values were indexed
+func (vm *VM) initHashIndex() error {
+	has, err := chain.HasHashIndex(vm.db)
+	if err != nil || has {
+		return err
+	}
+	vdb := versiondb.New(vm.db)
+	defer vdb.Abort()
+	indexed, err := chain.RebuildHashIndex(vdb)
+	if err != nil {
+		return err
+	}
+	if err := vdb.Commit(); err != nil {
+		return err
+	}
+	log.Info("indexed value hashes", "values", indexed)
+	return nil
+}
@@ -0,0 +1,67 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	log "github.com/inconshreveable/log15"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+// historyBatchSize is the number of blocks indexed per commit when catching up
+// on deferred history
+const historyBatchSize = 1024
+
+// indexDeferredHistory indexes the activity of every block accepted while
+// bootstrapping. Progress is committed in batches, so indexing resumes where
+// it left off if the node stops before it finishes.
+func (vm *VM) indexDeferredHistory() error {
+	start, deferred, err := chain.GetDeferredHistory(vm.db)
+	if err != nil {
+		return err
+	}
+	if !deferred {
+		return nil
+	}
+	begin := time.Now()
+	last := vm.lastAccepted.Hght
+	vdb := versiondb.New(vm.db)
+	for h := start; h <= last; h++ {
+		blkID, ok, err := chain.GetBlockIDAtHeight(vdb, h)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("%w: height %d", ErrBlockNotFound, h)
+		}
+		blk, err := vm.GetStatelessBlock(blkID)
+		if err != nil {
+			return err
+		}
+		if err := chain.IndexHistory(vdb, blk); err != nil {
+			return err
+		}
+		if (h-start+1)%historyBatchSize != 0 {
+			continue
+		}
+		if err := chain.SetDeferredHistory(vdb, h+1); err != nil {
+			return err
+		}
+		if err := vdb.Commit(); err != nil {
+			return err
+		}
+	}
+	if err := chain.ClearDeferredHistory(vdb); err != nil {
+		return err
+	}
+	if err := vdb.Commit(); err != nil {
+		return err
+	}
+	log.Info("indexed deferred history", "start", start, "end", last, "t", time.Since(begin))
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/gorilla/rpc/v2"
+	log "github.com/inconshreveable/log15"
+)
+
+const (
+	apiPublic = "public"
+	apiAdmin  = "admin"
+)
+
+type requestStartKey struct{}
+
+// instrument records the number of requests, errors, and latency of each
+// method served by [h] (which must be created by [newHandler]) under [api],
+// and logs requests that take longer than [Config.SlowRequestThreshold].
+// Latency only covers executing the method and writing its response, so
+// time spent waiting for the context lock or decoding is excluded.
+func (vm *VM) instrument(api string, h *common.HTTPHandler) {
+	server, ok := h.Handler.(*rpc.Server)
+	if !ok {
+		return
+	}
+	threshold := vm.config.SlowRequestThreshold
+	server.RegisterInterceptFunc(func(i *rpc.RequestInfo) *http.Request {
+		return i.Request.WithContext(context.WithValue(i.Request.Context(), requestStartKey{}, time.Now()))
+	})
+	server.RegisterAfterFunc(func(i *rpc.RequestInfo) {
+		start, ok := i.Request.Context().Value(requestStartKey{}).(time.Time)
+		if !ok {
+			return
+		}
+		elapsed := time.Since(start)
+		method := apiMethod(i.Method)
+		vm.metrics.apiRequests.WithLabelValues(api, method).Inc()
+		if i.Error != nil {
+			vm.metrics.apiErrors.WithLabelValues(api, method).Inc()
+		}
+		vm.metrics.apiLatency.WithLabelValues(api, method).Observe(elapsed.Seconds())
+		if threshold > 0 && elapsed >= threshold {
+			log.Warn("slow API request",
+				"api", api,
+				"method", method,
+				"t", elapsed,
+				"remote", i.Request.RemoteAddr,
+				"err", i.Error,
+			)
+		}
+	})
+}
+
+// apiMethod returns the name clients call [method] by. The JSON codec
+// capitalizes the method ("spacesvm.ping" is served as "spacesvm.Ping").
+func apiMethod(method string) string {
+	i := strings.LastIndexByte(method, '.') + 1
+	if i == len(method) {
+		return method
+	}
+	r, size := utf8.DecodeRuneInString(method[i:])
+	return method[:i] + string(unicode.ToLower(r)) + method[i+size:]
+}
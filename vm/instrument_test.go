@@ -0,0 +1,74 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestInstrument(t *testing.T) {
+	m, err := newMetrics(prometheus.NewRegistry())
+	if err != nil {
+		t.Fatal(err)
+	}
+	vm := &VM{metrics: m}
+	handlers := map[string]*common.HTTPHandler{}
+	for api, service := range map[string]interface{}{
+		apiPublic: &PublicService{vm: vm},
+		apiAdmin:  &AdminService{vm: vm},
+	} {
+		h, err := newHandler(Name, service)
+		if err != nil {
+			t.Fatal(err)
+		}
+		vm.instrument(api, h)
+		handlers[api] = h
+	}
+
+	tt := []struct {
+		api    string
+		method string
+		errors float64
+	}{
+		{api: apiPublic, method: "spacesvm.ping"},
+		{api: apiPublic, method: "spacesvm.ping"},
+		{api: apiAdmin, method: "spacesvm.backup", errors: 1},
+		// Unknown methods are rejected before they are instrumented
+		{api: apiPublic, method: "spacesvm.unknown"},
+	}
+	for _, tv := range tt {
+		body := `{"jsonrpc":"2.0","method":"` + tv.method + `","params":{},"id":1}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		handlers[tv.api].Handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	for _, tv := range []struct {
+		api      string
+		method   string
+		requests float64
+		errors   float64
+	}{
+		{api: apiPublic, method: "spacesvm.ping", requests: 2},
+		{api: apiAdmin, method: "spacesvm.backup", requests: 1, errors: 1},
+		{api: apiPublic, method: "spacesvm.unknown"},
+	} {
+		if requests := testutil.ToFloat64(m.apiRequests.WithLabelValues(tv.api, tv.method)); requests != tv.requests {
+			t.Fatalf("%s %s: requests expected %f, got %f", tv.api, tv.method, tv.requests, requests)
+		}
+		if errors := testutil.ToFloat64(m.apiErrors.WithLabelValues(tv.api, tv.method)); errors != tv.errors {
+			t.Fatalf("%s %s: errors expected %f, got %f", tv.api, tv.method, tv.errors, errors)
+		}
+	}
+	if n := testutil.CollectAndCount(m.apiLatency); n != 2 {
+		t.Fatalf("expected latency for 2 methods, got %d", n)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/database"
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	log "github.com/inconshreveable/log15"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+// checkIntegrity verifies that the secondary indexes are consistent with the
+// stored state after an unclean shutdown and repairs any drift that is found
+// (ex: if the node crashed during a non-atomic maintenance operation).
+func (vm *VM) checkIntegrity(lastAccepted *chain.StatelessBlock) error {
+	start := time.Now()
+	vdb := versiondb.New(vm.db)
+	defer vdb.Abort()
+
+	txRepairs, err := vm.repairTxIndex(vdb, lastAccepted)
+	if err != nil {
+		return err
+	}
+	indexRepairs, err := chain.RepairIndexes(vdb)
+	if err != nil {
+		return err
+	}
+	if err := vdb.Commit(); err != nil {
+		return err
+	}
+	if txRepairs+indexRepairs > 0 {
+		log.Warn("repaired database inconsistencies",
+			"tx index", txRepairs, "indexes", indexRepairs,
+		)
+	}
+	log.Info("completed integrity check", "t", time.Since(start))
+	return nil
+}
+
+// repairTxIndex walks back from [lastAccepted] and repairs the tx index of
+// every block whose indexes are retained (or of the last
+// [IntegrityCheckDepth] blocks if indexes are kept forever). Blocks whose
+// indexes may have been trimmed are expected to be missing from the tx index.
+func (vm *VM) repairTxIndex(db database.Database, lastAccepted *chain.StatelessBlock) (int, error) {
+	cutoff, trimmed := vm.trimCutoff()
+	repairs := 0
+	blk := lastAccepted
+	for depth := uint64(0); blk.Hght > 0; depth++ {
+		if trimmed && blk.Tmstmp <= cutoff {
+			break
+		}
+		if !trimmed && depth >= vm.config.IntegrityCheckDepth {
+			break
+		}
+		r, err := chain.RepairTxIndex(db, blk)
+		repairs += r
+		if err != nil {
+			return repairs, err
+		}
+		blk, err = vm.GetStatelessBlock(blk.Prnt)
+		if err != nil {
+			return repairs, err
+		}
+	}
+	return repairs, nil
+}
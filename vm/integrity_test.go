@@ -0,0 +1,99 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/cache"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+func TestRepairTxIndex(t *testing.T) {
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := chain.DefaultGenesis()
+
+	tt := []struct {
+		name      string
+		retention time.Duration
+		depth     uint64
+		repaired  []bool // by height, oldest first
+	}{
+		{name: "kept forever", depth: 2, repaired: []bool{false, false, true, true}},
+		{name: "retained", retention: 30 * time.Second, depth: 1, repaired: []bool{false, true, true, true}},
+		{name: "trimmed", retention: -1, depth: 4, repaired: []bool{false, false, false, false}},
+	}
+	for _, tv := range tt {
+		vm := &VM{
+			db:             memdb.New(),
+			genesis:        g,
+			blocks:         &cache.LRU{Size: 10},
+			verifiedBlocks: make(map[ids.ID]*chain.StatelessBlock),
+			config:         Config{IndexRetention: tv.retention, IntegrityCheckDepth: tv.depth},
+		}
+		vm.clock.Set(time.Unix(45, 0))
+
+		// Blocks at heights 1-4 with timestamps 10-40 that are missing from
+		// the tx index
+		blks := make([]*chain.StatelessBlock, 0, 5)
+		prnt := ids.Empty
+		for i := 0; i < 5; i++ {
+			var txs []*chain.Transaction
+			if i > 0 {
+				utx := &chain.TransferTx{BaseTx: &chain.BaseTx{BlockID: prnt, Price: 1}, Units: uint64(i)}
+				dh, err := chain.DigestHash(utx)
+				if err != nil {
+					t.Fatal(err)
+				}
+				sig, err := chain.Sign(dh, priv)
+				if err != nil {
+					t.Fatal(err)
+				}
+				txs = append(txs, chain.NewTx(utx, sig))
+			}
+			blk, err := chain.ParseStatefulBlock(&chain.StatefulBlock{
+				Prnt:   prnt,
+				Hght:   uint64(i),
+				Tmstmp: int64(i * 10),
+				Txs:    txs,
+			}, nil, choices.Accepted, vm)
+			if err != nil {
+				t.Fatal(err)
+			}
+			vm.blocks.Put(blk.ID(), blk)
+			blks = append(blks, blk)
+			prnt = blk.ID()
+		}
+
+		repairs, err := vm.repairTxIndex(vm.db, blks[len(blks)-1])
+		if err != nil {
+			t.Fatalf("%s: %v", tv.name, err)
+		}
+		expected := 0
+		for i, repaired := range tv.repaired {
+			if repaired {
+				expected++
+			}
+			has, err := chain.HasTransaction(vm.db, blks[i+1].Txs[0].ID())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if has != repaired {
+				t.Fatalf("%s: expected tx at height %d repaired=%t", tv.name, i+1, repaired)
+			}
+		}
+		if repairs != expected {
+			t.Fatalf("%s: expected %d repairs, got %d", tv.name, expected, repairs)
+		}
+	}
+}
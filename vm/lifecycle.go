@@ -0,0 +1,77 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"context"
+	"sync"
+)
+
+// lifecycle tracks the background workers of the VM (block building, gossip,
+// pruning, and compaction) so [Shutdown] can stop them and wait for any
+// in-flight work to finish before the database is closed.
+type lifecycle struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newLifecycle() *lifecycle {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &lifecycle{ctx: ctx, cancel: cancel}
+}
+
+// Go runs [f] in a worker goroutine that [Stop] waits for. [f] must return
+// once [Done] is closed.
+func (l *lifecycle) Go(f func()) {
+	l.wg.Add(1)
+	go func() {
+		defer l.wg.Done()
+		f()
+	}()
+}
+
+// Done is closed once the VM starts shutting down
+func (l *lifecycle) Done() <-chan struct{} {
+	return l.ctx.Done()
+}
+
+// Stopped returns true once the VM starts shutting down
+func (l *lifecycle) Stopped() bool {
+	return l.ctx.Err() != nil
+}
+
+// Stop signals all workers to exit and waits for them to do so
+func (l *lifecycle) Stop() {
+	l.cancel()
+	l.wg.Wait()
+}
+
+// acquire calls [lock] and returns true once it succeeds, or returns false
+// (without holding the lock) if the VM starts shutting down first.
+//
+// [Shutdown] is called with ctx.Lock held and waits for all workers to exit,
+// so workers must not block on ctx.Lock indefinitely.
+func (l *lifecycle) acquire(lock func(), unlock func()) bool {
+	acquired := make(chan struct{})
+	go func() {
+		lock()
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		if l.Stopped() {
+			unlock()
+			return false
+		}
+		return true
+	case <-l.Done():
+		// Release the lock once it is eventually acquired
+		go func() {
+			<-acquired
+			unlock()
+		}()
+		return false
+	}
+}
@@ -0,0 +1,62 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLifecycleStopWaitsForWorkers(t *testing.T) {
+	l := newLifecycle()
+	finished := false
+	l.Go(func() {
+		<-l.Done()
+		// Simulate in-flight work finishing after the stop signal
+		time.Sleep(10 * time.Millisecond)
+		finished = true
+	})
+	l.Stop()
+	if !finished {
+		t.Fatal("expected Stop to wait for the worker to finish")
+	}
+	if !l.Stopped() {
+		t.Fatal("expected lifecycle to be stopped")
+	}
+}
+
+func TestLifecycleAcquire(t *testing.T) {
+	var lock sync.RWMutex
+	l := newLifecycle()
+	if !l.acquire(lock.Lock, lock.Unlock) {
+		t.Fatal("expected to acquire an unlocked lock")
+	}
+
+	// A worker blocked on the lock must exit once the VM stops, as
+	// [Shutdown] is called while holding it
+	result := make(chan bool)
+	l.Go(func() {
+		result <- l.acquire(lock.Lock, lock.Unlock)
+	})
+	done := make(chan struct{})
+	go func() {
+		l.Stop()
+		close(done)
+	}()
+	select {
+	case acquired := <-result:
+		if acquired {
+			t.Fatal("expected acquire to fail after stopping")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("worker blocked on lock after stopping")
+	}
+	<-done
+
+	// The lock is released by the abandoned acquisition once it is unlocked
+	lock.Unlock()
+	lock.Lock()
+	lock.Unlock()
+}
@@ -0,0 +1,40 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"net/http"
+	"time"
+)
+
+// limitHandler bounds the resources a single request to [h] may use. Request
+// bodies larger than [maxBytes] are rejected, requests that take longer than
+// [timeout] are aborted, and requests made while [slots] is full are
+// rejected instead of queuing. A zero [maxBytes] or [timeout] or nil [slots]
+// disables the corresponding limit.
+func limitHandler(h http.Handler, maxBytes int64, timeout time.Duration, slots chan struct{}) http.Handler {
+	if timeout > 0 {
+		h = http.TimeoutHandler(h, timeout, "request timed out")
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if maxBytes > 0 {
+			if r.ContentLength > maxBytes {
+				http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+		}
+		if slots != nil {
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			default:
+				w.Header().Set("Retry-After", "1")
+				http.Error(w, "too many concurrent requests", http.StatusServiceUnavailable)
+				return
+			}
+		}
+		h.ServeHTTP(w, r)
+	})
+}
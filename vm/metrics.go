@@ -0,0 +1,188 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/mempool"
+)
+
+// apiLabels identify the API (see [apiPublic] and [apiAdmin]) and JSON-RPC
+// method an API metric was recorded for
+var apiLabels = []string{"api", "method"}
+
+type metrics struct {
+	compactions     prometheus.Counter
+	compactFailures prometheus.Counter
+	compactSkipped  prometheus.Counter
+	compactDuration prometheus.Histogram
+	prunedSpaces    prometheus.Counter
+	prunedKeys      prometheus.Counter
+	reclaimedKeys   prometheus.Counter
+
+	reclaimedValues     prometheus.Counter
+	reclaimedValueBytes prometheus.Counter
+	trimmedIndexEntries prometheus.Counter
+	releasedBlocks      prometheus.Counter
+
+	senderRateLimited prometheus.Counter
+
+	connectedPeers      prometheus.Gauge
+	validators          prometheus.Gauge
+	connectedValidators prometheus.Gauge
+	incompatiblePeers   prometheus.Gauge
+
+	apiRequests *prometheus.CounterVec
+	apiErrors   *prometheus.CounterVec
+	apiLatency  *prometheus.HistogramVec
+}
+
+// registerMempoolMetrics exposes the size of [mempool] and the number of txs
+// that have left it for each [chain.DropReason]
+func registerMempoolMetrics(registerer prometheus.Registerer, mempool *mempool.Mempool) error {
+	errs := wrappers.Errs{}
+	errs.Add(registerer.Register(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: Name,
+		Name:      "mempool_size",
+		Help:      "Number of transactions in the mempool",
+	}, func() float64 { return float64(mempool.Len()) })))
+	for _, reason := range chain.DropReasons {
+		reason := reason
+		errs.Add(registerer.Register(prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace:   Name,
+			Name:        "mempool_dropped",
+			Help:        "Number of transactions that left the mempool by reason",
+			ConstLabels: prometheus.Labels{"reason": string(reason)},
+		}, func() float64 { return float64(mempool.Dropped(reason)) })))
+	}
+	return errs.Err
+}
+
+func newMetrics(registerer prometheus.Registerer) (*metrics, error) {
+	m := &metrics{
+		compactions: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: Name,
+			Name:      "compactions",
+			Help:      "Number of range compactions completed",
+		}),
+		compactFailures: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: Name,
+			Name:      "compaction_failures",
+			Help:      "Number of range compactions that failed",
+		}),
+		compactSkipped: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: Name,
+			Name:      "compactions_skipped",
+			Help:      "Number of scheduled compactions deferred because the VM was busy",
+		}),
+		compactDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: Name,
+			Name:      "compaction_duration_seconds",
+			Help:      "Time spent compacting a single range",
+			Buckets:   prometheus.ExponentialBuckets(0.001, 4, 10),
+		}),
+		prunedSpaces: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: Name,
+			Name:      "pruned_spaces",
+			Help:      "Number of expired spaces pruned",
+		}),
+		prunedKeys: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: Name,
+			Name:      "pruned_keys",
+			Help:      "Number of space keys deleted by pruning",
+		}),
+		reclaimedKeys: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: Name,
+			Name:      "reclaimed_keys",
+			Help:      "Number of pruned keys whose space was reclaimed by compaction",
+		}),
+		reclaimedValues: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: Name,
+			Name:      "reclaimed_values",
+			Help:      "Number of deleted or overwritten values reclaimed",
+		}),
+		releasedBlocks: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: Name,
+			Name:      "released_blocks",
+			Help:      "Number of stale processing blocks released because they conflict with the last accepted block",
+		}),
+		reclaimedValueBytes: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: Name,
+			Name:      "reclaimed_value_bytes",
+			Help:      "Bytes of deleted or overwritten values reclaimed",
+		}),
+		trimmedIndexEntries: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: Name,
+			Name:      "trimmed_index_entries",
+			Help:      "Number of tx index and history entries trimmed",
+		}),
+		senderRateLimited: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: Name,
+			Name:      "sender_rate_limited",
+			Help:      "Number of submitted transactions rejected by the per-sender rate limit",
+		}),
+		connectedPeers: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: Name,
+			Name:      "connected_peers",
+			Help:      "Number of peers the node is connected to",
+		}),
+		validators: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: Name,
+			Name:      "validators",
+			Help:      "Number of subnet validators as of the last health check",
+		}),
+		connectedValidators: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: Name,
+			Name:      "connected_validators",
+			Help:      "Number of subnet validators the node was connected to as of the last health check",
+		}),
+		incompatiblePeers: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: Name,
+			Name:      "incompatible_peers",
+			Help:      "Number of peers running an incompatible codec as of the last health check",
+		}),
+		apiRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Name,
+			Name:      "api_requests",
+			Help:      "Number of API requests served by method",
+		}, apiLabels),
+		apiErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: Name,
+			Name:      "api_errors",
+			Help:      "Number of API requests that returned an error by method",
+		}, apiLabels),
+		apiLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Name,
+			Name:      "api_latency_seconds",
+			Help:      "Time spent serving API requests by method",
+			Buckets:   prometheus.ExponentialBuckets(0.0005, 4, 10),
+		}, apiLabels),
+	}
+	errs := wrappers.Errs{}
+	errs.Add(
+		registerer.Register(m.compactions),
+		registerer.Register(m.compactFailures),
+		registerer.Register(m.compactSkipped),
+		registerer.Register(m.compactDuration),
+		registerer.Register(m.prunedSpaces),
+		registerer.Register(m.prunedKeys),
+		registerer.Register(m.reclaimedKeys),
+		registerer.Register(m.reclaimedValues),
+		registerer.Register(m.reclaimedValueBytes),
+		registerer.Register(m.trimmedIndexEntries),
+		registerer.Register(m.releasedBlocks),
+		registerer.Register(m.senderRateLimited),
+		registerer.Register(m.connectedPeers),
+		registerer.Register(m.validators),
+		registerer.Register(m.connectedValidators),
+		registerer.Register(m.incompatiblePeers),
+		registerer.Register(m.apiRequests),
+		registerer.Register(m.apiErrors),
+		registerer.Register(m.apiLatency),
+	)
+	return m, errs.Err
+}
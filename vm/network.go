@@ -4,6 +4,8 @@
 package vm
 
 import (
+	"sort"
+
 	"github.com/ava-labs/avalanchego/cache"
 	"github.com/ava-labs/avalanchego/ids"
 	log "github.com/inconshreveable/log15"
@@ -27,11 +29,25 @@ func (vm *VM) NewPushNetwork() *PushNetwork {
 	}
 }
 
+// sendTxs gossips [txs] in messages of at most [Config.GossipMaxTxs] txs.
 func (n *PushNetwork) sendTxs(txs []*chain.Transaction) error {
-	if len(txs) == 0 {
-		return nil
+	size := n.vm.config.GossipMaxTxs
+	if size <= 0 {
+		size = len(txs)
+	}
+	for len(txs) > 0 {
+		if size > len(txs) {
+			size = len(txs)
+		}
+		if err := n.sendMessage(txs[:size]); err != nil {
+			return err
+		}
+		txs = txs[size:]
 	}
+	return nil
+}
 
+func (n *PushNetwork) sendMessage(txs []*chain.Transaction) error {
 	b, err := chain.Marshal(txs)
 	if err != nil {
 		log.Warn("failed to marshal txs", "error", err)
@@ -42,7 +58,13 @@ func (n *PushNetwork) sendTxs(txs []*chain.Transaction) error {
 		"txs", len(txs),
 		"size", len(b),
 	)
-	if err := n.vm.appSender.SendAppGossip(b); err != nil {
+	// Skip peers that could not parse the txs, if any are known, and sample
+	// [Config.GossipFanout] peers
+	send := n.vm.appSender.SendAppGossip
+	if targets, ok := n.vm.peers.gossipTargets(n.vm.config.GossipFanout); ok {
+		send = func(b []byte) error { return n.vm.appSender.SendAppGossipSpecific(targets, b) }
+	}
+	if err := send(b); err != nil {
 		log.Warn(
 			"GossipTxs failed",
 			"error", err,
@@ -53,13 +75,32 @@ func (n *PushNetwork) sendTxs(txs []*chain.Transaction) error {
 	return nil
 }
 
+// gossipUnits returns the maximum load units of the txs gossiped at once.
+func (n *PushNetwork) gossipUnits() uint64 {
+	if max := n.vm.config.GossipMaxUnits; max > 0 {
+		return max
+	}
+	return n.vm.genesis.TargetBlockSize
+}
+
+// GossipNewTxs gossips [newTxs] that were not recently gossiped, the highest
+// paying per unit first, up to [Config.GossipMaxUnits].
 func (n *PushNetwork) GossipNewTxs(newTxs []*chain.Transaction) error {
 	if n.vm.appSender == nil {
 		return nil
 	}
+	sorted := make([]*chain.Transaction, len(newTxs))
+	copy(sorted, newTxs)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].GetPrice() > sorted[j].GetPrice() })
+
 	txs := []*chain.Transaction{}
-	// Gossip at most the target units of a block at once
-	for _, tx := range newTxs {
+	units, maxUnits := uint64(0), n.gossipUnits()
+	for _, tx := range sorted {
+		txUnits := tx.LoadUnits(n.vm.genesis)
+		if txUnits > maxUnits-units {
+			// Left for regossip
+			continue
+		}
 		// skip if recently gossiped
 		// to further protect the node from being
 		// DDOSed via repeated gossip failures
@@ -67,8 +108,9 @@ func (n *PushNetwork) GossipNewTxs(newTxs []*chain.Transaction) error {
 			log.Debug("already gossiped, skipping", "txId", tx.ID())
 			continue
 		}
-		n.gossipedTxs.Put(tx.ID(), nil)
+		n.gossipedTxs.Put(tx.ID(), 0)
 		txs = append(txs, tx)
+		units += txUnits
 	}
 
 	return n.sendTxs(txs)
@@ -82,13 +124,25 @@ func (n *PushNetwork) RegossipTxs() error {
 	}
 	txs := []*chain.Transaction{}
 	units := uint64(0)
-	// Gossip at most the target units of a block at once
-	for n.vm.mempool.Len() > 0 && units < n.vm.genesis.TargetBlockSize {
-		tx, _ := n.vm.mempool.PopMax()
+	// Gossip at most [gossipUnits] at once, the highest paying first
+	for _, tx := range n.vm.mempool.Txs() {
+		if units >= n.gossipUnits() {
+			break
+		}
 
 		// Note: when regossiping, we force resend eventhough we may have done it
-		// recently.
-		n.gossipedTxs.Put(tx.ID(), nil)
+		// recently (up to [Config.GossipMaxResends] times). Txs that were
+		// resent too many times stay pending locally.
+		resends := 0
+		if v, ok := n.gossipedTxs.Get(tx.ID()); ok {
+			resends = v.(int) + 1
+		}
+		if max := n.vm.config.GossipMaxResends; max > 0 && resends > max {
+			log.Debug("resent too many times, skipping", "txId", tx.ID())
+			continue
+		}
+		n.vm.mempool.Drop(tx.ID(), chain.DropGossiped)
+		n.gossipedTxs.Put(tx.ID(), resends)
 		txs = append(txs, tx)
 		units += tx.LoadUnits(n.vm.genesis)
 	}
@@ -112,6 +166,11 @@ func (vm *VM) AppGossip(nodeID ids.NodeID, msg []byte) error {
 		"bytes", len(msg),
 	)
 
+	if vm.workers.Stopped() {
+		// Gossip received while shutting down would only be flushed again
+		return nil
+	}
+
 	txs := make([]*chain.Transaction, 0)
 	if _, err := chain.Unmarshal(msg, &txs); err != nil {
 		log.Debug(
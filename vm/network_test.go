@@ -0,0 +1,143 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/mempool"
+)
+
+var _ common.AppSender = &gossipRecorder{}
+
+// gossipRecorder records the gossip messages sent and their targets (nil
+// when sent to every peer)
+type gossipRecorder struct {
+	msgs    [][]byte
+	targets []ids.NodeIDSet
+}
+
+func (r *gossipRecorder) SendAppGossip(b []byte) error {
+	r.msgs = append(r.msgs, b)
+	r.targets = append(r.targets, nil)
+	return nil
+}
+
+func (r *gossipRecorder) SendAppGossipSpecific(targets ids.NodeIDSet, b []byte) error {
+	r.msgs = append(r.msgs, b)
+	r.targets = append(r.targets, targets)
+	return nil
+}
+
+func (r *gossipRecorder) SendAppRequest(ids.NodeIDSet, uint32, []byte) error { return nil }
+func (r *gossipRecorder) SendAppResponse(ids.NodeID, uint32, []byte) error   { return nil }
+
+func TestGossipConfig(t *testing.T) {
+	t.Parallel()
+
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := chain.DefaultGenesis()
+	txs := make([]*chain.Transaction, 5)
+	for i := range txs {
+		utx := &chain.TransferTx{BaseTx: &chain.BaseTx{BlockID: ids.GenerateTestID(), Price: uint64(i + 1)}, Units: 1}
+		dh, err := chain.DigestHash(utx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sig, err := chain.Sign(dh, priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		txs[i] = chain.NewTx(utx, sig)
+		if err := txs[i].Init(g); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sender := &gossipRecorder{}
+	vm := &VM{
+		genesis:   g,
+		appSender: sender,
+		peers:     newPeerTracker(),
+		mempool:   mempool.New(g, 8),
+		config:    Config{GossipFanout: 2, GossipMaxTxs: 2, GossipMaxResends: 1},
+	}
+	for i := 0; i < 3; i++ {
+		vm.peers.connect(ids.GenerateTestNodeID())
+	}
+	n := vm.NewPushNetwork()
+	if err := n.GossipNewTxs(txs); err != nil {
+		t.Fatal(err)
+	}
+	// 5 txs are split into messages of at most 2, each sent to 2 peers
+	if len(sender.msgs) != 3 {
+		t.Fatalf("expected 3 messages, got %d", len(sender.msgs))
+	}
+	var first []*chain.Transaction
+	if _, err := chain.Unmarshal(sender.msgs[0], &first); err != nil {
+		t.Fatal(err)
+	}
+	if len(first) != 2 || first[0].GetPrice() != 5 || first[1].GetPrice() != 4 {
+		t.Fatal("expected the highest paying txs to be gossiped first")
+	}
+	for _, targets := range sender.targets {
+		if targets.Len() != 2 {
+			t.Fatalf("expected 2 targets, got %d", targets.Len())
+		}
+	}
+
+	// Recently gossiped txs are not gossiped again
+	sender.msgs = nil
+	if err := n.GossipNewTxs(txs); err != nil {
+		t.Fatal(err)
+	}
+	if len(sender.msgs) != 0 {
+		t.Fatalf("expected no messages, got %d", len(sender.msgs))
+	}
+
+	// Txs are only resent [GossipMaxResends] times
+	for _, sent := range []int{1, 0} {
+		sender.msgs = nil
+		vm.mempool.Add(txs[0])
+		if err := n.RegossipTxs(); err != nil {
+			t.Fatal(err)
+		}
+		if len(sender.msgs) != sent {
+			t.Fatalf("expected %d messages, got %d", sent, len(sender.msgs))
+		}
+	}
+	// Txs that are no longer resent stay pending locally
+	if !vm.mempool.Has(txs[0].ID()) {
+		t.Fatal("expected tx to stay in the mempool")
+	}
+	if reason, ok := vm.mempool.DropReason(txs[0].ID()); ok {
+		t.Fatalf("expected pending tx to have no drop reason, got %q", reason)
+	}
+}
+
+func TestPeerTrackerFanout(t *testing.T) {
+	t.Parallel()
+
+	p := newPeerTracker()
+	for i := 0; i < 4; i++ {
+		p.connect(ids.GenerateTestNodeID())
+	}
+	targets, ok := p.gossipTargets(3)
+	if !ok || targets.Len() != 3 {
+		t.Fatalf("expected 3 sampled targets, got %d (restricted=%t)", targets.Len(), ok)
+	}
+	for _, fanout := range []int{0, 4, 5} {
+		if _, ok := p.gossipTargets(fanout); ok {
+			t.Fatalf("fanout %d should gossip to every peer", fanout)
+		}
+	}
+}
@@ -4,6 +4,7 @@
 package vm
 
 import (
+	"math"
 	"time"
 
 	"github.com/ava-labs/avalanchego/database/versiondb"
@@ -12,31 +13,128 @@ import (
 	"github.com/ava-labs/spacesvm/chain"
 )
 
-func (vm *VM) pruneCall() bool {
-	// Lock to prevent concurrent modification of state
-	vm.ctx.Lock.Lock()
-	defer vm.ctx.Lock.Unlock()
+// PruneStats summarize the work done by pruning.
+type PruneStats struct {
+	// Expired spaces whose remaining keys were deleted
+	Spaces int `serialize:"true" json:"spaces"`
+	Keys   int `serialize:"true" json:"keys"`
+	// Deleted or overwritten values reclaimed under
+	// [Config.DeletedValueRetention]
+	Values     int    `serialize:"true" json:"values"`
+	ValueBytes uint64 `serialize:"true" json:"valueBytes"`
+	// Accepted blocks whose indexes were trimmed under
+	// [Config.IndexRetention]
+	TrimmedBlocks int `serialize:"true" json:"trimmedBlocks"`
+	IndexEntries  int `serialize:"true" json:"indexEntries"`
+}
+
+// reclaimHeight returns the highest block height whose removed values may be
+// reclaimed, or false if none may be.
+func (vm *VM) reclaimHeight() (uint64, bool) {
+	retention := vm.config.DeletedValueRetention
+	if retention < 0 || vm.lastAccepted.Hght < uint64(retention) {
+		return 0, false
+	}
+	return vm.lastAccepted.Hght - uint64(retention), true
+}
+
+// trimCutoff returns the latest block timestamp whose indexes may be trimmed,
+// or false if indexes are kept forever.
+func (vm *VM) trimCutoff() (int64, bool) {
+	switch retention := vm.config.IndexRetention; {
+	case retention == 0:
+		return 0, false
+	case retention < 0:
+		return math.MaxInt64, true
+	default:
+		return vm.clock.Time().Add(-retention).Unix(), true
+	}
+}
 
+// pruneNext prunes up to [PruneLimit] expired spaces, reclaims up to
+// [PruneLimit] removed values, and trims the indexes of up to [PruneLimit]
+// blocks, adding the work done to [stats]. It returns true if any limit was
+// reached. Assumes ctx.Lock is held.
+func (vm *VM) pruneNext(stats *PruneStats) (bool, error) {
 	vdb := versiondb.New(vm.db)
 	defer vdb.Abort()
-	removals, err := chain.PruneNext(vdb, vm.config.PruneLimit)
+	removals, keys, err := chain.PruneNext(vdb, vm.config.PruneLimit)
 	if err != nil {
-		log.Warn("unable to prune next range", "error", err)
-		return false
+		return false, err
+	}
+	values, valueBytes := 0, uint64(0)
+	if height, ok := vm.reclaimHeight(); ok {
+		values, valueBytes, err = chain.ReclaimNext(vdb, height, vm.config.PruneLimit)
+		if err != nil {
+			return false, err
+		}
+	}
+	trimmed, entries := 0, 0
+	if cutoff, ok := vm.trimCutoff(); ok {
+		trimmed, entries, err = chain.TrimIndexes(vdb, cutoff, vm.config.PruneLimit)
+		if err != nil {
+			return false, err
+		}
 	}
 	if err := vdb.Commit(); err != nil {
-		log.Warn("unable to commit pruning work", "error", err)
-		return false
+		return false, err
 	}
 	if err := vm.lastAccepted.SetChildrenDB(vm.db); err != nil {
 		log.Error("unable to update child databases of last accepted block", "error", err)
 	}
-	return removals == vm.config.PruneLimit
+	vm.metrics.prunedSpaces.Add(float64(removals))
+	vm.metrics.prunedKeys.Add(float64(keys))
+	vm.metrics.reclaimedValues.Add(float64(values))
+	vm.metrics.reclaimedValueBytes.Add(float64(valueBytes))
+	vm.metrics.trimmedIndexEntries.Add(float64(entries))
+	vm.unreclaimedKeys += keys
+
+	stats.Spaces += removals
+	stats.Keys += keys
+	stats.Values += values
+	stats.ValueBytes += valueBytes
+	stats.TrimmedBlocks += trimmed
+	stats.IndexEntries += entries
+	limit := vm.config.PruneLimit
+	return removals == limit || values == limit || trimmed == limit, nil
+}
+
+func (vm *VM) pruneCall() bool {
+	// Lock to prevent concurrent modification of state
+	if !vm.workers.acquire(vm.ctx.Lock.Lock, vm.ctx.Lock.Unlock) {
+		return false
+	}
+	defer vm.ctx.Lock.Unlock()
+
+	vm.metrics.releasedBlocks.Add(float64(vm.releaseStaleBlocks()))
+	more, err := vm.pruneNext(&PruneStats{})
+	if err != nil {
+		log.Warn("unable to prune next range", "error", err)
+		return false
+	}
+	return more
+}
+
+// PruneAll prunes every expired space, reclaims every removed value, and
+// trims every index that is eligible under the retention config immediately.
+func (vm *VM) PruneAll() (*PruneStats, error) {
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	stats := &PruneStats{}
+	for {
+		more, err := vm.pruneNext(stats)
+		if err != nil {
+			return stats, err
+		}
+		if !more {
+			return stats, nil
+		}
+	}
 }
 
 func (vm *VM) prune() {
 	log.Debug("starting prune loops")
-	defer close(vm.donePrune)
 
 	// should retry less aggressively
 	t := time.NewTimer(vm.config.PruneInterval)
@@ -45,7 +143,7 @@ func (vm *VM) prune() {
 	for {
 		select {
 		case <-t.C:
-		case <-vm.stop:
+		case <-vm.workers.Done():
 			return
 		}
 		if vm.pruneCall() {
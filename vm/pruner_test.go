@@ -0,0 +1,31 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestTrimCutoff(t *testing.T) {
+	vm := &VM{}
+	vm.clock.Set(time.Unix(1000, 0))
+
+	if _, ok := vm.trimCutoff(); ok {
+		t.Fatal("expected indexes to be kept forever")
+	}
+	vm.config.IndexRetention = -1
+	if cutoff, ok := vm.trimCutoff(); !ok || cutoff != math.MaxInt64 {
+		t.Fatalf("expected every index to be trimmed, got %d (%t)", cutoff, ok)
+	}
+	vm.config.IndexRetention = time.Minute
+	if cutoff, ok := vm.trimCutoff(); !ok || cutoff != 940 {
+		t.Fatalf("expected cutoff 940, got %d (%t)", cutoff, ok)
+	}
+	vm.clock.Set(time.Unix(2000, 0))
+	if cutoff, ok := vm.trimCutoff(); !ok || cutoff != 1940 {
+		t.Fatalf("expected cutoff 1940, got %d (%t)", cutoff, ok)
+	}
+}
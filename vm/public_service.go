@@ -4,10 +4,15 @@
 package vm
 
 import (
+	"encoding/binary"
 	"fmt"
 	"net/http"
+	"strings"
 
+	"github.com/ava-labs/avalanchego/database"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/vms/secp256k1fx"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	log "github.com/inconshreveable/log15"
@@ -15,6 +20,7 @@ import (
 	"github.com/ava-labs/spacesvm/chain"
 	"github.com/ava-labs/spacesvm/parser"
 	"github.com/ava-labs/spacesvm/tdata"
+	"github.com/ava-labs/spacesvm/version"
 )
 
 type PublicService struct {
@@ -46,10 +52,13 @@ func (svc *PublicService) Network(_ *http.Request, _ *struct{}, reply *NetworkRe
 
 type GenesisReply struct {
 	Genesis *chain.Genesis `serialize:"true" json:"genesis"`
+	// Params is the rule set the node runs with this genesis
+	Params *chain.Params `serialize:"true" json:"params"`
 }
 
 func (svc *PublicService) Genesis(_ *http.Request, _ *struct{}, reply *GenesisReply) (err error) {
 	reply.Genesis = svc.vm.Genesis()
+	reply.Params = reply.Genesis.Params()
 	return nil
 }
 
@@ -66,21 +75,9 @@ func (svc *PublicService) IssueRawTx(_ *http.Request, args *IssueRawTxArgs, repl
 	if _, err := chain.Unmarshal(args.Tx, tx); err != nil {
 		return err
 	}
-
-	// otherwise, unexported tx.id field is empty
-	if err := tx.Init(svc.vm.genesis); err != nil {
-		return err
-	}
-	reply.TxID = tx.ID()
-
-	errs := svc.vm.Submit(tx)
-	if len(errs) == 0 {
-		return nil
-	}
-	if len(errs) == 1 {
-		return errs[0]
-	}
-	return fmt.Errorf("%v", errs)
+	txID, err := svc.submit(tx)
+	reply.TxID = txID
+	return err
 }
 
 type IssueTxArgs struct {
@@ -100,22 +97,140 @@ func (svc *PublicService) IssueTx(_ *http.Request, args *IssueTxArgs, reply *Iss
 	if err != nil {
 		return err
 	}
-	tx := chain.NewTx(utx, args.Signature[:])
+	reply.TxID, err = svc.submit(chain.NewTx(utx, args.Signature[:]))
+	return err
+}
 
+// submit initializes [tx] and adds it to the mempool, returning its ID
+func (svc *PublicService) submit(tx *chain.Transaction) (ids.ID, error) {
 	// otherwise, unexported tx.id field is empty
 	if err := tx.Init(svc.vm.genesis); err != nil {
-		return err
+		return ids.Empty, err
 	}
-	reply.TxID = tx.ID()
 
 	errs := svc.vm.Submit(tx)
 	if len(errs) == 0 {
-		return nil
+		return tx.ID(), nil
 	}
 	if len(errs) == 1 {
-		return errs[0]
+		return tx.ID(), errs[0]
+	}
+	return tx.ID(), fmt.Errorf("%v", errs)
+}
+
+type EncodeTxArgs struct {
+	Input *chain.Input `serialize:"true" json:"input"`
+	// Tx is encoded instead of [Input] if set. Its block ID and price are
+	// used unless overridden below.
+	Tx *chain.TxJSON `serialize:"true" json:"tx,omitempty"`
+	// Defaults to the last accepted block and the suggested price when empty
+	BlockID ids.ID `serialize:"true" json:"blockId"`
+	Price   uint64 `serialize:"true" json:"price"`
+}
+
+type EncodeTxReply struct {
+	TypedData *tdata.TypedData `serialize:"true" json:"typedData"`
+	// Tx is the canonical JSON encoding of the tx (see [chain.TxJSON])
+	Tx *chain.TxJSON `serialize:"true" json:"tx"`
+	// EIP-712 digest that must be signed to issue the tx
+	Digest hexutil.Bytes `serialize:"true" json:"digest"`
+	// Action is the hash a space co-signer approves to allow the tx (see
+	// [chain.ActionHash])
+	Action    common.Hash `serialize:"true" json:"action"`
+	BlockID   ids.ID      `serialize:"true" json:"blockId"`
+	Price     uint64      `serialize:"true" json:"price"`
+	TotalCost uint64      `serialize:"true" json:"totalCost"`
+}
+
+// EncodeTx canonically encodes [args.Input] and returns the digest to sign.
+// The signature can be issued with [IssueInputTx] using the returned block
+// ID and price.
+func (svc *PublicService) EncodeTx(_ *http.Request, args *EncodeTxArgs, reply *EncodeTxReply) error {
+	utx, err := svc.unsignedTx(args.Input, args.Tx, args.BlockID, args.Price)
+	if err != nil {
+		return err
+	}
+	reply.Digest, err = chain.DigestHash(utx)
+	if err != nil {
+		return err
+	}
+	reply.Action, err = chain.ActionHash(utx)
+	if err != nil {
+		return err
+	}
+	reply.TypedData = utx.TypedData()
+	reply.Tx = &chain.TxJSON{UnsignedTransaction: utx}
+	reply.BlockID = utx.GetBlockID()
+	reply.Price = utx.GetPrice()
+	reply.TotalCost = utx.FeeUnits(svc.vm.genesis) * reply.Price
+	return nil
+}
+
+type IssueInputTxArgs struct {
+	Input     *chain.Input  `serialize:"true" json:"input"`
+	Tx        *chain.TxJSON `serialize:"true" json:"tx,omitempty"`
+	BlockID   ids.ID        `serialize:"true" json:"blockId"`
+	Price     uint64        `serialize:"true" json:"price"`
+	Signature hexutil.Bytes `serialize:"true" json:"signature"`
+}
+
+// IssueInputTx issues a tx described by [args.Input] and signed over the
+// digest returned by [EncodeTx].
+func (svc *PublicService) IssueInputTx(_ *http.Request, args *IssueInputTxArgs, reply *IssueTxReply) error {
+	if args.BlockID == ids.Empty {
+		return ErrBlockIDIsEmpty
+	}
+	utx, err := svc.unsignedTx(args.Input, args.Tx, args.BlockID, args.Price)
+	if err != nil {
+		return err
+	}
+	reply.TxID, err = svc.submit(chain.NewTx(utx, args.Signature[:]))
+	return err
+}
+
+// unsignedTx decodes [input] (or copies [tx] if set) into a transaction at
+// [blockID] and [price], defaulting to those of [tx] and then to the last
+// accepted block and the suggested price when empty.
+func (svc *PublicService) unsignedTx(
+	input *chain.Input,
+	tx *chain.TxJSON,
+	blockID ids.ID,
+	price uint64,
+) (utx chain.UnsignedTransaction, err error) {
+	switch {
+	case tx != nil && tx.UnsignedTransaction != nil:
+		utx = tx.Copy()
+		if blockID == ids.Empty {
+			blockID = utx.GetBlockID()
+		}
+		if price == 0 {
+			price = utx.GetPrice()
+		}
+	case input != nil:
+		utx, err = input.Decode()
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, ErrInputIsNil
+	}
+	p := &chain.TxParams{
+		Genesis: svc.vm.genesis,
+		ChainID: svc.vm.ctx.ChainID,
+		BlockID: blockID,
+		Price:   price,
 	}
-	return fmt.Errorf("%v", errs)
+	if blockID == ids.Empty {
+		p.BlockID = svc.vm.lastAccepted.ID()
+	}
+	if price == 0 {
+		p.Price, p.BlockCost, err = svc.vm.SuggestedFee()
+		if err != nil {
+			return nil, err
+		}
+	}
+	p.Prepare(utx)
+	return utx, nil
 }
 
 type HasTxArgs struct {
@@ -147,6 +262,18 @@ func (svc *PublicService) LastAccepted(_ *http.Request, _ *struct{}, reply *Last
 	return nil
 }
 
+type FrontierReply struct {
+	Frontier *Frontier `serialize:"true" json:"frontier"`
+}
+
+// Frontier returns the last accepted block and the blocks that have been
+// verified but not yet decided (with their parents), so operators can spot
+// stalls and forks.
+func (svc *PublicService) Frontier(_ *http.Request, _ *struct{}, reply *FrontierReply) error {
+	reply.Frontier = svc.vm.frontier()
+	return nil
+}
+
 type SuggestedFeeArgs struct {
 	Input *chain.Input `serialize:"true" json:"input"`
 }
@@ -156,36 +283,69 @@ type SuggestedFeeReply struct {
 	TotalCost uint64           `serialize:"true" json:"totalCost"`
 }
 
+// SuggestedFee returns the typed data and cost of [args.Input] at the
+// suggested price. Set inputs that would exceed the storage limits of the
+// genesis are rejected.
 func (svc *PublicService) SuggestedFee(
 	_ *http.Request,
 	args *SuggestedFeeArgs,
 	reply *SuggestedFeeReply,
 ) error {
-	if args.Input == nil {
-		return ErrInputIsNil
-	}
-	utx, err := args.Input.Decode()
+	utx, err := svc.unsignedTx(args.Input, nil, ids.Empty, 0)
 	if err != nil {
 		return err
 	}
+	if err := svc.checkStorage(utx); err != nil {
+		return err
+	}
+	reply.TypedData = utx.TypedData()
+	reply.TotalCost = utx.FeeUnits(svc.vm.genesis) * utx.GetPrice()
+	return nil
+}
 
-	// Determine suggested fee
-	price, cost, err := svc.vm.SuggestedFee()
+// checkStorage returns an error if [utx] sets a value that would exceed the
+// storage limits of the genesis in the last accepted state.
+func (svc *PublicService) checkStorage(utx chain.UnsignedTransaction) error {
+	g := svc.vm.genesis
+	if c, ok := utx.(*chain.ClaimTx); ok {
+		return checkClaimStorage(g, c)
+	}
+	s, ok := utx.(*chain.SetTx)
+	if !ok {
+		return nil
+	}
+	if err := g.CheckValue(s.Key, uint64(len(s.Value))); err != nil {
+		return err
+	}
+	i, exists, err := chain.GetSpaceInfo(svc.vm.db, []byte(s.Space))
+	if err != nil || !exists {
+		return err
+	}
+	size := i.Size + uint64(len(s.Value))
+	v, exists, err := chain.GetValueMeta(svc.vm.db, []byte(s.Space), []byte(s.Key))
 	if err != nil {
 		return err
 	}
-	g := svc.vm.genesis
-	fu := utx.FeeUnits(g)
-	price += cost / fu
-
-	// Update meta
-	utx.SetBlockID(svc.vm.lastAccepted.ID())
-	utx.SetMagic(g.Magic)
-	utx.SetPrice(price)
+	if exists {
+		size -= v.Size
+	}
+	return g.CheckSpaceSize(size)
+}
 
-	reply.TypedData = utx.TypedData()
-	reply.TotalCost = fu * price
-	return nil
+// checkClaimStorage returns an error if the keys set by [c] exceed the
+// storage limits of the genesis (the claimed space starts empty).
+func checkClaimStorage(g *chain.Genesis, c *chain.ClaimTx) error {
+	if uint64(len(c.Keys)) > g.MaxClaimKeys {
+		return fmt.Errorf("%w: max=%d found=%d", chain.ErrTooManyClaimKeys, g.MaxClaimKeys, len(c.Keys))
+	}
+	size := uint64(0)
+	for _, ck := range c.Keys {
+		if err := g.CheckValue(ck.Key, uint64(len(ck.Value))); err != nil {
+			return err
+		}
+		size += uint64(len(ck.Value))
+	}
+	return g.CheckSpaceSize(size)
 }
 
 type SuggestedRawFeeReply struct {
@@ -209,6 +369,12 @@ func (svc *PublicService) SuggestedRawFee(
 
 type ClaimedArgs struct {
 	Space string `serialize:"true" json:"space"`
+	// Preferred serves the read from the preferred block's state instead of
+	// the last accepted state
+	Preferred bool `serialize:"true" json:"preferred,omitempty"`
+	// Pending overlays the transactions in the mempool on the state
+	// (best-effort)
+	Pending bool `serialize:"true" json:"pending,omitempty"`
 }
 
 type ClaimedReply struct {
@@ -219,7 +385,11 @@ func (svc *PublicService) Claimed(_ *http.Request, args *ClaimedArgs, reply *Cla
 	if err := parser.CheckContents(args.Space); err != nil {
 		return err
 	}
-	has, err := chain.HasSpace(svc.vm.db, []byte(args.Space))
+	db, err := svc.vm.readState(args.Preferred, args.Pending)
+	if err != nil {
+		return err
+	}
+	has, err := chain.HasSpace(db, []byte(args.Space))
 	if err != nil {
 		return err
 	}
@@ -228,7 +398,9 @@ func (svc *PublicService) Claimed(_ *http.Request, args *ClaimedArgs, reply *Cla
 }
 
 type InfoArgs struct {
-	Space string `serialize:"true" json:"space"`
+	Space     string `serialize:"true" json:"space"`
+	Preferred bool   `serialize:"true" json:"preferred,omitempty"`
+	Pending   bool   `serialize:"true" json:"pending,omitempty"`
 }
 
 type InfoReply struct {
@@ -241,7 +413,14 @@ func (svc *PublicService) Info(_ *http.Request, args *InfoArgs, reply *InfoReply
 		return err
 	}
 
-	i, exists, err := chain.GetSpaceInfo(svc.vm.db, []byte(args.Space))
+	if svc.vm.denylist.denied(args.Space) {
+		return fmt.Errorf("%w: %s", ErrSpaceDenied, args.Space)
+	}
+	db, err := svc.vm.readState(args.Preferred, args.Pending)
+	if err != nil {
+		return err
+	}
+	i, exists, err := chain.GetSpaceInfo(db, []byte(args.Space))
 	if err != nil {
 		return err
 	}
@@ -249,7 +428,7 @@ func (svc *PublicService) Info(_ *http.Request, args *InfoArgs, reply *InfoReply
 		return chain.ErrSpaceMissing
 	}
 
-	kvs, err := chain.GetAllValueMetas(svc.vm.db, i.RawSpace)
+	kvs, err := chain.GetAllValueMetas(db, i.RawSpace)
 	if err != nil {
 		return err
 	}
@@ -258,31 +437,147 @@ func (svc *PublicService) Info(_ *http.Request, args *InfoArgs, reply *InfoReply
 	return nil
 }
 
+type EstimateLifelineArgs struct {
+	Space string `serialize:"true" json:"space"`
+	Units uint64 `serialize:"true" json:"units"`
+	// If set, [Units] defaults to the number needed to extend the space by
+	// exactly [Extension] seconds
+	Extension uint64 `serialize:"true" json:"extension"`
+}
+
+type EstimateLifelineReply struct {
+	Units    uint64 `serialize:"true" json:"units"`
+	FeeUnits uint64 `serialize:"true" json:"feeUnits"`
+	// Cost at the suggested price
+	TotalCost uint64 `serialize:"true" json:"totalCost"`
+	// Seconds added to the life of the space
+	Extension uint64 `serialize:"true" json:"extension"`
+	Expiry    uint64 `serialize:"true" json:"expiry"`
+}
+
+// EstimateLifeline returns the cost of extending the life of [args.Space] by
+// [args.Units] (or exactly [args.Extension] seconds) and its resulting expiry.
+func (svc *PublicService) EstimateLifeline(_ *http.Request, args *EstimateLifelineArgs, reply *EstimateLifelineReply) error {
+	if err := parser.CheckContents(args.Space); err != nil {
+		return err
+	}
+	if args.Units == 0 && args.Extension == 0 {
+		return chain.ErrNonActionable
+	}
+	i, exists, err := chain.GetSpaceInfo(svc.vm.db, []byte(args.Space))
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return chain.ErrSpaceMissing
+	}
+
+	g := svc.vm.genesis
+	horizon := chain.SpaceHorizon(g, i)
+	units, extension := args.Units, args.Extension
+	if extension > 0 {
+		if extension > g.MaxLifelineExtension {
+			return fmt.Errorf("%w: max=%d found=%d", chain.ErrExtensionTooLong, g.MaxLifelineExtension, extension)
+		}
+		if units == 0 {
+			units = chain.RequiredLifelineUnits(horizon, i.Units, extension)
+		}
+		if credit := chain.LifelineCredit(horizon, i.Units, units); credit < extension {
+			return fmt.Errorf("%w: credit=%d extension=%d", chain.ErrInsufficientLifeline, credit, extension)
+		}
+	} else {
+		extension = chain.LifelineCredit(horizon, i.Units, units)
+	}
+
+	utx := &chain.LifelineTx{BaseTx: &chain.BaseTx{}, Space: args.Space, Units: units, Extension: args.Extension}
+	price, cost, err := svc.vm.SuggestedFee()
+	if err != nil {
+		return err
+	}
+	reply.Units = units
+	reply.FeeUnits = utx.FeeUnits(g)
+	reply.TotalCost = reply.FeeUnits * (price + cost/reply.FeeUnits)
+	reply.Extension = extension
+	reply.Expiry = i.Expiry + extension
+	return nil
+}
+
 type ResolveArgs struct {
 	Path string `serialize:"true" json:"path"`
+	// IfNoneMatch is the ETag of a previously resolved value. If it still
+	// matches, the value is omitted from the reply.
+	IfNoneMatch string `serialize:"true" json:"ifNoneMatch,omitempty"`
+	// Preferred serves the read from the preferred block's state instead of
+	// the last accepted state
+	Preferred bool `serialize:"true" json:"preferred,omitempty"`
+	// Pending overlays the transactions in the mempool on the state
+	// (best-effort)
+	Pending bool `serialize:"true" json:"pending,omitempty"`
 }
 
 type ResolveReply struct {
 	Exists    bool             `serialize:"true" json:"exists"`
 	Value     []byte           `serialize:"true" json:"value"`
 	ValueMeta *chain.ValueMeta `serialize:"true" json:"valueMeta"`
+	// ETag is the quoted hash of the value (only set if it exists)
+	ETag string `serialize:"true" json:"etag,omitempty"`
+	// NotModified is true when [ResolveArgs.IfNoneMatch] matched [ETag], in
+	// which case [Value] is omitted
+	NotModified bool `serialize:"true" json:"notModified,omitempty"`
+	// Attestation is the node's signature over the response (only set when
+	// the node is configured with a [ResponseKey])
+	Attestation *Attestation `serialize:"true" json:"attestation,omitempty"`
 }
 
 func (svc *PublicService) Resolve(_ *http.Request, args *ResolveArgs, reply *ResolveReply) error {
-	space, key, err := parser.ResolvePath(args.Path)
+	space, _, err := parser.ResolvePath(args.Path)
 	if err != nil {
 		return err
 	}
+	if svc.vm.denylist.denied(space) {
+		return fmt.Errorf("%w: %s", ErrSpaceDenied, space)
+	}
 
-	vmeta, exists, err := chain.GetValueMeta(svc.vm.db, []byte(space), []byte(key))
+	db, err := svc.vm.readState(args.Preferred, args.Pending)
+	if err != nil {
+		return err
+	}
+	return svc.resolveValue(db, svc.attester(args.Pending), svc.vm.lastAccepted, args.Path, args.IfNoneMatch, reply)
+}
+
+// attester returns the function resolved values are attested with. Pending
+// state is not the state of any block, so it is never attested.
+func (svc *PublicService) attester(pending bool) func(string, bool, []byte, *chain.StatelessBlock) (*Attestation, error) {
+	if pending {
+		return func(string, bool, []byte, *chain.StatelessBlock) (*Attestation, error) { return nil, nil }
+	}
+	return svc.vm.attest
+}
+
+// resolveValue resolves [path] from [db] into [reply], attesting the result
+// as of [la].
+func (svc *PublicService) resolveValue(
+	db database.KeyValueReader,
+	attest func(string, bool, []byte, *chain.StatelessBlock) (*Attestation, error),
+	la *chain.StatelessBlock,
+	path string,
+	ifNoneMatch string,
+	reply *ResolveReply,
+) error {
+	space, key, err := parser.ResolvePath(path)
+	if err != nil {
+		return err
+	}
+	vmeta, exists, err := chain.GetValueMeta(db, []byte(space), []byte(key))
 	if err != nil {
 		return err
 	}
 	if !exists {
 		// Avoid value lookup if doesn't exist
-		return nil
+		reply.Attestation, err = attest(path, false, nil, la)
+		return err
 	}
-	v, exists, err := chain.GetValue(svc.vm.db, []byte(space), []byte(key))
+	v, exists, err := chain.GetValue(db, []byte(space), []byte(key))
 	if err != nil {
 		return err
 	}
@@ -292,13 +587,141 @@ func (svc *PublicService) Resolve(_ *http.Request, args *ResolveArgs, reply *Res
 
 	// Set values properly
 	reply.Exists = true
-	reply.Value = v
 	reply.ValueMeta = vmeta
+	reply.ETag = valueETag(v)
+	if len(ifNoneMatch) > 0 && etagMatches(ifNoneMatch, reply.ETag) {
+		reply.NotModified = true
+	} else {
+		reply.Value = v
+	}
+	// The attestation always covers the current value, so a client that
+	// already has it can still verify a not modified reply
+	reply.Attestation, err = attest(path, true, v, la)
+	return err
+}
+
+type ResolveManyArgs struct {
+	// Paths are resolved in order (at most [chain.MaxPageLimit])
+	Paths []string `serialize:"true" json:"paths"`
+	// Preferred serves the reads from the preferred block's state instead of
+	// the last accepted state
+	Preferred bool `serialize:"true" json:"preferred,omitempty"`
+	// Pending overlays the transactions in the mempool on the state
+	// (best-effort)
+	Pending bool `serialize:"true" json:"pending,omitempty"`
+}
+
+type ResolveManyReply struct {
+	// BlockID and Height identify the block whose state every value was read
+	// from (for pending reads, the block the mempool was overlaid on)
+	BlockID ids.ID `serialize:"true" json:"blockId"`
+	Height  uint64 `serialize:"true" json:"height"`
+	// Values are in the order of [ResolveManyArgs.Paths]
+	Values []*ResolveReply `serialize:"true" json:"values"`
+}
+
+// ResolveMany resolves every path from a single state view, so no block is
+// accepted (or preferred) between reads. Values are attested as of the block
+// they were read from.
+func (svc *PublicService) ResolveMany(_ *http.Request, args *ResolveManyArgs, reply *ResolveManyReply) error {
+	if len(args.Paths) > chain.MaxPageLimit {
+		return fmt.Errorf("%w: %d paths (max=%d)", ErrTooManyPaths, len(args.Paths), chain.MaxPageLimit)
+	}
+	for _, path := range args.Paths {
+		space, _, err := parser.ResolvePath(path)
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, path)
+		}
+		if svc.vm.denylist.denied(space) {
+			return fmt.Errorf("%w: %s", ErrSpaceDenied, space)
+		}
+	}
+
+	db, blk, err := svc.vm.readView(args.Preferred, args.Pending)
+	if err != nil {
+		return err
+	}
+	attest := svc.attester(args.Pending)
+	reply.BlockID, reply.Height = blk.ID(), blk.Hght
+	reply.Values = make([]*ResolveReply, len(args.Paths))
+	for i, path := range args.Paths {
+		reply.Values[i] = new(ResolveReply)
+		if err := svc.resolveValue(db, attest, blk, path, "", reply.Values[i]); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+type FindByHashArgs struct {
+	// Hash is the Keccak-256 hash of the value (the same hash resolve
+	// responses attest to and the gateway uses as the ETag)
+	Hash   common.Hash `serialize:"true" json:"hash"`
+	Cursor string      `serialize:"true" json:"cursor"`
+	Limit  int         `serialize:"true" json:"limit"`
+}
+
+type FindByHashReply struct {
+	// Paths are the keys ("space/key") whose values have the hash
+	Paths []string `serialize:"true" json:"paths"`
+	// Next is the cursor of the next page (empty when there are no more
+	// results)
+	Next string `serialize:"true" json:"next"`
+}
+
+// FindByHash returns the keys whose values hash to [args.Hash], so duplicate
+// content can be detected (and content found) across spaces.
+func (svc *PublicService) FindByHash(_ *http.Request, args *FindByHashArgs, reply *FindByHashReply) error {
+	paths, next, err := chain.FindByHash(svc.vm.db, args.Hash, args.Cursor, args.Limit, svc.vm.lastAccepted.Hght)
+	if err != nil {
+		return err
+	}
+	reply.Paths = make([]string, 0, len(paths))
+	for _, path := range paths {
+		space, _, err := parser.ResolvePath(path)
+		if err != nil {
+			return fmt.Errorf("%w: hash index path %q", ErrCorruption, path)
+		}
+		if !svc.vm.denylist.denied(space) {
+			reply.Paths = append(reply.Paths, path)
+		}
+	}
+	reply.Next = next
+	return nil
+}
+
+type WarpMessageArgs struct {
+	TxID ids.ID `serialize:"true" json:"txId"`
+}
+
+type WarpMessageReply struct {
+	Message *chain.WarpMessage `serialize:"true" json:"message"`
+	// Signature is only set when the node is configured with a [ResponseKey]
+	Signature *WarpSignature `serialize:"true" json:"signature,omitempty"`
+}
+
+// WarpMessage returns the warp message emitted by the accepted broadcast set
+// [args.TxID], signed by this node
+func (svc *PublicService) WarpMessage(_ *http.Request, args *WarpMessageArgs, reply *WarpMessageReply) error {
+	m, exists, err := chain.GetWarpMessage(svc.vm.db, args.TxID)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrWarpMessageNotFound
+	}
+	reply.Message = m
+	if svc.vm.responseKey == nil {
+		return nil
+	}
+	reply.Signature, err = signWarpMessage(m, svc.vm.responseKey)
+	return err
+}
+
 type BalanceArgs struct {
-	Address common.Address `serialize:"true" json:"address"`
+	Address   common.Address `serialize:"true" json:"address"`
+	Preferred bool           `serialize:"true" json:"preferred,omitempty"`
+	Pending   bool           `serialize:"true" json:"pending,omitempty"`
 }
 
 type BalanceReply struct {
@@ -306,7 +729,11 @@ type BalanceReply struct {
 }
 
 func (svc *PublicService) Balance(_ *http.Request, args *BalanceArgs, reply *BalanceReply) error {
-	bal, err := chain.GetBalance(svc.vm.db, args.Address)
+	db, err := svc.vm.readState(args.Preferred, args.Pending)
+	if err != nil {
+		return err
+	}
+	bal, err := chain.GetBalance(db, args.Address)
 	if err != nil {
 		return err
 	}
@@ -314,6 +741,61 @@ func (svc *PublicService) Balance(_ *http.Request, args *BalanceArgs, reply *Bal
 	return err
 }
 
+// maxAtomicUTXOs is the most UTXOs returned by [AtomicUTXOs]
+const maxAtomicUTXOs = 1024
+
+type AtomicUTXOsArgs struct {
+	SourceChain ids.ID      `serialize:"true" json:"sourceChain"`
+	Address     ids.ShortID `serialize:"true" json:"address"`
+}
+
+type AtomicUTXO struct {
+	UTXOID   ids.ID `serialize:"true" json:"utxoID"`
+	Amount   uint64 `serialize:"true" json:"amount"`
+	Locktime uint64 `serialize:"true" json:"locktime"`
+}
+
+type AtomicUTXOsReply struct {
+	UTXOs []*AtomicUTXO `serialize:"true" json:"utxos"`
+}
+
+// AtomicUTXOs returns the UTXOs of the atomic asset that [args.SourceChain]
+// exported to this chain and [args.Address] owns (at most [maxAtomicUTXOs]).
+func (svc *PublicService) AtomicUTXOs(_ *http.Request, args *AtomicUTXOsArgs, reply *AtomicUTXOsReply) error {
+	g := svc.vm.genesis
+	if g.AtomicAssetID == ids.Empty {
+		return chain.ErrAtomicDisabled
+	}
+	a := svc.vm.Atomic()
+	if a == nil {
+		return chain.ErrAtomicUnavailable
+	}
+	values, _, _, err := a.SharedMemory().Indexed(
+		args.SourceChain, [][]byte{args.Address.Bytes()}, nil, nil, maxAtomicUTXOs,
+	)
+	if err != nil {
+		return err
+	}
+	reply.UTXOs = []*AtomicUTXO{}
+	for _, v := range values {
+		utxo, err := chain.ParseAtomicUTXO(v)
+		if err != nil {
+			return err
+		}
+		out, ok := utxo.Out.(*secp256k1fx.TransferOutput)
+		if !ok || utxo.AssetID() != g.AtomicAssetID {
+			// Not importable
+			continue
+		}
+		reply.UTXOs = append(reply.UTXOs, &AtomicUTXO{
+			UTXOID:   utxo.InputID(),
+			Amount:   out.Amt,
+			Locktime: out.Locktime,
+		})
+	}
+	return nil
+}
+
 type RecentActivityReply struct {
 	Activity []*chain.Activity `serialize:"true" json:"activity"`
 }
@@ -341,18 +823,593 @@ func (svc *PublicService) RecentActivity(_ *http.Request, _ *struct{}, reply *Re
 }
 
 type OwnedArgs struct {
-	Address common.Address `serialize:"true" json:"address"`
+	Address   common.Address `serialize:"true" json:"address"`
+	Cursor    string         `serialize:"true" json:"cursor"`
+	Limit     int            `serialize:"true" json:"limit"`
+	Preferred bool           `serialize:"true" json:"preferred,omitempty"`
+	Pending   bool           `serialize:"true" json:"pending,omitempty"`
 }
 
 type OwnedReply struct {
 	Spaces []string `serialize:"true" json:"spaces"`
+	// Next is the cursor of the next page (empty when there are no more
+	// results)
+	Next string `serialize:"true" json:"next"`
 }
 
 func (svc *PublicService) Owned(_ *http.Request, args *OwnedArgs, reply *OwnedReply) error {
-	spaces, err := chain.GetAllOwned(svc.vm.db, args.Address)
+	db, err := svc.vm.readState(args.Preferred, args.Pending)
+	if err != nil {
+		return err
+	}
+	reply.Spaces, reply.Next, err = chain.ListOwned(db, args.Address, args.Cursor, args.Limit, svc.vm.lastAccepted.Hght)
+	return err
+}
+
+type ListKeysArgs struct {
+	Space     string `serialize:"true" json:"space"`
+	Cursor    string `serialize:"true" json:"cursor"`
+	Limit     int    `serialize:"true" json:"limit"`
+	Preferred bool   `serialize:"true" json:"preferred,omitempty"`
+	Pending   bool   `serialize:"true" json:"pending,omitempty"`
+}
+
+type ListKeysReply struct {
+	Keys []*chain.KeyValueMeta `serialize:"true" json:"keys"`
+	// Next is the cursor of the next page (empty when there are no more
+	// results)
+	Next string `serialize:"true" json:"next"`
+}
+
+// ListKeys returns the keys of [args.Space] and their metadata in key order
+func (svc *PublicService) ListKeys(_ *http.Request, args *ListKeysArgs, reply *ListKeysReply) error {
+	if err := parser.CheckContents(args.Space); err != nil {
+		return err
+	}
+	if svc.vm.denylist.denied(args.Space) {
+		return fmt.Errorf("%w: %s", ErrSpaceDenied, args.Space)
+	}
+	db, err := svc.vm.readState(args.Preferred, args.Pending)
+	if err != nil {
+		return err
+	}
+	i, exists, err := chain.GetSpaceInfo(db, []byte(args.Space))
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return chain.ErrSpaceMissing
+	}
+	reply.Keys, reply.Next, err = chain.ListValueMetas(db, i.RawSpace, args.Cursor, args.Limit, svc.vm.lastAccepted.Hght)
+	return err
+}
+
+type HistoryArgs struct {
+	Space  string `serialize:"true" json:"space"`
+	Cursor string `serialize:"true" json:"cursor"`
+	Limit  int    `serialize:"true" json:"limit"`
+}
+
+type HistoryReply struct {
+	Activity []*chain.Activity `serialize:"true" json:"activity"`
+	// Next is the cursor of the next page (empty when there are no more
+	// results)
+	Next string `serialize:"true" json:"next"`
+}
+
+func (svc *PublicService) History(_ *http.Request, args *HistoryArgs, reply *HistoryReply) (err error) {
+	if err := parser.CheckContents(args.Space); err != nil {
+		return err
+	}
+	reply.Activity, reply.Next, err = chain.GetSpaceHistory(svc.vm.db, []byte(args.Space), args.Cursor, args.Limit, svc.vm.lastAccepted.Hght)
+	return err
+}
+
+type SenderHistoryArgs struct {
+	Address common.Address `serialize:"true" json:"address"`
+	Cursor  string         `serialize:"true" json:"cursor"`
+	Limit   int            `serialize:"true" json:"limit"`
+}
+
+func (svc *PublicService) SenderHistory(_ *http.Request, args *SenderHistoryArgs, reply *HistoryReply) (err error) {
+	reply.Activity, reply.Next, err = chain.GetSenderHistory(svc.vm.db, args.Address, args.Cursor, args.Limit, svc.vm.lastAccepted.Hght)
+	return err
+}
+
+type ChangesSinceArgs struct {
+	// Prefix is a space, optionally followed by a delimiter and the prefix of
+	// the keys to return (such as "foo/bar")
+	Prefix string `serialize:"true" json:"prefix"`
+	// BlockID is the accepted block changes are returned after
+	BlockID ids.ID `serialize:"true" json:"blockId"`
+	// Looked up instead of [BlockID] if set
+	Height *uint64 `serialize:"true" json:"height,omitempty"`
+	Limit  int     `serialize:"true" json:"limit"`
+}
+
+// KeyChange is the current value of a key that was set or deleted
+type KeyChange struct {
+	Key       string           `serialize:"true" json:"key"`
+	Deleted   bool             `serialize:"true" json:"deleted"`
+	Value     []byte           `serialize:"true" json:"value,omitempty"`
+	ValueMeta *chain.ValueMeta `serialize:"true" json:"valueMeta,omitempty"`
+}
+
+type ChangesSinceReply struct {
+	Changes []*KeyChange `serialize:"true" json:"changes"`
+	// BlockID and Height are the last block whose changes were included, to
+	// be passed to the next call
+	BlockID ids.ID `serialize:"true" json:"blockId"`
+	Height  uint64 `serialize:"true" json:"height"`
+	// More is true if there are changes after [Height]
+	More bool `serialize:"true" json:"more"`
+	// Reset is true if the space was claimed since, so keys may have been
+	// removed by its expiry without being reported. The space should be
+	// listed again.
+	Reset bool `serialize:"true" json:"reset"`
+}
+
+// ChangesSince returns the keys set or deleted under [args.Prefix] by the
+// blocks accepted after [args.BlockID] (or [args.Height]), so applications
+// can sync a space incrementally. Values are read at the last accepted block,
+// so they may already include changes after [reply.Height] (which are
+// returned again by the next call).
+func (svc *PublicService) ChangesSince(_ *http.Request, args *ChangesSinceArgs, reply *ChangesSinceReply) error {
+	parts := strings.SplitN(args.Prefix, parser.Delimiter, 2)
+	space, keyPrefix := parts[0], ""
+	if err := parser.CheckContents(space); err != nil {
+		return err
+	}
+	if len(parts) == 2 && len(parts[1]) > 0 {
+		keyPrefix = parts[1]
+		if err := parser.CheckContents(keyPrefix); err != nil {
+			return err
+		}
+	}
+	if svc.vm.denylist.denied(space) {
+		return fmt.Errorf("%w: %s", ErrSpaceDenied, space)
+	}
+	since, err := svc.lookupBlock(&BlockArgs{BlockID: args.BlockID, Height: args.Height})
+	if err != nil {
+		return err
+	}
+	la := svc.vm.lastAccepted
+	_, exists, err := chain.GetSpaceInfo(svc.vm.db, []byte(space))
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return chain.ErrSpaceMissing
+	}
+	keys, through, reset, err := chain.GetSpaceChanges(svc.vm.db, []byte(space), keyPrefix, since.Hght, la.Hght, args.Limit)
+	if err != nil {
+		return err
+	}
+	reply.Changes = make([]*KeyChange, 0, len(keys))
+	for _, key := range keys {
+		change := &KeyChange{Key: key}
+		vmeta, exists, err := chain.GetValueMeta(svc.vm.db, []byte(space), []byte(key))
+		if err != nil {
+			return err
+		}
+		if !exists {
+			change.Deleted = true
+			reply.Changes = append(reply.Changes, change)
+			continue
+		}
+		v, exists, err := chain.GetValue(svc.vm.db, []byte(space), []byte(key))
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrCorruption
+		}
+		change.Value, change.ValueMeta = v, vmeta
+		reply.Changes = append(reply.Changes, change)
+	}
+	if through == la.Hght {
+		reply.BlockID, reply.Height = la.ID(), la.Hght
+	} else {
+		blk, err := svc.blockAtHeight(through)
+		if err != nil {
+			return err
+		}
+		reply.BlockID, reply.Height, reply.More = blk.ID(), blk.Hght, true
+	}
+	reply.Reset = reset
+	return nil
+}
+
+type RecentBlocksArgs struct {
+	N      int    `serialize:"true" json:"n"`
+	Cursor string `serialize:"true" json:"cursor"`
+}
+
+type BlockSummary struct {
+	BlockID   ids.ID `serialize:"true" json:"blockId"`
+	Height    uint64 `serialize:"true" json:"height"`
+	Timestamp int64  `serialize:"true" json:"timestamp"`
+	Txs       int    `serialize:"true" json:"txs"`
+	Price     uint64 `serialize:"true" json:"price"`
+	Cost      uint64 `serialize:"true" json:"cost"`
+	Units     uint64 `serialize:"true" json:"units"`
+}
+
+type RecentBlocksReply struct {
+	Blocks []*BlockSummary `serialize:"true" json:"blocks"`
+	// Next is the cursor of the next (older) page (empty when the genesis
+	// block has been returned)
+	Next string `serialize:"true" json:"next"`
+}
+
+// RecentBlocks returns summaries of up to [N] accepted blocks, sorted from
+// newest to oldest and starting at the last accepted block (or the block
+// before [Cursor]).
+func (svc *PublicService) RecentBlocks(_ *http.Request, args *RecentBlocksArgs, reply *RecentBlocksReply) error {
+	n := chain.PageLimit(args.N)
+	c, err := chain.ParseCursor(args.Cursor, svc.vm.lastAccepted.Hght)
+	if err != nil {
+		return err
+	}
+	start := c.Height
+	if len(c.Key) > 0 {
+		if len(c.Key) != 8 || binary.BigEndian.Uint64(c.Key) == 0 {
+			return fmt.Errorf("%w: %q", chain.ErrInvalidCursor, args.Cursor)
+		}
+		start = binary.BigEndian.Uint64(c.Key) - 1
+	}
+	blk, err := svc.blockAtHeight(start)
+	if err != nil {
+		return err
+	}
+	g := svc.vm.genesis
+	reply.Blocks = []*BlockSummary{}
+	for {
+		units := uint64(0)
+		for _, tx := range blk.Txs {
+			units += tx.LoadUnits(g)
+		}
+		reply.Blocks = append(reply.Blocks, &BlockSummary{
+			BlockID:   blk.ID(),
+			Height:    blk.Hght,
+			Timestamp: blk.Tmstmp,
+			Txs:       len(blk.Txs),
+			Price:     blk.Price,
+			Cost:      blk.Cost,
+			Units:     units,
+		})
+		if blk.Hght == 0 {
+			return nil
+		}
+		if len(reply.Blocks) == n {
+			break
+		}
+		parent, err := svc.vm.GetStatelessBlock(blk.Prnt)
+		if err != nil {
+			return err
+		}
+		blk = parent
+	}
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, blk.Hght)
+	reply.Next = (&chain.Cursor{Height: c.Height, Key: key}).String()
+	return nil
+}
+
+type BlockArgs struct {
+	BlockID ids.ID `serialize:"true" json:"blockId"`
+	// Looked up instead of [BlockID] if set
+	Height *uint64 `serialize:"true" json:"height,omitempty"`
+}
+
+type BlockDetail struct {
+	BlockID   ids.ID      `serialize:"true" json:"blockId"`
+	Parent    ids.ID      `serialize:"true" json:"parent"`
+	Height    uint64      `serialize:"true" json:"height"`
+	Timestamp int64       `serialize:"true" json:"timestamp"`
+	Price     uint64      `serialize:"true" json:"price"`
+	Cost      uint64      `serialize:"true" json:"cost"`
+	Txs       []*TxDetail `serialize:"true" json:"txs"`
+}
+
+type TxDetail struct {
+	TxID       ids.ID         `serialize:"true" json:"txId"`
+	Type       string         `serialize:"true" json:"type"`
+	Sender     common.Address `serialize:"true" json:"sender"`
+	Signature  hexutil.Bytes  `serialize:"true" json:"signature"`
+	DigestHash hexutil.Bytes  `serialize:"true" json:"digestHash"`
+	Size       uint64         `serialize:"true" json:"size"`
+	Price      uint64         `serialize:"true" json:"price"`
+	FeeUnits   uint64         `serialize:"true" json:"feeUnits"`
+	LoadUnits  uint64         `serialize:"true" json:"loadUnits"`
+	Cost       uint64         `serialize:"true" json:"cost"`
+	// Every field of the unsigned transaction, as signed by the sender
+	// (omitted for spaces denied by this node)
+	TypedData *tdata.TypedData `serialize:"true" json:"typedData,omitempty"`
+}
+
+type BlockReply struct {
+	Block *BlockDetail `serialize:"true" json:"block"`
+}
+
+// Block returns the full contents of an accepted block by ID or height.
+func (svc *PublicService) Block(_ *http.Request, args *BlockArgs, reply *BlockReply) error {
+	blk, err := svc.lookupBlock(args)
+	if err != nil {
+		return err
+	}
+	reply.Block = svc.blockDetail(blk)
+	return nil
+}
+
+type RawBlockReply struct {
+	Bytes hexutil.Bytes `serialize:"true" json:"bytes"`
+}
+
+// RawBlock returns the encoded bytes of an accepted block by ID or height. The
+// block ID is the keccak256 hash of these bytes, so they can be verified
+// without trusting the node.
+func (svc *PublicService) RawBlock(_ *http.Request, args *BlockArgs, reply *RawBlockReply) error {
+	blk, err := svc.lookupBlock(args)
+	if err != nil {
+		return err
+	}
+	reply.Bytes = blk.Bytes()
+	return nil
+}
+
+type HeaderReply struct {
+	Header *chain.BlockHeader `serialize:"true" json:"header"`
+}
+
+// Header returns the header of an accepted block by ID or height.
+func (svc *PublicService) Header(_ *http.Request, args *BlockArgs, reply *HeaderReply) error {
+	blk, err := svc.lookupBlock(args)
+	if err != nil {
+		return err
+	}
+	reply.Header = blk.Header()
+	return nil
+}
+
+type HeadersArgs struct {
+	Start uint64 `serialize:"true" json:"start"`
+	N     int    `serialize:"true" json:"n"`
+}
+
+type HeadersReply struct {
+	Headers []*chain.BlockHeader `serialize:"true" json:"headers"`
+}
+
+// Headers returns the headers of up to [N] accepted blocks, sorted from
+// oldest to newest and starting at height [Start]. Fewer headers are returned
+// if the last accepted block is reached.
+func (svc *PublicService) Headers(_ *http.Request, args *HeadersArgs, reply *HeadersReply) error {
+	last := svc.vm.lastAccepted.Hght
+	if args.Start > last {
+		return fmt.Errorf("%w: height %d", ErrBlockNotFound, args.Start)
+	}
+	end := args.Start + uint64(chain.PageLimit(args.N)) - 1
+	if end < args.Start || end > last {
+		end = last
+	}
+	blk, err := svc.blockAtHeight(end)
+	if err != nil {
+		return err
+	}
+	reply.Headers = make([]*chain.BlockHeader, end-args.Start+1)
+	for i := len(reply.Headers) - 1; i >= 0; i-- {
+		reply.Headers[i] = blk.Header()
+		if i == 0 {
+			break
+		}
+		blk, err = svc.vm.GetStatelessBlock(blk.Prnt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type TxArgs struct {
+	TxID ids.ID `serialize:"true" json:"txId"`
+}
+
+type TxReply struct {
+	Accepted bool `serialize:"true" json:"accepted"`
+	// Empty if the transaction was accepted before blocks were indexed
+	BlockID ids.ID    `serialize:"true" json:"blockId"`
+	Tx      *TxDetail `serialize:"true" json:"tx,omitempty"`
+
+	// Pending is true if the transaction is in this node's mempool
+	Pending bool `serialize:"true" json:"pending"`
+	// DropReason is why the transaction recently left this node's mempool
+	// (if it has not been accepted)
+	DropReason chain.DropReason `serialize:"true" json:"dropReason,omitempty"`
+	// DroppedAt is when the transaction left the mempool, if it is known
+	// from the persisted drop records (see [Config.DroppedTxRecords])
+	DroppedAt int64 `serialize:"true" json:"droppedAt,omitempty"`
+}
+
+// Tx returns the full contents of an accepted transaction and the block that
+// accepted it. If the transaction has not been accepted, it reports whether
+// the transaction is pending or why it left the mempool.
+func (svc *PublicService) Tx(_ *http.Request, args *TxArgs, reply *TxReply) error {
+	blkID, accepted, err := chain.GetTransactionBlock(svc.vm.db, args.TxID)
+	if err != nil {
+		return err
+	}
+	reply.Accepted = accepted
+	reply.BlockID = blkID
+	if !accepted {
+		reply.Pending = svc.vm.mempool.Has(args.TxID)
+		if reply.Pending {
+			return nil
+		}
+		d, err := chain.GetDroppedTx(svc.vm.db, args.TxID)
+		if err != nil {
+			return err
+		}
+		if d != nil {
+			reply.DropReason, reply.DroppedAt = d.Reason, d.Timestamp
+		}
+		// The mempool knows about more recent drops (including txs that were
+		// included in blocks that were later rejected)
+		if reason, ok := svc.vm.mempool.DropReason(args.TxID); ok && reason != reply.DropReason {
+			reply.DropReason, reply.DroppedAt = reason, 0
+		}
+		return nil
+	}
+	if blkID == ids.Empty {
+		return nil
+	}
+	blk, err := svc.acceptedBlock(blkID)
 	if err != nil {
 		return err
 	}
-	reply.Spaces = spaces
+	for _, tx := range blk.Txs {
+		if tx.ID() == args.TxID {
+			reply.Tx = svc.txDetail(tx)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: tx %s missing from block %s", ErrCorruption, args.TxID, blkID)
+}
+
+// lookupBlock returns the accepted block at [args.Height] (if set) or with
+// [args.BlockID]
+func (svc *PublicService) lookupBlock(args *BlockArgs) (*chain.StatelessBlock, error) {
+	if args.Height != nil {
+		return svc.blockAtHeight(*args.Height)
+	}
+	return svc.acceptedBlock(args.BlockID)
+}
+
+func (svc *PublicService) acceptedBlock(blkID ids.ID) (*chain.StatelessBlock, error) {
+	blk, err := svc.vm.GetStatelessBlock(blkID)
+	if err != nil || blk.Status() != choices.Accepted {
+		return nil, fmt.Errorf("%w: %s", ErrBlockNotFound, blkID)
+	}
+	return blk, nil
+}
+
+// blockAtHeight returns the accepted block at [height], walking back from
+// the last accepted block if it was accepted before heights were indexed.
+func (svc *PublicService) blockAtHeight(height uint64) (*chain.StatelessBlock, error) {
+	blk := svc.vm.lastAccepted
+	if height > blk.Hght {
+		return nil, fmt.Errorf("%w: height %d", ErrBlockNotFound, height)
+	}
+	blkID, found, err := chain.GetBlockIDAtHeight(svc.vm.db, height)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		return svc.acceptedBlock(blkID)
+	}
+	for blk.Hght > height {
+		blk, err = svc.vm.GetStatelessBlock(blk.Prnt)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return blk, nil
+}
+
+func (svc *PublicService) blockDetail(blk *chain.StatelessBlock) *BlockDetail {
+	detail := &BlockDetail{
+		BlockID:   blk.ID(),
+		Parent:    blk.Prnt,
+		Height:    blk.Hght,
+		Timestamp: blk.Tmstmp,
+		Price:     blk.Price,
+		Cost:      blk.Cost,
+		Txs:       make([]*TxDetail, 0, len(blk.Txs)),
+	}
+	for _, tx := range blk.Txs {
+		detail.Txs = append(detail.Txs, svc.txDetail(tx))
+	}
+	return detail
+}
+
+func (svc *PublicService) txDetail(tx *chain.Transaction) *TxDetail {
+	g := svc.vm.genesis
+	activity := tx.Activity()
+	detail := &TxDetail{
+		TxID:       tx.ID(),
+		Type:       activity.Typ,
+		Sender:     tx.Sender(),
+		Signature:  tx.Signature,
+		DigestHash: tx.DigestHash(),
+		Size:       tx.Size(),
+		Price:      tx.GetPrice(),
+		FeeUnits:   tx.FeeUnits(g),
+		LoadUnits:  tx.LoadUnits(g),
+		Cost:       tx.FeeUnits(g) * tx.GetPrice(),
+	}
+	// Values written to denied spaces are not served
+	if !svc.vm.denylist.denied(activity.Space) {
+		detail.TypedData = tx.TypedData()
+	}
+	return detail
+}
+
+type StatsReply struct {
+	Height uint64 `serialize:"true" json:"height"`
+	// Number of recently accepted blocks the aggregates are computed over
+	Window           int     `serialize:"true" json:"window"`
+	AvgBlockInterval float64 `serialize:"true" json:"avgBlockInterval"` // seconds
+	TxsPerSecond     float64 `serialize:"true" json:"txsPerSecond"`
+	Price            uint64  `serialize:"true" json:"price"`
+	Cost             uint64  `serialize:"true" json:"cost"`
+	Mempool          int     `serialize:"true" json:"mempool"`
+
+	State *chain.StateStats `serialize:"true" json:"state"`
+}
+
+func (svc *PublicService) Stats(_ *http.Request, _ *struct{}, reply *StatsReply) (err error) {
+	la := svc.vm.lastAccepted
+	reply.Height = la.Hght
+	reply.Window = svc.vm.blockStats.count
+	reply.AvgBlockInterval, reply.TxsPerSecond = svc.vm.blockStats.aggregates()
+	reply.Price = la.Price
+	reply.Cost = la.Cost
+	reply.Mempool = svc.vm.mempool.Len()
+	reply.State, err = chain.GetStateStats(svc.vm.db)
+	return err
+}
+
+type FeeHistoryArgs struct {
+	// N is the number of recently accepted blocks to return (all blocks in
+	// the stats window if 0)
+	N int `serialize:"true" json:"n"`
+}
+
+type FeeHistoryReply struct {
+	Blocks []*FeeHistoryEntry `serialize:"true" json:"blocks"`
+	// SuggestedPrice and SuggestedCost are the fee estimate for the next
+	// block (see [SuggestedFee])
+	SuggestedPrice uint64 `serialize:"true" json:"suggestedPrice"`
+	SuggestedCost  uint64 `serialize:"true" json:"suggestedCost"`
+}
+
+// FeeHistory returns the price, cost, and lowest included transaction price
+// of up to [N] recently accepted blocks (sorted from oldest to newest), so
+// clients can estimate fees from recent demand instead of a single
+// suggestion.
+func (svc *PublicService) FeeHistory(_ *http.Request, args *FeeHistoryArgs, reply *FeeHistoryReply) (err error) {
+	reply.Blocks = svc.vm.blockStats.history(args.N)
+	reply.SuggestedPrice, reply.SuggestedCost, err = svc.vm.SuggestedFee()
+	return err
+}
+
+type DiscoverReply struct {
+	Version string               `serialize:"true" json:"version"`
+	Methods []*MethodDescription `serialize:"true" json:"methods"`
+}
+
+func (svc *PublicService) Discover(_ *http.Request, _ *struct{}, reply *DiscoverReply) error {
+	reply.Version = version.Version
+	reply.Methods = describeService(svc, Name)
 	return nil
 }
@@ -0,0 +1,120 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// number of tracked clients after which idle buckets are evicted
+const maxRateLimitClients = 4096
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// rateLimiter is a set of token buckets, one per client, that each refill at
+// [rate] tokens per second up to [burst].
+type rateLimiter struct {
+	rate  float64
+	burst float64
+	now   func() time.Time
+
+	l       sync.Mutex
+	buckets map[string]*tokenBucket
+}
+
+func newRateLimiter(rate float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		now:     time.Now,
+		buckets: map[string]*tokenBucket{},
+	}
+}
+
+// allow consumes a token from [client]'s bucket and returns false if none are
+// available
+func (r *rateLimiter) allow(client string) bool {
+	r.l.Lock()
+	defer r.l.Unlock()
+
+	now := r.now()
+	b, ok := r.buckets[client]
+	if !ok {
+		if len(r.buckets) >= maxRateLimitClients {
+			r.evict(now)
+		}
+		b = &tokenBucket{tokens: r.burst, last: now}
+		r.buckets[client] = b
+	}
+	b.tokens += now.Sub(b.last).Seconds() * r.rate
+	if b.tokens > r.burst {
+		b.tokens = r.burst
+	}
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// evict removes buckets that have refilled completely, as they are equivalent
+// to a new bucket. Assumes [r.l] is held.
+func (r *rateLimiter) evict(now time.Time) {
+	for client, b := range r.buckets {
+		if b.tokens+now.Sub(b.last).Seconds()*r.rate >= r.burst {
+			delete(r.buckets, client)
+		}
+	}
+}
+
+// rateLimitHandler limits the rate of requests each client can make to [h].
+// Methods for which [isWrite] returns true are limited by [writes] and all
+// others by [reads]. A nil limiter disables limiting for that class of
+// methods.
+func rateLimitHandler(h http.Handler, reads, writes *rateLimiter, isWrite func(method string) bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodOptions {
+			h.ServeHTTP(w, r)
+			return
+		}
+		method, err := rpcMethod(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		limiter := reads
+		if isWrite(method) {
+			limiter = writes
+		}
+		if limiter != nil && !limiter.allow(rateLimitClient(r)) {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "rate limit exceeded", http.StatusTooManyRequests)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+// rateLimitClient identifies the client making [r] by its bearer token, if
+// provided, or else by its IP
+func rateLimitClient(r *http.Request) string {
+	if token, ok := bearerToken(r); ok {
+		return "token:" + token
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "ip:" + host
+}
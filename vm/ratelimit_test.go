@@ -0,0 +1,78 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	r := newRateLimiter(1, 2)
+	r.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		if !r.allow("a") {
+			t.Fatalf("request %d should be allowed within burst", i)
+		}
+	}
+	if r.allow("a") {
+		t.Fatal("request should be limited after burst")
+	}
+	if !r.allow("b") {
+		t.Fatal("clients should be limited independently")
+	}
+	now = now.Add(time.Second)
+	if !r.allow("a") {
+		t.Fatal("request should be allowed after refill")
+	}
+	if r.allow("a") {
+		t.Fatal("bucket should only refill at rate")
+	}
+
+	// Full buckets are evicted once too many clients are tracked
+	now = now.Add(time.Minute)
+	r.evict(now)
+	if len(r.buckets) != 0 {
+		t.Fatalf("expected all buckets to be evicted, found %d", len(r.buckets))
+	}
+}
+
+func TestRateLimitHandler(t *testing.T) {
+	h := rateLimitHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), newRateLimiter(1, 2), newRateLimiter(1, 1), func(method string) bool {
+		_, ok := writeMethods[method]
+		return ok
+	})
+
+	tt := []struct {
+		method string
+		token  string
+		status int
+	}{
+		{method: "spacesvm.ping", status: http.StatusOK},
+		{method: "spacesvm.ping", status: http.StatusOK},
+		{method: "spacesvm.ping", status: http.StatusTooManyRequests},
+		{method: "spacesvm.ping", token: "secret", status: http.StatusOK},
+		{method: "spacesvm.issueRawTx", status: http.StatusOK},
+		{method: "spacesvm.issueRawTx", status: http.StatusTooManyRequests},
+	}
+	for i, tv := range tt {
+		body := `{"jsonrpc":"2.0","method":"` + tv.method + `","params":{},"id":1}`
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		if len(tv.token) > 0 {
+			req.Header.Set("Authorization", "Bearer "+tv.token)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tv.status {
+			t.Fatalf("request %d %s (token=%q): expected status %d, got %d", i, tv.method, tv.token, tv.status, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"time"
+
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	log "github.com/inconshreveable/log15"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+// ReindexStats summarize the work done by reindexing.
+type ReindexStats struct {
+	// Tx index, history, and indexed block entries deleted before rebuilding
+	Cleared int `serialize:"true" json:"cleared"`
+	// Accepted blocks (and their transactions) whose indexes were rebuilt
+	Blocks int `serialize:"true" json:"blocks"`
+	Txs    int `serialize:"true" json:"txs"`
+	// Expiry and owned index entries repaired from the stored space infos
+	Repairs int `serialize:"true" json:"repairs"`
+}
+
+// Reindex rebuilds every secondary index from the stored state and the
+// accepted blocks: the expiry and owned indexes are repaired, and the block
+// height index, tx index, and history are regenerated by replaying every
+// accepted block after genesis. Progress is committed (and logged) in
+// batches, so an interrupted reindex only leaves the indexes of later blocks
+// missing until it is run again. Indexes older than [Config.IndexRetention]
+// are trimmed again by the background pruner.
+//
+// The context lock is held for the duration of the reindex so that no blocks
+// are accepted while it runs.
+func (vm *VM) Reindex() (*ReindexStats, error) {
+	vm.ctx.Lock.Lock()
+	defer vm.ctx.Lock.Unlock()
+
+	start := time.Now()
+	blkIDs, err := vm.acceptedIDs()
+	if err != nil {
+		return nil, err
+	}
+	stats := &ReindexStats{}
+	stats.Cleared, err = chain.ClearIndexes(vm.db)
+	if err != nil {
+		return stats, err
+	}
+	vdb := versiondb.New(vm.db)
+	defer vdb.Abort()
+	stats.Repairs, err = chain.RepairIndexes(vdb)
+	if err != nil {
+		return stats, err
+	}
+	if err := vdb.Commit(); err != nil {
+		return stats, err
+	}
+	log.Info("reindexing blocks", "blocks", len(blkIDs), "cleared", stats.Cleared, "repairs", stats.Repairs)
+
+	for i, blkID := range blkIDs {
+		blk, err := vm.GetStatelessBlock(blkID)
+		if err != nil {
+			return stats, err
+		}
+		if err := chain.ReindexBlock(vdb, blk); err != nil {
+			return stats, err
+		}
+		stats.Blocks++
+		stats.Txs += len(blk.Txs)
+		if stats.Blocks%historyBatchSize != 0 && stats.Blocks != len(blkIDs) {
+			continue
+		}
+		if err := vdb.Commit(); err != nil {
+			return stats, err
+		}
+		log.Info("reindexed blocks",
+			"height", blk.Hght,
+			"progress", float64(i+1)/float64(len(blkIDs)),
+			"txs", stats.Txs,
+		)
+	}
+	if err := vm.lastAccepted.SetChildrenDB(vm.db); err != nil {
+		log.Error("unable to update child databases of last accepted block", "error", err)
+	}
+	log.Info("completed reindex",
+		"blocks", stats.Blocks,
+		"txs", stats.Txs,
+		"cleared", stats.Cleared,
+		"repairs", stats.Repairs,
+		"t", time.Since(start),
+	)
+	return stats, nil
+}
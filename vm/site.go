@@ -0,0 +1,169 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+const (
+	// SiteEndpoint serves the files synced into a space at
+	// [GatewayEndpoint]/<space>/<path>
+	SiteEndpoint = GatewayEndpoint + "/{path:.*}"
+
+	// siteManifestKey matches tree.ManifestKey
+	siteManifestKey = "manifest"
+	siteIndex       = "index.html"
+)
+
+// siteManifest mirrors the parts of tree.Manifest needed to serve a synced
+// space (tree depends on the client, so it can't be imported here)
+type siteManifest struct {
+	Files map[string]*siteFile `json:"files"`
+}
+
+type siteFile struct {
+	Root        string `json:"root"`
+	Hash        string `json:"hash"`
+	ContentType string `json:"contentType"`
+}
+
+// siteRoot mirrors tree.Root
+type siteRoot struct {
+	Contents []byte   `json:"contents"`
+	Children []string `json:"children"`
+}
+
+// siteHandler serves GET requests for <space>/<path> from the manifest
+// written by `spaces-cli sync`. Directory paths serve their index.html, the
+// content type recorded at sync time is used, and the keccak256 of each file
+// is returned as its ETag.
+func siteHandler(denied func(space string) bool, lookup gatewayLookup) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		i := strings.Index(r.URL.Path, GatewayEndpoint+"/")
+		if i < 0 {
+			http.NotFound(w, r)
+			return
+		}
+		parts := strings.SplitN(r.URL.Path[i+len(GatewayEndpoint)+1:], "/", 2)
+		space := parts[0]
+		if err := parser.CheckContents(space); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if denied(space) {
+			http.Error(w, ErrSpaceDenied.Error(), http.StatusForbidden)
+			return
+		}
+		// Relative links only resolve within the space if the site root ends
+		// with a slash
+		if len(parts) == 1 {
+			http.Redirect(w, r, r.URL.Path+"/", http.StatusMovedPermanently)
+			return
+		}
+
+		m, exists, err := readSiteManifest(lookup, space)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if !exists {
+			http.NotFound(w, r)
+			return
+		}
+		rel := strings.TrimPrefix(path.Clean("/"+parts[1]), "/")
+		if len(rel) == 0 || strings.HasSuffix(parts[1], "/") {
+			rel = path.Join(rel, siteIndex)
+		}
+		f, ok := m.Files[rel]
+		if !ok {
+			if _, ok := m.Files[path.Join(rel, siteIndex)]; ok && !strings.HasSuffix(parts[1], "/") {
+				http.Redirect(w, r, r.URL.Path+"/", http.StatusMovedPermanently)
+				return
+			}
+			http.NotFound(w, r)
+			return
+		}
+
+		etag := `"` + f.Hash + `"`
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", gatewayCacheControl)
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		b, err := readSiteFile(lookup, space, f.Root)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		// Manifests synced before content types were recorded fall back to
+		// the file extension (or sniffing) in [http.ServeContent]
+		if len(f.ContentType) > 0 {
+			w.Header().Set("Content-Type", f.ContentType)
+		}
+		http.ServeContent(w, r, rel, time.Time{}, bytes.NewReader(b))
+	})
+}
+
+// readSiteManifest returns the manifest of [space], if it has been synced
+func readSiteManifest(lookup gatewayLookup, space string) (*siteManifest, bool, error) {
+	_, root, exists, err := lookup(space, siteManifestKey)
+	if err != nil || !exists {
+		return nil, false, err
+	}
+	b, err := readSiteFile(lookup, space, string(root))
+	if err != nil {
+		return nil, false, err
+	}
+	m := &siteManifest{}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, false, fmt.Errorf("%w: invalid manifest", err)
+	}
+	return m, true, nil
+}
+
+// readSiteFile returns the contents of the file uploaded to [space] at [root]
+// (see tree.Upload)
+func readSiteFile(lookup gatewayLookup, space string, root string) ([]byte, error) {
+	_, rb, exists, err := lookup(space, root)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w: missing %s", ErrSiteFileMissing, root)
+	}
+	var r siteRoot
+	if err := json.Unmarshal(rb, &r); err != nil {
+		return nil, fmt.Errorf("%w: invalid root %s", err, root)
+	}
+	if len(r.Contents) > 0 {
+		return r.Contents, nil
+	}
+	var buf bytes.Buffer
+	for _, k := range r.Children {
+		_, chunk, exists, err := lookup(space, k)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("%w: missing %s", ErrSiteFileMissing, k)
+		}
+		buf.Write(chunk)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,106 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+func TestSiteHandler(t *testing.T) {
+	values := map[string][]byte{}
+	put := func(key string, v interface{}) {
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		values["site/"+key] = b
+	}
+	put("indexroot", &siteRoot{Contents: []byte("<h1>hi</h1>")})
+	put("cssroot", &siteRoot{Children: []string{"chunk1", "chunk2"}})
+	values["site/chunk1"] = []byte("body {")
+	values["site/chunk2"] = []byte("}")
+	put("docsroot", &siteRoot{Contents: []byte("docs")})
+	put("oldroot", &siteRoot{Contents: []byte("old")})
+	manifest, err := json.Marshal(&siteManifest{Files: map[string]*siteFile{
+		"index.html":      {Root: "indexroot", Hash: "aa", ContentType: "text/html; charset=utf-8"},
+		"css/site.css":    {Root: "cssroot", Hash: "bb", ContentType: "text/css; charset=utf-8"},
+		"docs/index.html": {Root: "docsroot", Hash: "cc", ContentType: "text/html; charset=utf-8"},
+		"old.txt":         {Root: "oldroot", Hash: "dd"},
+		"broken.html":     {Root: "missingroot", Hash: "ee"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	put("manifestroot", &siteRoot{Contents: manifest})
+	values["site/"+siteManifestKey] = []byte("manifestroot")
+
+	h := siteHandler(
+		func(space string) bool { return space == "denied" },
+		func(space string, key string) (*chain.ValueMeta, []byte, bool, error) {
+			v, ok := values[space+"/"+key]
+			if !ok {
+				return nil, nil, false, nil
+			}
+			return &chain.ValueMeta{}, v, true, nil
+		},
+	)
+
+	const base = "/ext/bc/chain" + GatewayEndpoint + "/"
+	tt := []struct {
+		method      string
+		path        string
+		ifNoneMatch string
+		status      int
+		location    string
+		contentType string
+		etag        string
+		body        string
+	}{
+		{method: http.MethodGet, path: "site/", status: http.StatusOK, contentType: "text/html; charset=utf-8", etag: `"aa"`, body: "<h1>hi</h1>"},
+		{method: http.MethodGet, path: "site", status: http.StatusMovedPermanently, location: base + "site/"},
+		{method: http.MethodGet, path: "site/index.html", status: http.StatusOK, etag: `"aa"`, body: "<h1>hi</h1>"},
+		{method: http.MethodGet, path: "site/css/site.css", status: http.StatusOK, contentType: "text/css; charset=utf-8", etag: `"bb"`, body: "body {}"},
+		{method: http.MethodGet, path: "site/css/../css/site.css", status: http.StatusOK, body: "body {}"},
+		{method: http.MethodGet, path: "site/docs/", status: http.StatusOK, etag: `"cc"`, body: "docs"},
+		{method: http.MethodGet, path: "site/docs", status: http.StatusMovedPermanently, location: base + "site/docs/"},
+		{method: http.MethodGet, path: "site/old.txt", status: http.StatusOK, contentType: "text/plain; charset=utf-8", body: "old"},
+		{method: http.MethodGet, path: "site/", ifNoneMatch: `"zz", "aa"`, status: http.StatusNotModified, etag: `"aa"`},
+		{method: http.MethodGet, path: "site/", ifNoneMatch: `"zz"`, status: http.StatusOK, body: "<h1>hi</h1>"},
+		{method: http.MethodHead, path: "site/", status: http.StatusOK, etag: `"aa"`},
+		{method: http.MethodGet, path: "site/missing.html", status: http.StatusNotFound},
+		{method: http.MethodGet, path: "site/broken.html", status: http.StatusInternalServerError},
+		{method: http.MethodGet, path: "unsynced/", status: http.StatusNotFound},
+		{method: http.MethodGet, path: "denied/", status: http.StatusForbidden},
+		{method: http.MethodGet, path: "INVALID/", status: http.StatusBadRequest},
+		{method: http.MethodPost, path: "site/", status: http.StatusMethodNotAllowed},
+	}
+	for i, tv := range tt {
+		req := httptest.NewRequest(tv.method, base+tv.path, nil)
+		if len(tv.ifNoneMatch) > 0 {
+			req.Header.Set("If-None-Match", tv.ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tv.status {
+			t.Fatalf("%d: expected status %d, got %d", i, tv.status, rec.Code)
+		}
+		if l := rec.Header().Get("Location"); l != tv.location {
+			t.Fatalf("%d: expected location %q, got %q", i, tv.location, l)
+		}
+		if c := rec.Header().Get("Content-Type"); len(tv.contentType) > 0 && c != tv.contentType {
+			t.Fatalf("%d: expected content type %q, got %q", i, tv.contentType, c)
+		}
+		if e := rec.Header().Get("ETag"); len(tv.etag) > 0 && e != tv.etag {
+			t.Fatalf("%d: expected etag %q, got %q", i, tv.etag, e)
+		}
+		if len(tv.body) > 0 && rec.Body.String() != tv.body {
+			t.Fatalf("%d: expected body %q, got %q", i, tv.body, rec.Body.String())
+		}
+	}
+}
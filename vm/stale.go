@@ -0,0 +1,50 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	log "github.com/inconshreveable/log15"
+)
+
+// releaseStaleBlocks finds processing blocks older than
+// [Config.StaleBlockAge] that are not the preferred block or one of its
+// ancestors. Stale blocks that conflict with the last accepted block can never
+// be accepted, so their state is released and they are no longer served from
+// [verifiedBlocks] (the engine may still reject them). Other stale blocks
+// could be accepted if the preference changes, so they are only logged. It
+// returns the number of blocks released. Assumes ctx.Lock is held.
+func (vm *VM) releaseStaleBlocks() int {
+	age := vm.config.StaleBlockAge
+	if age <= 0 {
+		return 0
+	}
+	cutoff := vm.clock.Time().Add(-age).Unix()
+	released := 0
+	for blkID, blk := range vm.verifiedBlocks {
+		if blk.Tmstmp > cutoff || vm.isDescendant(vm.preferred, blk) {
+			continue
+		}
+		// Children of released blocks are released as well because their
+		// ancestry no longer reaches the last accepted block
+		if vm.isDescendant(blkID, vm.lastAccepted) {
+			log.Warn("processing block is undecided",
+				"blkID", blkID,
+				"height", blk.Hght,
+				"timestamp", blk.Tmstmp,
+				"preferred", vm.preferred,
+			)
+			continue
+		}
+		blk.Release()
+		delete(vm.verifiedBlocks, blkID)
+		released++
+		log.Info("released stale processing block",
+			"blkID", blkID,
+			"parent", blk.Prnt,
+			"height", blk.Hght,
+			"lastAccepted", vm.lastAccepted.ID(),
+		)
+	}
+	return released
+}
@@ -0,0 +1,73 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/cache"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+func TestReleaseStaleBlocks(t *testing.T) {
+	newBlock := func(prnt ids.ID, hght uint64, tmstmp int64) *chain.StatelessBlock {
+		blk, err := chain.ParseStatefulBlock(&chain.StatefulBlock{
+			Prnt:   prnt,
+			Hght:   hght,
+			Tmstmp: tmstmp,
+		}, nil, choices.Processing, &VM{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return blk
+	}
+	root := newBlock(ids.GenerateTestID(), 1, 1)
+	accepted := newBlock(root.ID(), 2, 2)
+	conflict := newBlock(root.ID(), 2, 3)
+	conflictChild := newBlock(conflict.ID(), 3, 4)
+	sibling := newBlock(accepted.ID(), 3, 5)
+	preferred := newBlock(accepted.ID(), 3, 6)
+	recent := newBlock(root.ID(), 2, 100)
+
+	vm := &VM{
+		db:             memdb.New(),
+		blocks:         &cache.LRU{Size: 10},
+		lastAccepted:   accepted,
+		preferred:      preferred.ID(),
+		verifiedBlocks: map[ids.ID]*chain.StatelessBlock{},
+	}
+	vm.config.StaleBlockAge = time.Minute
+	vm.clock.Set(time.Unix(90, 0))
+	vm.blocks.Put(root.ID(), root)
+	vm.blocks.Put(accepted.ID(), accepted)
+	for _, blk := range []*chain.StatelessBlock{conflict, conflictChild, sibling, preferred, recent} {
+		vm.verifiedBlocks[blk.ID()] = blk
+	}
+
+	if released := vm.releaseStaleBlocks(); released != 2 {
+		t.Fatalf("expected 2 released blocks, got %d", released)
+	}
+	for _, blk := range []*chain.StatelessBlock{conflict, conflictChild} {
+		if _, ok := vm.verifiedBlocks[blk.ID()]; ok {
+			t.Fatalf("expected conflicting block at height %d to be released", blk.Hght)
+		}
+	}
+	// Blocks that may still be accepted (or are too recent) are kept
+	for _, blk := range []*chain.StatelessBlock{sibling, preferred, recent} {
+		if _, ok := vm.verifiedBlocks[blk.ID()]; !ok {
+			t.Fatalf("expected block at height %d (timestamp %d) to be kept", blk.Hght, blk.Tmstmp)
+		}
+	}
+
+	vm.config.StaleBlockAge = 0
+	vm.verifiedBlocks[conflict.ID()] = conflict
+	if released := vm.releaseStaleBlocks(); released != 0 {
+		t.Fatalf("expected no released blocks when disabled, got %d", released)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	ejson "encoding/json"
+	"net/http"
+
+	"github.com/ava-labs/avalanchego/utils/formatting"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+// StaticService helps users create new chains and is served at
+// "/ext/vm/[vmID]" without a running chain.
+type StaticService struct{}
+
+type EncodeGenesisArgs struct {
+	// Fields to override in [chain.DefaultGenesis]
+	Genesis  ejson.RawMessage    `serialize:"true" json:"genesis"`
+	Encoding formatting.Encoding `serialize:"true" json:"encoding"`
+}
+
+type EncodeGenesisReply struct {
+	Bytes    string              `serialize:"true" json:"bytes"`
+	Encoding formatting.Encoding `serialize:"true" json:"encoding"`
+}
+
+// EncodeGenesis returns the genesis bytes to provide when creating a chain.
+func (svc *StaticService) EncodeGenesis(_ *http.Request, args *EncodeGenesisArgs, reply *EncodeGenesisReply) error {
+	genesis := chain.DefaultGenesis()
+	if len(args.Genesis) > 0 {
+		if err := ejson.Unmarshal(args.Genesis, genesis); err != nil {
+			return err
+		}
+	}
+	if err := genesis.Verify(); err != nil {
+		return err
+	}
+	b, err := ejson.Marshal(genesis)
+	if err != nil {
+		return err
+	}
+	reply.Bytes, err = formatting.EncodeWithChecksum(args.Encoding, b)
+	reply.Encoding = args.Encoding
+	return err
+}
+
+type DecodeGenesisArgs struct {
+	Bytes    string              `serialize:"true" json:"bytes"`
+	Encoding formatting.Encoding `serialize:"true" json:"encoding"`
+}
+
+type DecodeGenesisReply struct {
+	Genesis *chain.Genesis `serialize:"true" json:"genesis"`
+}
+
+// DecodeGenesis parses and verifies encoded genesis bytes.
+func (svc *StaticService) DecodeGenesis(_ *http.Request, args *DecodeGenesisArgs, reply *DecodeGenesisReply) error {
+	b, err := formatting.Decode(args.Encoding, args.Bytes)
+	if err != nil {
+		return err
+	}
+	genesis := new(chain.Genesis)
+	if err := ejson.Unmarshal(b, genesis); err != nil {
+		return err
+	}
+	if err := genesis.Verify(); err != nil {
+		return err
+	}
+	reply.Genesis = genesis
+	return nil
+}
@@ -0,0 +1,47 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/utils/formatting"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+func TestStaticServiceGenesis(t *testing.T) {
+	svc := &StaticService{}
+
+	encoded := new(EncodeGenesisReply)
+	if err := svc.EncodeGenesis(nil, &EncodeGenesisArgs{
+		Genesis:  []byte(`{"magic":42,"minPrice":7}`),
+		Encoding: formatting.Hex,
+	}, encoded); err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := new(DecodeGenesisReply)
+	if err := svc.DecodeGenesis(nil, &DecodeGenesisArgs{
+		Bytes:    encoded.Bytes,
+		Encoding: encoded.Encoding,
+	}, decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Genesis.Magic != 42 || decoded.Genesis.MinPrice != 7 {
+		t.Fatalf("unexpected genesis %+v", decoded.Genesis)
+	}
+	// Fields that were not overridden keep their defaults
+	if decoded.Genesis.ClaimReward != chain.DefaultGenesis().ClaimReward {
+		t.Fatalf("unexpected claim reward %d", decoded.Genesis.ClaimReward)
+	}
+
+	// Invalid genesis parameters are rejected
+	if err := svc.EncodeGenesis(nil, &EncodeGenesisArgs{
+		Genesis: []byte(`{"minPrice":7}`),
+	}, new(EncodeGenesisReply)); !errors.Is(err, chain.ErrInvalidMagic) {
+		t.Fatalf("expected %v, got %v", chain.ErrInvalidMagic, err)
+	}
+}
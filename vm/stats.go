@@ -0,0 +1,135 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"github.com/ava-labs/avalanchego/database/versiondb"
+	log "github.com/inconshreveable/log15"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+// number of recently accepted blocks used to compute rolling aggregates
+const statsWindowSize = 128
+
+type blockStat struct {
+	height uint64
+	tmstmp int64
+	txs    int
+	price  uint64
+	cost   uint64
+	// minimum price paid by a transaction in the block (0 if it is empty)
+	minTxPrice uint64
+}
+
+// blockStats is a ring buffer of the most recently accepted blocks.
+type blockStats struct {
+	items  [statsWindowSize]blockStat
+	cursor int
+	count  int
+}
+
+func (s *blockStats) add(b *chain.StatelessBlock) {
+	stat := blockStat{height: b.Hght, tmstmp: b.Tmstmp, txs: len(b.Txs), price: b.Price, cost: b.Cost}
+	for i, tx := range b.Txs {
+		if p := tx.GetPrice(); i == 0 || p < stat.minTxPrice {
+			stat.minTxPrice = p
+		}
+	}
+	s.items[s.cursor] = stat
+	s.cursor = (s.cursor + 1) % statsWindowSize
+	if s.count < statsWindowSize {
+		s.count++
+	}
+}
+
+// aggregates returns the average block interval (in seconds) and
+// transactions per second over the window.
+func (s *blockStats) aggregates() (blockInterval float64, txsPerSecond float64) {
+	if s.count < 2 {
+		return 0, 0
+	}
+	oldest := s.items[(s.cursor-s.count+statsWindowSize)%statsWindowSize]
+	newest := s.items[(s.cursor-1+statsWindowSize)%statsWindowSize]
+	elapsed := float64(newest.tmstmp - oldest.tmstmp)
+	if elapsed <= 0 {
+		return 0, 0
+	}
+	// Txs in the oldest block were included before the window started
+	txs := -oldest.txs
+	for i := 0; i < s.count; i++ {
+		txs += s.items[i].txs
+	}
+	return elapsed / float64(s.count-1), float64(txs) / elapsed
+}
+
+// FeeHistoryEntry is the price and cost of an accepted block, and the lowest
+// price a transaction paid to be included in it.
+type FeeHistoryEntry struct {
+	Height     uint64 `serialize:"true" json:"height"`
+	Timestamp  int64  `serialize:"true" json:"timestamp"`
+	Txs        int    `serialize:"true" json:"txs"`
+	Price      uint64 `serialize:"true" json:"price"`
+	Cost       uint64 `serialize:"true" json:"cost"`
+	MinTxPrice uint64 `serialize:"true" json:"minTxPrice"`
+}
+
+// history returns up to [n] of the most recently accepted blocks in the
+// window, sorted from oldest to newest.
+func (s *blockStats) history(n int) []*FeeHistoryEntry {
+	if n <= 0 || n > s.count {
+		n = s.count
+	}
+	entries := make([]*FeeHistoryEntry, 0, n)
+	for i := n; i > 0; i-- {
+		item := s.items[(s.cursor-i+statsWindowSize)%statsWindowSize]
+		entries = append(entries, &FeeHistoryEntry{
+			Height:     item.height,
+			Timestamp:  item.tmstmp,
+			Txs:        item.txs,
+			Price:      item.price,
+			Cost:       item.cost,
+			MinTxPrice: item.minTxPrice,
+		})
+	}
+	return entries
+}
+
+// initStats seeds the rolling window from recently accepted blocks and
+// computes [chain.StateStats] if this database doesn't track them yet.
+func (vm *VM) initStats() error {
+	has, err := chain.HasStateStats(vm.db)
+	if err != nil {
+		return err
+	}
+	if !has {
+		vdb := versiondb.New(vm.db)
+		defer vdb.Abort()
+		s, err := chain.RecomputeStateStats(vdb)
+		if err != nil {
+			return err
+		}
+		if err := vdb.Commit(); err != nil {
+			return err
+		}
+		log.Info("computed state stats", "spaces", s.Spaces, "keys", s.Keys, "valueBytes", s.ValueBytes)
+	}
+
+	recent := []*chain.StatelessBlock{}
+	blk := vm.lastAccepted
+	for len(recent) < statsWindowSize {
+		recent = append(recent, blk)
+		if blk.Hght == 0 {
+			break
+		}
+		blk, err = vm.GetStatelessBlock(blk.Prnt)
+		if err != nil {
+			return err
+		}
+	}
+	for i := len(recent) - 1; i >= 0; i-- {
+		vm.blockStats.add(recent[i])
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"testing"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+func TestBlockStatsHistory(t *testing.T) {
+	s := &blockStats{}
+	if h := s.history(10); len(h) != 0 {
+		t.Fatalf("expected empty history, got %d entries", len(h))
+	}
+	for i := 0; i < statsWindowSize+5; i++ {
+		s.add(&chain.StatelessBlock{StatefulBlock: &chain.StatefulBlock{
+			Hght:   uint64(i),
+			Tmstmp: int64(i),
+			Price:  uint64(i + 1),
+			Cost:   2,
+		}})
+	}
+
+	h := s.history(3)
+	if len(h) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(h))
+	}
+	for i, e := range h {
+		height := uint64(statsWindowSize + 2 + i)
+		if e.Height != height || e.Price != height+1 || e.Cost != 2 || e.MinTxPrice != 0 {
+			t.Fatalf("#%d: unexpected entry %+v", i, e)
+		}
+	}
+	if h := s.history(0); len(h) != statsWindowSize || h[0].Height != 5 {
+		t.Fatalf("expected the full window starting at height 5, got %d entries", len(h))
+	}
+}
@@ -5,9 +5,11 @@
 package vm
 
 import (
+	"crypto/ecdsa"
 	ejson "encoding/json"
+	"errors"
 	"fmt"
-	"net/http"
+	"strings"
 	"time"
 
 	"github.com/ava-labs/avalanchego/cache"
@@ -22,8 +24,11 @@ import (
 	snowmanblock "github.com/ava-labs/avalanchego/snow/engine/snowman/block"
 	"github.com/ava-labs/avalanchego/utils"
 	"github.com/ava-labs/avalanchego/utils/json"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/gorilla/rpc/v2"
 	log "github.com/inconshreveable/log15"
+	"github.com/prometheus/client_golang/prometheus"
 
 	avagoversion "github.com/ava-labs/avalanchego/version"
 	"github.com/ava-labs/spacesvm/chain"
@@ -32,13 +37,16 @@ import (
 )
 
 const (
-	Name           = "spacesvm"
-	PublicEndpoint = "/public"
+	Name            = "spacesvm"
+	PublicEndpoint  = "/public"
+	AdminEndpoint   = "/admin"
+	GatewayEndpoint = "/gateway"
 )
 
 var (
-	_ snowmanblock.ChainVM = &VM{}
-	_ chain.VM             = &VM{}
+	_ snowmanblock.ChainVM        = &VM{}
+	_ snowmanblock.BatchedChainVM = &VM{}
+	_ chain.VM                    = &VM{}
 )
 
 type VM struct {
@@ -50,9 +58,31 @@ type VM struct {
 
 	bootstrapped utils.AtomicBool
 
+	// clock is the source of time for block building, verification, and
+	// mempool admission. It can be shifted with [Config.ClockOffset] and
+	// faked in tests via [Clock].
+	clock offsetClock
+
 	mempool   *mempool.Mempool
 	appSender common.AppSender
-	network   *PushNetwork
+	// senderLimiter limits the rate each sender may submit transactions (nil
+	// if disabled)
+	senderLimiter *rateLimiter
+	denylist      *denylist
+	// peers the node is connected to (see [VM.Connected])
+	peers *peerTracker
+	// handshakeRequestID is the ID of the last handshake sent
+	handshakeRequestID uint32
+	// responseKey signs resolve responses (nil if not configured)
+	responseKey *ecdsa.PrivateKey
+	// ipfsGateway IPFS values are redirected to (empty if not configured)
+	ipfsGateway string
+	// acceptors are notified of each accepted block (see [RegisterAcceptor])
+	acceptors snow.AcceptorGroup
+	// acceptHooks are notified of each accepted block and tx (see
+	// [AcceptHook])
+	acceptHooks []AcceptHook
+	network     *PushNetwork
 
 	// cache block objects to optimize "GetBlockStateless"
 	// only put when a block is accepted
@@ -74,16 +104,26 @@ type VM struct {
 	activityCacheCursor uint64
 	activityCache       []*chain.Activity
 
+	// Recently accepted blocks used for rolling stats
+	blockStats blockStats
+
 	// Execution checks
 	targetRangeUnits uint64
 
-	stop chan struct{}
+	metrics *metrics
+	// Keys deleted by pruning that have not yet been reclaimed by compacting
+	// the key range (guarded by ctx.Lock)
+	unreclaimedKeys int
+
+	// workers stops background work on shutdown
+	workers *lifecycle
+	// verifier recovers tx senders on workers shared by mempool admission,
+	// gossip, and block verification
+	verifier *chain.Verifier
 
 	builderStop chan struct{}
 	doneBuild   chan struct{}
 	doneGossip  chan struct{}
-	donePrune   chan struct{}
-	doneCompact chan struct{}
 }
 
 const (
@@ -110,18 +150,52 @@ func (vm *VM) Initialize(
 			return fmt.Errorf("failed to unmarshal config %s: %w", string(configBytes), err)
 		}
 	}
+	vm.clock.offset = vm.config.ClockOffset
 
 	vm.ctx = ctx
 	vm.db = dbManager.Current().Database
+	vm.acceptors = snow.NewAcceptorGroup(ctx.Log)
+	denylist, err := newDenylist(vm.config.DeniedSpaces)
+	if err != nil {
+		return err
+	}
+	vm.denylist = denylist
+	vm.peers = newPeerTracker()
+	if len(vm.config.ResponseKey) > 0 {
+		key, err := crypto.HexToECDSA(strings.TrimPrefix(vm.config.ResponseKey, "0x"))
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidResponseKey, err)
+		}
+		vm.responseKey = key
+		log.Info("signing resolve responses", "signer", crypto.PubkeyToAddress(key.PublicKey))
+	}
+	if len(vm.config.IPFSGateway) > 0 {
+		gateway, err := parseGateway(vm.config.IPFSGateway)
+		if err != nil {
+			return err
+		}
+		vm.ipfsGateway = gateway
+	}
 	vm.activityCache = make([]*chain.Activity, vm.config.ActivityCacheSize)
 
+	registry := prometheus.NewRegistry()
+	m, err := newMetrics(registry)
+	if err != nil {
+		return err
+	}
+	vm.metrics = m
+	if ctx.Metrics != nil {
+		if err := ctx.Metrics.Register(registry); err != nil {
+			return err
+		}
+	}
+
 	// Init channels before initializing other structs
-	vm.stop = make(chan struct{})
+	vm.workers = newLifecycle()
+	vm.verifier = chain.NewVerifier(vm.config.VerificationWorkers, vm.workers.Done())
 	vm.builderStop = make(chan struct{})
 	vm.doneBuild = make(chan struct{})
 	vm.doneGossip = make(chan struct{})
-	vm.donePrune = make(chan struct{})
-	vm.doneCompact = make(chan struct{})
 
 	vm.appSender = appSender
 	vm.network = vm.NewPushNetwork()
@@ -138,6 +212,16 @@ func (vm *VM) Initialize(
 		log.Error("could not determine if have last accepted")
 		return err
 	}
+	fresh := !has
+	if !has && len(vm.config.RestoreDir) > 0 {
+		meta, err := RestoreBackup(vm.db, vm.config.RestoreDir)
+		if err != nil {
+			log.Error("could not restore backup", "dir", vm.config.RestoreDir, "err", err)
+			return err
+		}
+		log.Info("restored backup", "dir", vm.config.RestoreDir, "lastAccepted", meta.LastAccepted, "height", meta.Height)
+		has = true
+	}
 
 	// Parse genesis data
 	vm.genesis = new(chain.Genesis)
@@ -149,11 +233,21 @@ func (vm *VM) Initialize(
 		log.Error("genesis is invalid")
 		return err
 	}
-	targetUnitsPerSecond := vm.genesis.TargetBlockSize / uint64(vm.genesis.TargetBlockRate)
-	vm.targetRangeUnits = targetUnitsPerSecond * uint64(vm.genesis.LookbackWindow)
-	log.Debug("loaded genesis", "genesis", string(genesisBytes), "target range units", vm.targetRangeUnits)
+	params := vm.genesis.Params()
+	vm.targetRangeUnits = params.TargetRangeUnits
+	log.Debug("loaded genesis", "genesis", string(genesisBytes))
+	log.Info("running chain params", "params", params)
 
 	vm.mempool = mempool.New(vm.genesis, vm.config.MempoolSize)
+	if vm.config.DroppedTxRecords > 0 {
+		vm.mempool.OnDrop(vm.persistDrop)
+	}
+	if err := registerMempoolMetrics(registry, vm.mempool); err != nil {
+		return err
+	}
+	if vm.config.SenderRateLimit > 0 {
+		vm.senderLimiter = newRateLimiter(vm.config.SenderRateLimit, vm.config.SenderRateBurst)
+	}
 
 	if has { //nolint:nestif
 		blkID, err := chain.GetLastAccepted(vm.db)
@@ -165,8 +259,19 @@ func (vm *VM) Initialize(
 		blk, err := vm.GetStatelessBlock(blkID)
 		if err != nil {
 			log.Error("could not load last accepted", "err", err)
+			return fmt.Errorf("%w: last accepted block %s unavailable: %v", ErrCorruption, blkID, err)
+		}
+		unclean, err := chain.HasUncleanShutdown(vm.db)
+		if err != nil {
+			log.Error("could not check for unclean shutdown", "err", err)
 			return err
 		}
+		if unclean && vm.config.IntegrityCheck {
+			if err := vm.checkIntegrity(blk); err != nil {
+				log.Error("integrity check failed", "err", err)
+				return err
+			}
+		}
 
 		vm.preferred, vm.lastAccepted = blkID, blk
 		log.Info("initialized spacesvm from last accepted", "block", blkID)
@@ -182,26 +287,64 @@ func (vm *VM) Initialize(
 			return err
 		}
 
+		// Write genesis state and the last accepted pointer in a single batch
+		// so that a crash during initialization can't leave allocations
+		// without a genesis block (or vice versa).
+		vdb := versiondb.New(vm.db)
+
 		// Set Balances
-		if err := vm.genesis.Load(vm.db, vm.AirdropData); err != nil {
+		if err := vm.genesis.Load(vdb, vm.AirdropData); err != nil {
 			log.Error("could not set genesis allocation", "err", err)
 			return err
 		}
 
-		if err := chain.SetLastAccepted(vm.db, genesisBlk); err != nil {
+		if err := chain.SetLastAccepted(vdb, genesisBlk); err != nil {
 			log.Error("could not set genesis as last accepted", "err", err)
 			return err
 		}
+		if err := vdb.Commit(); err != nil {
+			log.Error("could not commit genesis state", "err", err)
+			return err
+		}
 		gBlkID := genesisBlk.ID()
 		vm.preferred, vm.lastAccepted = gBlkID, genesisBlk
 		log.Info("initialized spacesvm from genesis", "block", gBlkID)
 	}
 	vm.AirdropData = nil
+	if err := chain.MarkRunning(vm.db); err != nil {
+		log.Error("could not mark database as running", "err", err)
+		return err
+	}
 
-	go vm.builder.Build()
-	go vm.builder.Gossip()
-	go vm.prune()
-	go vm.compact()
+	if err := vm.initAcceptHooks(); err != nil {
+		log.Error("could not initialize accept hooks", "err", err)
+		return err
+	}
+	if err := vm.initWebhooks(); err != nil {
+		log.Error("could not initialize webhooks", "err", err)
+		return err
+	}
+	if err := vm.initStats(); err != nil {
+		log.Error("could not initialize stats", "err", err)
+		return err
+	}
+	if err := vm.initHashIndex(); err != nil {
+		log.Error("could not initialize hash index", "err", err)
+		return err
+	}
+	if fresh && len(vm.config.ImportDir) > 0 {
+		meta, err := vm.importBlocks(vm.config.ImportDir)
+		if err != nil {
+			log.Error("could not import blocks", "dir", vm.config.ImportDir, "err", err)
+			return err
+		}
+		log.Info("imported blocks", "dir", vm.config.ImportDir, "lastAccepted", meta.LastAccepted, "height", meta.Height)
+	}
+
+	vm.workers.Go(vm.builder.Build)
+	vm.workers.Go(vm.builder.Gossip)
+	vm.workers.Go(vm.prune)
+	vm.workers.Go(vm.compact)
 	return nil
 }
 
@@ -228,30 +371,70 @@ func (vm *VM) onNormalOperationsStarted() error {
 		return nil
 	}
 	vm.bootstrapped.SetValue(true)
-	return nil
+	// History is not indexed while bootstrapping
+	return vm.indexDeferredHistory()
 }
 
 // implements "snowmanblock.ChainVM.common.VM"
+//
+// Shutdown stops all background workers (waiting for any in-flight block
+// building, gossip, pruning, or compaction to finish), flushes the mempool to
+// peers, and then closes the database.
 func (vm *VM) Shutdown() error {
-	close(vm.stop)
-	<-vm.doneBuild
-	<-vm.doneGossip
-	<-vm.donePrune
-	<-vm.doneCompact
+	vm.workers.Stop()
+	vm.flushMempool()
+	vm.closeAcceptHooks()
 	if vm.ctx == nil {
 		return nil
 	}
+	if err := chain.MarkStopped(vm.db); err != nil {
+		log.Warn("unable to mark database as stopped", "err", err)
+	}
 	return vm.db.Close()
 }
 
+// flushMempool gossips all pending transactions so they aren't lost when this
+// node stops
+func (vm *VM) flushMempool() {
+	if vm.appSender == nil {
+		return
+	}
+	pending := vm.mempool.Len()
+	for vm.mempool.Len() > 0 {
+		before := vm.mempool.Len()
+		if err := vm.network.RegossipTxs(); err != nil {
+			log.Warn("unable to flush mempool", "err", err)
+			return
+		}
+		if vm.mempool.Len() == before {
+			// The remaining txs were resent too many times
+			break
+		}
+	}
+	log.Debug("flushed mempool", "txs", pending)
+}
+
+// persistDrop records why [txID] left the mempool (see
+// [Config.DroppedTxRecords]). Txs included in blocks are not recorded, as
+// they are re-added to the mempool if their block is rejected.
+func (vm *VM) persistDrop(txID ids.ID, reason chain.DropReason) {
+	if reason == chain.DropIncluded {
+		return
+	}
+	d := &chain.DroppedTx{TxID: txID, Reason: reason, Timestamp: vm.clock.Time().Unix()}
+	if err := chain.PutDroppedTx(vm.db, vm.config.DroppedTxRecords, d); err != nil {
+		log.Warn("unable to record dropped tx", "txID", txID, "err", err)
+	}
+}
+
 // implements "snowmanblock.ChainVM.common.VM"
 func (vm *VM) Version() (string, error) { return version.Version, nil }
 
 // NewHandler returns a new Handler for a service where:
-//   * The handler's functionality is defined by [service]
+//   - The handler's functionality is defined by [service]
 //     [service] should be a gorilla RPC service (see https://www.gorillatoolkit.org/pkg/rpc/v2)
-//   * The name of the service is [name]
-//   * The LockOption is the first element of [lockOption]
+//   - The name of the service is [name]
+//   - The LockOption is the first element of [lockOption]
 //     By default the LockOption is WriteLock
 //     [lockOption] should have either 0 or 1 elements. Elements beside the first are ignored.
 func newHandler(name string, service interface{}, lockOption ...common.LockOption) (*common.HTTPHandler, error) {
@@ -277,48 +460,108 @@ func (vm *VM) CreateHandlers() (map[string]*common.HTTPHandler, error) {
 	if err != nil {
 		return nil, err
 	}
+	vm.instrument(apiPublic, public)
+	isWrite := func(method string) bool {
+		_, ok := writeMethods[method]
+		return ok
+	}
+	if len(vm.config.AuthTokens) > 0 {
+		public.Handler = authHandler(public.Handler, vm.config.AuthTokens, isWrite)
+	}
 	apis[PublicEndpoint] = public
+	if vm.config.AdminAPIEnabled {
+		// Admin calls acquire the context lock themselves when required
+		admin, err := newHandler(Name, &AdminService{vm: vm}, common.NoLock)
+		if err != nil {
+			return nil, err
+		}
+		vm.instrument(apiAdmin, admin)
+		if len(vm.config.AuthTokens) > 0 {
+			admin.Handler = authHandler(admin.Handler, vm.config.AuthTokens, func(string) bool { return true })
+		}
+		apis[AdminEndpoint] = admin
+	}
+	if len(vm.ipfsGateway) > 0 {
+		apis[GatewayEndpoint] = &common.HTTPHandler{
+			LockOptions: common.ReadLock,
+			Handler:     gatewayHandler(vm.ipfsGateway, vm.denylist.denied, vm.lookupValue),
+		}
+	}
+	if vm.config.SitesEnabled {
+		apis[SiteEndpoint] = &common.HTTPHandler{
+			LockOptions: common.ReadLock,
+			Handler:     siteHandler(vm.denylist.denied, vm.lookupValue),
+		}
+	}
+
+	// Limits are shared across endpoints so clients can't exceed them by
+	// spreading requests
+	var reads, writes *rateLimiter
+	if vm.config.ReadRateLimit > 0 {
+		reads = newRateLimiter(vm.config.ReadRateLimit, vm.config.ReadRateBurst)
+	}
+	if vm.config.WriteRateLimit > 0 {
+		writes = newRateLimiter(vm.config.WriteRateLimit, vm.config.WriteRateBurst)
+	}
+	var slots chan struct{}
+	if vm.config.MaxConcurrentRequests > 0 {
+		slots = make(chan struct{}, vm.config.MaxConcurrentRequests)
+	}
+	for endpoint, h := range apis {
+		if reads != nil || writes != nil {
+			limitWrites := isWrite
+			if endpoint == AdminEndpoint {
+				limitWrites = func(string) bool { return true }
+			}
+			h.Handler = rateLimitHandler(h.Handler, reads, writes, limitWrites)
+		}
+		if vm.config.CompressResponses {
+			h.Handler = gzipHandler(h.Handler)
+		}
+		if len(vm.config.CORSAllowedOrigins) > 0 {
+			h.Handler = corsHandler(h.Handler, vm.config.CORSAllowedOrigins, vm.config.CORSAllowedMethods)
+		}
+		h.Handler = limitHandler(h.Handler, vm.config.MaxRequestBytes, vm.config.RequestTimeout, slots)
+	}
 	return apis, nil
 }
 
 // implements "snowmanblock.ChainVM.common.VM"
 // for "ext/vm/[vmID]"
 func (vm *VM) CreateStaticHandlers() (map[string]*common.HTTPHandler, error) {
-	return nil, nil
-}
-
-// implements "snowmanblock.ChainVM.commom.VM.AppHandler"
-func (vm *VM) AppRequest(nodeID ids.NodeID, requestID uint32, deadline time.Time, request []byte) error {
-	// (currently) no app-specific messages
-	return nil
-}
-
-// implements "snowmanblock.ChainVM.commom.VM.AppHandler"
-func (vm *VM) AppRequestFailed(nodeID ids.NodeID, requestID uint32) error {
-	// (currently) no app-specific messages
-	return nil
-}
-
-// implements "snowmanblock.ChainVM.commom.VM.AppHandler"
-func (vm *VM) AppResponse(nodeID ids.NodeID, requestID uint32, response []byte) error {
-	// (currently) no app-specific messages
-	return nil
+	static, err := newHandler(Name, &StaticService{}, common.NoLock)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]*common.HTTPHandler{"": static}, nil
 }
 
 // implements "snowmanblock.ChainVM.commom.VM.health.Checkable"
 func (vm *VM) HealthCheck() (interface{}, error) {
-	return http.StatusOK, nil
+	c, err := vm.connectivity()
+	if err != nil {
+		return nil, err
+	}
+	vm.metrics.validators.Set(float64(c.Validators))
+	vm.metrics.connectedValidators.Set(float64(c.ConnectedValidators))
+	vm.metrics.incompatiblePeers.Set(float64(c.IncompatiblePeers))
+	return c, vm.checkConnectivity(c)
 }
 
 // implements "snowmanblock.ChainVM.commom.VM.validators.Connector"
 func (vm *VM) Connected(id ids.NodeID, nodeVersion avagoversion.Application) error {
-	// no-op
+	vm.metrics.connectedPeers.Set(float64(vm.peers.connect(id)))
+	if id == vm.ctx.NodeID {
+		vm.peers.setHandshake(id, localHandshake())
+		return nil
+	}
+	vm.sendHandshake(id)
 	return nil
 }
 
 // implements "snowmanblock.ChainVM.commom.VM.validators.Connector"
 func (vm *VM) Disconnected(id ids.NodeID) error {
-	// no-op
+	vm.metrics.connectedPeers.Set(float64(vm.peers.disconnect(id)))
 	return nil
 }
 
@@ -381,6 +624,61 @@ func (vm *VM) ParseBlock(source []byte) (snowman.Block, error) {
 	return newBlk, nil
 }
 
+// implements "snowmanblock.BatchedChainVM"
+// returns the bytes of [blkID] followed by as many of its ancestors as fit
+// within the provided limits, so bootstrapping peers can fetch many blocks
+// per request
+func (vm *VM) GetAncestors(
+	blkID ids.ID,
+	maxBlocksNum int,
+	maxBlocksSize int,
+	maxBlocksRetrivalTime time.Duration,
+) ([][]byte, error) {
+	start := time.Now()
+	blk, err := vm.GetStatelessBlock(blkID)
+	if errors.Is(err, database.ErrNotFound) {
+		// An empty response signals the peer to request the ancestors from
+		// someone else
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ancestors := make([][]byte, 1, maxBlocksNum)
+	ancestors[0] = blk.Bytes()
+	size := len(blk.Bytes()) + wrappers.IntLen
+	for len(ancestors) < maxBlocksNum && time.Since(start) < maxBlocksRetrivalTime {
+		if blk.Hght == 0 {
+			break
+		}
+		if blk, err = vm.GetStatelessBlock(blk.Prnt); err != nil {
+			break
+		}
+		// Each block is prefixed by its length in the response
+		newSize := size + len(blk.Bytes()) + wrappers.IntLen
+		if newSize > maxBlocksSize {
+			break
+		}
+		ancestors = append(ancestors, blk.Bytes())
+		size = newSize
+	}
+	return ancestors, nil
+}
+
+// implements "snowmanblock.BatchedChainVM"
+func (vm *VM) BatchedParseBlock(blks [][]byte) ([]snowman.Block, error) {
+	parsed := make([]snowman.Block, len(blks))
+	for i, source := range blks {
+		blk, err := vm.ParseBlock(source)
+		if err != nil {
+			return nil, err
+		}
+		parsed[i] = blk
+	}
+	return parsed, nil
+}
+
 // implements "snowmanblock.ChainVM"
 // called via "avalanchego" node over RPC
 func (vm *VM) BuildBlock() (snowman.Block, error) {
@@ -405,19 +703,30 @@ func (vm *VM) Submit(txs ...*chain.Transaction) (errs []error) {
 	if err != nil {
 		return []error{err}
 	}
-	now := time.Now().Unix()
+	now := vm.clock.Time().Unix()
 	ctx, err := vm.ExecutionContext(now, blk)
 	if err != nil {
 		return []error{err}
 	}
 	vdb := versiondb.New(vm.db)
 
-	// Expire outdated spaces before checking submission validity
-	if err := chain.ExpireNext(vdb, blk.Tmstmp, now, true); err != nil {
+	// Expire outdated spaces before checking submission validity (values are
+	// not read by submission checks, so none are cleared)
+	if err := chain.ExpireNext(vdb, blk.Tmstmp, now, 0); err != nil {
+		return []error{err}
+	}
+	if err := chain.ActivateMoves(vdb, now); err != nil {
 		return []error{err}
 	}
 
-	for _, tx := range txs {
+	// Recover senders concurrently before executing the txs in order
+	initErrs := vm.verifier.InitTxs(vm.genesis, txs)
+	for i, tx := range txs {
+		if err := initErrs[i]; err != nil {
+			log.Debug("failed to initialize transaction", "error", err)
+			errs = append(errs, err)
+			continue
+		}
 		if err := vm.submit(tx, vdb, now, ctx); err != nil {
 			log.Debug("failed to submit transaction",
 				"tx", tx.ID(),
@@ -431,18 +740,29 @@ func (vm *VM) Submit(txs ...*chain.Transaction) (errs []error) {
 	return errs
 }
 
+// submit assumes [tx] has been initialized
 func (vm *VM) submit(tx *chain.Transaction, db database.Database, blkTime int64, ctx *chain.Context) error {
-	if err := tx.Init(vm.genesis); err != nil {
-		return err
-	}
 	if err := tx.ExecuteBase(vm.genesis); err != nil {
 		return err
 	}
+	// Transactions already in the mempool (such as those regossiped) do not
+	// count against the sender's limit
+	if vm.senderLimiter != nil && !vm.mempool.Has(tx.ID()) && !vm.senderLimiter.allow(tx.Sender().Hex()) {
+		vm.metrics.senderRateLimited.Inc()
+		return fmt.Errorf("%w: %s", ErrSenderRateLimited, tx.Sender())
+	}
+	if space := tx.Activity().Space; vm.denylist.denied(space) {
+		return fmt.Errorf("%w: %s", ErrSpaceDenied, space)
+	}
 	dummy := chain.DummyBlock(blkTime, tx)
 	if err := tx.Execute(vm.genesis, db, dummy, ctx); err != nil {
 		return err
 	}
-	vm.mempool.Add(tx)
+	if !vm.mempool.Add(tx) {
+		if reason, _ := vm.mempool.DropReason(tx.ID()); reason == chain.DropConflict {
+			return fmt.Errorf("%w: %s", ErrClaimConflict, tx.Activity().Space)
+		}
+	}
 	return nil
 }
 
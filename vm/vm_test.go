@@ -4,11 +4,15 @@
 package vm
 
 import (
+	"bytes"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/ava-labs/avalanchego/cache"
+	"github.com/ava-labs/avalanchego/database/memdb"
 	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
 	"github.com/ava-labs/spacesvm/chain"
 )
 
@@ -44,3 +48,99 @@ func TestBlockCache(t *testing.T) {
 		t.Fatalf("block expected %+v, got %+v", blk, blk2)
 	}
 }
+
+func TestAcceptedResetsPreference(t *testing.T) {
+	newBlock := func(prnt ids.ID, hght uint64, tmstmp int64) *chain.StatelessBlock {
+		blk, err := chain.ParseStatefulBlock(&chain.StatefulBlock{
+			Prnt:   prnt,
+			Hght:   hght,
+			Tmstmp: tmstmp,
+		}, nil, choices.Processing, &VM{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return blk
+	}
+	root := newBlock(ids.GenerateTestID(), 1, 1)
+	left := newBlock(root.ID(), 2, 2)
+	right := newBlock(root.ID(), 2, 3)
+	leftChild := newBlock(left.ID(), 3, 4)
+
+	tt := []struct {
+		name      string
+		preferred *chain.StatelessBlock
+		expected  *chain.StatelessBlock
+	}{
+		{name: "preferred accepted", preferred: left, expected: left},
+		{name: "preferred descendant", preferred: leftChild, expected: leftChild},
+		{name: "preferred sibling", preferred: right, expected: left},
+	}
+	for _, tv := range tt {
+		vm := VM{
+			db:             memdb.New(),
+			blocks:         &cache.LRU{Size: 3},
+			verifiedBlocks: make(map[ids.ID]*chain.StatelessBlock),
+			preferred:      tv.preferred.ID(),
+		}
+		for _, blk := range []*chain.StatelessBlock{left, right, leftChild} {
+			vm.verifiedBlocks[blk.ID()] = blk
+		}
+		vm.Accepted(root)
+
+		vm.Accepted(left)
+		if vm.preferred != tv.expected.ID() {
+			t.Fatalf("%s: preferred expected %s, got %s", tv.name, tv.expected.ID(), vm.preferred)
+		}
+	}
+}
+
+func TestGetAncestors(t *testing.T) {
+	vm := &VM{
+		db:             memdb.New(),
+		blocks:         &cache.LRU{Size: 10},
+		verifiedBlocks: make(map[ids.ID]*chain.StatelessBlock),
+	}
+	blks := make([]*chain.StatelessBlock, 5)
+	prnt := ids.Empty
+	for i := range blks {
+		blk, err := chain.ParseStatefulBlock(&chain.StatefulBlock{
+			Prnt:   prnt,
+			Hght:   uint64(i),
+			Tmstmp: int64(i),
+		}, nil, choices.Accepted, vm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		vm.blocks.Put(blk.ID(), blk)
+		blks[i] = blk
+		prnt = blk.ID()
+	}
+	tip := blks[len(blks)-1]
+
+	tt := []struct {
+		name     string
+		blkID    ids.ID
+		maxNum   int
+		maxSize  int
+		expected int
+	}{
+		{name: "all ancestors", blkID: tip.ID(), maxNum: 10, maxSize: 1 << 20, expected: 5},
+		{name: "max blocks", blkID: tip.ID(), maxNum: 2, maxSize: 1 << 20, expected: 2},
+		{name: "max size", blkID: tip.ID(), maxNum: 10, maxSize: 2 * (len(tip.Bytes()) + 4), expected: 2},
+		{name: "unknown block", blkID: ids.GenerateTestID(), maxNum: 10, maxSize: 1 << 20, expected: 0},
+	}
+	for _, tv := range tt {
+		ancestors, err := vm.GetAncestors(tv.blkID, tv.maxNum, tv.maxSize, time.Minute)
+		if err != nil {
+			t.Fatalf("%s: %v", tv.name, err)
+		}
+		if len(ancestors) != tv.expected {
+			t.Fatalf("%s: expected %d ancestors, got %d", tv.name, tv.expected, len(ancestors))
+		}
+		for i, b := range ancestors {
+			if !bytes.Equal(b, blks[len(blks)-1-i].Bytes()) {
+				t.Fatalf("%s: unexpected ancestor at %d", tv.name, i)
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+// WarpSignature is a node's signature over a [chain.WarpMessage]. Relayers
+// collect signatures from the validators of the source subnet and deliver
+// them with the message to the consuming subnet.
+//
+// avalanchego v1.7 does not expose validator BLS keys to VMs, so messages are
+// signed with the node's response key instead.
+type WarpSignature struct {
+	Signer    common.Address `serialize:"true" json:"signer"`
+	Signature hexutil.Bytes  `serialize:"true" json:"signature"`
+}
+
+func signWarpMessage(m *chain.WarpMessage, priv *ecdsa.PrivateKey) (*WarpSignature, error) {
+	sig, err := chain.Sign(m.Digest(), priv)
+	if err != nil {
+		return nil, err
+	}
+	return &WarpSignature{
+		Signer:    crypto.PubkeyToAddress(priv.PublicKey),
+		Signature: sig,
+	}, nil
+}
+
+// Verify checks that [s] is [signer]'s signature over [m]
+func (s *WarpSignature) Verify(m *chain.WarpMessage, signer common.Address) error {
+	if s.Signer != signer {
+		return fmt.Errorf("%w: signed by %s, expected %s", ErrInvalidWarpSignature, s.Signer, signer)
+	}
+	pk, err := chain.DeriveSender(m.Digest(), s.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWarpSignature, err)
+	}
+	if recovered := crypto.PubkeyToAddress(*pk); recovered != signer {
+		return fmt.Errorf("%w: signature recovers %s, expected %s", ErrInvalidWarpSignature, recovered, signer)
+	}
+	return nil
+}
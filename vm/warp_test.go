@@ -0,0 +1,82 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+func TestWarpSignature(t *testing.T) {
+	t.Parallel()
+
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := crypto.PubkeyToAddress(priv.PublicKey)
+	other, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := &chain.WarpMessage{
+		SourceChainID: ids.GenerateTestID(),
+		TxID:          ids.GenerateTestID(),
+		Sender:        signer,
+		Space:         "space",
+		Key:           "key",
+		ValueHash:     crypto.Keccak256Hash([]byte("value")),
+		Timestamp:     10,
+	}
+	s, err := signWarpMessage(m, priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Verify(m, signer); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	tt := []struct {
+		name   string
+		modify func(m *chain.WarpMessage, s *WarpSignature)
+	}{
+		{
+			name:   "value",
+			modify: func(m *chain.WarpMessage, _ *WarpSignature) { m.ValueHash = crypto.Keccak256Hash([]byte("tampered")) },
+		},
+		{
+			// "spac"+"ekey" must not collide with "space"+"key"
+			name:   "boundary",
+			modify: func(m *chain.WarpMessage, _ *WarpSignature) { m.Space, m.Key = "spac", "ekey" },
+		},
+		{
+			name:   "chain",
+			modify: func(m *chain.WarpMessage, _ *WarpSignature) { m.SourceChainID = ids.GenerateTestID() },
+		},
+		{
+			name: "signer",
+			modify: func(_ *chain.WarpMessage, s *WarpSignature) {
+				s.Signer = crypto.PubkeyToAddress(other.PublicKey)
+			},
+		},
+		{
+			name:   "signature",
+			modify: func(_ *chain.WarpMessage, s *WarpSignature) { s.Signature = s.Signature[1:] },
+		},
+	}
+	for _, tv := range tt {
+		cm, cs := *m, *s
+		cs.Signature = append([]byte{}, s.Signature...)
+		tv.modify(&cm, &cs)
+		if err := cs.Verify(&cm, signer); !errors.Is(err, ErrInvalidWarpSignature) {
+			t.Fatalf("%s: expected %v, got %v", tv.name, ErrInvalidWarpSignature, err)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	log "github.com/inconshreveable/log15"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/parser"
+)
+
+const (
+	// WebhookSignatureHeader is the hex-encoded HMAC-SHA256 of the payload
+	// keyed by the webhook secret (prefixed by "sha256=")
+	WebhookSignatureHeader = "X-Spaces-Signature"
+
+	// webhookQueueSize is the number of payloads that may be waiting to be
+	// delivered to each webhook before new payloads are dropped
+	webhookQueueSize = 1024
+	webhookTimeout   = 10 * time.Second
+	webhookMaxDelay  = time.Minute
+)
+
+// WebhookConfig subscribes [URL] to the changes accepted under [Prefix]
+type WebhookConfig struct {
+	// Prefix is matched against the path of each change ("space" for
+	// changes of the space itself and "space/key" for changes of its keys)
+	Prefix string `serialize:"true" json:"prefix"`
+	// URL must be an https URL
+	URL string `serialize:"true" json:"url"`
+	// Secret signs payloads (see [WebhookSignatureHeader]). Payloads are not
+	// signed when empty.
+	Secret string `serialize:"true" json:"secret"`
+}
+
+// WebhookPayload is POSTed to a webhook for each accepted block that includes
+// changes under its prefix
+type WebhookPayload struct {
+	BlockID   ids.ID           `json:"blockId"`
+	Height    uint64           `json:"height"`
+	Timestamp int64            `json:"timestamp"`
+	Prefix    string           `json:"prefix"`
+	Changes   []*WebhookChange `json:"changes"`
+}
+
+// WebhookChange is an accepted transaction that changed [Path]. [Value] is
+// the value written by set transactions (and claims, for the keys they set).
+type WebhookChange struct {
+	Path string `json:"path"`
+	*chain.Activity
+	Value hexutil.Bytes `json:"value,omitempty"`
+}
+
+type webhook struct {
+	config  *WebhookConfig
+	queue   chan []byte
+	dropped uint64
+}
+
+// webhooks deliver the changes of accepted blocks to the configured webhooks
+// in the background, retrying failed deliveries with exponential backoff
+type webhooks struct {
+	hooks   []*webhook
+	client  *http.Client
+	retries int
+	backoff time.Duration
+	workers *lifecycle
+}
+
+// parseWebhook validates [c] (a webhook must be https)
+func parseWebhook(c *WebhookConfig) error {
+	u, err := url.Parse(c.URL)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidWebhook, err)
+	}
+	if u.Scheme != "https" || len(u.Host) == 0 {
+		return fmt.Errorf("%w: %q is not an https URL", ErrInvalidWebhook, c.URL)
+	}
+	return nil
+}
+
+// initWebhooks starts delivering to [Config.Webhooks] (if any)
+func (vm *VM) initWebhooks() error {
+	if len(vm.config.Webhooks) == 0 {
+		return nil
+	}
+	w := &webhooks{
+		client:  &http.Client{Timeout: webhookTimeout},
+		retries: vm.config.WebhookRetries,
+		backoff: vm.config.WebhookBackoff,
+		workers: vm.workers,
+	}
+	for _, c := range vm.config.Webhooks {
+		if err := parseWebhook(c); err != nil {
+			return err
+		}
+		w.hooks = append(w.hooks, &webhook{config: c, queue: make(chan []byte, webhookQueueSize)})
+	}
+	w.start()
+	vm.acceptHooks = append(vm.acceptHooks, w)
+	log.Info("enabled webhooks", "count", len(w.hooks))
+	return nil
+}
+
+func (w *webhooks) start() {
+	for _, h := range w.hooks {
+		h := h
+		w.workers.Go(func() { w.deliver(h) })
+	}
+}
+
+// webhookChanges returns the paths changed by the txs of [b]
+func webhookChanges(b *chain.StatelessBlock) []*WebhookChange {
+	changes := make([]*WebhookChange, 0, len(b.Txs))
+	for _, tx := range b.Txs {
+		activity := tx.Activity()
+		if len(activity.Space) == 0 {
+			continue
+		}
+		activity.Tmstmp = b.Tmstmp
+		change := &WebhookChange{Path: activity.Space, Activity: activity}
+		if len(activity.Key) > 0 {
+			change.Path += parser.Delimiter + activity.Key
+		}
+		if set, ok := tx.UnsignedTransaction.(*chain.SetTx); ok {
+			change.Value = set.Value
+		}
+		changes = append(changes, change)
+		// Keys set by a claim change their own paths
+		if claim, ok := tx.UnsignedTransaction.(*chain.ClaimTx); ok {
+			for _, ck := range claim.Keys {
+				keyActivity := *activity
+				keyActivity.Key = ck.Key
+				changes = append(changes, &WebhookChange{
+					Path:     activity.Space + parser.Delimiter + ck.Key,
+					Activity: &keyActivity,
+					Value:    ck.Value,
+				})
+			}
+		}
+	}
+	return changes
+}
+
+// AcceptedBlock queues a payload for every webhook with changes in [b]
+func (w *webhooks) AcceptedBlock(b *chain.StatelessBlock) error {
+	changes := webhookChanges(b)
+	if len(changes) == 0 {
+		return nil
+	}
+	for _, h := range w.hooks {
+		p := &WebhookPayload{
+			BlockID:   b.ID(),
+			Height:    b.Hght,
+			Timestamp: b.Tmstmp,
+			Prefix:    h.config.Prefix,
+		}
+		for _, change := range changes {
+			if strings.HasPrefix(change.Path, h.config.Prefix) {
+				p.Changes = append(p.Changes, change)
+			}
+		}
+		if len(p.Changes) == 0 {
+			continue
+		}
+		body, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+		select {
+		case h.queue <- body:
+		default:
+			h.dropped++
+			log.Warn("webhook queue is full", "url", h.config.URL, "blkID", b.ID(), "dropped", h.dropped)
+		}
+	}
+	return nil
+}
+
+// AcceptedTx is a no-op (changes are delivered per block)
+func (w *webhooks) AcceptedTx(*chain.StatelessBlock, *chain.Transaction) error {
+	return nil
+}
+
+// deliver POSTs the payloads queued for [h] until the VM shuts down
+func (w *webhooks) deliver(h *webhook) {
+	for {
+		select {
+		case body := <-h.queue:
+			if err := w.post(h, body); err != nil {
+				log.Warn("unable to deliver webhook", "url", h.config.URL, "err", err)
+			}
+		case <-w.workers.Done():
+			return
+		}
+	}
+}
+
+// post sends [body] to [h], retrying up to [retries] times
+func (w *webhooks) post(h *webhook, body []byte) error {
+	delay := w.backoff
+	for attempt := 0; ; attempt++ {
+		err := w.send(h, body)
+		if err == nil || attempt >= w.retries {
+			return err
+		}
+		log.Debug("retrying webhook", "url", h.config.URL, "attempt", attempt+1, "err", err)
+		select {
+		case <-time.After(delay):
+		case <-w.workers.Done():
+			return err
+		}
+		if delay *= 2; delay > webhookMaxDelay {
+			delay = webhookMaxDelay
+		}
+	}
+}
+
+func (w *webhooks) send(h *webhook, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, h.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if len(h.config.Secret) > 0 {
+		req.Header.Set(WebhookSignatureHeader, SignWebhook(h.config.Secret, body))
+	}
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: %s", ErrWebhookFailed, resp.Status)
+	}
+	return nil
+}
+
+// SignWebhook returns the [WebhookSignatureHeader] of [body] for [secret], so
+// receivers can authenticate payloads.
+func SignWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,117 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vm
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+
+	"github.com/ava-labs/spacesvm/chain"
+)
+
+func TestParseWebhook(t *testing.T) {
+	for _, u := range []string{"http://example.com/hook", "example.com/hook", "https://"} {
+		if err := parseWebhook(&WebhookConfig{URL: u}); !errors.Is(err, ErrInvalidWebhook) {
+			t.Fatalf("expected %q to be invalid, got %v", u, err)
+		}
+	}
+	if err := parseWebhook(&WebhookConfig{URL: "https://example.com/hook"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWebhooks(t *testing.T) {
+	var (
+		l        sync.Mutex
+		attempts int
+		payloads = make(chan *WebhookPayload, 2)
+	)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if sig := r.Header.Get(WebhookSignatureHeader); sig != SignWebhook("secret", body) {
+			t.Errorf("unexpected signature %q", sig)
+		}
+		// Fail the first delivery so it is retried
+		l.Lock()
+		attempts++
+		first := attempts == 1
+		l.Unlock()
+		if first {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		p := new(WebhookPayload)
+		if err := json.Unmarshal(body, p); err != nil {
+			t.Error(err)
+			return
+		}
+		payloads <- p
+	}))
+	defer srv.Close()
+
+	workers := newLifecycle()
+	defer workers.Stop()
+	w := &webhooks{
+		client:  srv.Client(),
+		retries: 2,
+		backoff: time.Millisecond,
+		workers: workers,
+	}
+	for _, prefix := range []string{"foo/", "baz"} {
+		c := &WebhookConfig{Prefix: prefix, URL: srv.URL, Secret: "secret"}
+		w.hooks = append(w.hooks, &webhook{config: c, queue: make(chan []byte, 1)})
+	}
+	w.start()
+
+	blk, err := chain.ParseStatefulBlock(&chain.StatefulBlock{
+		Prnt:   ids.GenerateTestID(),
+		Hght:   1,
+		Tmstmp: 10,
+	}, nil, choices.Accepted, &VM{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	blk.Txs = []*chain.Transaction{
+		chain.NewTx(&chain.ClaimTx{BaseTx: &chain.BaseTx{}, Space: "foo"}, nil),
+		chain.NewTx(&chain.SetTx{BaseTx: &chain.BaseTx{}, Space: "foo", Key: "a", Value: []byte("hello")}, nil),
+		chain.NewTx(&chain.ClaimTx{BaseTx: &chain.BaseTx{}, Space: "bar"}, nil),
+	}
+	if err := w.AcceptedBlock(blk); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case p := <-payloads:
+		if p.BlockID != blk.ID() || p.Height != 1 || p.Timestamp != 10 || p.Prefix != "foo/" {
+			t.Fatalf("unexpected payload %+v", p)
+		}
+		if len(p.Changes) != 1 {
+			t.Fatalf("expected 1 change, got %d", len(p.Changes))
+		}
+		c := p.Changes[0]
+		if c.Path != "foo/a" || c.Typ != "set" || string(c.Value) != "hello" {
+			t.Fatalf("unexpected change %+v", c)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("webhook was not delivered")
+	}
+	select {
+	case p := <-payloads:
+		t.Fatalf("unexpected payload %+v", p)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
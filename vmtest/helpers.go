@@ -0,0 +1,128 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package vmtest
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow/choices"
+	"github.com/ava-labs/avalanchego/snow/consensus/snowman"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/tdata"
+)
+
+var ErrUnexpectedStatus = errors.New("unexpected block status")
+
+// SignTx signs [utx] with [priv] and initializes the resulting transaction.
+// [utx] must already have its BlockID, Magic, and Price set.
+func SignTx(g *chain.Genesis, utx chain.UnsignedTransaction, priv *ecdsa.PrivateKey) (*chain.Transaction, error) {
+	dh, err := chain.DigestHash(utx)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := chain.Sign(dh, priv)
+	if err != nil {
+		return nil, err
+	}
+	tx := chain.NewTx(utx, sig)
+	if err := tx.Init(g); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// PrepareTx populates the BlockID, Magic, ChainID, and Price of [utx] using
+// the instance's chain, last accepted block, and suggested fee.
+func (i *Instance) PrepareTx(ctx context.Context, utx chain.UnsignedTransaction) error {
+	p, err := client.TxParams(ctx, i.Client)
+	if err != nil {
+		return err
+	}
+	p.Prepare(utx)
+	return nil
+}
+
+// IssueRawTx prepares, signs, and issues [utx] to the instance.
+func (i *Instance) IssueRawTx(
+	ctx context.Context,
+	utx chain.UnsignedTransaction,
+	priv *ecdsa.PrivateKey,
+) (ids.ID, error) {
+	p, err := client.TxParams(ctx, i.Client)
+	if err != nil {
+		return ids.Empty, err
+	}
+	b, err := p.Build(utx)
+	if err != nil {
+		return ids.Empty, err
+	}
+	tx, err := b.Sign(priv)
+	if err != nil {
+		return ids.Empty, err
+	}
+	return i.Client.IssueRawTx(ctx, tx.Bytes())
+}
+
+// IssueTx requests typed data for [input] from the instance, signs it, and
+// issues it.
+func (i *Instance) IssueTx(ctx context.Context, input *chain.Input, priv *ecdsa.PrivateKey) (ids.ID, error) {
+	td, _, err := i.Client.SuggestedFee(ctx, input)
+	if err != nil {
+		return ids.Empty, err
+	}
+	dh, err := tdata.DigestHash(td)
+	if err != nil {
+		return ids.Empty, err
+	}
+	sig, err := chain.Sign(dh, priv)
+	if err != nil {
+		return ids.Empty, err
+	}
+	return i.Client.IssueTx(ctx, td, sig)
+}
+
+// BuildAndPrefer mimics the consensus engine by signalling the builder,
+// building a block, verifying it, and setting it as preferred.
+func (i *Instance) BuildAndPrefer() (snowman.Block, error) {
+	// manually signal ready
+	i.Builder.NotifyBuild()
+	// manually ack ready sig as in engine
+	<-i.ToEngine
+
+	blk, err := i.VM.BuildBlock()
+	if err != nil {
+		return nil, err
+	}
+	if err := blk.Verify(); err != nil {
+		return nil, err
+	}
+	if st := blk.Status(); st != choices.Processing {
+		return nil, fmt.Errorf("%w: expected %s got %s", ErrUnexpectedStatus, choices.Processing, st)
+	}
+	if err := i.VM.SetPreference(blk.ID()); err != nil {
+		return nil, err
+	}
+	return blk, nil
+}
+
+// BuildAndAccept is [BuildAndPrefer] followed by accepting the block.
+func (i *Instance) BuildAndAccept() (snowman.Block, error) {
+	blk, err := i.BuildAndPrefer()
+	if err != nil {
+		return nil, err
+	}
+	if err := blk.Accept(); err != nil {
+		return nil, err
+	}
+	if st := blk.Status(); st != choices.Accepted {
+		return nil, fmt.Errorf("%w: expected %s got %s", ErrUnexpectedStatus, choices.Accepted, st)
+	}
+	return blk, nil
+}
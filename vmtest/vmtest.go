@@ -0,0 +1,260 @@
+// Copyright (C) 2019-2021, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package vmtest implements an in-memory spacesvm test harness that can be
+// used to write integration tests against the VM without running a node.
+package vmtest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"time"
+
+	"github.com/ava-labs/avalanchego/chains/atomic"
+	"github.com/ava-labs/avalanchego/database/manager"
+	"github.com/ava-labs/avalanchego/database/memdb"
+	"github.com/ava-labs/avalanchego/database/prefixdb"
+	"github.com/ava-labs/avalanchego/ids"
+	"github.com/ava-labs/avalanchego/snow"
+	"github.com/ava-labs/avalanchego/snow/engine/common"
+	"github.com/ava-labs/avalanchego/utils/logging"
+	"github.com/ava-labs/avalanchego/utils/wrappers"
+	avagoversion "github.com/ava-labs/avalanchego/version"
+
+	"github.com/ava-labs/spacesvm/chain"
+	"github.com/ava-labs/spacesvm/client"
+	"github.com/ava-labs/spacesvm/vm"
+)
+
+const defaultRequestTimeout = 30 * time.Second
+
+var (
+	ErrNoInstances = errors.New("at least one instance is required")
+
+	vmDBPrefix           = []byte("vm")
+	sharedMemoryDBPrefix = []byte("shared")
+)
+
+// Instance is a single embedded VM served over an in-process HTTP server.
+type Instance struct {
+	NodeID     ids.NodeID
+	VM         *vm.VM
+	ToEngine   chan common.Message
+	HTTPServer *httptest.Server
+	Client     client.Client
+	Builder    *vm.ManualBuilder
+	// Memory is the node's shared memory, which tests can use to act as
+	// other chains of the subnet (every chain is considered part of it)
+	Memory *atomic.Memory
+}
+
+// Network is a set of [Instance]s that share a genesis and gossip to each
+// other.
+type Network struct {
+	Genesis      *chain.Genesis
+	GenesisBytes []byte
+	SubnetID     ids.ID
+	ChainID      ids.ID
+	Instances    []*Instance
+
+	app *appSender
+}
+
+type Op struct {
+	airdropData    []byte
+	config         []byte
+	requestTimeout time.Duration
+	networkID      uint32
+	chainID        ids.ID
+}
+
+type OpOption func(*Op)
+
+func (op *Op) applyOpts(opts []OpOption) {
+	for _, opt := range opts {
+		opt(op)
+	}
+}
+
+// WithAirdropData sets the airdrop data provided to each VM.
+func WithAirdropData(b []byte) OpOption {
+	return func(op *Op) { op.airdropData = b }
+}
+
+// WithConfig sets the config bytes provided to each VM.
+func WithConfig(b []byte) OpOption {
+	return func(op *Op) { op.config = b }
+}
+
+// WithRequestTimeout sets the timeout used by each instance's client.
+func WithRequestTimeout(t time.Duration) OpOption {
+	return func(op *Op) { op.requestTimeout = t }
+}
+
+// WithNetworkID sets the network ID in each VM's snow.Context.
+func WithNetworkID(id uint32) OpOption {
+	return func(op *Op) { op.networkID = id }
+}
+
+// WithChainID sets the chain ID in each VM's snow.Context (a random ID by
+// default), so a network can continue the chain of another.
+func WithChainID(id ids.ID) OpOption {
+	return func(op *Op) { op.chainID = id }
+}
+
+// New creates [n] VMs backed by memdb from [g]. Block production is manual:
+// use [Instance.BuildAndAccept] to produce blocks.
+func New(g *chain.Genesis, n int, opts ...OpOption) (*Network, error) {
+	if n < 1 {
+		return nil, ErrNoInstances
+	}
+	ret := &Op{requestTimeout: defaultRequestTimeout, networkID: 1}
+	ret.applyOpts(opts)
+
+	genesisBytes, err := json.Marshal(g)
+	if err != nil {
+		return nil, err
+	}
+
+	subnetID := ids.GenerateTestID()
+	chainID := ret.chainID
+	if chainID == ids.Empty {
+		chainID = ids.GenerateTestID()
+	}
+	net := &Network{
+		Genesis:      g,
+		GenesisBytes: genesisBytes,
+		SubnetID:     subnetID,
+		ChainID:      chainID,
+		Instances:    make([]*Instance, n),
+		app:          &appSender{},
+	}
+	for i := range net.Instances {
+		// The VM database and shared memory must share a base database, so
+		// atomic operations can be written with the block that performs them
+		baseDB := memdb.New()
+		db, err := manager.NewManagerFromDBs([]*manager.VersionedDatabase{{
+			Database: prefixdb.New(vmDBPrefix, baseDB),
+			Version:  avagoversion.CurrentDatabase,
+		}})
+		if err != nil {
+			return nil, err
+		}
+		memory := &atomic.Memory{}
+		if err := memory.Initialize(logging.NoLog{}, prefixdb.New(sharedMemoryDBPrefix, baseDB)); err != nil {
+			return nil, err
+		}
+
+		ctx := &snow.Context{
+			NetworkID:    ret.networkID,
+			SubnetID:     subnetID,
+			ChainID:      chainID,
+			NodeID:       ids.GenerateTestNodeID(),
+			SharedMemory: memory.NewSharedMemory(chainID),
+			SNLookup:     subnetLookup(subnetID),
+		}
+
+		toEngine := make(chan common.Message, 1)
+
+		v := &vm.VM{AirdropData: ret.airdropData}
+		if err := v.Initialize(
+			ctx,
+			db,
+			genesisBytes,
+			nil,
+			ret.config,
+			toEngine,
+			nil,
+			net.app,
+		); err != nil {
+			return nil, fmt.Errorf("%w: failed to initialize vm %d", err, i)
+		}
+		// Instances start with the chain already decided, as a node does once
+		// bootstrapping finishes
+		if err := v.SetState(snow.NormalOp); err != nil {
+			return nil, fmt.Errorf("%w: failed to start vm %d", err, i)
+		}
+
+		var mb *vm.ManualBuilder
+		v.SetBlockBuilder(func() vm.BlockBuilder {
+			mb = v.NewManualBuilder()
+			return mb
+		})
+
+		hd, err := v.CreateHandlers()
+		if err != nil {
+			return nil, err
+		}
+		httpServer := httptest.NewServer(hd[vm.PublicEndpoint].Handler)
+		net.Instances[i] = &Instance{
+			NodeID:     ctx.NodeID,
+			VM:         v,
+			ToEngine:   toEngine,
+			HTTPServer: httpServer,
+			Client:     client.New(httpServer.URL, ret.requestTimeout),
+			Builder:    mb,
+			Memory:     memory,
+		}
+	}
+	net.app.instances = net.Instances
+	return net, nil
+}
+
+var _ snow.SubnetLookup = subnetLookup(ids.Empty)
+
+// subnetLookup places every chain in the same subnet
+type subnetLookup ids.ID
+
+func (s subnetLookup) SubnetID(ids.ID) (ids.ID, error) {
+	return ids.ID(s), nil
+}
+
+// Shutdown stops all HTTP servers and VMs in the network, returning the
+// first error encountered after every instance has been stopped.
+func (n *Network) Shutdown() error {
+	errs := wrappers.Errs{}
+	for _, inst := range n.Instances {
+		inst.HTTPServer.Close()
+		errs.Add(inst.VM.Shutdown())
+	}
+	return errs.Err
+}
+
+// SetTime fakes the clock of every instance in the network to [t].
+func (n *Network) SetTime(t time.Time) {
+	for _, inst := range n.Instances {
+		inst.VM.Clock().Set(t)
+	}
+}
+
+// AdvanceTime moves the clock of every instance in the network forward by
+// [d].
+func (n *Network) AdvanceTime(d time.Duration) {
+	for _, inst := range n.Instances {
+		clk := inst.VM.Clock()
+		clk.Set(clk.Time().Add(d))
+	}
+}
+
+var _ common.AppSender = &appSender{}
+
+// appSender delivers gossip to the next instance in the network in a
+// round-robin fashion.
+type appSender struct {
+	next      int
+	instances []*Instance
+}
+
+func (app *appSender) SendAppGossip(appGossipBytes []byte) error {
+	n := len(app.instances)
+	sender := app.instances[app.next].NodeID
+	app.next++
+	app.next %= n
+	return app.instances[app.next].VM.AppGossip(sender, appGossipBytes)
+}
+
+func (app *appSender) SendAppRequest(_ ids.NodeIDSet, _ uint32, _ []byte) error { return nil }
+func (app *appSender) SendAppResponse(_ ids.NodeID, _ uint32, _ []byte) error   { return nil }
+func (app *appSender) SendAppGossipSpecific(_ ids.NodeIDSet, _ []byte) error    { return nil }